@@ -0,0 +1,81 @@
+// Package agecategory derives cricket age groups (U-12, U-14, U-16, U-19) from a
+// date of birth. Following the BCCI convention, a player belongs to U-N for a season
+// if they were born on or after the season's cutoff date N years earlier, i.e. they
+// are still under N the day before the cutoff. Younger players may play up in an
+// older category.
+package agecategory
+
+import "time"
+
+// Open is the category of players too old for every age group
+const Open = "Open"
+
+// Category is an age group with its age limit
+type Category struct {
+	Name   string `json:"name"`
+	MaxAge int    `json:"maxAge"` // players must be younger than this the day before the cutoff date
+}
+
+// Categories lists the age groups from youngest to oldest
+var Categories = []Category{
+	{Name: "U-12", MaxAge: 12},
+	{Name: "U-14", MaxAge: 14},
+	{Name: "U-16", MaxAge: 16},
+	{Name: "U-19", MaxAge: 19},
+}
+
+// Lookup returns the category with the given name
+func Lookup(name string) (Category, bool) {
+	for _, category := range Categories {
+		if category.Name == name {
+			return category, true
+		}
+	}
+	return Category{}, false
+}
+
+// AgeOn returns a person's age in whole years on date
+func AgeOn(dateOfBirth time.Time, date time.Time) int {
+	dy, dm, dd := date.Date()
+	by, bm, bd := dateOfBirth.Date()
+	age := dy - by
+	if dm < bm || (dm == bm && dd < bd) {
+		age--
+	}
+	return age
+}
+
+// For returns the youngest category a player born on dateOfBirth belongs to for a season with the given cutoff,
+// or Open if they are too old for all of them
+func For(dateOfBirth time.Time, cutoff time.Time) string {
+	for _, category := range Categories {
+		if category.allows(dateOfBirth, cutoff) {
+			return category.Name
+		}
+	}
+	return Open
+}
+
+// Eligible reports whether a player born on dateOfBirth may play in the named category for a season with the
+// given cutoff. Every player is eligible for Open and unknown categories are never eligible.
+func Eligible(name string, dateOfBirth time.Time, cutoff time.Time) bool {
+	if name == Open {
+		return true
+	}
+	category, ok := Lookup(name)
+	if !ok {
+		return false
+	}
+	return category.allows(dateOfBirth, cutoff)
+}
+
+// BornOnOrAfter returns the earliest date of birth allowed in the category for a season with the given cutoff
+func (c Category) BornOnOrAfter(cutoff time.Time) time.Time {
+	y, m, d := cutoff.Date()
+	return time.Date(y-c.MaxAge, m, d, 0, 0, 0, 0, cutoff.Location())
+}
+
+func (c Category) allows(dateOfBirth time.Time, cutoff time.Time) bool {
+	y, m, d := dateOfBirth.Date()
+	return !time.Date(y, m, d, 0, 0, 0, 0, cutoff.Location()).Before(c.BornOnOrAfter(cutoff))
+}
@@ -0,0 +1,124 @@
+// Package antibot implements a proof-of-work challenge for public forms.
+//
+// The server issues a signed, expiring challenge. The client must find a
+// solution string such that SHA-256(challenge + ":" + solution) starts with at
+// least Difficulty zero bits. This costs a browser well under a second but makes
+// mass automated submissions expensive. Each challenge can be used only once.
+package antibot
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"math/bits"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	ErrInvalidChallenge = errors.New("invalid challenge")
+	ErrExpiredChallenge = errors.New("challenge has expired")
+	ErrUsedChallenge    = errors.New("challenge has already been used")
+	ErrInvalidSolution  = errors.New("invalid proof-of-work solution")
+)
+
+// Challenge is sent to the client to solve
+type Challenge struct {
+	Challenge  string    `json:"challenge"`
+	Difficulty int       `json:"difficulty"`
+	ExpiresAt  time.Time `json:"expiresAt"`
+}
+
+// Challenger issues and verifies proof-of-work challenges
+type Challenger struct {
+	secret     []byte
+	difficulty int
+	ttl        time.Duration
+
+	mu   sync.Mutex
+	used map[string]time.Time // challenge -> expiry, to prevent replays
+}
+
+// NewChallenger creates a Challenger requiring difficulty leading zero bits
+func NewChallenger(secret []byte, difficulty int, ttl time.Duration) *Challenger {
+	return &Challenger{
+		secret:     secret,
+		difficulty: difficulty,
+		ttl:        ttl,
+		used:       make(map[string]time.Time),
+	}
+}
+
+// NewChallenge issues a fresh challenge
+func (c *Challenger) NewChallenge() (Challenge, error) {
+	expiresAt := time.Now().Add(c.ttl)
+
+	payload := make([]byte, 16+8)
+	if _, err := rand.Read(payload[:16]); err != nil {
+		return Challenge{}, err
+	}
+	binary.BigEndian.PutUint64(payload[16:], uint64(expiresAt.Unix()))
+
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return Challenge{
+		Challenge:  encoded + "." + c.sign(encoded),
+		Difficulty: c.difficulty,
+		ExpiresAt:  expiresAt,
+	}, nil
+}
+
+// Verify checks the challenge was issued by us, is unexpired and unused, and that solution solves it
+func (c *Challenger) Verify(challenge string, solution string) error {
+	encoded, signature, ok := strings.Cut(challenge, ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(c.sign(encoded))) {
+		return ErrInvalidChallenge
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil || len(payload) != 24 {
+		return ErrInvalidChallenge
+	}
+	expiresAt := time.Unix(int64(binary.BigEndian.Uint64(payload[16:])), 0)
+	if time.Now().After(expiresAt) {
+		return ErrExpiredChallenge
+	}
+
+	sum := sha256.Sum256([]byte(challenge + ":" + solution))
+	if leadingZeroBits(sum[:]) < c.difficulty {
+		return ErrInvalidSolution
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	for key, expiry := range c.used {
+		if now.After(expiry) {
+			delete(c.used, key)
+		}
+	}
+	if _, seen := c.used[challenge]; seen {
+		return ErrUsedChallenge
+	}
+	c.used[challenge] = expiresAt
+	return nil
+}
+
+func (c *Challenger) sign(data string) string {
+	mac := hmac.New(sha256.New, c.secret)
+	mac.Write([]byte(data))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func leadingZeroBits(b []byte) int {
+	n := 0
+	for _, x := range b {
+		if x != 0 {
+			return n + bits.LeadingZeros8(x)
+		}
+		n += 8
+	}
+	return n
+}
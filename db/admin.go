@@ -46,3 +46,31 @@ func (m *MongoDB) UpdateAdmin(ctx context.Context, id primitive.ObjectID, admin
 	}
 	return nil
 }
+
+// UpdateAdminPermissions replaces an admin's permissions
+func (m *MongoDB) UpdateAdminPermissions(ctx context.Context, id primitive.ObjectID, permissions []string) error {
+	result, err := m.adminCollection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"permissions": permissions}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// BootstrapAdminPermission grants permission to the admin with the given email when no admin holds it yet,
+// so a fresh or upgraded installation always has someone who can grant it to others.
+// It reports whether the permission was granted.
+func (m *MongoDB) BootstrapAdminPermission(ctx context.Context, email string, permission string) (bool, error) {
+	count, err := m.adminCollection.CountDocuments(ctx, bson.M{"permissions": permission})
+	if err != nil || count > 0 {
+		return false, err
+	}
+
+	result, err := m.adminCollection.UpdateOne(ctx, bson.M{"email": email}, bson.M{"$addToSet": bson.M{"permissions": permission}})
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount > 0, nil
+}
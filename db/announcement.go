@@ -40,13 +40,14 @@ func (m *MongoDB) CreateAnnouncement(ctx context.Context, announcement *models.A
 	return &createdAnnouncement, nil
 }
 
+// GetAllAnnouncements retrieves all announcements that haven't been deleted, newest first
 func (m *MongoDB) GetAllAnnouncements(ctx context.Context) ([]models.Announcement, error) {
 	var announcements []models.Announcement
 	// Sort by createdAt descending
 	findOptions := options.Find()
 	findOptions.SetSort(bson.D{{Key: "createdAt", Value: -1}})
 
-	cursor, err := m.announcementCollection.Find(ctx, bson.M{}, findOptions)
+	cursor, err := m.announcementCollection.Find(ctx, bson.M{"deletedAt": bson.M{"$exists": false}}, findOptions)
 	if err != nil {
 		return nil, err
 	}
@@ -62,3 +63,222 @@ func (m *MongoDB) GetAllAnnouncements(ctx context.Context) ([]models.Announcemen
 
 	return announcements, nil
 }
+
+// GetAnnouncementByID retrieves an announcement by its ID
+func (m *MongoDB) GetAnnouncementByID(ctx context.Context, id primitive.ObjectID) (*models.Announcement, error) {
+	var announcement models.Announcement
+	err := m.announcementCollection.FindOne(ctx, bson.M{"_id": id}).Decode(&announcement)
+	if err != nil {
+		return nil, err
+	}
+	return &announcement, nil
+}
+
+// ListAnnouncements retrieves a page of non-deleted announcements, newest first.
+// Sorting and the (Before, BeforeID) cursor both use the (createdAt, _id) index, so
+// announcements created in the same instant are neither repeated nor skipped across pages.
+func (m *MongoDB) ListAnnouncements(ctx context.Context, filter models.AnnouncementFilter) ([]models.Announcement, error) {
+	query := bson.M{"deletedAt": bson.M{"$exists": false}}
+	if !filter.AllBatches {
+		batchIDs := filter.BatchIDs
+		if batchIDs == nil {
+			batchIDs = []primitive.ObjectID{} // $in requires an array
+		}
+		query["$or"] = bson.A{
+			bson.M{"batchIds": bson.M{"$exists": false}},
+			bson.M{"batchIds": bson.M{"$in": batchIDs}},
+		}
+	}
+	if filter.Before != nil {
+		query = bson.M{"$and": bson.A{query, beforeCursor(*filter.Before, filter.BeforeID)}}
+	}
+
+	findOptions := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}})
+	if filter.Limit > 0 {
+		findOptions.SetLimit(int64(filter.Limit))
+	}
+
+	cursor, err := m.announcementCollection.Find(ctx, query, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var announcements []models.Announcement
+	if err = cursor.All(ctx, &announcements); err != nil {
+		return nil, err
+	}
+
+	if announcements == nil {
+		return []models.Announcement{}, nil
+	}
+
+	return announcements, nil
+}
+
+// beforeCursor matches documents after (before, beforeID) in (createdAt, _id) descending order.
+// Without an ID it falls back to documents created strictly before the time.
+func beforeCursor(before time.Time, beforeID *primitive.ObjectID) bson.M {
+	if beforeID == nil {
+		return bson.M{"createdAt": bson.M{"$lt": before}}
+	}
+	return bson.M{"$or": bson.A{
+		bson.M{"createdAt": bson.M{"$lt": before}},
+		bson.M{"createdAt": before, "_id": bson.M{"$lt": *beforeID}},
+	}}
+}
+
+// UpdateAnnouncement saves an edited announcement and appends the previous version to its history
+func (m *MongoDB) UpdateAnnouncement(ctx context.Context, id primitive.ObjectID, announcement *models.Announcement, previous models.AnnouncementRevision) error {
+	now := time.Now()
+	announcement.UpdatedAt = &now
+
+	set := bson.M{
+		"title":                   announcement.Title,
+		"content":                 announcement.Content,
+		"contentHtml":             announcement.ContentHTML,
+		"requiresAcknowledgement": announcement.RequiresAcknowledgement,
+		"updatedAt":               announcement.UpdatedAt,
+	}
+	unset := bson.M{}
+	if len(announcement.AttachmentIDs) > 0 {
+		set["attachmentIds"] = announcement.AttachmentIDs
+	} else {
+		unset["attachmentIds"] = ""
+	}
+	if len(announcement.BatchIDs) > 0 {
+		set["batchIds"] = announcement.BatchIDs
+	} else {
+		unset["batchIds"] = ""
+	}
+
+	update := bson.M{
+		"$set":  set,
+		"$push": bson.M{"history": previous},
+	}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+
+	result, err := m.announcementCollection.UpdateOne(ctx, bson.M{"_id": id, "deletedAt": bson.M{"$exists": false}}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	announcement.History = append(announcement.History, previous)
+	return nil
+}
+
+// DeleteAnnouncement soft-deletes an announcement so it is hidden but kept for the record
+func (m *MongoDB) DeleteAnnouncement(ctx context.Context, id primitive.ObjectID, deletedBy string) error {
+	update := bson.M{"$set": bson.M{
+		"deletedAt": time.Now(),
+		"deletedBy": deletedBy,
+	}}
+
+	result, err := m.announcementCollection.UpdateOne(ctx, bson.M{"_id": id, "deletedAt": bson.M{"$exists": false}}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// --- Announcement receipt operations ---
+
+// MarkAnnouncementRead records that a cricketer has read an announcement.
+// The original read time is kept if the announcement was already read.
+func (m *MongoDB) MarkAnnouncementRead(ctx context.Context, announcementID primitive.ObjectID, cricketerID primitive.ObjectID) (*models.AnnouncementReceipt, error) {
+	filter := bson.M{"announcementId": announcementID, "cricketerId": cricketerID}
+	update := bson.M{"$setOnInsert": bson.M{"readAt": time.Now()}}
+	return m.upsertAnnouncementReceipt(ctx, filter, update)
+}
+
+// AcknowledgeAnnouncement records that a cricketer has acknowledged an announcement.
+// Acknowledging also marks the announcement as read. Repeated acknowledgements keep the first timestamp.
+// acknowledgedBy is the guardian acknowledging on the cricketer's behalf, or empty when the cricketer did.
+func (m *MongoDB) AcknowledgeAnnouncement(ctx context.Context, announcementID primitive.ObjectID, cricketerID primitive.ObjectID, acknowledgedBy string) (*models.AnnouncementReceipt, error) {
+	existing, err := m.getAnnouncementReceipt(ctx, announcementID, cricketerID)
+	if err != nil && err != mongo.ErrNoDocuments {
+		return nil, err
+	}
+	if existing != nil && existing.AcknowledgedAt != nil {
+		return existing, nil
+	}
+
+	now := time.Now()
+	filter := bson.M{"announcementId": announcementID, "cricketerId": cricketerID}
+	set := bson.M{"acknowledgedAt": now}
+	if acknowledgedBy != "" {
+		set["acknowledgedBy"] = acknowledgedBy
+	}
+	update := bson.M{
+		"$set":         set,
+		"$setOnInsert": bson.M{"readAt": now},
+	}
+	return m.upsertAnnouncementReceipt(ctx, filter, update)
+}
+
+// GetAnnouncementReceipts retrieves all receipts for an announcement
+func (m *MongoDB) GetAnnouncementReceipts(ctx context.Context, announcementID primitive.ObjectID) ([]models.AnnouncementReceipt, error) {
+	cursor, err := m.announcementReceiptCollection.Find(ctx, bson.M{"announcementId": announcementID})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var receipts []models.AnnouncementReceipt
+	if err = cursor.All(ctx, &receipts); err != nil {
+		return nil, err
+	}
+
+	if receipts == nil {
+		return []models.AnnouncementReceipt{}, nil
+	}
+
+	return receipts, nil
+}
+
+// GetAnnouncementReceiptsByCricketer retrieves all receipts for a cricketer
+func (m *MongoDB) GetAnnouncementReceiptsByCricketer(ctx context.Context, cricketerID primitive.ObjectID) ([]models.AnnouncementReceipt, error) {
+	cursor, err := m.announcementReceiptCollection.Find(ctx, bson.M{"cricketerId": cricketerID})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var receipts []models.AnnouncementReceipt
+	if err = cursor.All(ctx, &receipts); err != nil {
+		return nil, err
+	}
+
+	if receipts == nil {
+		return []models.AnnouncementReceipt{}, nil
+	}
+
+	return receipts, nil
+}
+
+func (m *MongoDB) getAnnouncementReceipt(ctx context.Context, announcementID primitive.ObjectID, cricketerID primitive.ObjectID) (*models.AnnouncementReceipt, error) {
+	var receipt models.AnnouncementReceipt
+	err := m.announcementReceiptCollection.FindOne(ctx, bson.M{"announcementId": announcementID, "cricketerId": cricketerID}).Decode(&receipt)
+	if err != nil {
+		return nil, err
+	}
+	return &receipt, nil
+}
+
+func (m *MongoDB) upsertAnnouncementReceipt(ctx context.Context, filter bson.M, update bson.M) (*models.AnnouncementReceipt, error) {
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var receipt models.AnnouncementReceipt
+	err := m.announcementReceiptCollection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&receipt)
+	if err != nil {
+		return nil, err
+	}
+	return &receipt, nil
+}
@@ -0,0 +1,181 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"cricketApp/models"
+)
+
+// CreateAssessmentTemplate inserts a new assessment template
+func (m *MongoDB) CreateAssessmentTemplate(ctx context.Context, template *models.AssessmentTemplate) error {
+	template.CreatedAt = time.Now()
+	template.UpdatedAt = template.CreatedAt
+	if template.ID.IsZero() {
+		template.ID = primitive.NewObjectID()
+	}
+
+	_, err := m.assessmentTemplateCollection.InsertOne(ctx, template)
+	return err
+}
+
+// GetAssessmentTemplateByID retrieves an assessment template by ID
+func (m *MongoDB) GetAssessmentTemplateByID(ctx context.Context, id primitive.ObjectID) (*models.AssessmentTemplate, error) {
+	var template models.AssessmentTemplate
+	err := m.assessmentTemplateCollection.FindOne(ctx, bson.M{"_id": id}).Decode(&template)
+	if err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+// GetAssessmentTemplates retrieves assessment templates by category and name, optionally only active ones
+func (m *MongoDB) GetAssessmentTemplates(ctx context.Context, activeOnly bool) ([]models.AssessmentTemplate, error) {
+	filter := bson.M{}
+	if activeOnly {
+		filter["active"] = true
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "category", Value: 1}, {Key: "name", Value: 1}})
+	cursor, err := m.assessmentTemplateCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	templates := []models.AssessmentTemplate{}
+	if err = cursor.All(ctx, &templates); err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+// UpdateAssessmentTemplate updates a template's name, description and active flag.
+// Criteria and scale are left alone so earlier assessments stay comparable.
+func (m *MongoDB) UpdateAssessmentTemplate(ctx context.Context, id primitive.ObjectID, template *models.AssessmentTemplate) error {
+	template.UpdatedAt = time.Now()
+
+	update := bson.M{
+		"$set": bson.M{
+			"name":        template.Name,
+			"description": template.Description,
+			"active":      template.Active,
+			"updatedAt":   template.UpdatedAt,
+		},
+	}
+
+	result, err := m.assessmentTemplateCollection.UpdateOne(ctx, bson.M{"_id": id}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// EnsureDefaultAssessmentTemplates creates the built-in templates when there are no templates at all.
+// It returns how many were created.
+func (m *MongoDB) EnsureDefaultAssessmentTemplates(ctx context.Context) (int, error) {
+	count, err := m.assessmentTemplateCollection.CountDocuments(ctx, bson.M{})
+	if err != nil || count > 0 {
+		return 0, err
+	}
+
+	templates := models.DefaultAssessmentTemplates()
+	for i := range templates {
+		templates[i].CreatedBy = "system"
+		if err := m.CreateAssessmentTemplate(ctx, &templates[i]); err != nil {
+			return i, err
+		}
+	}
+	return len(templates), nil
+}
+
+// CreateAssessment inserts a new assessment
+func (m *MongoDB) CreateAssessment(ctx context.Context, assessment *models.Assessment) error {
+	assessment.CreatedAt = time.Now()
+	assessment.UpdatedAt = assessment.CreatedAt
+	if assessment.ID.IsZero() {
+		assessment.ID = primitive.NewObjectID()
+	}
+
+	_, err := m.assessmentCollection.InsertOne(ctx, assessment)
+	return err
+}
+
+// GetAssessmentByID retrieves an assessment by ID
+func (m *MongoDB) GetAssessmentByID(ctx context.Context, id primitive.ObjectID) (*models.Assessment, error) {
+	var assessment models.Assessment
+	err := m.assessmentCollection.FindOne(ctx, bson.M{"_id": id}).Decode(&assessment)
+	if err != nil {
+		return nil, err
+	}
+	return &assessment, nil
+}
+
+// GetAssessments retrieves assessments matching the filter, oldest first
+func (m *MongoDB) GetAssessments(ctx context.Context, filter models.AssessmentFilter) ([]models.Assessment, error) {
+	query := bson.M{}
+	if filter.CricketerID != nil {
+		query["cricketerId"] = *filter.CricketerID
+	}
+	if filter.TemplateID != nil {
+		query["templateId"] = *filter.TemplateID
+	}
+	if filter.From != nil || filter.To != nil {
+		assessedOn := bson.M{}
+		if filter.From != nil {
+			assessedOn["$gte"] = *filter.From
+		}
+		if filter.To != nil {
+			assessedOn["$lt"] = *filter.To
+		}
+		query["assessedOn"] = assessedOn
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "assessedOn", Value: 1}, {Key: "createdAt", Value: 1}})
+	cursor, err := m.assessmentCollection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	assessments := []models.Assessment{}
+	if err = cursor.All(ctx, &assessments); err != nil {
+		return nil, err
+	}
+	return assessments, nil
+}
+
+// UpdateAssessment replaces an assessment's date, scores and comments
+func (m *MongoDB) UpdateAssessment(ctx context.Context, id primitive.ObjectID, assessment *models.Assessment) error {
+	assessment.UpdatedAt = time.Now()
+
+	update := bson.M{
+		"$set": bson.M{
+			"assessedOn":     assessment.AssessedOn,
+			"scores":         assessment.Scores,
+			"average":        assessment.Average,
+			"percent":        assessment.Percent,
+			"strengths":      assessment.Strengths,
+			"areasToImprove": assessment.AreasToImprove,
+			"comments":       assessment.Comments,
+			"updatedAt":      assessment.UpdatedAt,
+		},
+	}
+
+	result, err := m.assessmentCollection.UpdateOne(ctx, bson.M{"_id": id}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"cricketApp/models"
+)
+
+// CreateAttachment stores the metadata of an uploaded file
+func (m *MongoDB) CreateAttachment(ctx context.Context, attachment *models.Attachment) error {
+	attachment.CreatedAt = time.Now()
+	if attachment.ID.IsZero() {
+		attachment.ID = primitive.NewObjectID()
+	}
+
+	_, err := m.attachmentCollection.InsertOne(ctx, attachment)
+	return err
+}
+
+// GetAttachmentByID retrieves attachment metadata by its ID
+func (m *MongoDB) GetAttachmentByID(ctx context.Context, id primitive.ObjectID) (*models.Attachment, error) {
+	var attachment models.Attachment
+	err := m.attachmentCollection.FindOne(ctx, bson.M{"_id": id}).Decode(&attachment)
+	if err != nil {
+		return nil, err
+	}
+	return &attachment, nil
+}
+
+// GetAttachmentsByIDs retrieves the metadata of several attachments
+func (m *MongoDB) GetAttachmentsByIDs(ctx context.Context, ids []primitive.ObjectID) ([]models.Attachment, error) {
+	cursor, err := m.attachmentCollection.Find(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var attachments []models.Attachment
+	if err = cursor.All(ctx, &attachments); err != nil {
+		return nil, err
+	}
+
+	if attachments == nil {
+		return []models.Attachment{}, nil
+	}
+
+	return attachments, nil
+}
@@ -0,0 +1,65 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"cricketApp/models"
+)
+
+// SaveAttendance records attendance for a session. Each record replaces any earlier record
+// for the same cricketer and session.
+func (m *MongoDB) SaveAttendance(ctx context.Context, records []models.Attendance) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	writes := make([]mongo.WriteModel, 0, len(records))
+	for _, record := range records {
+		filter := bson.M{"sessionId": record.SessionID, "cricketerId": record.CricketerID}
+		update := bson.M{
+			"$set": bson.M{
+				"sessionDate":  record.SessionDate,
+				"status":       record.Status,
+				"note":         record.Note,
+				"markedBy":     record.MarkedBy,
+				"markedByRole": record.MarkedByRole,
+				"markedAt":     record.MarkedAt,
+			},
+		}
+		writes = append(writes, mongo.NewUpdateOneModel().SetFilter(filter).SetUpdate(update).SetUpsert(true))
+	}
+
+	_, err := m.attendanceCollection.BulkWrite(ctx, writes)
+	return err
+}
+
+// GetAttendanceForSession retrieves the attendance recorded for a session
+func (m *MongoDB) GetAttendanceForSession(ctx context.Context, sessionID primitive.ObjectID) ([]models.Attendance, error) {
+	return m.findAttendance(ctx, bson.M{"sessionId": sessionID}, options.Find())
+}
+
+// GetAttendanceForCricketer retrieves a cricketer's attendance for sessions on or after since, most recent first
+func (m *MongoDB) GetAttendanceForCricketer(ctx context.Context, cricketerID primitive.ObjectID, since time.Time) ([]models.Attendance, error) {
+	filter := bson.M{"cricketerId": cricketerID, "sessionDate": bson.M{"$gte": since}}
+	return m.findAttendance(ctx, filter, options.Find().SetSort(bson.D{{Key: "sessionDate", Value: -1}}))
+}
+
+func (m *MongoDB) findAttendance(ctx context.Context, filter bson.M, opts *options.FindOptions) ([]models.Attendance, error) {
+	cursor, err := m.attendanceCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	records := []models.Attendance{}
+	if err = cursor.All(ctx, &records); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
@@ -0,0 +1,54 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"cricketApp/models"
+)
+
+// CreateAuditEntry records an audited action
+func (m *MongoDB) CreateAuditEntry(ctx context.Context, entry *models.AuditEntry) error {
+	entry.CreatedAt = time.Now()
+	if entry.ID.IsZero() {
+		entry.ID = primitive.NewObjectID()
+	}
+
+	_, err := m.auditCollection.InsertOne(ctx, entry)
+	return err
+}
+
+// GetAuditEntries retrieves audit entries matching filter, newest first
+func (m *MongoDB) GetAuditEntries(ctx context.Context, filter models.AuditFilter) ([]models.AuditEntry, error) {
+	query := bson.M{}
+	if filter.Action != "" {
+		query["action"] = filter.Action
+	}
+	if filter.ActorID != "" {
+		query["actorId"] = filter.ActorID
+	}
+	if filter.TargetID != "" {
+		query["targetId"] = filter.TargetID
+	}
+
+	opts := options.Find().SetSort(bson.M{"createdAt": -1})
+	if filter.Limit > 0 {
+		opts.SetLimit(int64(filter.Limit))
+	}
+
+	cursor, err := m.auditCollection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	entries := []models.AuditEntry{}
+	if err = cursor.All(ctx, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
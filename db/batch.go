@@ -0,0 +1,96 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"cricketApp/models"
+)
+
+// CreateBatch creates a new batch
+func (m *MongoDB) CreateBatch(ctx context.Context, batch *models.Batch) error {
+	batch.CreatedAt = time.Now()
+	batch.UpdatedAt = time.Now()
+	if batch.CoachIDs == nil {
+		batch.CoachIDs = []primitive.ObjectID{}
+	}
+
+	result, err := m.batchCollection.InsertOne(ctx, batch)
+	if err != nil {
+		return err
+	}
+	if id, ok := result.InsertedID.(primitive.ObjectID); ok {
+		batch.ID = id
+	}
+	return nil
+}
+
+// GetBatchByID retrieves a batch by its ID
+func (m *MongoDB) GetBatchByID(ctx context.Context, id primitive.ObjectID) (*models.Batch, error) {
+	var batch models.Batch
+	err := m.batchCollection.FindOne(ctx, bson.M{"_id": id}).Decode(&batch)
+	if err != nil {
+		return nil, err
+	}
+	return &batch, nil
+}
+
+// GetAllBatches retrieves all batches sorted by name
+func (m *MongoDB) GetAllBatches(ctx context.Context) ([]models.Batch, error) {
+	return m.findBatches(ctx, bson.M{})
+}
+
+// GetBatchesByCoach retrieves the batches a coach is assigned to
+func (m *MongoDB) GetBatchesByCoach(ctx context.Context, coachID primitive.ObjectID) ([]models.Batch, error) {
+	return m.findBatches(ctx, bson.M{"coachIds": coachID})
+}
+
+// UpdateBatch updates an existing batch
+func (m *MongoDB) UpdateBatch(ctx context.Context, id primitive.ObjectID, batch *models.Batch) error {
+	batch.UpdatedAt = time.Now()
+	update := bson.M{
+		"$set": bson.M{
+			"name":        batch.Name,
+			"description": batch.Description,
+			"coachIds":    batch.CoachIDs,
+			"ageCategory": batch.AgeCategory,
+			"monthlyFee":  batch.MonthlyFee,
+			"updatedAt":   batch.UpdatedAt,
+		},
+	}
+
+	result, err := m.batchCollection.UpdateOne(ctx, bson.M{"_id": id}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+func (m *MongoDB) findBatches(ctx context.Context, filter bson.M) ([]models.Batch, error) {
+	findOptions := options.Find().SetSort(bson.D{{Key: "name", Value: 1}})
+
+	cursor, err := m.batchCollection.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var batches []models.Batch
+	if err = cursor.All(ctx, &batches); err != nil {
+		return nil, err
+	}
+
+	if batches == nil {
+		return []models.Batch{}, nil
+	}
+
+	return batches, nil
+}
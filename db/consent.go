@@ -0,0 +1,111 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"cricketApp/models"
+)
+
+// PublishConsentDocument stores document as the next version of its key and makes it the current one.
+// Earlier versions are kept so existing signatures still point at the text that was signed.
+func (m *MongoDB) PublishConsentDocument(ctx context.Context, document *models.ConsentDocument) error {
+	latest, err := m.GetCurrentConsentDocument(ctx, document.Key)
+	if err != nil && err != mongo.ErrNoDocuments {
+		return err
+	}
+
+	document.ID = primitive.NewObjectID()
+	document.Version = 1
+	if latest != nil {
+		document.Version = latest.Version + 1
+	}
+	document.Current = true
+	document.PublishedAt = time.Now()
+
+	// A unique index on key and version stops two concurrent publishes getting the same version
+	if _, err := m.consentDocumentCollection.InsertOne(ctx, document); err != nil {
+		return err
+	}
+
+	_, err = m.consentDocumentCollection.UpdateMany(ctx,
+		bson.M{"key": document.Key, "_id": bson.M{"$ne": document.ID}},
+		bson.M{"$set": bson.M{"current": false}})
+	return err
+}
+
+// GetConsentDocumentByID retrieves a consent document version by its ID
+func (m *MongoDB) GetConsentDocumentByID(ctx context.Context, id primitive.ObjectID) (*models.ConsentDocument, error) {
+	var document models.ConsentDocument
+	err := m.consentDocumentCollection.FindOne(ctx, bson.M{"_id": id}).Decode(&document)
+	if err != nil {
+		return nil, err
+	}
+	return &document, nil
+}
+
+// GetCurrentConsentDocument retrieves the latest version of a consent document
+func (m *MongoDB) GetCurrentConsentDocument(ctx context.Context, key string) (*models.ConsentDocument, error) {
+	opts := options.FindOne().SetSort(bson.D{{Key: "version", Value: -1}})
+
+	var document models.ConsentDocument
+	err := m.consentDocumentCollection.FindOne(ctx, bson.M{"key": key}, opts).Decode(&document)
+	if err != nil {
+		return nil, err
+	}
+	return &document, nil
+}
+
+// GetCurrentConsentDocuments retrieves the latest version of every consent document, sorted by key
+func (m *MongoDB) GetCurrentConsentDocuments(ctx context.Context) ([]models.ConsentDocument, error) {
+	return m.findConsentDocuments(ctx, bson.M{"current": true}, bson.D{{Key: "key", Value: 1}})
+}
+
+// GetConsentDocumentVersions retrieves every version of a consent document, newest first
+func (m *MongoDB) GetConsentDocumentVersions(ctx context.Context, key string) ([]models.ConsentDocument, error) {
+	return m.findConsentDocuments(ctx, bson.M{"key": key}, bson.D{{Key: "version", Value: -1}})
+}
+
+// CreateConsentSignature records a signature. Signatures are never changed or removed.
+func (m *MongoDB) CreateConsentSignature(ctx context.Context, signature *models.ConsentSignature) error {
+	if signature.ID.IsZero() {
+		signature.ID = primitive.NewObjectID()
+	}
+	_, err := m.consentSignatureCollection.InsertOne(ctx, signature)
+	return err
+}
+
+// GetConsentSignatures retrieves the signatures recorded for any of the cricketers, newest first
+func (m *MongoDB) GetConsentSignatures(ctx context.Context, cricketerIDs []primitive.ObjectID) ([]models.ConsentSignature, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "signedAt", Value: -1}})
+	cursor, err := m.consentSignatureCollection.Find(ctx, bson.M{"cricketerId": bson.M{"$in": cricketerIDs}}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	signatures := []models.ConsentSignature{}
+	if err = cursor.All(ctx, &signatures); err != nil {
+		return nil, err
+	}
+	return signatures, nil
+}
+
+func (m *MongoDB) findConsentDocuments(ctx context.Context, filter bson.M, sort bson.D) ([]models.ConsentDocument, error) {
+	cursor, err := m.consentDocumentCollection.Find(ctx, filter, options.Find().SetSort(sort))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	documents := []models.ConsentDocument{}
+	if err = cursor.All(ctx, &documents); err != nil {
+		return nil, err
+	}
+	return documents, nil
+}
@@ -152,3 +152,93 @@ func (m *MongoDB) UpdateCricketerInactiveStatus(ctx context.Context, id primitiv
 	}
 	return nil
 }
+
+// UpdateCricketerBatch assigns a cricketer to a batch, or removes them from their batch when batchID is nil
+func (m *MongoDB) UpdateCricketerBatch(ctx context.Context, id primitive.ObjectID, batchID *primitive.ObjectID) error {
+	update := bson.M{"$set": bson.M{"batchId": batchID}}
+	if batchID == nil {
+		update = bson.M{"$unset": bson.M{"batchId": ""}}
+	}
+	result, err := m.cricketerCollection.UpdateOne(ctx, bson.M{"_id": id}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// UpdateCricketerDateOfBirth sets a cricketer's date of birth
+func (m *MongoDB) UpdateCricketerDateOfBirth(ctx context.Context, id primitive.ObjectID, dateOfBirth time.Time) error {
+	result, err := m.cricketerCollection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"dateOfBirth": dateOfBirth}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// UpdateCricketerGender sets a cricketer's gender
+func (m *MongoDB) UpdateCricketerGender(ctx context.Context, id primitive.ObjectID, gender string) error {
+	result, err := m.cricketerCollection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"gender": gender}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// SetCricketerPasswordSetup issues a new one-time password setup link, replacing any earlier one
+func (m *MongoDB) SetCricketerPasswordSetup(ctx context.Context, id primitive.ObjectID, tokenHash string, expiresAt time.Time) error {
+	update := bson.M{"$set": bson.M{"passwordSetupHash": tokenHash, "passwordSetupExpiresAt": expiresAt}}
+	result, err := m.cricketerCollection.UpdateOne(ctx, bson.M{"_id": id}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// CompleteCricketerPasswordSetup sets the password of the cricketer holding an unexpired setup link
+// and uses the link up. An unknown, used or expired link returns mongo.ErrNoDocuments.
+func (m *MongoDB) CompleteCricketerPasswordSetup(ctx context.Context, tokenHash string, hashedPassword string, now time.Time) (*models.Cricketer, error) {
+	filter := bson.M{"passwordSetupHash": tokenHash, "passwordSetupExpiresAt": bson.M{"$gt": now}}
+	update := bson.M{
+		"$set":   bson.M{"password": hashedPassword},
+		"$unset": bson.M{"passwordSetupHash": "", "passwordSetupExpiresAt": ""},
+	}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var cricketer models.Cricketer
+	if err := m.cricketerCollection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&cricketer); err != nil {
+		return nil, err
+	}
+	return &cricketer, nil
+}
+
+// GetCricketersByBatches retrieves all cricketers assigned to any of the given batches
+func (m *MongoDB) GetCricketersByBatches(ctx context.Context, batchIDs []primitive.ObjectID) ([]models.Cricketer, error) {
+	var cricketers []models.Cricketer
+	cursor, err := m.cricketerCollection.Find(ctx, bson.M{"batchId": bson.M{"$in": batchIDs}})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	if err = cursor.All(ctx, &cricketers); err != nil {
+		return nil, err
+	}
+
+	if cricketers == nil {
+		return []models.Cricketer{}, nil
+	}
+
+	return cricketers, nil
+}
@@ -0,0 +1,81 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"cricketApp/models"
+)
+
+// SaveRegistrationDocument stores the document for a registration's document slot, replacing any earlier upload.
+// It returns the replaced document, or nil if the slot was empty.
+func (m *MongoDB) SaveRegistrationDocument(ctx context.Context, document *models.RegistrationDocument) (*models.RegistrationDocument, error) {
+	document.UploadedAt = time.Now()
+	filter := bson.M{"registrationId": document.RegistrationID, "type": document.Type}
+
+	// Keep the slot's ID across re-uploads
+	replacement := *document
+	replacement.ID = primitive.NilObjectID
+	opts := options.FindOneAndReplace().SetUpsert(true).SetReturnDocument(options.Before)
+
+	var previous models.RegistrationDocument
+	err := m.registrationDocumentCollection.FindOneAndReplace(ctx, filter, replacement, opts).Decode(&previous)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &previous, nil
+}
+
+// GetRegistrationDocument retrieves the document uploaded for one slot of a registration
+func (m *MongoDB) GetRegistrationDocument(ctx context.Context, registrationID primitive.ObjectID, documentType string) (*models.RegistrationDocument, error) {
+	var document models.RegistrationDocument
+	err := m.registrationDocumentCollection.FindOne(ctx, bson.M{"registrationId": registrationID, "type": documentType}).Decode(&document)
+	if err != nil {
+		return nil, err
+	}
+	return &document, nil
+}
+
+// GetRegistrationDocuments retrieves all documents uploaded for a registration
+func (m *MongoDB) GetRegistrationDocuments(ctx context.Context, registrationID primitive.ObjectID) ([]models.RegistrationDocument, error) {
+	cursor, err := m.registrationDocumentCollection.Find(ctx, bson.M{"registrationId": registrationID})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	documents := []models.RegistrationDocument{}
+	if err = cursor.All(ctx, &documents); err != nil {
+		return nil, err
+	}
+	return documents, nil
+}
+
+// ReviewRegistrationDocument records a reviewer's decision on the current upload for a document slot.
+// key identifies the upload that was reviewed, so a decision isn't applied to a file uploaded since.
+func (m *MongoDB) ReviewRegistrationDocument(ctx context.Context, registrationID primitive.ObjectID, documentType string, key string, status string, note string, reviewedBy string) error {
+	filter := bson.M{"registrationId": registrationID, "type": documentType, "key": key}
+	update := bson.M{"$set": bson.M{
+		"status":     status,
+		"reviewNote": note,
+		"reviewedBy": reviewedBy,
+		"reviewedAt": time.Now(),
+	}}
+
+	result, err := m.registrationDocumentCollection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
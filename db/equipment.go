@@ -0,0 +1,239 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"cricketApp/models"
+)
+
+// CreateEquipmentItem adds a line of kit to the inventory
+func (m *MongoDB) CreateEquipmentItem(ctx context.Context, item *models.EquipmentItem) error {
+	item.CreatedAt = time.Now()
+	item.UpdatedAt = item.CreatedAt
+	if item.ID.IsZero() {
+		item.ID = primitive.NewObjectID()
+	}
+
+	_, err := m.equipmentCollection.InsertOne(ctx, item)
+	return err
+}
+
+// GetEquipmentItemByID retrieves an inventory item by ID
+func (m *MongoDB) GetEquipmentItemByID(ctx context.Context, id primitive.ObjectID) (*models.EquipmentItem, error) {
+	var item models.EquipmentItem
+	err := m.equipmentCollection.FindOne(ctx, bson.M{"_id": id}).Decode(&item)
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// GetEquipmentItems retrieves the inventory items matching the filter, ordered by category and name
+func (m *MongoDB) GetEquipmentItems(ctx context.Context, filter models.EquipmentFilter) ([]models.EquipmentItem, error) {
+	query := bson.M{}
+	if filter.Category != "" {
+		query["category"] = filter.Category
+	}
+	if filter.Location != "" {
+		query["location"] = filter.Location
+	}
+	if !filter.IncludeRetired {
+		query["retired"] = false
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "category", Value: 1}, {Key: "name", Value: 1}, {Key: "size", Value: 1}})
+	cursor, err := m.equipmentCollection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	items := []models.EquipmentItem{}
+	if err = cursor.All(ctx, &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// UpdateEquipmentItemDetails saves an item's editable details, leaving its stock counts alone
+func (m *MongoDB) UpdateEquipmentItemDetails(ctx context.Context, id primitive.ObjectID, item *models.EquipmentItem) error {
+	item.UpdatedAt = time.Now()
+
+	update := bson.M{
+		"$set": bson.M{
+			"name":              item.Name,
+			"category":          item.Category,
+			"size":              item.Size,
+			"condition":         item.Condition,
+			"location":          item.Location,
+			"lowStockThreshold": item.LowStockThreshold,
+			"notes":             item.Notes,
+			"retired":           item.Retired,
+			"updatedAt":         item.UpdatedAt,
+		},
+	}
+
+	result, err := m.equipmentCollection.UpdateOne(ctx, bson.M{"_id": id}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// AdjustEquipmentStock adds or removes stock outside an issue and records why. Removing more than
+// is in store returns mongo.ErrNoDocuments.
+func (m *MongoDB) AdjustEquipmentStock(ctx context.Context, id primitive.ObjectID, adjustment models.EquipmentAdjustment) error {
+	filter := bson.M{"_id": id}
+	if adjustment.Change < 0 {
+		filter["available"] = bson.M{"$gte": -adjustment.Change}
+	}
+	update := bson.M{
+		"$inc":  bson.M{"quantity": adjustment.Change, "available": adjustment.Change},
+		"$push": bson.M{"adjustments": adjustment},
+		"$set":  bson.M{"updatedAt": time.Now()},
+	}
+
+	result, err := m.equipmentCollection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// ReserveEquipment takes quantity of an item out of store for an issue. If there aren't enough
+// in store, or the item is retired or unusable, it returns mongo.ErrNoDocuments.
+func (m *MongoDB) ReserveEquipment(ctx context.Context, id primitive.ObjectID, quantity int) error {
+	filter := bson.M{
+		"_id":       id,
+		"retired":   false,
+		"condition": bson.M{"$ne": models.EquipmentUnusable},
+		"available": bson.M{"$gte": quantity},
+	}
+	update := bson.M{
+		"$inc": bson.M{"available": -quantity},
+		"$set": bson.M{"updatedAt": time.Now()},
+	}
+
+	result, err := m.equipmentCollection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// RestockEquipment puts returned kit back in store and writes off what was lost or damaged,
+// recording the write-off as an adjustment
+func (m *MongoDB) RestockEquipment(ctx context.Context, id primitive.ObjectID, returned int, writeOff *models.EquipmentAdjustment) error {
+	update := bson.M{
+		"$inc": bson.M{"available": returned},
+		"$set": bson.M{"updatedAt": time.Now()},
+	}
+	if writeOff != nil {
+		update["$inc"] = bson.M{"available": returned, "quantity": writeOff.Change}
+		update["$push"] = bson.M{"adjustments": writeOff}
+	}
+
+	result, err := m.equipmentCollection.UpdateOne(ctx, bson.M{"_id": id}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// CreateEquipmentIssue records kit lent out
+func (m *MongoDB) CreateEquipmentIssue(ctx context.Context, issue *models.EquipmentIssue) error {
+	if issue.ID.IsZero() {
+		issue.ID = primitive.NewObjectID()
+	}
+
+	_, err := m.equipmentIssueCollection.InsertOne(ctx, issue)
+	return err
+}
+
+// GetEquipmentIssueByID retrieves an equipment issue by ID
+func (m *MongoDB) GetEquipmentIssueByID(ctx context.Context, id primitive.ObjectID) (*models.EquipmentIssue, error) {
+	var issue models.EquipmentIssue
+	err := m.equipmentIssueCollection.FindOne(ctx, bson.M{"_id": id}).Decode(&issue)
+	if err != nil {
+		return nil, err
+	}
+	return &issue, nil
+}
+
+// GetEquipmentIssues retrieves the equipment issues matching the filter, most recent first
+func (m *MongoDB) GetEquipmentIssues(ctx context.Context, filter models.EquipmentIssueFilter) ([]models.EquipmentIssue, error) {
+	query := bson.M{}
+	if filter.ItemID != nil {
+		query["itemId"] = *filter.ItemID
+	}
+	if filter.CricketerID != nil {
+		query["cricketerId"] = *filter.CricketerID
+	}
+	if filter.SessionID != nil {
+		query["sessionId"] = *filter.SessionID
+	}
+	if filter.BatchIDs != nil {
+		query["batchId"] = bson.M{"$in": filter.BatchIDs}
+	}
+	if filter.OutstandingOnly {
+		query["status"] = models.EquipmentIssued
+	}
+	if filter.DueBefore != nil {
+		query["dueAt"] = bson.M{"$lt": *filter.DueBefore}
+	}
+
+	cursor, err := m.equipmentIssueCollection.Find(ctx, query, options.Find().SetSort(bson.D{{Key: "issuedAt", Value: -1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	issues := []models.EquipmentIssue{}
+	if err = cursor.All(ctx, &issues); err != nil {
+		return nil, err
+	}
+	return issues, nil
+}
+
+// ReturnEquipmentIssue records the return of kit still out. If it has already been returned it
+// returns mongo.ErrNoDocuments.
+func (m *MongoDB) ReturnEquipmentIssue(ctx context.Context, id primitive.ObjectID, equipmentReturn models.EquipmentReturn) error {
+	filter := bson.M{"_id": id, "status": models.EquipmentIssued}
+	update := bson.M{"$set": bson.M{"status": models.EquipmentReturned, "return": equipmentReturn}}
+
+	result, err := m.equipmentIssueCollection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// MarkEquipmentIssuesReminded records when the holders of overdue kit were last reminded
+func (m *MongoDB) MarkEquipmentIssuesReminded(ctx context.Context, ids []primitive.ObjectID, at time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	_, err := m.equipmentIssueCollection.UpdateMany(ctx, bson.M{"_id": bson.M{"$in": ids}}, bson.M{"$set": bson.M{"remindedAt": at}})
+	return err
+}
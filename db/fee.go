@@ -0,0 +1,78 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"cricketApp/models"
+)
+
+// ErrFeePeriodChanged is returned when a cricketer's due date was moved, e.g. by another payer,
+// between reading it and recording a payment for the period it started
+var ErrFeePeriodChanged = errors.New("fee period has changed")
+
+// CreateFeePayment records a fee payment and moves the cricketer's due date to the end of the period
+// paid for. dueDate is the due date the period was worked out from (nil if the cricketer had none);
+// if it has changed since, nothing is recorded and ErrFeePeriodChanged is returned.
+func (m *MongoDB) CreateFeePayment(ctx context.Context, payment *models.FeePayment, dueDate *time.Time) error {
+	payment.CreatedAt = time.Now()
+	if payment.ID.IsZero() {
+		payment.ID = primitive.NewObjectID()
+	}
+
+	session, err := m.client.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		filter := bson.M{"_id": payment.CricketerID, "dueDate": nil}
+		if dueDate != nil {
+			filter["dueDate"] = *dueDate
+		}
+		result, err := m.cricketerCollection.UpdateOne(sessCtx, filter, bson.M{"$set": bson.M{"dueDate": payment.PeriodEnd}})
+		if err != nil {
+			return nil, err
+		}
+		if result.MatchedCount == 0 {
+			return nil, ErrFeePeriodChanged
+		}
+
+		_, err = m.feePaymentCollection.InsertOne(sessCtx, payment)
+		return nil, err
+	})
+	return err
+}
+
+// GetFeePaymentByIdempotencyKey retrieves the payment recorded for an idempotency key
+func (m *MongoDB) GetFeePaymentByIdempotencyKey(ctx context.Context, key string) (*models.FeePayment, error) {
+	var payment models.FeePayment
+	err := m.feePaymentCollection.FindOne(ctx, bson.M{"idempotencyKey": key}).Decode(&payment)
+	if err != nil {
+		return nil, err
+	}
+	return &payment, nil
+}
+
+// GetFeePayments retrieves a cricketer's fee payments, most recent first
+func (m *MongoDB) GetFeePayments(ctx context.Context, cricketerID primitive.ObjectID) ([]models.FeePayment, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}})
+	cursor, err := m.feePaymentCollection.Find(ctx, bson.M{"cricketerId": cricketerID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	payments := []models.FeePayment{}
+	if err = cursor.All(ctx, &payments); err != nil {
+		return nil, err
+	}
+	return payments, nil
+}
@@ -0,0 +1,245 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"cricketApp/models"
+)
+
+// ErrDuplicateFitnessTestKey is returned when a fitness test's key is already in the catalogue
+var ErrDuplicateFitnessTestKey = errors.New("a fitness test with this key already exists")
+
+// CreateFitnessTest adds a test to the fitness catalogue
+func (m *MongoDB) CreateFitnessTest(ctx context.Context, test *models.FitnessTest) error {
+	test.CreatedAt = time.Now()
+	test.UpdatedAt = test.CreatedAt
+	if test.ID.IsZero() {
+		test.ID = primitive.NewObjectID()
+	}
+
+	_, err := m.fitnessTestCollection.InsertOne(ctx, test)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrDuplicateFitnessTestKey
+	}
+	return err
+}
+
+// GetFitnessTestByID retrieves a fitness test by ID
+func (m *MongoDB) GetFitnessTestByID(ctx context.Context, id primitive.ObjectID) (*models.FitnessTest, error) {
+	var test models.FitnessTest
+	err := m.fitnessTestCollection.FindOne(ctx, bson.M{"_id": id}).Decode(&test)
+	if err != nil {
+		return nil, err
+	}
+	return &test, nil
+}
+
+// GetFitnessTests retrieves the fitness catalogue by name, optionally only active tests
+func (m *MongoDB) GetFitnessTests(ctx context.Context, activeOnly bool) ([]models.FitnessTest, error) {
+	filter := bson.M{}
+	if activeOnly {
+		filter["active"] = true
+	}
+
+	cursor, err := m.fitnessTestCollection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "name", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	tests := []models.FitnessTest{}
+	if err = cursor.All(ctx, &tests); err != nil {
+		return nil, err
+	}
+	return tests, nil
+}
+
+// UpdateFitnessTest updates a test's name, description, alert threshold, benchmarks and active flag
+func (m *MongoDB) UpdateFitnessTest(ctx context.Context, id primitive.ObjectID, test *models.FitnessTest) error {
+	test.UpdatedAt = time.Now()
+
+	update := bson.M{
+		"$set": bson.M{
+			"name":          test.Name,
+			"description":   test.Description,
+			"dropThreshold": test.DropThreshold,
+			"benchmarks":    test.Benchmarks,
+			"active":        test.Active,
+			"updatedAt":     test.UpdatedAt,
+		},
+	}
+
+	result, err := m.fitnessTestCollection.UpdateOne(ctx, bson.M{"_id": id}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// EnsureDefaultFitnessTests adds the built-in tests when the catalogue is empty.
+// It returns how many were added.
+func (m *MongoDB) EnsureDefaultFitnessTests(ctx context.Context) (int, error) {
+	count, err := m.fitnessTestCollection.CountDocuments(ctx, bson.M{})
+	if err != nil || count > 0 {
+		return 0, err
+	}
+
+	tests := models.DefaultFitnessTests()
+	for i := range tests {
+		if err := m.CreateFitnessTest(ctx, &tests[i]); err != nil {
+			return i, err
+		}
+	}
+	return len(tests), nil
+}
+
+// CreateFitnessResults inserts a set of fitness results
+func (m *MongoDB) CreateFitnessResults(ctx context.Context, results []models.FitnessResult) error {
+	if len(results) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	documents := make([]interface{}, len(results))
+	for i := range results {
+		results[i].CreatedAt = now
+		if results[i].ID.IsZero() {
+			results[i].ID = primitive.NewObjectID()
+		}
+		documents[i] = results[i]
+	}
+
+	_, err := m.fitnessResultCollection.InsertMany(ctx, documents)
+	return err
+}
+
+// GetFitnessResults retrieves fitness results matching the filter, oldest first
+func (m *MongoDB) GetFitnessResults(ctx context.Context, filter models.FitnessResultFilter) ([]models.FitnessResult, error) {
+	query := bson.M{}
+	if filter.CricketerID != nil {
+		query["cricketerId"] = *filter.CricketerID
+	}
+	if filter.TestID != nil {
+		query["testId"] = *filter.TestID
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "testedOn", Value: 1}, {Key: "createdAt", Value: 1}})
+	cursor, err := m.fitnessResultCollection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	results := []models.FitnessResult{}
+	if err = cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// GetPreviousFitnessResult retrieves a cricketer's latest result in a test from before the given time.
+// It returns mongo.ErrNoDocuments when there is none.
+func (m *MongoDB) GetPreviousFitnessResult(ctx context.Context, cricketerID primitive.ObjectID, testID primitive.ObjectID, before time.Time) (*models.FitnessResult, error) {
+	filter := bson.M{"cricketerId": cricketerID, "testId": testID, "testedOn": bson.M{"$lt": before}}
+	opts := options.FindOne().SetSort(bson.D{{Key: "testedOn", Value: -1}, {Key: "createdAt", Value: -1}})
+
+	var result models.FitnessResult
+	if err := m.fitnessResultCollection.FindOne(ctx, filter, opts).Decode(&result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetLatestFitnessResults retrieves each cricketer's latest result in a test within an age category,
+// counting only results tested in [from, to)
+func (m *MongoDB) GetLatestFitnessResults(ctx context.Context, testID primitive.ObjectID, ageCategory string, from time.Time, to time.Time) ([]models.FitnessResult, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"testId":      testID,
+			"ageCategory": ageCategory,
+			"testedOn":    bson.M{"$gte": from, "$lt": to},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "testedOn", Value: -1}, {Key: "createdAt", Value: -1}}}},
+		{{Key: "$group", Value: bson.M{"_id": "$cricketerId", "result": bson.M{"$first": "$$ROOT"}}}},
+		{{Key: "$replaceRoot", Value: bson.M{"newRoot": "$result"}}},
+	}
+
+	cursor, err := m.fitnessResultCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	results := []models.FitnessResult{}
+	if err = cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// CreateFitnessAlert records a drop in a cricketer's fitness result
+func (m *MongoDB) CreateFitnessAlert(ctx context.Context, alert *models.FitnessAlert) error {
+	alert.CreatedAt = time.Now()
+	if alert.ID.IsZero() {
+		alert.ID = primitive.NewObjectID()
+	}
+
+	_, err := m.fitnessAlertCollection.InsertOne(ctx, alert)
+	return err
+}
+
+// GetFitnessAlertByID retrieves a fitness alert by ID
+func (m *MongoDB) GetFitnessAlertByID(ctx context.Context, id primitive.ObjectID) (*models.FitnessAlert, error) {
+	var alert models.FitnessAlert
+	err := m.fitnessAlertCollection.FindOne(ctx, bson.M{"_id": id}).Decode(&alert)
+	if err != nil {
+		return nil, err
+	}
+	return &alert, nil
+}
+
+// GetFitnessAlerts retrieves fitness alerts matching the filter, newest first
+func (m *MongoDB) GetFitnessAlerts(ctx context.Context, filter models.FitnessAlertFilter) ([]models.FitnessAlert, error) {
+	query := bson.M{}
+	if filter.BatchIDs != nil {
+		query["batchId"] = bson.M{"$in": filter.BatchIDs}
+	}
+	if filter.CricketerID != nil {
+		query["cricketerId"] = *filter.CricketerID
+	}
+	if filter.Open {
+		query["acknowledgedAt"] = bson.M{"$exists": false}
+	}
+
+	cursor, err := m.fitnessAlertCollection.Find(ctx, query, options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	alerts := []models.FitnessAlert{}
+	if err = cursor.All(ctx, &alerts); err != nil {
+		return nil, err
+	}
+	return alerts, nil
+}
+
+// AcknowledgeFitnessAlert marks a fitness alert as seen. Acknowledging it again keeps the first acknowledgement.
+func (m *MongoDB) AcknowledgeFitnessAlert(ctx context.Context, id primitive.ObjectID, acknowledgedBy string) (*models.FitnessAlert, error) {
+	update := bson.M{"$set": bson.M{"acknowledgedBy": acknowledgedBy, "acknowledgedAt": time.Now()}}
+	_, err := m.fitnessAlertCollection.UpdateOne(ctx, bson.M{"_id": id, "acknowledgedAt": bson.M{"$exists": false}}, update)
+	if err != nil {
+		return nil, err
+	}
+	return m.GetFitnessAlertByID(ctx, id)
+}
@@ -0,0 +1,115 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"cricketApp/models"
+)
+
+// CreateGuardian creates a new guardian account
+func (m *MongoDB) CreateGuardian(ctx context.Context, guardian *models.Guardian) error {
+	guardian.CreatedAt = time.Now()
+	guardian.UpdatedAt = guardian.CreatedAt
+	if guardian.ID.IsZero() {
+		guardian.ID = primitive.NewObjectID()
+	}
+	if guardian.CricketerIDs == nil {
+		guardian.CricketerIDs = []primitive.ObjectID{}
+	}
+
+	_, err := m.guardianCollection.InsertOne(ctx, guardian)
+	return err
+}
+
+// GetGuardianByID retrieves a guardian by their ID
+func (m *MongoDB) GetGuardianByID(ctx context.Context, id primitive.ObjectID) (*models.Guardian, error) {
+	var guardian models.Guardian
+	err := m.guardianCollection.FindOne(ctx, bson.M{"_id": id}).Decode(&guardian)
+	if err != nil {
+		return nil, err
+	}
+	return &guardian, nil
+}
+
+// GetGuardianByMobile retrieves a guardian by their mobile number
+func (m *MongoDB) GetGuardianByMobile(ctx context.Context, mobile string) (*models.Guardian, error) {
+	var guardian models.Guardian
+	err := m.guardianCollection.FindOne(ctx, bson.M{"mobile": mobile}).Decode(&guardian)
+	if err != nil {
+		return nil, err
+	}
+	return &guardian, nil
+}
+
+// GetAllGuardians retrieves all guardians sorted by name
+func (m *MongoDB) GetAllGuardians(ctx context.Context) ([]models.Guardian, error) {
+	return m.findGuardians(ctx, bson.M{})
+}
+
+// GetGuardiansForCricketer retrieves the guardians linked to a cricketer
+func (m *MongoDB) GetGuardiansForCricketer(ctx context.Context, cricketerID primitive.ObjectID) ([]models.Guardian, error) {
+	return m.findGuardians(ctx, bson.M{"cricketerIds": cricketerID})
+}
+
+// UpdateGuardian updates a guardian's name, email, active flag and linked children
+func (m *MongoDB) UpdateGuardian(ctx context.Context, id primitive.ObjectID, guardian *models.Guardian) error {
+	guardian.UpdatedAt = time.Now()
+	if guardian.CricketerIDs == nil {
+		guardian.CricketerIDs = []primitive.ObjectID{}
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"name":         guardian.Name,
+			"email":        guardian.Email,
+			"isActive":     guardian.IsActive,
+			"cricketerIds": guardian.CricketerIDs,
+			"updatedAt":    guardian.UpdatedAt,
+		},
+	}
+
+	result, err := m.guardianCollection.UpdateOne(ctx, bson.M{"_id": id}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// LinkGuardianToCricketer adds a cricketer to a guardian's children. Linking an already linked child has no effect.
+func (m *MongoDB) LinkGuardianToCricketer(ctx context.Context, guardianID primitive.ObjectID, cricketerID primitive.ObjectID) error {
+	update := bson.M{
+		"$addToSet": bson.M{"cricketerIds": cricketerID},
+		"$set":      bson.M{"updatedAt": time.Now()},
+	}
+
+	result, err := m.guardianCollection.UpdateOne(ctx, bson.M{"_id": guardianID}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+func (m *MongoDB) findGuardians(ctx context.Context, filter bson.M) ([]models.Guardian, error) {
+	cursor, err := m.guardianCollection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "name", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	guardians := []models.Guardian{}
+	if err = cursor.All(ctx, &guardians); err != nil {
+		return nil, err
+	}
+	return guardians, nil
+}
@@ -0,0 +1,31 @@
+package db
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"cricketApp/models"
+)
+
+// GetIDCard retrieves the ID card issued to a cricketer
+func (m *MongoDB) GetIDCard(ctx context.Context, cricketerID primitive.ObjectID) (*models.IDCard, error) {
+	var card models.IDCard
+	err := m.idCardCollection.FindOne(ctx, bson.M{"cricketerId": cricketerID}).Decode(&card)
+	if err != nil {
+		return nil, err
+	}
+	return &card, nil
+}
+
+// SaveIDCard saves a cricketer's ID card, replacing the one issued before
+func (m *MongoDB) SaveIDCard(ctx context.Context, card *models.IDCard) error {
+	if card.ID.IsZero() {
+		card.ID = primitive.NewObjectID()
+	}
+
+	_, err := m.idCardCollection.ReplaceOne(ctx, bson.M{"cricketerId": card.CricketerID}, card, options.Replace().SetUpsert(true))
+	return err
+}
@@ -0,0 +1,118 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"cricketApp/models"
+)
+
+// CreateInjury records a new injury
+func (m *MongoDB) CreateInjury(ctx context.Context, injury *models.Injury) error {
+	injury.CreatedAt = time.Now()
+	injury.UpdatedAt = injury.CreatedAt
+	if injury.ID.IsZero() {
+		injury.ID = primitive.NewObjectID()
+	}
+
+	_, err := m.injuryCollection.InsertOne(ctx, injury)
+	return err
+}
+
+// GetInjuryByID retrieves an injury by ID
+func (m *MongoDB) GetInjuryByID(ctx context.Context, id primitive.ObjectID) (*models.Injury, error) {
+	var injury models.Injury
+	err := m.injuryCollection.FindOne(ctx, bson.M{"_id": id}).Decode(&injury)
+	if err != nil {
+		return nil, err
+	}
+	return &injury, nil
+}
+
+// GetInjuries retrieves the injuries matching the filter, most recent injury first
+func (m *MongoDB) GetInjuries(ctx context.Context, filter models.InjuryFilter) ([]models.Injury, error) {
+	query := bson.M{}
+	if filter.CricketerID != nil {
+		query["cricketerId"] = *filter.CricketerID
+	}
+	if filter.BatchIDs != nil {
+		query["batchId"] = bson.M{"$in": filter.BatchIDs}
+	}
+	if filter.ActiveOnly {
+		query["status"] = bson.M{"$ne": models.InjuryFullyFit}
+	}
+	injuredOn := bson.M{}
+	if filter.From != nil {
+		injuredOn["$gte"] = *filter.From
+	}
+	if filter.To != nil {
+		injuredOn["$lt"] = *filter.To
+	}
+	if len(injuredOn) > 0 {
+		query["injuredOn"] = injuredOn
+	}
+
+	cursor, err := m.injuryCollection.Find(ctx, query, options.Find().SetSort(bson.D{{Key: "injuredOn", Value: -1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	injuries := []models.Injury{}
+	if err = cursor.All(ctx, &injuries); err != nil {
+		return nil, err
+	}
+	return injuries, nil
+}
+
+// GetActiveInjuries retrieves the injuries of the given cricketers that are not yet cleared as fully fit
+func (m *MongoDB) GetActiveInjuries(ctx context.Context, cricketerIDs []primitive.ObjectID) ([]models.Injury, error) {
+	query := bson.M{
+		"cricketerId": bson.M{"$in": cricketerIDs},
+		"status":      bson.M{"$ne": models.InjuryFullyFit},
+	}
+
+	cursor, err := m.injuryCollection.Find(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	injuries := []models.Injury{}
+	if err = cursor.All(ctx, &injuries); err != nil {
+		return nil, err
+	}
+	return injuries, nil
+}
+
+// UpdateInjuryStatus saves an injury's status, restrictions, clearance and history. It fails with
+// mongo.ErrNoDocuments if the status changed since the injury was read.
+func (m *MongoDB) UpdateInjuryStatus(ctx context.Context, id primitive.ObjectID, previousStatus string, injury *models.Injury) error {
+	injury.UpdatedAt = time.Now()
+
+	update := bson.M{
+		"$set": bson.M{
+			"status":        injury.Status,
+			"restrictions":  injury.Restrictions,
+			"clearedBy":     injury.ClearedBy,
+			"clearedByName": injury.ClearedByName,
+			"clearedAt":     injury.ClearedAt,
+			"history":       injury.History,
+			"updatedAt":     injury.UpdatedAt,
+		},
+	}
+
+	result, err := m.injuryCollection.UpdateOne(ctx, bson.M{"_id": id, "status": previousStatus}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
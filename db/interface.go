@@ -20,6 +20,12 @@ type Database interface {
 	UpdateCricketerJoiningDate(ctx context.Context, id primitive.ObjectID, joiningDate *time.Time) error
 	UpdateCricketerDueDate(ctx context.Context, id primitive.ObjectID, dueDate *time.Time) error
 	UpdateCricketerInactiveStatus(ctx context.Context, id primitive.ObjectID, isInactive bool) error
+	UpdateCricketerBatch(ctx context.Context, id primitive.ObjectID, batchID *primitive.ObjectID) error
+	GetCricketersByBatches(ctx context.Context, batchIDs []primitive.ObjectID) ([]models.Cricketer, error)
+	UpdateCricketerDateOfBirth(ctx context.Context, id primitive.ObjectID, dateOfBirth time.Time) error
+	UpdateCricketerGender(ctx context.Context, id primitive.ObjectID, gender string) error
+	SetCricketerPasswordSetup(ctx context.Context, id primitive.ObjectID, tokenHash string, expiresAt time.Time) error
+	CompleteCricketerPasswordSetup(ctx context.Context, tokenHash string, hashedPassword string, now time.Time) (*models.Cricketer, error)
 
 	// Coach operations
 	CreateCoach(ctx context.Context, coach *models.Coach) error
@@ -28,13 +34,37 @@ type Database interface {
 	GetAllCoaches(ctx context.Context) ([]models.Coach, error)
 	UpdateCoach(ctx context.Context, id primitive.ObjectID, coach *models.Coach) error
 
+	// Batch operations
+	CreateBatch(ctx context.Context, batch *models.Batch) error
+	GetBatchByID(ctx context.Context, id primitive.ObjectID) (*models.Batch, error)
+	GetAllBatches(ctx context.Context) ([]models.Batch, error)
+	GetBatchesByCoach(ctx context.Context, coachID primitive.ObjectID) ([]models.Batch, error)
+	UpdateBatch(ctx context.Context, id primitive.ObjectID, batch *models.Batch) error
+
 	// Admin operations
 	GetAdminByEmail(ctx context.Context, email string) (*models.Admin, error)
 	GetAdminByID(ctx context.Context, id primitive.ObjectID) (*models.Admin, error)
+	UpdateAdminPermissions(ctx context.Context, id primitive.ObjectID, permissions []string) error
+	BootstrapAdminPermission(ctx context.Context, email string, permission string) (bool, error)
 
 	// Announcement operations
 	CreateAnnouncement(ctx context.Context, announcement *models.Announcement) (*models.Announcement, error)
 	GetAllAnnouncements(ctx context.Context) ([]models.Announcement, error)
+	GetAnnouncementByID(ctx context.Context, id primitive.ObjectID) (*models.Announcement, error)
+	ListAnnouncements(ctx context.Context, filter models.AnnouncementFilter) ([]models.Announcement, error)
+	UpdateAnnouncement(ctx context.Context, id primitive.ObjectID, announcement *models.Announcement, previous models.AnnouncementRevision) error
+	DeleteAnnouncement(ctx context.Context, id primitive.ObjectID, deletedBy string) error
+
+	// Announcement receipt operations
+	MarkAnnouncementRead(ctx context.Context, announcementID primitive.ObjectID, cricketerID primitive.ObjectID) (*models.AnnouncementReceipt, error)
+	AcknowledgeAnnouncement(ctx context.Context, announcementID primitive.ObjectID, cricketerID primitive.ObjectID, acknowledgedBy string) (*models.AnnouncementReceipt, error)
+	GetAnnouncementReceipts(ctx context.Context, announcementID primitive.ObjectID) ([]models.AnnouncementReceipt, error)
+	GetAnnouncementReceiptsByCricketer(ctx context.Context, cricketerID primitive.ObjectID) ([]models.AnnouncementReceipt, error)
+
+	// Attachment operations
+	CreateAttachment(ctx context.Context, attachment *models.Attachment) error
+	GetAttachmentByID(ctx context.Context, id primitive.ObjectID) (*models.Attachment, error)
+	GetAttachmentsByIDs(ctx context.Context, ids []primitive.ObjectID) ([]models.Attachment, error)
 
 	// Session methods
 	CreateSession(ctx context.Context, session *models.Session) error
@@ -43,10 +73,202 @@ type Database interface {
 	GetAllSessions(ctx context.Context) ([]*models.Session, error)
 	UpdateSession(ctx context.Context, id primitive.ObjectID, session *models.Session) error
 	DeleteSession(ctx context.Context, id primitive.ObjectID) error
+	GetSessionsForBatches(ctx context.Context, batchIDs []primitive.ObjectID, from time.Time, to time.Time) ([]*models.Session, error)
+	GetSessionsBetween(ctx context.Context, from time.Time, to time.Time) ([]*models.Session, error)
+
+	// Attendance operations
+	SaveAttendance(ctx context.Context, records []models.Attendance) error
+	GetAttendanceForSession(ctx context.Context, sessionID primitive.ObjectID) ([]models.Attendance, error)
+	GetAttendanceForCricketer(ctx context.Context, cricketerID primitive.ObjectID, since time.Time) ([]models.Attendance, error)
+
+	// Guardian operations
+	CreateGuardian(ctx context.Context, guardian *models.Guardian) error
+	GetGuardianByID(ctx context.Context, id primitive.ObjectID) (*models.Guardian, error)
+	GetGuardianByMobile(ctx context.Context, mobile string) (*models.Guardian, error)
+	GetAllGuardians(ctx context.Context) ([]models.Guardian, error)
+	GetGuardiansForCricketer(ctx context.Context, cricketerID primitive.ObjectID) ([]models.Guardian, error)
+	UpdateGuardian(ctx context.Context, id primitive.ObjectID, guardian *models.Guardian) error
+	LinkGuardianToCricketer(ctx context.Context, guardianID primitive.ObjectID, cricketerID primitive.ObjectID) error
+
+	// Fee payment operations
+	CreateFeePayment(ctx context.Context, payment *models.FeePayment, dueDate *time.Time) error
+	GetFeePaymentByIdempotencyKey(ctx context.Context, key string) (*models.FeePayment, error)
+	GetFeePayments(ctx context.Context, cricketerID primitive.ObjectID) ([]models.FeePayment, error)
 
 	// Registration methods
 	CreateRegistration(ctx context.Context, registration *models.RegistrationForm) error
 	GetRegistrationByID(ctx context.Context, id primitive.ObjectID) (*models.RegistrationForm, error)
-	GetAllRegistrations(ctx context.Context) ([]*models.RegistrationForm, error)
+	SearchRegistrations(ctx context.Context, filter models.RegistrationFilter) ([]*models.RegistrationForm, int64, error)
+	StreamRegistrations(ctx context.Context, filter models.RegistrationFilter, fn func(*models.RegistrationForm) error) error
 	UpdateRegistration(ctx context.Context, id primitive.ObjectID, registration *models.RegistrationForm) error
+	TransitionRegistration(ctx context.Context, id primitive.ObjectID, change models.RegistrationStatusChange) error
+	ApproveRegistration(ctx context.Context, id primitive.ObjectID, approval models.RegistrationApproval) (*models.Cricketer, bool, error)
+	FindDuplicateFormNumbers(ctx context.Context) ([]models.DuplicateFormNo, error)
+	FindRegistrationDuplicates(ctx context.Context, aadhaarNo string, contactNos []string, fullName string, dateOfBirth time.Time) ([]models.DuplicateMatch, error)
+
+	MigrateRegistrationPII(ctx context.Context) (int, error)
+
+	// Registration document operations
+	SaveRegistrationDocument(ctx context.Context, document *models.RegistrationDocument) (*models.RegistrationDocument, error)
+	GetRegistrationDocument(ctx context.Context, registrationID primitive.ObjectID, documentType string) (*models.RegistrationDocument, error)
+	GetRegistrationDocuments(ctx context.Context, registrationID primitive.ObjectID) ([]models.RegistrationDocument, error)
+	ReviewRegistrationDocument(ctx context.Context, registrationID primitive.ObjectID, documentType string, key string, status string, note string, reviewedBy string) error
+
+	// Season operations
+	CreateSeason(ctx context.Context, season *models.Season) error
+	GetSeasonByID(ctx context.Context, id primitive.ObjectID) (*models.Season, error)
+	GetAllSeasons(ctx context.Context) ([]models.Season, error)
+	GetSeasonForDate(ctx context.Context, date time.Time) (*models.Season, error)
+	UpdateSeason(ctx context.Context, id primitive.ObjectID, season *models.Season) error
+	SeasonOverlaps(ctx context.Context, excludeID primitive.ObjectID, start time.Time, end time.Time) (bool, error)
+
+	// Audit log operations
+	CreateAuditEntry(ctx context.Context, entry *models.AuditEntry) error
+	GetAuditEntries(ctx context.Context, filter models.AuditFilter) ([]models.AuditEntry, error)
+
+	// Contact verification operations
+	CreateVerification(ctx context.Context, verification *models.ContactVerification) error
+	GetVerificationByID(ctx context.Context, id primitive.ObjectID) (*models.ContactVerification, error)
+	RecordVerificationAttempt(ctx context.Context, id primitive.ObjectID) (*models.ContactVerification, error)
+	MarkVerificationVerified(ctx context.Context, id primitive.ObjectID) error
+	ConsumeVerification(ctx context.Context, id primitive.ObjectID, channel string, target string, notBefore time.Time) error
+
+	// Medical profile operations
+	SaveMedicalProfile(ctx context.Context, profile *models.MedicalProfile) error
+	GetMedicalProfile(ctx context.Context, cricketerID primitive.ObjectID) (*models.MedicalProfile, error)
+	GetMedicalProfiles(ctx context.Context, cricketerIDs []primitive.ObjectID) ([]models.MedicalProfile, error)
+	RewrapMedicalProfiles(ctx context.Context) (int, error)
+
+	// Consent operations
+	PublishConsentDocument(ctx context.Context, document *models.ConsentDocument) error
+	GetConsentDocumentByID(ctx context.Context, id primitive.ObjectID) (*models.ConsentDocument, error)
+	GetCurrentConsentDocument(ctx context.Context, key string) (*models.ConsentDocument, error)
+	GetCurrentConsentDocuments(ctx context.Context) ([]models.ConsentDocument, error)
+	GetConsentDocumentVersions(ctx context.Context, key string) ([]models.ConsentDocument, error)
+	CreateConsentSignature(ctx context.Context, signature *models.ConsentSignature) error
+	GetConsentSignatures(ctx context.Context, cricketerIDs []primitive.ObjectID) ([]models.ConsentSignature, error)
+
+	// Match operations
+	CreateMatch(ctx context.Context, match *models.Match) error
+	GetMatchByID(ctx context.Context, id primitive.ObjectID) (*models.Match, error)
+	GetMatches(ctx context.Context, filter models.MatchFilter) ([]models.Match, error)
+	UpdateMatch(ctx context.Context, id primitive.ObjectID, match *models.Match) error
+	DeleteMatch(ctx context.Context, id primitive.ObjectID) error
+	GetCareerStats(ctx context.Context, cricketerID primitive.ObjectID) (*models.CareerStats, error)
+
+	// Ball-by-ball scoring operations
+	AppendBallEvent(ctx context.Context, event *models.BallEvent) error
+	GetBallEvents(ctx context.Context, matchID primitive.ObjectID) ([]models.BallEvent, error)
+	SaveMatchScore(ctx context.Context, matchID primitive.ObjectID, innings []models.Innings, result models.MatchResult) error
+
+	// Assessment operations
+	CreateAssessmentTemplate(ctx context.Context, template *models.AssessmentTemplate) error
+	GetAssessmentTemplateByID(ctx context.Context, id primitive.ObjectID) (*models.AssessmentTemplate, error)
+	GetAssessmentTemplates(ctx context.Context, activeOnly bool) ([]models.AssessmentTemplate, error)
+	UpdateAssessmentTemplate(ctx context.Context, id primitive.ObjectID, template *models.AssessmentTemplate) error
+	EnsureDefaultAssessmentTemplates(ctx context.Context) (int, error)
+	CreateAssessment(ctx context.Context, assessment *models.Assessment) error
+	GetAssessmentByID(ctx context.Context, id primitive.ObjectID) (*models.Assessment, error)
+	GetAssessments(ctx context.Context, filter models.AssessmentFilter) ([]models.Assessment, error)
+	UpdateAssessment(ctx context.Context, id primitive.ObjectID, assessment *models.Assessment) error
+
+	// Fitness operations
+	CreateFitnessTest(ctx context.Context, test *models.FitnessTest) error
+	GetFitnessTestByID(ctx context.Context, id primitive.ObjectID) (*models.FitnessTest, error)
+	GetFitnessTests(ctx context.Context, activeOnly bool) ([]models.FitnessTest, error)
+	UpdateFitnessTest(ctx context.Context, id primitive.ObjectID, test *models.FitnessTest) error
+	EnsureDefaultFitnessTests(ctx context.Context) (int, error)
+	CreateFitnessResults(ctx context.Context, results []models.FitnessResult) error
+	GetFitnessResults(ctx context.Context, filter models.FitnessResultFilter) ([]models.FitnessResult, error)
+	GetPreviousFitnessResult(ctx context.Context, cricketerID primitive.ObjectID, testID primitive.ObjectID, before time.Time) (*models.FitnessResult, error)
+	GetLatestFitnessResults(ctx context.Context, testID primitive.ObjectID, ageCategory string, from time.Time, to time.Time) ([]models.FitnessResult, error)
+	CreateFitnessAlert(ctx context.Context, alert *models.FitnessAlert) error
+	GetFitnessAlertByID(ctx context.Context, id primitive.ObjectID) (*models.FitnessAlert, error)
+	GetFitnessAlerts(ctx context.Context, filter models.FitnessAlertFilter) ([]models.FitnessAlert, error)
+	AcknowledgeFitnessAlert(ctx context.Context, id primitive.ObjectID, acknowledgedBy string) (*models.FitnessAlert, error)
+
+	// Note operations
+	CreateNote(ctx context.Context, note *models.CricketerNote) error
+	GetNoteByID(ctx context.Context, id primitive.ObjectID) (*models.CricketerNote, error)
+	GetNotes(ctx context.Context, filter models.NoteFilter) ([]models.CricketerNote, error)
+	UpdateNote(ctx context.Context, id primitive.ObjectID, note *models.CricketerNote) error
+	DeleteNote(ctx context.Context, id primitive.ObjectID) error
+
+	// Workload operations
+	SaveWorkloadEntries(ctx context.Context, entries []models.WorkloadEntry) error
+	GetWorkloadEntries(ctx context.Context, cricketerIDs []primitive.ObjectID, from time.Time, to time.Time) ([]models.WorkloadEntry, error)
+	GetWorkloadEntriesForSession(ctx context.Context, sessionID primitive.ObjectID) ([]models.WorkloadEntry, error)
+	GetWorkloadLimits(ctx context.Context) ([]models.WorkloadLimit, error)
+	SaveWorkloadLimit(ctx context.Context, limit *models.WorkloadLimit) error
+	EnsureDefaultWorkloadLimits(ctx context.Context) (int, error)
+	CreateWorkloadAlert(ctx context.Context, alert *models.WorkloadAlert) (bool, error)
+	GetWorkloadAlertByID(ctx context.Context, id primitive.ObjectID) (*models.WorkloadAlert, error)
+	GetWorkloadAlerts(ctx context.Context, filter models.WorkloadAlertFilter) ([]models.WorkloadAlert, error)
+	AcknowledgeWorkloadAlert(ctx context.Context, id primitive.ObjectID, acknowledgedBy string) (*models.WorkloadAlert, error)
+
+	// Injury operations
+	CreateInjury(ctx context.Context, injury *models.Injury) error
+	GetInjuryByID(ctx context.Context, id primitive.ObjectID) (*models.Injury, error)
+	GetInjuries(ctx context.Context, filter models.InjuryFilter) ([]models.Injury, error)
+	GetActiveInjuries(ctx context.Context, cricketerIDs []primitive.ObjectID) ([]models.Injury, error)
+	UpdateInjuryStatus(ctx context.Context, id primitive.ObjectID, previousStatus string, injury *models.Injury) error
+
+	// Tournament operations
+	CreateTournament(ctx context.Context, tournament *models.Tournament) error
+	GetTournamentByID(ctx context.Context, id primitive.ObjectID) (*models.Tournament, error)
+	GetTournaments(ctx context.Context, endingFrom *time.Time) ([]models.Tournament, error)
+	UpdateTournament(ctx context.Context, id primitive.ObjectID, tournament *models.Tournament) error
+	SaveTournamentSquad(ctx context.Context, id primitive.ObjectID, squad []models.SquadMember, selectedBy string) error
+	CreateFixture(ctx context.Context, fixture *models.Fixture) error
+	GetFixtureByID(ctx context.Context, id primitive.ObjectID) (*models.Fixture, error)
+	GetFixtures(ctx context.Context, tournamentID primitive.ObjectID) ([]models.Fixture, error)
+	UpdateFixture(ctx context.Context, id primitive.ObjectID, fixture *models.Fixture) error
+	SaveFixturePlayingXI(ctx context.Context, id primitive.ObjectID, xi *models.PlayingXI) error
+	CreateAvailabilityRequests(ctx context.Context, requests []models.Availability) ([]models.Availability, error)
+	GetTournamentAvailability(ctx context.Context, tournamentID primitive.ObjectID) ([]models.Availability, error)
+	GetCricketerAvailability(ctx context.Context, cricketerID primitive.ObjectID) ([]models.Availability, error)
+	RespondToAvailability(ctx context.Context, tournamentID primitive.ObjectID, cricketerID primitive.ObjectID, response string, note string, respondedBy string, respondedByRole string) (*models.Availability, error)
+
+	// League operations
+	CreateLeague(ctx context.Context, league *models.League) error
+	GetLeagueByID(ctx context.Context, id primitive.ObjectID) (*models.League, error)
+	GetLeagues(ctx context.Context) ([]models.League, error)
+	CreateLeagueFixtures(ctx context.Context, fixtures []models.LeagueFixture) error
+	DeleteLeagueFixtures(ctx context.Context, leagueID primitive.ObjectID, stages []string) error
+	GetLeagueFixtureByID(ctx context.Context, id primitive.ObjectID) (*models.LeagueFixture, error)
+	GetLeagueFixtures(ctx context.Context, leagueID primitive.ObjectID) ([]models.LeagueFixture, error)
+	GetLeagueFixturesBetween(ctx context.Context, from time.Time, to time.Time) ([]models.LeagueFixture, error)
+	SaveLeagueResult(ctx context.Context, id primitive.ObjectID, result *models.LeagueResult) error
+	SetLeagueFixtureTeam(ctx context.Context, slot models.BracketSlot, teamID *primitive.ObjectID) error
+
+	// ID card operations
+	GetIDCard(ctx context.Context, cricketerID primitive.ObjectID) (*models.IDCard, error)
+	SaveIDCard(ctx context.Context, card *models.IDCard) error
+
+	// Profile photo operations
+	CreatePhoto(ctx context.Context, photo *models.ProfilePhoto) error
+	GetPhotoByID(ctx context.Context, id primitive.ObjectID) (*models.ProfilePhoto, error)
+	GetPhotosByIDs(ctx context.Context, ids []primitive.ObjectID) ([]models.ProfilePhoto, error)
+	GetLatestPhoto(ctx context.Context, ownerType string, ownerID primitive.ObjectID) (*models.ProfilePhoto, error)
+	GetPhotos(ctx context.Context, status string) ([]models.ProfilePhoto, error)
+	ReplacePhoto(ctx context.Context, id primitive.ObjectID) error
+	RejectPhoto(ctx context.Context, id primitive.ObjectID, rejectedBy string, reason string) error
+	SetOwnerPhoto(ctx context.Context, ownerType string, ownerID primitive.ObjectID, photoID *primitive.ObjectID) error
+
+	// Equipment operations
+	CreateEquipmentItem(ctx context.Context, item *models.EquipmentItem) error
+	GetEquipmentItemByID(ctx context.Context, id primitive.ObjectID) (*models.EquipmentItem, error)
+	GetEquipmentItems(ctx context.Context, filter models.EquipmentFilter) ([]models.EquipmentItem, error)
+	UpdateEquipmentItemDetails(ctx context.Context, id primitive.ObjectID, item *models.EquipmentItem) error
+	AdjustEquipmentStock(ctx context.Context, id primitive.ObjectID, adjustment models.EquipmentAdjustment) error
+	ReserveEquipment(ctx context.Context, id primitive.ObjectID, quantity int) error
+	RestockEquipment(ctx context.Context, id primitive.ObjectID, returned int, writeOff *models.EquipmentAdjustment) error
+	CreateEquipmentIssue(ctx context.Context, issue *models.EquipmentIssue) error
+	GetEquipmentIssueByID(ctx context.Context, id primitive.ObjectID) (*models.EquipmentIssue, error)
+	GetEquipmentIssues(ctx context.Context, filter models.EquipmentIssueFilter) ([]models.EquipmentIssue, error)
+	ReturnEquipmentIssue(ctx context.Context, id primitive.ObjectID, equipmentReturn models.EquipmentReturn) error
+	MarkEquipmentIssuesReminded(ctx context.Context, ids []primitive.ObjectID, at time.Time) error
+
+	// Counter operations
+	NextSequence(ctx context.Context, name string) (int64, error)
 }
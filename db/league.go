@@ -0,0 +1,164 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"cricketApp/models"
+)
+
+// CreateLeague inserts a new league
+func (m *MongoDB) CreateLeague(ctx context.Context, league *models.League) error {
+	league.CreatedAt = time.Now()
+	league.UpdatedAt = league.CreatedAt
+	if league.ID.IsZero() {
+		league.ID = primitive.NewObjectID()
+	}
+
+	_, err := m.leagueCollection.InsertOne(ctx, league)
+	return err
+}
+
+// GetLeagueByID retrieves a league by ID
+func (m *MongoDB) GetLeagueByID(ctx context.Context, id primitive.ObjectID) (*models.League, error) {
+	var league models.League
+	err := m.leagueCollection.FindOne(ctx, bson.M{"_id": id}).Decode(&league)
+	if err != nil {
+		return nil, err
+	}
+	return &league, nil
+}
+
+// GetLeagues retrieves every league, newest first
+func (m *MongoDB) GetLeagues(ctx context.Context) ([]models.League, error) {
+	cursor, err := m.leagueCollection.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	leagues := []models.League{}
+	if err = cursor.All(ctx, &leagues); err != nil {
+		return nil, err
+	}
+	return leagues, nil
+}
+
+// CreateLeagueFixtures inserts a league's fixtures. Fixtures without an ID are given one.
+func (m *MongoDB) CreateLeagueFixtures(ctx context.Context, fixtures []models.LeagueFixture) error {
+	if len(fixtures) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	documents := make([]interface{}, len(fixtures))
+	for i := range fixtures {
+		fixtures[i].CreatedAt = now
+		fixtures[i].UpdatedAt = now
+		if fixtures[i].ID.IsZero() {
+			fixtures[i].ID = primitive.NewObjectID()
+		}
+		documents[i] = fixtures[i]
+	}
+
+	_, err := m.leagueFixtureCollection.InsertMany(ctx, documents)
+	return err
+}
+
+// DeleteLeagueFixtures deletes a league's fixtures in the given stages
+func (m *MongoDB) DeleteLeagueFixtures(ctx context.Context, leagueID primitive.ObjectID, stages []string) error {
+	_, err := m.leagueFixtureCollection.DeleteMany(ctx, bson.M{"leagueId": leagueID, "stage": bson.M{"$in": stages}})
+	return err
+}
+
+// GetLeagueFixtureByID retrieves a league fixture by ID
+func (m *MongoDB) GetLeagueFixtureByID(ctx context.Context, id primitive.ObjectID) (*models.LeagueFixture, error) {
+	var fixture models.LeagueFixture
+	err := m.leagueFixtureCollection.FindOne(ctx, bson.M{"_id": id}).Decode(&fixture)
+	if err != nil {
+		return nil, err
+	}
+	return &fixture, nil
+}
+
+// GetLeagueFixtures retrieves a league's fixtures in the order they are played
+func (m *MongoDB) GetLeagueFixtures(ctx context.Context, leagueID primitive.ObjectID) ([]models.LeagueFixture, error) {
+	cursor, err := m.leagueFixtureCollection.Find(ctx, bson.M{"leagueId": leagueID},
+		options.Find().SetSort(bson.D{{Key: "startsAt", Value: 1}, {Key: "venue", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	fixtures := []models.LeagueFixture{}
+	if err = cursor.All(ctx, &fixtures); err != nil {
+		return nil, err
+	}
+	return fixtures, nil
+}
+
+// GetLeagueFixturesBetween retrieves the fixtures of every league that overlap the period between from and to
+func (m *MongoDB) GetLeagueFixturesBetween(ctx context.Context, from time.Time, to time.Time) ([]models.LeagueFixture, error) {
+	filter := bson.M{
+		"startsAt": bson.M{"$lt": to},
+		"endsAt":   bson.M{"$gt": from},
+	}
+	cursor, err := m.leagueFixtureCollection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "startsAt", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	fixtures := []models.LeagueFixture{}
+	if err = cursor.All(ctx, &fixtures); err != nil {
+		return nil, err
+	}
+	return fixtures, nil
+}
+
+// SaveLeagueResult records a league fixture's result, replacing any earlier one
+func (m *MongoDB) SaveLeagueResult(ctx context.Context, id primitive.ObjectID, result *models.LeagueResult) error {
+	update := bson.M{
+		"$set": bson.M{
+			"result":    result,
+			"updatedAt": time.Now(),
+		},
+	}
+
+	updated, err := m.leagueFixtureCollection.UpdateOne(ctx, bson.M{"_id": id}, update)
+	if err != nil {
+		return err
+	}
+	if updated.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// SetLeagueFixtureTeam puts a team, or nobody when teamID is nil, into one side of a knockout fixture
+func (m *MongoDB) SetLeagueFixtureTeam(ctx context.Context, slot models.BracketSlot, teamID *primitive.ObjectID) error {
+	field := "awayTeamId"
+	if slot.Home {
+		field = "homeTeamId"
+	}
+	update := bson.M{
+		"$set": bson.M{
+			field:       teamID,
+			"updatedAt": time.Now(),
+		},
+	}
+
+	result, err := m.leagueFixtureCollection.UpdateOne(ctx, bson.M{"_id": slot.FixtureID}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
@@ -0,0 +1,222 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"cricketApp/models"
+)
+
+// CreateMatch inserts a new match
+func (m *MongoDB) CreateMatch(ctx context.Context, match *models.Match) error {
+	match.CreatedAt = time.Now()
+	match.UpdatedAt = match.CreatedAt
+	if match.ID.IsZero() {
+		match.ID = primitive.NewObjectID()
+	}
+
+	_, err := m.matchCollection.InsertOne(ctx, match)
+	return err
+}
+
+// GetMatchByID retrieves a match by ID
+func (m *MongoDB) GetMatchByID(ctx context.Context, id primitive.ObjectID) (*models.Match, error) {
+	var match models.Match
+	err := m.matchCollection.FindOne(ctx, bson.M{"_id": id}).Decode(&match)
+	if err != nil {
+		return nil, err
+	}
+	return &match, nil
+}
+
+// GetMatches retrieves matches matching the filter, most recent first
+func (m *MongoDB) GetMatches(ctx context.Context, filter models.MatchFilter) ([]models.Match, error) {
+	query := bson.M{}
+	if filter.CricketerID != nil {
+		query["$or"] = playedInQuery(*filter.CricketerID)
+	}
+	if filter.Status != "" {
+		query["result.status"] = filter.Status
+	}
+	if filter.From != nil || filter.To != nil {
+		date := bson.M{}
+		if filter.From != nil {
+			date["$gte"] = *filter.From
+		}
+		if filter.To != nil {
+			date["$lt"] = *filter.To
+		}
+		query["date"] = date
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "date", Value: -1}})
+	if filter.Limit > 0 {
+		opts.SetLimit(int64(filter.Limit))
+	}
+	cursor, err := m.matchCollection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	matches := []models.Match{}
+	if err = cursor.All(ctx, &matches); err != nil {
+		return nil, err
+	}
+	return matches, nil
+}
+
+// UpdateMatch replaces a match's details and scorecard
+func (m *MongoDB) UpdateMatch(ctx context.Context, id primitive.ObjectID, match *models.Match) error {
+	match.UpdatedAt = time.Now()
+
+	update := bson.M{
+		"$set": bson.M{
+			"title":           match.Title,
+			"type":            match.Type,
+			"format":          match.Format,
+			"oversPerInnings": match.OversPerInnings,
+			"date":            match.Date,
+			"venue":           match.Venue,
+			"teams":           match.Teams,
+			"tossWinner":      match.TossWinner,
+			"tossDecision":    match.TossDecision,
+			"innings":         match.Innings,
+			"result":          match.Result,
+			"updatedAt":       match.UpdatedAt,
+		},
+	}
+
+	result, err := m.matchCollection.UpdateOne(ctx, bson.M{"_id": id}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// DeleteMatch removes a match and its ball-by-ball scoring log
+func (m *MongoDB) DeleteMatch(ctx context.Context, id primitive.ObjectID) error {
+	result, err := m.matchCollection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	_, err = m.ballEventCollection.DeleteMany(ctx, bson.M{"matchId": id})
+	return err
+}
+
+// GetCareerStats totals a cricketer's batting and bowling across matches that count towards
+// statistics. Averages, strike rates and economy are left for the caller to derive.
+func (m *MongoDB) GetCareerStats(ctx context.Context, cricketerID primitive.ObjectID) (*models.CareerStats, error) {
+	statuses := bson.M{"$in": models.StatsMatchStatuses}
+
+	matches, err := m.matchCollection.CountDocuments(ctx, bson.M{
+		"result.status": statuses,
+		"$or":           playedInQuery(cricketerID),
+	})
+	if err != nil {
+		return nil, err
+	}
+	stats := &models.CareerStats{Matches: int(matches)}
+
+	// Highest score is ranked as runs*2 plus one for not out, so an unbeaten score beats an equal dismissed one
+	notOut := bson.M{"$cond": bson.A{
+		bson.M{"$in": bson.A{"$innings.batting.dismissal", bson.A{models.DismissalNotOut, models.DismissalRetiredHurt}}}, 1, 0,
+	}}
+	runs := "$innings.batting.runs"
+	batting := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"result.status": statuses, "innings.batting.cricketerId": cricketerID}}},
+		{{Key: "$unwind", Value: "$innings"}},
+		{{Key: "$unwind", Value: "$innings.batting"}},
+		{{Key: "$match", Value: bson.M{
+			"innings.batting.cricketerId": cricketerID,
+			"innings.batting.dismissal":   bson.M{"$ne": models.DismissalDidNotBat},
+		}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":     nil,
+			"innings": bson.M{"$sum": 1},
+			"notOuts": bson.M{"$sum": notOut},
+			"runs":    bson.M{"$sum": runs},
+			"balls":   bson.M{"$sum": "$innings.batting.balls"},
+			"fours":   bson.M{"$sum": "$innings.batting.fours"},
+			"sixes":   bson.M{"$sum": "$innings.batting.sixes"},
+			"fifties": bson.M{"$sum": bson.M{"$cond": bson.A{
+				bson.M{"$and": bson.A{bson.M{"$gte": bson.A{runs, 50}}, bson.M{"$lt": bson.A{runs, 100}}}}, 1, 0,
+			}}},
+			"hundreds": bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$gte": bson.A{runs, 100}}, 1, 0}}},
+			"highest":  bson.M{"$max": bson.M{"$add": bson.A{bson.M{"$multiply": bson.A{runs, 2}}, notOut}}},
+		}}},
+	}
+	var battingTotals []struct {
+		models.BattingStats `bson:",inline"`
+		Highest             int `bson:"highest"`
+	}
+	if err := m.aggregateMatches(ctx, batting, &battingTotals); err != nil {
+		return nil, err
+	}
+	if len(battingTotals) > 0 {
+		stats.Batting = battingTotals[0].BattingStats
+		stats.Batting.HighestScore = battingTotals[0].Highest / 2
+		stats.Batting.HighestNotOut = battingTotals[0].Highest%2 == 1
+	}
+
+	// Sorting by wickets then runs conceded puts the best figures first in the group
+	bowling := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"result.status": statuses, "innings.bowling.cricketerId": cricketerID}}},
+		{{Key: "$unwind", Value: "$innings"}},
+		{{Key: "$unwind", Value: "$innings.bowling"}},
+		{{Key: "$match", Value: bson.M{"innings.bowling.cricketerId": cricketerID}}},
+		{{Key: "$sort", Value: bson.D{{Key: "innings.bowling.wickets", Value: -1}, {Key: "innings.bowling.runs", Value: 1}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":         nil,
+			"innings":     bson.M{"$sum": 1},
+			"balls":       bson.M{"$sum": "$innings.bowling.balls"},
+			"maidens":     bson.M{"$sum": "$innings.bowling.maidens"},
+			"runs":        bson.M{"$sum": "$innings.bowling.runs"},
+			"wickets":     bson.M{"$sum": "$innings.bowling.wickets"},
+			"wides":       bson.M{"$sum": "$innings.bowling.wides"},
+			"noBalls":     bson.M{"$sum": "$innings.bowling.noBalls"},
+			"fiveWickets": bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$gte": bson.A{"$innings.bowling.wickets", 5}}, 1, 0}}},
+			"bestWickets": bson.M{"$first": "$innings.bowling.wickets"},
+			"bestRuns":    bson.M{"$first": "$innings.bowling.runs"},
+		}}},
+	}
+	var bowlingTotals []models.BowlingStats
+	if err := m.aggregateMatches(ctx, bowling, &bowlingTotals); err != nil {
+		return nil, err
+	}
+	if len(bowlingTotals) > 0 {
+		stats.Bowling = bowlingTotals[0]
+	}
+
+	return stats, nil
+}
+
+// aggregateMatches runs a pipeline over the matches collection and decodes every result
+func (m *MongoDB) aggregateMatches(ctx context.Context, pipeline mongo.Pipeline, results interface{}) error {
+	cursor, err := m.matchCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+	return cursor.All(ctx, results)
+}
+
+// playedInQuery matches matches where the cricketer was picked in a team or appears on the scorecard
+func playedInQuery(cricketerID primitive.ObjectID) bson.A {
+	return bson.A{
+		bson.M{"teams.players.cricketerId": cricketerID},
+		bson.M{"innings.batting.cricketerId": cricketerID},
+		bson.M{"innings.bowling.cricketerId": cricketerID},
+	}
+}
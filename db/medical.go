@@ -0,0 +1,119 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"cricketApp/models"
+)
+
+// sealedMedicalProfile is the plaintext encrypted into MedicalProfile.Sealed
+type sealedMedicalProfile struct {
+	Medical           models.MedicalInfo        `json:"medical"`
+	EmergencyContacts []models.EmergencyContact `json:"emergencyContacts"`
+}
+
+// SaveMedicalProfile creates or replaces a cricketer's medical profile. The medical information and
+// emergency contacts are encrypted before they are stored.
+func (m *MongoDB) SaveMedicalProfile(ctx context.Context, profile *models.MedicalProfile) error {
+	plaintext, err := json.Marshal(sealedMedicalProfile{Medical: profile.Medical, EmergencyContacts: profile.EmergencyContacts})
+	if err != nil {
+		return err
+	}
+	sealed, err := m.pii.Encrypt(models.PIIFieldMedicalProfile, string(plaintext))
+	if err != nil {
+		return err
+	}
+	profile.Sealed = sealed
+
+	update := bson.M{
+		"$set": bson.M{
+			"sealed":        profile.Sealed,
+			"updatedBy":     profile.UpdatedBy,
+			"updatedByRole": profile.UpdatedByRole,
+			"updatedAt":     profile.UpdatedAt,
+		},
+	}
+	_, err = m.medicalProfileCollection.UpdateOne(ctx, bson.M{"cricketerId": profile.CricketerID}, update, options.Update().SetUpsert(true))
+	return err
+}
+
+// GetMedicalProfile retrieves and decrypts a cricketer's medical profile
+func (m *MongoDB) GetMedicalProfile(ctx context.Context, cricketerID primitive.ObjectID) (*models.MedicalProfile, error) {
+	var profile models.MedicalProfile
+	if err := m.medicalProfileCollection.FindOne(ctx, bson.M{"cricketerId": cricketerID}).Decode(&profile); err != nil {
+		return nil, err
+	}
+	if err := m.openMedicalProfile(&profile); err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+// GetMedicalProfiles retrieves and decrypts the medical profiles of several cricketers.
+// Cricketers without a profile are left out.
+func (m *MongoDB) GetMedicalProfiles(ctx context.Context, cricketerIDs []primitive.ObjectID) ([]models.MedicalProfile, error) {
+	cursor, err := m.medicalProfileCollection.Find(ctx, bson.M{"cricketerId": bson.M{"$in": cricketerIDs}})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	profiles := []models.MedicalProfile{}
+	if err = cursor.All(ctx, &profiles); err != nil {
+		return nil, err
+	}
+	for i := range profiles {
+		if err := m.openMedicalProfile(&profiles[i]); err != nil {
+			return nil, err
+		}
+	}
+	return profiles, nil
+}
+
+// RewrapMedicalProfiles re-wraps the data keys of medical profiles encrypted with a key-encryption key
+// that is no longer current. It returns the number of profiles updated.
+func (m *MongoDB) RewrapMedicalProfiles(ctx context.Context) (int, error) {
+	cursor, err := m.medicalProfileCollection.Find(ctx, bson.M{})
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	updated := 0
+	for cursor.Next(ctx) {
+		var profile models.MedicalProfile
+		if err := cursor.Decode(&profile); err != nil {
+			return updated, err
+		}
+		if !m.pii.NeedsRewrap(profile.Sealed) {
+			continue
+		}
+		if err := m.pii.Rewrap(profile.Sealed); err != nil {
+			return updated, err
+		}
+		if _, err := m.medicalProfileCollection.UpdateOne(ctx, bson.M{"_id": profile.ID}, bson.M{"$set": bson.M{"sealed": profile.Sealed}}); err != nil {
+			return updated, err
+		}
+		updated++
+	}
+	return updated, cursor.Err()
+}
+
+func (m *MongoDB) openMedicalProfile(profile *models.MedicalProfile) error {
+	plaintext, err := m.pii.Decrypt(models.PIIFieldMedicalProfile, profile.Sealed)
+	if err != nil {
+		return err
+	}
+	var opened sealedMedicalProfile
+	if err := json.Unmarshal([]byte(plaintext), &opened); err != nil {
+		return err
+	}
+	profile.Medical = opened.Medical
+	profile.EmergencyContacts = opened.EmergencyContacts
+	return nil
+}
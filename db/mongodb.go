@@ -12,6 +12,7 @@ import (
 	"golang.org/x/crypto/bcrypt"
 
 	"cricketApp/models"
+	"cricketApp/pii"
 )
 
 // Removed global Client: var Client *mongo.Client
@@ -56,6 +57,48 @@ func initCollections(client *mongo.Client) error { // Accept client
 	if err := initAdminsCollection(client, dbName); err != nil {
 		return err
 	}
+	if err := initRegistrationsCollection(client, dbName); err != nil {
+		return err
+	}
+	if err := initGuardiansCollection(client, dbName); err != nil {
+		return err
+	}
+	if err := initConsentCollections(client, dbName); err != nil {
+		return err
+	}
+	if err := initMatchesCollection(client, dbName); err != nil {
+		return err
+	}
+	if err := initAssessmentsCollection(client, dbName); err != nil {
+		return err
+	}
+	if err := initFitnessCollections(client, dbName); err != nil {
+		return err
+	}
+	if err := initNotesCollection(client, dbName); err != nil {
+		return err
+	}
+	if err := initWorkloadCollections(client, dbName); err != nil {
+		return err
+	}
+	if err := initInjuriesCollection(client, dbName); err != nil {
+		return err
+	}
+	if err := initTournamentCollections(client, dbName); err != nil {
+		return err
+	}
+	if err := initLeagueFixturesCollection(client, dbName); err != nil {
+		return err
+	}
+	if err := initIDCardsCollection(client, dbName); err != nil {
+		return err
+	}
+	if err := initPhotosCollection(client, dbName); err != nil {
+		return err
+	}
+	if err := initEquipmentCollections(client, dbName); err != nil {
+		return err
+	}
 	log.Println("Collections and indexes created successfully")
 	return nil
 }
@@ -76,7 +119,13 @@ func initCricketersCollection(client *mongo.Client, dbName string) error { // Ac
 		Options: options.Index().SetUnique(true),
 	}
 
-	_, err := cricketersCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{emailIndex, mobileIndex})
+	// Password setup links are looked up by their hash
+	setupIndex := mongo.IndexModel{
+		Keys:    bson.D{{Key: "passwordSetupHash", Value: 1}},
+		Options: options.Index().SetSparse(true),
+	}
+
+	_, err := cricketersCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{emailIndex, mobileIndex, setupIndex})
 	if err != nil {
 		log.Printf("Error creating cricketers indexes: %v", err)
 		return err
@@ -89,9 +138,9 @@ func initAnnouncementsCollection(client *mongo.Client, dbName string) error { //
 	ctx := context.Background()
 	announcementsCollection := client.Database(dbName).Collection("announcements")
 
-	// Create index for sorting by creation date
+	// Create index for sorting by creation date, with _id breaking ties for the page cursor
 	createdAtIndex := mongo.IndexModel{
-		Keys: bson.D{{Key: "createdAt", Value: -1}}, // -1 for descending order
+		Keys: bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}}, // -1 for descending order
 	}
 
 	_, err := announcementsCollection.Indexes().CreateOne(ctx, createdAtIndex)
@@ -99,6 +148,376 @@ func initAnnouncementsCollection(client *mongo.Client, dbName string) error { //
 		log.Printf("Error creating announcements index: %v", err)
 		return err
 	}
+
+	// One receipt per cricketer per announcement
+	receiptsCollection := client.Database(dbName).Collection("announcementReceipts")
+	receiptIndex := mongo.IndexModel{
+		Keys:    bson.D{{Key: "announcementId", Value: 1}, {Key: "cricketerId", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}
+
+	_, err = receiptsCollection.Indexes().CreateOne(ctx, receiptIndex)
+	if err != nil {
+		log.Printf("Error creating announcement receipts index: %v", err)
+		return err
+	}
+	return nil
+}
+
+// initRegistrationsCollection creates indexes used for duplicate detection, search and document slots,
+// and expires old contact verifications.
+func initRegistrationsCollection(client *mongo.Client, dbName string) error {
+	ctx := context.Background()
+	registrationsCollection := client.Database(dbName).Collection("registrations")
+
+	_, err := registrationsCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "aadhaarIndex", Value: 1}}},
+		{Keys: bson.D{{Key: "contactNo", Value: 1}}},
+		{Keys: bson.D{{Key: "dateOfBirth", Value: 1}}},
+		{Keys: bson.D{{Key: "date", Value: -1}}}, // search results are sorted by form date
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "date", Value: -1}}},
+	})
+	if err != nil {
+		log.Printf("Error creating registrations indexes: %v", err)
+		return err
+	}
+
+	// One document per slot per registration
+	documentsCollection := client.Database(dbName).Collection("registrationDocuments")
+	documentIndex := mongo.IndexModel{
+		Keys:    bson.D{{Key: "registrationId", Value: 1}, {Key: "type", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}
+
+	_, err = documentsCollection.Indexes().CreateOne(ctx, documentIndex)
+	if err != nil {
+		log.Printf("Error creating registration documents index: %v", err)
+		return err
+	}
+
+	// Verifications are only needed for a day after they were created
+	verificationsCollection := client.Database(dbName).Collection("verifications")
+	expiryIndex := mongo.IndexModel{
+		Keys:    bson.D{{Key: "createdAt", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(24 * 60 * 60),
+	}
+
+	_, err = verificationsCollection.Indexes().CreateOne(ctx, expiryIndex)
+	if err != nil {
+		log.Printf("Error creating verifications index: %v", err)
+		return err
+	}
+	return nil
+}
+
+// initGuardiansCollection creates indexes for guardians and the attendance and fee records they view.
+func initGuardiansCollection(client *mongo.Client, dbName string) error {
+	ctx := context.Background()
+	database := client.Database(dbName)
+
+	_, err := database.Collection("guardians").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "mobile", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "cricketerIds", Value: 1}}},
+	})
+	if err != nil {
+		log.Printf("Error creating guardians indexes: %v", err)
+		return err
+	}
+
+	// One attendance record per cricketer per session
+	_, err = database.Collection("attendance").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "sessionId", Value: 1}, {Key: "cricketerId", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "cricketerId", Value: 1}, {Key: "sessionDate", Value: -1}}},
+	})
+	if err != nil {
+		log.Printf("Error creating attendance indexes: %v", err)
+		return err
+	}
+
+	// A retried payment request is recorded once
+	_, err = database.Collection("feePayments").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "cricketerId", Value: 1}, {Key: "createdAt", Value: -1}}},
+		{Keys: bson.D{{Key: "idempotencyKey", Value: 1}}, Options: options.Index().SetUnique(true)},
+	})
+	if err != nil {
+		log.Printf("Error creating fee payments indexes: %v", err)
+		return err
+	}
+	return nil
+}
+
+// initConsentCollections creates indexes for medical profiles, consent documents and signatures.
+func initConsentCollections(client *mongo.Client, dbName string) error {
+	ctx := context.Background()
+	database := client.Database(dbName)
+
+	// One medical profile per cricketer
+	_, err := database.Collection("medicalProfiles").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "cricketerId", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		log.Printf("Error creating medical profiles index: %v", err)
+		return err
+	}
+
+	_, err = database.Collection("consentDocuments").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "key", Value: 1}, {Key: "version", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "current", Value: 1}}},
+	})
+	if err != nil {
+		log.Printf("Error creating consent documents indexes: %v", err)
+		return err
+	}
+
+	_, err = database.Collection("consentSignatures").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "cricketerId", Value: 1}, {Key: "signedAt", Value: -1}},
+	})
+	if err != nil {
+		log.Printf("Error creating consent signatures index: %v", err)
+		return err
+	}
+	return nil
+}
+
+// initMatchesCollection creates indexes for listing matches, finding a cricketer's scorecards and
+// ordering ball-by-ball scoring logs.
+func initMatchesCollection(client *mongo.Client, dbName string) error {
+	ctx := context.Background()
+	matchesCollection := client.Database(dbName).Collection("matches")
+
+	_, err := matchesCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "date", Value: -1}}},
+		{Keys: bson.D{{Key: "teams.players.cricketerId", Value: 1}}},
+		{Keys: bson.D{{Key: "innings.batting.cricketerId", Value: 1}}},
+		{Keys: bson.D{{Key: "innings.bowling.cricketerId", Value: 1}}},
+	})
+	if err != nil {
+		log.Printf("Error creating matches indexes: %v", err)
+		return err
+	}
+
+	// One event per seq keeps each match's scoring log in a single order
+	_, err = client.Database(dbName).Collection("ballEvents").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "matchId", Value: 1}, {Key: "seq", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		log.Printf("Error creating ball events index: %v", err)
+		return err
+	}
+	return nil
+}
+
+// initAssessmentsCollection creates the index for a cricketer's assessments in date order.
+func initAssessmentsCollection(client *mongo.Client, dbName string) error {
+	ctx := context.Background()
+	assessmentsCollection := client.Database(dbName).Collection("assessments")
+
+	_, err := assessmentsCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "cricketerId", Value: 1}, {Key: "templateId", Value: 1}, {Key: "assessedOn", Value: 1}},
+	})
+	if err != nil {
+		log.Printf("Error creating assessments index: %v", err)
+		return err
+	}
+	return nil
+}
+
+// initFitnessCollections creates the unique test key index and the indexes for trends, rankings and alerts
+func initFitnessCollections(client *mongo.Client, dbName string) error {
+	ctx := context.Background()
+	database := client.Database(dbName)
+
+	_, err := database.Collection("fitnessTests").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "key", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		log.Printf("Error creating fitnessTests index: %v", err)
+		return err
+	}
+
+	_, err = database.Collection("fitnessResults").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "cricketerId", Value: 1}, {Key: "testId", Value: 1}, {Key: "testedOn", Value: 1}}},
+		{Keys: bson.D{{Key: "testId", Value: 1}, {Key: "ageCategory", Value: 1}, {Key: "testedOn", Value: 1}}},
+	})
+	if err != nil {
+		log.Printf("Error creating fitnessResults indexes: %v", err)
+		return err
+	}
+
+	_, err = database.Collection("fitnessAlerts").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "batchId", Value: 1}, {Key: "createdAt", Value: -1}},
+	})
+	if err != nil {
+		log.Printf("Error creating fitnessAlerts index: %v", err)
+		return err
+	}
+	return nil
+}
+
+// initNotesCollection creates the index for a cricketer's note feed
+func initNotesCollection(client *mongo.Client, dbName string) error {
+	ctx := context.Background()
+	notesCollection := client.Database(dbName).Collection("cricketerNotes")
+
+	_, err := notesCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "cricketerId", Value: 1}, {Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}},
+	})
+	if err != nil {
+		log.Printf("Error creating cricketerNotes index: %v", err)
+		return err
+	}
+	return nil
+}
+
+// initWorkloadCollections creates the unique indexes that keep one delivery count per bowler and
+// session and one alert per bowler, day, kind and level
+func initWorkloadCollections(client *mongo.Client, dbName string) error {
+	ctx := context.Background()
+	database := client.Database(dbName)
+
+	_, err := database.Collection("workloadEntries").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "cricketerId", Value: 1}, {Key: "sessionId", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{Keys: bson.D{{Key: "cricketerId", Value: 1}, {Key: "date", Value: 1}}},
+	})
+	if err != nil {
+		log.Printf("Error creating workloadEntries indexes: %v", err)
+		return err
+	}
+
+	_, err = database.Collection("workloadAlerts").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "cricketerId", Value: 1}, {Key: "date", Value: 1}, {Key: "kind", Value: 1}, {Key: "level", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		log.Printf("Error creating workloadAlerts index: %v", err)
+		return err
+	}
+	return nil
+}
+
+// initInjuriesCollection creates the indexes for a cricketer's injuries, the active injury checks
+// and the injury report
+func initInjuriesCollection(client *mongo.Client, dbName string) error {
+	ctx := context.Background()
+	injuriesCollection := client.Database(dbName).Collection("injuries")
+
+	_, err := injuriesCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "cricketerId", Value: 1}, {Key: "status", Value: 1}}},
+		{Keys: bson.D{{Key: "injuredOn", Value: -1}}},
+	})
+	if err != nil {
+		log.Printf("Error creating injuries indexes: %v", err)
+		return err
+	}
+	return nil
+}
+
+// initTournamentCollections creates the index for a tournament's fixtures and the unique index that
+// keeps one availability request per cricketer and tournament
+func initTournamentCollections(client *mongo.Client, dbName string) error {
+	ctx := context.Background()
+	database := client.Database(dbName)
+
+	_, err := database.Collection("fixtures").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "tournamentId", Value: 1}, {Key: "startsAt", Value: 1}},
+	})
+	if err != nil {
+		log.Printf("Error creating fixtures index: %v", err)
+		return err
+	}
+
+	_, err = database.Collection("availability").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "tournamentId", Value: 1}, {Key: "cricketerId", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{Keys: bson.D{{Key: "cricketerId", Value: 1}, {Key: "startDate", Value: -1}}},
+	})
+	if err != nil {
+		log.Printf("Error creating availability indexes: %v", err)
+		return err
+	}
+	return nil
+}
+
+// initLeagueFixturesCollection creates the indexes for a league's fixtures and for finding clashing fixtures
+func initLeagueFixturesCollection(client *mongo.Client, dbName string) error {
+	ctx := context.Background()
+	leagueFixturesCollection := client.Database(dbName).Collection("leagueFixtures")
+
+	_, err := leagueFixturesCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "leagueId", Value: 1}, {Key: "startsAt", Value: 1}}},
+		{Keys: bson.D{{Key: "startsAt", Value: 1}}},
+	})
+	if err != nil {
+		log.Printf("Error creating league fixtures indexes: %v", err)
+		return err
+	}
+	return nil
+}
+
+// initIDCardsCollection creates the unique index that keeps one ID card per cricketer
+func initIDCardsCollection(client *mongo.Client, dbName string) error {
+	ctx := context.Background()
+	idCardsCollection := client.Database(dbName).Collection("idCards")
+
+	_, err := idCardsCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "cricketerId", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		log.Printf("Error creating ID cards index: %v", err)
+		return err
+	}
+	return nil
+}
+
+// initPhotosCollection creates the indexes for an owner's photos and the review list
+func initPhotosCollection(client *mongo.Client, dbName string) error {
+	ctx := context.Background()
+	photosCollection := client.Database(dbName).Collection("photos")
+
+	_, err := photosCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "ownerType", Value: 1}, {Key: "ownerId", Value: 1}, {Key: "uploadedAt", Value: -1}}},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "uploadedAt", Value: -1}}},
+	})
+	if err != nil {
+		log.Printf("Error creating photos indexes: %v", err)
+		return err
+	}
+	return nil
+}
+
+// initEquipmentCollections creates the indexes for browsing the inventory and for an item's,
+// cricketer's, session's or batch's issues and overdue kit
+func initEquipmentCollections(client *mongo.Client, dbName string) error {
+	ctx := context.Background()
+	database := client.Database(dbName)
+
+	_, err := database.Collection("equipment").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "category", Value: 1}, {Key: "name", Value: 1}, {Key: "size", Value: 1}},
+	})
+	if err != nil {
+		log.Printf("Error creating equipment index: %v", err)
+		return err
+	}
+
+	_, err = database.Collection("equipmentIssues").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "itemId", Value: 1}, {Key: "issuedAt", Value: -1}}},
+		{Keys: bson.D{{Key: "cricketerId", Value: 1}, {Key: "issuedAt", Value: -1}}},
+		{Keys: bson.D{{Key: "sessionId", Value: 1}}},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "dueAt", Value: 1}}},
+	})
+	if err != nil {
+		log.Printf("Error creating equipment issues indexes: %v", err)
+		return err
+	}
 	return nil
 }
 
@@ -141,9 +560,10 @@ func initAdminsCollection(client *mongo.Client, dbName string) error { // Accept
 	}
 	defaultAdmin := models.Admin{
 		// ID: primitive.NewObjectID(), // Let MongoDB generate ID
-		Name:     "Admin",
-		Email:    defaultAdminEmail,
-		Password: string(hashedPassword),
+		Name:        "Admin",
+		Email:       defaultAdminEmail,
+		Password:    string(hashedPassword),
+		Permissions: []string{models.PermissionRevealPII, models.PermissionManagePIIKeys},
 	}
 
 	// Try to insert default admin
@@ -189,10 +609,49 @@ type MongoDB struct {
 	announcementCollection *mongo.Collection
 	sessionCollection      *mongo.Collection
 	registrationCollection *mongo.Collection
+
+	announcementReceiptCollection *mongo.Collection
+	attachmentCollection          *mongo.Collection
+	batchCollection               *mongo.Collection
+	counterCollection             *mongo.Collection
+	verificationCollection        *mongo.Collection
+	auditCollection               *mongo.Collection
+
+	registrationDocumentCollection *mongo.Collection
+	seasonCollection               *mongo.Collection
+	guardianCollection             *mongo.Collection
+	attendanceCollection           *mongo.Collection
+	feePaymentCollection           *mongo.Collection
+	medicalProfileCollection       *mongo.Collection
+	consentDocumentCollection      *mongo.Collection
+	consentSignatureCollection     *mongo.Collection
+	matchCollection                *mongo.Collection
+	ballEventCollection            *mongo.Collection
+	assessmentTemplateCollection   *mongo.Collection
+	assessmentCollection           *mongo.Collection
+	fitnessTestCollection          *mongo.Collection
+	fitnessResultCollection        *mongo.Collection
+	fitnessAlertCollection         *mongo.Collection
+	noteCollection                 *mongo.Collection
+	workloadEntryCollection        *mongo.Collection
+	workloadLimitCollection        *mongo.Collection
+	workloadAlertCollection        *mongo.Collection
+	injuryCollection               *mongo.Collection
+	tournamentCollection           *mongo.Collection
+	fixtureCollection              *mongo.Collection
+	availabilityCollection         *mongo.Collection
+	leagueCollection               *mongo.Collection
+	leagueFixtureCollection        *mongo.Collection
+	idCardCollection               *mongo.Collection
+	photoCollection                *mongo.Collection
+	equipmentCollection            *mongo.Collection
+	equipmentIssueCollection       *mongo.Collection
+
+	pii *pii.Cipher // encrypts sensitive registration fields
 }
 
-// NewMongoDB creates a new MongoDB instance
-func NewMongoDB(client *mongo.Client, dbName string) *MongoDB {
+// NewMongoDB creates a new MongoDB instance. piiCipher encrypts sensitive fields before they are stored.
+func NewMongoDB(client *mongo.Client, dbName string, piiCipher *pii.Cipher) *MongoDB {
 	db := client.Database(dbName)
 	return &MongoDB{
 		client:                 client,
@@ -205,5 +664,44 @@ func NewMongoDB(client *mongo.Client, dbName string) *MongoDB {
 		announcementCollection: db.Collection("announcements"),
 		sessionCollection:      db.Collection("sessions"),
 		registrationCollection: db.Collection("registrations"),
+
+		announcementReceiptCollection: db.Collection("announcementReceipts"),
+		attachmentCollection:          db.Collection("attachments"),
+		batchCollection:               db.Collection("batches"),
+		counterCollection:             db.Collection("counters"),
+		verificationCollection:        db.Collection("verifications"),
+		auditCollection:               db.Collection("auditLog"),
+
+		registrationDocumentCollection: db.Collection("registrationDocuments"),
+		seasonCollection:               db.Collection("seasons"),
+		guardianCollection:             db.Collection("guardians"),
+		attendanceCollection:           db.Collection("attendance"),
+		feePaymentCollection:           db.Collection("feePayments"),
+		medicalProfileCollection:       db.Collection("medicalProfiles"),
+		consentDocumentCollection:      db.Collection("consentDocuments"),
+		consentSignatureCollection:     db.Collection("consentSignatures"),
+		matchCollection:                db.Collection("matches"),
+		ballEventCollection:            db.Collection("ballEvents"),
+		assessmentTemplateCollection:   db.Collection("assessmentTemplates"),
+		assessmentCollection:           db.Collection("assessments"),
+		fitnessTestCollection:          db.Collection("fitnessTests"),
+		fitnessResultCollection:        db.Collection("fitnessResults"),
+		fitnessAlertCollection:         db.Collection("fitnessAlerts"),
+		noteCollection:                 db.Collection("cricketerNotes"),
+		workloadEntryCollection:        db.Collection("workloadEntries"),
+		workloadLimitCollection:        db.Collection("workloadLimits"),
+		workloadAlertCollection:        db.Collection("workloadAlerts"),
+		injuryCollection:               db.Collection("injuries"),
+		tournamentCollection:           db.Collection("tournaments"),
+		fixtureCollection:              db.Collection("fixtures"),
+		availabilityCollection:         db.Collection("availability"),
+		leagueCollection:               db.Collection("leagues"),
+		leagueFixtureCollection:        db.Collection("leagueFixtures"),
+		idCardCollection:               db.Collection("idCards"),
+		photoCollection:                db.Collection("photos"),
+		equipmentCollection:            db.Collection("equipment"),
+		equipmentIssueCollection:       db.Collection("equipmentIssues"),
+
+		pii: piiCipher,
 	}
 }
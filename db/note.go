@@ -0,0 +1,105 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"cricketApp/models"
+)
+
+// CreateNote inserts a new note about a cricketer
+func (m *MongoDB) CreateNote(ctx context.Context, note *models.CricketerNote) error {
+	note.CreatedAt = time.Now()
+	note.UpdatedAt = note.CreatedAt
+	if note.ID.IsZero() {
+		note.ID = primitive.NewObjectID()
+	}
+
+	_, err := m.noteCollection.InsertOne(ctx, note)
+	return err
+}
+
+// GetNoteByID retrieves a note by ID
+func (m *MongoDB) GetNoteByID(ctx context.Context, id primitive.ObjectID) (*models.CricketerNote, error) {
+	var note models.CricketerNote
+	err := m.noteCollection.FindOne(ctx, bson.M{"_id": id}).Decode(&note)
+	if err != nil {
+		return nil, err
+	}
+	return &note, nil
+}
+
+// GetNotes retrieves a page of a cricketer's notes matching the filter, newest first
+func (m *MongoDB) GetNotes(ctx context.Context, filter models.NoteFilter) ([]models.CricketerNote, error) {
+	query := bson.M{"cricketerId": filter.CricketerID}
+	if filter.Visibilities != nil {
+		visible := bson.A{bson.M{"visibility": bson.M{"$in": filter.Visibilities}}}
+		if filter.AuthorID != "" {
+			visible = append(visible, bson.M{"authorId": filter.AuthorID})
+		}
+		query["$or"] = visible
+	}
+	if filter.Tag != "" {
+		query["tags"] = filter.Tag
+	}
+	if filter.Before != nil {
+		query = bson.M{"$and": bson.A{query, beforeCursor(*filter.Before, filter.BeforeID)}}
+	}
+
+	findOptions := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}})
+	if filter.Limit > 0 {
+		findOptions.SetLimit(int64(filter.Limit))
+	}
+
+	cursor, err := m.noteCollection.Find(ctx, query, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	notes := []models.CricketerNote{}
+	if err = cursor.All(ctx, &notes); err != nil {
+		return nil, err
+	}
+	return notes, nil
+}
+
+// UpdateNote saves a note's body, visibility and tags
+func (m *MongoDB) UpdateNote(ctx context.Context, id primitive.ObjectID, note *models.CricketerNote) error {
+	note.UpdatedAt = time.Now()
+
+	update := bson.M{
+		"$set": bson.M{
+			"body":       note.Body,
+			"visibility": note.Visibility,
+			"tags":       note.Tags,
+			"updatedAt":  note.UpdatedAt,
+		},
+	}
+
+	result, err := m.noteCollection.UpdateOne(ctx, bson.M{"_id": id}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// DeleteNote removes a note
+func (m *MongoDB) DeleteNote(ctx context.Context, id primitive.ObjectID) error {
+	result, err := m.noteCollection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"cricketApp/models"
+)
+
+// CreatePhoto stores a newly uploaded profile photo
+func (m *MongoDB) CreatePhoto(ctx context.Context, photo *models.ProfilePhoto) error {
+	if photo.ID.IsZero() {
+		photo.ID = primitive.NewObjectID()
+	}
+
+	_, err := m.photoCollection.InsertOne(ctx, photo)
+	return err
+}
+
+// GetPhotoByID retrieves a profile photo by its ID
+func (m *MongoDB) GetPhotoByID(ctx context.Context, id primitive.ObjectID) (*models.ProfilePhoto, error) {
+	var photo models.ProfilePhoto
+	err := m.photoCollection.FindOne(ctx, bson.M{"_id": id}).Decode(&photo)
+	if err != nil {
+		return nil, err
+	}
+	return &photo, nil
+}
+
+// GetPhotosByIDs retrieves several profile photos
+func (m *MongoDB) GetPhotosByIDs(ctx context.Context, ids []primitive.ObjectID) ([]models.ProfilePhoto, error) {
+	return m.findPhotos(ctx, bson.M{"_id": bson.M{"$in": ids}}, nil)
+}
+
+// GetLatestPhoto retrieves the photo most recently uploaded for a cricketer or coach, whatever its status
+func (m *MongoDB) GetLatestPhoto(ctx context.Context, ownerType string, ownerID primitive.ObjectID) (*models.ProfilePhoto, error) {
+	var photo models.ProfilePhoto
+	opts := options.FindOne().SetSort(bson.D{{Key: "uploadedAt", Value: -1}})
+	err := m.photoCollection.FindOne(ctx, bson.M{"ownerType": ownerType, "ownerId": ownerID}, opts).Decode(&photo)
+	if err != nil {
+		return nil, err
+	}
+	return &photo, nil
+}
+
+// GetPhotos retrieves the profile photos with a status, newest first
+func (m *MongoDB) GetPhotos(ctx context.Context, status string) ([]models.ProfilePhoto, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "uploadedAt", Value: -1}})
+	return m.findPhotos(ctx, bson.M{"status": status}, opts)
+}
+
+// ReplacePhoto marks an active profile photo as replaced
+func (m *MongoDB) ReplacePhoto(ctx context.Context, id primitive.ObjectID) error {
+	filter := bson.M{"_id": id, "status": models.PhotoActive}
+	result, err := m.photoCollection.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"status": models.PhotoReplaced}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// RejectPhoto takes down an active profile photo
+func (m *MongoDB) RejectPhoto(ctx context.Context, id primitive.ObjectID, rejectedBy string, reason string) error {
+	filter := bson.M{"_id": id, "status": models.PhotoActive}
+	update := bson.M{"$set": bson.M{
+		"status":       models.PhotoRejected,
+		"rejectedBy":   rejectedBy,
+		"rejectedAt":   time.Now(),
+		"rejectReason": reason,
+	}}
+	result, err := m.photoCollection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// SetOwnerPhoto sets the photo shown on a cricketer's or coach's profile, or removes it when photoID is nil
+func (m *MongoDB) SetOwnerPhoto(ctx context.Context, ownerType string, ownerID primitive.ObjectID, photoID *primitive.ObjectID) error {
+	var collection *mongo.Collection
+	switch ownerType {
+	case models.PhotoOwnerCricketer:
+		collection = m.cricketerCollection
+	case models.PhotoOwnerCoach:
+		collection = m.coachCollection
+	default:
+		return fmt.Errorf("unknown photo owner type %q", ownerType)
+	}
+
+	update := bson.M{"$set": bson.M{"photoId": photoID}}
+	if photoID == nil {
+		update = bson.M{"$unset": bson.M{"photoId": ""}}
+	}
+	result, err := collection.UpdateOne(ctx, bson.M{"_id": ownerID}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+func (m *MongoDB) findPhotos(ctx context.Context, filter bson.M, opts *options.FindOptions) ([]models.ProfilePhoto, error) {
+	cursor, err := m.photoCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var photos []models.ProfilePhoto
+	if err = cursor.All(ctx, &photos); err != nil {
+		return nil, err
+	}
+
+	if photos == nil {
+		return []models.ProfilePhoto{}, nil
+	}
+
+	return photos, nil
+}
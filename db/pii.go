@@ -0,0 +1,132 @@
+package db
+
+import (
+	"context"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	"cricketApp/models"
+	"cricketApp/pii"
+)
+
+// sealRegistration returns a copy of registration with its sensitive fields encrypted and the plaintext cleared
+func (m *MongoDB) sealRegistration(registration *models.RegistrationForm) (*models.RegistrationForm, error) {
+	sealed := *registration
+	sealed.SealedPII = &models.SealedRegistrationPII{}
+
+	var err error
+	if sealed.SealedPII.AadhaarNo, err = m.pii.Encrypt(models.PIIFieldAadhaarNo, registration.AadhaarNo); err != nil {
+		return nil, err
+	}
+	if sealed.SealedPII.ResidenceAddress, err = m.pii.Encrypt(models.PIIFieldResidenceAddress, registration.ResidenceAddress); err != nil {
+		return nil, err
+	}
+	if sealed.SealedPII.ParentContactNo, err = m.pii.Encrypt(models.PIIFieldParentContactNo, registration.ParentDetails.ContactNo); err != nil {
+		return nil, err
+	}
+	sealed.AadhaarIndex = m.aadhaarIndex(registration.AadhaarNo)
+
+	sealed.AadhaarNo = ""
+	sealed.ResidenceAddress = ""
+	sealed.ParentDetails.ContactNo = ""
+	return &sealed, nil
+}
+
+// openRegistration decrypts a stored registration's sensitive fields in place.
+// Registrations stored before encryption was introduced keep their plaintext fields.
+func (m *MongoDB) openRegistration(registration *models.RegistrationForm) error {
+	if registration.SealedPII == nil {
+		return nil
+	}
+
+	var err error
+	if registration.AadhaarNo, err = m.pii.Decrypt(models.PIIFieldAadhaarNo, registration.SealedPII.AadhaarNo); err != nil {
+		return err
+	}
+	if registration.ResidenceAddress, err = m.pii.Decrypt(models.PIIFieldResidenceAddress, registration.SealedPII.ResidenceAddress); err != nil {
+		return err
+	}
+	if registration.ParentDetails.ContactNo, err = m.pii.Decrypt(models.PIIFieldParentContactNo, registration.SealedPII.ParentContactNo); err != nil {
+		return err
+	}
+	return nil
+}
+
+// aadhaarIndex returns the blind index of an Aadhaar number, ignoring spaces and dashes
+func (m *MongoDB) aadhaarIndex(aadhaarNo string) string {
+	digits := strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, aadhaarNo)
+	return m.pii.BlindIndex(models.PIIFieldAadhaarNo, digits)
+}
+
+// sealedRegistrationUpdate builds the update that stores sealed's encrypted fields and removes any plaintext
+func sealedRegistrationUpdate(sealed *models.RegistrationForm) (bson.M, bson.M) {
+	set := bson.M{
+		"sealedPii":    sealed.SealedPII,
+		"aadhaarIndex": sealed.AadhaarIndex,
+	}
+	unset := bson.M{
+		"aadhaarNo":               "",
+		"residenceAddress":        "",
+		"parentDetails.contactNo": "",
+	}
+	return set, unset
+}
+
+// MigrateRegistrationPII encrypts registrations stored before field-level encryption was introduced and
+// re-wraps data keys of registrations encrypted with a key-encryption key that is no longer current.
+// It returns the number of registrations updated.
+func (m *MongoDB) MigrateRegistrationPII(ctx context.Context) (int, error) {
+	cursor, err := m.registrationCollection.Find(ctx, bson.M{})
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	updated := 0
+	for cursor.Next(ctx) {
+		var registration models.RegistrationForm
+		if err := cursor.Decode(&registration); err != nil {
+			return updated, err
+		}
+
+		var set, unset bson.M
+		if registration.SealedPII == nil {
+			sealed, err := m.sealRegistration(&registration)
+			if err != nil {
+				return updated, err
+			}
+			set, unset = sealedRegistrationUpdate(sealed)
+		} else {
+			changed := false
+			sealedPII := registration.SealedPII
+			for _, value := range []*pii.EncryptedValue{sealedPII.AadhaarNo, sealedPII.ResidenceAddress, sealedPII.ParentContactNo} {
+				if m.pii.NeedsRewrap(value) {
+					if err := m.pii.Rewrap(value); err != nil {
+						return updated, err
+					}
+					changed = true
+				}
+			}
+			if !changed {
+				continue
+			}
+			set = bson.M{"sealedPii": registration.SealedPII}
+		}
+
+		update := bson.M{"$set": set}
+		if unset != nil {
+			update["$unset"] = unset
+		}
+		if _, err := m.registrationCollection.UpdateOne(ctx, bson.M{"_id": registration.ID}, update); err != nil {
+			return updated, err
+		}
+		updated++
+	}
+	return updated, cursor.Err()
+}
@@ -2,6 +2,8 @@ package db
 
 import (
 	"context"
+	"errors"
+	"regexp"
 	"time"
 
 	"cricketApp/models"
@@ -9,15 +11,31 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+// ErrRegistrationStatusChanged is returned when a registration's status was changed by someone else
+// between reading it and applying a transition
+var ErrRegistrationStatusChanged = errors.New("registration status has changed")
+
+// ErrRegistrationDocumentsChanged is returned when a required document was uploaded again or reviewed
+// as needing a re-upload between checking the documents and approving the registration
+var ErrRegistrationDocumentsChanged = errors.New("registration documents have changed")
+
 // CreateRegistration creates a new registration form
 func (m *MongoDB) CreateRegistration(ctx context.Context, registration *models.RegistrationForm) error {
 	registration.CreatedAt = time.Now()
 	registration.UpdatedAt = time.Now()
-	registration.Status = "pending" // Default status
+	registration.Status = models.RegistrationSubmitted // Default status
+	if registration.ID.IsZero() {
+		registration.ID = primitive.NewObjectID()
+	}
 
-	_, err := m.registrationCollection.InsertOne(ctx, registration)
+	sealed, err := m.sealRegistration(registration)
+	if err != nil {
+		return err
+	}
+	_, err = m.registrationCollection.InsertOne(ctx, sealed)
 	return err
 }
 
@@ -28,47 +46,141 @@ func (m *MongoDB) GetRegistrationByID(ctx context.Context, id primitive.ObjectID
 	if err != nil {
 		return nil, err
 	}
+	if err := m.openRegistration(&registration); err != nil {
+		return nil, err
+	}
 	return &registration, nil
 }
 
-// GetAllRegistrations retrieves all registrations
-func (m *MongoDB) GetAllRegistrations(ctx context.Context) ([]*models.RegistrationForm, error) {
-	cursor, err := m.registrationCollection.Find(ctx, bson.M{})
+// SearchRegistrations retrieves the registrations matching filter, newest form date first,
+// along with the total number of matches ignoring Skip and Limit
+func (m *MongoDB) SearchRegistrations(ctx context.Context, filter models.RegistrationFilter) ([]*models.RegistrationForm, int64, error) {
+	query := registrationQuery(filter)
+	total, err := m.registrationCollection.CountDocuments(ctx, query)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
+	}
+
+	cursor, err := m.registrationCollection.Find(ctx, query, registrationFindOptions(filter))
+	if err != nil {
+		return nil, 0, err
 	}
 	defer cursor.Close(ctx)
 
-	var registrations []*models.RegistrationForm
+	registrations := []*models.RegistrationForm{}
 	if err = cursor.All(ctx, &registrations); err != nil {
-		return nil, err
+		return nil, 0, err
+	}
+	for _, registration := range registrations {
+		if err := m.openRegistration(registration); err != nil {
+			return nil, 0, err
+		}
+	}
+	return registrations, total, nil
+}
+
+// StreamRegistrations calls fn with each registration matching filter, newest form date first,
+// without loading the whole result set into memory. It stops at the first error from fn.
+func (m *MongoDB) StreamRegistrations(ctx context.Context, filter models.RegistrationFilter, fn func(*models.RegistrationForm) error) error {
+	cursor, err := m.registrationCollection.Find(ctx, registrationQuery(filter), registrationFindOptions(filter))
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var registration models.RegistrationForm
+		if err := cursor.Decode(&registration); err != nil {
+			return err
+		}
+		if err := m.openRegistration(&registration); err != nil {
+			return err
+		}
+		if err := fn(&registration); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
+
+// registrationQuery builds the MongoDB query for a registration filter.
+// Encrypted fields can't be searched, so phone matches the applicant's own numbers only.
+func registrationQuery(filter models.RegistrationFilter) bson.M {
+	query := bson.M{}
+	contains := func(text string) bson.M {
+		return bson.M{"$regex": regexp.QuoteMeta(text), "$options": "i"}
+	}
+
+	if filter.Name != "" {
+		query["fullName"] = contains(filter.Name)
 	}
-	return registrations, nil
+	if filter.FormNo != "" {
+		query["formNo"] = bson.M{"$regex": "^" + regexp.QuoteMeta(filter.FormNo), "$options": "i"}
+	}
+	if filter.School != "" {
+		query["schoolCollege"] = contains(filter.School)
+	}
+	if filter.Phone != "" {
+		query["$or"] = bson.A{
+			bson.M{"contactNo": contains(filter.Phone)},
+			bson.M{"whatsapp": contains(filter.Phone)},
+		}
+	}
+	if len(filter.Statuses) > 0 {
+		query["status"] = bson.M{"$in": models.StoredRegistrationStatuses(filter.Statuses)}
+	}
+	if filter.From != nil || filter.To != nil {
+		dateRange := bson.M{}
+		if filter.From != nil {
+			dateRange["$gte"] = *filter.From
+		}
+		if filter.To != nil {
+			dateRange["$lt"] = *filter.To
+		}
+		query["date"] = dateRange
+	}
+	return query
+}
+
+func registrationFindOptions(filter models.RegistrationFilter) *options.FindOptions {
+	findOptions := options.Find().SetSort(bson.D{{Key: "date", Value: -1}, {Key: "_id", Value: -1}})
+	if filter.Skip > 0 {
+		findOptions.SetSkip(int64(filter.Skip))
+	}
+	if filter.Limit > 0 {
+		findOptions.SetLimit(int64(filter.Limit))
+	}
+	return findOptions
 }
 
 // UpdateRegistration updates an existing registration
 func (m *MongoDB) UpdateRegistration(ctx context.Context, id primitive.ObjectID, registration *models.RegistrationForm) error {
 	registration.UpdatedAt = time.Now()
-	update := bson.M{
-		"$set": bson.M{
-			"formNo":           registration.FormNo,
-			"date":             registration.Date,
-			"reference":        registration.Reference,
-			"fullName":         registration.FullName,
-			"dateOfBirth":      registration.DateOfBirth,
-			"residenceAddress": registration.ResidenceAddress,
-			"contactNo":        registration.ContactNo,
-			"email":            registration.Email,
-			"education":        registration.Education,
-			"schoolCollege":    registration.SchoolCollege,
-			"aadhaarNo":        registration.AadhaarNo,
-			"whatsapp":         registration.Whatsapp,
-			"parentDetails":    registration.ParentDetails,
-			"cricketerId":      registration.CricketerID,
-			"status":           registration.Status,
-			"updatedAt":        registration.UpdatedAt,
-		},
+	sealed, err := m.sealRegistration(registration)
+	if err != nil {
+		return err
+	}
+	set := bson.M{
+		"formNo":        registration.FormNo,
+		"date":          registration.Date,
+		"reference":     registration.Reference,
+		"fullName":      registration.FullName,
+		"dateOfBirth":   registration.DateOfBirth,
+		"contactNo":     registration.ContactNo,
+		"email":         registration.Email,
+		"education":     registration.Education,
+		"schoolCollege": registration.SchoolCollege,
+		"whatsapp":      registration.Whatsapp,
+		"parentDetails": sealed.ParentDetails, // without the encrypted contact number
+		"cricketerId":   registration.CricketerID,
+		"updatedAt":     registration.UpdatedAt,
+	}
+	sealedSet, unset := sealedRegistrationUpdate(sealed)
+	for key, value := range sealedSet {
+		set[key] = value
 	}
+	delete(unset, "parentDetails.contactNo") // parentDetails is replaced as a whole
+	update := bson.M{"$set": set, "$unset": unset}
 
 	result, err := m.registrationCollection.UpdateOne(ctx, bson.M{"_id": id}, update)
 	if err != nil {
@@ -79,3 +191,280 @@ func (m *MongoDB) UpdateRegistration(ctx context.Context, id primitive.ObjectID,
 	}
 	return nil
 }
+
+// TransitionRegistration moves a registration to a new status and records the change in its history.
+// The update only applies if the registration is still in change.From.
+func (m *MongoDB) TransitionRegistration(ctx context.Context, id primitive.ObjectID, change models.RegistrationStatusChange) error {
+	return m.transitionRegistration(ctx, id, change, bson.M{})
+}
+
+// ApproveRegistration approves a registration and provisions the cricketer account in a single transaction:
+// the registration is linked to an existing cricketer (by cricketerId, email or mobile) or a new one is created,
+// and the cricketer's joining date, first due date and batch are set. Every required document must still be
+// accepted. It returns the cricketer and whether it was newly created. Transactions require MongoDB to run as
+// a replica set.
+func (m *MongoDB) ApproveRegistration(ctx context.Context, id primitive.ObjectID, approval models.RegistrationApproval) (*models.Cricketer, bool, error) {
+	session, err := m.client.StartSession()
+	if err != nil {
+		return nil, false, err
+	}
+	defer session.EndSession(ctx)
+
+	var cricketer *models.Cricketer
+	var created bool
+	_, err = session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		cricketer, created = nil, false // reset state if the transaction is retried
+
+		registration, err := m.GetRegistrationByID(sessCtx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		// Writing the accepted documents makes an upload or review of them that races the approval
+		// conflict with this transaction, instead of leaving the registration approved without them
+		for _, documentType := range models.RegistrationDocumentTypes {
+			if !documentType.Required {
+				continue
+			}
+			result, err := m.registrationDocumentCollection.UpdateOne(sessCtx,
+				bson.M{"registrationId": id, "type": documentType.Type, "status": models.DocumentAccepted},
+				bson.M{"$set": bson.M{"approvedAt": approval.Change.ChangedAt}})
+			if err != nil {
+				return nil, err
+			}
+			if result.MatchedCount == 0 {
+				return nil, ErrRegistrationDocumentsChanged
+			}
+		}
+
+		// Find the cricketer account to link. Only an explicit link or the same email is trusted:
+		// siblings often share a parent's mobile number.
+		switch {
+		case !registration.CricketerID.IsZero():
+			cricketer, err = m.GetCricketerByID(sessCtx, registration.CricketerID)
+		case approval.CricketerID != nil:
+			cricketer, err = m.GetCricketerByID(sessCtx, *approval.CricketerID)
+		default:
+			cricketer, err = m.GetCricketerByEmail(sessCtx, registration.Email)
+			if err == mongo.ErrNoDocuments {
+				cricketer, err = nil, nil
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+		if cricketer == nil {
+			if approval.NewCricketer == nil {
+				return nil, errors.New("no cricketer account to link and none to create")
+			}
+			cricketer = approval.NewCricketer
+			if cricketer.ID.IsZero() {
+				cricketer.ID = primitive.NewObjectID()
+			}
+			if err := m.CreateCricketer(sessCtx, cricketer); err != nil {
+				return nil, err
+			}
+			created = true
+		}
+
+		// Age categories are derived from the date of birth on the registration
+		if cricketer.DateOfBirth == nil && !registration.DateOfBirth.IsZero() {
+			if err := m.UpdateCricketerDateOfBirth(sessCtx, cricketer.ID, registration.DateOfBirth); err != nil {
+				return nil, err
+			}
+		}
+
+		// Start the cricketer's membership
+		if err := m.UpdateCricketerJoiningDate(sessCtx, cricketer.ID, &approval.JoiningDate); err != nil {
+			return nil, err
+		}
+		if approval.BatchID != nil {
+			if err := m.UpdateCricketerBatch(sessCtx, cricketer.ID, approval.BatchID); err != nil {
+				return nil, err
+			}
+		}
+		if _, err := m.cricketerCollection.UpdateOne(sessCtx, bson.M{"_id": cricketer.ID}, bson.M{"$set": bson.M{"inactiveCricketer": false}}); err != nil {
+			return nil, err
+		}
+
+		extra := bson.M{"cricketerId": cricketer.ID}
+		if approval.BatchID != nil {
+			extra["batchId"] = approval.BatchID
+		}
+		if err := m.transitionRegistration(sessCtx, id, approval.Change, extra); err != nil {
+			return nil, err
+		}
+
+		// Return the cricketer as stored after all updates
+		cricketer, err = m.GetCricketerByID(sessCtx, cricketer.ID)
+		return nil, err
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return cricketer, created, nil
+}
+
+func (m *MongoDB) transitionRegistration(ctx context.Context, id primitive.ObjectID, change models.RegistrationStatusChange, extra bson.M) error {
+	set := bson.M{
+		"status":       change.To,
+		"statusReason": change.Reason,
+		"reviewedBy":   change.ChangedBy,
+		"updatedAt":    change.ChangedAt,
+	}
+	for k, v := range extra {
+		set[k] = v
+	}
+	update := bson.M{
+		"$set":  set,
+		"$push": bson.M{"statusHistory": change},
+	}
+
+	result, err := m.registrationCollection.UpdateOne(ctx, bson.M{"_id": id, "status": change.From}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrRegistrationStatusChanged
+	}
+	return nil
+}
+
+// FindDuplicateFormNumbers reports form numbers used by more than one registration.
+// These must be resolved before the unique formNo index can be created.
+func (m *MongoDB) FindDuplicateFormNumbers(ctx context.Context) ([]models.DuplicateFormNo, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$group", Value: bson.M{
+			"_id":             "$formNo",
+			"count":           bson.M{"$sum": 1},
+			"registrationIds": bson.M{"$push": "$_id"},
+		}}},
+		{{Key: "$match", Value: bson.M{"count": bson.M{"$gt": 1}}}},
+		{{Key: "$sort", Value: bson.M{"_id": 1}}},
+	}
+
+	cursor, err := m.registrationCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var duplicates []models.DuplicateFormNo
+	if err = cursor.All(ctx, &duplicates); err != nil {
+		return nil, err
+	}
+
+	if duplicates == nil {
+		return []models.DuplicateFormNo{}, nil
+	}
+
+	return duplicates, nil
+}
+
+// MigrateRegistrationFormNumbers prepares the registrations collection for server-allocated form numbers.
+// It reports any form numbers shared by several registrations and, only when there are none,
+// creates the unique index on formNo.
+func (m *MongoDB) MigrateRegistrationFormNumbers(ctx context.Context) ([]models.DuplicateFormNo, error) {
+	duplicates, err := m.FindDuplicateFormNumbers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(duplicates) > 0 {
+		return duplicates, nil
+	}
+
+	formNoIndex := mongo.IndexModel{
+		Keys:    bson.D{{Key: "formNo", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}
+
+	_, err = m.registrationCollection.Indexes().CreateOne(ctx, formNoIndex)
+	return duplicates, err
+}
+
+// FindRegistrationDuplicates looks for existing registrations and cricketers that appear to be the same applicant:
+// a registration with the same Aadhaar number that wasn't rejected, registrations or cricketers with the same
+// phone number, and registrations with the same name and date of birth.
+// contactNos lists the forms the phone number may have been stored in.
+func (m *MongoDB) FindRegistrationDuplicates(ctx context.Context, aadhaarNo string, contactNos []string, fullName string, dateOfBirth time.Time) ([]models.DuplicateMatch, error) {
+	matches := []models.DuplicateMatch{}
+	seen := make(map[string]bool)
+	add := func(field string, registrationID *primitive.ObjectID, cricketerID *primitive.ObjectID) {
+		key := field
+		if registrationID != nil {
+			key += ":r:" + registrationID.Hex()
+		}
+		if cricketerID != nil {
+			key += ":c:" + cricketerID.Hex()
+		}
+		if !seen[key] {
+			seen[key] = true
+			matches = append(matches, models.DuplicateMatch{Field: field, RegistrationID: registrationID, CricketerID: cricketerID})
+		}
+	}
+
+	findRegistrations := func(field string, filter bson.M) error {
+		opts := options.Find().SetProjection(bson.M{"_id": 1}).SetLimit(20)
+		cursor, err := m.registrationCollection.Find(ctx, filter, opts)
+		if err != nil {
+			return err
+		}
+		var found []struct {
+			ID primitive.ObjectID `bson:"_id"`
+		}
+		if err := cursor.All(ctx, &found); err != nil {
+			return err
+		}
+		for i := range found {
+			add(field, &found[i].ID, nil)
+		}
+		return nil
+	}
+
+	if aadhaarNo != "" {
+		filter := bson.M{
+			"$or": bson.A{
+				bson.M{"aadhaarIndex": m.aadhaarIndex(aadhaarNo)},
+				bson.M{"aadhaarNo": aadhaarNo}, // not yet migrated to encrypted storage
+			},
+			"status": bson.M{"$ne": models.RegistrationRejected},
+		}
+		if err := findRegistrations(models.DuplicateOnAadhaar, filter); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(contactNos) > 0 {
+		if err := findRegistrations(models.DuplicateOnPhone, bson.M{"contactNo": bson.M{"$in": contactNos}}); err != nil {
+			return nil, err
+		}
+
+		opts := options.Find().SetProjection(bson.M{"_id": 1}).SetLimit(20)
+		cursor, err := m.cricketerCollection.Find(ctx, bson.M{"mobile": bson.M{"$in": contactNos}}, opts)
+		if err != nil {
+			return nil, err
+		}
+		var cricketers []struct {
+			ID primitive.ObjectID `bson:"_id"`
+		}
+		if err := cursor.All(ctx, &cricketers); err != nil {
+			return nil, err
+		}
+		for i := range cricketers {
+			add(models.DuplicateOnPhone, nil, &cricketers[i].ID)
+		}
+	}
+
+	if fullName != "" && !dateOfBirth.IsZero() {
+		day := time.Date(dateOfBirth.Year(), dateOfBirth.Month(), dateOfBirth.Day(), 0, 0, 0, 0, dateOfBirth.Location())
+		filter := bson.M{
+			"fullName":    bson.M{"$regex": "^" + regexp.QuoteMeta(fullName) + "$", "$options": "i"},
+			"dateOfBirth": bson.M{"$gte": day, "$lt": day.AddDate(0, 0, 1)},
+		}
+		if err := findRegistrations(models.DuplicateOnNameAndDOB, filter); err != nil {
+			return nil, err
+		}
+	}
+
+	return matches, nil
+}
@@ -0,0 +1,68 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"cricketApp/models"
+)
+
+// ErrBallEventConflict is returned when another event was recorded with the same seq first
+var ErrBallEventConflict = errors.New("another event was recorded for this match first")
+
+// AppendBallEvent adds an event to the end of a match's scoring log. The unique (matchId, seq) index
+// makes concurrent scorers conflict instead of interleaving.
+func (m *MongoDB) AppendBallEvent(ctx context.Context, event *models.BallEvent) error {
+	event.RecordedAt = time.Now()
+	if event.ID.IsZero() {
+		event.ID = primitive.NewObjectID()
+	}
+
+	_, err := m.ballEventCollection.InsertOne(ctx, event)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrBallEventConflict
+	}
+	return err
+}
+
+// GetBallEvents retrieves a match's scoring log, oldest first
+func (m *MongoDB) GetBallEvents(ctx context.Context, matchID primitive.ObjectID) ([]models.BallEvent, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "seq", Value: 1}})
+	cursor, err := m.ballEventCollection.Find(ctx, bson.M{"matchId": matchID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	events := []models.BallEvent{}
+	if err = cursor.All(ctx, &events); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// SaveMatchScore stores the scorecard and result derived from a match's scoring log
+func (m *MongoDB) SaveMatchScore(ctx context.Context, matchID primitive.ObjectID, innings []models.Innings, result models.MatchResult) error {
+	update := bson.M{
+		"$set": bson.M{
+			"innings":   innings,
+			"result":    result,
+			"updatedAt": time.Now(),
+		},
+	}
+
+	res, err := m.matchCollection.UpdateOne(ctx, bson.M{"_id": matchID}, update)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
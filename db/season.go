@@ -0,0 +1,97 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"cricketApp/models"
+)
+
+// CreateSeason creates a new season
+func (m *MongoDB) CreateSeason(ctx context.Context, season *models.Season) error {
+	season.CreatedAt = time.Now()
+	season.UpdatedAt = time.Now()
+	if season.ID.IsZero() {
+		season.ID = primitive.NewObjectID()
+	}
+
+	_, err := m.seasonCollection.InsertOne(ctx, season)
+	return err
+}
+
+// GetSeasonByID retrieves a season by its ID
+func (m *MongoDB) GetSeasonByID(ctx context.Context, id primitive.ObjectID) (*models.Season, error) {
+	var season models.Season
+	err := m.seasonCollection.FindOne(ctx, bson.M{"_id": id}).Decode(&season)
+	if err != nil {
+		return nil, err
+	}
+	return &season, nil
+}
+
+// GetAllSeasons retrieves all seasons, oldest first
+func (m *MongoDB) GetAllSeasons(ctx context.Context) ([]models.Season, error) {
+	cursor, err := m.seasonCollection.Find(ctx, bson.M{}, options.Find().SetSort(bson.M{"startDate": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	seasons := []models.Season{}
+	if err = cursor.All(ctx, &seasons); err != nil {
+		return nil, err
+	}
+	return seasons, nil
+}
+
+// GetSeasonForDate retrieves the season that date falls in
+func (m *MongoDB) GetSeasonForDate(ctx context.Context, date time.Time) (*models.Season, error) {
+	filter := bson.M{"startDate": bson.M{"$lte": date}, "endDate": bson.M{"$gte": date}}
+	opts := options.FindOne().SetSort(bson.M{"startDate": -1})
+
+	var season models.Season
+	err := m.seasonCollection.FindOne(ctx, filter, opts).Decode(&season)
+	if err != nil {
+		return nil, err
+	}
+	return &season, nil
+}
+
+// UpdateSeason updates an existing season
+func (m *MongoDB) UpdateSeason(ctx context.Context, id primitive.ObjectID, season *models.Season) error {
+	season.UpdatedAt = time.Now()
+	update := bson.M{
+		"$set": bson.M{
+			"name":       season.Name,
+			"startDate":  season.StartDate,
+			"endDate":    season.EndDate,
+			"cutoffDate": season.CutoffDate,
+			"updatedAt":  season.UpdatedAt,
+		},
+	}
+
+	result, err := m.seasonCollection.UpdateOne(ctx, bson.M{"_id": id}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// SeasonOverlaps reports whether another season overlaps the given dates
+func (m *MongoDB) SeasonOverlaps(ctx context.Context, excludeID primitive.ObjectID, start time.Time, end time.Time) (bool, error) {
+	filter := bson.M{
+		"_id":       bson.M{"$ne": excludeID},
+		"startDate": bson.M{"$lte": end},
+		"endDate":   bson.M{"$gte": start},
+	}
+	count, err := m.seasonCollection.CountDocuments(ctx, filter)
+	return count > 0, err
+}
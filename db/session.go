@@ -9,6 +9,7 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 // CreateSession creates a new coaching session
@@ -70,6 +71,7 @@ func (m *MongoDB) UpdateSession(ctx context.Context, id primitive.ObjectID, sess
 			"date":        session.Date,
 			"startTime":   session.StartTime,
 			"endTime":     session.EndTime,
+			"batchId":     session.BatchID,
 			"venue":       session.Venue,
 			"maxStudents": session.MaxStudents,
 			"updatedAt":   session.UpdatedAt,
@@ -86,6 +88,44 @@ func (m *MongoDB) UpdateSession(ctx context.Context, id primitive.ObjectID, sess
 	return nil
 }
 
+// GetSessionsForBatches retrieves the sessions for any of the batches held between from and to, in date order
+func (m *MongoDB) GetSessionsForBatches(ctx context.Context, batchIDs []primitive.ObjectID, from time.Time, to time.Time) ([]*models.Session, error) {
+	filter := bson.M{
+		"batchId":   bson.M{"$in": batchIDs},
+		"startTime": bson.M{"$gte": from, "$lt": to},
+	}
+	cursor, err := m.sessionCollection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "startTime", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	sessions := []*models.Session{}
+	if err = cursor.All(ctx, &sessions); err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// GetSessionsBetween retrieves every session that overlaps the period between from and to, in date order
+func (m *MongoDB) GetSessionsBetween(ctx context.Context, from time.Time, to time.Time) ([]*models.Session, error) {
+	filter := bson.M{
+		"startTime": bson.M{"$lt": to},
+		"endTime":   bson.M{"$gt": from},
+	}
+	cursor, err := m.sessionCollection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "startTime", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	sessions := []*models.Session{}
+	if err = cursor.All(ctx, &sessions); err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
 // DeleteSession deletes a session by its ID
 func (m *MongoDB) DeleteSession(ctx context.Context, id primitive.ObjectID) error {
 	result, err := m.sessionCollection.DeleteOne(ctx, bson.M{"_id": id})
@@ -0,0 +1,266 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"cricketApp/models"
+)
+
+// CreateTournament inserts a new tournament
+func (m *MongoDB) CreateTournament(ctx context.Context, tournament *models.Tournament) error {
+	tournament.CreatedAt = time.Now()
+	tournament.UpdatedAt = tournament.CreatedAt
+	if tournament.ID.IsZero() {
+		tournament.ID = primitive.NewObjectID()
+	}
+	if tournament.Squad == nil {
+		tournament.Squad = []models.SquadMember{}
+	}
+
+	_, err := m.tournamentCollection.InsertOne(ctx, tournament)
+	return err
+}
+
+// GetTournamentByID retrieves a tournament by ID
+func (m *MongoDB) GetTournamentByID(ctx context.Context, id primitive.ObjectID) (*models.Tournament, error) {
+	var tournament models.Tournament
+	err := m.tournamentCollection.FindOne(ctx, bson.M{"_id": id}).Decode(&tournament)
+	if err != nil {
+		return nil, err
+	}
+	return &tournament, nil
+}
+
+// GetTournaments retrieves tournaments, optionally only those ending on or after a date, latest start first
+func (m *MongoDB) GetTournaments(ctx context.Context, endingFrom *time.Time) ([]models.Tournament, error) {
+	filter := bson.M{}
+	if endingFrom != nil {
+		filter["endDate"] = bson.M{"$gte": *endingFrom}
+	}
+
+	cursor, err := m.tournamentCollection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "startDate", Value: -1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	tournaments := []models.Tournament{}
+	if err = cursor.All(ctx, &tournaments); err != nil {
+		return nil, err
+	}
+	return tournaments, nil
+}
+
+// UpdateTournament saves a tournament's details. The squad is saved with SaveTournamentSquad.
+func (m *MongoDB) UpdateTournament(ctx context.Context, id primitive.ObjectID, tournament *models.Tournament) error {
+	tournament.UpdatedAt = time.Now()
+
+	update := bson.M{
+		"$set": bson.M{
+			"name":        tournament.Name,
+			"organiser":   tournament.Organiser,
+			"ageCategory": tournament.AgeCategory,
+			"venue":       tournament.Venue,
+			"startDate":   tournament.StartDate,
+			"endDate":     tournament.EndDate,
+			"squadSize":   tournament.SquadSize,
+			"updatedAt":   tournament.UpdatedAt,
+		},
+	}
+
+	result, err := m.tournamentCollection.UpdateOne(ctx, bson.M{"_id": id}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// SaveTournamentSquad replaces a tournament's squad
+func (m *MongoDB) SaveTournamentSquad(ctx context.Context, id primitive.ObjectID, squad []models.SquadMember, selectedBy string) error {
+	now := time.Now()
+	update := bson.M{
+		"$set": bson.M{
+			"squad":           squad,
+			"squadSelectedBy": selectedBy,
+			"squadSelectedAt": now,
+			"updatedAt":       now,
+		},
+	}
+
+	result, err := m.tournamentCollection.UpdateOne(ctx, bson.M{"_id": id}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// CreateFixture inserts a new fixture
+func (m *MongoDB) CreateFixture(ctx context.Context, fixture *models.Fixture) error {
+	fixture.CreatedAt = time.Now()
+	fixture.UpdatedAt = fixture.CreatedAt
+	if fixture.ID.IsZero() {
+		fixture.ID = primitive.NewObjectID()
+	}
+
+	_, err := m.fixtureCollection.InsertOne(ctx, fixture)
+	return err
+}
+
+// GetFixtureByID retrieves a fixture by ID
+func (m *MongoDB) GetFixtureByID(ctx context.Context, id primitive.ObjectID) (*models.Fixture, error) {
+	var fixture models.Fixture
+	err := m.fixtureCollection.FindOne(ctx, bson.M{"_id": id}).Decode(&fixture)
+	if err != nil {
+		return nil, err
+	}
+	return &fixture, nil
+}
+
+// GetFixtures retrieves a tournament's fixtures in the order they are played
+func (m *MongoDB) GetFixtures(ctx context.Context, tournamentID primitive.ObjectID) ([]models.Fixture, error) {
+	cursor, err := m.fixtureCollection.Find(ctx, bson.M{"tournamentId": tournamentID},
+		options.Find().SetSort(bson.D{{Key: "startsAt", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	fixtures := []models.Fixture{}
+	if err = cursor.All(ctx, &fixtures); err != nil {
+		return nil, err
+	}
+	return fixtures, nil
+}
+
+// UpdateFixture saves a fixture's details. The playing XI is saved with SaveFixturePlayingXI.
+func (m *MongoDB) UpdateFixture(ctx context.Context, id primitive.ObjectID, fixture *models.Fixture) error {
+	fixture.UpdatedAt = time.Now()
+
+	update := bson.M{
+		"$set": bson.M{
+			"opponent":  fixture.Opponent,
+			"startsAt":  fixture.StartsAt,
+			"venue":     fixture.Venue,
+			"stage":     fixture.Stage,
+			"updatedAt": fixture.UpdatedAt,
+		},
+	}
+
+	result, err := m.fixtureCollection.UpdateOne(ctx, bson.M{"_id": id}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// SaveFixturePlayingXI replaces a fixture's playing XI
+func (m *MongoDB) SaveFixturePlayingXI(ctx context.Context, id primitive.ObjectID, xi *models.PlayingXI) error {
+	update := bson.M{
+		"$set": bson.M{
+			"playingXI": xi,
+			"updatedAt": time.Now(),
+		},
+	}
+
+	result, err := m.fixtureCollection.UpdateOne(ctx, bson.M{"_id": id}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// CreateAvailabilityRequests asks cricketers for their availability. Cricketers already asked about
+// the tournament keep their existing request and answer. It returns the requests that were created.
+func (m *MongoDB) CreateAvailabilityRequests(ctx context.Context, requests []models.Availability) ([]models.Availability, error) {
+	created := []models.Availability{}
+	for _, request := range requests {
+		request.ID = primitive.NewObjectID()
+		request.Response = models.AvailabilityPending
+		request.RequestedAt = time.Now()
+
+		filter := bson.M{"tournamentId": request.TournamentID, "cricketerId": request.CricketerID}
+		result, err := m.availabilityCollection.UpdateOne(ctx, filter, bson.M{"$setOnInsert": request}, options.Update().SetUpsert(true))
+		if err != nil {
+			return nil, err
+		}
+		if result.UpsertedCount > 0 {
+			created = append(created, request)
+		}
+	}
+	return created, nil
+}
+
+// GetTournamentAvailability retrieves the availability requests for a tournament by cricketer name
+func (m *MongoDB) GetTournamentAvailability(ctx context.Context, tournamentID primitive.ObjectID) ([]models.Availability, error) {
+	cursor, err := m.availabilityCollection.Find(ctx, bson.M{"tournamentId": tournamentID},
+		options.Find().SetSort(bson.D{{Key: "cricketerName", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	availability := []models.Availability{}
+	if err = cursor.All(ctx, &availability); err != nil {
+		return nil, err
+	}
+	return availability, nil
+}
+
+// GetCricketerAvailability retrieves a cricketer's availability requests, latest tournament first
+func (m *MongoDB) GetCricketerAvailability(ctx context.Context, cricketerID primitive.ObjectID) ([]models.Availability, error) {
+	cursor, err := m.availabilityCollection.Find(ctx, bson.M{"cricketerId": cricketerID},
+		options.Find().SetSort(bson.D{{Key: "startDate", Value: -1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	availability := []models.Availability{}
+	if err = cursor.All(ctx, &availability); err != nil {
+		return nil, err
+	}
+	return availability, nil
+}
+
+// RespondToAvailability records a cricketer's answer to a tournament's availability request.
+// It returns mongo.ErrNoDocuments if the cricketer wasn't asked.
+func (m *MongoDB) RespondToAvailability(ctx context.Context, tournamentID primitive.ObjectID, cricketerID primitive.ObjectID, response string, note string, respondedBy string, respondedByRole string) (*models.Availability, error) {
+	update := bson.M{
+		"$set": bson.M{
+			"response":        response,
+			"note":            note,
+			"respondedBy":     respondedBy,
+			"respondedByRole": respondedByRole,
+			"respondedAt":     time.Now(),
+		},
+	}
+
+	var availability models.Availability
+	err := m.availabilityCollection.FindOneAndUpdate(ctx,
+		bson.M{"tournamentId": tournamentID, "cricketerId": cricketerID},
+		update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&availability)
+	if err != nil {
+		return nil, err
+	}
+	return &availability, nil
+}
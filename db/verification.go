@@ -0,0 +1,78 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"cricketApp/models"
+)
+
+// CreateVerification stores a new verification code
+func (m *MongoDB) CreateVerification(ctx context.Context, verification *models.ContactVerification) error {
+	verification.CreatedAt = time.Now()
+	if verification.ID.IsZero() {
+		verification.ID = primitive.NewObjectID()
+	}
+
+	_, err := m.verificationCollection.InsertOne(ctx, verification)
+	return err
+}
+
+// GetVerificationByID retrieves a verification by its ID
+func (m *MongoDB) GetVerificationByID(ctx context.Context, id primitive.ObjectID) (*models.ContactVerification, error) {
+	var verification models.ContactVerification
+	err := m.verificationCollection.FindOne(ctx, bson.M{"_id": id}).Decode(&verification)
+	if err != nil {
+		return nil, err
+	}
+	return &verification, nil
+}
+
+// RecordVerificationAttempt counts a confirmation attempt and returns the updated verification
+func (m *MongoDB) RecordVerificationAttempt(ctx context.Context, id primitive.ObjectID) (*models.ContactVerification, error) {
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var verification models.ContactVerification
+	err := m.verificationCollection.FindOneAndUpdate(ctx, bson.M{"_id": id}, bson.M{"$inc": bson.M{"attempts": 1}}, opts).Decode(&verification)
+	if err != nil {
+		return nil, err
+	}
+	return &verification, nil
+}
+
+// MarkVerificationVerified records that the correct code was entered
+func (m *MongoDB) MarkVerificationVerified(ctx context.Context, id primitive.ObjectID) error {
+	result, err := m.verificationCollection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"verifiedAt": time.Now()}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// ConsumeVerification marks a verified, unused verification for the given channel and target as used.
+// It returns mongo.ErrNoDocuments if no such verification exists or it was verified before notBefore.
+func (m *MongoDB) ConsumeVerification(ctx context.Context, id primitive.ObjectID, channel string, target string, notBefore time.Time) error {
+	filter := bson.M{
+		"_id":        id,
+		"channel":    channel,
+		"target":     target,
+		"verifiedAt": bson.M{"$gte": notBefore},
+		"consumedAt": bson.M{"$exists": false},
+	}
+	result, err := m.verificationCollection.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"consumedAt": time.Now()}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
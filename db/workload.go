@@ -0,0 +1,178 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"cricketApp/models"
+)
+
+// SaveWorkloadEntries records session deliveries, replacing any earlier count for the same bowler and session
+func (m *MongoDB) SaveWorkloadEntries(ctx context.Context, entries []models.WorkloadEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	writes := make([]mongo.WriteModel, 0, len(entries))
+	for i := range entries {
+		entries[i].UpdatedAt = now
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"cricketerId": entries[i].CricketerID, "sessionId": entries[i].SessionID}).
+			SetUpdate(bson.M{"$set": bson.M{
+				"date":           entries[i].Date,
+				"deliveries":     entries[i].Deliveries,
+				"recordedBy":     entries[i].RecordedBy,
+				"recordedByRole": entries[i].RecordedByRole,
+				"updatedAt":      now,
+			}}).
+			SetUpsert(true))
+	}
+
+	_, err := m.workloadEntryCollection.BulkWrite(ctx, writes)
+	return err
+}
+
+// GetWorkloadEntries retrieves session deliveries for the cricketers dated in [from, to)
+func (m *MongoDB) GetWorkloadEntries(ctx context.Context, cricketerIDs []primitive.ObjectID, from time.Time, to time.Time) ([]models.WorkloadEntry, error) {
+	filter := bson.M{
+		"cricketerId": bson.M{"$in": cricketerIDs},
+		"date":        bson.M{"$gte": from, "$lt": to},
+	}
+
+	cursor, err := m.workloadEntryCollection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "date", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	entries := []models.WorkloadEntry{}
+	if err = cursor.All(ctx, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// GetWorkloadEntriesForSession retrieves the deliveries recorded for a session
+func (m *MongoDB) GetWorkloadEntriesForSession(ctx context.Context, sessionID primitive.ObjectID) ([]models.WorkloadEntry, error) {
+	cursor, err := m.workloadEntryCollection.Find(ctx, bson.M{"sessionId": sessionID})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	entries := []models.WorkloadEntry{}
+	if err = cursor.All(ctx, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// GetWorkloadLimits retrieves the workload limits for every age category
+func (m *MongoDB) GetWorkloadLimits(ctx context.Context) ([]models.WorkloadLimit, error) {
+	cursor, err := m.workloadLimitCollection.Find(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	limits := []models.WorkloadLimit{}
+	if err = cursor.All(ctx, &limits); err != nil {
+		return nil, err
+	}
+	return limits, nil
+}
+
+// SaveWorkloadLimit creates or replaces an age category's workload limits
+func (m *MongoDB) SaveWorkloadLimit(ctx context.Context, limit *models.WorkloadLimit) error {
+	limit.UpdatedAt = time.Now()
+	_, err := m.workloadLimitCollection.ReplaceOne(ctx, bson.M{"_id": limit.AgeCategory}, limit, options.Replace().SetUpsert(true))
+	return err
+}
+
+// EnsureDefaultWorkloadLimits adds the default limits for age categories without any.
+// It returns how many were added.
+func (m *MongoDB) EnsureDefaultWorkloadLimits(ctx context.Context) (int, error) {
+	created := 0
+	for _, limit := range models.DefaultWorkloadLimits() {
+		limit.UpdatedAt = time.Now()
+		result, err := m.workloadLimitCollection.UpdateOne(ctx,
+			bson.M{"_id": limit.AgeCategory},
+			bson.M{"$setOnInsert": limit},
+			options.Update().SetUpsert(true))
+		if err != nil {
+			return created, err
+		}
+		if result.UpsertedCount > 0 {
+			created++
+		}
+	}
+	return created, nil
+}
+
+// CreateWorkloadAlert records an alert unless the bowler already has one of the same kind and level
+// for that day. It reports whether the alert is new.
+func (m *MongoDB) CreateWorkloadAlert(ctx context.Context, alert *models.WorkloadAlert) (bool, error) {
+	alert.CreatedAt = time.Now()
+	if alert.ID.IsZero() {
+		alert.ID = primitive.NewObjectID()
+	}
+
+	filter := bson.M{"cricketerId": alert.CricketerID, "date": alert.Date, "kind": alert.Kind, "level": alert.Level}
+	result, err := m.workloadAlertCollection.UpdateOne(ctx, filter, bson.M{"$setOnInsert": alert}, options.Update().SetUpsert(true))
+	if err != nil {
+		return false, err
+	}
+	return result.UpsertedCount > 0, nil
+}
+
+// GetWorkloadAlertByID retrieves a workload alert by ID
+func (m *MongoDB) GetWorkloadAlertByID(ctx context.Context, id primitive.ObjectID) (*models.WorkloadAlert, error) {
+	var alert models.WorkloadAlert
+	err := m.workloadAlertCollection.FindOne(ctx, bson.M{"_id": id}).Decode(&alert)
+	if err != nil {
+		return nil, err
+	}
+	return &alert, nil
+}
+
+// GetWorkloadAlerts retrieves workload alerts matching the filter, newest first
+func (m *MongoDB) GetWorkloadAlerts(ctx context.Context, filter models.WorkloadAlertFilter) ([]models.WorkloadAlert, error) {
+	query := bson.M{}
+	if filter.BatchIDs != nil {
+		query["batchId"] = bson.M{"$in": filter.BatchIDs}
+	}
+	if filter.CricketerID != nil {
+		query["cricketerId"] = *filter.CricketerID
+	}
+	if filter.Open {
+		query["acknowledgedAt"] = bson.M{"$exists": false}
+	}
+
+	cursor, err := m.workloadAlertCollection.Find(ctx, query, options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	alerts := []models.WorkloadAlert{}
+	if err = cursor.All(ctx, &alerts); err != nil {
+		return nil, err
+	}
+	return alerts, nil
+}
+
+// AcknowledgeWorkloadAlert marks a workload alert as seen. Acknowledging it again keeps the first acknowledgement.
+func (m *MongoDB) AcknowledgeWorkloadAlert(ctx context.Context, id primitive.ObjectID, acknowledgedBy string) (*models.WorkloadAlert, error) {
+	update := bson.M{"$set": bson.M{"acknowledgedBy": acknowledgedBy, "acknowledgedAt": time.Now()}}
+	_, err := m.workloadAlertCollection.UpdateOne(ctx, bson.M{"_id": id, "acknowledgedAt": bson.M{"$exists": false}}, update)
+	if err != nil {
+		return nil, err
+	}
+	return m.GetWorkloadAlertByID(ctx, id)
+}
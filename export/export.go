@@ -0,0 +1,78 @@
+// Package export streams tabular data as CSV or XLSX, one row at a time,
+// so large result sets never have to be held in memory.
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Supported formats
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+)
+
+// Writer writes rows of a table. Close must be called to finish the file.
+type Writer interface {
+	WriteRow(cells []string) error
+	Close() error
+}
+
+// NewWriter returns a Writer for format that writes to w
+func NewWriter(format string, w io.Writer, sheetName string) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return NewCSVWriter(w), nil
+	case FormatXLSX:
+		return NewXLSXWriter(w, sheetName), nil
+	}
+	return nil, fmt.Errorf("unsupported export format %q", format)
+}
+
+// ContentType returns the MIME type for format
+func ContentType(format string) string {
+	if format == FormatXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// CSVWriter writes rows as RFC 4180 CSV
+type CSVWriter struct {
+	csv *csv.Writer
+}
+
+// NewCSVWriter returns a CSVWriter that writes to w
+func NewCSVWriter(w io.Writer) *CSVWriter {
+	return &CSVWriter{csv: csv.NewWriter(w)}
+}
+
+// WriteRow writes one record and flushes it, so rows reach the client as they are produced
+func (c *CSVWriter) WriteRow(cells []string) error {
+	escaped := make([]string, len(cells))
+	for i, cell := range cells {
+		escaped[i] = neutralizeFormula(cell)
+	}
+	if err := c.csv.Write(escaped); err != nil {
+		return err
+	}
+	c.csv.Flush()
+	return c.csv.Error()
+}
+
+// Close flushes any buffered data
+func (c *CSVWriter) Close() error {
+	c.csv.Flush()
+	return c.csv.Error()
+}
+
+// neutralizeFormula stops spreadsheet apps from evaluating a cell that starts like a formula
+func neutralizeFormula(cell string) string {
+	if cell != "" && strings.ContainsRune("=+-@\t\r", rune(cell[0])) {
+		return "'" + cell
+	}
+	return cell
+}
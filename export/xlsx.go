@@ -0,0 +1,179 @@
+package export
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"io"
+	"strconv"
+)
+
+// Static parts of a minimal single-sheet workbook
+const (
+	xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
+<Default Extension="xml" ContentType="application/xml"/>
+<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>
+<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>
+</Types>`
+
+	xlsxRootRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>
+</Relationships>`
+
+	xlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>
+</Relationships>`
+
+	xlsxWorkbookStart = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<sheets><sheet name="`
+
+	xlsxWorkbookEnd = `" sheetId="1" r:id="rId1"/></sheets>
+</workbook>`
+
+	xlsxSheetStart = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`
+
+	xlsxSheetEnd = `</sheetData></worksheet>`
+)
+
+// XLSXWriter writes rows to a single-sheet Excel workbook. Cells are written as inline strings,
+// so the workbook needs no shared string table and rows can be streamed as they arrive.
+type XLSXWriter struct {
+	zip       *zip.Writer
+	sheet     *bufio.Writer
+	sheetName string
+	rows      int
+	err       error
+}
+
+// NewXLSXWriter returns an XLSXWriter that writes to w
+func NewXLSXWriter(w io.Writer, sheetName string) *XLSXWriter {
+	x := &XLSXWriter{zip: zip.NewWriter(w), sheetName: sheetName}
+	if x.sheetName == "" {
+		x.sheetName = "Sheet1"
+	}
+	return x
+}
+
+// WriteRow appends a row to the sheet
+func (x *XLSXWriter) WriteRow(cells []string) error {
+	if x.err != nil {
+		return x.err
+	}
+	if x.sheet == nil {
+		x.err = x.start()
+		if x.err != nil {
+			return x.err
+		}
+	}
+
+	x.rows++
+	row := strconv.Itoa(x.rows)
+	x.sheet.WriteString(`<row r="` + row + `">`)
+	for i, cell := range cells {
+		x.sheet.WriteString(`<c r="` + columnName(i) + row + `" t="inlineStr"><is><t xml:space="preserve">`)
+		xml.EscapeText(x.sheet, []byte(cell))
+		x.sheet.WriteString(`</t></is></c>`)
+	}
+	_, x.err = x.sheet.WriteString(`</row>`)
+	if x.err == nil {
+		x.err = x.sheet.Flush()
+	}
+	return x.err
+}
+
+// Close finishes the sheet and writes the rest of the workbook
+func (x *XLSXWriter) Close() error {
+	if x.err != nil {
+		return x.err
+	}
+	if x.sheet == nil {
+		if x.err = x.start(); x.err != nil {
+			return x.err
+		}
+	}
+	x.sheet.WriteString(xlsxSheetEnd)
+	if x.err = x.sheet.Flush(); x.err != nil {
+		return x.err
+	}
+
+	parts := []struct {
+		name string
+		body func(w io.Writer) error
+	}{
+		{"[Content_Types].xml", staticPart(xlsxContentTypes)},
+		{"_rels/.rels", staticPart(xlsxRootRels)},
+		{"xl/_rels/workbook.xml.rels", staticPart(xlsxWorkbookRels)},
+		{"xl/workbook.xml", func(w io.Writer) error {
+			io.WriteString(w, xlsxWorkbookStart)
+			if err := xml.EscapeText(w, []byte(sheetTitle(x.sheetName))); err != nil {
+				return err
+			}
+			_, err := io.WriteString(w, xlsxWorkbookEnd)
+			return err
+		}},
+	}
+	for _, part := range parts {
+		w, err := x.zip.Create(part.name)
+		if err != nil {
+			x.err = err
+			return err
+		}
+		if err := part.body(w); err != nil {
+			x.err = err
+			return err
+		}
+	}
+
+	x.err = x.zip.Close()
+	return x.err
+}
+
+// start opens the sheet entry. The sheet is written first so rows can stream straight into the archive.
+func (x *XLSXWriter) start() error {
+	w, err := x.zip.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return err
+	}
+	x.sheet = bufio.NewWriter(w)
+	_, err = x.sheet.WriteString(xlsxSheetStart)
+	return err
+}
+
+func staticPart(body string) func(w io.Writer) error {
+	return func(w io.Writer) error {
+		_, err := io.WriteString(w, body)
+		return err
+	}
+}
+
+// columnName converts a zero-based column index to its spreadsheet letters: 0 -> A, 26 -> AA
+func columnName(index int) string {
+	name := ""
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+	return name
+}
+
+// sheetTitle trims a sheet name to what Excel accepts: at most 31 characters, none of []:*?/\
+func sheetTitle(name string) string {
+	runes := []rune{}
+	for _, r := range name {
+		switch r {
+		case '[', ']', ':', '*', '?', '/', '\\':
+			r = '-'
+		}
+		runes = append(runes, r)
+		if len(runes) == 31 {
+			break
+		}
+	}
+	return string(runes)
+}
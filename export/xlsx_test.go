@@ -0,0 +1,181 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type sheetXML struct {
+	XMLName xml.Name `xml:"http://schemas.openxmlformats.org/spreadsheetml/2006/main worksheet"`
+	Rows    []struct {
+		R     string `xml:"r,attr"`
+		Cells []struct {
+			R    string `xml:"r,attr"`
+			T    string `xml:"t,attr"`
+			Text string `xml:"is>t"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+type workbookXML struct {
+	Sheets []struct {
+		Name string `xml:"name,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+// readXLSX unzips a workbook, checks it has every part, and returns the sheet name and rows
+func readXLSX(t *testing.T, data []byte) (string, sheetXML) {
+	t.Helper()
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("not a zip archive: %v", err)
+	}
+
+	parts := map[string][]byte{}
+	for _, file := range archive.File {
+		rc, err := file.Open()
+		if err != nil {
+			t.Fatalf("opening %s: %v", file.Name, err)
+		}
+		body, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatalf("reading %s: %v", file.Name, err)
+		}
+		parts[file.Name] = body
+	}
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/_rels/workbook.xml.rels", "xl/workbook.xml", "xl/worksheets/sheet1.xml"} {
+		body, ok := parts[name]
+		if !ok {
+			t.Fatalf("workbook has no %s", name)
+		}
+		// Every part must be well-formed XML
+		var anything struct{}
+		if err := xml.Unmarshal(body, &anything); err != nil {
+			t.Fatalf("%s is not well-formed: %v", name, err)
+		}
+	}
+
+	var workbook workbookXML
+	if err := xml.Unmarshal(parts["xl/workbook.xml"], &workbook); err != nil {
+		t.Fatalf("decoding workbook: %v", err)
+	}
+	if len(workbook.Sheets) != 1 {
+		t.Fatalf("workbook has %d sheets, want 1", len(workbook.Sheets))
+	}
+	var sheet sheetXML
+	if err := xml.Unmarshal(parts["xl/worksheets/sheet1.xml"], &sheet); err != nil {
+		t.Fatalf("decoding sheet: %v", err)
+	}
+	return workbook.Sheets[0].Name, sheet
+}
+
+func TestXLSXRoundTrip(t *testing.T) {
+	rows := [][]string{
+		{"Form No", "Name", "Contact"},
+		{"42", `Tom & "Jerry" <Jr.>`, "9876543210"},
+		{"  padded  ", "", "line one\nline two"},
+		{"=SUM(A1:A2)", "Ünïcödé क्रिकेट", "'quoted'"},
+	}
+
+	var buf bytes.Buffer
+	w := NewXLSXWriter(&buf, "Registrations")
+	for _, row := range rows {
+		if err := w.WriteRow(row); err != nil {
+			t.Fatalf("WriteRow: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	name, sheet := readXLSX(t, buf.Bytes())
+	if name != "Registrations" {
+		t.Errorf("sheet name %q, want Registrations", name)
+	}
+	if len(sheet.Rows) != len(rows) {
+		t.Fatalf("sheet has %d rows, want %d", len(sheet.Rows), len(rows))
+	}
+	for i, row := range sheet.Rows {
+		if want := string(rune('1' + i)); row.R != want {
+			t.Errorf("row %d is numbered %s, want %s", i, row.R, want)
+		}
+		got := make([]string, len(row.Cells))
+		for j, cell := range row.Cells {
+			if want := string(rune('A'+j)) + row.R; cell.R != want || cell.T != "inlineStr" {
+				t.Errorf("cell %s of type %q, want %s of type inlineStr", cell.R, cell.T, want)
+			}
+			got[j] = cell.Text
+		}
+		// Inline strings are never evaluated, so formulas are kept as written
+		if !reflect.DeepEqual(got, rows[i]) {
+			t.Errorf("row %d = %q, want %q", i, got, rows[i])
+		}
+	}
+}
+
+func TestXLSXReplacesCharactersXMLCantHold(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewXLSXWriter(&buf, "")
+	if err := w.WriteRow([]string{"bell\x07", "nul\x00"}); err != nil {
+		t.Fatalf("WriteRow: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	name, sheet := readXLSX(t, buf.Bytes())
+	if name != "Sheet1" {
+		t.Errorf("sheet name %q, want the default Sheet1", name)
+	}
+	cells := sheet.Rows[0].Cells
+	if cells[0].Text != "bell�" || cells[1].Text != "nul�" {
+		t.Errorf("cells = %q and %q, want control characters replaced", cells[0].Text, cells[1].Text)
+	}
+}
+
+func TestXLSXEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := NewXLSXWriter(&buf, "Empty").Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if _, sheet := readXLSX(t, buf.Bytes()); len(sheet.Rows) != 0 {
+		t.Errorf("sheet has %d rows, want none", len(sheet.Rows))
+	}
+}
+
+func TestXLSXSheetTitle(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"Fees 2026/27", "Fees 2026-27"},
+		{`a[b]c:d*e?f\g`, "a-b-c-d-e-f-g"},
+		{strings.Repeat("x", 40), strings.Repeat("x", 31)},
+		{strings.Repeat("é", 40), strings.Repeat("é", 31)},
+		{"Tom & Jerry <Jr>", "Tom & Jerry <Jr>"},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		if err := NewXLSXWriter(&buf, tt.name).Close(); err != nil {
+			t.Fatalf("Close: %v", err)
+		}
+		if got, _ := readXLSX(t, buf.Bytes()); got != tt.want {
+			t.Errorf("sheet %q is named %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestColumnName(t *testing.T) {
+	tests := map[int]string{0: "A", 25: "Z", 26: "AA", 27: "AB", 51: "AZ", 52: "BA", 701: "ZZ", 702: "AAA"}
+	for index, want := range tests {
+		if got := columnName(index); got != want {
+			t.Errorf("columnName(%d) = %s, want %s", index, got, want)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+// Package formnumber builds registration form numbers such as CCA/2026-27/0001
+// from a configurable pattern and an allocated sequence number.
+//
+// Pattern tokens:
+//
+//	{AY}     academic year, e.g. 2026-27
+//	{YYYY}   year the academic year starts in, e.g. 2026
+//	{SEQ}    sequence number
+//	{SEQ:n}  sequence number zero-padded to n digits
+package formnumber
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultPattern is used when REGISTRATION_FORM_NO_PATTERN is not set
+	DefaultPattern = "CCA/{AY}/{SEQ:4}"
+
+	// DefaultAcademicYearStartMonth is April, when the Indian academic year begins
+	DefaultAcademicYearStartMonth = time.April
+)
+
+var seqPattern = regexp.MustCompile(`\{SEQ(?::(\d+))?\}`)
+
+// Config controls how form numbers are generated
+type Config struct {
+	Pattern                string
+	AcademicYearStartMonth time.Month
+}
+
+// ConfigFromEnv reads REGISTRATION_FORM_NO_PATTERN and ACADEMIC_YEAR_START_MONTH (1-12)
+func ConfigFromEnv() Config {
+	config := Config{
+		Pattern:                os.Getenv("REGISTRATION_FORM_NO_PATTERN"),
+		AcademicYearStartMonth: DefaultAcademicYearStartMonth,
+	}
+	if config.Pattern == "" {
+		config.Pattern = DefaultPattern
+	}
+	if month, err := strconv.Atoi(os.Getenv("ACADEMIC_YEAR_START_MONTH")); err == nil && month >= 1 && month <= 12 {
+		config.AcademicYearStartMonth = time.Month(month)
+	}
+	return config
+}
+
+// AcademicYearStart returns the year the academic year containing t starts in
+func (c Config) AcademicYearStart(t time.Time) int {
+	if t.Month() < c.AcademicYearStartMonth {
+		return t.Year() - 1
+	}
+	return t.Year()
+}
+
+// AcademicYear returns the academic year containing t, e.g. 2026-27
+func (c Config) AcademicYear(t time.Time) string {
+	start := c.AcademicYearStart(t)
+	if c.AcademicYearStartMonth == time.January {
+		return strconv.Itoa(start)
+	}
+	return fmt.Sprintf("%d-%02d", start, (start+1)%100)
+}
+
+// CounterName returns the name of the sequence counter for form numbers issued at t.
+// Patterns that include the academic year get a fresh counter every academic year.
+func (c Config) CounterName(t time.Time) string {
+	if strings.Contains(c.Pattern, "{AY}") || strings.Contains(c.Pattern, "{YYYY}") {
+		return "registrationFormNo:" + c.AcademicYear(t)
+	}
+	return "registrationFormNo"
+}
+
+// Format builds the form number for sequence number seq issued at t
+func (c Config) Format(t time.Time, seq int64) string {
+	formNo := strings.ReplaceAll(c.Pattern, "{AY}", c.AcademicYear(t))
+	formNo = strings.ReplaceAll(formNo, "{YYYY}", strconv.Itoa(c.AcademicYearStart(t)))
+	return seqPattern.ReplaceAllStringFunc(formNo, func(token string) string {
+		width := seqPattern.FindStringSubmatch(token)[1]
+		if width == "" {
+			return strconv.FormatInt(seq, 10)
+		}
+		return fmt.Sprintf("%0"+width+"d", seq)
+	})
+}
@@ -13,12 +13,11 @@ import (
 
 func (h *CricketerHandler) HandleAdminLogin(w http.ResponseWriter, r *http.Request) {
 	var loginRequest struct {
-		Email    string `json:"email"`
-		Password string `json:"password"`
+		Email    string `json:"email" binding:"required"`
+		Password string `json:"password" binding:"required"`
 	}
 
-	if err := json.NewDecoder(r.Body).Decode(&loginRequest); err != nil {
-		http.Error(w, "Invalid request body: "+err.Error(), http.StatusBadRequest)
+	if !decodeRequest(w, r, &loginRequest) {
 		return
 	}
 
@@ -59,9 +58,10 @@ func (h *CricketerHandler) HandleAdminLogin(w http.ResponseWriter, r *http.Reque
 		"message": "Login successful",
 		"token":   tokenString,
 		"admin": map[string]interface{}{
-			"id":    admin.ID,
-			"email": admin.Email,
-			"name":  admin.Name,
+			"id":          admin.ID,
+			"email":       admin.Email,
+			"name":        admin.Name,
+			"permissions": admin.Permissions,
 		},
 	})
 }
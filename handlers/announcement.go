@@ -1,55 +1,79 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
+	"log"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
-	"github.com/go-chi/jwtauth/v5"
+	"github.com/go-chi/chi/v5"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 
+	"cricketApp/db"
+	"cricketApp/markdown"
 	"cricketApp/models"
+	"cricketApp/notification"
 
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
+const (
+	defaultAnnouncementPageSize = 20
+	maxAnnouncementPageSize     = 100
+)
+
+// announcementAuthor is the admin or coach creating or editing an announcement
+type announcementAuthor struct {
+	id   string
+	role string
+	// batchIDs holds the batches a coach is assigned to; unused for admins
+	batchIDs map[primitive.ObjectID]bool
+}
+
+// canManage reports whether the author may edit or delete the announcement.
+// Admins can manage every announcement, coaches only their own.
+func (a *announcementAuthor) canManage(announcement *models.Announcement) bool {
+	return a.role == "admin" || announcement.CreatedBy == a.id
+}
+
 // CreateAnnouncement is now a method of CricketerHandler
 func (h *CricketerHandler) CreateAnnouncement(w http.ResponseWriter, r *http.Request) {
 	var announcement models.Announcement
-	if err := json.NewDecoder(r.Body).Decode(&announcement); err != nil {
-		http.Error(w, "Invalid request body: "+err.Error(), http.StatusBadRequest)
+	if !decodeRequest(w, r, &announcement) {
 		return
 	}
 
-	// Get admin ID (subject) from JWT claims
-	_, claims, err := jwtauth.FromContext(r.Context())
-	if err != nil {
-		http.Error(w, "Invalid token", http.StatusUnauthorized)
-		return
-	}
-	adminIDHex, ok := claims["sub"].(string) // Assuming admin ID (string) is in sub
+	author, ok := h.announcementAuthorFromClaims(w, r)
 	if !ok {
-		http.Error(w, "Invalid token claims (sub is not string)", http.StatusUnauthorized)
 		return
 	}
 
-	adminID, err := primitive.ObjectIDFromHex(adminIDHex)
+	batchIDs, status, err := h.validateAnnouncementBatches(r.Context(), author, announcement.BatchIDs)
 	if err != nil {
-		http.Error(w, "Invalid coach ID format in token", http.StatusUnauthorized)
+		http.Error(w, err.Error(), status)
 		return
 	}
-
-	// Validate if the adminID exists in the database
-	_, err = h.db.GetAdminByID(r.Context(), adminID)
+	attachmentIDs, status, err := h.validateAnnouncementAttachments(r.Context(), announcement.AttachmentIDs)
 	if err != nil {
-		if err == mongo.ErrNoDocuments {
-			http.Error(w, "Admin user not found", http.StatusUnauthorized) // Or Forbidden
-		} else {
-			http.Error(w, "Error validating admin user: "+err.Error(), http.StatusInternalServerError)
-		}
+		http.Error(w, err.Error(), status)
 		return
 	}
 
-	announcement.CreatedBy = adminIDHex
+	// Only the fields below come from the author; everything else is server controlled
+	announcement = models.Announcement{
+		Title:                   announcement.Title,
+		Content:                 announcement.Content,
+		ContentHTML:             markdown.ToSafeHTML(announcement.Content),
+		RequiresAcknowledgement: announcement.RequiresAcknowledgement,
+		AttachmentIDs:           attachmentIDs,
+		BatchIDs:                batchIDs,
+		CreatedBy:               author.id,
+		CreatedByRole:           author.role,
+	}
 
 	// Use the database interface, now returns the created object
 	createdAnnouncement, err := h.db.CreateAnnouncement(r.Context(), &announcement)
@@ -64,15 +88,606 @@ func (h *CricketerHandler) CreateAnnouncement(w http.ResponseWriter, r *http.Req
 	json.NewEncoder(w).Encode(createdAnnouncement)
 }
 
+// UpdateAnnouncement edits an announcement, keeping the previous version in its history.
+// Admins can edit any announcement, coaches only the ones they wrote.
+func (h *CricketerHandler) UpdateAnnouncement(w http.ResponseWriter, r *http.Request) {
+	author, ok := h.announcementAuthorFromClaims(w, r)
+	if !ok {
+		return
+	}
+
+	announcement, ok := h.announcementFromURL(w, r)
+	if !ok {
+		return
+	}
+	if !author.canManage(announcement) {
+		http.Error(w, "You can only edit your own announcements", http.StatusForbidden)
+		return
+	}
+
+	var updateData models.UpdateAnnouncementRequest
+	if !decodeRequest(w, r, &updateData) {
+		return
+	}
+
+	previous := models.AnnouncementRevision{
+		Title:    announcement.Title,
+		Content:  announcement.Content,
+		EditedBy: author.id,
+		EditedAt: time.Now(),
+	}
+
+	if updateData.Title != nil {
+		if strings.TrimSpace(*updateData.Title) == "" {
+			writeFieldError(w, "title", "required", "title is required")
+			return
+		}
+		announcement.Title = *updateData.Title
+	}
+	if updateData.Content != nil {
+		announcement.Content = *updateData.Content
+		announcement.ContentHTML = markdown.ToSafeHTML(announcement.Content)
+	}
+	if updateData.RequiresAcknowledgement != nil {
+		announcement.RequiresAcknowledgement = *updateData.RequiresAcknowledgement
+	}
+	if updateData.BatchIDs != nil {
+		batchIDs, status, err := h.validateAnnouncementBatches(r.Context(), author, *updateData.BatchIDs)
+		if err != nil {
+			http.Error(w, err.Error(), status)
+			return
+		}
+		announcement.BatchIDs = batchIDs
+	}
+	if updateData.AttachmentIDs != nil {
+		attachmentIDs, status, err := h.validateAnnouncementAttachments(r.Context(), *updateData.AttachmentIDs)
+		if err != nil {
+			http.Error(w, err.Error(), status)
+			return
+		}
+		announcement.AttachmentIDs = attachmentIDs
+	}
+
+	announcementID, _ := primitive.ObjectIDFromHex(announcement.ID)
+	if err := h.db.UpdateAnnouncement(r.Context(), announcementID, announcement, previous); err != nil {
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "Announcement not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Error updating announcement: "+err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":      "Announcement updated successfully",
+		"announcement": announcement,
+	})
+}
+
+// DeleteAnnouncement soft-deletes an announcement.
+// Admins can delete any announcement, coaches only the ones they wrote.
+func (h *CricketerHandler) DeleteAnnouncement(w http.ResponseWriter, r *http.Request) {
+	author, ok := h.announcementAuthorFromClaims(w, r)
+	if !ok {
+		return
+	}
+
+	announcement, ok := h.announcementFromURL(w, r)
+	if !ok {
+		return
+	}
+	if !author.canManage(announcement) {
+		http.Error(w, "You can only delete your own announcements", http.StatusForbidden)
+		return
+	}
+
+	announcementID, _ := primitive.ObjectIDFromHex(announcement.ID)
+	if err := h.db.DeleteAnnouncement(r.Context(), announcementID, author.id); err != nil {
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "Announcement not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Error deleting announcement: "+err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": "Announcement deleted successfully"})
+}
+
+// ListAnnouncements returns a page of announcements for admins (all) and coaches
+// (academy-wide plus their own batches), newest first
+func (h *CricketerHandler) ListAnnouncements(w http.ResponseWriter, r *http.Request) {
+	author, ok := h.announcementAuthorFromClaims(w, r)
+	if !ok {
+		return
+	}
+
+	filter, ok := announcementPageFromQuery(w, r)
+	if !ok {
+		return
+	}
+	if author.role == "admin" {
+		filter.AllBatches = true
+	} else {
+		for batchID := range author.batchIDs {
+			filter.BatchIDs = append(filter.BatchIDs, batchID)
+		}
+	}
+
+	announcements, err := h.db.ListAnnouncements(r.Context(), filter)
+	if err != nil {
+		http.Error(w, "Error fetching announcements: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writeAnnouncementPage(w, announcements, filter.Limit)
+}
+
 // GetAnnouncements is now a method of CricketerHandler
 func (h *CricketerHandler) GetAnnouncements(w http.ResponseWriter, r *http.Request) {
-	// Use the database interface
-	announcements, err := h.db.GetAllAnnouncements(r.Context())
+	cricketerID, err := subjectIDFromClaims(r)
+	if err != nil {
+		http.Error(w, "Invalid token claims", http.StatusUnauthorized)
+		return
+	}
+
+	cricketer, err := h.db.GetCricketerByID(r.Context(), cricketerID)
+	if err != nil {
+		http.Error(w, "Error fetching cricketer: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	filter, ok := announcementPageFromQuery(w, r)
+	if !ok {
+		return
+	}
+	if cricketer.BatchID != nil {
+		filter.BatchIDs = []primitive.ObjectID{*cricketer.BatchID}
+	}
+
+	response, err := cricketerAnnouncements(r.Context(), h.db, cricketerID, filter)
 	if err != nil {
 		http.Error(w, "Error fetching announcements: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
 
+	var nextBefore *time.Time
+	if len(response) == filter.Limit {
+		nextBefore = &response[len(response)-1].CreatedAt
+	}
+
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(announcements)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"announcements": response,
+		"nextBefore":    nextBefore,
+	})
+}
+
+// MarkAnnouncementRead records that the logged-in cricketer has read an announcement
+func (h *CricketerHandler) MarkAnnouncementRead(w http.ResponseWriter, r *http.Request) {
+	cricketerID, err := subjectIDFromClaims(r)
+	if err != nil {
+		http.Error(w, "Invalid token claims", http.StatusUnauthorized)
+		return
+	}
+
+	announcement, ok := h.cricketerAnnouncementFromURL(w, r, cricketerID)
+	if !ok {
+		return
+	}
+	announcementID, _ := primitive.ObjectIDFromHex(announcement.ID)
+
+	receipt, err := h.db.MarkAnnouncementRead(r.Context(), announcementID, cricketerID)
+	if err != nil {
+		http.Error(w, "Error marking announcement as read: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(receipt)
+}
+
+// AcknowledgeAnnouncement records that the logged-in cricketer has acknowledged an announcement
+func (h *CricketerHandler) AcknowledgeAnnouncement(w http.ResponseWriter, r *http.Request) {
+	cricketerID, err := subjectIDFromClaims(r)
+	if err != nil {
+		http.Error(w, "Invalid token claims", http.StatusUnauthorized)
+		return
+	}
+
+	announcement, ok := h.cricketerAnnouncementFromURL(w, r, cricketerID)
+	if !ok {
+		return
+	}
+	if !announcement.RequiresAcknowledgement {
+		http.Error(w, "Announcement does not require acknowledgement", http.StatusBadRequest)
+		return
+	}
+	announcementID, _ := primitive.ObjectIDFromHex(announcement.ID)
+
+	receipt, err := h.db.AcknowledgeAnnouncement(r.Context(), announcementID, cricketerID, "")
+	if err != nil {
+		http.Error(w, "Error acknowledging announcement: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(receipt)
+}
+
+// GetAnnouncementReceipts lists who has and hasn't read/acknowledged an announcement (admin only)
+func (h *CricketerHandler) GetAnnouncementReceipts(w http.ResponseWriter, r *http.Request) {
+	announcement, ok := h.announcementFromURL(w, r)
+	if !ok {
+		return
+	}
+
+	report, err := h.buildReceiptReport(r, announcement)
+	if err != nil {
+		http.Error(w, "Error building receipt report: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+// RenotifyAnnouncement re-sends an announcement to everyone who hasn't read it,
+// or hasn't acknowledged it when acknowledgement is required (admin only)
+func (h *CricketerHandler) RenotifyAnnouncement(w http.ResponseWriter, r *http.Request) {
+	announcement, ok := h.announcementFromURL(w, r)
+	if !ok {
+		return
+	}
+
+	report, err := h.buildReceiptReport(r, announcement)
+	if err != nil {
+		http.Error(w, "Error building receipt report: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	stragglers := report.Unread
+	if announcement.RequiresAcknowledgement {
+		stragglers = report.NotAcknowledged
+	}
+
+	notified := 0
+	for _, status := range stragglers {
+		recipient := notification.Recipient{Name: status.Name, Mobile: status.Mobile}
+		if err := h.notifier.Notify(r.Context(), recipient, "Reminder: "+announcement.Title, announcement.Content); err != nil {
+			log.Printf("Error re-notifying cricketer %s about announcement %s: %v", status.CricketerID.Hex(), announcement.ID, err)
+			continue
+		}
+		notified++
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":    "Reminders sent successfully",
+		"notified":   notified,
+		"recipients": stragglers,
+	})
+}
+
+// announcementAuthorFromClaims identifies the admin or coach making the request,
+// writing an error response and returning false if they cannot author announcements
+func (h *CricketerHandler) announcementAuthorFromClaims(w http.ResponseWriter, r *http.Request) (*announcementAuthor, bool) {
+	userID, err := subjectIDFromClaims(r)
+	if err != nil {
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return nil, false
+	}
+
+	author := &announcementAuthor{id: userID.Hex(), role: roleFromClaims(r)}
+	switch author.role {
+	case "admin":
+		// Validate if the adminID exists in the database
+		_, err = h.db.GetAdminByID(r.Context(), userID)
+		if err != nil {
+			if err == mongo.ErrNoDocuments {
+				http.Error(w, "Admin user not found", http.StatusUnauthorized) // Or Forbidden
+			} else {
+				http.Error(w, "Error validating admin user: "+err.Error(), http.StatusInternalServerError)
+			}
+			return nil, false
+		}
+	case "coach":
+		_, err = h.db.GetCoachByID(r.Context(), userID)
+		if err != nil {
+			if err == mongo.ErrNoDocuments {
+				http.Error(w, "Coach not found", http.StatusUnauthorized)
+			} else {
+				http.Error(w, "Error validating coach: "+err.Error(), http.StatusInternalServerError)
+			}
+			return nil, false
+		}
+		batches, err := h.db.GetBatchesByCoach(r.Context(), userID)
+		if err != nil {
+			http.Error(w, "Error fetching coach batches: "+err.Error(), http.StatusInternalServerError)
+			return nil, false
+		}
+		author.batchIDs = make(map[primitive.ObjectID]bool, len(batches))
+		for _, batch := range batches {
+			author.batchIDs[batch.ID] = true
+		}
+	default:
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return nil, false
+	}
+	return author, true
+}
+
+// validateAnnouncementBatches checks the target batches exist and, for coaches, that
+// every batch is one they coach. Coaches cannot post academy-wide announcements.
+func (h *CricketerHandler) validateAnnouncementBatches(ctx context.Context, author *announcementAuthor, batchIDs []primitive.ObjectID) ([]primitive.ObjectID, int, error) {
+	batchIDs = uniqueObjectIDs(batchIDs)
+
+	if author.role == "coach" {
+		if len(batchIDs) == 0 {
+			return nil, http.StatusForbidden, fmt.Errorf("Coaches must target at least one of their batches")
+		}
+		for _, id := range batchIDs {
+			if !author.batchIDs[id] {
+				return nil, http.StatusForbidden, fmt.Errorf("You can only post to batches you coach")
+			}
+		}
+		return batchIDs, http.StatusOK, nil
+	}
+
+	for _, id := range batchIDs {
+		if _, err := h.db.GetBatchByID(ctx, id); err != nil {
+			if err == mongo.ErrNoDocuments {
+				return nil, http.StatusBadRequest, fmt.Errorf("Batch %s not found", id.Hex())
+			}
+			return nil, http.StatusInternalServerError, fmt.Errorf("Error validating batches: %v", err)
+		}
+	}
+	return batchIDs, http.StatusOK, nil
+}
+
+// validateAnnouncementAttachments makes sure every referenced attachment has been uploaded
+func (h *CricketerHandler) validateAnnouncementAttachments(ctx context.Context, attachmentIDs []primitive.ObjectID) ([]primitive.ObjectID, int, error) {
+	attachmentIDs = uniqueObjectIDs(attachmentIDs)
+	if len(attachmentIDs) == 0 {
+		return nil, http.StatusOK, nil
+	}
+
+	attachments, err := h.db.GetAttachmentsByIDs(ctx, attachmentIDs)
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("Error validating attachments: %v", err)
+	}
+	if len(attachments) != len(attachmentIDs) {
+		return nil, http.StatusBadRequest, fmt.Errorf("One or more attachments not found")
+	}
+	return attachmentIDs, http.StatusOK, nil
+}
+
+// announcementFromURL loads the announcement identified by the {id} URL parameter,
+// writing an error response and returning false if it cannot be found or was deleted
+func (h *CricketerHandler) announcementFromURL(w http.ResponseWriter, r *http.Request) (*models.Announcement, bool) {
+	announcementID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid announcement ID", http.StatusBadRequest)
+		return nil, false
+	}
+
+	announcement, err := h.db.GetAnnouncementByID(r.Context(), announcementID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "Announcement not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Error fetching announcement: "+err.Error(), http.StatusInternalServerError)
+		}
+		return nil, false
+	}
+	if announcement.DeletedAt != nil {
+		http.Error(w, "Announcement not found", http.StatusNotFound)
+		return nil, false
+	}
+	return announcement, true
+}
+
+// cricketerAnnouncementFromURL is announcementFromURL restricted to announcements the cricketer can see
+func (h *CricketerHandler) cricketerAnnouncementFromURL(w http.ResponseWriter, r *http.Request, cricketerID primitive.ObjectID) (*models.Announcement, bool) {
+	announcement, ok := h.announcementFromURL(w, r)
+	if !ok {
+		return nil, false
+	}
+
+	cricketer, err := h.db.GetCricketerByID(r.Context(), cricketerID)
+	if err != nil {
+		http.Error(w, "Error fetching cricketer: "+err.Error(), http.StatusInternalServerError)
+		return nil, false
+	}
+	if !announcementVisibleTo(announcement, cricketer) {
+		http.Error(w, "Announcement not found", http.StatusNotFound)
+		return nil, false
+	}
+	return announcement, true
+}
+
+// buildReceiptReport splits the announcement's audience into read/unread and acknowledged/not acknowledged
+func (h *CricketerHandler) buildReceiptReport(r *http.Request, announcement *models.Announcement) (*models.AnnouncementReceiptReport, error) {
+	announcementID, err := primitive.ObjectIDFromHex(announcement.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var cricketers []models.Cricketer
+	if len(announcement.BatchIDs) > 0 {
+		cricketers, err = h.db.GetCricketersByBatches(r.Context(), announcement.BatchIDs)
+	} else {
+		cricketers, err = h.db.GetAllCricketers(r.Context())
+	}
+	if err != nil {
+		return nil, err
+	}
+	receipts, err := h.db.GetAnnouncementReceipts(r.Context(), announcementID)
+	if err != nil {
+		return nil, err
+	}
+	receiptsByCricketer := make(map[primitive.ObjectID]models.AnnouncementReceipt, len(receipts))
+	for _, receipt := range receipts {
+		receiptsByCricketer[receipt.CricketerID] = receipt
+	}
+
+	report := &models.AnnouncementReceiptReport{
+		Announcement:    *announcement,
+		Read:            []models.AnnouncementRecipientStatus{},
+		Unread:          []models.AnnouncementRecipientStatus{},
+		Acknowledged:    []models.AnnouncementRecipientStatus{},
+		NotAcknowledged: []models.AnnouncementRecipientStatus{},
+	}
+	for _, c := range cricketers {
+		if c.InactiveCricketer {
+			continue
+		}
+
+		status := models.AnnouncementRecipientStatus{
+			CricketerID: c.ID,
+			Name:        c.Name,
+			Mobile:      c.Mobile,
+		}
+		receipt, ok := receiptsByCricketer[c.ID]
+		if ok {
+			readAt := receipt.ReadAt
+			status.ReadAt = &readAt
+			status.AcknowledgedAt = receipt.AcknowledgedAt
+			report.Read = append(report.Read, status)
+		} else {
+			report.Unread = append(report.Unread, status)
+		}
+
+		if announcement.RequiresAcknowledgement {
+			if status.AcknowledgedAt != nil {
+				report.Acknowledged = append(report.Acknowledged, status)
+			} else {
+				report.NotAcknowledged = append(report.NotAcknowledged, status)
+			}
+		}
+	}
+
+	return report, nil
+}
+
+// cricketerAnnouncements lists the announcements matching filter with the cricketer's read/acknowledgement state attached
+func cricketerAnnouncements(ctx context.Context, database db.Database, cricketerID primitive.ObjectID, filter models.AnnouncementFilter) ([]models.CricketerAnnouncement, error) {
+	announcements, err := database.ListAnnouncements(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	receipts, err := database.GetAnnouncementReceiptsByCricketer(ctx, cricketerID)
+	if err != nil {
+		return nil, err
+	}
+	receiptsByAnnouncement := make(map[string]models.AnnouncementReceipt, len(receipts))
+	for _, receipt := range receipts {
+		receiptsByAnnouncement[receipt.AnnouncementID.Hex()] = receipt
+	}
+
+	response := make([]models.CricketerAnnouncement, len(announcements))
+	for i, announcement := range announcements {
+		announcement.History = nil // Edit history is only for staff
+		response[i] = models.CricketerAnnouncement{Announcement: announcement}
+		if receipt, ok := receiptsByAnnouncement[announcement.ID]; ok {
+			readAt := receipt.ReadAt
+			response[i].ReadAt = &readAt
+			response[i].AcknowledgedAt = receipt.AcknowledgedAt
+			response[i].AcknowledgedBy = receipt.AcknowledgedBy
+		}
+	}
+	return response, nil
+}
+
+// announcementVisibleTo reports whether an active cricketer is in the announcement's audience
+func announcementVisibleTo(announcement *models.Announcement, cricketer *models.Cricketer) bool {
+	if announcement.DeletedAt != nil || cricketer.InactiveCricketer {
+		return false
+	}
+	if len(announcement.BatchIDs) == 0 {
+		return true
+	}
+	if cricketer.BatchID == nil {
+		return false
+	}
+	for _, id := range announcement.BatchIDs {
+		if id == *cricketer.BatchID {
+			return true
+		}
+	}
+	return false
+}
+
+// announcementPageFromQuery reads the limit, before (RFC 3339 createdAt cursor) and beforeId query
+// parameters
+func announcementPageFromQuery(w http.ResponseWriter, r *http.Request) (models.AnnouncementFilter, bool) {
+	filter := models.AnnouncementFilter{Limit: defaultAnnouncementPageSize}
+
+	if limit := r.URL.Query().Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 1 {
+			http.Error(w, "Invalid limit", http.StatusBadRequest)
+			return filter, false
+		}
+		if n > maxAnnouncementPageSize {
+			n = maxAnnouncementPageSize
+		}
+		filter.Limit = n
+	}
+
+	if before := r.URL.Query().Get("before"); before != "" {
+		t, err := time.Parse(time.RFC3339Nano, before)
+		if err != nil {
+			http.Error(w, "Invalid before cursor, expected RFC 3339 time", http.StatusBadRequest)
+			return filter, false
+		}
+		filter.Before = &t
+	}
+
+	if beforeID := r.URL.Query().Get("beforeId"); beforeID != "" {
+		id, err := primitive.ObjectIDFromHex(beforeID)
+		if err != nil || filter.Before == nil {
+			http.Error(w, "Invalid beforeId cursor, expected an ID alongside before", http.StatusBadRequest)
+			return filter, false
+		}
+		filter.BeforeID = &id
+	}
+
+	return filter, true
+}
+
+// writeAnnouncementPage responds with a page of announcements and the cursor for the next page
+func writeAnnouncementPage(w http.ResponseWriter, announcements []models.Announcement, limit int) {
+	var nextBefore *time.Time
+	var nextBeforeID *string
+	if len(announcements) == limit {
+		last := announcements[len(announcements)-1]
+		nextBefore, nextBeforeID = &last.CreatedAt, &last.ID
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"announcements": announcements,
+		"nextBefore":    nextBefore,
+		"nextBeforeId":  nextBeforeID,
+	})
+}
+
+// uniqueObjectIDs removes duplicate IDs while preserving order
+func uniqueObjectIDs(ids []primitive.ObjectID) []primitive.ObjectID {
+	if len(ids) == 0 {
+		return nil
+	}
+	seen := make(map[primitive.ObjectID]bool, len(ids))
+	unique := make([]primitive.ObjectID, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
 }
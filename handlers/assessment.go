@@ -0,0 +1,657 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"cricketApp/db"
+	"cricketApp/models"
+)
+
+// AssessmentHandler manages assessment templates and the skill assessments coaches record against them
+type AssessmentHandler struct {
+	db db.Database
+}
+
+func NewAssessmentHandler(db db.Database) *AssessmentHandler {
+	return &AssessmentHandler{db: db}
+}
+
+// CreateAssessmentTemplate creates a template with its criteria and rubric scale (admin only)
+func (h *AssessmentHandler) CreateAssessmentTemplate(w http.ResponseWriter, r *http.Request) {
+	adminID, err := subjectIDFromClaims(r)
+	if err != nil {
+		http.Error(w, "Invalid token claims", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.CreateAssessmentTemplateRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	keys := make(map[string]bool, len(req.Criteria))
+	for i, criterion := range req.Criteria {
+		key := strings.TrimSpace(criterion.Key)
+		if keys[key] {
+			writeFieldError(w, fmt.Sprintf("criteria[%d].key", i), "unique", "criterion keys must be unique")
+			return
+		}
+		keys[key] = true
+		req.Criteria[i].Key = key
+	}
+
+	sort.SliceStable(req.Scale, func(i, j int) bool { return req.Scale[i].Score < req.Scale[j].Score })
+	for i := 1; i < len(req.Scale); i++ {
+		if req.Scale[i].Score == req.Scale[i-1].Score {
+			writeFieldError(w, "scale", "unique", "each level must have a different score")
+			return
+		}
+	}
+
+	template := &models.AssessmentTemplate{
+		Name:        strings.TrimSpace(req.Name),
+		Category:    req.Category,
+		Description: req.Description,
+		Criteria:    req.Criteria,
+		Scale:       req.Scale,
+		Active:      true,
+		CreatedBy:   adminID.Hex(),
+	}
+	if err := h.db.CreateAssessmentTemplate(r.Context(), template); err != nil {
+		http.Error(w, "Error creating assessment template", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":  "Assessment template created successfully",
+		"template": template,
+	})
+}
+
+// GetAssessmentTemplates lists the active templates, or every template with ?includeInactive=true
+func (h *AssessmentHandler) GetAssessmentTemplates(w http.ResponseWriter, r *http.Request) {
+	includeInactive, _ := strconv.ParseBool(r.URL.Query().Get("includeInactive"))
+
+	templates, err := h.db.GetAssessmentTemplates(r.Context(), !includeInactive)
+	if err != nil {
+		http.Error(w, "Error fetching assessment templates", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(templates)
+}
+
+// UpdateAssessmentTemplate renames or retires a template (admin only). Retired templates keep
+// their assessments but can't be used for new ones.
+func (h *AssessmentHandler) UpdateAssessmentTemplate(w http.ResponseWriter, r *http.Request) {
+	templateID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid template ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.UpdateAssessmentTemplateRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	template, err := h.db.GetAssessmentTemplateByID(r.Context(), templateID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "Assessment template not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Error fetching assessment template", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			writeFieldError(w, "name", "required", "is required")
+			return
+		}
+		template.Name = name
+	}
+	if req.Description != nil {
+		template.Description = *req.Description
+	}
+	if req.Active != nil {
+		template.Active = *req.Active
+	}
+
+	if err := h.db.UpdateAssessmentTemplate(r.Context(), templateID, template); err != nil {
+		http.Error(w, "Error updating assessment template", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":  "Assessment template updated successfully",
+		"template": template,
+	})
+}
+
+// CreateAssessment records an assessment of a cricketer against an active template.
+// Coaches can only assess cricketers in batches they coach.
+func (h *AssessmentHandler) CreateAssessment(w http.ResponseWriter, r *http.Request) {
+	userID, err := subjectIDFromClaims(r)
+	if err != nil {
+		http.Error(w, "Invalid token claims", http.StatusUnauthorized)
+		return
+	}
+	role := roleFromClaims(r)
+
+	var req models.AssessmentRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	cricketerID, _ := primitive.ObjectIDFromHex(req.CricketerID)
+	cricketer, ok := staffCricketer(w, r, h.db, cricketerID)
+	if !ok {
+		return
+	}
+
+	templateID, _ := primitive.ObjectIDFromHex(req.TemplateID)
+	template, err := h.db.GetAssessmentTemplateByID(r.Context(), templateID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			writeFieldError(w, "templateId", "exists", "assessment template not found")
+		} else {
+			http.Error(w, "Error fetching assessment template", http.StatusInternalServerError)
+		}
+		return
+	}
+	if !template.Active {
+		writeFieldError(w, "templateId", "active", "assessment template has been retired")
+		return
+	}
+
+	assessment := &models.Assessment{
+		CricketerID:    cricketer.ID,
+		TemplateID:     template.ID,
+		TemplateName:   template.Name,
+		Category:       template.Category,
+		AssessedBy:     userID.Hex(),
+		AssessedByRole: role,
+	}
+	if !applyAssessmentRequest(w, template, assessment, &req) {
+		return
+	}
+
+	assessment.AssessorName, err = staffName(r.Context(), h.db, userID, role)
+	if err != nil {
+		http.Error(w, "Error fetching assessor", http.StatusInternalServerError)
+		return
+	}
+
+	if err := h.db.CreateAssessment(r.Context(), assessment); err != nil {
+		http.Error(w, "Error creating assessment", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":    "Assessment recorded successfully",
+		"assessment": assessment,
+	})
+}
+
+// UpdateAssessment corrects an assessment's date, scores or comments. Coaches can only
+// correct their own assessments; the cricketer and template can't change.
+func (h *AssessmentHandler) UpdateAssessment(w http.ResponseWriter, r *http.Request) {
+	assessmentID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid assessment ID", http.StatusBadRequest)
+		return
+	}
+	userID, err := subjectIDFromClaims(r)
+	if err != nil {
+		http.Error(w, "Invalid token claims", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.AssessmentRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	assessment, err := h.db.GetAssessmentByID(r.Context(), assessmentID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "Assessment not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Error fetching assessment", http.StatusInternalServerError)
+		}
+		return
+	}
+	if roleFromClaims(r) != "admin" && assessment.AssessedBy != userID.Hex() {
+		http.Error(w, "You can only correct assessments you recorded", http.StatusForbidden)
+		return
+	}
+	if req.CricketerID != assessment.CricketerID.Hex() {
+		writeFieldError(w, "cricketerId", "immutable", "can't be changed; record a new assessment instead")
+		return
+	}
+	if req.TemplateID != assessment.TemplateID.Hex() {
+		writeFieldError(w, "templateId", "immutable", "can't be changed; record a new assessment instead")
+		return
+	}
+
+	template, err := h.db.GetAssessmentTemplateByID(r.Context(), assessment.TemplateID)
+	if err != nil {
+		http.Error(w, "Error fetching assessment template", http.StatusInternalServerError)
+		return
+	}
+	if !applyAssessmentRequest(w, template, assessment, &req) {
+		return
+	}
+
+	if err := h.db.UpdateAssessment(r.Context(), assessmentID, assessment); err != nil {
+		http.Error(w, "Error updating assessment", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":    "Assessment updated successfully",
+		"assessment": assessment,
+	})
+}
+
+// GetCricketerAssessments lists a cricketer's assessments, oldest first, optionally for one ?templateId
+func (h *AssessmentHandler) GetCricketerAssessments(w http.ResponseWriter, r *http.Request) {
+	cricketer, ok := staffCricketerFromURL(w, r, h.db)
+	if !ok {
+		return
+	}
+	filter := models.AssessmentFilter{CricketerID: &cricketer.ID}
+	if !assessmentTemplateFromQuery(w, r, &filter) {
+		return
+	}
+
+	assessments, err := h.db.GetAssessments(r.Context(), filter)
+	if err != nil {
+		http.Error(w, "Error fetching assessments", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(assessments)
+}
+
+// GetAssessmentProgress compares a cricketer's assessments over time, one entry per template
+func (h *AssessmentHandler) GetAssessmentProgress(w http.ResponseWriter, r *http.Request) {
+	cricketer, ok := staffCricketerFromURL(w, r, h.db)
+	if !ok {
+		return
+	}
+	writeAssessmentProgress(w, r, h.db, cricketer)
+}
+
+// GetTermReport returns a cricketer's term report for ?from/?to, or the current season
+func (h *AssessmentHandler) GetTermReport(w http.ResponseWriter, r *http.Request) {
+	cricketer, ok := staffCricketerFromURL(w, r, h.db)
+	if !ok {
+		return
+	}
+	writeTermReport(w, r, h.db, cricketer)
+}
+
+// GetChildAssessmentProgress shows a guardian how their child's assessments have changed
+func (h *GuardianHandler) GetChildAssessmentProgress(w http.ResponseWriter, r *http.Request) {
+	guardian, ok := h.guardianFromClaims(w, r)
+	if !ok {
+		return
+	}
+	cricketer, ok := h.childFromURL(w, r, guardian)
+	if !ok {
+		return
+	}
+	writeAssessmentProgress(w, r, h.db, cricketer)
+}
+
+// GetChildTermReport returns a child's term report for their guardian
+func (h *GuardianHandler) GetChildTermReport(w http.ResponseWriter, r *http.Request) {
+	guardian, ok := h.guardianFromClaims(w, r)
+	if !ok {
+		return
+	}
+	cricketer, ok := h.childFromURL(w, r, guardian)
+	if !ok {
+		return
+	}
+	writeTermReport(w, r, h.db, cricketer)
+}
+
+// applyAssessmentRequest checks every criterion on the template is scored once on its scale and
+// copies the request onto the assessment, writing a field error and returning false if it isn't
+func applyAssessmentRequest(w http.ResponseWriter, template *models.AssessmentTemplate, assessment *models.Assessment, req *models.AssessmentRequest) bool {
+	names := make(map[string]bool, len(template.Criteria))
+	for _, criterion := range template.Criteria {
+		names[criterion.Key] = true
+	}
+
+	scored := make(map[string]models.AssessmentScore, len(req.Scores))
+	for i, score := range req.Scores {
+		field := fmt.Sprintf("scores[%d]", i)
+		if !names[score.CriterionKey] {
+			writeFieldError(w, field+".criterionKey", "exists", "is not a criterion on this template")
+			return false
+		}
+		if _, ok := scored[score.CriterionKey]; ok {
+			writeFieldError(w, field+".criterionKey", "unique", "criterion is scored more than once")
+			return false
+		}
+		if _, ok := template.Level(score.Score); !ok {
+			low, high := template.ScaleRange()
+			writeFieldError(w, field+".score", "scale", fmt.Sprintf("must be a level on the template's scale (%d-%d)", low, high))
+			return false
+		}
+		scored[score.CriterionKey] = score
+	}
+
+	// Store scores in the template's criterion order so assessments line up
+	scores := make([]models.AssessmentScore, 0, len(template.Criteria))
+	total := 0
+	for _, criterion := range template.Criteria {
+		score, ok := scored[criterion.Key]
+		if !ok {
+			writeFieldError(w, "scores", "required", "criterion "+criterion.Key+" must be scored")
+			return false
+		}
+		scores = append(scores, score)
+		total += score.Score
+	}
+
+	assessment.AssessedOn = req.AssessedOn
+	assessment.Scores = scores
+	assessment.Average = math.Round(float64(total)/float64(len(scores))*100) / 100
+	assessment.Percent = 0
+	if low, high := template.ScaleRange(); high > low {
+		percent := (float64(total)/float64(len(scores)) - float64(low)) / float64(high-low) * 100
+		assessment.Percent = math.Round(percent*100) / 100
+	}
+	assessment.Strengths = strings.TrimSpace(req.Strengths)
+	assessment.AreasToImprove = strings.TrimSpace(req.AreasToImprove)
+	assessment.Comments = strings.TrimSpace(req.Comments)
+	return true
+}
+
+// assessmentTemplateFromQuery narrows filter to the ?templateId query parameter, if any
+func assessmentTemplateFromQuery(w http.ResponseWriter, r *http.Request, filter *models.AssessmentFilter) bool {
+	value := r.URL.Query().Get("templateId")
+	if value == "" {
+		return true
+	}
+	templateID, err := primitive.ObjectIDFromHex(value)
+	if err != nil {
+		http.Error(w, "Invalid template ID", http.StatusBadRequest)
+		return false
+	}
+	filter.TemplateID = &templateID
+	return true
+}
+
+func writeAssessmentProgress(w http.ResponseWriter, r *http.Request, database db.Database, cricketer *models.Cricketer) {
+	filter := models.AssessmentFilter{CricketerID: &cricketer.ID}
+	if !assessmentTemplateFromQuery(w, r, &filter) {
+		return
+	}
+
+	progress, err := assessmentProgress(r.Context(), database, filter)
+	if err != nil {
+		http.Error(w, "Error fetching assessments", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(progress)
+}
+
+// writeTermReport responds with a cricketer's assessments and attendance for the period given by
+// ?from and ?to (YYYY-MM-DD, both inclusive), defaulting to the season containing today
+func writeTermReport(w http.ResponseWriter, r *http.Request, database db.Database, cricketer *models.Cricketer) {
+	now := time.Now()
+	season, err := seasonAt(r.Context(), database, now)
+	if err != nil {
+		http.Error(w, "Error fetching season", http.StatusInternalServerError)
+		return
+	}
+
+	report := &models.TermReport{
+		CricketerID:   cricketer.ID,
+		CricketerName: cricketer.Name,
+		AgeCategory:   ageCategoryAt(cricketer.DateOfBirth, season),
+		Term:          season.Name,
+		From:          season.StartDate,
+		To:            season.EndDate,
+		Sections:      []models.TermReportSection{},
+		GeneratedAt:   now,
+	}
+	end := season.EndDate.Add(time.Nanosecond)
+
+	query := r.URL.Query()
+	if query.Get("from") != "" || query.Get("to") != "" {
+		from, errFrom := time.Parse("2006-01-02", query.Get("from"))
+		to, errTo := time.Parse("2006-01-02", query.Get("to"))
+		if errFrom != nil || errTo != nil {
+			http.Error(w, "Invalid term, expected from and to dates as YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+		if to.Before(from) {
+			http.Error(w, "Invalid term, to is before from", http.StatusBadRequest)
+			return
+		}
+		end = to.AddDate(0, 0, 1)
+		report.Term = from.Format("2 Jan 2006") + " - " + to.Format("2 Jan 2006")
+		report.From = from
+		report.To = end.Add(-time.Nanosecond)
+	}
+
+	if cricketer.BatchID != nil {
+		batch, err := database.GetBatchByID(r.Context(), *cricketer.BatchID)
+		if err != nil && err != mongo.ErrNoDocuments {
+			http.Error(w, "Error fetching batch", http.StatusInternalServerError)
+			return
+		}
+		if batch != nil {
+			report.BatchName = batch.Name
+		}
+	}
+
+	attendance, err := database.GetAttendanceForCricketer(r.Context(), cricketer.ID, report.From)
+	if err != nil {
+		http.Error(w, "Error fetching attendance", http.StatusInternalServerError)
+		return
+	}
+	for _, record := range attendance {
+		if record.SessionDate.Before(end) {
+			report.Attendance.Add(record.Status)
+		}
+	}
+
+	progress, err := assessmentProgress(r.Context(), database, models.AssessmentFilter{
+		CricketerID: &cricketer.ID,
+		From:        &report.From,
+		To:          &end,
+	})
+	if err != nil {
+		http.Error(w, "Error fetching assessments", http.StatusInternalServerError)
+		return
+	}
+	for _, entry := range progress {
+		report.Sections = append(report.Sections, models.TermReportSection{
+			TemplateID:   entry.Template.ID,
+			TemplateName: entry.Template.Name,
+			Category:     entry.Template.Category,
+			Scale:        entry.Template.Scale,
+			Assessments:  len(entry.Assessments),
+			Latest:       entry.Assessments[len(entry.Assessments)-1],
+			Changes:      entry.Changes,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+// assessmentProgress groups the matching assessments by template and compares each criterion's
+// first, previous and latest scores. Templates are ordered by category and name.
+func assessmentProgress(ctx context.Context, database db.Database, filter models.AssessmentFilter) ([]models.AssessmentProgress, error) {
+	assessments, err := database.GetAssessments(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	progress := []models.AssessmentProgress{}
+	index := make(map[primitive.ObjectID]int)
+	for _, assessment := range assessments {
+		i, ok := index[assessment.TemplateID]
+		if !ok {
+			template, err := database.GetAssessmentTemplateByID(ctx, assessment.TemplateID)
+			if err != nil {
+				return nil, err
+			}
+			i = len(progress)
+			index[assessment.TemplateID] = i
+			progress = append(progress, models.AssessmentProgress{Template: *template})
+		}
+		progress[i].Assessments = append(progress[i].Assessments, assessment)
+	}
+
+	for i := range progress {
+		progress[i].Changes = criterionChanges(&progress[i].Template, progress[i].Assessments)
+	}
+	sort.SliceStable(progress, func(i, j int) bool {
+		if progress[i].Template.Category != progress[j].Template.Category {
+			return progress[i].Template.Category < progress[j].Template.Category
+		}
+		return progress[i].Template.Name < progress[j].Template.Name
+	})
+	return progress, nil
+}
+
+// criterionChanges compares each criterion across assessments ordered oldest first
+func criterionChanges(template *models.AssessmentTemplate, assessments []models.Assessment) []models.CriterionChange {
+	changes := make([]models.CriterionChange, 0, len(template.Criteria))
+	for _, criterion := range template.Criteria {
+		change := models.CriterionChange{Key: criterion.Key, Name: criterion.Name}
+		for i := range assessments {
+			score, ok := assessments[i].Score(criterion.Key)
+			if !ok {
+				continue
+			}
+			if change.First == nil {
+				change.First = &score
+			}
+			change.Previous = change.Latest
+			change.Latest = &score
+		}
+		if change.Latest != nil {
+			change.Change = *change.Latest - *change.First
+			if change.Previous != nil {
+				change.Recent = *change.Latest - *change.Previous
+			}
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+// staffCricketerFromURL loads the cricketer named by {id} for an admin or one of the cricketer's coaches
+func staffCricketerFromURL(w http.ResponseWriter, r *http.Request, database db.Database) (*models.Cricketer, bool) {
+	cricketerID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid cricketer ID", http.StatusBadRequest)
+		return nil, false
+	}
+	return staffCricketer(w, r, database, cricketerID)
+}
+
+// staffCricketer loads a cricketer for staff. Coaches only get cricketers in batches they coach;
+// admins get any cricketer.
+func staffCricketer(w http.ResponseWriter, r *http.Request, database db.Database, cricketerID primitive.ObjectID) (*models.Cricketer, bool) {
+	cricketer, err := database.GetCricketerByID(r.Context(), cricketerID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "Cricketer not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Error fetching cricketer", http.StatusInternalServerError)
+		}
+		return nil, false
+	}
+
+	if roleFromClaims(r) != "coach" {
+		return cricketer, true
+	}
+
+	coachID, err := subjectIDFromClaims(r)
+	if err != nil {
+		http.Error(w, "Invalid token claims", http.StatusUnauthorized)
+		return nil, false
+	}
+	coaches, err := coachesCricketer(r.Context(), database, coachID, cricketer)
+	if err != nil {
+		http.Error(w, "Error fetching batch", http.StatusInternalServerError)
+		return nil, false
+	}
+	if !coaches {
+		http.Error(w, "Cricketer not found", http.StatusNotFound)
+		return nil, false
+	}
+	return cricketer, true
+}
+
+// coachesCricketer reports whether the coach coaches the cricketer's batch
+func coachesCricketer(ctx context.Context, database db.Database, coachID primitive.ObjectID, cricketer *models.Cricketer) (bool, error) {
+	if cricketer.BatchID == nil {
+		return false, nil
+	}
+	batch, err := database.GetBatchByID(ctx, *cricketer.BatchID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return false, nil
+		}
+		return false, err
+	}
+	for _, id := range batch.CoachIDs {
+		if id == coachID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// staffName returns the name of the admin or coach with the given ID
+func staffName(ctx context.Context, database db.Database, userID primitive.ObjectID, role string) (string, error) {
+	if role == "coach" {
+		coach, err := database.GetCoachByID(ctx, userID)
+		if err != nil {
+			return "", err
+		}
+		return coach.Name, nil
+	}
+	admin, err := database.GetAdminByID(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	return admin.Name, nil
+}
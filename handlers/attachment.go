@@ -0,0 +1,285 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"cricketApp/db"
+	"cricketApp/models"
+	"cricketApp/storage"
+	"cricketApp/virusscan"
+)
+
+const (
+	// MaxAttachmentSize is the largest file accepted as an attachment (10 MB)
+	MaxAttachmentSize = 10 << 20
+
+	// attachmentURLTTL is how long a signed download URL stays valid
+	attachmentURLTTL = 15 * time.Minute
+)
+
+// allowedAttachmentTypes lists the MIME types accepted for attachments, detected from the file content
+var allowedAttachmentTypes = map[string]bool{
+	"application/pdf": true,
+	"image/jpeg":      true,
+	"image/png":       true,
+	"image/gif":       true,
+	"image/webp":      true,
+}
+
+type AttachmentHandler struct {
+	db      db.Database
+	store   storage.BlobStore
+	scanner virusscan.Scanner
+	signer  *storage.URLSigner
+}
+
+func NewAttachmentHandler(db db.Database, store storage.BlobStore, scanner virusscan.Scanner, secret []byte) *AttachmentHandler {
+	return &AttachmentHandler{
+		db:      db,
+		store:   store,
+		scanner: scanner,
+		signer:  storage.NewURLSigner(secret, attachmentURLTTL),
+	}
+}
+
+// UploadAttachment accepts a multipart file upload ("file" field) and stores it in the blob store
+func (h *AttachmentHandler) UploadAttachment(w http.ResponseWriter, r *http.Request) {
+	uploaderID, err := subjectIDFromClaims(r)
+	if err != nil {
+		http.Error(w, "Invalid token claims", http.StatusUnauthorized)
+		return
+	}
+
+	attachment, status, err := saveUpload(r, w, h.store, h.scanner, "attachments", "file", MaxAttachmentSize, allowedAttachmentTypes)
+	if err != nil {
+		http.Error(w, err.Error(), status)
+		return
+	}
+	attachment.UploadedBy = uploaderID.Hex()
+
+	if err := h.db.CreateAttachment(r.Context(), attachment); err != nil {
+		// Don't leave an orphaned blob behind
+		if delErr := h.store.Delete(r.Context(), attachment.Key); delErr != nil {
+			log.Printf("Error deleting blob %s after failed insert: %v", attachment.Key, delErr)
+		}
+		http.Error(w, "Error saving attachment", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(attachment)
+}
+
+// GetAttachmentURL issues a time-limited download URL for any attachment (admin only)
+func (h *AttachmentHandler) GetAttachmentURL(w http.ResponseWriter, r *http.Request) {
+	attachmentID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "attachmentId"))
+	if err != nil {
+		http.Error(w, "Invalid attachment ID", http.StatusBadRequest)
+		return
+	}
+
+	h.writeSignedURL(w, r, attachmentID)
+}
+
+// GetAnnouncementAttachmentURL issues a time-limited download URL for an attachment
+// of an announcement visible to the logged-in cricketer
+func (h *AttachmentHandler) GetAnnouncementAttachmentURL(w http.ResponseWriter, r *http.Request) {
+	cricketerID, err := subjectIDFromClaims(r)
+	if err != nil {
+		http.Error(w, "Invalid token claims", http.StatusUnauthorized)
+		return
+	}
+
+	announcementID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid announcement ID", http.StatusBadRequest)
+		return
+	}
+	attachmentID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "attachmentId"))
+	if err != nil {
+		http.Error(w, "Invalid attachment ID", http.StatusBadRequest)
+		return
+	}
+
+	cricketer, err := h.db.GetCricketerByID(r.Context(), cricketerID)
+	if err != nil {
+		http.Error(w, "Error fetching cricketer", http.StatusInternalServerError)
+		return
+	}
+
+	announcement, err := h.db.GetAnnouncementByID(r.Context(), announcementID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "Announcement not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Error fetching announcement", http.StatusInternalServerError)
+		}
+		return
+	}
+	// Only the announcement's audience may download its attachments
+	if !announcementVisibleTo(announcement, cricketer) {
+		http.Error(w, "Announcement not found", http.StatusNotFound)
+		return
+	}
+
+	attached := false
+	for _, id := range announcement.AttachmentIDs {
+		if id == attachmentID {
+			attached = true
+			break
+		}
+	}
+	if !attached {
+		http.Error(w, "Attachment not found", http.StatusNotFound)
+		return
+	}
+
+	h.writeSignedURL(w, r, attachmentID)
+}
+
+// DownloadAttachment streams an attachment to anyone holding a valid signed URL
+func (h *AttachmentHandler) DownloadAttachment(w http.ResponseWriter, r *http.Request) {
+	if err := h.signer.Verify(r.URL.Path, r.URL.Query()); err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	attachmentID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "attachmentId"))
+	if err != nil {
+		http.Error(w, "Invalid attachment ID", http.StatusBadRequest)
+		return
+	}
+
+	attachment, err := h.db.GetAttachmentByID(r.Context(), attachmentID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "Attachment not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Error fetching attachment", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	serveBlob(w, r, h.store, attachment, "attachment")
+}
+
+// writeSignedURL responds with a signed download URL for the attachment
+func (h *AttachmentHandler) writeSignedURL(w http.ResponseWriter, r *http.Request, attachmentID primitive.ObjectID) {
+	attachment, err := h.db.GetAttachmentByID(r.Context(), attachmentID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "Attachment not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Error fetching attachment", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	url, expiresAt := h.signer.Sign("/api/attachments/" + attachment.ID.Hex() + "/download")
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"attachment": attachment,
+		"url":        url,
+		"expiresAt":  expiresAt,
+	})
+}
+
+// saveUpload reads a multipart file from the request, validates its size and sniffed
+// MIME type, runs it through the virus scanner and writes it to the blob store under prefix.
+// It returns the attachment metadata (not yet persisted) or an error with the HTTP status to respond with.
+func saveUpload(r *http.Request, w http.ResponseWriter, store storage.BlobStore, scanner virusscan.Scanner, prefix string, field string, maxSize int64, allowedTypes map[string]bool) (*models.Attachment, int, error) {
+	// Allow some headroom for the multipart envelope
+	r.Body = http.MaxBytesReader(w, r.Body, maxSize+1<<20)
+	if err := r.ParseMultipartForm(1 << 20); err != nil {
+		return nil, http.StatusRequestEntityTooLarge, fmt.Errorf("Invalid upload or file larger than %d bytes", maxSize)
+	}
+
+	file, header, err := r.FormFile(field)
+	if err != nil {
+		return nil, http.StatusBadRequest, fmt.Errorf("Missing %q file field", field)
+	}
+	defer file.Close()
+
+	if header.Size > maxSize {
+		return nil, http.StatusRequestEntityTooLarge, fmt.Errorf("File larger than %d bytes", maxSize)
+	}
+
+	// Detect the type from the content rather than trusting the client
+	sniff := make([]byte, 512)
+	n, err := io.ReadFull(file, sniff)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return nil, http.StatusBadRequest, fmt.Errorf("Error reading upload")
+	}
+	contentType := http.DetectContentType(sniff[:n])
+	if !allowedTypes[contentType] {
+		return nil, http.StatusUnsupportedMediaType, fmt.Errorf("File type %s is not allowed", contentType)
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("Error reading upload")
+	}
+
+	scan, err := scanner.Scan(r.Context(), file)
+	if err != nil {
+		log.Printf("Error scanning upload %q: %v", header.Filename, err)
+		return nil, http.StatusServiceUnavailable, fmt.Errorf("Unable to scan file, try again later")
+	}
+	if !scan.Clean {
+		log.Printf("Rejected upload %q: %s detected", header.Filename, scan.Signature)
+		return nil, http.StatusUnprocessableEntity, fmt.Errorf("File rejected by virus scan")
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("Error reading upload")
+	}
+
+	attachment := &models.Attachment{
+		ID:          primitive.NewObjectID(),
+		FileName:    filepath.Base(header.Filename),
+		ContentType: contentType,
+		Size:        header.Size,
+		Scan:        &scan,
+	}
+	attachment.Key = prefix + "/" + attachment.ID.Hex()
+
+	if err := store.Put(r.Context(), attachment.Key, file, header.Size, contentType); err != nil {
+		log.Printf("Error storing blob %s: %v", attachment.Key, err)
+		return nil, http.StatusInternalServerError, fmt.Errorf("Error storing file")
+	}
+
+	return attachment, http.StatusOK, nil
+}
+
+// serveBlob streams a stored blob with the attachment's content type and file name
+func serveBlob(w http.ResponseWriter, r *http.Request, store storage.BlobStore, attachment *models.Attachment, disposition string) {
+	body, err := store.Get(r.Context(), attachment.Key)
+	if err != nil {
+		if err == storage.ErrNotFound {
+			http.Error(w, "File not found", http.StatusNotFound)
+		} else {
+			log.Printf("Error reading blob %s: %v", attachment.Key, err)
+			http.Error(w, "Error reading file", http.StatusInternalServerError)
+		}
+		return
+	}
+	defer body.Close()
+
+	w.Header().Set("Content-Type", attachment.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(attachment.Size, 10))
+	w.Header().Set("Content-Disposition", fmt.Sprintf("%s; filename=%q", disposition, attachment.FileName))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	if _, err := io.Copy(w, body); err != nil {
+		log.Printf("Error streaming blob %s: %v", attachment.Key, err)
+	}
+}
@@ -0,0 +1,190 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"cricketApp/db"
+	"cricketApp/models"
+)
+
+// MarkAttendance records attendance for a session. Coaches can only mark sessions they run or
+// that belong to one of their batches; admins can mark any session. When the session is for a batch,
+// every cricketer marked must be in that batch. Cricketers who are injured or in rehab can't be
+// marked as attending until they are cleared.
+func (h *SessionHandler) MarkAttendance(w http.ResponseWriter, r *http.Request) {
+	session, ok := staffSessionFromURL(w, r, h.db)
+	if !ok {
+		return
+	}
+
+	var req models.MarkAttendanceRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	markedBy, err := subjectIDFromClaims(r)
+	if err != nil {
+		http.Error(w, "Invalid token claims", http.StatusUnauthorized)
+		return
+	}
+
+	now := time.Now()
+	seen := make(map[primitive.ObjectID]bool, len(req.Records))
+	records := make([]models.Attendance, 0, len(req.Records))
+	for i, mark := range req.Records {
+		field := fmt.Sprintf("records[%d].cricketerId", i)
+		cricketerID, _ := primitive.ObjectIDFromHex(mark.CricketerID)
+		if seen[cricketerID] {
+			writeFieldError(w, field, "unique", "cricketer is marked more than once")
+			return
+		}
+		seen[cricketerID] = true
+
+		cricketer, err := h.db.GetCricketerByID(r.Context(), cricketerID)
+		if err != nil {
+			if err == mongo.ErrNoDocuments {
+				writeFieldError(w, field, "exists", "cricketer not found")
+			} else {
+				http.Error(w, "Error fetching cricketer", http.StatusInternalServerError)
+			}
+			return
+		}
+		if session.BatchID != nil && (cricketer.BatchID == nil || *cricketer.BatchID != *session.BatchID) {
+			writeFieldError(w, field, "batch", "cricketer is not in the session's batch")
+			return
+		}
+
+		records = append(records, models.Attendance{
+			SessionID:    session.ID,
+			CricketerID:  cricketerID,
+			SessionDate:  session.StartTime,
+			Status:       mark.Status,
+			Note:         mark.Note,
+			MarkedBy:     markedBy.Hex(),
+			MarkedByRole: roleFromClaims(r),
+			MarkedAt:     now,
+		})
+	}
+
+	if !attendanceClearOfInjuries(w, r, h.db, records) {
+		return
+	}
+
+	if err := h.db.SaveAttendance(r.Context(), records); err != nil {
+		http.Error(w, "Error saving attendance", http.StatusInternalServerError)
+		return
+	}
+
+	attendance, err := h.db.GetAttendanceForSession(r.Context(), session.ID)
+	if err != nil {
+		http.Error(w, "Error fetching attendance", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":    "Attendance saved successfully",
+		"attendance": attendance,
+	})
+}
+
+// attendanceClearOfInjuries rejects attendance marking an injured cricketer as present or late
+func attendanceClearOfInjuries(w http.ResponseWriter, r *http.Request, database db.Database, records []models.Attendance) bool {
+	ids := make([]primitive.ObjectID, len(records))
+	for i, record := range records {
+		ids[i] = record.CricketerID
+	}
+	injuries, err := activeInjuries(r.Context(), database, ids)
+	if err != nil {
+		http.Error(w, "Error fetching injuries", http.StatusInternalServerError)
+		return false
+	}
+
+	for i, record := range records {
+		attending := record.Status == models.AttendancePresent || record.Status == models.AttendanceLate
+		if injury, ok := injuries[record.CricketerID]; ok && attending && injury.BlocksTraining() {
+			writeFieldError(w, fmt.Sprintf("records[%d].status", i), "injured", "cricketer is "+injury.Status+" and not cleared to train")
+			return false
+		}
+	}
+	return true
+}
+
+// GetSessionAttendance lists the attendance recorded for a session
+func (h *SessionHandler) GetSessionAttendance(w http.ResponseWriter, r *http.Request) {
+	session, ok := staffSessionFromURL(w, r, h.db)
+	if !ok {
+		return
+	}
+
+	attendance, err := h.db.GetAttendanceForSession(r.Context(), session.ID)
+	if err != nil {
+		http.Error(w, "Error fetching attendance", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(attendance)
+}
+
+// staffSessionFromURL loads the session named in the URL. Coaches only get sessions they run or
+// that belong to one of their batches; admins get any session.
+func staffSessionFromURL(w http.ResponseWriter, r *http.Request, database db.Database) (*models.Session, bool) {
+	sessionID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid session ID", http.StatusBadRequest)
+		return nil, false
+	}
+	return staffSession(w, r, database, sessionID)
+}
+
+// staffSession loads a session for staff. Coaches only get sessions they run or that belong to
+// one of their batches; admins get any session.
+func staffSession(w http.ResponseWriter, r *http.Request, database db.Database, sessionID primitive.ObjectID) (*models.Session, bool) {
+	session, err := database.GetSessionByID(r.Context(), sessionID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "Session not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Error fetching session", http.StatusInternalServerError)
+		}
+		return nil, false
+	}
+
+	if roleFromClaims(r) != "coach" {
+		return session, true
+	}
+
+	coachID, err := subjectIDFromClaims(r)
+	if err != nil {
+		http.Error(w, "Invalid token claims", http.StatusUnauthorized)
+		return nil, false
+	}
+	if session.CoachID == coachID {
+		return session, true
+	}
+	if session.BatchID != nil {
+		batch, err := database.GetBatchByID(r.Context(), *session.BatchID)
+		if err != nil && err != mongo.ErrNoDocuments {
+			http.Error(w, "Error fetching batch", http.StatusInternalServerError)
+			return nil, false
+		}
+		if batch != nil {
+			for _, id := range batch.CoachIDs {
+				if id == coachID {
+					return session, true
+				}
+			}
+		}
+	}
+
+	http.Error(w, "Session not found", http.StatusNotFound)
+	return nil, false
+}
@@ -0,0 +1,249 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"cricketApp/agecategory"
+	"cricketApp/db"
+	"cricketApp/models"
+)
+
+type BatchHandler struct {
+	db db.Database
+}
+
+func NewBatchHandler(db db.Database) *BatchHandler {
+	return &BatchHandler{db: db}
+}
+
+// CreateBatch creates a new batch (admin only)
+func (h *BatchHandler) CreateBatch(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateBatchRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	if !validBatchAgeCategory(req.AgeCategory) {
+		writeFieldError(w, "ageCategory", "oneof", "ageCategory must be one of: "+strings.Join(batchAgeCategoryNames(), ", "))
+		return
+	}
+
+	coachIDs, err := parseObjectIDs(req.CoachIDs)
+	if err != nil {
+		http.Error(w, "Invalid coach ID", http.StatusBadRequest)
+		return
+	}
+
+	batch := &models.Batch{
+		Name:        req.Name,
+		Description: req.Description,
+		CoachIDs:    coachIDs,
+		AgeCategory: req.AgeCategory,
+		MonthlyFee:  req.MonthlyFee,
+	}
+
+	if err := h.db.CreateBatch(r.Context(), batch); err != nil {
+		http.Error(w, "Failed to create batch", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Batch created successfully",
+		"batch":   batch,
+	})
+}
+
+// GetAllBatches retrieves all batches (admin only)
+func (h *BatchHandler) GetAllBatches(w http.ResponseWriter, r *http.Request) {
+	batches, err := h.db.GetAllBatches(r.Context())
+	if err != nil {
+		http.Error(w, "Error fetching batches", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(batches)
+}
+
+// GetCoachBatches retrieves the batches the logged-in coach is assigned to
+func (h *BatchHandler) GetCoachBatches(w http.ResponseWriter, r *http.Request) {
+	coachID, err := subjectIDFromClaims(r)
+	if err != nil {
+		http.Error(w, "Invalid token claims", http.StatusUnauthorized)
+		return
+	}
+
+	batches, err := h.db.GetBatchesByCoach(r.Context(), coachID)
+	if err != nil {
+		http.Error(w, "Error fetching batches", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(batches)
+}
+
+// UpdateBatch updates an existing batch (admin only)
+func (h *BatchHandler) UpdateBatch(w http.ResponseWriter, r *http.Request) {
+	objID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid batch ID", http.StatusBadRequest)
+		return
+	}
+
+	batch, err := h.db.GetBatchByID(r.Context(), objID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "Batch not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Error fetching batch", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	var updateData models.UpdateBatchRequest
+	if !decodeRequest(w, r, &updateData) {
+		return
+	}
+
+	if updateData.Name != nil {
+		batch.Name = *updateData.Name
+	}
+	if updateData.Description != nil {
+		batch.Description = *updateData.Description
+	}
+	if updateData.CoachIDs != nil {
+		coachIDs, err := parseObjectIDs(*updateData.CoachIDs)
+		if err != nil {
+			http.Error(w, "Invalid coach ID", http.StatusBadRequest)
+			return
+		}
+		batch.CoachIDs = coachIDs
+	}
+	if updateData.AgeCategory != nil {
+		if !validBatchAgeCategory(*updateData.AgeCategory) {
+			writeFieldError(w, "ageCategory", "oneof", "ageCategory must be one of: "+strings.Join(batchAgeCategoryNames(), ", "))
+			return
+		}
+		batch.AgeCategory = *updateData.AgeCategory
+	}
+	if updateData.MonthlyFee != nil {
+		batch.MonthlyFee = *updateData.MonthlyFee
+	}
+
+	if err := h.db.UpdateBatch(r.Context(), objID, batch); err != nil {
+		http.Error(w, "Error updating batch", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Batch updated successfully",
+		"batch":   batch,
+	})
+}
+
+// AssignCricketerBatch moves a cricketer into a batch, or out of any batch when batchId is empty (admin only)
+func (h *BatchHandler) AssignCricketerBatch(w http.ResponseWriter, r *http.Request) {
+	cricketerID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid cricketer ID", http.StatusBadRequest)
+		return
+	}
+
+	var request struct {
+		BatchID string `json:"batchId" binding:"omitempty,objectid"`
+	}
+	if !decodeRequest(w, r, &request) {
+		return
+	}
+
+	var batchID *primitive.ObjectID
+	if request.BatchID != "" {
+		id, err := primitive.ObjectIDFromHex(request.BatchID)
+		if err != nil {
+			http.Error(w, "Invalid batch ID", http.StatusBadRequest)
+			return
+		}
+		batch, err := h.db.GetBatchByID(r.Context(), id)
+		if err != nil {
+			if err == mongo.ErrNoDocuments {
+				http.Error(w, "Batch not found", http.StatusNotFound)
+			} else {
+				http.Error(w, "Error fetching batch", http.StatusInternalServerError)
+			}
+			return
+		}
+
+		// Age-restricted batches only take players young enough this season
+		if batch.AgeCategory != "" {
+			cricketer, err := h.db.GetCricketerByID(r.Context(), cricketerID)
+			if err != nil {
+				if err == mongo.ErrNoDocuments {
+					http.Error(w, "Cricketer not found", http.StatusNotFound)
+				} else {
+					http.Error(w, "Error fetching cricketer", http.StatusInternalServerError)
+				}
+				return
+			}
+			season, err := seasonAt(r.Context(), h.db, time.Now())
+			if err != nil {
+				http.Error(w, "Error fetching season", http.StatusInternalServerError)
+				return
+			}
+			if err := checkAgeEligibility(batch.AgeCategory, cricketer.DateOfBirth, season); err != nil {
+				http.Error(w, "Cricketer is not eligible for this "+batch.AgeCategory+" batch: "+err.Error(), http.StatusConflict)
+				return
+			}
+		}
+		batchID = &id
+	}
+
+	if err := h.db.UpdateCricketerBatch(r.Context(), cricketerID, batchID); err != nil {
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "Cricketer not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Error updating cricketer batch", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": "Cricketer batch updated successfully"})
+}
+
+// validBatchAgeCategory reports whether category can restrict a batch; empty means no restriction
+func validBatchAgeCategory(category string) bool {
+	_, ok := agecategory.Lookup(category)
+	return ok || category == ""
+}
+
+// batchAgeCategoryNames lists the age categories a batch can be restricted to
+func batchAgeCategoryNames() []string {
+	names := make([]string, len(agecategory.Categories))
+	for i, category := range agecategory.Categories {
+		names[i] = category.Name
+	}
+	return names
+}
+
+// parseObjectIDs converts a list of hex strings into ObjectIDs
+func parseObjectIDs(hexIDs []string) ([]primitive.ObjectID, error) {
+	ids := make([]primitive.ObjectID, 0, len(hexIDs))
+	for _, hexID := range hexIDs {
+		id, err := primitive.ObjectIDFromHex(hexID)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/jwtauth/v5"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var errInvalidSubject = errors.New("invalid token subject (sub)")
+
+// subjectIDFromClaims extracts the authenticated user's ObjectID from the JWT 'sub' claim
+func subjectIDFromClaims(r *http.Request) (primitive.ObjectID, error) {
+	_, claims, err := jwtauth.FromContext(r.Context())
+	if err != nil {
+		return primitive.NilObjectID, err
+	}
+	subject, ok := claims["sub"].(string)
+	if !ok {
+		return primitive.NilObjectID, errInvalidSubject
+	}
+	id, err := primitive.ObjectIDFromHex(subject)
+	if err != nil {
+		return primitive.NilObjectID, errInvalidSubject
+	}
+	return id, nil
+}
+
+// roleFromClaims extracts the authenticated user's role from the JWT 'role' claim
+func roleFromClaims(r *http.Request) string {
+	_, claims, err := jwtauth.FromContext(r.Context())
+	if err != nil {
+		return ""
+	}
+	role, _ := claims["role"].(string)
+	return role
+}
@@ -8,6 +8,7 @@ import (
 	"cricketApp/db"
 	"cricketApp/middleware/authmiddleware"
 	"cricketApp/models"
+	"cricketApp/storage"
 
 	"github.com/go-chi/jwtauth/v5"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -16,21 +17,21 @@ import (
 )
 
 type CoachHandler struct {
-	db db.Database
+	db          db.Database
+	photoSigner *storage.URLSigner
 }
 
-func NewCoachHandler(db db.Database) *CoachHandler {
-	return &CoachHandler{db: db}
+func NewCoachHandler(db db.Database, photoSecret []byte) *CoachHandler {
+	return &CoachHandler{db: db, photoSigner: newPhotoURLSigner(photoSecret)}
 }
 
 func (h *CoachHandler) HandleCoachLogin(w http.ResponseWriter, r *http.Request) {
 	var loginRequest struct {
-		Mobile   string `json:"mobile"`
-		Password string `json:"password"`
+		Mobile   string `json:"mobile" binding:"required"`
+		Password string `json:"password" binding:"required"`
 	}
 
-	if err := json.NewDecoder(r.Body).Decode(&loginRequest); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+	if !decodeRequest(w, r, &loginRequest) {
 		return
 	}
 
@@ -102,12 +103,19 @@ func (h *CoachHandler) GetCoachProfile(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	photo, err := profilePhotoURL(r.Context(), h.db, h.photoSigner, coach.PhotoID)
+	if err != nil {
+		http.Error(w, "Error fetching photo", http.StatusInternalServerError)
+		return
+	}
+
 	// Return profile without sensitive information
 	profile := map[string]interface{}{
 		"id":        coach.ID.Hex(),
 		"name":      coach.Name,
 		"mobile":    coach.Mobile,
 		"createdAt": coach.CreatedAt,
+		"photo":     photo,
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -116,8 +124,7 @@ func (h *CoachHandler) GetCoachProfile(w http.ResponseWriter, r *http.Request) {
 
 func (h *CoachHandler) CreateCoach(w http.ResponseWriter, r *http.Request) {
 	var coach models.Coach
-	if err := json.NewDecoder(r.Body).Decode(&coach); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+	if !decodeRequest(w, r, &coach) {
 		return
 	}
 
@@ -158,14 +165,29 @@ func (h *CoachHandler) GetAllCoaches(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	photoIDs := make([]*primitive.ObjectID, len(coaches))
+	for i := range coaches {
+		photoIDs[i] = coaches[i].PhotoID
+	}
+	photos, err := profilePhotoURLs(r.Context(), h.db, h.photoSigner, photoIDs)
+	if err != nil {
+		http.Error(w, "Error fetching photos", http.StatusInternalServerError)
+		return
+	}
+
 	// Map to response model to avoid exposing sensitive data
 	responseCoaches := make([]map[string]interface{}, len(coaches))
 	for i, c := range coaches {
+		var photo *models.PhotoURLs
+		if c.PhotoID != nil {
+			photo = photos[*c.PhotoID]
+		}
 		responseCoaches[i] = map[string]interface{}{
 			"id":        c.ID.Hex(),
 			"name":      c.Name,
 			"mobile":    c.Mobile,
 			"createdAt": c.CreatedAt,
+			"photo":     photo,
 		}
 	}
 
@@ -181,8 +203,7 @@ func (h *CoachHandler) UpdateCoach(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var updateData models.UpdateCoachRequest
-	if err := json.NewDecoder(r.Body).Decode(&updateData); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+	if !decodeRequest(w, r, &updateData) {
 		return
 	}
 
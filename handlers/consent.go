@@ -0,0 +1,395 @@
+package handlers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"cricketApp/agecategory"
+	"cricketApp/db"
+	"cricketApp/middleware/ratelimit"
+	"cricketApp/models"
+)
+
+// Cricketers this old can sign consent documents themselves
+const adultAge = 18
+
+// ConsentHandler manages consent documents and reports on signatures
+type ConsentHandler struct {
+	db db.Database
+}
+
+func NewConsentHandler(db db.Database) *ConsentHandler {
+	return &ConsentHandler{db: db}
+}
+
+// PublishConsentDocument publishes a new version of a consent document (admin only). Everyone who
+// signed an earlier version has to sign again. Publishing unchanged text is rejected.
+func (h *ConsentHandler) PublishConsentDocument(w http.ResponseWriter, r *http.Request) {
+	admin, ok := requireAdminPermission(w, r, h.db, "")
+	if !ok {
+		return
+	}
+
+	var req models.PublishConsentDocumentRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	document := &models.ConsentDocument{
+		Key:         strings.TrimSpace(req.Key),
+		Title:       strings.TrimSpace(req.Title),
+		Body:        req.Body,
+		Required:    req.Required,
+		PublishedBy: admin.ID,
+	}
+	document.Hash = consentDocumentHash(document.Title, document.Body)
+
+	current, err := h.db.GetCurrentConsentDocument(r.Context(), document.Key)
+	if err != nil && err != mongo.ErrNoDocuments {
+		http.Error(w, "Error fetching consent document", http.StatusInternalServerError)
+		return
+	}
+	if current != nil && current.Hash == document.Hash && current.Required == document.Required {
+		http.Error(w, "Consent document is unchanged", http.StatusConflict)
+		return
+	}
+
+	if err := h.db.PublishConsentDocument(r.Context(), document); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			http.Error(w, "Another version was published at the same time; try again", http.StatusConflict)
+		} else {
+			http.Error(w, "Error publishing consent document", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	entry := &models.AuditEntry{
+		Action:     models.AuditPublishConsent,
+		TargetType: "consentDocument",
+		TargetID:   document.ID.Hex(),
+		Details:    map[string]string{"key": document.Key, "hash": document.Hash},
+	}
+	if err := recordAudit(r, h.db, admin, entry); err != nil {
+		log.Printf("Error recording consent document publish by %s: %v", admin.ID, err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":  "Consent document published successfully",
+		"document": document,
+	})
+}
+
+// GetConsentDocuments lists the current version of every consent document, or every version of
+// one document when key is given (admin only)
+func (h *ConsentHandler) GetConsentDocuments(w http.ResponseWriter, r *http.Request) {
+	var documents []models.ConsentDocument
+	var err error
+	if key := r.URL.Query().Get("key"); key != "" {
+		documents, err = h.db.GetConsentDocumentVersions(r.Context(), key)
+	} else {
+		documents, err = h.db.GetCurrentConsentDocuments(r.Context())
+	}
+	if err != nil {
+		http.Error(w, "Error fetching consent documents", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(documents)
+}
+
+// GetCricketerConsents shows a cricketer's consent status for each document and every signature
+// recorded for them (admin only)
+func (h *ConsentHandler) GetCricketerConsents(w http.ResponseWriter, r *http.Request) {
+	cricketerID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid cricketer ID", http.StatusBadRequest)
+		return
+	}
+
+	statuses, err := consentStatusesFor(r.Context(), h.db, []primitive.ObjectID{cricketerID})
+	if err != nil {
+		http.Error(w, "Error fetching consents", http.StatusInternalServerError)
+		return
+	}
+	signatures, err := h.db.GetConsentSignatures(r.Context(), []primitive.ObjectID{cricketerID})
+	if err != nil {
+		http.Error(w, "Error fetching signatures", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"consents":   statuses[cricketerID],
+		"signatures": signatures,
+	})
+}
+
+// GetOutstandingConsents lists active cricketers who haven't signed the current version of a
+// required consent document (admin only)
+func (h *ConsentHandler) GetOutstandingConsents(w http.ResponseWriter, r *http.Request) {
+	cricketers, err := h.db.GetAllCricketers(r.Context())
+	if err != nil {
+		http.Error(w, "Error fetching cricketers", http.StatusInternalServerError)
+		return
+	}
+
+	active := make([]models.Cricketer, 0, len(cricketers))
+	ids := make([]primitive.ObjectID, 0, len(cricketers))
+	for _, cricketer := range cricketers {
+		if !cricketer.InactiveCricketer {
+			active = append(active, cricketer)
+			ids = append(ids, cricketer.ID)
+		}
+	}
+
+	statuses, err := consentStatusesFor(r.Context(), h.db, ids)
+	if err != nil {
+		http.Error(w, "Error fetching consents", http.StatusInternalServerError)
+		return
+	}
+
+	outstanding := []models.OutstandingConsent{}
+	for _, cricketer := range active {
+		for _, status := range statuses[cricketer.ID] {
+			if !status.Document.Required || status.Status == models.ConsentSigned {
+				continue
+			}
+			outstanding = append(outstanding, models.OutstandingConsent{
+				CricketerID:   cricketer.ID,
+				CricketerName: cricketer.Name,
+				DocumentKey:   status.Document.Key,
+				Title:         status.Document.Title,
+				Version:       status.Document.Version,
+				Status:        status.Status,
+			})
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(outstanding)
+}
+
+// GetChildConsents shows the guardian each consent document and whether it is signed for the child
+func (h *GuardianHandler) GetChildConsents(w http.ResponseWriter, r *http.Request) {
+	guardian, ok := h.guardianFromClaims(w, r)
+	if !ok {
+		return
+	}
+	cricketer, ok := h.childFromURL(w, r, guardian)
+	if !ok {
+		return
+	}
+	writeConsentStatuses(w, r, h.db, cricketer.ID)
+}
+
+// SignChildConsent signs the current version of a consent document on a child's behalf
+func (h *GuardianHandler) SignChildConsent(w http.ResponseWriter, r *http.Request) {
+	guardian, ok := h.guardianFromClaims(w, r)
+	if !ok {
+		return
+	}
+	cricketer, ok := h.childFromURL(w, r, guardian)
+	if !ok {
+		return
+	}
+	signConsent(w, r, h.db, cricketer, guardian.ID.Hex(), "guardian")
+}
+
+// GetConsents shows the logged-in cricketer each consent document and whether it is signed
+func (h *CricketerHandler) GetConsents(w http.ResponseWriter, r *http.Request) {
+	cricketerID, err := subjectIDFromClaims(r)
+	if err != nil {
+		http.Error(w, "Invalid token claims", http.StatusUnauthorized)
+		return
+	}
+	writeConsentStatuses(w, r, h.db, cricketerID)
+}
+
+// SignConsent lets an adult cricketer sign a consent document themselves. Minors' documents are
+// signed by a guardian.
+func (h *CricketerHandler) SignConsent(w http.ResponseWriter, r *http.Request) {
+	cricketerID, err := subjectIDFromClaims(r)
+	if err != nil {
+		http.Error(w, "Invalid token claims", http.StatusUnauthorized)
+		return
+	}
+	cricketer, err := h.db.GetCricketerByID(r.Context(), cricketerID)
+	if err != nil {
+		http.Error(w, "Error fetching cricketer", http.StatusInternalServerError)
+		return
+	}
+	if cricketer.DateOfBirth == nil || agecategory.AgeOn(*cricketer.DateOfBirth, time.Now()) < adultAge {
+		http.Error(w, "Consent documents for cricketers under 18 must be signed by a guardian", http.StatusForbidden)
+		return
+	}
+	signConsent(w, r, h.db, cricketer, cricketer.ID.Hex(), "cricketer")
+}
+
+// signConsent records the caller's signature of the consent document named by {documentId} for a cricketer.
+// Only the current version can be signed, and documentHash must match it, so the signature is bound
+// to the exact text the signer was shown.
+func signConsent(w http.ResponseWriter, r *http.Request, database db.Database, cricketer *models.Cricketer, signedBy string, role string) {
+	documentID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "documentId"))
+	if err != nil {
+		http.Error(w, "Invalid document ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.SignConsentRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	if !req.Agree {
+		writeFieldError(w, "agree", "required", "agree must be true to sign")
+		return
+	}
+	typedName := strings.Join(strings.Fields(req.TypedName), " ")
+	if typedName == "" {
+		writeFieldError(w, "typedName", "required", "typedName is required")
+		return
+	}
+
+	document, err := database.GetConsentDocumentByID(r.Context(), documentID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "Consent document not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Error fetching consent document", http.StatusInternalServerError)
+		}
+		return
+	}
+	if !document.Current {
+		http.Error(w, "A newer version of this document has been published; review and sign that instead", http.StatusConflict)
+		return
+	}
+	if !strings.EqualFold(req.DocumentHash, document.Hash) {
+		writeFieldError(w, "documentHash", "match", "documentHash does not match the current version of the document")
+		return
+	}
+
+	statuses, err := consentStatusesFor(r.Context(), database, []primitive.ObjectID{cricketer.ID})
+	if err != nil {
+		http.Error(w, "Error fetching consents", http.StatusInternalServerError)
+		return
+	}
+	for _, status := range statuses[cricketer.ID] {
+		if status.Document.ID == document.ID && status.Status == models.ConsentSigned {
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"message":   "Document already signed",
+				"signature": status.Signature,
+			})
+			return
+		}
+	}
+
+	signature := &models.ConsentSignature{
+		DocumentID:   document.ID,
+		DocumentKey:  document.Key,
+		Version:      document.Version,
+		DocumentHash: document.Hash,
+		CricketerID:  cricketer.ID,
+		SignedBy:     signedBy,
+		SignerRole:   role,
+		TypedName:    typedName,
+		IP:           ratelimit.ClientIP(r),
+		UserAgent:    r.UserAgent(),
+		SignedAt:     time.Now(),
+	}
+	if err := database.CreateConsentSignature(r.Context(), signature); err != nil {
+		http.Error(w, "Error recording signature", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":   "Document signed successfully",
+		"signature": signature,
+	})
+}
+
+func writeConsentStatuses(w http.ResponseWriter, r *http.Request, database db.Database, cricketerID primitive.ObjectID) {
+	statuses, err := consentStatusesFor(r.Context(), database, []primitive.ObjectID{cricketerID})
+	if err != nil {
+		http.Error(w, "Error fetching consents", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(statuses[cricketerID])
+}
+
+// consentStatusesFor works out each cricketer's status for the current version of every consent document.
+// A signature counts only for the version and text it was made against.
+func consentStatusesFor(ctx context.Context, database db.Database, cricketerIDs []primitive.ObjectID) (map[primitive.ObjectID][]models.ConsentStatus, error) {
+	documents, err := database.GetCurrentConsentDocuments(ctx)
+	if err != nil {
+		return nil, err
+	}
+	signatures, err := database.GetConsentSignatures(ctx, cricketerIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	// Signatures are newest first, so the first one seen for a document is the latest
+	type signatureKey struct {
+		cricketerID primitive.ObjectID
+		documentKey string
+	}
+	latest := make(map[signatureKey]models.ConsentSignature)
+	for _, signature := range signatures {
+		key := signatureKey{signature.CricketerID, signature.DocumentKey}
+		if _, ok := latest[key]; !ok {
+			latest[key] = signature
+		}
+	}
+
+	statuses := make(map[primitive.ObjectID][]models.ConsentStatus, len(cricketerIDs))
+	for _, cricketerID := range cricketerIDs {
+		list := make([]models.ConsentStatus, 0, len(documents))
+		for _, document := range documents {
+			status := models.ConsentStatus{Document: document, Status: models.ConsentMissing}
+			if signature, ok := latest[signatureKey{cricketerID, document.Key}]; ok {
+				signature := signature
+				status.Signature = &signature
+				status.Status = models.ConsentOutdated
+				if signature.DocumentID == document.ID && signature.DocumentHash == document.Hash {
+					status.Status = models.ConsentSigned
+				}
+			}
+			list = append(list, status)
+		}
+		statuses[cricketerID] = list
+	}
+	return statuses, nil
+}
+
+// consentsComplete reports whether every required document is signed at its current version
+func consentsComplete(statuses []models.ConsentStatus) bool {
+	for _, status := range statuses {
+		if status.Document.Required && status.Status != models.ConsentSigned {
+			return false
+		}
+	}
+	return true
+}
+
+// consentDocumentHash fingerprints the text of a consent document
+func consentDocumentHash(title string, body string) string {
+	sum := sha256.Sum256([]byte(title + "\n\n" + body))
+	return hex.EncodeToString(sum[:])
+}
@@ -7,7 +7,7 @@ import (
 	"net/http"
 	"time"
 
-	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/jwtauth/v5"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -16,22 +16,25 @@ import (
 	"cricketApp/db"
 	"cricketApp/middleware/authmiddleware"
 	"cricketApp/models"
+	"cricketApp/notification"
+	"cricketApp/storage"
 )
 
 // CricketerHandler holds the database interface
 type CricketerHandler struct {
-	db db.Database
+	db          db.Database
+	notifier    notification.Notifier
+	photoSigner *storage.URLSigner
 }
 
 // NewCricketerHandler creates a new CricketerHandler
-func NewCricketerHandler(db db.Database) *CricketerHandler {
-	return &CricketerHandler{db: db}
+func NewCricketerHandler(db db.Database, notifier notification.Notifier, photoSecret []byte) *CricketerHandler {
+	return &CricketerHandler{db: db, notifier: notifier, photoSigner: newPhotoURLSigner(photoSecret)}
 }
 
 func (h *CricketerHandler) HandleCricketerSignup(w http.ResponseWriter, r *http.Request) {
 	var cricketer models.Cricketer
-	if err := json.NewDecoder(r.Body).Decode(&cricketer); err != nil {
-		http.Error(w, "Invalid request body: "+err.Error(), http.StatusBadRequest)
+	if !decodeRequest(w, r, &cricketer) {
 		return
 	}
 
@@ -82,12 +85,11 @@ func (h *CricketerHandler) HandleCricketerSignup(w http.ResponseWriter, r *http.
 
 func (h *CricketerHandler) HandleCricketerLogin(w http.ResponseWriter, r *http.Request) {
 	var loginRequest struct {
-		Mobile   string `json:"mobile"`
-		Password string `json:"password"`
+		Mobile   string `json:"mobile" binding:"required"`
+		Password string `json:"password" binding:"required"`
 	}
 
-	if err := json.NewDecoder(r.Body).Decode(&loginRequest); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+	if !decodeRequest(w, r, &loginRequest) {
 		return
 	}
 
@@ -164,6 +166,24 @@ func (h *CricketerHandler) GetCricketerProfile(w http.ResponseWriter, r *http.Re
 		return
 	}
 
+	season, err := seasonAt(r.Context(), h.db, time.Now())
+	if err != nil {
+		http.Error(w, "Error fetching season", http.StatusInternalServerError)
+		return
+	}
+
+	stats, err := careerStats(r.Context(), h.db, cricketer.ID)
+	if err != nil {
+		http.Error(w, "Error calculating career statistics", http.StatusInternalServerError)
+		return
+	}
+
+	photo, err := profilePhotoURL(r.Context(), h.db, h.photoSigner, cricketer.PhotoID)
+	if err != nil {
+		http.Error(w, "Error fetching photo", http.StatusInternalServerError)
+		return
+	}
+
 	// Return profile without sensitive information
 	profile := map[string]interface{}{
 		"id":                cricketer.ID.Hex(),
@@ -174,6 +194,11 @@ func (h *CricketerHandler) GetCricketerProfile(w http.ResponseWriter, r *http.Re
 		"joiningDate":       cricketer.JoiningDate,
 		"dueDate":           cricketer.DueDate,
 		"inactiveCricketer": cricketer.InactiveCricketer,
+		"batchId":           cricketer.BatchID,
+		"dateOfBirth":       cricketer.DateOfBirth,
+		"ageCategory":       ageCategoryAt(cricketer.DateOfBirth, season),
+		"careerStats":       stats,
+		"photo":             photo,
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -200,13 +225,12 @@ func (h *CricketerHandler) UpdateCricketerProfile(w http.ResponseWriter, r *http
 
 	// Decode update request
 	var updateData struct {
-		Name     *string `json:"name,omitempty"` // Use pointers to handle omitted fields
-		Email    *string `json:"email,omitempty"`
-		Password *string `json:"password,omitempty"`
+		Name     *string `json:"name,omitempty" binding:"omitempty,min=1,max=100"` // Use pointers to handle omitted fields
+		Email    *string `json:"email,omitempty" binding:"omitempty,email"`
+		Password *string `json:"password,omitempty" binding:"omitempty,min=6"`
 	}
 
-	if err := json.NewDecoder(r.Body).Decode(&updateData); err != nil {
-		http.Error(w, "Invalid request body: "+err.Error(), http.StatusBadRequest)
+	if !decodeRequest(w, r, &updateData) {
 		return
 	}
 
@@ -246,6 +270,12 @@ func (h *CricketerHandler) UpdateCricketerProfile(w http.ResponseWriter, r *http
 		return
 	}
 
+	photo, err := profilePhotoURL(r.Context(), h.db, h.photoSigner, updatedCricketer.PhotoID)
+	if err != nil {
+		http.Error(w, "Error fetching photo", http.StatusInternalServerError)
+		return
+	}
+
 	// Return updated profile without sensitive information
 	profile := map[string]interface{}{
 		"id":                updatedCricketer.ID.Hex(),
@@ -256,6 +286,8 @@ func (h *CricketerHandler) UpdateCricketerProfile(w http.ResponseWriter, r *http
 		"joiningDate":       updatedCricketer.JoiningDate,
 		"dueDate":           updatedCricketer.DueDate,
 		"inactiveCricketer": updatedCricketer.InactiveCricketer,
+		"batchId":           updatedCricketer.BatchID,
+		"photo":             photo,
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -270,10 +302,30 @@ func (h *CricketerHandler) GetAllCricketers(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
+	season, err := seasonAt(r.Context(), h.db, time.Now())
+	if err != nil {
+		http.Error(w, "Error fetching season", http.StatusInternalServerError)
+		return
+	}
+
+	photoIDs := make([]*primitive.ObjectID, len(cricketers))
+	for i := range cricketers {
+		photoIDs[i] = cricketers[i].PhotoID
+	}
+	photos, err := profilePhotoURLs(r.Context(), h.db, h.photoSigner, photoIDs)
+	if err != nil {
+		http.Error(w, "Error fetching photos", http.StatusInternalServerError)
+		return
+	}
+
 	// IMPORTANT: Map to a response model to avoid exposing sensitive data like passwords
 	// Define a response struct or use map[string]interface{}
 	responseProfiles := make([]map[string]interface{}, len(cricketers))
 	for i, c := range cricketers {
+		var photo *models.PhotoURLs
+		if c.PhotoID != nil {
+			photo = photos[*c.PhotoID]
+		}
 		responseProfiles[i] = map[string]interface{}{
 			"id":                c.ID.Hex(),
 			"name":              c.Name,
@@ -283,6 +335,10 @@ func (h *CricketerHandler) GetAllCricketers(w http.ResponseWriter, r *http.Reque
 			"joiningDate":       c.JoiningDate,
 			"dueDate":           c.DueDate,
 			"inactiveCricketer": c.InactiveCricketer,
+			"batchId":           c.BatchID,
+			"dateOfBirth":       c.DateOfBirth,
+			"ageCategory":       ageCategoryAt(c.DateOfBirth, season),
+			"photo":             photo,
 		}
 	}
 
@@ -303,10 +359,9 @@ func (h *CricketerHandler) UpdateCricketerJoiningDate(w http.ResponseWriter, r *
 
 	// Parse request body
 	var request struct {
-		JoiningDate time.Time `json:"joiningDate"`
+		JoiningDate time.Time `json:"joiningDate" binding:"required"`
 	}
-	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		http.Error(w, "Invalid request body: "+err.Error(), http.StatusBadRequest)
+	if !decodeRequest(w, r, &request) {
 		return
 	}
 
@@ -325,6 +380,62 @@ func (h *CricketerHandler) UpdateCricketerJoiningDate(w http.ResponseWriter, r *
 	json.NewEncoder(w).Encode(map[string]string{"message": "Joining date updated successfully"})
 }
 
+// UpdateCricketerDateOfBirth records a cricketer's date of birth, used for age categories (admin only)
+func (h *CricketerHandler) UpdateCricketerDateOfBirth(w http.ResponseWriter, r *http.Request) {
+	cricketerID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid cricketer ID", http.StatusBadRequest)
+		return
+	}
+
+	var request struct {
+		DateOfBirth time.Time `json:"dateOfBirth" binding:"required,past"`
+	}
+	if !decodeRequest(w, r, &request) {
+		return
+	}
+
+	if err := h.db.UpdateCricketerDateOfBirth(r.Context(), cricketerID, request.DateOfBirth); err != nil {
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "Cricketer not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Error updating date of birth", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": "Date of birth updated successfully"})
+}
+
+// UpdateCricketerGender records a cricketer's gender, used for fitness benchmarks (admin only)
+func (h *CricketerHandler) UpdateCricketerGender(w http.ResponseWriter, r *http.Request) {
+	cricketerID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid cricketer ID", http.StatusBadRequest)
+		return
+	}
+
+	var request struct {
+		Gender string `json:"gender" binding:"required,oneof=male female"`
+	}
+	if !decodeRequest(w, r, &request) {
+		return
+	}
+
+	if err := h.db.UpdateCricketerGender(r.Context(), cricketerID, request.Gender); err != nil {
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "Cricketer not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Error updating gender", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": "Gender updated successfully"})
+}
+
 // UpdateCricketerInactiveStatus updates whether a cricketer is inactive or not (admin only)
 func (h *CricketerHandler) UpdateCricketerInactiveStatus(w http.ResponseWriter, r *http.Request) {
 	// Parse cricketer ID from URL
@@ -341,8 +452,7 @@ func (h *CricketerHandler) UpdateCricketerInactiveStatus(w http.ResponseWriter,
 	var request struct {
 		IsInactive bool `json:"isInactive"`
 	}
-	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		http.Error(w, "Invalid request body: "+err.Error(), http.StatusBadRequest)
+	if !decodeRequest(w, r, &request) {
 		return
 	}
 
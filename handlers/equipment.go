@@ -0,0 +1,625 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"cricketApp/db"
+	"cricketApp/models"
+)
+
+// defaultEquipmentLoan is how long cricketers keep kit when no due date is given
+const defaultEquipmentLoan = 7 * 24 * time.Hour
+
+// EquipmentHandler manages the kit inventory and lending it to cricketers and sessions
+type EquipmentHandler struct {
+	db db.Database
+}
+
+func NewEquipmentHandler(db db.Database) *EquipmentHandler {
+	return &EquipmentHandler{db: db}
+}
+
+// CreateEquipmentItem adds a line of kit to the inventory with its opening stock (admin only)
+func (h *EquipmentHandler) CreateEquipmentItem(w http.ResponseWriter, r *http.Request) {
+	adminID, err := subjectIDFromClaims(r)
+	if err != nil {
+		http.Error(w, "Invalid token claims", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.CreateEquipmentItemRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	item := &models.EquipmentItem{Quantity: req.Quantity, Available: req.Quantity, Adjustments: []models.EquipmentAdjustment{}}
+	applyEquipmentDetails(item, req.EquipmentDetails)
+	if req.Quantity > 0 {
+		item.Adjustments = append(item.Adjustments, models.EquipmentAdjustment{
+			Change: req.Quantity,
+			Reason: "Opening stock",
+			By:     adminID.Hex(),
+			At:     time.Now(),
+		})
+	}
+
+	if err := h.db.CreateEquipmentItem(r.Context(), item); err != nil {
+		http.Error(w, "Error creating item", http.StatusInternalServerError)
+		return
+	}
+	item.LowStock = item.IsLowStock()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Item added to the inventory",
+		"item":    item,
+	})
+}
+
+// GetEquipmentItems lists the inventory, optionally only one category or location, or only items
+// running low. Retired items are left out unless includeRetired is set.
+func (h *EquipmentHandler) GetEquipmentItems(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := models.EquipmentFilter{
+		Category: query.Get("category"),
+		Location: query.Get("location"),
+	}
+	filter.IncludeRetired, _ = strconv.ParseBool(query.Get("includeRetired"))
+	lowStockOnly, _ := strconv.ParseBool(query.Get("lowStock"))
+
+	items, err := h.db.GetEquipmentItems(r.Context(), filter)
+	if err != nil {
+		http.Error(w, "Error fetching equipment", http.StatusInternalServerError)
+		return
+	}
+
+	listed := make([]models.EquipmentItem, 0, len(items))
+	for _, item := range items {
+		item.LowStock = item.IsLowStock()
+		if lowStockOnly && !item.LowStock {
+			continue
+		}
+		listed = append(listed, item)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(listed)
+}
+
+// GetEquipmentItem returns an inventory item with its stock adjustments
+func (h *EquipmentHandler) GetEquipmentItem(w http.ResponseWriter, r *http.Request) {
+	item, ok := h.itemFromURL(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(item)
+}
+
+// UpdateEquipmentItem edits an item's details or retires it. Stock is changed with AdjustEquipmentStock (admin only).
+func (h *EquipmentHandler) UpdateEquipmentItem(w http.ResponseWriter, r *http.Request) {
+	item, ok := h.itemFromURL(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateEquipmentItemRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	applyEquipmentDetails(item, req.EquipmentDetails)
+	item.Retired = req.Retired
+
+	if err := h.db.UpdateEquipmentItemDetails(r.Context(), item.ID, item); err != nil {
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "Item not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Error updating item", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Item updated",
+		"item":    item,
+	})
+}
+
+// AdjustEquipmentStock adds stock that was bought or found, or removes stock that was written off
+// outside an issue (admin only)
+func (h *EquipmentHandler) AdjustEquipmentStock(w http.ResponseWriter, r *http.Request) {
+	adminID, err := subjectIDFromClaims(r)
+	if err != nil {
+		http.Error(w, "Invalid token claims", http.StatusUnauthorized)
+		return
+	}
+	item, ok := h.itemFromURL(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.AdjustEquipmentStockRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	adjustment := models.EquipmentAdjustment{
+		Change: req.Change,
+		Reason: strings.TrimSpace(req.Reason),
+		By:     adminID.Hex(),
+		At:     time.Now(),
+	}
+	if err := h.db.AdjustEquipmentStock(r.Context(), item.ID, adjustment); err != nil {
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, fmt.Sprintf("Only %d in store; kit out on issue is written off when it is returned", item.Available), http.StatusConflict)
+		} else {
+			http.Error(w, "Error adjusting stock", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	item, err = h.db.GetEquipmentItemByID(r.Context(), item.ID)
+	if err != nil {
+		http.Error(w, "Error fetching item", http.StatusInternalServerError)
+		return
+	}
+	item.LowStock = item.IsLowStock()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Stock adjusted",
+		"item":    item,
+	})
+}
+
+// GetEquipmentItemHistory reports every issue of an item and what came back (admin only)
+func (h *EquipmentHandler) GetEquipmentItemHistory(w http.ResponseWriter, r *http.Request) {
+	item, ok := h.itemFromURL(w, r)
+	if !ok {
+		return
+	}
+
+	issues, err := h.db.GetEquipmentIssues(r.Context(), models.EquipmentIssueFilter{ItemID: &item.ID})
+	if err != nil {
+		http.Error(w, "Error fetching issues", http.StatusInternalServerError)
+		return
+	}
+	now := time.Now()
+	markOverdue(issues, now)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(models.EquipmentItemHistory{
+		Item:    *item,
+		Summary: equipmentSummary(issues, now),
+		Issues:  issues,
+	})
+}
+
+// IssueEquipment lends kit to a cricketer or for a session. Coaches can issue to cricketers in
+// their batches and for sessions they run or that belong to their batches.
+func (h *EquipmentHandler) IssueEquipment(w http.ResponseWriter, r *http.Request) {
+	userID, err := subjectIDFromClaims(r)
+	if err != nil {
+		http.Error(w, "Invalid token claims", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.IssueEquipmentRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	switch {
+	case req.CricketerID == "" && req.SessionID == "":
+		writeFieldError(w, "cricketerId", "required", "cricketerId or sessionId is required")
+		return
+	case req.CricketerID != "" && req.SessionID != "":
+		writeFieldError(w, "sessionId", "excluded", "give either cricketerId or sessionId, not both")
+		return
+	}
+	now := time.Now()
+	if req.DueAt != nil && !req.DueAt.After(now) {
+		writeFieldError(w, "dueAt", "future", "dueAt must be in the future")
+		return
+	}
+
+	itemID, _ := primitive.ObjectIDFromHex(req.ItemID)
+	item, err := h.db.GetEquipmentItemByID(r.Context(), itemID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			writeFieldError(w, "itemId", "exists", "item not found")
+		} else {
+			http.Error(w, "Error fetching item", http.StatusInternalServerError)
+		}
+		return
+	}
+	if item.Retired {
+		http.Error(w, item.Name+" has been retired", http.StatusConflict)
+		return
+	}
+	if item.Condition == models.EquipmentUnusable {
+		http.Error(w, item.Name+" is unusable until it is repaired", http.StatusConflict)
+		return
+	}
+
+	issue := &models.EquipmentIssue{
+		ItemID:       item.ID,
+		ItemName:     item.Name,
+		Size:         item.Size,
+		Quantity:     req.Quantity,
+		Note:         strings.TrimSpace(req.Note),
+		Status:       models.EquipmentIssued,
+		IssuedBy:     userID.Hex(),
+		IssuedByRole: roleFromClaims(r),
+		IssuedAt:     now,
+		DueAt:        now.Add(defaultEquipmentLoan),
+	}
+	if req.CricketerID != "" {
+		cricketerID, _ := primitive.ObjectIDFromHex(req.CricketerID)
+		cricketer, ok := staffCricketer(w, r, h.db, cricketerID)
+		if !ok {
+			return
+		}
+		if cricketer.InactiveCricketer {
+			http.Error(w, cricketer.Name+" is inactive", http.StatusConflict)
+			return
+		}
+		issue.CricketerID = &cricketer.ID
+		issue.CricketerName = cricketer.Name
+		issue.BatchID = cricketer.BatchID
+	} else {
+		sessionID, _ := primitive.ObjectIDFromHex(req.SessionID)
+		session, ok := staffSession(w, r, h.db, sessionID)
+		if !ok {
+			return
+		}
+		if now.After(session.EndTime) {
+			http.Error(w, "The session has already ended", http.StatusConflict)
+			return
+		}
+		issue.SessionID = &session.ID
+		issue.BatchID = session.BatchID
+		issue.DueAt = session.EndTime
+	}
+	if req.DueAt != nil {
+		issue.DueAt = *req.DueAt
+	}
+
+	if err := h.db.ReserveEquipment(r.Context(), item.ID, req.Quantity); err != nil {
+		if err == mongo.ErrNoDocuments {
+			if current, err := h.db.GetEquipmentItemByID(r.Context(), item.ID); err == nil {
+				item = current
+			}
+			http.Error(w, fmt.Sprintf("Only %d %s in store", item.Available, item.Name), http.StatusConflict)
+		} else {
+			http.Error(w, "Error issuing equipment", http.StatusInternalServerError)
+		}
+		return
+	}
+	if err := h.db.CreateEquipmentIssue(r.Context(), issue); err != nil {
+		// Put the kit back so the counts stay right
+		h.db.RestockEquipment(r.Context(), item.ID, req.Quantity, nil)
+		http.Error(w, "Error issuing equipment", http.StatusInternalServerError)
+		return
+	}
+
+	item, err = h.db.GetEquipmentItemByID(r.Context(), item.ID)
+	if err != nil {
+		http.Error(w, "Error fetching item", http.StatusInternalServerError)
+		return
+	}
+	item.LowStock = item.IsLowStock()
+
+	message := "Equipment issued"
+	if item.LowStock {
+		message += fmt.Sprintf("; only %d %s left in store", item.Available, item.Name)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": message,
+		"issue":   issue,
+		"item":    item,
+	})
+}
+
+// GetEquipmentIssues lists issues, optionally only kit still out or overdue, or only one batch's.
+// Coaches only see issues for their batches.
+func (h *EquipmentHandler) GetEquipmentIssues(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	now := time.Now()
+	filter := models.EquipmentIssueFilter{}
+	filter.OutstandingOnly, _ = strconv.ParseBool(query.Get("outstanding"))
+	if overdue, _ := strconv.ParseBool(query.Get("overdue")); overdue {
+		filter.OutstandingOnly = true
+		filter.DueBefore = &now
+	}
+	if value := query.Get("batchId"); value != "" {
+		batchID, err := primitive.ObjectIDFromHex(value)
+		if err != nil {
+			http.Error(w, "Invalid batch ID", http.StatusBadRequest)
+			return
+		}
+		filter.BatchIDs = []primitive.ObjectID{batchID}
+	}
+
+	if roleFromClaims(r) == "coach" {
+		batchIDs, ok := coachBatchIDs(w, r, h.db)
+		if !ok {
+			return
+		}
+		if filter.BatchIDs != nil && !containsObjectID(batchIDs, filter.BatchIDs[0]) {
+			http.Error(w, "Batch not found", http.StatusNotFound)
+			return
+		}
+		if filter.BatchIDs == nil {
+			filter.BatchIDs = batchIDs
+		}
+	}
+
+	issues, err := h.db.GetEquipmentIssues(r.Context(), filter)
+	if err != nil {
+		http.Error(w, "Error fetching issues", http.StatusInternalServerError)
+		return
+	}
+	markOverdue(issues, now)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(issues)
+}
+
+// GetSessionEquipment lists the kit issued for a session
+func (h *EquipmentHandler) GetSessionEquipment(w http.ResponseWriter, r *http.Request) {
+	session, ok := staffSessionFromURL(w, r, h.db)
+	if !ok {
+		return
+	}
+
+	issues, err := h.db.GetEquipmentIssues(r.Context(), models.EquipmentIssueFilter{SessionID: &session.ID})
+	if err != nil {
+		http.Error(w, "Error fetching issues", http.StatusInternalServerError)
+		return
+	}
+	markOverdue(issues, time.Now())
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(issues)
+}
+
+// ReturnEquipment takes back kit that was issued. Whatever isn't reported lost or damaged goes
+// back in store; lost and damaged kit is written off the item's stock.
+func (h *EquipmentHandler) ReturnEquipment(w http.ResponseWriter, r *http.Request) {
+	userID, err := subjectIDFromClaims(r)
+	if err != nil {
+		http.Error(w, "Invalid token claims", http.StatusUnauthorized)
+		return
+	}
+	issue, ok := h.issueFromURL(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.ReturnEquipmentRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	if req.Lost+req.Damaged > issue.Quantity {
+		writeFieldError(w, "lost", "max", fmt.Sprintf("lost and damaged together can't be more than the %d issued", issue.Quantity))
+		return
+	}
+	if issue.Status != models.EquipmentIssued {
+		http.Error(w, "This kit has already been returned", http.StatusConflict)
+		return
+	}
+
+	equipmentReturn := models.EquipmentReturn{
+		Returned:   issue.Quantity - req.Lost - req.Damaged,
+		Lost:       req.Lost,
+		Damaged:    req.Damaged,
+		Note:       strings.TrimSpace(req.Note),
+		ReceivedBy: userID.Hex(),
+		ReceivedAt: time.Now(),
+	}
+	if err := h.db.ReturnEquipmentIssue(r.Context(), issue.ID, equipmentReturn); err != nil {
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "This kit has already been returned", http.StatusConflict)
+		} else {
+			http.Error(w, "Error returning equipment", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	var writeOff *models.EquipmentAdjustment
+	if req.Lost+req.Damaged > 0 {
+		writeOff = &models.EquipmentAdjustment{
+			Change:  -(req.Lost + req.Damaged),
+			Reason:  writeOffReason(issue, req.Lost, req.Damaged),
+			By:      userID.Hex(),
+			At:      equipmentReturn.ReceivedAt,
+			IssueID: &issue.ID,
+		}
+	}
+	if err := h.db.RestockEquipment(r.Context(), issue.ItemID, equipmentReturn.Returned, writeOff); err != nil && err != mongo.ErrNoDocuments {
+		http.Error(w, "Error restocking equipment", http.StatusInternalServerError)
+		return
+	}
+
+	issue.Status = models.EquipmentReturned
+	issue.Return = &equipmentReturn
+	issue.Overdue = false
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Equipment returned",
+		"issue":   issue,
+	})
+}
+
+// GetCricketerEquipment reports the kit a cricketer holds and has borrowed before. Coaches can
+// only see cricketers in their batches.
+func (h *EquipmentHandler) GetCricketerEquipment(w http.ResponseWriter, r *http.Request) {
+	cricketer, ok := staffCricketerFromURL(w, r, h.db)
+	if !ok {
+		return
+	}
+	h.writeCricketerEquipment(w, r, cricketer)
+}
+
+// GetOwnEquipment reports the kit the logged-in cricketer holds and has borrowed before
+func (h *EquipmentHandler) GetOwnEquipment(w http.ResponseWriter, r *http.Request) {
+	cricketerID, err := subjectIDFromClaims(r)
+	if err != nil {
+		http.Error(w, "Invalid token claims", http.StatusUnauthorized)
+		return
+	}
+	cricketer, err := h.db.GetCricketerByID(r.Context(), cricketerID)
+	if err != nil {
+		http.Error(w, "Error fetching cricketer", http.StatusInternalServerError)
+		return
+	}
+	h.writeCricketerEquipment(w, r, cricketer)
+}
+
+func (h *EquipmentHandler) writeCricketerEquipment(w http.ResponseWriter, r *http.Request, cricketer *models.Cricketer) {
+	issues, err := h.db.GetEquipmentIssues(r.Context(), models.EquipmentIssueFilter{CricketerID: &cricketer.ID})
+	if err != nil {
+		http.Error(w, "Error fetching issues", http.StatusInternalServerError)
+		return
+	}
+	now := time.Now()
+	markOverdue(issues, now)
+
+	holding := []models.EquipmentIssue{}
+	for _, issue := range issues {
+		if issue.Status == models.EquipmentIssued {
+			holding = append(holding, issue)
+		}
+	}
+	sort.SliceStable(holding, func(i, j int) bool { return holding[i].DueAt.Before(holding[j].DueAt) })
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(models.CricketerEquipmentHistory{
+		CricketerID:   cricketer.ID,
+		CricketerName: cricketer.Name,
+		Summary:       equipmentSummary(issues, now),
+		Holding:       holding,
+		Issues:        issues,
+	})
+}
+
+// itemFromURL loads the inventory item named in the URL
+func (h *EquipmentHandler) itemFromURL(w http.ResponseWriter, r *http.Request) (*models.EquipmentItem, bool) {
+	itemID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid item ID", http.StatusBadRequest)
+		return nil, false
+	}
+
+	item, err := h.db.GetEquipmentItemByID(r.Context(), itemID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "Item not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Error fetching item", http.StatusInternalServerError)
+		}
+		return nil, false
+	}
+	item.LowStock = item.IsLowStock()
+	return item, true
+}
+
+// issueFromURL loads the equipment issue named in the URL. Coaches only get issues to cricketers
+// they coach or for sessions they could issue kit for.
+func (h *EquipmentHandler) issueFromURL(w http.ResponseWriter, r *http.Request) (*models.EquipmentIssue, bool) {
+	issueID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid issue ID", http.StatusBadRequest)
+		return nil, false
+	}
+
+	issue, err := h.db.GetEquipmentIssueByID(r.Context(), issueID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "Issue not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Error fetching issue", http.StatusInternalServerError)
+		}
+		return nil, false
+	}
+
+	if issue.CricketerID != nil {
+		if _, ok := staffCricketer(w, r, h.db, *issue.CricketerID); !ok {
+			return nil, false
+		}
+	} else if issue.SessionID != nil {
+		if _, ok := staffSession(w, r, h.db, *issue.SessionID); !ok {
+			return nil, false
+		}
+	}
+	issue.Overdue = issue.IsOverdue(time.Now())
+	return issue, true
+}
+
+func applyEquipmentDetails(item *models.EquipmentItem, details models.EquipmentDetails) {
+	item.Name = strings.TrimSpace(details.Name)
+	item.Category = details.Category
+	item.Size = strings.TrimSpace(details.Size)
+	item.Condition = details.Condition
+	item.Location = strings.TrimSpace(details.Location)
+	item.LowStockThreshold = details.LowStockThreshold
+	item.Notes = strings.TrimSpace(details.Notes)
+}
+
+// markOverdue flags the issues still out after they were due back
+func markOverdue(issues []models.EquipmentIssue, now time.Time) {
+	for i := range issues {
+		issues[i].Overdue = issues[i].IsOverdue(now)
+	}
+}
+
+// equipmentSummary counts what a group of issues lent and what came back
+func equipmentSummary(issues []models.EquipmentIssue, now time.Time) models.EquipmentIssueSummary {
+	summary := models.EquipmentIssueSummary{}
+	for _, issue := range issues {
+		summary.TimesIssued++
+		summary.UnitsIssued += issue.Quantity
+		if issue.Status == models.EquipmentIssued {
+			summary.Outstanding += issue.Quantity
+			if issue.IsOverdue(now) {
+				summary.Overdue += issue.Quantity
+			}
+		}
+		if issue.Return != nil {
+			summary.Lost += issue.Return.Lost
+			summary.Damaged += issue.Return.Damaged
+		}
+	}
+	return summary
+}
+
+// writeOffReason describes kit written off when an issue came back short
+func writeOffReason(issue *models.EquipmentIssue, lost int, damaged int) string {
+	var parts []string
+	if lost > 0 {
+		parts = append(parts, fmt.Sprintf("%d lost", lost))
+	}
+	if damaged > 0 {
+		parts = append(parts, fmt.Sprintf("%d damaged", damaged))
+	}
+	holder := "a session"
+	if issue.CricketerName != "" {
+		holder = issue.CricketerName
+	}
+	return strings.Join(parts, " and ") + " on issue to " + holder
+}
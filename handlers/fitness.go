@@ -0,0 +1,686 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"cricketApp/agecategory"
+	"cricketApp/db"
+	"cricketApp/models"
+	"cricketApp/notification"
+)
+
+// FitnessHandler manages the fitness test catalogue, test results and drop alerts
+type FitnessHandler struct {
+	db       db.Database
+	notifier notification.Notifier
+}
+
+func NewFitnessHandler(db db.Database, notifier notification.Notifier) *FitnessHandler {
+	return &FitnessHandler{db: db, notifier: notifier}
+}
+
+// CreateFitnessTest adds a test to the catalogue (admin only)
+func (h *FitnessHandler) CreateFitnessTest(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateFitnessTestRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	if !validFitnessBenchmarks(w, req.HigherIsBetter, req.Benchmarks) {
+		return
+	}
+
+	test := &models.FitnessTest{
+		Key:            strings.ToLower(strings.TrimSpace(req.Key)),
+		Name:           strings.TrimSpace(req.Name),
+		Unit:           strings.TrimSpace(req.Unit),
+		HigherIsBetter: req.HigherIsBetter,
+		Description:    req.Description,
+		DropThreshold:  req.DropThreshold,
+		Benchmarks:     req.Benchmarks,
+		Active:         true,
+	}
+	if test.Benchmarks == nil {
+		test.Benchmarks = []models.FitnessBenchmark{}
+	}
+
+	if err := h.db.CreateFitnessTest(r.Context(), test); err != nil {
+		if err == db.ErrDuplicateFitnessTestKey {
+			writeFieldError(w, "key", "unique", err.Error())
+		} else {
+			http.Error(w, "Error creating fitness test", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Fitness test created successfully",
+		"test":    test,
+	})
+}
+
+// GetFitnessTests lists the active tests, or the whole catalogue with ?includeInactive=true
+func (h *FitnessHandler) GetFitnessTests(w http.ResponseWriter, r *http.Request) {
+	includeInactive, _ := strconv.ParseBool(r.URL.Query().Get("includeInactive"))
+
+	tests, err := h.db.GetFitnessTests(r.Context(), !includeInactive)
+	if err != nil {
+		http.Error(w, "Error fetching fitness tests", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(tests)
+}
+
+// UpdateFitnessTest updates a test's name, description, alert threshold or benchmarks, or retires it (admin only)
+func (h *FitnessHandler) UpdateFitnessTest(w http.ResponseWriter, r *http.Request) {
+	test, ok := h.fitnessTestFromURL(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateFitnessTestRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			writeFieldError(w, "name", "required", "is required")
+			return
+		}
+		test.Name = name
+	}
+	if req.Description != nil {
+		test.Description = *req.Description
+	}
+	if req.DropThreshold != nil {
+		test.DropThreshold = *req.DropThreshold
+	}
+	if req.Benchmarks != nil {
+		if !validFitnessBenchmarks(w, test.HigherIsBetter, *req.Benchmarks) {
+			return
+		}
+		test.Benchmarks = *req.Benchmarks
+		if test.Benchmarks == nil {
+			test.Benchmarks = []models.FitnessBenchmark{}
+		}
+	}
+	if req.Active != nil {
+		test.Active = *req.Active
+	}
+
+	if err := h.db.UpdateFitnessTest(r.Context(), test.ID, test); err != nil {
+		http.Error(w, "Error updating fitness test", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Fitness test updated successfully",
+		"test":    test,
+	})
+}
+
+// RecordBatchFitnessResults enters a batch's results in a test. Each result is rated against the
+// benchmark for the cricketer's age category, and results significantly worse than the cricketer's
+// previous result raise an alert for the batch's coaches.
+func (h *FitnessHandler) RecordBatchFitnessResults(w http.ResponseWriter, r *http.Request) {
+	userID, err := subjectIDFromClaims(r)
+	if err != nil {
+		http.Error(w, "Invalid token claims", http.StatusUnauthorized)
+		return
+	}
+	batch, ok := staffBatchFromURL(w, r, h.db)
+	if !ok {
+		return
+	}
+
+	var req models.FitnessBatchResultsRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	testID, _ := primitive.ObjectIDFromHex(req.TestID)
+	test, err := h.db.GetFitnessTestByID(r.Context(), testID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			writeFieldError(w, "testId", "exists", "fitness test not found")
+		} else {
+			http.Error(w, "Error fetching fitness test", http.StatusInternalServerError)
+		}
+		return
+	}
+	if !test.Active {
+		writeFieldError(w, "testId", "active", "fitness test has been retired")
+		return
+	}
+
+	season, err := seasonAt(r.Context(), h.db, req.TestedOn)
+	if err != nil {
+		http.Error(w, "Error fetching season", http.StatusInternalServerError)
+		return
+	}
+	cricketers, err := h.db.GetCricketersByBatches(r.Context(), []primitive.ObjectID{batch.ID})
+	if err != nil {
+		http.Error(w, "Error fetching cricketers", http.StatusInternalServerError)
+		return
+	}
+	members := make(map[primitive.ObjectID]*models.Cricketer, len(cricketers))
+	for i := range cricketers {
+		members[cricketers[i].ID] = &cricketers[i]
+	}
+
+	role := roleFromClaims(r)
+	results := make([]models.FitnessResult, 0, len(req.Results))
+	seen := make(map[primitive.ObjectID]bool, len(req.Results))
+	for i, entry := range req.Results {
+		field := fmt.Sprintf("results[%d].cricketerId", i)
+		cricketerID, _ := primitive.ObjectIDFromHex(entry.CricketerID)
+		cricketer, ok := members[cricketerID]
+		if !ok {
+			writeFieldError(w, field, "batch", "cricketer is not in this batch")
+			return
+		}
+		if seen[cricketerID] {
+			writeFieldError(w, field, "unique", "cricketer has more than one result")
+			return
+		}
+		seen[cricketerID] = true
+
+		ageCategory := ageCategoryAt(cricketer.DateOfBirth, season)
+		results = append(results, models.FitnessResult{
+			CricketerID:    cricketer.ID,
+			CricketerName:  cricketer.Name,
+			BatchID:        &batch.ID,
+			TestID:         test.ID,
+			TestName:       test.Name,
+			Unit:           test.Unit,
+			TestedOn:       req.TestedOn,
+			Value:          entry.Value,
+			AgeCategory:    ageCategory,
+			Gender:         cricketer.Gender,
+			Rating:         test.Rate(entry.Value, ageCategory, cricketer.Gender),
+			Note:           strings.TrimSpace(entry.Note),
+			RecordedBy:     userID.Hex(),
+			RecordedByRole: role,
+		})
+	}
+
+	// Look up previous results before saving the new ones
+	previous := make([]*models.FitnessResult, len(results))
+	for i := range results {
+		result, err := h.db.GetPreviousFitnessResult(r.Context(), results[i].CricketerID, test.ID, req.TestedOn)
+		if err != nil && err != mongo.ErrNoDocuments {
+			http.Error(w, "Error fetching previous results", http.StatusInternalServerError)
+			return
+		}
+		previous[i] = result
+	}
+
+	if err := h.db.CreateFitnessResults(r.Context(), results); err != nil {
+		http.Error(w, "Error saving fitness results", http.StatusInternalServerError)
+		return
+	}
+
+	alerts := []models.FitnessAlert{}
+	for i, result := range results {
+		if previous[i] == nil || test.DropThreshold <= 0 {
+			continue
+		}
+		drop := test.DropPercent(previous[i].Value, result.Value)
+		if drop < test.DropThreshold {
+			continue
+		}
+		alert := models.FitnessAlert{
+			CricketerID:      result.CricketerID,
+			CricketerName:    result.CricketerName,
+			BatchID:          result.BatchID,
+			TestID:           test.ID,
+			TestName:         test.Name,
+			Unit:             test.Unit,
+			ResultID:         result.ID,
+			PreviousResultID: previous[i].ID,
+			Previous:         previous[i].Value,
+			Current:          result.Value,
+			DropPercent:      math.Round(drop*10) / 10,
+		}
+		if err := h.db.CreateFitnessAlert(r.Context(), &alert); err != nil {
+			// The results are saved; the alert is only a prompt for the coach
+			log.Printf("Error creating fitness alert for cricketer %s: %v", result.CricketerID.Hex(), err)
+			continue
+		}
+		alerts = append(alerts, alert)
+	}
+	h.notifyFitnessAlerts(r.Context(), batch, alerts)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": fmt.Sprintf("%d fitness results recorded", len(results)),
+		"results": results,
+		"alerts":  alerts,
+	})
+}
+
+// GetCricketerFitness returns a cricketer's trend in each test, optionally only ?testId, with the
+// latest result's ranking within their age category for that season
+func (h *FitnessHandler) GetCricketerFitness(w http.ResponseWriter, r *http.Request) {
+	cricketer, ok := staffCricketerFromURL(w, r, h.db)
+	if !ok {
+		return
+	}
+
+	filter := models.FitnessResultFilter{CricketerID: &cricketer.ID}
+	if value := r.URL.Query().Get("testId"); value != "" {
+		testID, err := primitive.ObjectIDFromHex(value)
+		if err != nil {
+			http.Error(w, "Invalid test ID", http.StatusBadRequest)
+			return
+		}
+		filter.TestID = &testID
+	}
+
+	results, err := h.db.GetFitnessResults(r.Context(), filter)
+	if err != nil {
+		http.Error(w, "Error fetching fitness results", http.StatusInternalServerError)
+		return
+	}
+
+	trends := []models.FitnessTrend{}
+	index := make(map[primitive.ObjectID]int)
+	for _, result := range results {
+		i, ok := index[result.TestID]
+		if !ok {
+			test, err := h.db.GetFitnessTestByID(r.Context(), result.TestID)
+			if err != nil {
+				http.Error(w, "Error fetching fitness test", http.StatusInternalServerError)
+				return
+			}
+			i = len(trends)
+			index[result.TestID] = i
+			trends = append(trends, models.FitnessTrend{Test: *test})
+		}
+		trends[i].Results = append(trends[i].Results, result)
+	}
+
+	for i := range trends {
+		if err := h.completeFitnessTrend(r.Context(), &trends[i]); err != nil {
+			http.Error(w, "Error ranking fitness results", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(trends)
+}
+
+// GetFitnessLeaderboard ranks each cricketer's latest result in a test within ?ageCategory for the
+// season containing ?date (default today). Coaches see the cricketers in their batches, ranked
+// against the whole age category.
+func (h *FitnessHandler) GetFitnessLeaderboard(w http.ResponseWriter, r *http.Request) {
+	test, ok := h.fitnessTestFromURL(w, r)
+	if !ok {
+		return
+	}
+
+	query := r.URL.Query()
+	ageCategory := query.Get("ageCategory")
+	if _, ok := agecategory.Lookup(ageCategory); !ok && ageCategory != agecategory.Open {
+		http.Error(w, "Invalid age category", http.StatusBadRequest)
+		return
+	}
+	date := time.Now()
+	if value := query.Get("date"); value != "" {
+		parsed, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			http.Error(w, "Invalid date, expected YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+		date = parsed
+	}
+
+	season, err := seasonAt(r.Context(), h.db, date)
+	if err != nil {
+		http.Error(w, "Error fetching season", http.StatusInternalServerError)
+		return
+	}
+	cohort, err := h.db.GetLatestFitnessResults(r.Context(), test.ID, ageCategory, season.StartDate, season.EndDate.Add(time.Nanosecond))
+	if err != nil {
+		http.Error(w, "Error fetching fitness results", http.StatusInternalServerError)
+		return
+	}
+
+	var visible map[primitive.ObjectID]bool
+	if roleFromClaims(r) == "coach" {
+		coachID, err := subjectIDFromClaims(r)
+		if err != nil {
+			http.Error(w, "Invalid token claims", http.StatusUnauthorized)
+			return
+		}
+		batches, err := h.db.GetBatchesByCoach(r.Context(), coachID)
+		if err != nil {
+			http.Error(w, "Error fetching coach batches", http.StatusInternalServerError)
+			return
+		}
+		visible = make(map[primitive.ObjectID]bool, len(batches))
+		for _, batch := range batches {
+			visible[batch.ID] = true
+		}
+	}
+
+	leaderboard := models.FitnessLeaderboard{
+		Test:        *test,
+		AgeCategory: ageCategory,
+		Season:      season.Name,
+		Entries:     []models.FitnessLeaderboardEntry{},
+	}
+	for _, result := range cohort {
+		if visible != nil && (result.BatchID == nil || !visible[*result.BatchID]) {
+			continue
+		}
+		rank, percentile := rankFitnessResult(test, result.Value, cohort)
+		leaderboard.Entries = append(leaderboard.Entries, models.FitnessLeaderboardEntry{
+			FitnessResult: result,
+			Rank:          rank,
+			Percentile:    percentile,
+		})
+	}
+	sort.SliceStable(leaderboard.Entries, func(i, j int) bool {
+		if leaderboard.Entries[i].Rank != leaderboard.Entries[j].Rank {
+			return leaderboard.Entries[i].Rank < leaderboard.Entries[j].Rank
+		}
+		return leaderboard.Entries[i].CricketerName < leaderboard.Entries[j].CricketerName
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(leaderboard)
+}
+
+// GetFitnessAlerts lists drop alerts, newest first, optionally only ?open ones or for ?cricketerId.
+// Coaches see alerts for their batches.
+func (h *FitnessHandler) GetFitnessAlerts(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := models.FitnessAlertFilter{}
+	filter.Open, _ = strconv.ParseBool(query.Get("open"))
+	if value := query.Get("cricketerId"); value != "" {
+		cricketerID, err := primitive.ObjectIDFromHex(value)
+		if err != nil {
+			http.Error(w, "Invalid cricketer ID", http.StatusBadRequest)
+			return
+		}
+		filter.CricketerID = &cricketerID
+	}
+
+	if roleFromClaims(r) == "coach" {
+		batchIDs, ok := coachBatchIDs(w, r, h.db)
+		if !ok {
+			return
+		}
+		filter.BatchIDs = batchIDs
+	}
+
+	alerts, err := h.db.GetFitnessAlerts(r.Context(), filter)
+	if err != nil {
+		http.Error(w, "Error fetching fitness alerts", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(alerts)
+}
+
+// AcknowledgeFitnessAlert marks a drop alert as followed up
+func (h *FitnessHandler) AcknowledgeFitnessAlert(w http.ResponseWriter, r *http.Request) {
+	alertID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid alert ID", http.StatusBadRequest)
+		return
+	}
+	userID, err := subjectIDFromClaims(r)
+	if err != nil {
+		http.Error(w, "Invalid token claims", http.StatusUnauthorized)
+		return
+	}
+
+	alert, err := h.db.GetFitnessAlertByID(r.Context(), alertID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "Fitness alert not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Error fetching fitness alert", http.StatusInternalServerError)
+		}
+		return
+	}
+	if roleFromClaims(r) == "coach" {
+		batchIDs, ok := coachBatchIDs(w, r, h.db)
+		if !ok {
+			return
+		}
+		if alert.BatchID == nil || !containsObjectID(batchIDs, *alert.BatchID) {
+			http.Error(w, "Fitness alert not found", http.StatusNotFound)
+			return
+		}
+	}
+
+	alert, err = h.db.AcknowledgeFitnessAlert(r.Context(), alertID, userID.Hex())
+	if err != nil {
+		http.Error(w, "Error acknowledging fitness alert", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Fitness alert acknowledged",
+		"alert":   alert,
+	})
+}
+
+// completeFitnessTrend fills in a trend's best result, latest change and ranking
+func (h *FitnessHandler) completeFitnessTrend(ctx context.Context, trend *models.FitnessTrend) error {
+	test := &trend.Test
+	for i := range trend.Results {
+		if trend.Best == nil || test.Better(trend.Results[i].Value, trend.Best.Value) {
+			trend.Best = &trend.Results[i]
+		}
+	}
+
+	latest := trend.Results[len(trend.Results)-1]
+	if len(trend.Results) > 1 {
+		previous := trend.Results[len(trend.Results)-2]
+		change := math.Round((latest.Value-previous.Value)*100) / 100
+		trend.Change = &change
+		if previous.Value != 0 {
+			improvement := math.Round(-test.DropPercent(previous.Value, latest.Value)*10) / 10
+			trend.ChangePercent = &improvement
+		}
+	}
+
+	if latest.AgeCategory == "" {
+		return nil
+	}
+	season, err := seasonAt(ctx, h.db, latest.TestedOn)
+	if err != nil {
+		return err
+	}
+	cohort, err := h.db.GetLatestFitnessResults(ctx, test.ID, latest.AgeCategory, season.StartDate, season.EndDate.Add(time.Nanosecond))
+	if err != nil {
+		return err
+	}
+	rank, percentile := rankFitnessResult(test, latest.Value, cohort)
+	trend.Ranking = &models.FitnessRanking{
+		AgeCategory: latest.AgeCategory,
+		Season:      season.Name,
+		Rank:        rank,
+		Cohort:      len(cohort),
+		Percentile:  percentile,
+	}
+	return nil
+}
+
+// notifyFitnessAlerts tells the batch's coaches which cricketers' results dropped
+func (h *FitnessHandler) notifyFitnessAlerts(ctx context.Context, batch *models.Batch, alerts []models.FitnessAlert) {
+	if len(alerts) == 0 {
+		return
+	}
+	lines := make([]string, 0, len(alerts))
+	for _, alert := range alerts {
+		lines = append(lines, fmt.Sprintf("%s: %s %g %s, down %.1f%% from %g",
+			alert.CricketerName, alert.TestName, alert.Current, alert.Unit, alert.DropPercent, alert.Previous))
+	}
+	message := strings.Join(lines, "; ")
+
+	for _, coachID := range batch.CoachIDs {
+		coach, err := h.db.GetCoachByID(ctx, coachID)
+		if err != nil {
+			log.Printf("Error fetching coach %s for fitness alerts: %v", coachID.Hex(), err)
+			continue
+		}
+		recipient := notification.Recipient{Name: coach.Name, Mobile: coach.Mobile}
+		if err := h.notifier.Notify(ctx, recipient, "Fitness drop in "+batch.Name, message); err != nil {
+			log.Printf("Error notifying coach %s of fitness alerts: %v", coachID.Hex(), err)
+		}
+	}
+}
+
+func (h *FitnessHandler) fitnessTestFromURL(w http.ResponseWriter, r *http.Request) (*models.FitnessTest, bool) {
+	testID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid test ID", http.StatusBadRequest)
+		return nil, false
+	}
+
+	test, err := h.db.GetFitnessTestByID(r.Context(), testID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "Fitness test not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Error fetching fitness test", http.StatusInternalServerError)
+		}
+		return nil, false
+	}
+	return test, true
+}
+
+// coachBatchIDs returns the batches the logged-in coach coaches
+func coachBatchIDs(w http.ResponseWriter, r *http.Request, database db.Database) ([]primitive.ObjectID, bool) {
+	coachID, err := subjectIDFromClaims(r)
+	if err != nil {
+		http.Error(w, "Invalid token claims", http.StatusUnauthorized)
+		return nil, false
+	}
+	batches, err := database.GetBatchesByCoach(r.Context(), coachID)
+	if err != nil {
+		http.Error(w, "Error fetching coach batches", http.StatusInternalServerError)
+		return nil, false
+	}
+	batchIDs := make([]primitive.ObjectID, 0, len(batches))
+	for _, batch := range batches {
+		batchIDs = append(batchIDs, batch.ID)
+	}
+	return batchIDs, true
+}
+
+// validFitnessBenchmarks checks each benchmark names a known age category, is unique for its
+// category and gender, and gets stricter from average to excellent
+func validFitnessBenchmarks(w http.ResponseWriter, higherIsBetter bool, benchmarks []models.FitnessBenchmark) bool {
+	test := &models.FitnessTest{HigherIsBetter: higherIsBetter}
+	seen := make(map[string]bool, len(benchmarks))
+	for i, benchmark := range benchmarks {
+		field := fmt.Sprintf("benchmarks[%d]", i)
+		if _, ok := agecategory.Lookup(benchmark.AgeCategory); !ok && benchmark.AgeCategory != agecategory.Open {
+			writeFieldError(w, field+".ageCategory", "oneof", "must be one of "+strings.Join(batchAgeCategoryNames(), ", "))
+			return false
+		}
+		key := benchmark.AgeCategory + "/" + benchmark.Gender
+		if seen[key] {
+			writeFieldError(w, field, "unique", "more than one benchmark for this age category and gender")
+			return false
+		}
+		seen[key] = true
+		if !test.Better(benchmark.Good, benchmark.Average) || !test.Better(benchmark.Excellent, benchmark.Good) {
+			writeFieldError(w, field, "order", "good must beat average, and excellent must beat good")
+			return false
+		}
+	}
+	return true
+}
+
+// rankFitnessResult ranks a result among the cohort's results. Equal results share a rank, and the
+// percentile counts results it beats plus half of those it ties, including itself.
+func rankFitnessResult(test *models.FitnessTest, value float64, cohort []models.FitnessResult) (int, float64) {
+	if len(cohort) == 0 {
+		return 1, 100
+	}
+	better, worse, equal := 0, 0, 0
+	for _, result := range cohort {
+		switch {
+		case test.Better(result.Value, value):
+			better++
+		case result.Value == value:
+			equal++
+		default:
+			worse++
+		}
+	}
+	percentile := (float64(worse) + float64(equal)/2) / float64(len(cohort)) * 100
+	return better + 1, math.Round(percentile*10) / 10
+}
+
+// staffBatchFromURL loads the batch named by {id} for an admin or one of the batch's coaches
+func staffBatchFromURL(w http.ResponseWriter, r *http.Request, database db.Database) (*models.Batch, bool) {
+	batchID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid batch ID", http.StatusBadRequest)
+		return nil, false
+	}
+
+	batch, err := database.GetBatchByID(r.Context(), batchID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "Batch not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Error fetching batch", http.StatusInternalServerError)
+		}
+		return nil, false
+	}
+
+	if roleFromClaims(r) != "coach" {
+		return batch, true
+	}
+	coachID, err := subjectIDFromClaims(r)
+	if err != nil {
+		http.Error(w, "Invalid token claims", http.StatusUnauthorized)
+		return nil, false
+	}
+	if !containsObjectID(batch.CoachIDs, coachID) {
+		http.Error(w, "Batch not found", http.StatusNotFound)
+		return nil, false
+	}
+	return batch, true
+}
+
+func containsObjectID(ids []primitive.ObjectID, id primitive.ObjectID) bool {
+	for _, candidate := range ids {
+		if candidate == id {
+			return true
+		}
+	}
+	return false
+}
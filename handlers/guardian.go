@@ -0,0 +1,608 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"golang.org/x/crypto/bcrypt"
+
+	"cricketApp/db"
+	"cricketApp/middleware/authmiddleware"
+	"cricketApp/models"
+	"cricketApp/payments"
+)
+
+// The guardian dashboard shows sessions this far ahead and attendance this far back
+const (
+	dashboardSessionWindow    = 14 * 24 * time.Hour
+	dashboardAttendanceWindow = 30 * 24 * time.Hour
+	dashboardAnnouncements    = 10
+)
+
+// GuardianHandler serves parents and guardians, who act on behalf of the cricketers linked to them
+type GuardianHandler struct {
+	db      db.Database
+	gateway payments.Gateway
+}
+
+func NewGuardianHandler(db db.Database, gateway payments.Gateway) *GuardianHandler {
+	return &GuardianHandler{db: db, gateway: gateway}
+}
+
+// HandleGuardianLogin logs a guardian in with their mobile number and password
+func (h *GuardianHandler) HandleGuardianLogin(w http.ResponseWriter, r *http.Request) {
+	var loginRequest struct {
+		Mobile   string `json:"mobile" binding:"required"`
+		Password string `json:"password" binding:"required"`
+	}
+
+	if !decodeRequest(w, r, &loginRequest) {
+		return
+	}
+
+	guardian, err := h.db.GetGuardianByMobile(r.Context(), normalizeMobile(loginRequest.Mobile))
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "Invalid mobile number or password", http.StatusUnauthorized)
+		} else {
+			http.Error(w, "Database error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(guardian.Password), []byte(loginRequest.Password)); err != nil {
+		http.Error(w, "Invalid mobile number or password", http.StatusUnauthorized)
+		return
+	}
+	if !guardian.IsActive {
+		http.Error(w, "Guardian account is disabled", http.StatusForbidden)
+		return
+	}
+
+	claims := map[string]interface{}{
+		"sub":  guardian.ID.Hex(),
+		"role": "guardian",
+		"exp":  time.Now().Add(time.Hour * 24).Unix(),
+	}
+
+	_, tokenString, err := authmiddleware.TokenAuth.Encode(claims)
+	if err != nil {
+		http.Error(w, "Error generating token", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":  "Login successful",
+		"token":    tokenString,
+		"guardian": guardian,
+	})
+}
+
+// CreateGuardian creates a guardian account linked to the given cricketers (admin only)
+func (h *GuardianHandler) CreateGuardian(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateGuardianRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	cricketerIDs, ok := h.guardianChildren(w, r, req.CricketerIDs)
+	if !ok {
+		return
+	}
+
+	mobile := normalizeMobile(req.Mobile)
+	_, err := h.db.GetGuardianByMobile(r.Context(), mobile)
+	if err == nil {
+		http.Error(w, "Mobile number already exists", http.StatusConflict)
+		return
+	} else if err != mongo.ErrNoDocuments {
+		http.Error(w, "Database error checking mobile", http.StatusInternalServerError)
+		return
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		http.Error(w, "Error hashing password", http.StatusInternalServerError)
+		return
+	}
+
+	guardian := &models.Guardian{
+		Name:         strings.TrimSpace(req.Name),
+		Mobile:       mobile,
+		Email:        req.Email,
+		Password:     string(hashedPassword),
+		CricketerIDs: cricketerIDs,
+		IsActive:     true,
+	}
+	if err := h.db.CreateGuardian(r.Context(), guardian); err != nil {
+		http.Error(w, "Error creating guardian", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":  "Guardian created successfully",
+		"guardian": guardian,
+	})
+}
+
+// GetAllGuardians lists guardian accounts, or those linked to a cricketer when cricketerId is given (admin only)
+func (h *GuardianHandler) GetAllGuardians(w http.ResponseWriter, r *http.Request) {
+	var guardians []models.Guardian
+	var err error
+	if cricketerID := r.URL.Query().Get("cricketerId"); cricketerID != "" {
+		id, parseErr := primitive.ObjectIDFromHex(cricketerID)
+		if parseErr != nil {
+			writeFieldError(w, "cricketerId", "objectid", "cricketerId must be a valid ID")
+			return
+		}
+		guardians, err = h.db.GetGuardiansForCricketer(r.Context(), id)
+	} else {
+		guardians, err = h.db.GetAllGuardians(r.Context())
+	}
+	if err != nil {
+		http.Error(w, "Error fetching guardians", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(guardians)
+}
+
+// UpdateGuardian updates a guardian account or the children linked to it (admin only)
+func (h *GuardianHandler) UpdateGuardian(w http.ResponseWriter, r *http.Request) {
+	objID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid guardian ID", http.StatusBadRequest)
+		return
+	}
+
+	guardian, err := h.db.GetGuardianByID(r.Context(), objID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "Guardian not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Error fetching guardian", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	var updateData models.UpdateGuardianRequest
+	if !decodeRequest(w, r, &updateData) {
+		return
+	}
+
+	if updateData.Name != nil {
+		guardian.Name = strings.TrimSpace(*updateData.Name)
+	}
+	if updateData.Email != nil {
+		guardian.Email = *updateData.Email
+	}
+	if updateData.IsActive != nil {
+		guardian.IsActive = *updateData.IsActive
+	}
+	if updateData.CricketerIDs != nil {
+		cricketerIDs, ok := h.guardianChildren(w, r, *updateData.CricketerIDs)
+		if !ok {
+			return
+		}
+		guardian.CricketerIDs = cricketerIDs
+	}
+
+	if err := h.db.UpdateGuardian(r.Context(), objID, guardian); err != nil {
+		http.Error(w, "Error updating guardian", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":  "Guardian updated successfully",
+		"guardian": guardian,
+	})
+}
+
+// GetGuardianProfile returns the logged-in guardian's account
+func (h *GuardianHandler) GetGuardianProfile(w http.ResponseWriter, r *http.Request) {
+	guardian, ok := h.guardianFromClaims(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(guardian)
+}
+
+// GetGuardianDashboard shows each linked child's upcoming sessions, recent attendance,
+// fees and announcements
+func (h *GuardianHandler) GetGuardianDashboard(w http.ResponseWriter, r *http.Request) {
+	guardian, ok := h.guardianFromClaims(w, r)
+	if !ok {
+		return
+	}
+
+	season, err := seasonAt(r.Context(), h.db, time.Now())
+	if err != nil {
+		http.Error(w, "Error fetching season", http.StatusInternalServerError)
+		return
+	}
+
+	children := make([]models.GuardianChild, 0, len(guardian.CricketerIDs))
+	for _, cricketerID := range guardian.CricketerIDs {
+		cricketer, err := h.db.GetCricketerByID(r.Context(), cricketerID)
+		if err == mongo.ErrNoDocuments {
+			continue
+		}
+		if err != nil {
+			http.Error(w, "Error fetching cricketer", http.StatusInternalServerError)
+			return
+		}
+
+		child, err := h.buildGuardianChild(r.Context(), cricketer, season)
+		if err != nil {
+			log.Printf("Error building dashboard for cricketer %s: %v", cricketer.ID.Hex(), err)
+			http.Error(w, "Error building dashboard", http.StatusInternalServerError)
+			return
+		}
+		children = append(children, *child)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"guardian": guardian,
+		"children": children,
+	})
+}
+
+// PayChildFee charges the monthly fee for one or more months through the payment gateway and
+// moves the child's due date forward. Months are paid from the current due date, so arrears are
+// covered first. Retrying with the same idempotency key returns the original payment.
+func (h *GuardianHandler) PayChildFee(w http.ResponseWriter, r *http.Request) {
+	guardian, ok := h.guardianFromClaims(w, r)
+	if !ok {
+		return
+	}
+	cricketer, ok := h.childFromURL(w, r, guardian)
+	if !ok {
+		return
+	}
+
+	var req models.PayFeeRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	if req.Months == 0 {
+		req.Months = 1
+	}
+
+	// Keys are scoped to the guardian so one payer can't collide with another
+	idempotencyKey := guardian.ID.Hex() + ":" + req.IdempotencyKey
+	existing, err := h.db.GetFeePaymentByIdempotencyKey(r.Context(), idempotencyKey)
+	if err == nil {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"message": "Payment already recorded",
+			"payment": existing,
+		})
+		return
+	} else if err != mongo.ErrNoDocuments {
+		http.Error(w, "Error checking payment", http.StatusInternalServerError)
+		return
+	}
+
+	if cricketer.BatchID == nil {
+		http.Error(w, "Cricketer is not in a batch", http.StatusConflict)
+		return
+	}
+	batch, err := h.db.GetBatchByID(r.Context(), *cricketer.BatchID)
+	if err != nil {
+		http.Error(w, "Error fetching batch", http.StatusInternalServerError)
+		return
+	}
+	if batch.MonthlyFee <= 0 {
+		http.Error(w, "Fees for this batch cannot be paid online", http.StatusConflict)
+		return
+	}
+
+	dueDate := cricketer.DueDate
+	payment := &models.FeePayment{
+		CricketerID:    cricketer.ID,
+		BatchID:        batch.ID,
+		Months:         req.Months,
+		Amount:         batch.MonthlyFee * int64(req.Months),
+		Currency:       "INR",
+		PaidBy:         guardian.ID.Hex(),
+		PaidByRole:     "guardian",
+		IdempotencyKey: idempotencyKey,
+	}
+	setFeePeriod(payment, dueDate)
+
+	receipt, err := h.gateway.Charge(r.Context(), payments.Charge{
+		Amount:         payment.Amount,
+		Currency:       payment.Currency,
+		Token:          req.PaymentToken,
+		Description:    fmt.Sprintf("%s fees for %s, %d month(s)", batch.Name, cricketer.Name, req.Months),
+		IdempotencyKey: idempotencyKey,
+	})
+	if err != nil {
+		if errors.Is(err, payments.ErrDeclined) {
+			http.Error(w, "Payment declined", http.StatusPaymentRequired)
+		} else {
+			log.Printf("Error charging fees for cricketer %s: %v", cricketer.ID.Hex(), err)
+			http.Error(w, "Payment could not be processed", http.StatusBadGateway)
+		}
+		return
+	}
+	payment.Receipt = receipt
+
+	// If another guardian paid for this period while the charge went through, the money has still
+	// been taken, so it pays for the next unpaid period instead of the same one twice
+	requestedStart := payment.PeriodStart
+	for attempt := 1; ; attempt++ {
+		err = h.db.CreateFeePayment(r.Context(), payment, dueDate)
+		if err != db.ErrFeePeriodChanged || attempt == maxFeePeriodAttempts {
+			break
+		}
+		var current *models.Cricketer
+		if current, err = h.db.GetCricketerByID(r.Context(), cricketer.ID); err != nil {
+			break
+		}
+		dueDate = current.DueDate
+		setFeePeriod(payment, dueDate)
+	}
+	if err != nil {
+		// The money has been taken, so this needs to be reconciled by hand
+		log.Printf("Fee payment %s charged but not recorded for cricketer %s: %v", receipt.ChargeID, cricketer.ID.Hex(), err)
+		http.Error(w, "Payment taken but could not be recorded; contact the academy with reference "+receipt.ChargeID, http.StatusInternalServerError)
+		return
+	}
+
+	message := "Payment successful"
+	if !payment.PeriodStart.Equal(requestedStart) {
+		message += fmt.Sprintf("; fees from %s had just been paid by someone else, so this payment covers %s to %s",
+			requestedStart.Format("2 Jan 2006"), payment.PeriodStart.Format("2 Jan 2006"), payment.PeriodEnd.Format("2 Jan 2006"))
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": message,
+		"payment": payment,
+	})
+}
+
+// maxFeePeriodAttempts bounds how often a payment is moved on to a later period when others keep paying
+const maxFeePeriodAttempts = 3
+
+// setFeePeriod sets the period a payment covers: from the cricketer's due date, or today if they
+// have none, for the months paid
+func setFeePeriod(payment *models.FeePayment, dueDate *time.Time) {
+	payment.PeriodStart = startOfDay(time.Now())
+	if dueDate != nil {
+		payment.PeriodStart = *dueDate
+	}
+	payment.PeriodEnd = payment.PeriodStart.AddDate(0, payment.Months, 0)
+}
+
+// GetChildFeePayments lists the fee payments made for a child
+func (h *GuardianHandler) GetChildFeePayments(w http.ResponseWriter, r *http.Request) {
+	guardian, ok := h.guardianFromClaims(w, r)
+	if !ok {
+		return
+	}
+	cricketer, ok := h.childFromURL(w, r, guardian)
+	if !ok {
+		return
+	}
+
+	feePayments, err := h.db.GetFeePayments(r.Context(), cricketer.ID)
+	if err != nil {
+		http.Error(w, "Error fetching fee payments", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(feePayments)
+}
+
+// AcknowledgeChildAnnouncement acknowledges an announcement on a child's behalf. The receipt records
+// which guardian acknowledged it.
+func (h *GuardianHandler) AcknowledgeChildAnnouncement(w http.ResponseWriter, r *http.Request) {
+	guardian, ok := h.guardianFromClaims(w, r)
+	if !ok {
+		return
+	}
+	cricketer, ok := h.childFromURL(w, r, guardian)
+	if !ok {
+		return
+	}
+
+	announcementID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid announcement ID", http.StatusBadRequest)
+		return
+	}
+	announcement, err := h.db.GetAnnouncementByID(r.Context(), announcementID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "Announcement not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Error fetching announcement", http.StatusInternalServerError)
+		}
+		return
+	}
+	if !announcementVisibleTo(announcement, cricketer) {
+		http.Error(w, "Announcement not found", http.StatusNotFound)
+		return
+	}
+	if !announcement.RequiresAcknowledgement {
+		http.Error(w, "Announcement does not require acknowledgement", http.StatusBadRequest)
+		return
+	}
+
+	receipt, err := h.db.AcknowledgeAnnouncement(r.Context(), announcementID, cricketer.ID, guardian.ID.Hex())
+	if err != nil {
+		http.Error(w, "Error acknowledging announcement", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(receipt)
+}
+
+// buildGuardianChild collects one child's dashboard section
+func (h *GuardianHandler) buildGuardianChild(ctx context.Context, cricketer *models.Cricketer, season *models.Season) (*models.GuardianChild, error) {
+	now := time.Now()
+	child := &models.GuardianChild{
+		ID:               cricketer.ID,
+		Name:             cricketer.Name,
+		BatchID:          cricketer.BatchID,
+		AgeCategory:      ageCategoryAt(cricketer.DateOfBirth, season),
+		Inactive:         cricketer.InactiveCricketer,
+		DueDate:          cricketer.DueDate,
+		FeeOverdue:       cricketer.DueDate != nil && cricketer.DueDate.Before(now),
+		UpcomingSessions: []*models.Session{},
+	}
+
+	announcementFilter := models.AnnouncementFilter{Limit: dashboardAnnouncements}
+	if cricketer.BatchID != nil {
+		batch, err := h.db.GetBatchByID(ctx, *cricketer.BatchID)
+		if err != nil && err != mongo.ErrNoDocuments {
+			return nil, err
+		}
+		if batch != nil {
+			child.BatchName = batch.Name
+			child.MonthlyFee = batch.MonthlyFee
+		}
+
+		sessions, err := h.db.GetSessionsForBatches(ctx, []primitive.ObjectID{*cricketer.BatchID}, now, now.Add(dashboardSessionWindow))
+		if err != nil {
+			return nil, err
+		}
+		child.UpcomingSessions = sessions
+		announcementFilter.BatchIDs = []primitive.ObjectID{*cricketer.BatchID}
+	}
+
+	attendance, err := h.db.GetAttendanceForCricketer(ctx, cricketer.ID, now.Add(-dashboardAttendanceWindow))
+	if err != nil {
+		return nil, err
+	}
+	child.Attendance = attendance
+	for _, record := range attendance {
+		child.AttendanceSummary.Add(record.Status)
+	}
+
+	feePayments, err := h.db.GetFeePayments(ctx, cricketer.ID)
+	if err != nil {
+		return nil, err
+	}
+	child.FeePayments = feePayments
+
+	announcements, err := cricketerAnnouncements(ctx, h.db, cricketer.ID, announcementFilter)
+	if err != nil {
+		return nil, err
+	}
+	child.Announcements = announcements
+	for _, announcement := range announcements {
+		if announcement.RequiresAcknowledgement && announcement.AcknowledgedAt == nil {
+			child.PendingAcknowledgements++
+		}
+	}
+
+	consents, err := consentStatusesFor(ctx, h.db, []primitive.ObjectID{cricketer.ID})
+	if err != nil {
+		return nil, err
+	}
+	for _, status := range consents[cricketer.ID] {
+		if status.Document.Required && status.Status != models.ConsentSigned {
+			child.PendingConsents++
+		}
+	}
+
+	return child, nil
+}
+
+// guardianFromClaims loads the logged-in guardian, rejecting disabled accounts
+func (h *GuardianHandler) guardianFromClaims(w http.ResponseWriter, r *http.Request) (*models.Guardian, bool) {
+	guardianID, err := subjectIDFromClaims(r)
+	if err != nil {
+		http.Error(w, "Invalid token claims", http.StatusUnauthorized)
+		return nil, false
+	}
+
+	guardian, err := h.db.GetGuardianByID(r.Context(), guardianID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "Guardian not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Error fetching guardian", http.StatusInternalServerError)
+		}
+		return nil, false
+	}
+	if !guardian.IsActive {
+		http.Error(w, "Guardian account is disabled", http.StatusForbidden)
+		return nil, false
+	}
+	return guardian, true
+}
+
+// childFromURL loads the cricketer named by {cricketerId}, which must be linked to the guardian
+func (h *GuardianHandler) childFromURL(w http.ResponseWriter, r *http.Request, guardian *models.Guardian) (*models.Cricketer, bool) {
+	cricketerID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "cricketerId"))
+	if err != nil {
+		http.Error(w, "Invalid cricketer ID", http.StatusBadRequest)
+		return nil, false
+	}
+	if !guardian.IsGuardianOf(cricketerID) {
+		http.Error(w, "Cricketer not found", http.StatusNotFound)
+		return nil, false
+	}
+
+	cricketer, err := h.db.GetCricketerByID(r.Context(), cricketerID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "Cricketer not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Error fetching cricketer", http.StatusInternalServerError)
+		}
+		return nil, false
+	}
+	return cricketer, true
+}
+
+// guardianChildren parses and checks the cricketers to link to a guardian
+func (h *GuardianHandler) guardianChildren(w http.ResponseWriter, r *http.Request, hexIDs []string) ([]primitive.ObjectID, bool) {
+	ids := make([]primitive.ObjectID, 0, len(hexIDs))
+	for i, hexID := range hexIDs {
+		field := fmt.Sprintf("cricketerIds[%d]", i)
+		id, err := primitive.ObjectIDFromHex(hexID)
+		if err != nil {
+			writeFieldError(w, field, "objectid", "must be a valid ID")
+			return nil, false
+		}
+		if _, err := h.db.GetCricketerByID(r.Context(), id); err != nil {
+			if err == mongo.ErrNoDocuments {
+				writeFieldError(w, field, "exists", "cricketer not found")
+			} else {
+				http.Error(w, "Error fetching cricketer", http.StatusInternalServerError)
+			}
+			return nil, false
+		}
+		ids = append(ids, id)
+	}
+	return uniqueObjectIDs(ids), true
+}
+
+func startOfDay(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,326 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"cricketApp/db"
+	"cricketApp/idcard"
+	"cricketApp/models"
+	"cricketApp/pii"
+	"cricketApp/storage"
+)
+
+// checkInOpensBefore is how long before a session starts cricketers can check in
+const checkInOpensBefore = 30 * time.Minute
+
+// IDCardHandler issues player ID cards and checks cricketers in to sessions with them
+type IDCardHandler struct {
+	db     db.Database
+	store  storage.BlobStore
+	signer *idcard.Signer
+	title  string
+}
+
+func NewIDCardHandler(db db.Database, store storage.BlobStore, keys pii.KeyProvider) *IDCardHandler {
+	title := os.Getenv("ACADEMY_NAME")
+	if title == "" {
+		title = "Cricket Academy"
+	}
+	return &IDCardHandler{db: db, store: store, signer: idcard.NewSigner(keys), title: title}
+}
+
+// GetIDCard downloads a cricketer's ID card as a PDF, issuing one for the current season if they
+// don't have a valid card. Coaches can only get cards for cricketers in their batches.
+func (h *IDCardHandler) GetIDCard(w http.ResponseWriter, r *http.Request) {
+	cricketer, ok := staffCricketerFromURL(w, r, h.db)
+	if !ok {
+		return
+	}
+	h.writeIDCard(w, r, cricketer)
+}
+
+// GetOwnIDCard downloads the logged-in cricketer's ID card as a PDF
+func (h *IDCardHandler) GetOwnIDCard(w http.ResponseWriter, r *http.Request) {
+	cricketerID, err := subjectIDFromClaims(r)
+	if err != nil {
+		http.Error(w, "Invalid token claims", http.StatusUnauthorized)
+		return
+	}
+	cricketer, err := h.db.GetCricketerByID(r.Context(), cricketerID)
+	if err != nil {
+		http.Error(w, "Error fetching cricketer", http.StatusInternalServerError)
+		return
+	}
+	h.writeIDCard(w, r, cricketer)
+}
+
+// ReissueIDCard replaces a cricketer's ID card, e.g. when it is lost. The QR codes on earlier
+// cards stop working straight away (admin only).
+func (h *IDCardHandler) ReissueIDCard(w http.ResponseWriter, r *http.Request) {
+	cricketer, ok := staffCricketerFromURL(w, r, h.db)
+	if !ok {
+		return
+	}
+
+	var req models.ReissueIDCardRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	if cricketer.InactiveCricketer {
+		http.Error(w, "Inactive cricketers can't be issued an ID card", http.StatusConflict)
+		return
+	}
+
+	card, err := h.issueIDCard(r, cricketer, strings.TrimSpace(req.Reason))
+	if err != nil {
+		http.Error(w, "Error issuing ID card", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "ID card reissued; earlier cards no longer work",
+		"card":    card,
+	})
+}
+
+// CheckIn marks a cricketer present at a session by scanning the QR code on their ID card. The
+// card must be genuine, unexpired and the latest issued, check-in must be open, and the cricketer
+// must be active, enrolled in the session and not injured. A session for a batch enrolls its
+// members; a session without one only those already on its attendance list. Coaches can check in
+// to sessions they run or that belong to one of their batches.
+func (h *IDCardHandler) CheckIn(w http.ResponseWriter, r *http.Request) {
+	session, ok := staffSessionFromURL(w, r, h.db)
+	if !ok {
+		return
+	}
+
+	var req models.CheckInRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	now := time.Now()
+	if now.Before(session.StartTime.Add(-checkInOpensBefore)) || now.After(session.EndTime) {
+		http.Error(w, fmt.Sprintf("Check-in is open from %d minutes before the session starts until it ends", int(checkInOpensBefore.Minutes())), http.StatusConflict)
+		return
+	}
+
+	claims, err := h.signer.Verify(req.Token)
+	switch err {
+	case nil:
+	case idcard.ErrTokenExpired:
+		writeFieldError(w, "token", "expired", "this ID card expired on "+claims.ExpiresAt.Format("2 Jan 2006"))
+		return
+	default:
+		writeFieldError(w, "token", "signature", "this is not a valid academy ID card")
+		return
+	}
+
+	card, err := h.db.GetIDCard(r.Context(), claims.CricketerID)
+	if err != nil && err != mongo.ErrNoDocuments {
+		http.Error(w, "Error fetching ID card", http.StatusInternalServerError)
+		return
+	}
+	if card == nil || card.Version != claims.Version {
+		writeFieldError(w, "token", "replaced", "this ID card has been replaced by a newer one")
+		return
+	}
+
+	cricketer, err := h.db.GetCricketerByID(r.Context(), claims.CricketerID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			writeFieldError(w, "token", "exists", "the cricketer on this card no longer exists")
+		} else {
+			http.Error(w, "Error fetching cricketer", http.StatusInternalServerError)
+		}
+		return
+	}
+	if cricketer.InactiveCricketer {
+		http.Error(w, cricketer.Name+" is inactive", http.StatusConflict)
+		return
+	}
+	if session.BatchID != nil && (cricketer.BatchID == nil || *cricketer.BatchID != *session.BatchID) {
+		http.Error(w, cricketer.Name+" is not in this session's batch", http.StatusConflict)
+		return
+	}
+
+	attendance, err := h.db.GetAttendanceForSession(r.Context(), session.ID)
+	if err != nil {
+		http.Error(w, "Error fetching attendance", http.StatusInternalServerError)
+		return
+	}
+	var existing *models.Attendance
+	for i := range attendance {
+		if attendance[i].CricketerID == cricketer.ID {
+			existing = &attendance[i]
+			break
+		}
+	}
+	if session.BatchID == nil && existing == nil {
+		http.Error(w, cricketer.Name+" is not on this session's attendance list", http.StatusConflict)
+		return
+	}
+	if existing != nil && (existing.Status == models.AttendancePresent || existing.Status == models.AttendanceLate) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"message":    cricketer.Name + " is already checked in",
+			"attendance": existing,
+		})
+		return
+	}
+
+	injuries, err := activeInjuries(r.Context(), h.db, []primitive.ObjectID{cricketer.ID})
+	if err != nil {
+		http.Error(w, "Error fetching injuries", http.StatusInternalServerError)
+		return
+	}
+	if injury, ok := injuries[cricketer.ID]; ok && injury.BlocksTraining() {
+		http.Error(w, cricketer.Name+" is "+injury.Status+" and not cleared to train", http.StatusConflict)
+		return
+	}
+
+	coachID, err := subjectIDFromClaims(r)
+	if err != nil {
+		http.Error(w, "Invalid token claims", http.StatusUnauthorized)
+		return
+	}
+	record := models.Attendance{
+		SessionID:    session.ID,
+		CricketerID:  cricketer.ID,
+		SessionDate:  session.StartTime,
+		Status:       models.AttendancePresent,
+		Note:         "Checked in with ID card",
+		MarkedBy:     coachID.Hex(),
+		MarkedByRole: roleFromClaims(r),
+		MarkedAt:     now,
+	}
+	if err := h.db.SaveAttendance(r.Context(), []models.Attendance{record}); err != nil {
+		http.Error(w, "Error saving attendance", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":    cricketer.Name + " checked in",
+		"attendance": record,
+	})
+}
+
+// writeIDCard responds with the cricketer's ID card as a PDF
+func (h *IDCardHandler) writeIDCard(w http.ResponseWriter, r *http.Request, cricketer *models.Cricketer) {
+	if cricketer.InactiveCricketer {
+		http.Error(w, "Inactive cricketers can't be issued an ID card", http.StatusConflict)
+		return
+	}
+
+	card, err := h.db.GetIDCard(r.Context(), cricketer.ID)
+	if err != nil && err != mongo.ErrNoDocuments {
+		http.Error(w, "Error fetching ID card", http.StatusInternalServerError)
+		return
+	}
+	if card == nil || time.Now().After(card.ValidUntil) {
+		if card, err = h.issueIDCard(r, cricketer, ""); err != nil {
+			http.Error(w, "Error issuing ID card", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	printed, err := h.printedCard(r.Context(), cricketer, card)
+	if err != nil {
+		log.Printf("Error preparing ID card for cricketer %s: %v", cricketer.ID.Hex(), err)
+		http.Error(w, "Error preparing ID card", http.StatusInternalServerError)
+		return
+	}
+	var pdf bytes.Buffer
+	if err := idcard.WritePDF(&pdf, printed); err != nil {
+		log.Printf("Error drawing ID card for cricketer %s: %v", cricketer.ID.Hex(), err)
+		http.Error(w, "Error preparing ID card", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Length", strconv.Itoa(pdf.Len()))
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", "id-card-"+cricketer.ID.Hex()+".pdf"))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Write(pdf.Bytes())
+}
+
+// issueIDCard issues a card valid until the end of the current season. A reason reissues the
+// card under a new version; without one an expired card is renewed under the same version.
+func (h *IDCardHandler) issueIDCard(r *http.Request, cricketer *models.Cricketer, reason string) (*models.IDCard, error) {
+	issuedBy, err := subjectIDFromClaims(r)
+	if err != nil {
+		return nil, err
+	}
+	season, err := seasonAt(r.Context(), h.db, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	card, err := h.db.GetIDCard(r.Context(), cricketer.ID)
+	if err == mongo.ErrNoDocuments {
+		card = &models.IDCard{CricketerID: cricketer.ID}
+	} else if err != nil {
+		return nil, err
+	}
+	if card.Version == 0 || reason != "" {
+		card.Version++
+	}
+	card.ValidUntil = season.EndDate
+	card.IssuedBy = issuedBy.Hex()
+	card.IssuedAt = time.Now()
+	card.ReissueReason = reason
+
+	if err := h.db.SaveIDCard(r.Context(), card); err != nil {
+		return nil, err
+	}
+	return card, nil
+}
+
+// printedCard gathers what goes on a cricketer's card and signs its QR token
+func (h *IDCardHandler) printedCard(ctx context.Context, cricketer *models.Cricketer, card *models.IDCard) (*idcard.Card, error) {
+	token, err := h.signer.Sign(idcard.Claims{CricketerID: cricketer.ID, Version: card.Version, ExpiresAt: card.ValidUntil})
+	if err != nil {
+		return nil, err
+	}
+	printed := &idcard.Card{
+		Title:      h.title,
+		Name:       cricketer.Name,
+		Number:     fmt.Sprintf("%s-%d", strings.ToUpper(cricketer.ID.Hex()[16:]), card.Version),
+		ValidUntil: card.ValidUntil,
+		Token:      token,
+	}
+
+	if cricketer.BatchID != nil {
+		batch, err := h.db.GetBatchByID(ctx, *cricketer.BatchID)
+		if err != nil && err != mongo.ErrNoDocuments {
+			return nil, err
+		}
+		if batch != nil {
+			printed.Batch = batch.Name
+		}
+	}
+	season, err := seasonAt(ctx, h.db, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	printed.AgeCategory = ageCategoryAt(cricketer.DateOfBirth, season)
+
+	if printed.Photo, err = profilePhotoJPEG(ctx, h.db, h.store, cricketer.PhotoID); err != nil {
+		return nil, err
+	}
+	return printed, nil
+}
@@ -0,0 +1,430 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"cricketApp/db"
+	"cricketApp/models"
+)
+
+// InjuryHandler records cricketers' injuries and clears them back to play
+type InjuryHandler struct {
+	db db.Database
+}
+
+func NewInjuryHandler(db db.Database) *InjuryHandler {
+	return &InjuryHandler{db: db}
+}
+
+// CreateInjury reports an injury to a cricketer. Coaches can only report injuries to cricketers they coach.
+// The cricketer is kept out of sessions and squads until cleared.
+func (h *InjuryHandler) CreateInjury(w http.ResponseWriter, r *http.Request) {
+	userID, err := subjectIDFromClaims(r)
+	if err != nil {
+		http.Error(w, "Invalid token claims", http.StatusUnauthorized)
+		return
+	}
+	cricketer, ok := staffCricketerFromURL(w, r, h.db)
+	if !ok {
+		return
+	}
+
+	var req models.CreateInjuryRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	role := roleFromClaims(r)
+	name, err := staffName(r.Context(), h.db, userID, role)
+	if err != nil {
+		http.Error(w, "Error fetching reporter", http.StatusInternalServerError)
+		return
+	}
+
+	injury := &models.Injury{
+		CricketerID:   cricketer.ID,
+		CricketerName: cricketer.Name,
+		BatchID:       cricketer.BatchID,
+		BodyPart:      req.BodyPart,
+		Type:          req.Type,
+		Severity:      req.Severity,
+		Mechanism:     req.Mechanism,
+		Description:   strings.TrimSpace(req.Description),
+		InjuredOn:     req.InjuredOn,
+		Status:        models.InjuryInjured,
+		History: []models.InjuryStatusChange{{
+			Status:        models.InjuryInjured,
+			ChangedBy:     userID.Hex(),
+			ChangedByName: name,
+			ChangedByRole: role,
+			ChangedAt:     time.Now(),
+		}},
+		ReportedBy:     userID.Hex(),
+		ReportedByRole: role,
+	}
+
+	if err := h.db.CreateInjury(r.Context(), injury); err != nil {
+		http.Error(w, "Error creating injury", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Injury recorded successfully",
+		"injury":  injury,
+	})
+}
+
+// GetCricketerInjuries returns a cricketer's injury history, most recent first
+func (h *InjuryHandler) GetCricketerInjuries(w http.ResponseWriter, r *http.Request) {
+	cricketer, ok := staffCricketerFromURL(w, r, h.db)
+	if !ok {
+		return
+	}
+
+	injuries, err := h.db.GetInjuries(r.Context(), models.InjuryFilter{CricketerID: &cricketer.ID})
+	if err != nil {
+		http.Error(w, "Error fetching injuries", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(injuries)
+}
+
+// GetInjuries lists injuries, most recent first, optionally only ?active ones or for ?batchId.
+// Coaches see injuries in their batches.
+func (h *InjuryHandler) GetInjuries(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := models.InjuryFilter{}
+	filter.ActiveOnly, _ = strconv.ParseBool(query.Get("active"))
+	if value := query.Get("batchId"); value != "" {
+		batchID, err := primitive.ObjectIDFromHex(value)
+		if err != nil {
+			http.Error(w, "Invalid batch ID", http.StatusBadRequest)
+			return
+		}
+		filter.BatchIDs = []primitive.ObjectID{batchID}
+	}
+
+	if roleFromClaims(r) == "coach" {
+		batchIDs, ok := coachBatchIDs(w, r, h.db)
+		if !ok {
+			return
+		}
+		if filter.BatchIDs != nil && !containsObjectID(batchIDs, filter.BatchIDs[0]) {
+			http.Error(w, "Batch not found", http.StatusNotFound)
+			return
+		}
+		if filter.BatchIDs == nil {
+			filter.BatchIDs = batchIDs
+		}
+	}
+
+	injuries, err := h.db.GetInjuries(r.Context(), filter)
+	if err != nil {
+		http.Error(w, "Error fetching injuries", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(injuries)
+}
+
+// GetInjury returns an injury with its status history
+func (h *InjuryHandler) GetInjury(w http.ResponseWriter, r *http.Request) {
+	injury, ok := h.injuryFromURL(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(injury)
+}
+
+// UpdateInjuryStatus moves an injury along the return-to-play flow, recording who made the change.
+// Clearing a cricketer, with or without restrictions, records them as the clearer.
+func (h *InjuryHandler) UpdateInjuryStatus(w http.ResponseWriter, r *http.Request) {
+	userID, err := subjectIDFromClaims(r)
+	if err != nil {
+		http.Error(w, "Invalid token claims", http.StatusUnauthorized)
+		return
+	}
+	injury, ok := h.injuryFromURL(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateInjuryStatusRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	restrictions := strings.TrimSpace(req.Restrictions)
+	if req.Status == models.InjuryClearedRestricted && restrictions == "" {
+		writeFieldError(w, "restrictions", "required", "is required when clearing with restrictions")
+		return
+	}
+	if !injury.CanMoveTo(req.Status) {
+		http.Error(w, "An injury that is "+injury.Status+" cannot move to "+req.Status, http.StatusConflict)
+		return
+	}
+
+	role := roleFromClaims(r)
+	name, err := staffName(r.Context(), h.db, userID, role)
+	if err != nil {
+		http.Error(w, "Error fetching user", http.StatusInternalServerError)
+		return
+	}
+
+	now := time.Now()
+	previousStatus := injury.Status
+	injury.Status = req.Status
+	injury.Restrictions = ""
+	if req.Status == models.InjuryClearedRestricted {
+		injury.Restrictions = restrictions
+	}
+	if req.Status == models.InjuryClearedRestricted || req.Status == models.InjuryFullyFit {
+		injury.ClearedBy = userID.Hex()
+		injury.ClearedByName = name
+		injury.ClearedAt = &now
+	} else {
+		// A setback withdraws the earlier clearance
+		injury.ClearedBy = ""
+		injury.ClearedByName = ""
+		injury.ClearedAt = nil
+	}
+	injury.History = append(injury.History, models.InjuryStatusChange{
+		Status:        req.Status,
+		Restrictions:  injury.Restrictions,
+		Note:          strings.TrimSpace(req.Note),
+		ChangedBy:     userID.Hex(),
+		ChangedByName: name,
+		ChangedByRole: role,
+		ChangedAt:     now,
+	})
+
+	if err := h.db.UpdateInjuryStatus(r.Context(), injury.ID, previousStatus, injury); err != nil {
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "The injury's status has changed; reload and try again", http.StatusConflict)
+		} else {
+			http.Error(w, "Error updating injury", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Injury status updated successfully",
+		"injury":  injury,
+	})
+}
+
+// GetInjuryReport summarises the injuries that happened this season, or between ?from and ?to,
+// by injury type and by batch (admin only)
+func (h *InjuryHandler) GetInjuryReport(w http.ResponseWriter, r *http.Request) {
+	season, err := seasonAt(r.Context(), h.db, time.Now())
+	if err != nil {
+		http.Error(w, "Error fetching season", http.StatusInternalServerError)
+		return
+	}
+	from := season.StartDate
+	end := season.EndDate.Add(time.Nanosecond)
+
+	query := r.URL.Query()
+	if query.Get("from") != "" || query.Get("to") != "" {
+		fromDate, errFrom := time.Parse("2006-01-02", query.Get("from"))
+		toDate, errTo := time.Parse("2006-01-02", query.Get("to"))
+		if errFrom != nil || errTo != nil {
+			http.Error(w, "Invalid period, expected from and to dates as YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+		if toDate.Before(fromDate) {
+			http.Error(w, "Invalid period, to is before from", http.StatusBadRequest)
+			return
+		}
+		from = fromDate
+		end = toDate.AddDate(0, 0, 1)
+	}
+
+	injuries, err := h.db.GetInjuries(r.Context(), models.InjuryFilter{From: &from, To: &end})
+	if err != nil {
+		http.Error(w, "Error fetching injuries", http.StatusInternalServerError)
+		return
+	}
+	batches, err := h.db.GetAllBatches(r.Context())
+	if err != nil {
+		http.Error(w, "Error fetching batches", http.StatusInternalServerError)
+		return
+	}
+
+	report := injuryReport(injuries, batches)
+	report.From = from
+	report.To = end.Add(-time.Nanosecond)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+// injuryReport groups injuries by type and by the batch the cricketer was in when injured
+func injuryReport(injuries []models.Injury, batches []models.Batch) *models.InjuryReport {
+	batchNames := make(map[primitive.ObjectID]string, len(batches))
+	for _, batch := range batches {
+		batchNames[batch.ID] = batch.Name
+	}
+
+	overall := &injuryTally{}
+	byType := make(map[string]*injuryTally)
+	byBatch := make(map[primitive.ObjectID]*injuryTally)
+	batchTypes := make(map[primitive.ObjectID]map[string]int)
+	for _, injury := range injuries {
+		overall.add(injury)
+		if byType[injury.Type] == nil {
+			byType[injury.Type] = &injuryTally{}
+		}
+		byType[injury.Type].add(injury)
+
+		var batchID primitive.ObjectID // zero for cricketers without a batch
+		if injury.BatchID != nil {
+			batchID = *injury.BatchID
+		}
+		if byBatch[batchID] == nil {
+			byBatch[batchID] = &injuryTally{}
+			batchTypes[batchID] = make(map[string]int)
+		}
+		byBatch[batchID].add(injury)
+		batchTypes[batchID][injury.Type]++
+	}
+
+	report := &models.InjuryReport{
+		Overall: overall.count(),
+		ByType:  []models.InjuryTypeSummary{},
+		ByBatch: []models.InjuryBatchSummary{},
+	}
+	for injuryType, tally := range byType {
+		report.ByType = append(report.ByType, models.InjuryTypeSummary{Type: injuryType, InjuryCount: tally.count()})
+	}
+	sort.Slice(report.ByType, func(i, j int) bool {
+		if report.ByType[i].Total != report.ByType[j].Total {
+			return report.ByType[i].Total > report.ByType[j].Total
+		}
+		return report.ByType[i].Type < report.ByType[j].Type
+	})
+
+	for batchID, tally := range byBatch {
+		summary := models.InjuryBatchSummary{BatchName: "No batch", InjuryCount: tally.count(), ByType: batchTypes[batchID]}
+		if !batchID.IsZero() {
+			id := batchID
+			summary.BatchID = &id
+			summary.BatchName = batchNames[batchID]
+		}
+		report.ByBatch = append(report.ByBatch, summary)
+	}
+	sort.Slice(report.ByBatch, func(i, j int) bool {
+		if report.ByBatch[i].Total != report.ByBatch[j].Total {
+			return report.ByBatch[i].Total > report.ByBatch[j].Total
+		}
+		return report.ByBatch[i].BatchName < report.ByBatch[j].BatchName
+	})
+	return report
+}
+
+// injuryTally accumulates an InjuryCount
+type injuryTally struct {
+	models.InjuryCount
+	healed  int
+	daysOut float64
+}
+
+func (t *injuryTally) add(injury models.Injury) {
+	t.Total++
+	if injury.Active() {
+		t.Active++
+	}
+	switch injury.Severity {
+	case models.InjuryMinor:
+		t.Minor++
+	case models.InjuryModerate:
+		t.Moderate++
+	case models.InjurySevere:
+		t.Severe++
+	}
+	if !injury.Active() && len(injury.History) > 0 {
+		fit := injury.History[len(injury.History)-1].ChangedAt
+		t.healed++
+		t.daysOut += math.Max(0, fit.Sub(injury.InjuredOn).Hours()/24)
+	}
+}
+
+func (t *injuryTally) count() models.InjuryCount {
+	count := t.InjuryCount
+	if t.healed > 0 {
+		average := math.Round(t.daysOut/float64(t.healed)*10) / 10
+		count.AverageDaysOut = &average
+	}
+	return count
+}
+
+func (h *InjuryHandler) injuryFromURL(w http.ResponseWriter, r *http.Request) (*models.Injury, bool) {
+	injuryID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid injury ID", http.StatusBadRequest)
+		return nil, false
+	}
+
+	injury, err := h.db.GetInjuryByID(r.Context(), injuryID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "Injury not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Error fetching injury", http.StatusInternalServerError)
+		}
+		return nil, false
+	}
+	// Coaches only see injuries of cricketers they coach
+	if _, ok := staffCricketer(w, r, h.db, injury.CricketerID); !ok {
+		return nil, false
+	}
+	return injury, true
+}
+
+// activeInjuries returns the injuries keeping each of the given cricketers out, keyed by cricketer.
+// Sessions and squads use it to leave out injured cricketers.
+func activeInjuries(ctx context.Context, database db.Database, cricketerIDs []primitive.ObjectID) (map[primitive.ObjectID]models.Injury, error) {
+	injuries, err := database.GetActiveInjuries(ctx, cricketerIDs)
+	if err != nil {
+		return nil, err
+	}
+	active := make(map[primitive.ObjectID]models.Injury, len(injuries))
+	for _, injury := range injuries {
+		// A cricketer with more than one open injury is held back by the least recovered
+		if current, ok := active[injury.CricketerID]; !ok || injuryStage(injury.Status) < injuryStage(current.Status) {
+			active[injury.CricketerID] = injury
+		}
+	}
+	return active, nil
+}
+
+// injuryStage orders injury statuses along the return-to-play flow
+func injuryStage(status string) int {
+	switch status {
+	case models.InjuryInjured:
+		return 0
+	case models.InjuryRehab:
+		return 1
+	case models.InjuryClearedRestricted:
+		return 2
+	}
+	return 3
+}
@@ -0,0 +1,965 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"cricketApp/db"
+	"cricketApp/models"
+)
+
+// leagueSchedulingDays is how far past the start date fixtures are looked for
+const leagueSchedulingDays = 180
+
+// LeagueHandler manages internal leagues: teams, fixtures, results, the points table and knockouts
+type LeagueHandler struct {
+	db db.Database
+}
+
+func NewLeagueHandler(db db.Database) *LeagueHandler {
+	return &LeagueHandler{db: db}
+}
+
+// CreateLeague adds a league with its teams. A cricketer can only play for one team (admin only).
+func (h *LeagueHandler) CreateLeague(w http.ResponseWriter, r *http.Request) {
+	adminID, err := subjectIDFromClaims(r)
+	if err != nil {
+		http.Error(w, "Invalid token claims", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.CreateLeagueRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	league := &models.League{
+		Name:            strings.TrimSpace(req.Name),
+		OversPerInnings: req.OversPerInnings,
+		CreatedBy:       adminID.Hex(),
+	}
+	teamNames := map[string]bool{}
+	picked := map[primitive.ObjectID]string{}
+	for i, teamReq := range req.Teams {
+		field := fmt.Sprintf("teams[%d]", i)
+		team := models.LeagueTeam{ID: primitive.NewObjectID(), Name: strings.TrimSpace(teamReq.Name), Players: []models.MatchPlayer{}}
+		if team.Name == "" {
+			writeFieldError(w, field+".name", "required", "name is required")
+			return
+		}
+		if teamNames[strings.ToLower(team.Name)] {
+			writeFieldError(w, field+".name", "unique", "another team is called "+team.Name)
+			return
+		}
+		teamNames[strings.ToLower(team.Name)] = true
+
+		for j, hexID := range teamReq.CricketerIDs {
+			playerField := fmt.Sprintf("%s.cricketerIds[%d]", field, j)
+			cricketerID, err := primitive.ObjectIDFromHex(hexID)
+			if err != nil {
+				writeFieldError(w, playerField, "objectid", "must be a valid ID")
+				return
+			}
+			cricketer, err := h.db.GetCricketerByID(r.Context(), cricketerID)
+			if err != nil {
+				if err == mongo.ErrNoDocuments {
+					writeFieldError(w, playerField, "exists", "cricketer not found")
+				} else {
+					http.Error(w, "Error fetching cricketer", http.StatusInternalServerError)
+				}
+				return
+			}
+			if cricketer.InactiveCricketer {
+				writeFieldError(w, playerField, "active", cricketer.Name+" is inactive")
+				return
+			}
+			if other, ok := picked[cricketerID]; ok {
+				writeFieldError(w, playerField, "unique", cricketer.Name+" is already in "+other)
+				return
+			}
+			picked[cricketerID] = team.Name
+			team.Players = append(team.Players, models.MatchPlayer{CricketerID: &cricketer.ID, Name: cricketer.Name})
+		}
+		league.Teams = append(league.Teams, team)
+	}
+
+	if err := h.db.CreateLeague(r.Context(), league); err != nil {
+		http.Error(w, "Error creating league", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "League created successfully",
+		"league":  league,
+	})
+}
+
+// GetLeagues lists every league, newest first
+func (h *LeagueHandler) GetLeagues(w http.ResponseWriter, r *http.Request) {
+	leagues, err := h.db.GetLeagues(r.Context())
+	if err != nil {
+		http.Error(w, "Error fetching leagues", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(leagues)
+}
+
+// GetLeague returns a league and its teams
+func (h *LeagueHandler) GetLeague(w http.ResponseWriter, r *http.Request) {
+	league, ok := h.leagueFromURL(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(league)
+}
+
+// GetLeagueFixtures lists a league's fixtures, league stage and knockouts, in the order they are played
+func (h *LeagueHandler) GetLeagueFixtures(w http.ResponseWriter, r *http.Request) {
+	league, ok := h.leagueFromURL(w, r)
+	if !ok {
+		return
+	}
+
+	fixtures, err := h.db.GetLeagueFixtures(r.Context(), league.ID)
+	if err != nil {
+		http.Error(w, "Error fetching fixtures", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(fixtures)
+}
+
+// GenerateLeagueFixtures draws up a round-robin schedule and allocates each match a venue and start
+// time that clashes with no other fixture or coaching session. It replaces any earlier schedule, so
+// it is refused once a result has been entered (admin only).
+func (h *LeagueHandler) GenerateLeagueFixtures(w http.ResponseWriter, r *http.Request) {
+	league, ok := h.leagueFromURL(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.GenerateLeagueFixturesRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	allStages := []string{models.LeagueStageLeague, models.LeagueStageQuarterFinal, models.LeagueStageSemiFinal, models.LeagueStageFinal}
+	scheduler, ok := h.newLeagueScheduler(w, r, league, &req.LeagueSchedule, allStages)
+	if !ok {
+		return
+	}
+
+	existing, err := h.db.GetLeagueFixtures(r.Context(), league.ID)
+	if err != nil {
+		http.Error(w, "Error fetching fixtures", http.StatusInternalServerError)
+		return
+	}
+	for _, fixture := range existing {
+		if fixture.Result != nil {
+			http.Error(w, "Results have already been entered for this league", http.StatusConflict)
+			return
+		}
+	}
+
+	teamIDs := make([]primitive.ObjectID, len(league.Teams))
+	for i, team := range league.Teams {
+		teamIDs[i] = team.ID
+	}
+	rounds := roundRobin(teamIDs)
+	if req.DoubleRoundRobin {
+		firstLeg := rounds
+		for _, round := range firstLeg {
+			reversed := make([][2]primitive.ObjectID, len(round))
+			for i, pair := range round {
+				reversed[i] = [2]primitive.ObjectID{pair[1], pair[0]}
+			}
+			rounds = append(rounds, reversed)
+		}
+	}
+
+	fixtures := []models.LeagueFixture{}
+	notBefore := scheduler.start
+	for i, round := range rounds {
+		latest := notBefore
+		for _, pair := range round {
+			home, away := pair[0], pair[1]
+			slot, ok := scheduler.place([]primitive.ObjectID{home, away}, notBefore)
+			if !ok {
+				http.Error(w, fmt.Sprintf("Not enough free slots in the %d days from the start date to schedule round %d", leagueSchedulingDays, i+1), http.StatusConflict)
+				return
+			}
+			fixtures = append(fixtures, models.LeagueFixture{
+				ID:       primitive.NewObjectID(),
+				LeagueID: league.ID,
+				Stage:    models.LeagueStageLeague,
+				Round:    i + 1,
+				HomeID:   &home,
+				AwayID:   &away,
+				StartsAt: slot.start,
+				EndsAt:   slot.end,
+				Venue:    slot.venue,
+			})
+			if slot.start.After(latest) {
+				latest = slot.start
+			}
+		}
+		notBefore = startOfDay(latest)
+	}
+
+	if err := h.db.DeleteLeagueFixtures(r.Context(), league.ID, allStages); err != nil {
+		http.Error(w, "Error replacing fixtures", http.StatusInternalServerError)
+		return
+	}
+	if err := h.db.CreateLeagueFixtures(r.Context(), fixtures); err != nil {
+		http.Error(w, "Error creating fixtures", http.StatusInternalServerError)
+		return
+	}
+	sortLeagueFixtures(fixtures)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":  fmt.Sprintf("%d fixtures scheduled over %d rounds", len(fixtures), len(rounds)),
+		"fixtures": fixtures,
+	})
+}
+
+// GetPointsTable returns the league-stage standings: two points for a win and one for a tie or no
+// result, ranked by points, then wins, then net run rate
+func (h *LeagueHandler) GetPointsTable(w http.ResponseWriter, r *http.Request) {
+	league, ok := h.leagueFromURL(w, r)
+	if !ok {
+		return
+	}
+
+	fixtures, err := h.db.GetLeagueFixtures(r.Context(), league.ID)
+	if err != nil {
+		http.Error(w, "Error fetching fixtures", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(pointsTable(league, fixtures))
+}
+
+// GenerateKnockout seeds the top teams of the completed league stage into a knockout bracket, 1st
+// playing last, and schedules every round. Later rounds are filled in as results come in. An
+// earlier bracket is replaced as long as none of its results have been entered (admin only).
+func (h *LeagueHandler) GenerateKnockout(w http.ResponseWriter, r *http.Request) {
+	league, ok := h.leagueFromURL(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.KnockoutRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	if req.TopN > len(league.Teams) {
+		writeFieldError(w, "topN", "max", fmt.Sprintf("the league only has %d teams", len(league.Teams)))
+		return
+	}
+	knockoutStages := []string{models.LeagueStageQuarterFinal, models.LeagueStageSemiFinal, models.LeagueStageFinal}
+	scheduler, ok := h.newLeagueScheduler(w, r, league, &req.LeagueSchedule, knockoutStages)
+	if !ok {
+		return
+	}
+
+	fixtures, err := h.db.GetLeagueFixtures(r.Context(), league.ID)
+	if err != nil {
+		http.Error(w, "Error fetching fixtures", http.StatusInternalServerError)
+		return
+	}
+	leagueStageFixtures := 0
+	for _, fixture := range fixtures {
+		if fixture.Stage == models.LeagueStageLeague {
+			leagueStageFixtures++
+			if fixture.Result == nil {
+				http.Error(w, "Every league fixture needs a result before the knockouts", http.StatusConflict)
+				return
+			}
+			if day := startOfDay(fixture.StartsAt).AddDate(0, 0, 1); day.After(scheduler.start) {
+				scheduler.start = day
+			}
+		} else if fixture.Result != nil {
+			http.Error(w, "Knockout results have already been entered", http.StatusConflict)
+			return
+		}
+	}
+	if leagueStageFixtures == 0 {
+		http.Error(w, "The league has no fixtures yet", http.StatusConflict)
+		return
+	}
+
+	table := pointsTable(league, fixtures)
+	stages := knockoutRounds(req.TopN)
+	order := seedOrder(req.TopN)
+
+	// Each fixture can be reached by the teams feeding into it, which decides whose sessions it must avoid
+	var bracket [][]models.LeagueFixture
+	var contenders [][][]primitive.ObjectID
+	notBefore := scheduler.start
+	for round, stage := range stages {
+		matches := req.TopN >> (round + 1)
+		roundFixtures := make([]models.LeagueFixture, matches)
+		roundContenders := make([][]primitive.ObjectID, matches)
+		latest := notBefore
+		for i := range roundFixtures {
+			fixture := models.LeagueFixture{ID: primitive.NewObjectID(), LeagueID: league.ID, Stage: stage, Round: round + 1}
+			if round == 0 {
+				home, away := table[order[2*i]-1], table[order[2*i+1]-1]
+				fixture.HomeID, fixture.HomeSeed = &home.TeamID, home.Position
+				fixture.AwayID, fixture.AwaySeed = &away.TeamID, away.Position
+				roundContenders[i] = []primitive.ObjectID{home.TeamID, away.TeamID}
+			} else {
+				previous := contenders[round-1]
+				roundContenders[i] = append(append([]primitive.ObjectID{}, previous[2*i]...), previous[2*i+1]...)
+				bracket[round-1][2*i].Next = &models.BracketSlot{FixtureID: fixture.ID, Home: true}
+				bracket[round-1][2*i+1].Next = &models.BracketSlot{FixtureID: fixture.ID, Home: false}
+			}
+
+			slot, ok := scheduler.place(roundContenders[i], notBefore)
+			if !ok {
+				http.Error(w, fmt.Sprintf("Not enough free slots in the %d days from the start date to schedule the %s", leagueSchedulingDays, strings.ReplaceAll(stage, "_", "-")), http.StatusConflict)
+				return
+			}
+			fixture.StartsAt, fixture.EndsAt, fixture.Venue = slot.start, slot.end, slot.venue
+			if slot.start.After(latest) {
+				latest = slot.start
+			}
+			roundFixtures[i] = fixture
+		}
+		bracket = append(bracket, roundFixtures)
+		contenders = append(contenders, roundContenders)
+		notBefore = startOfDay(latest).AddDate(0, 0, 1)
+	}
+
+	knockout := []models.LeagueFixture{}
+	for _, roundFixtures := range bracket {
+		knockout = append(knockout, roundFixtures...)
+	}
+	if err := h.db.DeleteLeagueFixtures(r.Context(), league.ID, knockoutStages); err != nil {
+		http.Error(w, "Error replacing knockout fixtures", http.StatusInternalServerError)
+		return
+	}
+	if err := h.db.CreateLeagueFixtures(r.Context(), knockout); err != nil {
+		http.Error(w, "Error creating knockout fixtures", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":  fmt.Sprintf("Knockout bracket for the top %d created", req.TopN),
+		"fixtures": knockout,
+	})
+}
+
+// RecordLeagueResult enters or corrects a fixture's result. The points table follows from the
+// results, and a knockout winner moves into their next fixture.
+func (h *LeagueHandler) RecordLeagueResult(w http.ResponseWriter, r *http.Request) {
+	fixtureID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid fixture ID", http.StatusBadRequest)
+		return
+	}
+	fixture, err := h.db.GetLeagueFixtureByID(r.Context(), fixtureID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "Fixture not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Error fetching fixture", http.StatusInternalServerError)
+		}
+		return
+	}
+	league, err := h.db.GetLeagueByID(r.Context(), fixture.LeagueID)
+	if err != nil {
+		http.Error(w, "Error fetching league", http.StatusInternalServerError)
+		return
+	}
+
+	var req models.LeagueResultRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	if fixture.HomeID == nil || fixture.AwayID == nil {
+		http.Error(w, "Both teams for this fixture aren't known yet", http.StatusConflict)
+		return
+	}
+	if fixture.StartsAt.After(time.Now()) {
+		http.Error(w, "The fixture hasn't started yet", http.StatusConflict)
+		return
+	}
+
+	result, ok := buildLeagueResult(w, league, fixture, &req)
+	if !ok {
+		return
+	}
+
+	if fixture.Stage == models.LeagueStageLeague {
+		fixtures, err := h.db.GetLeagueFixtures(r.Context(), league.ID)
+		if err != nil {
+			http.Error(w, "Error fetching fixtures", http.StatusInternalServerError)
+			return
+		}
+		for _, other := range fixtures {
+			if other.Stage != models.LeagueStageLeague {
+				http.Error(w, "The knockout bracket has been drawn, so league results can't change", http.StatusConflict)
+				return
+			}
+		}
+	}
+
+	var next *models.LeagueFixture
+	if fixture.Next != nil {
+		next, err = h.db.GetLeagueFixtureByID(r.Context(), fixture.Next.FixtureID)
+		if err != nil {
+			http.Error(w, "Error fetching next fixture", http.StatusInternalServerError)
+			return
+		}
+		if next.Result != nil && (fixture.Result == nil || fixture.Result.WinnerID == nil || *fixture.Result.WinnerID != *result.WinnerID) {
+			http.Error(w, "The next round has already been played, so the winner can't change", http.StatusConflict)
+			return
+		}
+	}
+
+	userID, err := subjectIDFromClaims(r)
+	if err != nil {
+		http.Error(w, "Invalid token claims", http.StatusUnauthorized)
+		return
+	}
+	result.RecordedBy = userID.Hex()
+	result.RecordedAt = time.Now()
+
+	if err := h.db.SaveLeagueResult(r.Context(), fixture.ID, result); err != nil {
+		http.Error(w, "Error saving result", http.StatusInternalServerError)
+		return
+	}
+	if next != nil {
+		if err := h.db.SetLeagueFixtureTeam(r.Context(), *fixture.Next, result.WinnerID); err != nil {
+			http.Error(w, "Error advancing the winner", http.StatusInternalServerError)
+			return
+		}
+	}
+	fixture.Result = result
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Result recorded successfully",
+		"fixture": fixture,
+	})
+}
+
+// buildLeagueResult checks a result against the fixture's teams and the league's rules, writing a
+// field error and returning false when it doesn't add up
+func buildLeagueResult(w http.ResponseWriter, league *models.League, fixture *models.LeagueFixture, req *models.LeagueResultRequest) (*models.LeagueResult, bool) {
+	knockout := fixture.Stage != models.LeagueStageLeague
+	result := &models.LeagueResult{Status: req.Status, Innings: []models.LeagueInnings{}}
+
+	if req.Status != models.LeagueResultNoResult && len(req.Innings) != 2 {
+		writeFieldError(w, "innings", "len", "both innings are needed for a "+strings.ReplaceAll(req.Status, "_", " ")+" match")
+		return nil, false
+	}
+	seen := map[primitive.ObjectID]bool{}
+	for i, inningsReq := range req.Innings {
+		field := fmt.Sprintf("innings[%d]", i)
+		teamID, _ := primitive.ObjectIDFromHex(inningsReq.TeamID)
+		if teamID != *fixture.HomeID && teamID != *fixture.AwayID {
+			writeFieldError(w, field+".teamId", "fixture", "team isn't playing in this fixture")
+			return nil, false
+		}
+		if seen[teamID] {
+			writeFieldError(w, field+".teamId", "unique", "each team bats once")
+			return nil, false
+		}
+		seen[teamID] = true
+		team := league.Team(teamID)
+
+		balls, ok := parseOvers(inningsReq.Overs)
+		if !ok {
+			writeFieldError(w, field+".overs", "overs", "overs must look like 19 or 19.4")
+			return nil, false
+		}
+		allotted := inningsReq.AllottedOvers
+		if allotted == 0 {
+			allotted = league.OversPerInnings
+		}
+		if balls > allotted*6 {
+			writeFieldError(w, field+".overs", "max", fmt.Sprintf("no more than %d overs can be bowled", allotted))
+			return nil, false
+		}
+		if inningsReq.Wickets > team.MaxWickets() {
+			writeFieldError(w, field+".wickets", "max", fmt.Sprintf("%s can only lose %d wickets", team.Name, team.MaxWickets()))
+			return nil, false
+		}
+		result.Innings = append(result.Innings, models.LeagueInnings{
+			TeamID:        teamID,
+			Runs:          inningsReq.Runs,
+			Wickets:       inningsReq.Wickets,
+			Balls:         balls,
+			Overs:         models.FormatOversFromBalls(balls),
+			AllottedOvers: allotted,
+			AllOut:        inningsReq.Wickets == team.MaxWickets(),
+		})
+	}
+
+	var winnerID *primitive.ObjectID
+	if req.WinnerTeamID != "" {
+		id, _ := primitive.ObjectIDFromHex(req.WinnerTeamID)
+		if id != *fixture.HomeID && id != *fixture.AwayID {
+			writeFieldError(w, "winnerTeamId", "fixture", "team isn't playing in this fixture")
+			return nil, false
+		}
+		winnerID = &id
+	}
+
+	switch req.Status {
+	case models.LeagueResultCompleted:
+		first, second := result.Innings[0], result.Innings[1]
+		if first.Runs == second.Runs {
+			writeFieldError(w, "status", "tie", "the scores are level, so the match is a tie")
+			return nil, false
+		}
+		winner := first
+		if second.Runs > first.Runs {
+			winner = second
+		}
+		if winnerID != nil && *winnerID != winner.TeamID {
+			writeFieldError(w, "winnerTeamId", "score", "the winner must be the team with more runs")
+			return nil, false
+		}
+		result.WinnerID = &winner.TeamID
+		if winner.TeamID == first.TeamID {
+			result.Summary = fmt.Sprintf("%s won by %s", league.Team(first.TeamID).Name, plural(first.Runs-second.Runs, "run"))
+		} else {
+			team := league.Team(second.TeamID)
+			result.Summary = fmt.Sprintf("%s won by %s", team.Name, plural(team.MaxWickets()-second.Wickets, "wicket"))
+		}
+
+	case models.LeagueResultTie, models.LeagueResultNoResult:
+		if req.Status == models.LeagueResultTie && result.Innings[0].Runs != result.Innings[1].Runs {
+			writeFieldError(w, "status", "tie", "a tie needs both teams on the same score")
+			return nil, false
+		}
+		result.Summary = "Match tied"
+		if req.Status == models.LeagueResultNoResult {
+			result.Summary = "No result"
+		}
+		if !knockout {
+			if winnerID != nil {
+				writeFieldError(w, "winnerTeamId", "knockout", "a winner can only be named for knockout fixtures")
+				return nil, false
+			}
+			break
+		}
+		if winnerID == nil {
+			writeFieldError(w, "winnerTeamId", "required", "a knockout fixture needs a team to go through")
+			return nil, false
+		}
+		result.WinnerID = winnerID
+		if req.Status == models.LeagueResultTie {
+			result.Summary += fmt.Sprintf(", %s won the super over", league.Team(*winnerID).Name)
+		} else {
+			result.Summary += fmt.Sprintf(", %s go through", league.Team(*winnerID).Name)
+		}
+	}
+	return result, true
+}
+
+// pointsTable ranks a league's teams on their league-stage results. In the net run rate a team that
+// is bowled out counts as having used its full quota of overs, and no-result matches are left out.
+func pointsTable(league *models.League, fixtures []models.LeagueFixture) []models.PointsTableRow {
+	type tally struct {
+		row         models.PointsTableRow
+		ballsFaced  int
+		ballsBowled int
+	}
+	tallies := make(map[primitive.ObjectID]*tally, len(league.Teams))
+	for _, team := range league.Teams {
+		tallies[team.ID] = &tally{row: models.PointsTableRow{TeamID: team.ID, TeamName: team.Name}}
+	}
+
+	for _, fixture := range fixtures {
+		if fixture.Stage != models.LeagueStageLeague || fixture.Result == nil || fixture.HomeID == nil || fixture.AwayID == nil {
+			continue
+		}
+		home, away := tallies[*fixture.HomeID], tallies[*fixture.AwayID]
+		if home == nil || away == nil {
+			continue
+		}
+		home.row.Played++
+		away.row.Played++
+
+		result := fixture.Result
+		switch result.Status {
+		case models.LeagueResultCompleted:
+			winner, loser := home, away
+			if *result.WinnerID == *fixture.AwayID {
+				winner, loser = away, home
+			}
+			winner.row.Won++
+			winner.row.Points += models.LeaguePointsWin
+			loser.row.Lost++
+		case models.LeagueResultTie:
+			home.row.Tied++
+			away.row.Tied++
+			home.row.Points += models.LeaguePointsTie
+			away.row.Points += models.LeaguePointsTie
+		case models.LeagueResultNoResult:
+			home.row.NoResult++
+			away.row.NoResult++
+			home.row.Points += models.LeaguePointsNoResult
+			away.row.Points += models.LeaguePointsNoResult
+			continue
+		}
+
+		for _, innings := range result.Innings {
+			batting, bowling := home, away
+			if innings.TeamID == *fixture.AwayID {
+				batting, bowling = away, home
+			}
+			balls := innings.Balls
+			if innings.AllOut {
+				balls = innings.AllottedOvers * 6
+			}
+			batting.row.RunsFor += innings.Runs
+			batting.ballsFaced += balls
+			bowling.row.RunsAgainst += innings.Runs
+			bowling.ballsBowled += balls
+		}
+	}
+
+	table := make([]models.PointsTableRow, 0, len(tallies))
+	for _, team := range league.Teams {
+		t := tallies[team.ID]
+		t.row.OversFaced = models.FormatOversFromBalls(t.ballsFaced)
+		t.row.OversBowled = models.FormatOversFromBalls(t.ballsBowled)
+		var rate float64
+		if t.ballsFaced > 0 {
+			rate += float64(t.row.RunsFor) * 6 / float64(t.ballsFaced)
+		}
+		if t.ballsBowled > 0 {
+			rate -= float64(t.row.RunsAgainst) * 6 / float64(t.ballsBowled)
+		}
+		t.row.NetRunRate = math.Round(rate*1000) / 1000
+		table = append(table, t.row)
+	}
+
+	sort.SliceStable(table, func(i, j int) bool {
+		a, b := table[i], table[j]
+		if a.Points != b.Points {
+			return a.Points > b.Points
+		}
+		if a.Won != b.Won {
+			return a.Won > b.Won
+		}
+		if a.NetRunRate != b.NetRunRate {
+			return a.NetRunRate > b.NetRunRate
+		}
+		return strings.ToLower(a.TeamName) < strings.ToLower(b.TeamName)
+	})
+	for i := range table {
+		table[i].Position = i + 1
+	}
+	return table
+}
+
+// roundRobin pairs every team with every other once using the circle method, so each team plays at
+// most once a round. With an odd number of teams one team sits out each round.
+func roundRobin(teamIDs []primitive.ObjectID) [][][2]primitive.ObjectID {
+	slots := make([]*primitive.ObjectID, len(teamIDs))
+	for i := range teamIDs {
+		slots[i] = &teamIDs[i]
+	}
+	if len(slots)%2 == 1 {
+		slots = append(slots, nil) // the bye
+	}
+
+	n := len(slots)
+	rounds := make([][][2]primitive.ObjectID, 0, n-1)
+	for round := 0; round < n-1; round++ {
+		pairs := [][2]primitive.ObjectID{}
+		for i := 0; i < n/2; i++ {
+			home, away := slots[i], slots[n-1-i]
+			if home == nil || away == nil {
+				continue
+			}
+			// Alternate the fixed team's home and away so it isn't always at home
+			if i == 0 && round%2 == 1 {
+				home, away = away, home
+			}
+			pairs = append(pairs, [2]primitive.ObjectID{*home, *away})
+		}
+		rounds = append(rounds, pairs)
+
+		// Keep the first team fixed and rotate the rest one place
+		last := slots[n-1]
+		copy(slots[2:], slots[1:n-1])
+		slots[1] = last
+	}
+	return rounds
+}
+
+// knockoutRounds names the rounds of a knockout between the top n teams
+func knockoutRounds(n int) []string {
+	switch n {
+	case 8:
+		return []string{models.LeagueStageQuarterFinal, models.LeagueStageSemiFinal, models.LeagueStageFinal}
+	case 4:
+		return []string{models.LeagueStageSemiFinal, models.LeagueStageFinal}
+	default:
+		return []string{models.LeagueStageFinal}
+	}
+}
+
+// seedOrder lists the seeds 1 to n in bracket order, so consecutive pairs meet in the first round
+// and the top two seeds can only meet in the final, e.g. 1 8 4 5 2 7 3 6
+func seedOrder(n int) []int {
+	order := []int{1}
+	for size := 2; size <= n; size *= 2 {
+		next := make([]int, 0, size)
+		for _, seed := range order {
+			next = append(next, seed, size+1-seed)
+		}
+		order = next
+	}
+	return order
+}
+
+// sortLeagueFixtures puts fixtures in the order they are played
+func sortLeagueFixtures(fixtures []models.LeagueFixture) {
+	sort.SliceStable(fixtures, func(i, j int) bool {
+		if !fixtures[i].StartsAt.Equal(fixtures[j].StartsAt) {
+			return fixtures[i].StartsAt.Before(fixtures[j].StartsAt)
+		}
+		return fixtures[i].Venue < fixtures[j].Venue
+	})
+}
+
+func plural(count int, noun string) string {
+	if count == 1 {
+		return fmt.Sprintf("%d %s", count, noun)
+	}
+	return fmt.Sprintf("%d %ss", count, noun)
+}
+
+// leagueSlot is a venue booked for a period
+type leagueSlot struct {
+	start time.Time
+	end   time.Time
+	venue string
+}
+
+// leagueBooking is a fixture or session that a new fixture must not clash with
+type leagueBooking struct {
+	leagueSlot
+	teams   []primitive.ObjectID // the league teams playing, for this league's fixtures
+	batchID *primitive.ObjectID  // the batch a session is for
+}
+
+// leagueScheduler hands out the earliest free slots allowed by a LeagueSchedule. A slot is free when
+// its venue isn't in use, neither team already plays that day, and none of the players' batches
+// has a session at the time.
+type leagueScheduler struct {
+	start       time.Time
+	weekdays    map[time.Weekday]bool
+	startTimes  []time.Duration // after midnight
+	duration    time.Duration
+	venues      []string
+	bookings    []leagueBooking
+	teamBatches map[primitive.ObjectID][]primitive.ObjectID
+}
+
+// newLeagueScheduler checks a schedule and loads the fixtures, sessions and batches it has to work
+// around, leaving out the league's fixtures in the stages being replaced. It writes an error and
+// returns false when it can't.
+func (h *LeagueHandler) newLeagueScheduler(w http.ResponseWriter, r *http.Request, league *models.League, schedule *models.LeagueSchedule, replacing []string) (*leagueScheduler, bool) {
+	scheduler := &leagueScheduler{
+		start:       startOfDay(schedule.StartDate),
+		weekdays:    map[time.Weekday]bool{},
+		duration:    time.Duration(schedule.DurationMinutes) * time.Minute,
+		teamBatches: map[primitive.ObjectID][]primitive.ObjectID{},
+	}
+	if scheduler.start.Before(startOfDay(time.Now())) {
+		writeFieldError(w, "startDate", "future", "start date can't be in the past")
+		return nil, false
+	}
+	for i, weekday := range schedule.Weekdays {
+		if weekday < 0 || weekday > 6 {
+			writeFieldError(w, fmt.Sprintf("weekdays[%d]", i), "weekday", "weekdays run from 0 (Sunday) to 6 (Saturday)")
+			return nil, false
+		}
+		scheduler.weekdays[time.Weekday(weekday)] = true
+	}
+	for i, value := range schedule.StartTimes {
+		clock, err := time.Parse("15:04", strings.TrimSpace(value))
+		if err != nil {
+			writeFieldError(w, fmt.Sprintf("startTimes[%d]", i), "time", "start times must look like 09:30")
+			return nil, false
+		}
+		scheduler.startTimes = append(scheduler.startTimes, time.Duration(clock.Hour())*time.Hour+time.Duration(clock.Minute())*time.Minute)
+	}
+	sort.Slice(scheduler.startTimes, func(i, j int) bool { return scheduler.startTimes[i] < scheduler.startTimes[j] })
+	scheduler.venues = cleanList(schedule.Venues)
+	if len(scheduler.venues) == 0 {
+		writeFieldError(w, "venues", "required", "at least one venue is required")
+		return nil, false
+	}
+
+	ctx := r.Context()
+	end := scheduler.start.AddDate(0, 0, leagueSchedulingDays+1)
+	sessions, err := h.db.GetSessionsBetween(ctx, scheduler.start, end)
+	if err != nil {
+		http.Error(w, "Error fetching sessions", http.StatusInternalServerError)
+		return nil, false
+	}
+	for _, session := range sessions {
+		scheduler.bookings = append(scheduler.bookings, leagueBooking{
+			leagueSlot: leagueSlot{start: session.StartTime, end: session.EndTime, venue: session.Venue},
+			batchID:    session.BatchID,
+		})
+	}
+	others, err := h.db.GetLeagueFixturesBetween(ctx, scheduler.start, end)
+	if err != nil {
+		http.Error(w, "Error fetching fixtures", http.StatusInternalServerError)
+		return nil, false
+	}
+	for _, fixture := range others {
+		if fixture.LeagueID != league.ID || !containsString(replacing, fixture.Stage) {
+			scheduler.bookings = append(scheduler.bookings, leagueBooking{
+				leagueSlot: leagueSlot{start: fixture.StartsAt, end: fixture.EndsAt, venue: fixture.Venue},
+			})
+		}
+	}
+
+	if err := teamBatches(ctx, h.db, league, scheduler.teamBatches); err != nil {
+		http.Error(w, "Error fetching cricketers", http.StatusInternalServerError)
+		return nil, false
+	}
+	return scheduler, true
+}
+
+// teamBatches records the batches each team's players currently train in
+func teamBatches(ctx context.Context, database db.Database, league *models.League, batches map[primitive.ObjectID][]primitive.ObjectID) error {
+	for _, team := range league.Teams {
+		ids := []primitive.ObjectID{}
+		for _, player := range team.Players {
+			cricketer, err := database.GetCricketerByID(ctx, *player.CricketerID)
+			if err == mongo.ErrNoDocuments {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			if cricketer.BatchID != nil {
+				ids = append(ids, *cricketer.BatchID)
+			}
+		}
+		batches[team.ID] = uniqueObjectIDs(ids)
+	}
+	return nil
+}
+
+// place books the earliest free slot on or after notBefore for a fixture between the given teams
+func (s *leagueScheduler) place(teams []primitive.ObjectID, notBefore time.Time) (leagueSlot, bool) {
+	batches := []primitive.ObjectID{}
+	for _, team := range teams {
+		batches = append(batches, s.teamBatches[team]...)
+	}
+
+	day := s.start
+	if notBefore.After(day) {
+		day = startOfDay(notBefore)
+	}
+	last := s.start.AddDate(0, 0, leagueSchedulingDays)
+	for ; !day.After(last); day = day.AddDate(0, 0, 1) {
+		if len(s.weekdays) > 0 && !s.weekdays[day.Weekday()] {
+			continue
+		}
+		if s.playsOn(day, teams) {
+			continue
+		}
+		for _, offset := range s.startTimes {
+			start := day.Add(offset)
+			if start.Before(notBefore) {
+				continue
+			}
+			for _, venue := range s.venues {
+				slot := leagueSlot{start: start, end: start.Add(s.duration), venue: venue}
+				if s.clashes(slot, batches) {
+					continue
+				}
+				s.bookings = append(s.bookings, leagueBooking{leagueSlot: slot, teams: teams})
+				return slot, true
+			}
+		}
+	}
+	return leagueSlot{}, false
+}
+
+// playsOn reports whether any of the teams already has a fixture on the day
+func (s *leagueScheduler) playsOn(day time.Time, teams []primitive.ObjectID) bool {
+	next := day.AddDate(0, 0, 1)
+	for _, booking := range s.bookings {
+		if booking.start.Before(day) || !booking.start.Before(next) {
+			continue
+		}
+		for _, team := range teams {
+			if containsObjectID(booking.teams, team) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// clashes reports whether a slot overlaps a booking at the same venue or a session for one of the batches
+func (s *leagueScheduler) clashes(slot leagueSlot, batches []primitive.ObjectID) bool {
+	for _, booking := range s.bookings {
+		if !booking.start.Before(slot.end) || !slot.start.Before(booking.end) {
+			continue
+		}
+		if strings.EqualFold(strings.TrimSpace(booking.venue), slot.venue) {
+			return true
+		}
+		if booking.batchID != nil && containsObjectID(batches, *booking.batchID) {
+			return true
+		}
+	}
+	return false
+}
+
+func (h *LeagueHandler) leagueFromURL(w http.ResponseWriter, r *http.Request) (*models.League, bool) {
+	leagueID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid league ID", http.StatusBadRequest)
+		return nil, false
+	}
+
+	league, err := h.db.GetLeagueByID(r.Context(), leagueID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "League not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Error fetching league", http.StatusInternalServerError)
+		}
+		return nil, false
+	}
+	return league, true
+}
@@ -0,0 +1,255 @@
+package handlers
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"cricketApp/models"
+)
+
+// testLeague returns a 20-over league with teams of the given names
+func testLeague(names ...string) *models.League {
+	league := &models.League{OversPerInnings: 20}
+	for _, name := range names {
+		league.Teams = append(league.Teams, models.LeagueTeam{ID: primitive.NewObjectID(), Name: name})
+	}
+	return league
+}
+
+func teamID(league *models.League, name string) primitive.ObjectID {
+	for _, team := range league.Teams {
+		if team.Name == name {
+			return team.ID
+		}
+	}
+	panic("no team " + name)
+}
+
+// innings is a team's total: runs off balls of allotted overs
+func innings(league *models.League, team string, runs int, balls int, allotted int, allOut bool) models.LeagueInnings {
+	return models.LeagueInnings{TeamID: teamID(league, team), Runs: runs, Balls: balls, AllottedOvers: allotted, AllOut: allOut}
+}
+
+// fixture is a league-stage fixture between home and away with a result. The winner is named for
+// completed matches.
+func fixture(league *models.League, home string, away string, status string, winner string, innings ...models.LeagueInnings) models.LeagueFixture {
+	homeID, awayID := teamID(league, home), teamID(league, away)
+	result := &models.LeagueResult{Status: status, Innings: innings}
+	if winner != "" {
+		id := teamID(league, winner)
+		result.WinnerID = &id
+	}
+	return models.LeagueFixture{Stage: models.LeagueStageLeague, HomeID: &homeID, AwayID: &awayID, Result: result}
+}
+
+func rowFor(t *testing.T, table []models.PointsTableRow, name string) models.PointsTableRow {
+	t.Helper()
+	for _, row := range table {
+		if row.TeamName == name {
+			return row
+		}
+	}
+	t.Fatalf("%s is not in the table", name)
+	return models.PointsTableRow{}
+}
+
+func TestPointsTableNetRunRate(t *testing.T) {
+	type want struct {
+		team        string
+		played      int
+		points      int
+		oversFaced  string
+		oversBowled string
+		nrr         float64
+	}
+	tests := []struct {
+		name     string
+		fixtures func(league *models.League) []models.LeagueFixture
+		want     []want
+	}{
+		{
+			name: "both sides bat their overs",
+			fixtures: func(l *models.League) []models.LeagueFixture {
+				return []models.LeagueFixture{fixture(l, "A", "B", models.LeagueResultCompleted, "A",
+					innings(l, "A", 160, 120, 20, false), innings(l, "B", 140, 120, 20, false))}
+			},
+			want: []want{{"A", 1, 2, "20", "20", 1.0}, {"B", 1, 0, "20", "20", -1.0}},
+		},
+		{
+			name: "bowled out side is charged its full quota",
+			fixtures: func(l *models.League) []models.LeagueFixture {
+				return []models.LeagueFixture{fixture(l, "A", "B", models.LeagueResultCompleted, "A",
+					innings(l, "A", 160, 120, 20, false), innings(l, "B", 100, 90, 20, true))}
+			},
+			// 160/20 - 100/20, not 100/15
+			want: []want{{"A", 1, 2, "20", "20", 3.0}, {"B", 1, 0, "20", "20", -3.0}},
+		},
+		{
+			name: "successful chase uses the balls it took",
+			fixtures: func(l *models.League) []models.LeagueFixture {
+				return []models.LeagueFixture{fixture(l, "A", "B", models.LeagueResultCompleted, "B",
+					innings(l, "A", 120, 120, 20, false), innings(l, "B", 121, 80, 20, false))}
+			},
+			// 121/13.2 - 120/20
+			want: []want{{"B", 1, 2, "13.2", "20", 3.075}, {"A", 1, 0, "20", "13.2", -3.075}},
+		},
+		{
+			name: "reduced overs",
+			fixtures: func(l *models.League) []models.LeagueFixture {
+				return []models.LeagueFixture{fixture(l, "A", "B", models.LeagueResultCompleted, "A",
+					innings(l, "A", 80, 60, 10, false), innings(l, "B", 50, 45, 10, true))}
+			},
+			// 80/10 - 50/10: B is charged the 10 overs the match was cut to, not the league's 20
+			want: []want{{"A", 1, 2, "10", "10", 3.0}, {"B", 1, 0, "10", "10", -3.0}},
+		},
+		{
+			name: "no result is left out of the run rate",
+			fixtures: func(l *models.League) []models.LeagueFixture {
+				return []models.LeagueFixture{
+					fixture(l, "A", "B", models.LeagueResultCompleted, "A",
+						innings(l, "A", 150, 120, 20, false), innings(l, "B", 120, 120, 20, false)),
+					fixture(l, "A", "B", models.LeagueResultNoResult, "",
+						innings(l, "B", 60, 30, 20, false)),
+				}
+			},
+			want: []want{{"A", 2, 3, "20", "20", 1.5}, {"B", 2, 1, "20", "20", -1.5}},
+		},
+		{
+			name: "tie counts in the run rate",
+			fixtures: func(l *models.League) []models.LeagueFixture {
+				return []models.LeagueFixture{fixture(l, "A", "B", models.LeagueResultTie, "",
+					innings(l, "A", 150, 120, 20, false), innings(l, "B", 150, 120, 20, false))}
+			},
+			want: []want{{"A", 1, 1, "20", "20", 0}, {"B", 1, 1, "20", "20", 0}},
+		},
+		{
+			name: "unplayed and knockout fixtures don't count",
+			fixtures: func(l *models.League) []models.LeagueFixture {
+				knockout := fixture(l, "A", "B", models.LeagueResultCompleted, "A",
+					innings(l, "A", 150, 120, 20, false), innings(l, "B", 100, 120, 20, false))
+				knockout.Stage = models.LeagueStageFinal
+				unplayed := fixture(l, "A", "B", "", "")
+				unplayed.Result = nil
+				return []models.LeagueFixture{knockout, unplayed}
+			},
+			want: []want{{"A", 0, 0, "0", "0", 0}, {"B", 0, 0, "0", "0", 0}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			league := testLeague("A", "B")
+			table := pointsTable(league, tt.fixtures(league))
+
+			for _, w := range tt.want {
+				row := rowFor(t, table, w.team)
+				if row.Played != w.played || row.Points != w.points {
+					t.Errorf("%s: played %d with %d points, want %d with %d", w.team, row.Played, row.Points, w.played, w.points)
+				}
+				if row.OversFaced != w.oversFaced || row.OversBowled != w.oversBowled {
+					t.Errorf("%s: faced %s and bowled %s overs, want %s and %s", w.team, row.OversFaced, row.OversBowled, w.oversFaced, w.oversBowled)
+				}
+				if row.NetRunRate != w.nrr {
+					t.Errorf("%s: net run rate %v, want %v", w.team, row.NetRunRate, w.nrr)
+				}
+			}
+			if table[0].TeamName != tt.want[0].team || table[0].Position != 1 {
+				t.Errorf("top of the table is %s at %d, want %s", table[0].TeamName, table[0].Position, tt.want[0].team)
+			}
+		})
+	}
+}
+
+func TestPointsTableOrdering(t *testing.T) {
+	league := testLeague("Bravo", "alpha", "V", "W", "X", "Y", "Z")
+	full := func(team string, runs int) models.LeagueInnings {
+		return innings(league, team, runs, 120, 20, false)
+	}
+	fixtures := []models.LeagueFixture{
+		fixture(league, "X", "V", models.LeagueResultCompleted, "X", full("X", 101), full("V", 100)),
+		fixture(league, "W", "X", models.LeagueResultCompleted, "W", full("W", 150), full("X", 100)),
+		fixture(league, "Y", "Z", models.LeagueResultTie, "", full("Y", 120), full("Z", 120)),
+		fixture(league, "Y", "V", models.LeagueResultTie, "", full("Y", 90), full("V", 90)),
+	}
+
+	// W, X and Y have 2 points: W and X won a match, and W has the better run rate. Y's two
+	// ties leave it a better run rate than X but fewer wins. Z and V have a point each and are
+	// split on run rate; alpha and Bravo haven't played and are split by name.
+	want := []string{"W", "X", "Y", "Z", "V", "alpha", "Bravo"}
+	table := pointsTable(league, fixtures)
+	if len(table) != len(want) {
+		t.Fatalf("table has %d rows, want %d", len(table), len(want))
+	}
+	for i, row := range table {
+		if row.TeamName != want[i] || row.Position != i+1 {
+			t.Errorf("position %d: %s (%d points, %d won, NRR %v), want %s", row.Position, row.TeamName, row.Points, row.Won, row.NetRunRate, want[i])
+		}
+	}
+}
+
+func TestRoundRobin(t *testing.T) {
+	for teams := 2; teams <= 9; teams++ {
+		ids := make([]primitive.ObjectID, teams)
+		for i := range ids {
+			ids[i] = primitive.NewObjectID()
+		}
+
+		rounds := roundRobin(ids)
+
+		wantRounds := teams - 1
+		if teams%2 == 1 {
+			wantRounds = teams
+		}
+		if len(rounds) != wantRounds {
+			t.Errorf("%d teams: %d rounds, want %d", teams, len(rounds), wantRounds)
+		}
+
+		met := map[[2]primitive.ObjectID]int{}
+		byes := map[primitive.ObjectID]int{}
+		for r, pairs := range rounds {
+			if len(pairs) != teams/2 {
+				t.Errorf("%d teams: round %d has %d fixtures, want %d", teams, r+1, len(pairs), teams/2)
+			}
+			playing := map[primitive.ObjectID]bool{}
+			for _, pair := range pairs {
+				for _, id := range pair {
+					if playing[id] {
+						t.Errorf("%d teams: a team plays twice in round %d", teams, r+1)
+					}
+					playing[id] = true
+				}
+				key := pair
+				if key[1].Hex() < key[0].Hex() {
+					key[0], key[1] = key[1], key[0]
+				}
+				met[key]++
+			}
+			for _, id := range ids {
+				if !playing[id] {
+					byes[id]++
+				}
+			}
+		}
+
+		for i := range ids {
+			for j := i + 1; j < len(ids); j++ {
+				key := [2]primitive.ObjectID{ids[i], ids[j]}
+				if key[1].Hex() < key[0].Hex() {
+					key[0], key[1] = key[1], key[0]
+				}
+				if met[key] != 1 {
+					t.Errorf("%d teams: teams %d and %d meet %d times, want once", teams, i, j, met[key])
+				}
+			}
+		}
+		for _, id := range ids {
+			wantByes := 0
+			if teams%2 == 1 {
+				wantByes = 1
+			}
+			if byes[id] != wantByes {
+				t.Errorf("%d teams: a team sits out %d rounds, want %d", teams, byes[id], wantByes)
+			}
+		}
+	}
+}
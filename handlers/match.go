@@ -0,0 +1,542 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"cricketApp/db"
+	"cricketApp/models"
+	"cricketApp/notification"
+)
+
+// MatchHandler manages match scorecards and the career statistics derived from them
+type MatchHandler struct {
+	db       db.Database
+	notifier notification.Notifier
+}
+
+func NewMatchHandler(db db.Database, notifier notification.Notifier) *MatchHandler {
+	return &MatchHandler{db: db, notifier: notifier}
+}
+
+// CreateMatch records a match and, optionally, its scorecard (admins and coaches)
+func (h *MatchHandler) CreateMatch(w http.ResponseWriter, r *http.Request) {
+	userID, err := subjectIDFromClaims(r)
+	if err != nil {
+		http.Error(w, "Invalid token claims", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.MatchRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	match, ok := h.buildMatch(w, r, &req)
+	if !ok {
+		return
+	}
+	match.CreatedBy = userID.Hex()
+	match.CreatedByRole = roleFromClaims(r)
+
+	if err := h.db.CreateMatch(r.Context(), match); err != nil {
+		http.Error(w, "Error creating match", http.StatusInternalServerError)
+		return
+	}
+	checkWorkloadAlerts(r.Context(), h.db, h.notifier, matchBowlerIDs(match.Innings), match.Date)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Match created successfully",
+		"match":   match,
+	})
+}
+
+// GetMatches lists matches, optionally filtered by ?cricketerId, ?status and a ?from/?to date range
+func (h *MatchHandler) GetMatches(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := models.MatchFilter{Status: query.Get("status")}
+
+	if value := query.Get("cricketerId"); value != "" {
+		cricketerID, err := primitive.ObjectIDFromHex(value)
+		if err != nil {
+			http.Error(w, "Invalid cricketer ID", http.StatusBadRequest)
+			return
+		}
+		filter.CricketerID = &cricketerID
+	}
+	for name, target := range map[string]**time.Time{"from": &filter.From, "to": &filter.To} {
+		if value := query.Get(name); value != "" {
+			date, err := time.Parse("2006-01-02", value)
+			if err != nil {
+				http.Error(w, "Invalid "+name+" date, expected YYYY-MM-DD", http.StatusBadRequest)
+				return
+			}
+			*target = &date
+		}
+	}
+
+	matches, err := h.db.GetMatches(r.Context(), filter)
+	if err != nil {
+		http.Error(w, "Error fetching matches", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(matches)
+}
+
+// GetMatch returns a match with its scorecard
+func (h *MatchHandler) GetMatch(w http.ResponseWriter, r *http.Request) {
+	match, ok := h.matchFromURL(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(match)
+}
+
+// UpdateMatch replaces a match's details and scorecard. Admins can edit any match, coaches only
+// the ones they created. Matches scored ball by ball are corrected through their event log instead.
+func (h *MatchHandler) UpdateMatch(w http.ResponseWriter, r *http.Request) {
+	match, ok := h.matchFromURL(w, r)
+	if !ok {
+		return
+	}
+	if !canManageMatch(r, match) {
+		http.Error(w, "You can only edit matches you created", http.StatusForbidden)
+		return
+	}
+	events, err := h.db.GetBallEvents(r.Context(), match.ID)
+	if err != nil {
+		http.Error(w, "Error fetching ball events", http.StatusInternalServerError)
+		return
+	}
+	if len(events) > 0 {
+		http.Error(w, "This match is scored ball by ball; correct it by undoing events", http.StatusConflict)
+		return
+	}
+
+	var req models.MatchRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	updated, ok := h.buildMatch(w, r, &req)
+	if !ok {
+		return
+	}
+
+	if err := h.db.UpdateMatch(r.Context(), match.ID, updated); err != nil {
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "Match not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Error updating match", http.StatusInternalServerError)
+		}
+		return
+	}
+	updated.ID = match.ID
+	updated.CreatedBy = match.CreatedBy
+	updated.CreatedByRole = match.CreatedByRole
+	updated.CreatedAt = match.CreatedAt
+	checkWorkloadAlerts(r.Context(), h.db, h.notifier, matchBowlerIDs(updated.Innings), updated.Date)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Match updated successfully",
+		"match":   updated,
+	})
+}
+
+// DeleteMatch removes a match. Admins can delete any match, coaches only the ones they created.
+func (h *MatchHandler) DeleteMatch(w http.ResponseWriter, r *http.Request) {
+	match, ok := h.matchFromURL(w, r)
+	if !ok {
+		return
+	}
+	if !canManageMatch(r, match) {
+		http.Error(w, "You can only delete matches you created", http.StatusForbidden)
+		return
+	}
+
+	if err := h.db.DeleteMatch(r.Context(), match.ID); err != nil && err != mongo.ErrNoDocuments {
+		http.Error(w, "Error deleting match", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Match deleted successfully",
+	})
+}
+
+// GetCricketerStats returns a cricketer's career statistics
+func (h *MatchHandler) GetCricketerStats(w http.ResponseWriter, r *http.Request) {
+	cricketerID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid cricketer ID", http.StatusBadRequest)
+		return
+	}
+	if _, err := h.db.GetCricketerByID(r.Context(), cricketerID); err != nil {
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "Cricketer not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Error fetching cricketer", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	stats, err := careerStats(r.Context(), h.db, cricketerID)
+	if err != nil {
+		http.Error(w, "Error calculating career statistics", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(stats)
+}
+
+func (h *MatchHandler) matchFromURL(w http.ResponseWriter, r *http.Request) (*models.Match, bool) {
+	matchID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid match ID", http.StatusBadRequest)
+		return nil, false
+	}
+
+	match, err := h.db.GetMatchByID(r.Context(), matchID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "Match not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Error fetching match", http.StatusInternalServerError)
+		}
+		return nil, false
+	}
+	return match, true
+}
+
+// canManageMatch reports whether the caller may edit or delete a match
+func canManageMatch(r *http.Request, match *models.Match) bool {
+	if roleFromClaims(r) == "admin" {
+		return true
+	}
+	userID, err := subjectIDFromClaims(r)
+	return err == nil && match.CreatedBy == userID.Hex()
+}
+
+// buildMatch checks a match request for consistency and derives the innings totals from the
+// scorecard entries, writing a field error and returning false when the scorecard doesn't add up
+func (h *MatchHandler) buildMatch(w http.ResponseWriter, r *http.Request, req *models.MatchRequest) (*models.Match, bool) {
+	match := &models.Match{
+		Title:           strings.TrimSpace(req.Title),
+		Type:            req.Type,
+		Format:          req.Format,
+		OversPerInnings: models.FormatOvers(req.Format),
+		Date:            req.Date,
+		Venue:           strings.TrimSpace(req.Venue),
+		TossDecision:    req.TossDecision,
+		Result:          req.Result,
+		Innings:         []models.Innings{},
+	}
+	if req.Format == models.MatchFormatCustom {
+		if req.OversPerInnings == 0 {
+			writeFieldError(w, "oversPerInnings", "required", "oversPerInnings is required for custom matches")
+			return nil, false
+		}
+		match.OversPerInnings = req.OversPerInnings
+	}
+
+	// Teams: academy cricketers must exist and can only play for one side
+	picked := map[primitive.ObjectID]bool{}
+	teams := make([]models.MatchTeam, len(req.Teams))
+	for i, team := range req.Teams {
+		team.Name = strings.TrimSpace(team.Name)
+		if i > 0 && strings.EqualFold(team.Name, teams[0].Name) {
+			writeFieldError(w, fmt.Sprintf("teams[%d].name", i), "unique", "teams must have different names")
+			return nil, false
+		}
+		players := make([]models.MatchPlayer, 0, len(team.Players))
+		for j, player := range team.Players {
+			field := fmt.Sprintf("teams[%d].players[%d]", i, j)
+			player.Name = strings.TrimSpace(player.Name)
+			if player.CricketerID != nil {
+				if picked[*player.CricketerID] {
+					writeFieldError(w, field+".cricketerId", "unique", "cricketer is picked more than once")
+					return nil, false
+				}
+				picked[*player.CricketerID] = true
+
+				cricketer, err := h.db.GetCricketerByID(r.Context(), *player.CricketerID)
+				if err != nil {
+					if err == mongo.ErrNoDocuments {
+						writeFieldError(w, field+".cricketerId", "exists", "cricketer not found")
+					} else {
+						http.Error(w, "Error fetching cricketer", http.StatusInternalServerError)
+					}
+					return nil, false
+				}
+				if player.Name == "" {
+					player.Name = cricketer.Name
+				}
+			} else if player.Name == "" {
+				writeFieldError(w, field+".name", "required", "name is required for players without a cricketerId")
+				return nil, false
+			}
+			players = append(players, player)
+		}
+		team.Players = players
+		teams[i] = team
+	}
+	match.Teams = teams
+
+	if req.TossWinner != "" {
+		team := matchTeam(match, req.TossWinner)
+		if team == nil {
+			writeFieldError(w, "tossWinner", "team", "tossWinner must be one of the teams")
+			return nil, false
+		}
+		match.TossWinner = team.Name
+	}
+
+	if len(req.Innings) > 0 && match.Result.Status == models.MatchScheduled {
+		writeFieldError(w, "innings", "status", "a scheduled match cannot have innings")
+		return nil, false
+	}
+	for i, innings := range req.Innings {
+		scored, field, code, message := scoreInnings(match, innings)
+		if field != "" {
+			writeFieldError(w, fmt.Sprintf("innings[%d].%s", i, field), code, message)
+			return nil, false
+		}
+		for _, previous := range match.Innings {
+			if previous.BattingTeam == scored.BattingTeam {
+				writeFieldError(w, fmt.Sprintf("innings[%d].battingTeam", i), "unique", "each team bats once")
+				return nil, false
+			}
+		}
+		match.Innings = append(match.Innings, *scored)
+	}
+
+	if match.Result.Winner != "" {
+		team := matchTeam(match, match.Result.Winner)
+		if team == nil {
+			writeFieldError(w, "result.winner", "team", "winner must be one of the teams")
+			return nil, false
+		}
+		if match.Result.Status != models.MatchCompleted {
+			writeFieldError(w, "result.winner", "status", "only completed matches have a winner")
+			return nil, false
+		}
+		match.Result.Winner = team.Name
+	}
+	return match, true
+}
+
+// scoreInnings validates an innings against the match and fills in its totals. It returns the
+// field (relative to the innings), code and message of the first problem found.
+func scoreInnings(match *models.Match, innings models.Innings) (*models.Innings, string, string, string) {
+	battingTeam := matchTeam(match, innings.BattingTeam)
+	if battingTeam == nil {
+		return nil, "battingTeam", "team", "battingTeam must be one of the teams"
+	}
+	bowlingTeam := &match.Teams[0]
+	if bowlingTeam == battingTeam {
+		bowlingTeam = &match.Teams[1]
+	}
+	innings.BattingTeam = battingTeam.Name
+
+	batRuns, wickets, creditedWickets := 0, 0, 0
+	positions := map[int]bool{}
+	batters := map[primitive.ObjectID]bool{}
+	for i, entry := range innings.Batting {
+		field := fmt.Sprintf("batting[%d]", i)
+		player, problem := scorecardPlayer(battingTeam, entry.MatchPlayer, batters)
+		if problem != "" {
+			return nil, field + ".cricketerId", "team", problem
+		}
+		if player.Name == "" {
+			return nil, field + ".name", "required", "name is required for players without a cricketerId"
+		}
+		entry.MatchPlayer = player
+		if positions[entry.Position] {
+			return nil, field + ".position", "unique", "batting position is used more than once"
+		}
+		positions[entry.Position] = true
+
+		if entry.Dismissal == models.DismissalDidNotBat && (entry.Runs > 0 || entry.Balls > 0) {
+			return nil, field + ".dismissal", "did_not_bat", "a batter who did not bat cannot have runs or balls"
+		}
+		if entry.Fours*4+entry.Sixes*6 > entry.Runs {
+			return nil, field + ".runs", "boundaries", "runs cannot be less than the runs from boundaries"
+		}
+		if entry.Fours+entry.Sixes > entry.Balls {
+			return nil, field + ".balls", "boundaries", "balls cannot be fewer than the boundaries hit"
+		}
+		batRuns += entry.Runs
+		if models.DismissalCountsAsOut(entry.Dismissal) {
+			wickets++
+		}
+		if models.DismissalCreditsBowler(entry.Dismissal) {
+			creditedWickets++
+		}
+		innings.Batting[i] = entry
+	}
+	if wickets > 10 {
+		return nil, "batting", "wickets", "an innings cannot have more than 10 wickets"
+	}
+
+	balls, bowlerRuns, bowlerWickets, bowlerWides, bowlerNoBalls := 0, 0, 0, 0, 0
+	bowlers := map[primitive.ObjectID]bool{}
+	for i, entry := range innings.Bowling {
+		field := fmt.Sprintf("bowling[%d]", i)
+		player, problem := scorecardPlayer(bowlingTeam, entry.MatchPlayer, bowlers)
+		if problem != "" {
+			return nil, field + ".cricketerId", "team", problem
+		}
+		if player.Name == "" {
+			return nil, field + ".name", "required", "name is required for players without a cricketerId"
+		}
+		entry.MatchPlayer = player
+
+		entryBalls, ok := parseOvers(entry.Overs)
+		if !ok {
+			return nil, field + ".overs", "format", "overs must be completed overs and balls, e.g. 3.4"
+		}
+		entry.Balls = entryBalls
+		entry.Overs = models.FormatOversFromBalls(entryBalls)
+		if entry.Maidens > entryBalls/6 {
+			return nil, field + ".maidens", "max", "maidens cannot exceed completed overs"
+		}
+		if entry.Maidens > 0 && entry.Runs > 0 && entry.Maidens == entryBalls/6 && entryBalls%6 == 0 {
+			return nil, field + ".maidens", "max", "a bowler whose every over was a maiden cannot concede runs"
+		}
+		if entry.Wides+entry.NoBalls > entry.Runs {
+			return nil, field + ".runs", "extras", "runs must include the wides and no-balls bowled"
+		}
+		balls += entryBalls
+		bowlerRuns += entry.Runs
+		bowlerWickets += entry.Wickets
+		bowlerWides += entry.Wides
+		bowlerNoBalls += entry.NoBalls
+		innings.Bowling[i] = entry
+	}
+	if balls > match.OversPerInnings*6 {
+		return nil, "bowling", "overs", fmt.Sprintf("an innings cannot be longer than %d overs", match.OversPerInnings)
+	}
+
+	// Bowlers are charged runs off the bat plus wides and no-balls, and the wickets they are credited with
+	if len(innings.Bowling) > 0 {
+		if bowlerRuns != batRuns+innings.Extras.Wides+innings.Extras.NoBalls {
+			return nil, "bowling", "runs", "runs conceded by the bowlers don't match the runs off the bat plus wides and no-balls"
+		}
+		if bowlerWickets != creditedWickets {
+			return nil, "bowling", "wickets", "wickets taken by the bowlers don't match the batters' dismissals"
+		}
+		if bowlerWides != innings.Extras.Wides || bowlerNoBalls != innings.Extras.NoBalls {
+			return nil, "extras", "bowling", "wides and no-balls don't match the bowling figures"
+		}
+	}
+
+	innings.Runs = batRuns + innings.Extras.Total()
+	innings.Wickets = wickets
+	innings.Balls = balls
+	innings.Overs = models.FormatOversFromBalls(balls)
+	if innings.Batting == nil {
+		innings.Batting = []models.BattingEntry{}
+	}
+	if innings.Bowling == nil {
+		innings.Bowling = []models.BowlingEntry{}
+	}
+	return &innings, "", "", ""
+}
+
+// scorecardPlayer resolves a scorecard entry against the team's players. Academy cricketers must have
+// been picked for the team and appear only once; their name is taken from the team sheet.
+func scorecardPlayer(team *models.MatchTeam, player models.MatchPlayer, seen map[primitive.ObjectID]bool) (models.MatchPlayer, string) {
+	player.Name = strings.TrimSpace(player.Name)
+	if player.CricketerID == nil {
+		return player, ""
+	}
+	if seen[*player.CricketerID] {
+		return player, "cricketer appears more than once"
+	}
+	seen[*player.CricketerID] = true
+	for _, teamPlayer := range team.Players {
+		if teamPlayer.CricketerID != nil && *teamPlayer.CricketerID == *player.CricketerID {
+			if player.Name == "" {
+				player.Name = teamPlayer.Name
+			}
+			return player, ""
+		}
+	}
+	return player, "cricketer is not in " + team.Name
+}
+
+// matchTeam finds a team by name, ignoring case
+func matchTeam(match *models.Match, name string) *models.MatchTeam {
+	name = strings.TrimSpace(name)
+	for i := range match.Teams {
+		if strings.EqualFold(match.Teams[i].Name, name) {
+			return &match.Teams[i]
+		}
+	}
+	return nil
+}
+
+// parseOvers converts overs written as "3" or "3.4" into legal deliveries
+func parseOvers(overs string) (int, bool) {
+	whole, part, hasPart := strings.Cut(strings.TrimSpace(overs), ".")
+	completed, err := strconv.Atoi(whole)
+	if err != nil || completed < 0 {
+		return 0, false
+	}
+	balls := 0
+	if hasPart {
+		balls, err = strconv.Atoi(part)
+		if err != nil || len(part) != 1 || balls > 5 {
+			return 0, false
+		}
+	}
+	return completed*6 + balls, true
+}
+
+// careerStats totals a cricketer's career and derives the averages and rates
+func careerStats(ctx context.Context, database db.Database, cricketerID primitive.ObjectID) (*models.CareerStats, error) {
+	stats, err := database.GetCareerStats(ctx, cricketerID)
+	if err != nil {
+		return nil, err
+	}
+
+	batting := &stats.Batting
+	batting.Average = ratio(batting.Runs, batting.Innings-batting.NotOuts, 1)
+	batting.StrikeRate = ratio(batting.Runs, batting.Balls, 100)
+
+	bowling := &stats.Bowling
+	bowling.Overs = models.FormatOversFromBalls(bowling.Balls)
+	bowling.Average = ratio(bowling.Runs, bowling.Wickets, 1)
+	bowling.Economy = ratio(bowling.Runs, bowling.Balls, 6)
+	bowling.StrikeRate = ratio(bowling.Balls, bowling.Wickets, 1)
+	if bowling.Innings > 0 {
+		bowling.Best = fmt.Sprintf("%d/%d", bowling.BestWickets, bowling.BestRuns)
+	}
+	return stats, nil
+}
+
+// ratio returns scale*numerator/denominator rounded to two places, or nil when the denominator is zero
+func ratio(numerator int, denominator int, scale float64) *float64 {
+	if denominator <= 0 {
+		return nil
+	}
+	value := math.Round(float64(numerator)*scale/float64(denominator)*100) / 100
+	return &value
+}
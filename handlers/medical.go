@@ -0,0 +1,340 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"cricketApp/agecategory"
+	"cricketApp/db"
+	"cricketApp/models"
+)
+
+// MedicalHandler serves medical profiles to admins and emergency cards to coaches
+type MedicalHandler struct {
+	db db.Database
+}
+
+func NewMedicalHandler(db db.Database) *MedicalHandler {
+	return &MedicalHandler{db: db}
+}
+
+// GetMedicalProfile returns a cricketer's medical profile (admin only). Every view is audited.
+func (h *MedicalHandler) GetMedicalProfile(w http.ResponseWriter, r *http.Request) {
+	admin, ok := requireAdminPermission(w, r, h.db, "")
+	if !ok {
+		return
+	}
+	cricketerID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid cricketer ID", http.StatusBadRequest)
+		return
+	}
+
+	profile, err := h.db.GetMedicalProfile(r.Context(), cricketerID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "Medical profile not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Error fetching medical profile", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	entry := &models.AuditEntry{Action: models.AuditViewMedical, TargetType: "cricketer", TargetID: cricketerID.Hex()}
+	if err := recordAudit(r, h.db, admin, entry); err != nil {
+		log.Printf("Error recording medical profile view by %s: %v", admin.ID, err)
+		http.Error(w, "Error recording access", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(profile)
+}
+
+// UpdateMedicalProfile replaces a cricketer's medical information and emergency contacts (admin only)
+func (h *MedicalHandler) UpdateMedicalProfile(w http.ResponseWriter, r *http.Request) {
+	admin, ok := requireAdminPermission(w, r, h.db, "")
+	if !ok {
+		return
+	}
+	cricketerID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid cricketer ID", http.StatusBadRequest)
+		return
+	}
+	if _, err := h.db.GetCricketerByID(r.Context(), cricketerID); err != nil {
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "Cricketer not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Error fetching cricketer", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	profile, ok := saveMedicalProfile(w, r, h.db, cricketerID, admin.ID, "admin")
+	if !ok {
+		return
+	}
+
+	entry := &models.AuditEntry{Action: models.AuditUpdateMedical, TargetType: "cricketer", TargetID: cricketerID.Hex()}
+	if err := recordAudit(r, h.db, admin, entry); err != nil {
+		log.Printf("Error recording medical profile update by %s: %v", admin.ID, err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Medical profile updated successfully",
+		"profile": profile,
+	})
+}
+
+// GetSessionEmergencyCards returns an emergency card for every cricketer on a session's roster:
+// the members of the session's batch, or the cricketers with attendance recorded when the session
+// has no batch. Coaches only get cards for their own sessions, and every view is audited.
+func (h *MedicalHandler) GetSessionEmergencyCards(w http.ResponseWriter, r *http.Request) {
+	session, ok := staffSessionFromURL(w, r, h.db)
+	if !ok {
+		return
+	}
+	viewerID, err := subjectIDFromClaims(r)
+	if err != nil {
+		http.Error(w, "Invalid token claims", http.StatusUnauthorized)
+		return
+	}
+
+	cricketers, err := h.sessionRoster(r, session)
+	if err != nil {
+		http.Error(w, "Error fetching roster", http.StatusInternalServerError)
+		return
+	}
+
+	cricketerIDs := make([]primitive.ObjectID, len(cricketers))
+	for i, cricketer := range cricketers {
+		cricketerIDs[i] = cricketer.ID
+	}
+	profiles, err := h.db.GetMedicalProfiles(r.Context(), cricketerIDs)
+	if err != nil {
+		http.Error(w, "Error fetching medical profiles", http.StatusInternalServerError)
+		return
+	}
+	profilesByCricketer := make(map[primitive.ObjectID]models.MedicalProfile, len(profiles))
+	for _, profile := range profiles {
+		profilesByCricketer[profile.CricketerID] = profile
+	}
+
+	consents, err := consentStatusesFor(r.Context(), h.db, cricketerIDs)
+	if err != nil {
+		http.Error(w, "Error fetching consents", http.StatusInternalServerError)
+		return
+	}
+
+	cards := make([]models.EmergencyCard, 0, len(cricketers))
+	for _, cricketer := range cricketers {
+		card := models.EmergencyCard{
+			CricketerID:       cricketer.ID,
+			Name:              cricketer.Name,
+			Allergies:         []string{},
+			Conditions:        []string{},
+			Medications:       []string{},
+			EmergencyContacts: []models.EmergencyContact{},
+			Guardians:         []models.EmergencyContact{},
+			ConsentsComplete:  consentsComplete(consents[cricketer.ID]),
+		}
+		if cricketer.DateOfBirth != nil {
+			card.Age = agecategory.AgeOn(*cricketer.DateOfBirth, time.Now())
+		}
+		if profile, ok := profilesByCricketer[cricketer.ID]; ok {
+			card.BloodGroup = profile.Medical.BloodGroup
+			card.Allergies = nonNilStrings(profile.Medical.Allergies)
+			card.Conditions = nonNilStrings(profile.Medical.Conditions)
+			card.Medications = nonNilStrings(profile.Medical.Medications)
+			card.Notes = profile.Medical.Notes
+			card.EmergencyContacts = profile.EmergencyContacts
+		} else {
+			card.MissingProfile = true
+		}
+
+		guardians, err := h.db.GetGuardiansForCricketer(r.Context(), cricketer.ID)
+		if err != nil {
+			http.Error(w, "Error fetching guardians", http.StatusInternalServerError)
+			return
+		}
+		for _, guardian := range guardians {
+			if guardian.IsActive {
+				card.Guardians = append(card.Guardians, models.EmergencyContact{Name: guardian.Name, Relationship: "Guardian", Mobile: guardian.Mobile})
+			}
+		}
+		cards = append(cards, card)
+	}
+
+	entry := &models.AuditEntry{
+		Action:     models.AuditViewMedical,
+		TargetType: "session",
+		TargetID:   session.ID.Hex(),
+		Details:    map[string]string{"cricketers": joinObjectIDs(cricketerIDs)},
+	}
+	if err := recordActorAudit(r, h.db, viewerID.Hex(), roleFromClaims(r), entry); err != nil {
+		log.Printf("Error recording emergency card view by %s: %v", viewerID.Hex(), err)
+		http.Error(w, "Error recording access", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"session": session,
+		"cards":   cards,
+	})
+}
+
+// sessionRoster lists the active cricketers expected at a session
+func (h *MedicalHandler) sessionRoster(r *http.Request, session *models.Session) ([]models.Cricketer, error) {
+	var cricketers []models.Cricketer
+	if session.BatchID != nil {
+		members, err := h.db.GetCricketersByBatches(r.Context(), []primitive.ObjectID{*session.BatchID})
+		if err != nil {
+			return nil, err
+		}
+		cricketers = members
+	} else {
+		attendance, err := h.db.GetAttendanceForSession(r.Context(), session.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, record := range attendance {
+			cricketer, err := h.db.GetCricketerByID(r.Context(), record.CricketerID)
+			if err == mongo.ErrNoDocuments {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			cricketers = append(cricketers, *cricketer)
+		}
+	}
+
+	roster := make([]models.Cricketer, 0, len(cricketers))
+	for _, cricketer := range cricketers {
+		if !cricketer.InactiveCricketer {
+			roster = append(roster, cricketer)
+		}
+	}
+	return roster, nil
+}
+
+// GetChildMedicalProfile returns a child's medical profile to their guardian
+func (h *GuardianHandler) GetChildMedicalProfile(w http.ResponseWriter, r *http.Request) {
+	guardian, ok := h.guardianFromClaims(w, r)
+	if !ok {
+		return
+	}
+	cricketer, ok := h.childFromURL(w, r, guardian)
+	if !ok {
+		return
+	}
+
+	profile, err := h.db.GetMedicalProfile(r.Context(), cricketer.ID)
+	if err == mongo.ErrNoDocuments {
+		profile = &models.MedicalProfile{CricketerID: cricketer.ID, EmergencyContacts: []models.EmergencyContact{}}
+	} else if err != nil {
+		http.Error(w, "Error fetching medical profile", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(profile)
+}
+
+// UpdateChildMedicalProfile replaces a child's medical information and emergency contacts
+func (h *GuardianHandler) UpdateChildMedicalProfile(w http.ResponseWriter, r *http.Request) {
+	guardian, ok := h.guardianFromClaims(w, r)
+	if !ok {
+		return
+	}
+	cricketer, ok := h.childFromURL(w, r, guardian)
+	if !ok {
+		return
+	}
+
+	profile, ok := saveMedicalProfile(w, r, h.db, cricketer.ID, guardian.ID.Hex(), "guardian")
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Medical profile updated successfully",
+		"profile": profile,
+	})
+}
+
+// saveMedicalProfile decodes an UpdateMedicalProfileRequest and stores it as the cricketer's profile
+func saveMedicalProfile(w http.ResponseWriter, r *http.Request, database db.Database, cricketerID primitive.ObjectID, updatedBy string, role string) (*models.MedicalProfile, bool) {
+	var req models.UpdateMedicalProfileRequest
+	if !decodeRequest(w, r, &req) {
+		return nil, false
+	}
+
+	contacts := make([]models.EmergencyContact, len(req.EmergencyContacts))
+	for i, contact := range req.EmergencyContacts {
+		contact.Name = strings.TrimSpace(contact.Name)
+		contact.Relationship = strings.TrimSpace(contact.Relationship)
+		contact.Mobile = normalizeMobile(contact.Mobile)
+		if contact.AltMobile != "" {
+			contact.AltMobile = normalizeMobile(contact.AltMobile)
+		}
+		contacts[i] = contact
+	}
+
+	medical := req.Medical
+	medical.Allergies = cleanList(medical.Allergies)
+	medical.Conditions = cleanList(medical.Conditions)
+	medical.Medications = cleanList(medical.Medications)
+
+	profile := &models.MedicalProfile{
+		CricketerID:       cricketerID,
+		Medical:           medical,
+		EmergencyContacts: contacts,
+		UpdatedBy:         updatedBy,
+		UpdatedByRole:     role,
+		UpdatedAt:         time.Now(),
+	}
+	if err := database.SaveMedicalProfile(r.Context(), profile); err != nil {
+		log.Printf("Error saving medical profile for cricketer %s: %v", cricketerID.Hex(), err)
+		http.Error(w, "Error saving medical profile", http.StatusInternalServerError)
+		return nil, false
+	}
+	return profile, true
+}
+
+// cleanList trims entries and drops empty ones
+func cleanList(values []string) []string {
+	cleaned := []string{}
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			cleaned = append(cleaned, value)
+		}
+	}
+	return cleaned
+}
+
+func nonNilStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
+
+func joinObjectIDs(ids []primitive.ObjectID) string {
+	hexIDs := make([]string, len(ids))
+	for i, id := range ids {
+		hexIDs[i] = id.Hex()
+	}
+	return strings.Join(hexIDs, ",")
+}
@@ -0,0 +1,343 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"cricketApp/db"
+	"cricketApp/models"
+)
+
+const (
+	defaultNotePageSize = 20
+	maxNotePageSize     = 100
+	maxNoteTagLength    = 30
+)
+
+// NoteHandler manages coaches' notes about cricketers
+type NoteHandler struct {
+	db db.Database
+}
+
+func NewNoteHandler(db db.Database) *NoteHandler {
+	return &NoteHandler{db: db}
+}
+
+// CreateNote writes a note about a cricketer. Coaches can only write notes about cricketers they coach.
+func (h *NoteHandler) CreateNote(w http.ResponseWriter, r *http.Request) {
+	userID, err := subjectIDFromClaims(r)
+	if err != nil {
+		http.Error(w, "Invalid token claims", http.StatusUnauthorized)
+		return
+	}
+	cricketer, ok := staffCricketerFromURL(w, r, h.db)
+	if !ok {
+		return
+	}
+
+	var req models.CreateNoteRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	body := strings.TrimSpace(req.Body)
+	if body == "" {
+		writeFieldError(w, "body", "required", "is required")
+		return
+	}
+	tags, ok := noteTags(w, req.Tags)
+	if !ok {
+		return
+	}
+
+	role := roleFromClaims(r)
+	note := &models.CricketerNote{
+		CricketerID: cricketer.ID,
+		Body:        body,
+		Visibility:  req.Visibility,
+		Tags:        tags,
+		AuthorID:    userID.Hex(),
+		AuthorRole:  role,
+	}
+	note.AuthorName, err = staffName(r.Context(), h.db, userID, role)
+	if err != nil {
+		http.Error(w, "Error fetching author", http.StatusInternalServerError)
+		return
+	}
+
+	if err := h.db.CreateNote(r.Context(), note); err != nil {
+		http.Error(w, "Error creating note", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Note created successfully",
+		"note":    note,
+	})
+}
+
+// GetCricketerNotes returns a page of a cricketer's notes, newest first, optionally with ?tag.
+// Admins see every note. The cricketer's coaches see coaches-only and shared notes, plus
+// admin-only notes they wrote themselves.
+func (h *NoteHandler) GetCricketerNotes(w http.ResponseWriter, r *http.Request) {
+	cricketer, ok := staffCricketerFromURL(w, r, h.db)
+	if !ok {
+		return
+	}
+	filter, ok := notePageFromQuery(w, r, cricketer.ID)
+	if !ok {
+		return
+	}
+
+	if roleFromClaims(r) == "coach" {
+		coachID, err := subjectIDFromClaims(r)
+		if err != nil {
+			http.Error(w, "Invalid token claims", http.StatusUnauthorized)
+			return
+		}
+		filter.Visibilities = []string{models.NoteCoachesOnly, models.NoteShared}
+		filter.AuthorID = coachID.Hex()
+	}
+
+	notes, err := h.db.GetNotes(r.Context(), filter)
+	if err != nil {
+		http.Error(w, "Error fetching notes", http.StatusInternalServerError)
+		return
+	}
+	writeNotePage(w, notes, filter.Limit)
+}
+
+// UpdateNote edits a note's body, visibility or tags. Only the author can edit a note, and coaches
+// only while they still coach the cricketer.
+func (h *NoteHandler) UpdateNote(w http.ResponseWriter, r *http.Request) {
+	note, ok := h.authoredNoteFromURL(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateNoteRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	if req.Body != nil {
+		body := strings.TrimSpace(*req.Body)
+		if body == "" {
+			writeFieldError(w, "body", "required", "is required")
+			return
+		}
+		note.Body = body
+	}
+	if req.Visibility != nil {
+		note.Visibility = *req.Visibility
+	}
+	if req.Tags != nil {
+		tags, ok := noteTags(w, *req.Tags)
+		if !ok {
+			return
+		}
+		note.Tags = tags
+	}
+
+	if err := h.db.UpdateNote(r.Context(), note.ID, note); err != nil {
+		http.Error(w, "Error updating note", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Note updated successfully",
+		"note":    note,
+	})
+}
+
+// DeleteNote removes a note. Authors can delete their own notes and admins can delete any note.
+func (h *NoteHandler) DeleteNote(w http.ResponseWriter, r *http.Request) {
+	load := h.authoredNoteFromURL
+	if roleFromClaims(r) == "admin" {
+		load = h.noteFromURL
+	}
+	note, ok := load(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.db.DeleteNote(r.Context(), note.ID); err != nil {
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "Note not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Error deleting note", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Note deleted successfully",
+	})
+}
+
+// GetSharedNotes returns the notes shared with the logged-in cricketer, newest first
+func (h *CricketerHandler) GetSharedNotes(w http.ResponseWriter, r *http.Request) {
+	cricketerID, err := subjectIDFromClaims(r)
+	if err != nil {
+		http.Error(w, "Invalid token claims", http.StatusUnauthorized)
+		return
+	}
+	writeSharedNotes(w, r, h.db, cricketerID)
+}
+
+// GetChildNotes returns the notes shared about a child with their guardian, newest first
+func (h *GuardianHandler) GetChildNotes(w http.ResponseWriter, r *http.Request) {
+	guardian, ok := h.guardianFromClaims(w, r)
+	if !ok {
+		return
+	}
+	cricketer, ok := h.childFromURL(w, r, guardian)
+	if !ok {
+		return
+	}
+	writeSharedNotes(w, r, h.db, cricketer.ID)
+}
+
+func writeSharedNotes(w http.ResponseWriter, r *http.Request, database db.Database, cricketerID primitive.ObjectID) {
+	filter, ok := notePageFromQuery(w, r, cricketerID)
+	if !ok {
+		return
+	}
+	filter.Visibilities = []string{models.NoteShared}
+
+	notes, err := database.GetNotes(r.Context(), filter)
+	if err != nil {
+		http.Error(w, "Error fetching notes", http.StatusInternalServerError)
+		return
+	}
+	writeNotePage(w, notes, filter.Limit)
+}
+
+func (h *NoteHandler) noteFromURL(w http.ResponseWriter, r *http.Request) (*models.CricketerNote, bool) {
+	noteID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid note ID", http.StatusBadRequest)
+		return nil, false
+	}
+
+	note, err := h.db.GetNoteByID(r.Context(), noteID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "Note not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Error fetching note", http.StatusInternalServerError)
+		}
+		return nil, false
+	}
+	return note, true
+}
+
+// authoredNoteFromURL loads the note named by {id}, which the caller must have written.
+// Coaches must also still coach the cricketer.
+func (h *NoteHandler) authoredNoteFromURL(w http.ResponseWriter, r *http.Request) (*models.CricketerNote, bool) {
+	userID, err := subjectIDFromClaims(r)
+	if err != nil {
+		http.Error(w, "Invalid token claims", http.StatusUnauthorized)
+		return nil, false
+	}
+	note, ok := h.noteFromURL(w, r)
+	if !ok {
+		return nil, false
+	}
+	if note.AuthorID != userID.Hex() {
+		http.Error(w, "You can only change notes you wrote", http.StatusForbidden)
+		return nil, false
+	}
+	if _, ok := staffCricketer(w, r, h.db, note.CricketerID); !ok {
+		return nil, false
+	}
+	return note, true
+}
+
+// noteTags lower-cases, trims and de-duplicates tags
+func noteTags(w http.ResponseWriter, values []string) ([]string, bool) {
+	tags := []string{}
+	seen := make(map[string]bool, len(values))
+	for i, value := range cleanList(values) {
+		tag := strings.ToLower(value)
+		if len([]rune(tag)) > maxNoteTagLength {
+			writeFieldError(w, fmt.Sprintf("tags[%d]", i), "max", fmt.Sprintf("must be at most %d characters", maxNoteTagLength))
+			return nil, false
+		}
+		if !seen[tag] {
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+	}
+	return tags, true
+}
+
+// notePageFromQuery reads the tag, limit, before (RFC 3339 createdAt cursor) and beforeId query
+// parameters
+func notePageFromQuery(w http.ResponseWriter, r *http.Request, cricketerID primitive.ObjectID) (models.NoteFilter, bool) {
+	query := r.URL.Query()
+	filter := models.NoteFilter{
+		CricketerID: cricketerID,
+		Tag:         strings.ToLower(strings.TrimSpace(query.Get("tag"))),
+		Limit:       defaultNotePageSize,
+	}
+
+	if limit := query.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 1 {
+			http.Error(w, "Invalid limit", http.StatusBadRequest)
+			return filter, false
+		}
+		if n > maxNotePageSize {
+			n = maxNotePageSize
+		}
+		filter.Limit = n
+	}
+
+	if before := query.Get("before"); before != "" {
+		t, err := time.Parse(time.RFC3339Nano, before)
+		if err != nil {
+			http.Error(w, "Invalid before cursor, expected RFC 3339 time", http.StatusBadRequest)
+			return filter, false
+		}
+		filter.Before = &t
+	}
+
+	if beforeID := query.Get("beforeId"); beforeID != "" {
+		id, err := primitive.ObjectIDFromHex(beforeID)
+		if err != nil || filter.Before == nil {
+			http.Error(w, "Invalid beforeId cursor, expected an ID alongside before", http.StatusBadRequest)
+			return filter, false
+		}
+		filter.BeforeID = &id
+	}
+
+	return filter, true
+}
+
+// writeNotePage responds with a page of notes and the cursor for the next page
+func writeNotePage(w http.ResponseWriter, notes []models.CricketerNote, limit int) {
+	var nextBefore *time.Time
+	var nextBeforeID *primitive.ObjectID
+	if len(notes) == limit {
+		last := notes[len(notes)-1]
+		nextBefore, nextBeforeID = &last.CreatedAt, &last.ID
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"notes":        notes,
+		"nextBefore":   nextBefore,
+		"nextBeforeId": nextBeforeID,
+	})
+}
@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"golang.org/x/crypto/bcrypt"
+
+	"cricketApp/models"
+	"cricketApp/notification"
+)
+
+// passwordSetupTTL is how long a password setup link can be used
+const passwordSetupTTL = 7 * 24 * time.Hour
+
+// CompletePasswordSetup sets the password of an account created on approval, using the one-time
+// link sent to the applicant (public). The link stops working once used or expired.
+func (h *RegistrationHandler) CompletePasswordSetup(w http.ResponseWriter, r *http.Request) {
+	var req models.CompletePasswordSetupRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		http.Error(w, "Error hashing password", http.StatusInternalServerError)
+		return
+	}
+
+	cricketer, err := h.db.CompleteCricketerPasswordSetup(r.Context(), hashDocumentToken(req.Token), string(hashedPassword), time.Now())
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "This link is invalid, used or expired. Ask the academy for a new one.", http.StatusGone)
+		} else {
+			http.Error(w, "Error setting password", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Password set. Log in with your mobile number " + cricketer.Mobile,
+	})
+}
+
+// ResendPasswordSetup sends the cricketer linked to an approved registration a new password setup
+// link, replacing any earlier one (admin only)
+func (h *RegistrationHandler) ResendPasswordSetup(w http.ResponseWriter, r *http.Request) {
+	objID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid registration ID", http.StatusBadRequest)
+		return
+	}
+
+	registration, err := h.db.GetRegistrationByID(r.Context(), objID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "Registration not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Error fetching registration", http.StatusInternalServerError)
+		}
+		return
+	}
+	if registration.Status != models.RegistrationApproved || registration.CricketerID.IsZero() {
+		http.Error(w, "Registration has not been approved", http.StatusConflict)
+		return
+	}
+
+	cricketer, err := h.db.GetCricketerByID(r.Context(), registration.CricketerID)
+	if err != nil {
+		http.Error(w, "Error fetching cricketer", http.StatusInternalServerError)
+		return
+	}
+
+	token, tokenHash, err := generateDocumentToken()
+	if err != nil {
+		http.Error(w, "Error generating setup link", http.StatusInternalServerError)
+		return
+	}
+	expiresAt := time.Now().Add(passwordSetupTTL)
+	if err := h.db.SetCricketerPasswordSetup(r.Context(), cricketer.ID, tokenHash, expiresAt); err != nil {
+		http.Error(w, "Error saving setup link", http.StatusInternalServerError)
+		return
+	}
+
+	recipient := notification.Recipient{Name: cricketer.Name, Mobile: cricketer.Mobile, Email: cricketer.Email}
+	ctx := notification.WithSecret(r.Context())
+	if err := h.notifier.Notify(ctx, recipient, "Set your password", h.passwordSetupMessage(cricketer.Mobile, token)); err != nil {
+		log.Printf("Error sending password setup link to cricketer %s: %v", cricketer.ID.Hex(), err)
+		http.Error(w, "Error sending setup link", http.StatusBadGateway)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":   "Password setup link sent",
+		"expiresAt": expiresAt,
+	})
+}
+
+// passwordSetupMessage invites a cricketer to choose their password from a one-time link
+func (h *RegistrationHandler) passwordSetupMessage(mobile string, token string) string {
+	link := h.appBaseURL + "/set-password?token=" + url.QueryEscape(token)
+	return fmt.Sprintf("Choose your password at %s within %d days, then log in with your mobile number %s.",
+		link, int(passwordSetupTTL.Hours()/24), mobile)
+}
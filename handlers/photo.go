@@ -0,0 +1,569 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"cricketApp/db"
+	"cricketApp/imaging"
+	"cricketApp/models"
+	"cricketApp/notification"
+	"cricketApp/storage"
+)
+
+const (
+	// MaxPhotoSize is the largest profile photo upload accepted (10 MB)
+	MaxPhotoSize = 10 << 20
+
+	// photoURLTTL is how long the photo links on a profile stay valid
+	photoURLTTL = time.Hour
+)
+
+// Profile photo renditions and their sizes in pixels
+const (
+	photoThumbnail     = "thumbnail" // square, for lists and avatars
+	photoMedium        = "medium"    // the whole photo, for profiles and ID cards
+	photoThumbnailSize = 160
+	photoMediumSize    = 640
+)
+
+// PhotoHandler handles profile photos of cricketers and coaches
+type PhotoHandler struct {
+	db       db.Database
+	store    storage.BlobStore
+	signer   *storage.URLSigner
+	notifier notification.Notifier
+}
+
+func NewPhotoHandler(db db.Database, store storage.BlobStore, notifier notification.Notifier, photoSecret []byte) *PhotoHandler {
+	return &PhotoHandler{
+		db:       db,
+		store:    store,
+		signer:   newPhotoURLSigner(photoSecret),
+		notifier: notifier,
+	}
+}
+
+// newPhotoURLSigner creates the signer for the photo links handed out on profiles
+func newPhotoURLSigner(secret []byte) *storage.URLSigner {
+	return storage.NewURLSigner(secret, photoURLTTL)
+}
+
+// photoOwner is the cricketer or coach a profile photo belongs to
+type photoOwner struct {
+	Type    string
+	ID      primitive.ObjectID
+	PhotoID *primitive.ObjectID
+	Name    string
+	Mobile  string
+	Email   string
+}
+
+func cricketerPhotoOwner(cricketer *models.Cricketer) *photoOwner {
+	return &photoOwner{
+		Type:    models.PhotoOwnerCricketer,
+		ID:      cricketer.ID,
+		PhotoID: cricketer.PhotoID,
+		Name:    cricketer.Name,
+		Mobile:  cricketer.Mobile,
+		Email:   cricketer.Email,
+	}
+}
+
+// GetOwnPhoto returns the logged-in cricketer's or coach's latest photo, including whether it was rejected
+func (h *PhotoHandler) GetOwnPhoto(w http.ResponseWriter, r *http.Request) {
+	owner, ok := h.ownerFromClaims(w, r)
+	if !ok {
+		return
+	}
+
+	photo, err := h.db.GetLatestPhoto(r.Context(), owner.Type, owner.ID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "No photo uploaded", http.StatusNotFound)
+		} else {
+			http.Error(w, "Error fetching photo", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"photo": photo,
+		"urls":  photoURLs(h.signer, photo),
+	})
+}
+
+// UploadOwnPhoto sets the logged-in cricketer's or coach's profile photo
+func (h *PhotoHandler) UploadOwnPhoto(w http.ResponseWriter, r *http.Request) {
+	owner, ok := h.ownerFromClaims(w, r)
+	if !ok {
+		return
+	}
+	h.uploadPhoto(w, r, owner)
+}
+
+// DeleteOwnPhoto removes the logged-in cricketer's or coach's profile photo
+func (h *PhotoHandler) DeleteOwnPhoto(w http.ResponseWriter, r *http.Request) {
+	owner, ok := h.ownerFromClaims(w, r)
+	if !ok {
+		return
+	}
+	if owner.PhotoID == nil {
+		http.Error(w, "No photo to remove", http.StatusNotFound)
+		return
+	}
+
+	if err := h.db.SetOwnerPhoto(r.Context(), owner.Type, owner.ID, nil); err != nil {
+		http.Error(w, "Error removing photo", http.StatusInternalServerError)
+		return
+	}
+	h.retirePhoto(r.Context(), *owner.PhotoID)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": "Photo removed"})
+}
+
+// UploadCricketerPhoto sets a cricketer's profile photo. Coaches can only set photos of cricketers in their batches.
+func (h *PhotoHandler) UploadCricketerPhoto(w http.ResponseWriter, r *http.Request) {
+	cricketer, ok := staffCricketerFromURL(w, r, h.db)
+	if !ok {
+		return
+	}
+	h.uploadPhoto(w, r, cricketerPhotoOwner(cricketer))
+}
+
+// UploadCoachPhoto sets a coach's profile photo (admin only)
+func (h *PhotoHandler) UploadCoachPhoto(w http.ResponseWriter, r *http.Request) {
+	coachID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid coach ID", http.StatusBadRequest)
+		return
+	}
+	owner, err := h.loadOwner(r.Context(), models.PhotoOwnerCoach, coachID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "Coach not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Error fetching coach", http.StatusInternalServerError)
+		}
+		return
+	}
+	h.uploadPhoto(w, r, owner)
+}
+
+// ListPhotos lists profile photos with a status, newest first, so admins can review them.
+// Defaults to the active photos (admin only).
+func (h *PhotoHandler) ListPhotos(w http.ResponseWriter, r *http.Request) {
+	status := r.URL.Query().Get("status")
+	switch status {
+	case "":
+		status = models.PhotoActive
+	case models.PhotoActive, models.PhotoRejected:
+	default:
+		writeFieldError(w, "status", "oneof", "status must be one of active, rejected")
+		return
+	}
+
+	photos, err := h.db.GetPhotos(r.Context(), status)
+	if err != nil {
+		http.Error(w, "Error fetching photos", http.StatusInternalServerError)
+		return
+	}
+
+	type photoWithURLs struct {
+		models.ProfilePhoto
+		URLs *models.PhotoURLs `json:"urls,omitempty"`
+	}
+	response := make([]photoWithURLs, len(photos))
+	for i := range photos {
+		response[i] = photoWithURLs{ProfilePhoto: photos[i], URLs: photoURLs(h.signer, &photos[i])}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// RejectPhoto takes down an inappropriate profile photo and tells its owner why (admin only)
+func (h *PhotoHandler) RejectPhoto(w http.ResponseWriter, r *http.Request) {
+	adminID, err := subjectIDFromClaims(r)
+	if err != nil {
+		http.Error(w, "Invalid token claims", http.StatusUnauthorized)
+		return
+	}
+	photoID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid photo ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.RejectPhotoRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	reason := strings.TrimSpace(req.Reason)
+
+	photo, err := h.db.GetPhotoByID(r.Context(), photoID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "Photo not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Error fetching photo", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	if err := h.db.RejectPhoto(r.Context(), photo.ID, adminID.Hex(), reason); err != nil {
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "Only photos on a profile can be rejected", http.StatusConflict)
+		} else {
+			http.Error(w, "Error rejecting photo", http.StatusInternalServerError)
+		}
+		return
+	}
+	// Active photos are always the one on their owner's profile
+	if err := h.db.SetOwnerPhoto(r.Context(), photo.OwnerType, photo.OwnerID, nil); err != nil && err != mongo.ErrNoDocuments {
+		http.Error(w, "Error removing photo from profile", http.StatusInternalServerError)
+		return
+	}
+	h.deleteRenditions(r.Context(), photo)
+
+	if owner, err := h.loadOwner(r.Context(), photo.OwnerType, photo.OwnerID); err == nil {
+		recipient := notification.Recipient{Name: owner.Name, Mobile: owner.Mobile, Email: owner.Email}
+		message := "Your profile photo was removed by the academy: " + reason + ". Please upload a different photo."
+		if err := h.notifier.Notify(r.Context(), recipient, "Profile photo removed", message); err != nil {
+			log.Printf("Error notifying %s %s of rejected photo: %v", owner.Type, owner.ID.Hex(), err)
+		}
+	}
+
+	photo, _ = h.db.GetPhotoByID(r.Context(), photo.ID)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Photo rejected and removed from the profile",
+		"photo":   photo,
+	})
+}
+
+// ServePhoto streams a rendition of a profile photo to anyone holding a valid signed URL
+func (h *PhotoHandler) ServePhoto(w http.ResponseWriter, r *http.Request) {
+	if err := h.signer.Verify(r.URL.Path, r.URL.Query()); err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	photoID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid photo ID", http.StatusBadRequest)
+		return
+	}
+	photo, err := h.db.GetPhotoByID(r.Context(), photoID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "Photo not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Error fetching photo", http.StatusInternalServerError)
+		}
+		return
+	}
+	// Replaced and rejected photos are deleted, so their links stop working
+	if photo.Status != models.PhotoActive {
+		http.Error(w, "Photo not found", http.StatusNotFound)
+		return
+	}
+
+	var key string
+	switch chi.URLParam(r, "rendition") {
+	case photoThumbnail:
+		key = photo.ThumbnailKey
+	case photoMedium:
+		key = photo.MediumKey
+	default:
+		http.Error(w, "Photo not found", http.StatusNotFound)
+		return
+	}
+
+	body, err := h.store.Get(r.Context(), key)
+	if err != nil {
+		if err == storage.ErrNotFound {
+			http.Error(w, "Photo not found", http.StatusNotFound)
+		} else {
+			log.Printf("Error reading blob %s: %v", key, err)
+			http.Error(w, "Error reading photo", http.StatusInternalServerError)
+		}
+		return
+	}
+	defer body.Close()
+
+	w.Header().Set("Content-Type", "image/jpeg")
+	w.Header().Set("Cache-Control", fmt.Sprintf("private, max-age=%d", int(photoURLTTL.Seconds())))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	if _, err := io.Copy(w, body); err != nil {
+		log.Printf("Error streaming blob %s: %v", key, err)
+	}
+}
+
+// uploadPhoto validates the uploaded "photo", stores its renditions and puts it on the owner's
+// profile in place of their previous photo
+func (h *PhotoHandler) uploadPhoto(w http.ResponseWriter, r *http.Request, owner *photoOwner) {
+	uploaderID, err := subjectIDFromClaims(r)
+	if err != nil {
+		http.Error(w, "Invalid token claims", http.StatusUnauthorized)
+		return
+	}
+
+	data, status, err := readPhotoUpload(w, r)
+	if err != nil {
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	decoded, err := imaging.Decode(data)
+	if err != nil {
+		code := "unreadable"
+		switch err {
+		case imaging.ErrUnsupportedFormat:
+			code = "format"
+		case imaging.ErrTooSmall:
+			code = "min"
+		case imaging.ErrTooLarge:
+			code = "max"
+		case imaging.ErrBadAspect:
+			code = "aspect"
+		}
+		writeFieldError(w, "photo", code, err.Error())
+		return
+	}
+
+	photo := &models.ProfilePhoto{
+		ID:             primitive.NewObjectID(),
+		OwnerType:      owner.Type,
+		OwnerID:        owner.ID,
+		Status:         models.PhotoActive,
+		UploadedBy:     uploaderID.Hex(),
+		UploadedByRole: roleFromClaims(r),
+		UploadedAt:     time.Now(),
+	}
+	photo.ThumbnailKey = "photos/" + photo.ID.Hex() + "/" + photoThumbnail + ".jpg"
+	photo.MediumKey = "photos/" + photo.ID.Hex() + "/" + photoMedium + ".jpg"
+
+	medium := decoded.Fit(photoMediumSize)
+	photo.Width, photo.Height = medium.Width, medium.Height
+	renditions := []struct {
+		key   string
+		image *imaging.Photo
+	}{
+		{photo.ThumbnailKey, decoded.Square(photoThumbnailSize)},
+		{photo.MediumKey, medium},
+	}
+	for _, rendition := range renditions {
+		encoded, err := rendition.image.JPEG()
+		if err == nil {
+			err = h.store.Put(r.Context(), rendition.key, bytes.NewReader(encoded), int64(len(encoded)), "image/jpeg")
+		}
+		if err != nil {
+			log.Printf("Error storing photo rendition %s: %v", rendition.key, err)
+			h.deleteRenditions(r.Context(), photo)
+			http.Error(w, "Error storing photo", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	if err := h.db.CreatePhoto(r.Context(), photo); err != nil {
+		h.deleteRenditions(r.Context(), photo)
+		http.Error(w, "Error saving photo", http.StatusInternalServerError)
+		return
+	}
+	if err := h.db.SetOwnerPhoto(r.Context(), owner.Type, owner.ID, &photo.ID); err != nil {
+		h.retirePhoto(r.Context(), photo.ID)
+		http.Error(w, "Error saving photo", http.StatusInternalServerError)
+		return
+	}
+	if owner.PhotoID != nil {
+		h.retirePhoto(r.Context(), *owner.PhotoID)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Photo uploaded",
+		"photo":   photo,
+		"urls":    photoURLs(h.signer, photo),
+	})
+}
+
+// readPhotoUpload reads the multipart "photo" field, checking its size and that its content is a
+// JPEG or PNG. It returns an error with the HTTP status to respond with.
+func readPhotoUpload(w http.ResponseWriter, r *http.Request) ([]byte, int, error) {
+	// Allow some headroom for the multipart envelope
+	r.Body = http.MaxBytesReader(w, r.Body, MaxPhotoSize+1<<20)
+	if err := r.ParseMultipartForm(1 << 20); err != nil {
+		return nil, http.StatusRequestEntityTooLarge, fmt.Errorf("Invalid upload or photo larger than %d bytes", MaxPhotoSize)
+	}
+
+	file, _, err := r.FormFile("photo")
+	if err != nil {
+		return nil, http.StatusBadRequest, errors.New(`Missing "photo" file field`)
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, MaxPhotoSize+1))
+	if err != nil {
+		return nil, http.StatusBadRequest, errors.New("Error reading upload")
+	}
+	if len(data) > MaxPhotoSize {
+		return nil, http.StatusRequestEntityTooLarge, fmt.Errorf("Photo larger than %d bytes", MaxPhotoSize)
+	}
+
+	// Detect the type from the content rather than trusting the client
+	contentType := http.DetectContentType(data)
+	if contentType != "image/jpeg" && contentType != "image/png" {
+		return nil, http.StatusUnsupportedMediaType, fmt.Errorf("File type %s is not allowed, upload a JPEG or PNG", contentType)
+	}
+	return data, http.StatusOK, nil
+}
+
+// retirePhoto marks a photo that has left its owner's profile as replaced and deletes its renditions
+func (h *PhotoHandler) retirePhoto(ctx context.Context, photoID primitive.ObjectID) {
+	photo, err := h.db.GetPhotoByID(ctx, photoID)
+	if err != nil {
+		log.Printf("Error fetching photo %s to retire: %v", photoID.Hex(), err)
+		return
+	}
+	if err := h.db.ReplacePhoto(ctx, photo.ID); err != nil && err != mongo.ErrNoDocuments {
+		log.Printf("Error retiring photo %s: %v", photo.ID.Hex(), err)
+		return
+	}
+	h.deleteRenditions(ctx, photo)
+}
+
+func (h *PhotoHandler) deleteRenditions(ctx context.Context, photo *models.ProfilePhoto) {
+	for _, key := range []string{photo.ThumbnailKey, photo.MediumKey} {
+		if err := h.store.Delete(ctx, key); err != nil && err != storage.ErrNotFound {
+			log.Printf("Error deleting blob %s: %v", key, err)
+		}
+	}
+}
+
+// ownerFromClaims loads the logged-in cricketer or coach
+func (h *PhotoHandler) ownerFromClaims(w http.ResponseWriter, r *http.Request) (*photoOwner, bool) {
+	userID, err := subjectIDFromClaims(r)
+	if err != nil {
+		http.Error(w, "Invalid token claims", http.StatusUnauthorized)
+		return nil, false
+	}
+	ownerType := models.PhotoOwnerCricketer
+	if roleFromClaims(r) == "coach" {
+		ownerType = models.PhotoOwnerCoach
+	}
+
+	owner, err := h.loadOwner(r.Context(), ownerType, userID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "Profile not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Error fetching profile", http.StatusInternalServerError)
+		}
+		return nil, false
+	}
+	return owner, true
+}
+
+func (h *PhotoHandler) loadOwner(ctx context.Context, ownerType string, id primitive.ObjectID) (*photoOwner, error) {
+	if ownerType == models.PhotoOwnerCoach {
+		coach, err := h.db.GetCoachByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		return &photoOwner{Type: ownerType, ID: coach.ID, PhotoID: coach.PhotoID, Name: coach.Name, Mobile: coach.Mobile}, nil
+	}
+	cricketer, err := h.db.GetCricketerByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return cricketerPhotoOwner(cricketer), nil
+}
+
+// photoURLs signs links to an active photo's renditions. Other photos have no links.
+func photoURLs(signer *storage.URLSigner, photo *models.ProfilePhoto) *models.PhotoURLs {
+	if photo == nil || photo.Status != models.PhotoActive {
+		return nil
+	}
+	base := "/api/photos/" + photo.ID.Hex() + "/"
+	thumbnail, expiresAt := signer.Sign(base + photoThumbnail)
+	medium, _ := signer.Sign(base + photoMedium)
+	return &models.PhotoURLs{ThumbnailURL: thumbnail, MediumURL: medium, ExpiresAt: expiresAt}
+}
+
+// profilePhotoURLs returns links to the photos on several profiles, keyed by photo ID
+func profilePhotoURLs(ctx context.Context, database db.Database, signer *storage.URLSigner, photoIDs []*primitive.ObjectID) (map[primitive.ObjectID]*models.PhotoURLs, error) {
+	var ids []primitive.ObjectID
+	for _, id := range photoIDs {
+		if id != nil {
+			ids = append(ids, *id)
+		}
+	}
+	urls := make(map[primitive.ObjectID]*models.PhotoURLs, len(ids))
+	if len(ids) == 0 {
+		return urls, nil
+	}
+
+	photos, err := database.GetPhotosByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for i := range photos {
+		if link := photoURLs(signer, &photos[i]); link != nil {
+			urls[photos[i].ID] = link
+		}
+	}
+	return urls, nil
+}
+
+// profilePhotoURL returns links to the photo on one profile, or nil if it has none
+func profilePhotoURL(ctx context.Context, database db.Database, signer *storage.URLSigner, photoID *primitive.ObjectID) (*models.PhotoURLs, error) {
+	if photoID == nil {
+		return nil, nil
+	}
+	urls, err := profilePhotoURLs(ctx, database, signer, []*primitive.ObjectID{photoID})
+	if err != nil {
+		return nil, err
+	}
+	return urls[*photoID], nil
+}
+
+// profilePhotoJPEG reads the medium rendition of the photo on a profile, or returns nil if it has none
+func profilePhotoJPEG(ctx context.Context, database db.Database, store storage.BlobStore, photoID *primitive.ObjectID) ([]byte, error) {
+	if photoID == nil {
+		return nil, nil
+	}
+	photo, err := database.GetPhotoByID(ctx, *photoID)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if photo.Status != models.PhotoActive {
+		return nil, nil
+	}
+
+	body, err := store.Get(ctx, photo.MediumKey)
+	if err == storage.ErrNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	return io.ReadAll(body)
+}
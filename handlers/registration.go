@@ -1,70 +1,194 @@
 package handlers
 
 import (
+	"context"
+	"crypto/rand"
 	"encoding/json"
+	"fmt"
+	"log"
+	"math/big"
 	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
 
+	"cricketApp/antibot"
 	"cricketApp/db"
+	"cricketApp/formnumber"
+	"cricketApp/middleware/ratelimit"
 	"cricketApp/models"
+	"cricketApp/notification"
+	"cricketApp/storage"
+	"cricketApp/virusscan"
 
-	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/v5"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	defaultRegistrationPowDifficulty = 18
+	registrationChallengeTTL         = 10 * time.Minute
 )
 
 type RegistrationHandler struct {
-	db db.Database
+	db          db.Database
+	store       storage.BlobStore
+	scanner     virusscan.Scanner
+	notifier    notification.Notifier
+	formNumbers formnumber.Config
+	challenger  *antibot.Challenger
+	secret      []byte // keys proof-of-work challenges and verification code hashes
+	appBaseURL  string // where password setup links point
 }
 
-func NewRegistrationHandler(db db.Database) *RegistrationHandler {
-	return &RegistrationHandler{db: db}
+func NewRegistrationHandler(db db.Database, store storage.BlobStore, scanner virusscan.Scanner, notifier notification.Notifier, secret []byte) *RegistrationHandler {
+	difficulty, err := strconv.Atoi(os.Getenv("REGISTRATION_POW_DIFFICULTY"))
+	if err != nil || difficulty < 0 {
+		difficulty = defaultRegistrationPowDifficulty
+	}
+	return &RegistrationHandler{
+		db:          db,
+		store:       store,
+		scanner:     scanner,
+		notifier:    notifier,
+		formNumbers: formnumber.ConfigFromEnv(),
+		challenger:  antibot.NewChallenger(secret, difficulty, registrationChallengeTTL),
+		secret:      secret,
+		appBaseURL:  strings.TrimRight(os.Getenv("APP_BASE_URL"), "/"),
+	}
+}
+
+// GetRegistrationChallenge issues a proof-of-work challenge that must be solved to submit a registration (public)
+func (h *RegistrationHandler) GetRegistrationChallenge(w http.ResponseWriter, r *http.Request) {
+	challenge, err := h.challenger.NewChallenge()
+	if err != nil {
+		http.Error(w, "Error creating challenge", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(challenge)
 }
 
-// CreateRegistration handles the creation of a new registration form
+// CreateRegistration handles a public registration application.
+// The applicant must have solved a proof-of-work challenge and verified their email and phone number.
 func (h *RegistrationHandler) CreateRegistration(w http.ResponseWriter, r *http.Request) {
 	var req models.CreateRegistrationRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+	if !decodeJSON(w, r, &req) {
 		return
 	}
 
-	// Create new registration form
-	registration := &models.RegistrationForm{
-		FormNo:           req.FormNo,
-		Date:             req.Date,
-		Reference:        req.Reference,
-		FullName:         req.FullName,
-		DateOfBirth:      req.DateOfBirth,
-		ResidenceAddress: req.ResidenceAddress,
-		ContactNo:        req.ContactNo,
-		Email:            req.Email,
-		Education:        req.Education,
-		SchoolCollege:    req.SchoolCollege,
-		AadhaarNo:        req.AadhaarNo,
-		Whatsapp:         req.Whatsapp,
-		ParentDetails:    req.ParentDetails,
+	// Bots fill in the hidden website field; pretend it worked so they don't adapt
+	if req.Website != "" {
+		log.Printf("Registration honeypot triggered from %s", ratelimit.ClientIP(r))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"message": "Registration created successfully",
+		})
+		return
+	}
+
+	if !validateRequest(w, &req) {
+		return
+	}
+
+	if err := h.challenger.Verify(req.Challenge, req.Solution); err != nil {
+		http.Error(w, "Anti-spam check failed: "+err.Error(), http.StatusForbidden)
+		return
+	}
+
+	email := normalizeEmail(req.Email)
+	contactNo := normalizeMobile(req.ContactNo)
+	aadhaarNo := onlyDigits(req.AadhaarNo)
+
+	// Reject Aadhaar numbers that already have an active application
+	duplicates, err := h.db.FindRegistrationDuplicates(r.Context(), aadhaarNo, mobileVariants(contactNo), strings.TrimSpace(req.FullName), req.DateOfBirth)
+	if err != nil {
+		http.Error(w, "Error checking for duplicate registrations", http.StatusInternalServerError)
+		return
+	}
+	for _, duplicate := range duplicates {
+		if duplicate.Field == models.DuplicateOnAadhaar {
+			http.Error(w, "A registration with this Aadhaar number already exists", http.StatusConflict)
+			return
+		}
 	}
 
-	// Convert CricketerID string to ObjectID
-	cricketerID, err := primitive.ObjectIDFromHex(req.CricketerID)
+	// Each verification can only be used for one registration
+	if !h.consumeVerification(w, r, req.EmailVerificationID, models.VerificationEmail, email) ||
+		!h.consumeVerification(w, r, req.PhoneVerificationID, models.VerificationPhone, contactNo) {
+		return
+	}
+
+	// The applicant uploads their documents with this token
+	documentToken, documentTokenHash, err := generateDocumentToken()
 	if err != nil {
-		http.Error(w, "Invalid cricketer ID format", http.StatusBadRequest)
+		http.Error(w, "Error creating registration", http.StatusInternalServerError)
 		return
 	}
-	registration.CricketerID = cricketerID
 
-	if err := h.db.CreateRegistration(r.Context(), registration); err != nil {
+	// Create new registration form
+	registration := &models.RegistrationForm{
+		Date:               req.Date,
+		Reference:          req.Reference,
+		FullName:           strings.TrimSpace(req.FullName),
+		DateOfBirth:        req.DateOfBirth,
+		ResidenceAddress:   req.ResidenceAddress,
+		ContactNo:          contactNo,
+		Email:              email,
+		Education:          req.Education,
+		SchoolCollege:      req.SchoolCollege,
+		AadhaarNo:          aadhaarNo,
+		Whatsapp:           normalizeMobile(req.Whatsapp),
+		ParentDetails:      req.ParentDetails,
+		EmailVerified:      true,
+		PhoneVerified:      true,
+		PossibleDuplicates: duplicates, // siblings often share a phone number, so these are flagged for review rather than rejected
+		SubmittedFromIP:    ratelimit.ClientIP(r),
+		DocumentTokenHash:  documentTokenHash,
+	}
+
+	if err := h.createWithFormNo(r.Context(), registration); err != nil {
+		log.Printf("Error creating registration: %v", err)
 		http.Error(w, "Failed to create registration", http.StatusInternalServerError)
 		return
 	}
 
+	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(map[string]interface{}{
-		"message":      "Registration created successfully",
-		"registration": registration,
+		"message":        "Registration created successfully",
+		"registrationId": registration.ID.Hex(),
+		"formNo":         registration.FormNo,
+		"documentToken":  documentToken,
+		"documentTypes":  models.RegistrationDocumentTypes,
 	})
 }
 
+// consumeVerification marks a confirmed verification of target as used, writing an error response if it can't be
+func (h *RegistrationHandler) consumeVerification(w http.ResponseWriter, r *http.Request, verificationID string, channel string, target string) bool {
+	id, err := primitive.ObjectIDFromHex(verificationID)
+	if err != nil {
+		http.Error(w, "Invalid "+channel+" verification ID", http.StatusBadRequest)
+		return false
+	}
+
+	err = h.db.ConsumeVerification(r.Context(), id, channel, target, time.Now().Add(-verificationValidForUse))
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "The "+channel+" has not been verified, or the verification has expired or was already used", http.StatusForbidden)
+		} else {
+			http.Error(w, "Error checking "+channel+" verification", http.StatusInternalServerError)
+		}
+		return false
+	}
+	return true
+}
+
 // GetRegistration retrieves a registration by ID
 func (h *RegistrationHandler) GetRegistration(w http.ResponseWriter, r *http.Request) {
 	registrationID := chi.URLParam(r, "id")
@@ -90,17 +214,31 @@ func (h *RegistrationHandler) GetRegistration(w http.ResponseWriter, r *http.Req
 		return
 	}
 
+	h.setAgeCategories(r, registration)
+	registration.MaskPII()
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(registration)
 }
 
-// GetAllRegistrations retrieves all registrations
+// GetAllRegistrations lists registrations, optionally filtered and paginated by query parameters
+// (see registrationFilterFromQuery). The total number of matches is returned in X-Total-Count.
 func (h *RegistrationHandler) GetAllRegistrations(w http.ResponseWriter, r *http.Request) {
-	registrations, err := h.db.GetAllRegistrations(r.Context())
+	filter, ok := registrationFilterFromQuery(w, r)
+	if !ok {
+		return
+	}
+
+	registrations, total, err := h.db.SearchRegistrations(r.Context(), filter)
 	if err != nil {
 		http.Error(w, "Error fetching registrations", http.StatusInternalServerError)
 		return
 	}
+	w.Header().Set("X-Total-Count", strconv.FormatInt(total, 10))
+
+	h.setAgeCategories(r, registrations...)
+	for _, registration := range registrations {
+		registration.MaskPII()
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(registrations)
@@ -134,15 +272,23 @@ func (h *RegistrationHandler) UpdateRegistration(w http.ResponseWriter, r *http.
 
 	// Decode update request
 	var updateData models.UpdateRegistrationRequest
-	if err := json.NewDecoder(r.Body).Decode(&updateData); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+	if !decodeRequest(w, r, &updateData) {
 		return
 	}
 
-	// Update fields if provided
+	// Form numbers are allocated by the server
 	if updateData.FormNo != nil {
-		registration.FormNo = *updateData.FormNo
+		http.Error(w, "Form number cannot be edited", http.StatusBadRequest)
+		return
 	}
+
+	// Status changes must go through the review workflow
+	if updateData.Status != nil {
+		http.Error(w, "Status cannot be edited directly, use POST /api/registrations/{id}/status", http.StatusBadRequest)
+		return
+	}
+
+	// Update fields if provided
 	if updateData.Date != nil {
 		registration.Date = *updateData.Date
 	}
@@ -158,8 +304,9 @@ func (h *RegistrationHandler) UpdateRegistration(w http.ResponseWriter, r *http.
 	if updateData.ResidenceAddress != nil {
 		registration.ResidenceAddress = *updateData.ResidenceAddress
 	}
+	// Mobile numbers are stored normalized so phone searches and duplicate checks find them
 	if updateData.ContactNo != nil {
-		registration.ContactNo = *updateData.ContactNo
+		registration.ContactNo = normalizeMobile(*updateData.ContactNo)
 	}
 	if updateData.Email != nil {
 		registration.Email = *updateData.Email
@@ -174,14 +321,11 @@ func (h *RegistrationHandler) UpdateRegistration(w http.ResponseWriter, r *http.
 		registration.AadhaarNo = *updateData.AadhaarNo
 	}
 	if updateData.Whatsapp != nil {
-		registration.Whatsapp = *updateData.Whatsapp
+		registration.Whatsapp = normalizeMobile(*updateData.Whatsapp)
 	}
 	if updateData.ParentDetails != nil {
 		registration.ParentDetails = *updateData.ParentDetails
 	}
-	if updateData.Status != nil {
-		registration.Status = *updateData.Status
-	}
 
 	// Update registration in database
 	if err := h.db.UpdateRegistration(r.Context(), objID, registration); err != nil {
@@ -192,6 +336,380 @@ func (h *RegistrationHandler) UpdateRegistration(w http.ResponseWriter, r *http.
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(map[string]interface{}{
 		"message":      "Registration updated successfully",
-		"registration": registration,
+		"registration": maskedRegistration(registration),
+	})
+}
+
+// RevealRegistration returns a registration with its sensitive fields unmasked.
+// Requires the pii:reveal permission and a reason, and every reveal is recorded in the audit log.
+func (h *RegistrationHandler) RevealRegistration(w http.ResponseWriter, r *http.Request) {
+	objID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid registration ID", http.StatusBadRequest)
+		return
+	}
+
+	admin, ok := requireAdminPermission(w, r, h.db, models.PermissionRevealPII)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Reason string `json:"reason" binding:"required"`
+	}
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	registration, err := h.db.GetRegistrationByID(r.Context(), objID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "Registration not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Error fetching registration", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	// Nothing is revealed unless the access was recorded
+	entry := &models.AuditEntry{
+		Action:     models.AuditRevealPII,
+		TargetType: "registration",
+		TargetID:   objID.Hex(),
+		Reason:     strings.TrimSpace(req.Reason),
+	}
+	if err := recordAudit(r, h.db, admin, entry); err != nil {
+		log.Printf("Error recording PII reveal for registration %s: %v", objID.Hex(), err)
+		http.Error(w, "Error recording access", http.StatusInternalServerError)
+		return
+	}
+
+	h.setAgeCategories(r, registration)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(registration)
+}
+
+// setAgeCategories fills in each registration's age category for the current season
+func (h *RegistrationHandler) setAgeCategories(r *http.Request, registrations ...*models.RegistrationForm) {
+	season, err := seasonAt(r.Context(), h.db, time.Now())
+	if err != nil {
+		log.Printf("Error fetching season for age categories: %v", err)
+		return
+	}
+	for _, registration := range registrations {
+		registration.AgeCategory = ageCategoryAt(&registration.DateOfBirth, season)
+	}
+}
+
+// maskedRegistration masks a registration's sensitive fields for a response
+func maskedRegistration(registration *models.RegistrationForm) *models.RegistrationForm {
+	if registration != nil {
+		registration.MaskPII()
+	}
+	return registration
+}
+
+// GetDuplicateFormNumbers reports form numbers shared by more than one registration (admin only)
+func (h *RegistrationHandler) GetDuplicateFormNumbers(w http.ResponseWriter, r *http.Request) {
+	duplicates, err := h.db.FindDuplicateFormNumbers(r.Context())
+	if err != nil {
+		http.Error(w, "Error checking form numbers", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(duplicates)
+}
+
+// createWithFormNo allocates the next form number and inserts the registration.
+// Allocation is retried if the number is already taken, e.g. by a manually entered legacy form number.
+func (h *RegistrationHandler) createWithFormNo(ctx context.Context, registration *models.RegistrationForm) error {
+	const maxAttempts = 5
+
+	now := time.Now()
+	var err error
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		var seq int64
+		seq, err = h.db.NextSequence(ctx, h.formNumbers.CounterName(now))
+		if err != nil {
+			return err
+		}
+		registration.FormNo = h.formNumbers.Format(now, seq)
+
+		err = h.db.CreateRegistration(ctx, registration)
+		if !mongo.IsDuplicateKeyError(err) {
+			return err
+		}
+	}
+	return err
+}
+
+// TransitionRegistration moves a registration through the review workflow (admin only).
+// Approving provisions the cricketer account, starts their membership and assigns their batch.
+func (h *RegistrationHandler) TransitionRegistration(w http.ResponseWriter, r *http.Request) {
+	objID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid registration ID", http.StatusBadRequest)
+		return
+	}
+
+	reviewerID, err := subjectIDFromClaims(r)
+	if err != nil {
+		http.Error(w, "Invalid token claims", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.RegistrationTransitionRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	registration, err := h.db.GetRegistrationByID(r.Context(), objID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "Registration not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Error fetching registration", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	if !models.CanTransitionRegistration(registration.Status, req.Status) {
+		http.Error(w, fmt.Sprintf("Cannot change registration status from %q to %q", registration.Status, req.Status), http.StatusConflict)
+		return
+	}
+	if (req.Status == models.RegistrationRejected || req.Status == models.RegistrationWaitlisted) && strings.TrimSpace(req.Reason) == "" {
+		writeFieldError(w, "reason", "required", "reason is required to reject or waitlist a registration")
+		return
+	}
+
+	change := models.RegistrationStatusChange{
+		From:      registration.Status,
+		To:        req.Status,
+		Reason:    req.Reason,
+		ChangedBy: reviewerID.Hex(),
+		ChangedAt: time.Now(),
+	}
+
+	if req.Status != models.RegistrationApproved {
+		if err := h.db.TransitionRegistration(r.Context(), objID, change); err != nil {
+			writeTransitionError(w, err)
+			return
+		}
+		h.notifyApplicant(r, registration, change, "")
+
+		registration, _ = h.db.GetRegistrationByID(r.Context(), objID)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"message":      "Registration status updated successfully",
+			"registration": maskedRegistration(registration),
+		})
+		return
+	}
+
+	// All required documents must have been accepted
+	documents, err := h.db.GetRegistrationDocuments(r.Context(), objID)
+	if err != nil {
+		http.Error(w, "Error fetching documents", http.StatusInternalServerError)
+		return
+	}
+	if missing := missingRequiredDocuments(documents); len(missing) > 0 {
+		http.Error(w, "Required documents have not been accepted: "+strings.Join(missing, ", "), http.StatusConflict)
+		return
+	}
+
+	approval := models.RegistrationApproval{
+		Change:      change,
+		JoiningDate: time.Now(),
+	}
+	if req.JoiningDate != nil {
+		approval.JoiningDate = *req.JoiningDate
+	}
+	if req.BatchID != "" {
+		batchID, err := primitive.ObjectIDFromHex(req.BatchID)
+		if err != nil {
+			http.Error(w, "Invalid batch ID", http.StatusBadRequest)
+			return
+		}
+		batch, err := h.db.GetBatchByID(r.Context(), batchID)
+		if err != nil {
+			if err == mongo.ErrNoDocuments {
+				http.Error(w, "Batch not found", http.StatusNotFound)
+			} else {
+				http.Error(w, "Error fetching batch", http.StatusInternalServerError)
+			}
+			return
+		}
+		season, err := seasonAt(r.Context(), h.db, approval.JoiningDate)
+		if err != nil {
+			http.Error(w, "Error fetching season", http.StatusInternalServerError)
+			return
+		}
+		if err := checkAgeEligibility(batch.AgeCategory, &registration.DateOfBirth, season); err != nil {
+			http.Error(w, "Applicant is not eligible for this "+batch.AgeCategory+" batch: "+err.Error(), http.StatusConflict)
+			return
+		}
+		approval.BatchID = &batchID
+	}
+	if !h.approvalLink(w, r, registration, req.CricketerID, &approval) {
+		return
+	}
+
+	// Prepare an account in case the applicant doesn't have one yet. Its password is random and never
+	// shared; the applicant chooses their own from a one-time setup link.
+	randomPassword, err := generateRandomPassword()
+	if err != nil {
+		http.Error(w, "Error generating password", http.StatusInternalServerError)
+		return
+	}
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(randomPassword), bcrypt.DefaultCost)
+	if err != nil {
+		http.Error(w, "Error hashing password", http.StatusInternalServerError)
+		return
+	}
+	setupToken, setupHash, err := generateDocumentToken()
+	if err != nil {
+		http.Error(w, "Error generating setup link", http.StatusInternalServerError)
+		return
+	}
+	setupExpiresAt := time.Now().Add(passwordSetupTTL)
+	approval.NewCricketer = &models.Cricketer{
+		ID:                     primitive.NewObjectID(),
+		Name:                   registration.FullName,
+		Mobile:                 registration.ContactNo,
+		Email:                  registration.Email,
+		Password:               string(hashedPassword),
+		CreatedAt:              time.Now(),
+		DateOfBirth:            &registration.DateOfBirth,
+		PasswordSetupHash:      setupHash,
+		PasswordSetupExpiresAt: &setupExpiresAt,
+	}
+
+	cricketer, created, err := h.db.ApproveRegistration(r.Context(), objID, approval)
+	if err != nil {
+		writeTransitionError(w, err)
+		return
+	}
+
+	if !created {
+		setupToken = ""
+	}
+	h.notifyApplicant(r, registration, change, setupToken)
+
+	registration, _ = h.db.GetRegistrationByID(r.Context(), objID)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":          "Registration approved successfully",
+		"registration":     maskedRegistration(registration),
+		"cricketerId":      cricketer.ID.Hex(),
+		"cricketerCreated": created,
 	})
 }
+
+// approvalLink decides which existing cricketer, if any, an approval links the registration to.
+// An account that only shares the applicant's mobile number is never linked on its own, since
+// siblings often share a parent's phone; the reviewer has to confirm it by passing its cricketerId.
+func (h *RegistrationHandler) approvalLink(w http.ResponseWriter, r *http.Request, registration *models.RegistrationForm, cricketerID string, approval *models.RegistrationApproval) bool {
+	if !registration.CricketerID.IsZero() {
+		return true
+	}
+
+	if cricketerID != "" {
+		objID, _ := primitive.ObjectIDFromHex(cricketerID)
+		if _, err := h.db.GetCricketerByID(r.Context(), objID); err != nil {
+			if err == mongo.ErrNoDocuments {
+				writeFieldError(w, "cricketerId", "exists", "cricketer not found")
+			} else {
+				http.Error(w, "Error fetching cricketer", http.StatusInternalServerError)
+			}
+			return false
+		}
+		approval.CricketerID = &objID
+		return true
+	}
+
+	if _, err := h.db.GetCricketerByEmail(r.Context(), registration.Email); err == nil {
+		return true
+	} else if err != mongo.ErrNoDocuments {
+		http.Error(w, "Error fetching cricketer", http.StatusInternalServerError)
+		return false
+	}
+
+	for _, mobile := range mobileVariants(registration.ContactNo) {
+		existing, err := h.db.GetCricketerByMobile(r.Context(), mobile)
+		if err == mongo.ErrNoDocuments {
+			continue
+		}
+		if err != nil {
+			http.Error(w, "Error fetching cricketer", http.StatusInternalServerError)
+			return false
+		}
+		http.Error(w, fmt.Sprintf("Cricketer %s (%s) already has this mobile number. Approve with their cricketerId if this is the same person, or change the registration's contact number.",
+			existing.Name, existing.ID.Hex()), http.StatusConflict)
+		return false
+	}
+	return true
+}
+
+// notifyApplicant tells the applicant about a decision on their registration.
+// setupToken is given when a new account was created for them, so they can choose a password.
+func (h *RegistrationHandler) notifyApplicant(r *http.Request, registration *models.RegistrationForm, change models.RegistrationStatusChange, setupToken string) {
+	var message string
+	switch change.To {
+	case models.RegistrationApproved:
+		message = fmt.Sprintf("Welcome to the academy, %s! Your registration (form %s) has been approved.", registration.FullName, registration.FormNo)
+		if setupToken != "" {
+			message += " " + h.passwordSetupMessage(registration.ContactNo, setupToken)
+		}
+	case models.RegistrationRejected:
+		message = fmt.Sprintf("Your registration (form %s) was not accepted: %s", registration.FormNo, change.Reason)
+	case models.RegistrationWaitlisted:
+		message = fmt.Sprintf("Your registration (form %s) has been waitlisted: %s", registration.FormNo, change.Reason)
+	default:
+		return
+	}
+
+	recipient := notification.Recipient{
+		Name:   registration.FullName,
+		Mobile: registration.ContactNo,
+		Email:  registration.Email,
+	}
+	ctx := r.Context()
+	if setupToken != "" {
+		ctx = notification.WithSecret(ctx) // the setup link works as a password until it is used
+	}
+	if err := h.notifier.Notify(ctx, recipient, "Registration "+change.To, message); err != nil {
+		log.Printf("Error notifying applicant for registration %s: %v", registration.ID.Hex(), err)
+	}
+}
+
+func writeTransitionError(w http.ResponseWriter, err error) {
+	switch {
+	case err == db.ErrRegistrationStatusChanged:
+		http.Error(w, "Registration status was changed by someone else, reload and try again", http.StatusConflict)
+	case err == db.ErrRegistrationDocumentsChanged:
+		http.Error(w, "Required documents were changed by someone else, reload and try again", http.StatusConflict)
+	case err == mongo.ErrNoDocuments:
+		http.Error(w, "Registration not found", http.StatusNotFound)
+	case mongo.IsDuplicateKeyError(err):
+		http.Error(w, "A cricketer with this email or mobile number already exists", http.StatusConflict)
+	default:
+		log.Printf("Error updating registration status: %v", err)
+		http.Error(w, "Error updating registration status", http.StatusInternalServerError)
+	}
+}
+
+// generateRandomPassword creates a random password for a newly provisioned account. It is never
+// shown to anyone; it only keeps the account locked until the applicant chooses a password.
+func generateRandomPassword() (string, error) {
+	const alphabet = "abcdefghjkmnpqrstuvwxyzABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	password := make([]byte, 10)
+	for i := range password {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(alphabet))))
+		if err != nil {
+			return "", err
+		}
+		password[i] = alphabet[n.Int64()]
+	}
+	return string(password), nil
+}
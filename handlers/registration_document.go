@@ -0,0 +1,328 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"cricketApp/models"
+	"cricketApp/notification"
+)
+
+// registrationTokenHeader carries the document token returned to the applicant when they register
+const registrationTokenHeader = "X-Registration-Token"
+
+// ListApplicantDocuments lists the document slots of the applicant's registration (public, requires the document token)
+func (h *RegistrationHandler) ListApplicantDocuments(w http.ResponseWriter, r *http.Request) {
+	registration, ok := h.registrationForApplicant(w, r)
+	if !ok {
+		return
+	}
+	h.writeDocumentSlots(w, r, registration.ID)
+}
+
+// UploadApplicantDocument uploads a document for the applicant's registration (public, requires the document token)
+func (h *RegistrationHandler) UploadApplicantDocument(w http.ResponseWriter, r *http.Request) {
+	registration, ok := h.registrationForApplicant(w, r)
+	if !ok {
+		return
+	}
+	h.uploadDocument(w, r, registration, "applicant")
+}
+
+// ListRegistrationDocuments lists a registration's document slots (admin only)
+func (h *RegistrationHandler) ListRegistrationDocuments(w http.ResponseWriter, r *http.Request) {
+	registration, ok := h.registrationFromURL(w, r)
+	if !ok {
+		return
+	}
+	h.writeDocumentSlots(w, r, registration.ID)
+}
+
+// UploadRegistrationDocument uploads a document on an applicant's behalf, e.g. one sent on WhatsApp (admin only)
+func (h *RegistrationHandler) UploadRegistrationDocument(w http.ResponseWriter, r *http.Request) {
+	adminID, err := subjectIDFromClaims(r)
+	if err != nil {
+		http.Error(w, "Invalid token claims", http.StatusUnauthorized)
+		return
+	}
+	registration, ok := h.registrationFromURL(w, r)
+	if !ok {
+		return
+	}
+	h.uploadDocument(w, r, registration, adminID.Hex())
+}
+
+// DownloadRegistrationDocument streams an uploaded registration document (admin only)
+func (h *RegistrationHandler) DownloadRegistrationDocument(w http.ResponseWriter, r *http.Request) {
+	registrationID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid registration ID", http.StatusBadRequest)
+		return
+	}
+
+	document, err := h.db.GetRegistrationDocument(r.Context(), registrationID, chi.URLParam(r, "type"))
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "Document not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Error fetching document", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	serveBlob(w, r, h.store, &models.Attachment{
+		Key:         document.Key,
+		FileName:    document.FileName,
+		ContentType: document.ContentType,
+		Size:        document.Size,
+	}, "inline")
+}
+
+// ReviewRegistrationDocument accepts a document or asks the applicant to upload it again (admin only)
+func (h *RegistrationHandler) ReviewRegistrationDocument(w http.ResponseWriter, r *http.Request) {
+	reviewerID, err := subjectIDFromClaims(r)
+	if err != nil {
+		http.Error(w, "Invalid token claims", http.StatusUnauthorized)
+		return
+	}
+	registration, ok := h.registrationFromURL(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.ReviewDocumentRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	switch req.Status {
+	case models.DocumentAccepted:
+	case models.DocumentNeedsReupload:
+		if strings.TrimSpace(req.Note) == "" {
+			writeFieldError(w, "note", "required", "note explaining what needs to change is required")
+			return
+		}
+	}
+
+	documentType := chi.URLParam(r, "type")
+	document, err := h.db.GetRegistrationDocument(r.Context(), registration.ID, documentType)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "Document not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Error fetching document", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	err = h.db.ReviewRegistrationDocument(r.Context(), registration.ID, documentType, document.Key, req.Status, req.Note, reviewerID.Hex())
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "The document was replaced while you were reviewing it, reload and try again", http.StatusConflict)
+		} else {
+			http.Error(w, "Error reviewing document", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	if req.Status == models.DocumentNeedsReupload {
+		label := documentType
+		if t, ok := models.LookupDocumentType(documentType); ok {
+			label = t.Label
+		}
+		recipient := notification.Recipient{
+			Name:   registration.FullName,
+			Mobile: registration.ContactNo,
+			Email:  registration.Email,
+		}
+		message := fmt.Sprintf("Please upload your %s again for registration %s: %s", label, registration.FormNo, req.Note)
+		if err := h.notifier.Notify(r.Context(), recipient, "Document needs re-upload", message); err != nil {
+			log.Printf("Error notifying applicant for registration %s: %v", registration.ID.Hex(), err)
+		}
+	}
+
+	document, _ = h.db.GetRegistrationDocument(r.Context(), registration.ID, documentType)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":  "Document reviewed successfully",
+		"document": document,
+	})
+}
+
+// uploadDocument stores the uploaded "file" for the document slot in the URL and resets its review
+func (h *RegistrationHandler) uploadDocument(w http.ResponseWriter, r *http.Request, registration *models.RegistrationForm, uploadedBy string) {
+	documentType, ok := models.LookupDocumentType(chi.URLParam(r, "type"))
+	if !ok {
+		http.Error(w, "Unknown document type", http.StatusNotFound)
+		return
+	}
+	if registration.Status == models.RegistrationApproved || registration.Status == models.RegistrationRejected {
+		http.Error(w, "Documents can't be changed after a registration has been "+registration.Status, http.StatusConflict)
+		return
+	}
+
+	allowedTypes := make(map[string]bool, len(documentType.ContentTypes))
+	for _, contentType := range documentType.ContentTypes {
+		allowedTypes[contentType] = true
+	}
+
+	prefix := "registrations/" + registration.ID.Hex() + "/" + documentType.Type
+	upload, status, err := saveUpload(r, w, h.store, h.scanner, prefix, "file", documentType.MaxSize, allowedTypes)
+	if err != nil {
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	document := &models.RegistrationDocument{
+		RegistrationID: registration.ID,
+		Type:           documentType.Type,
+		Key:            upload.Key,
+		FileName:       upload.FileName,
+		ContentType:    upload.ContentType,
+		Size:           upload.Size,
+		Scan:           *upload.Scan,
+		Status:         models.DocumentPendingReview,
+		UploadedBy:     uploadedBy,
+	}
+
+	previous, err := h.db.SaveRegistrationDocument(r.Context(), document)
+	if err != nil {
+		// Don't leave an orphaned blob behind
+		if delErr := h.store.Delete(r.Context(), upload.Key); delErr != nil {
+			log.Printf("Error deleting blob %s after failed insert: %v", upload.Key, delErr)
+		}
+		http.Error(w, "Error saving document", http.StatusInternalServerError)
+		return
+	}
+	if previous != nil {
+		if err := h.store.Delete(r.Context(), previous.Key); err != nil {
+			log.Printf("Error deleting replaced document blob %s: %v", previous.Key, err)
+		}
+	}
+
+	document, err = h.db.GetRegistrationDocument(r.Context(), registration.ID, documentType.Type)
+	if err != nil {
+		http.Error(w, "Error fetching document", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":  "Document uploaded successfully",
+		"document": document,
+	})
+}
+
+// writeDocumentSlots responds with every document slot and the document uploaded for it
+func (h *RegistrationHandler) writeDocumentSlots(w http.ResponseWriter, r *http.Request, registrationID primitive.ObjectID) {
+	documents, err := h.db.GetRegistrationDocuments(r.Context(), registrationID)
+	if err != nil {
+		http.Error(w, "Error fetching documents", http.StatusInternalServerError)
+		return
+	}
+
+	byType := make(map[string]*models.RegistrationDocument, len(documents))
+	for i := range documents {
+		byType[documents[i].Type] = &documents[i]
+	}
+
+	slots := make([]models.RegistrationDocumentSlot, 0, len(models.RegistrationDocumentTypes))
+	for _, documentType := range models.RegistrationDocumentTypes {
+		slots = append(slots, models.RegistrationDocumentSlot{
+			DocumentType: documentType,
+			Document:     byType[documentType.Type],
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"documents":        slots,
+		"missingDocuments": missingRequiredDocuments(documents),
+	})
+}
+
+// missingRequiredDocuments lists the required document types that haven't been accepted yet
+func missingRequiredDocuments(documents []models.RegistrationDocument) []string {
+	accepted := make(map[string]bool, len(documents))
+	for _, document := range documents {
+		if document.Status == models.DocumentAccepted {
+			accepted[document.Type] = true
+		}
+	}
+
+	missing := []string{}
+	for _, documentType := range models.RegistrationDocumentTypes {
+		if documentType.Required && !accepted[documentType.Type] {
+			missing = append(missing, documentType.Type)
+		}
+	}
+	return missing
+}
+
+// registrationFromURL loads the registration named by the {id} URL parameter, writing an error response if it can't
+func (h *RegistrationHandler) registrationFromURL(w http.ResponseWriter, r *http.Request) (*models.RegistrationForm, bool) {
+	registrationID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid registration ID", http.StatusBadRequest)
+		return nil, false
+	}
+
+	registration, err := h.db.GetRegistrationByID(r.Context(), registrationID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "Registration not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Error fetching registration", http.StatusInternalServerError)
+		}
+		return nil, false
+	}
+	return registration, true
+}
+
+// registrationForApplicant loads the registration in the URL and checks the request carries its document token
+func (h *RegistrationHandler) registrationForApplicant(w http.ResponseWriter, r *http.Request) (*models.RegistrationForm, bool) {
+	token := r.Header.Get(registrationTokenHeader)
+	if token == "" {
+		http.Error(w, "Missing "+registrationTokenHeader+" header", http.StatusUnauthorized)
+		return nil, false
+	}
+
+	registration, ok := h.registrationFromURL(w, r)
+	if !ok {
+		return nil, false
+	}
+	if registration.DocumentTokenHash == "" ||
+		subtle.ConstantTimeCompare([]byte(hashDocumentToken(token)), []byte(registration.DocumentTokenHash)) != 1 {
+		// Don't reveal whether the registration exists
+		http.Error(w, "Registration not found", http.StatusNotFound)
+		return nil, false
+	}
+	return registration, true
+}
+
+// generateDocumentToken creates the secret an applicant uses to upload documents, and its hash for storage
+func generateDocumentToken() (string, string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(b)
+	return token, hashDocumentToken(token), nil
+}
+
+func hashDocumentToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
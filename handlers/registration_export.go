@@ -0,0 +1,302 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"cricketApp/export"
+	"cricketApp/models"
+)
+
+const maxRegistrationPageSize = 500
+
+// registrationColumn is a column that can be included in a registration export
+type registrationColumn struct {
+	Key    string
+	Header string
+	PII    bool // masked unless the caller reveals personal information
+	Value  func(registration *models.RegistrationForm) string
+}
+
+// registrationColumns lists the exportable columns in the order they appear in a file
+var registrationColumns = []registrationColumn{
+	{"formNo", "Form No", false, func(r *models.RegistrationForm) string { return r.FormNo }},
+	{"date", "Form Date", false, func(r *models.RegistrationForm) string { return formatExportDate(r.Date) }},
+	{"reference", "Reference", false, func(r *models.RegistrationForm) string { return r.Reference }},
+	{"fullName", "Full Name", false, func(r *models.RegistrationForm) string { return r.FullName }},
+	{"dateOfBirth", "Date of Birth", false, func(r *models.RegistrationForm) string { return formatExportDate(r.DateOfBirth) }},
+	{"ageCategory", "Age Category", false, func(r *models.RegistrationForm) string { return r.AgeCategory }},
+	{"contactNo", "Contact No", false, func(r *models.RegistrationForm) string { return r.ContactNo }},
+	{"whatsapp", "WhatsApp", false, func(r *models.RegistrationForm) string { return r.Whatsapp }},
+	{"email", "Email", false, func(r *models.RegistrationForm) string { return r.Email }},
+	{"education", "Education", false, func(r *models.RegistrationForm) string { return r.Education }},
+	{"schoolCollege", "School/College", false, func(r *models.RegistrationForm) string { return r.SchoolCollege }},
+	{"aadhaarNo", "Aadhaar No", true, func(r *models.RegistrationForm) string { return r.AadhaarNo }},
+	{"residenceAddress", "Residence Address", true, func(r *models.RegistrationForm) string { return r.ResidenceAddress }},
+	{"parentName", "Parent Name", false, func(r *models.RegistrationForm) string { return r.ParentDetails.Name }},
+	{"parentContactNo", "Parent Contact No", true, func(r *models.RegistrationForm) string { return r.ParentDetails.ContactNo }},
+	{"parentOccupation", "Parent Occupation", false, func(r *models.RegistrationForm) string { return r.ParentDetails.Occupation }},
+	{"status", "Status", false, func(r *models.RegistrationForm) string { return r.Status }},
+	{"statusReason", "Status Reason", false, func(r *models.RegistrationForm) string { return r.StatusReason }},
+	{"emailVerified", "Email Verified", false, func(r *models.RegistrationForm) string { return strconv.FormatBool(r.EmailVerified) }},
+	{"phoneVerified", "Phone Verified", false, func(r *models.RegistrationForm) string { return strconv.FormatBool(r.PhoneVerified) }},
+	{"possibleDuplicates", "Possible Duplicates", false, func(r *models.RegistrationForm) string { return strconv.Itoa(len(r.PossibleDuplicates)) }},
+	{"createdAt", "Submitted At", false, func(r *models.RegistrationForm) string { return r.CreatedAt.Format(time.RFC3339) }},
+}
+
+// defaultRegistrationColumns are exported when no columns are requested
+var defaultRegistrationColumns = []string{"formNo", "date", "fullName", "dateOfBirth", "ageCategory", "contactNo", "email", "schoolCollege", "status"}
+
+// ExportRegistrations streams the registrations matching the search filters as CSV or XLSX (admin only).
+//
+// Query parameters, besides the filters of GetAllRegistrations:
+//
+//	format   csv (default) or xlsx
+//	columns  comma-separated column keys, see registrationColumns
+//	reveal   true to export personal information unmasked; requires the pii:reveal permission and a reason
+//	reason   why personal information is being revealed
+//
+// Every export is recorded in the audit log.
+func (h *RegistrationHandler) ExportRegistrations(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	format := query.Get("format")
+	if format == "" {
+		format = export.FormatCSV
+	}
+	if format != export.FormatCSV && format != export.FormatXLSX {
+		writeFieldError(w, "format", "oneof", "format must be one of: csv, xlsx")
+		return
+	}
+
+	columns, ok := registrationColumnsFromQuery(w, query.Get("columns"))
+	if !ok {
+		return
+	}
+
+	filter, ok := registrationFilterFromQuery(w, r)
+	if !ok {
+		return
+	}
+	// Exports always cover the whole result set
+	filter.Skip, filter.Limit = 0, 0
+
+	reveal := query.Get("reveal") == "true"
+	reason := strings.TrimSpace(query.Get("reason"))
+	permission := ""
+	if reveal {
+		permission = models.PermissionRevealPII
+	}
+	admin, ok := requireAdminPermission(w, r, h.db, permission)
+	if !ok {
+		return
+	}
+	if reveal && reason == "" {
+		writeFieldError(w, "reason", "required", "reason is required to reveal personal information")
+		return
+	}
+
+	// Nothing is exported unless the export was recorded
+	keys := make([]string, len(columns))
+	for i, column := range columns {
+		keys[i] = column.Key
+	}
+	entry := &models.AuditEntry{
+		Action:     models.AuditExportData,
+		TargetType: "registrations",
+		Reason:     reason,
+		Details: map[string]string{
+			"format":  format,
+			"columns": strings.Join(keys, ","),
+			"filter":  r.URL.Query().Encode(),
+		},
+	}
+	for _, column := range columns {
+		if reveal && column.PII {
+			entry.Action = models.AuditRevealPII
+		}
+	}
+	if err := recordAudit(r, h.db, admin, entry); err != nil {
+		log.Printf("Error recording registration export by %s: %v", admin.ID, err)
+		http.Error(w, "Error recording export", http.StatusInternalServerError)
+		return
+	}
+
+	season, err := seasonAt(r.Context(), h.db, time.Now())
+	if err != nil {
+		http.Error(w, "Error fetching season", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", export.ContentType(format))
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", "registrations-"+time.Now().Format("20060102")+"."+format))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+
+	writer, err := export.NewWriter(format, w, "Registrations")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	header := make([]string, len(columns))
+	for i, column := range columns {
+		header[i] = column.Header
+	}
+	if err := writer.WriteRow(header); err != nil {
+		log.Printf("Error writing registration export: %v", err)
+		return
+	}
+
+	// Headers are already sent, so failures part way through can only be logged
+	err = h.db.StreamRegistrations(r.Context(), filter, func(registration *models.RegistrationForm) error {
+		registration.AgeCategory = ageCategoryAt(&registration.DateOfBirth, season)
+		if !reveal {
+			registration.MaskPII()
+		}
+		row := make([]string, len(columns))
+		for i, column := range columns {
+			row[i] = column.Value(registration)
+		}
+		return writer.WriteRow(row)
+	})
+	if err == nil {
+		err = writer.Close()
+	}
+	if err != nil {
+		log.Printf("Error streaming registration export: %v", err)
+	}
+}
+
+// registrationFilterFromQuery reads the registration search filters from the query string:
+//
+//	name, phone, school   match anywhere in the value, ignoring case
+//	formNo                matches the start of the form number
+//	status                comma-separated statuses
+//	from, to              form date range, as YYYY-MM-DD (to is inclusive) or RFC 3339 times
+//	limit, offset         pagination; everything is returned when limit is omitted
+func registrationFilterFromQuery(w http.ResponseWriter, r *http.Request) (models.RegistrationFilter, bool) {
+	query := r.URL.Query()
+	filter := models.RegistrationFilter{
+		Name:   strings.TrimSpace(query.Get("name")),
+		FormNo: strings.TrimSpace(query.Get("formNo")),
+		School: strings.TrimSpace(query.Get("school")),
+	}
+
+	if phone := strings.TrimSpace(query.Get("phone")); phone != "" {
+		filter.Phone = normalizeMobile(phone)
+		if filter.Phone == "" {
+			writeFieldError(w, "phone", "numeric", "phone must contain digits")
+			return filter, false
+		}
+	}
+
+	if statuses := query.Get("status"); statuses != "" {
+		for _, status := range strings.Split(statuses, ",") {
+			status = strings.TrimSpace(status)
+			if !models.IsRegistrationStatus(status) {
+				writeFieldError(w, "status", "oneof", fmt.Sprintf("status %q is not a registration status", status))
+				return filter, false
+			}
+			filter.Statuses = append(filter.Statuses, status)
+		}
+	}
+
+	for _, bound := range []struct {
+		param string
+		dest  **time.Time
+		end   bool
+	}{{"from", &filter.From, false}, {"to", &filter.To, true}} {
+		value := query.Get(bound.param)
+		if value == "" {
+			continue
+		}
+		t, err := parseFilterDate(value, bound.end)
+		if err != nil {
+			writeFieldError(w, bound.param, "type", bound.param+" must be a date (YYYY-MM-DD) or an RFC 3339 time")
+			return filter, false
+		}
+		*bound.dest = &t
+	}
+	if filter.From != nil && filter.To != nil && !filter.To.After(*filter.From) {
+		writeFieldError(w, "to", "gtfield", "to must be after from")
+		return filter, false
+	}
+
+	if limit := query.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 1 || n > maxRegistrationPageSize {
+			writeFieldError(w, "limit", "max", fmt.Sprintf("limit must be between 1 and %d", maxRegistrationPageSize))
+			return filter, false
+		}
+		filter.Limit = n
+	}
+	if offset := query.Get("offset"); offset != "" {
+		n, err := strconv.Atoi(offset)
+		if err != nil || n < 0 {
+			writeFieldError(w, "offset", "min", "offset must be 0 or more")
+			return filter, false
+		}
+		filter.Skip = n
+	}
+
+	return filter, true
+}
+
+// parseFilterDate parses a date or time. A bare date used as an end bound covers the whole day.
+func parseFilterDate(value string, end bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if end {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
+
+// registrationColumnsFromQuery resolves a comma-separated list of column keys
+func registrationColumnsFromQuery(w http.ResponseWriter, param string) ([]registrationColumn, bool) {
+	keys := defaultRegistrationColumns
+	if strings.TrimSpace(param) != "" {
+		keys = strings.Split(param, ",")
+	}
+
+	columns := make([]registrationColumn, 0, len(keys))
+	for _, key := range keys {
+		column, err := lookupRegistrationColumn(strings.TrimSpace(key))
+		if err != nil {
+			writeFieldError(w, "columns", "oneof", err.Error())
+			return nil, false
+		}
+		columns = append(columns, column)
+	}
+	return columns, true
+}
+
+func lookupRegistrationColumn(key string) (registrationColumn, error) {
+	for _, column := range registrationColumns {
+		if column.Key == key {
+			return column, nil
+		}
+	}
+	valid := make([]string, len(registrationColumns))
+	for i, column := range registrationColumns {
+		valid[i] = column.Key
+	}
+	return registrationColumn{}, errors.New("unknown column " + strconv.Quote(key) + ", expected one of: " + strings.Join(valid, ", "))
+}
+
+func formatExportDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format("2006-01-02")
+}
@@ -0,0 +1,230 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"cricketApp/db"
+	"cricketApp/models"
+	"cricketApp/scoring"
+)
+
+// GetLiveScore returns the scorecard, partnerships and fall of wickets derived from a match's ball events
+func (h *MatchHandler) GetLiveScore(w http.ResponseWriter, r *http.Request) {
+	match, ok := h.matchFromURL(w, r)
+	if !ok {
+		return
+	}
+	scorer, _, ok := h.replayMatch(w, r, match)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(scorer.Score())
+}
+
+// GetBallEvents returns a match's scoring log, including undone events and the undos themselves
+func (h *MatchHandler) GetBallEvents(w http.ResponseWriter, r *http.Request) {
+	match, ok := h.matchFromURL(w, r)
+	if !ok {
+		return
+	}
+
+	events, err := h.db.GetBallEvents(r.Context(), match.ID)
+	if err != nil {
+		http.Error(w, "Error fetching ball events", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(events)
+}
+
+// RecordBallEvent starts an innings, records a delivery or ends an innings. The event is checked
+// against the match so far and appended to the log, and the derived scorecard is saved on the match.
+func (h *MatchHandler) RecordBallEvent(w http.ResponseWriter, r *http.Request) {
+	match, ok := h.scorableMatchFromURL(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.BallEventRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	scorer, events, ok := h.replayMatch(w, r, match)
+	if !ok {
+		return
+	}
+	if len(events) == 0 && len(match.Innings) > 0 {
+		http.Error(w, "This match's scorecard was entered manually and can't be scored ball by ball", http.StatusConflict)
+		return
+	}
+	if !checkExpectedSeq(w, req.Seq, scorer) {
+		return
+	}
+
+	event := models.BallEvent{
+		MatchID:     match.ID,
+		Seq:         scorer.NextSeq(),
+		Type:        req.Type,
+		BattingTeam: req.BattingTeam,
+		Striker:     req.Striker,
+		NonStriker:  req.NonStriker,
+		Bowler:      req.Bowler,
+		Runs:        req.Runs,
+		Boundary:    req.Boundary,
+		Extra:       req.Extra,
+		ExtraRuns:   req.ExtraRuns,
+		Wicket:      req.Wicket,
+		NewBatter:   req.NewBatter,
+		Reason:      req.Reason,
+	}
+	if err := scorer.Apply(event); err != nil {
+		var ruleErr *scoring.RuleError
+		if errors.As(err, &ruleErr) {
+			writeFieldError(w, ruleErr.Field, ruleErr.Code, ruleErr.Message)
+		} else {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		}
+		return
+	}
+
+	if !h.appendBallEvent(w, r, &event) {
+		return
+	}
+	h.saveLiveScore(w, r, match, scorer, event, http.StatusCreated, "Event recorded successfully")
+}
+
+// UndoBallEvent cancels the latest event still in effect by appending an undo event to the log
+func (h *MatchHandler) UndoBallEvent(w http.ResponseWriter, r *http.Request) {
+	match, ok := h.scorableMatchFromURL(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.UndoBallRequest
+	if r.ContentLength != 0 && !decodeRequest(w, r, &req) {
+		return
+	}
+
+	scorer, events, ok := h.replayMatch(w, r, match)
+	if !ok {
+		return
+	}
+	if !checkExpectedSeq(w, req.Seq, scorer) {
+		return
+	}
+	last := scorer.LastEvent()
+	if last == nil {
+		http.Error(w, "Nothing to undo", http.StatusConflict)
+		return
+	}
+
+	event := models.BallEvent{
+		MatchID: match.ID,
+		Seq:     scorer.NextSeq(),
+		Type:    models.BallEventUndo,
+		Undoes:  last.Seq,
+	}
+	if !h.appendBallEvent(w, r, &event) {
+		return
+	}
+
+	scorer, err := scoring.Replay(match, append(events, event))
+	if err != nil {
+		log.Printf("Error replaying match %s after undo: %v", match.ID.Hex(), err)
+		http.Error(w, "Error replaying ball events", http.StatusInternalServerError)
+		return
+	}
+	h.saveLiveScore(w, r, match, scorer, event, http.StatusOK, "Event undone successfully")
+}
+
+// scorableMatchFromURL loads the match in the URL for a scorer, who must be allowed to manage it
+func (h *MatchHandler) scorableMatchFromURL(w http.ResponseWriter, r *http.Request) (*models.Match, bool) {
+	match, ok := h.matchFromURL(w, r)
+	if !ok {
+		return nil, false
+	}
+	if !canManageMatch(r, match) {
+		http.Error(w, "You can only score matches you created", http.StatusForbidden)
+		return nil, false
+	}
+	if match.Result.Status == models.MatchAbandoned {
+		http.Error(w, "This match was abandoned", http.StatusConflict)
+		return nil, false
+	}
+	return match, true
+}
+
+// replayMatch rebuilds a match's state from its scoring log
+func (h *MatchHandler) replayMatch(w http.ResponseWriter, r *http.Request, match *models.Match) (*scoring.Scorer, []models.BallEvent, bool) {
+	events, err := h.db.GetBallEvents(r.Context(), match.ID)
+	if err != nil {
+		http.Error(w, "Error fetching ball events", http.StatusInternalServerError)
+		return nil, nil, false
+	}
+	scorer, err := scoring.Replay(match, events)
+	if err != nil {
+		log.Printf("Error replaying match %s: %v", match.ID.Hex(), err)
+		http.Error(w, "Error replaying ball events", http.StatusInternalServerError)
+		return nil, nil, false
+	}
+	return scorer, events, true
+}
+
+// checkExpectedSeq rejects an event from a scorer whose view of the log is out of date
+func checkExpectedSeq(w http.ResponseWriter, expected *int, scorer *scoring.Scorer) bool {
+	if expected != nil && *expected != scorer.NextSeq() {
+		http.Error(w, "The score has changed; the next event is "+strconv.Itoa(scorer.NextSeq()), http.StatusConflict)
+		return false
+	}
+	return true
+}
+
+func (h *MatchHandler) appendBallEvent(w http.ResponseWriter, r *http.Request, event *models.BallEvent) bool {
+	recordedBy, err := subjectIDFromClaims(r)
+	if err != nil {
+		http.Error(w, "Invalid token claims", http.StatusUnauthorized)
+		return false
+	}
+	event.RecordedBy = recordedBy.Hex()
+	event.RecordedByRole = roleFromClaims(r)
+
+	if err := h.db.AppendBallEvent(r.Context(), event); err != nil {
+		if err == db.ErrBallEventConflict {
+			http.Error(w, "The score has changed; refresh and try again", http.StatusConflict)
+		} else {
+			http.Error(w, "Error saving ball event", http.StatusInternalServerError)
+		}
+		return false
+	}
+	return true
+}
+
+// saveLiveScore stores the derived scorecard on the match and responds with the live score
+func (h *MatchHandler) saveLiveScore(w http.ResponseWriter, r *http.Request, match *models.Match, scorer *scoring.Scorer, event models.BallEvent, status int, message string) {
+	if err := h.db.SaveMatchScore(r.Context(), match.ID, scorer.Scorecard(), scorer.Result()); err != nil {
+		// The log is the source of truth; the next event or undo saves the scorecard again
+		log.Printf("Error saving scorecard for match %s: %v", match.ID.Hex(), err)
+	}
+
+	// Bowlers' workloads are checked as each innings ends rather than after every ball
+	score := scorer.Score()
+	if event.Type != models.BallEventUndo && len(score.Innings) > 0 && score.Innings[len(score.Innings)-1].Complete {
+		checkWorkloadAlerts(r.Context(), h.db, h.notifier, matchBowlerIDs(scorer.Scorecard()), match.Date)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": message,
+		"event":   event,
+		"score":   score,
+	})
+}
@@ -0,0 +1,317 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"cricketApp/agecategory"
+	"cricketApp/db"
+	"cricketApp/formnumber"
+	"cricketApp/models"
+)
+
+// defaultCutoffMonth and defaultCutoffDay give the BCCI age cutoff (1 September), used when no season is configured
+const (
+	defaultCutoffMonth = time.September
+	defaultCutoffDay   = 1
+)
+
+// errDateOfBirthUnknown is returned by checkAgeEligibility when the player's date of birth hasn't been recorded
+var errDateOfBirthUnknown = errors.New("date of birth is not recorded")
+
+type SeasonHandler struct {
+	db db.Database
+}
+
+func NewSeasonHandler(db db.Database) *SeasonHandler {
+	return &SeasonHandler{db: db}
+}
+
+// CreateSeason creates a season (admin only)
+func (h *SeasonHandler) CreateSeason(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateSeasonRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	season := &models.Season{
+		Name:       req.Name,
+		StartDate:  req.StartDate,
+		EndDate:    req.EndDate,
+		CutoffDate: req.CutoffDate,
+	}
+	if !h.validateSeason(w, r, primitive.NilObjectID, season) {
+		return
+	}
+
+	if err := h.db.CreateSeason(r.Context(), season); err != nil {
+		http.Error(w, "Failed to create season", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Season created successfully",
+		"season":  season,
+	})
+}
+
+// GetAllSeasons lists the configured seasons (admin only)
+func (h *SeasonHandler) GetAllSeasons(w http.ResponseWriter, r *http.Request) {
+	seasons, err := h.db.GetAllSeasons(r.Context())
+	if err != nil {
+		http.Error(w, "Error fetching seasons", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(seasons)
+}
+
+// GetCurrentSeason returns the season in progress with its cutoff date and age categories
+func (h *SeasonHandler) GetCurrentSeason(w http.ResponseWriter, r *http.Request) {
+	season, err := seasonAt(r.Context(), h.db, time.Now())
+	if err != nil {
+		http.Error(w, "Error fetching season", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"season":        season,
+		"ageCategories": agecategory.Categories,
+	})
+}
+
+// UpdateSeason updates a season (admin only)
+func (h *SeasonHandler) UpdateSeason(w http.ResponseWriter, r *http.Request) {
+	objID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid season ID", http.StatusBadRequest)
+		return
+	}
+
+	season, err := h.db.GetSeasonByID(r.Context(), objID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "Season not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Error fetching season", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	var updateData models.UpdateSeasonRequest
+	if !decodeRequest(w, r, &updateData) {
+		return
+	}
+
+	if updateData.Name != nil {
+		season.Name = *updateData.Name
+	}
+	if updateData.StartDate != nil {
+		season.StartDate = *updateData.StartDate
+	}
+	if updateData.EndDate != nil {
+		season.EndDate = *updateData.EndDate
+	}
+	if updateData.CutoffDate != nil {
+		season.CutoffDate = *updateData.CutoffDate
+	}
+	if !h.validateSeason(w, r, objID, season) {
+		return
+	}
+
+	if err := h.db.UpdateSeason(r.Context(), objID, season); err != nil {
+		http.Error(w, "Error updating season", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Season updated successfully",
+		"season":  season,
+	})
+}
+
+// GetAgeCategoryChanges reports active cricketers whose age category changes next season (admin only)
+func (h *SeasonHandler) GetAgeCategoryChanges(w http.ResponseWriter, r *http.Request) {
+	current, err := seasonAt(r.Context(), h.db, time.Now())
+	if err != nil {
+		http.Error(w, "Error fetching season", http.StatusInternalServerError)
+		return
+	}
+	next, err := seasonAt(r.Context(), h.db, current.EndDate.AddDate(0, 0, 1))
+	if err != nil {
+		http.Error(w, "Error fetching season", http.StatusInternalServerError)
+		return
+	}
+
+	cricketers, err := h.db.GetAllCricketers(r.Context())
+	if err != nil {
+		http.Error(w, "Error fetching cricketers", http.StatusInternalServerError)
+		return
+	}
+
+	report := models.AgeCategoryChangeReport{
+		CurrentSeason: *current,
+		NextSeason:    *next,
+		Changes:       []models.AgeCategoryChange{},
+		MissingDOB:    []primitive.ObjectID{},
+	}
+	for _, c := range cricketers {
+		if c.InactiveCricketer {
+			continue
+		}
+		if c.DateOfBirth == nil {
+			report.MissingDOB = append(report.MissingDOB, c.ID)
+			continue
+		}
+		currentCategory := agecategory.For(*c.DateOfBirth, current.CutoffDate)
+		nextCategory := agecategory.For(*c.DateOfBirth, next.CutoffDate)
+		if currentCategory != nextCategory {
+			report.Changes = append(report.Changes, models.AgeCategoryChange{
+				CricketerID:     c.ID,
+				Name:            c.Name,
+				DateOfBirth:     *c.DateOfBirth,
+				BatchID:         c.BatchID,
+				CurrentCategory: currentCategory,
+				NextCategory:    nextCategory,
+			})
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+// CheckCricketerEligibility reports whether a cricketer may play in the age category given by the category
+// query parameter this season (admin only)
+func (h *SeasonHandler) CheckCricketerEligibility(w http.ResponseWriter, r *http.Request) {
+	cricketerID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid cricketer ID", http.StatusBadRequest)
+		return
+	}
+	category := r.URL.Query().Get("category")
+	if _, ok := agecategory.Lookup(category); !ok && category != agecategory.Open {
+		http.Error(w, "Unknown age category", http.StatusBadRequest)
+		return
+	}
+
+	cricketer, err := h.db.GetCricketerByID(r.Context(), cricketerID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "Cricketer not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Error fetching cricketer", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	season, err := seasonAt(r.Context(), h.db, time.Now())
+	if err != nil {
+		http.Error(w, "Error fetching season", http.StatusInternalServerError)
+		return
+	}
+
+	response := map[string]interface{}{
+		"cricketerId": cricketer.ID.Hex(),
+		"category":    category,
+		"season":      season,
+		"eligible":    false,
+	}
+	if cricketer.DateOfBirth != nil {
+		response["ageCategory"] = agecategory.For(*cricketer.DateOfBirth, season.CutoffDate)
+		response["ageOnCutoff"] = agecategory.AgeOn(*cricketer.DateOfBirth, season.CutoffDate)
+	}
+	if err := checkAgeEligibility(category, cricketer.DateOfBirth, season); err != nil {
+		response["reason"] = err.Error()
+	} else {
+		response["eligible"] = true
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// validateSeason checks a season's dates, writing an error response if they are invalid. The
+// request has already been validated, so only the order of the merged dates is checked here.
+func (h *SeasonHandler) validateSeason(w http.ResponseWriter, r *http.Request, id primitive.ObjectID, season *models.Season) bool {
+	if !season.EndDate.After(season.StartDate) {
+		writeFieldError(w, "endDate", "gtfield", "endDate must be after startDate")
+		return false
+	}
+
+	overlaps, err := h.db.SeasonOverlaps(r.Context(), id, season.StartDate, season.EndDate)
+	if err != nil {
+		http.Error(w, "Error checking seasons", http.StatusInternalServerError)
+		return false
+	}
+	if overlaps {
+		http.Error(w, "Season overlaps an existing season", http.StatusConflict)
+		return false
+	}
+	return true
+}
+
+// seasonAt returns the configured season containing date. When none is configured it derives one from
+// the academic year containing date, with the cutoff on 1 September.
+func seasonAt(ctx context.Context, database db.Database, date time.Time) (*models.Season, error) {
+	season, err := database.GetSeasonForDate(ctx, date)
+	if err == nil {
+		return season, nil
+	}
+	if err != mongo.ErrNoDocuments {
+		return nil, err
+	}
+
+	academicYear := formnumber.ConfigFromEnv()
+	startYear := academicYear.AcademicYearStart(date)
+	start := time.Date(startYear, academicYear.AcademicYearStartMonth, 1, 0, 0, 0, 0, date.Location())
+	return &models.Season{
+		Name:       academicYear.AcademicYear(date),
+		StartDate:  start,
+		EndDate:    start.AddDate(1, 0, 0).Add(-time.Nanosecond),
+		CutoffDate: time.Date(startYear, defaultCutoffMonth, defaultCutoffDay, 0, 0, 0, 0, date.Location()),
+		Default:    true,
+	}, nil
+}
+
+// checkAgeEligibility checks a player may play in an age category during season.
+// An empty category has no age restriction.
+func checkAgeEligibility(category string, dateOfBirth *time.Time, season *models.Season) error {
+	if category == "" {
+		return nil
+	}
+	if dateOfBirth == nil {
+		return errDateOfBirthUnknown
+	}
+	if !agecategory.Eligible(category, *dateOfBirth, season.CutoffDate) {
+		limit, ok := agecategory.Lookup(category)
+		if !ok {
+			return fmt.Errorf("unknown age category %s", category)
+		}
+		return fmt.Errorf("%s requires a date of birth on or after %s for season %s, player is %s",
+			category, limit.BornOnOrAfter(season.CutoffDate).Format("2 Jan 2006"), season.Name,
+			agecategory.For(*dateOfBirth, season.CutoffDate))
+	}
+	return nil
+}
+
+// ageCategoryAt returns the age category for dateOfBirth in season, or "" if it isn't known
+func ageCategoryAt(dateOfBirth *time.Time, season *models.Season) string {
+	if dateOfBirth == nil || dateOfBirth.IsZero() || season == nil {
+		return ""
+	}
+	return agecategory.For(*dateOfBirth, season.CutoffDate)
+}
@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"fmt"
+	"os"
+)
+
+// Secrets holds the keys the handlers sign challenges, codes and download links with
+type Secrets struct {
+	Registration  []byte // keys proof-of-work challenges and verification code hashes
+	AttachmentURL []byte // signs attachment download links
+	PhotoURL      []byte // signs profile photo links, which are served without a login
+}
+
+// SecretsFromEnv loads the handler secrets from the environment. Each one must be set: there is
+// no built-in default, since anyone who can read the source could forge whatever it signs.
+func SecretsFromEnv() (*Secrets, error) {
+	registration, err := requiredSecret("REGISTRATION_SECRET")
+	if err != nil {
+		return nil, err
+	}
+	attachmentURL, err := requiredSecret("ATTACHMENT_URL_SECRET")
+	if err != nil {
+		return nil, err
+	}
+	photoURL, err := requiredSecret("PHOTO_URL_SECRET")
+	if err != nil {
+		return nil, err
+	}
+	return &Secrets{Registration: registration, AttachmentURL: attachmentURL, PhotoURL: photoURL}, nil
+}
+
+// requiredSecret reads a secret from the named environment variable
+func requiredSecret(name string) ([]byte, error) {
+	secret := os.Getenv(name)
+	if secret == "" {
+		return nil, fmt.Errorf("%s is not set", name)
+	}
+	return []byte(secret), nil
+}
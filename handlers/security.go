@@ -0,0 +1,219 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"cricketApp/db"
+	"cricketApp/middleware/ratelimit"
+	"cricketApp/models"
+	"cricketApp/pii"
+)
+
+// knownPermissions lists the permissions that can be granted to admins
+var knownPermissions = map[string]bool{
+	models.PermissionRevealPII:     true,
+	models.PermissionManagePIIKeys: true,
+}
+
+type SecurityHandler struct {
+	db   db.Database
+	keys pii.KeyProvider
+}
+
+func NewSecurityHandler(db db.Database, keys pii.KeyProvider) *SecurityHandler {
+	return &SecurityHandler{db: db, keys: keys}
+}
+
+// RotatePIIKey generates a new key-encryption key and re-wraps the data keys of all encrypted registrations
+// and medical profiles
+// (admins with the pii:manageKeys permission)
+func (h *SecurityHandler) RotatePIIKey(w http.ResponseWriter, r *http.Request) {
+	admin, ok := requireAdminPermission(w, r, h.db, models.PermissionManagePIIKeys)
+	if !ok {
+		return
+	}
+
+	rotator, ok := h.keys.(pii.Rotator)
+	if !ok {
+		http.Error(w, "The configured key provider does not support rotation", http.StatusNotImplemented)
+		return
+	}
+
+	keyID, err := rotator.Rotate()
+	if err != nil {
+		log.Printf("Error rotating PII key: %v", err)
+		http.Error(w, "Error rotating key", http.StatusInternalServerError)
+		return
+	}
+
+	entry := &models.AuditEntry{
+		Action:     models.AuditRotatePIIKey,
+		TargetType: "key",
+		TargetID:   keyID,
+	}
+	if err := recordAudit(r, h.db, admin, entry); err != nil {
+		log.Printf("Error recording key rotation: %v", err)
+	}
+
+	updated, err := h.db.MigrateRegistrationPII(r.Context())
+	if err != nil {
+		log.Printf("Error re-wrapping registration keys after rotation to %s: %v", keyID, err)
+		http.Error(w, "Key rotated but re-wrapping existing data failed; it will be retried at startup", http.StatusInternalServerError)
+		return
+	}
+	profilesUpdated, err := h.db.RewrapMedicalProfiles(r.Context())
+	if err != nil {
+		log.Printf("Error re-wrapping medical profile keys after rotation to %s: %v", keyID, err)
+		http.Error(w, "Key rotated but re-wrapping existing data failed; it will be retried at startup", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":                "Key rotated successfully",
+		"keyId":                  keyID,
+		"registrationsUpdated":   updated,
+		"medicalProfilesUpdated": profilesUpdated,
+	})
+}
+
+// UpdateAdminPermissions replaces another admin's permissions. Admins can only grant or
+// remove permissions they hold themselves (admin only).
+func (h *SecurityHandler) UpdateAdminPermissions(w http.ResponseWriter, r *http.Request) {
+	targetID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid admin ID", http.StatusBadRequest)
+		return
+	}
+
+	actor, ok := requireAdminPermission(w, r, h.db, "")
+	if !ok {
+		return
+	}
+
+	var req models.UpdateAdminPermissionsRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	target, err := h.db.GetAdminByID(r.Context(), targetID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "Admin not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Error fetching admin", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	permissions := []string{}
+	requested := make(map[string]bool)
+	for _, permission := range req.Permissions {
+		if !knownPermissions[permission] {
+			http.Error(w, "Unknown permission: "+permission, http.StatusBadRequest)
+			return
+		}
+		if !requested[permission] {
+			requested[permission] = true
+			permissions = append(permissions, permission)
+		}
+	}
+	for permission := range knownPermissions {
+		if requested[permission] != target.HasPermission(permission) && !actor.HasPermission(permission) {
+			http.Error(w, "You can only grant or remove permissions you hold: "+permission, http.StatusForbidden)
+			return
+		}
+	}
+
+	if err := h.db.UpdateAdminPermissions(r.Context(), targetID, permissions); err != nil {
+		http.Error(w, "Error updating permissions", http.StatusInternalServerError)
+		return
+	}
+
+	entry := &models.AuditEntry{
+		Action:     models.AuditGrantPermissions,
+		TargetType: "admin",
+		TargetID:   targetID.Hex(),
+		Details:    map[string]string{"permissions": strings.Join(permissions, ",")},
+	}
+	if err := recordAudit(r, h.db, actor, entry); err != nil {
+		log.Printf("Error recording permission change: %v", err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":     "Permissions updated successfully",
+		"permissions": permissions,
+	})
+}
+
+// GetAuditLog lists audit entries, optionally filtered by action, actorId and targetId (admin only)
+func (h *SecurityHandler) GetAuditLog(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := models.AuditFilter{
+		Action:   query.Get("action"),
+		ActorID:  query.Get("actorId"),
+		TargetID: query.Get("targetId"),
+		Limit:    100,
+	}
+	if limit, err := strconv.Atoi(query.Get("limit")); err == nil && limit > 0 && limit <= 1000 {
+		filter.Limit = limit
+	}
+
+	entries, err := h.db.GetAuditEntries(r.Context(), filter)
+	if err != nil {
+		http.Error(w, "Error fetching audit log", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(entries)
+}
+
+// requireAdminPermission loads the calling admin and checks they hold permission, writing an error
+// response if not. Permissions are read from the database so revoking one takes effect immediately.
+// An empty permission only requires the caller to be an admin.
+func requireAdminPermission(w http.ResponseWriter, r *http.Request, database db.Database, permission string) (*models.Admin, bool) {
+	adminID, err := subjectIDFromClaims(r)
+	if err != nil || roleFromClaims(r) != "admin" {
+		http.Error(w, "Invalid token claims", http.StatusUnauthorized)
+		return nil, false
+	}
+
+	admin, err := database.GetAdminByID(r.Context(), adminID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "Admin not found", http.StatusUnauthorized)
+		} else {
+			http.Error(w, "Error fetching admin", http.StatusInternalServerError)
+		}
+		return nil, false
+	}
+
+	if permission != "" && !admin.HasPermission(permission) {
+		http.Error(w, "Forbidden: requires the "+permission+" permission", http.StatusForbidden)
+		return nil, false
+	}
+	return admin, true
+}
+
+// recordAudit fills in the actor and client IP and stores entry
+func recordAudit(r *http.Request, database db.Database, admin *models.Admin, entry *models.AuditEntry) error {
+	return recordActorAudit(r, database, admin.ID, "admin", entry)
+}
+
+// recordActorAudit is recordAudit for actions taken by users other than admins
+func recordActorAudit(r *http.Request, database db.Database, actorID string, role string, entry *models.AuditEntry) error {
+	entry.ActorID = actorID
+	entry.ActorRole = role
+	entry.IP = ratelimit.ClientIP(r)
+	return database.CreateAuditEntry(r.Context(), entry)
+}
@@ -7,7 +7,7 @@ import (
 	"cricketApp/db"
 	"cricketApp/models"
 
-	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/v5"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 )
@@ -23,8 +23,7 @@ func NewSessionHandler(db db.Database) *SessionHandler {
 // CreateSession creates a new coaching session
 func (h *SessionHandler) CreateSession(w http.ResponseWriter, r *http.Request) {
 	var req models.CreateSessionRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+	if !decodeRequest(w, r, &req) {
 		return
 	}
 
@@ -35,9 +34,19 @@ func (h *SessionHandler) CreateSession(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	var batchID *primitive.ObjectID
+	if req.BatchID != "" {
+		id, ok := h.sessionBatch(w, r, req.BatchID)
+		if !ok {
+			return
+		}
+		batchID = &id
+	}
+
 	// Create new session
 	session := &models.Session{
 		CoachID:     coachID,
+		BatchID:     batchID,
 		Title:       req.Title,
 		Description: req.Description,
 		Date:        req.Date,
@@ -153,8 +162,7 @@ func (h *SessionHandler) UpdateSession(w http.ResponseWriter, r *http.Request) {
 
 	// Decode update request
 	var updateData models.UpdateSessionRequest
-	if err := json.NewDecoder(r.Body).Decode(&updateData); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+	if !decodeRequest(w, r, &updateData) {
 		return
 	}
 
@@ -165,6 +173,16 @@ func (h *SessionHandler) UpdateSession(w http.ResponseWriter, r *http.Request) {
 	if updateData.Description != nil {
 		session.Description = *updateData.Description
 	}
+	if updateData.BatchID != nil {
+		session.BatchID = nil
+		if *updateData.BatchID != "" {
+			id, ok := h.sessionBatch(w, r, *updateData.BatchID)
+			if !ok {
+				return
+			}
+			session.BatchID = &id
+		}
+	}
 	if updateData.Date != nil {
 		session.Date = *updateData.Date
 	}
@@ -181,6 +199,14 @@ func (h *SessionHandler) UpdateSession(w http.ResponseWriter, r *http.Request) {
 		session.MaxStudents = *updateData.MaxStudents
 	}
 
+	// A new start time must still be before the existing end time, and the other way round.
+	// Only this rule is checked on the merged session, so fields the update leaves alone are
+	// accepted as they are stored.
+	if !session.EndTime.After(session.StartTime) {
+		writeFieldError(w, "endTime", "gtfield", "endTime must be after startTime")
+		return
+	}
+
 	// Update session in database
 	if err := h.db.UpdateSession(r.Context(), objID, session); err != nil {
 		http.Error(w, "Error updating session", http.StatusInternalServerError)
@@ -221,3 +247,17 @@ func (h *SessionHandler) DeleteSession(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(map[string]string{"message": "Session deleted successfully"})
 }
+
+// sessionBatch checks that the batch a session is scheduled for exists
+func (h *SessionHandler) sessionBatch(w http.ResponseWriter, r *http.Request, hexID string) (primitive.ObjectID, bool) {
+	batchID, _ := primitive.ObjectIDFromHex(hexID)
+	if _, err := h.db.GetBatchByID(r.Context(), batchID); err != nil {
+		if err == mongo.ErrNoDocuments {
+			writeFieldError(w, "batchId", "exists", "batch not found")
+		} else {
+			http.Error(w, "Error fetching batch", http.StatusInternalServerError)
+		}
+		return primitive.NilObjectID, false
+	}
+	return batchID, true
+}
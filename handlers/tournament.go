@@ -0,0 +1,881 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"cricketApp/db"
+	"cricketApp/models"
+	"cricketApp/notification"
+)
+
+const defaultSquadSize = 15
+
+// TournamentHandler manages external tournaments: fixtures, availability polls, squads and playing XIs
+type TournamentHandler struct {
+	db       db.Database
+	notifier notification.Notifier
+}
+
+func NewTournamentHandler(db db.Database, notifier notification.Notifier) *TournamentHandler {
+	return &TournamentHandler{db: db, notifier: notifier}
+}
+
+// CreateTournament adds a tournament (admin only)
+func (h *TournamentHandler) CreateTournament(w http.ResponseWriter, r *http.Request) {
+	adminID, err := subjectIDFromClaims(r)
+	if err != nil {
+		http.Error(w, "Invalid token claims", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.TournamentRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	tournament := &models.Tournament{CreatedBy: adminID.Hex()}
+	if !applyTournamentRequest(w, &req, tournament) {
+		return
+	}
+
+	if err := h.db.CreateTournament(r.Context(), tournament); err != nil {
+		http.Error(w, "Error creating tournament", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":    "Tournament created successfully",
+		"tournament": tournament,
+	})
+}
+
+// GetTournaments lists tournaments, latest first, optionally only ?upcoming ones that haven't ended
+func (h *TournamentHandler) GetTournaments(w http.ResponseWriter, r *http.Request) {
+	var endingFrom *time.Time
+	if upcoming, _ := strconv.ParseBool(r.URL.Query().Get("upcoming")); upcoming {
+		today := startOfDay(time.Now())
+		endingFrom = &today
+	}
+
+	tournaments, err := h.db.GetTournaments(r.Context(), endingFrom)
+	if err != nil {
+		http.Error(w, "Error fetching tournaments", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(tournaments)
+}
+
+// GetTournament returns a tournament with its squad and fixtures
+func (h *TournamentHandler) GetTournament(w http.ResponseWriter, r *http.Request) {
+	tournament, ok := h.tournamentFromURL(w, r)
+	if !ok {
+		return
+	}
+
+	fixtures, err := h.db.GetFixtures(r.Context(), tournament.ID)
+	if err != nil {
+		http.Error(w, "Error fetching fixtures", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"tournament": tournament,
+		"fixtures":   fixtures,
+	})
+}
+
+// UpdateTournament changes a tournament's details (admin only). The squad is kept.
+func (h *TournamentHandler) UpdateTournament(w http.ResponseWriter, r *http.Request) {
+	tournament, ok := h.tournamentFromURL(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.TournamentRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	if !applyTournamentRequest(w, &req, tournament) {
+		return
+	}
+	if len(tournament.Squad) > tournament.SquadSize {
+		writeFieldError(w, "squadSize", "min", fmt.Sprintf("the squad already has %d players", len(tournament.Squad)))
+		return
+	}
+
+	if err := h.db.UpdateTournament(r.Context(), tournament.ID, tournament); err != nil {
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "Tournament not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Error updating tournament", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":    "Tournament updated successfully",
+		"tournament": tournament,
+	})
+}
+
+// CreateFixture adds a match to a tournament (admin only)
+func (h *TournamentHandler) CreateFixture(w http.ResponseWriter, r *http.Request) {
+	tournament, ok := h.tournamentFromURL(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.FixtureRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	fixture := &models.Fixture{TournamentID: tournament.ID}
+	if !applyFixtureRequest(w, &req, tournament, fixture) {
+		return
+	}
+
+	if err := h.db.CreateFixture(r.Context(), fixture); err != nil {
+		http.Error(w, "Error creating fixture", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Fixture created successfully",
+		"fixture": fixture,
+	})
+}
+
+// UpdateFixture changes a fixture's opponent, time, venue or stage (admin only)
+func (h *TournamentHandler) UpdateFixture(w http.ResponseWriter, r *http.Request) {
+	fixture, tournament, ok := h.fixtureFromURL(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.FixtureRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	if !applyFixtureRequest(w, &req, tournament, fixture) {
+		return
+	}
+
+	if err := h.db.UpdateFixture(r.Context(), fixture.ID, fixture); err != nil {
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "Fixture not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Error updating fixture", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Fixture updated successfully",
+		"fixture": fixture,
+	})
+}
+
+// PollAvailability asks a candidate pool, given by cricketer and by batch, whether they can play in
+// a tournament, and notifies the cricketers and their guardians. Cricketers already asked keep
+// their answers and aren't notified again.
+func (h *TournamentHandler) PollAvailability(w http.ResponseWriter, r *http.Request) {
+	userID, err := subjectIDFromClaims(r)
+	if err != nil {
+		http.Error(w, "Invalid token claims", http.StatusUnauthorized)
+		return
+	}
+	tournament, ok := h.tournamentFromURL(w, r)
+	if !ok {
+		return
+	}
+	if tournament.EndDate.Before(time.Now()) {
+		http.Error(w, "The tournament has ended", http.StatusConflict)
+		return
+	}
+
+	var req models.AvailabilityPollRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	if len(req.CricketerIDs) == 0 && len(req.BatchIDs) == 0 {
+		writeFieldError(w, "cricketerIds", "required", "give cricketerIds, batchIds or both")
+		return
+	}
+
+	var candidates []models.Cricketer
+	for i, hexID := range req.CricketerIDs {
+		field := fmt.Sprintf("cricketerIds[%d]", i)
+		cricketerID, err := primitive.ObjectIDFromHex(hexID)
+		if err != nil {
+			writeFieldError(w, field, "objectid", "must be a valid ID")
+			return
+		}
+		cricketer, err := h.db.GetCricketerByID(r.Context(), cricketerID)
+		if err != nil {
+			if err == mongo.ErrNoDocuments {
+				writeFieldError(w, field, "exists", "cricketer not found")
+			} else {
+				http.Error(w, "Error fetching cricketer", http.StatusInternalServerError)
+			}
+			return
+		}
+		if cricketer.InactiveCricketer {
+			writeFieldError(w, field, "active", "cricketer is inactive")
+			return
+		}
+		candidates = append(candidates, *cricketer)
+	}
+
+	if len(req.BatchIDs) > 0 {
+		batchIDs, err := parseObjectIDs(req.BatchIDs)
+		if err != nil {
+			writeFieldError(w, "batchIds", "objectid", "must be valid IDs")
+			return
+		}
+		for i, batchID := range batchIDs {
+			if _, err := h.db.GetBatchByID(r.Context(), batchID); err != nil {
+				if err == mongo.ErrNoDocuments {
+					writeFieldError(w, fmt.Sprintf("batchIds[%d]", i), "exists", "batch not found")
+				} else {
+					http.Error(w, "Error fetching batch", http.StatusInternalServerError)
+				}
+				return
+			}
+		}
+		members, err := h.db.GetCricketersByBatches(r.Context(), batchIDs)
+		if err != nil {
+			http.Error(w, "Error fetching cricketers", http.StatusInternalServerError)
+			return
+		}
+		for _, member := range members {
+			if !member.InactiveCricketer {
+				candidates = append(candidates, member)
+			}
+		}
+	}
+
+	seen := make(map[primitive.ObjectID]bool, len(candidates))
+	requests := make([]models.Availability, 0, len(candidates))
+	cricketers := make(map[primitive.ObjectID]models.Cricketer, len(candidates))
+	for _, cricketer := range candidates {
+		if seen[cricketer.ID] {
+			continue
+		}
+		seen[cricketer.ID] = true
+		cricketers[cricketer.ID] = cricketer
+		requests = append(requests, models.Availability{
+			TournamentID:   tournament.ID,
+			TournamentName: tournament.Name,
+			StartDate:      tournament.StartDate,
+			EndDate:        tournament.EndDate,
+			CricketerID:    cricketer.ID,
+			CricketerName:  cricketer.Name,
+			RequestedBy:    userID.Hex(),
+		})
+	}
+
+	created, err := h.db.CreateAvailabilityRequests(r.Context(), requests)
+	if err != nil {
+		http.Error(w, "Error creating availability requests", http.StatusInternalServerError)
+		return
+	}
+
+	for _, request := range created {
+		cricketer := cricketers[request.CricketerID]
+		message := fmt.Sprintf("Is %s available for %s, %s? Please reply in the app.",
+			cricketer.Name, tournament.Name, tournamentDates(tournament))
+		notifyCricketerAndGuardians(r.Context(), h.db, h.notifier, &cricketer, "Availability: "+tournament.Name, message)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":   "Availability requested successfully",
+		"requested": created,
+		"pool":      len(requests),
+	})
+}
+
+// GetTournamentCandidates lists everyone polled for a tournament with their answer and whether they
+// can be selected: in the tournament's age category, with fees paid and not injured
+func (h *TournamentHandler) GetTournamentCandidates(w http.ResponseWriter, r *http.Request) {
+	tournament, ok := h.tournamentFromURL(w, r)
+	if !ok {
+		return
+	}
+
+	availability, err := h.db.GetTournamentAvailability(r.Context(), tournament.ID)
+	if err != nil {
+		http.Error(w, "Error fetching availability", http.StatusInternalServerError)
+		return
+	}
+	ids := make([]primitive.ObjectID, len(availability))
+	for i, entry := range availability {
+		ids[i] = entry.CricketerID
+	}
+	checker, err := newSelectionChecker(r.Context(), h.db, tournament, ids)
+	if err != nil {
+		http.Error(w, "Error checking eligibility", http.StatusInternalServerError)
+		return
+	}
+
+	candidates := make([]models.TournamentCandidate, 0, len(availability))
+	for _, entry := range availability {
+		candidate := models.TournamentCandidate{Availability: entry, Reasons: []string{}}
+		cricketer, err := h.db.GetCricketerByID(r.Context(), entry.CricketerID)
+		if err != nil && err != mongo.ErrNoDocuments {
+			http.Error(w, "Error fetching cricketer", http.StatusInternalServerError)
+			return
+		}
+		if cricketer == nil {
+			candidate.Reasons = append(candidate.Reasons, "cricketer no longer exists")
+		} else {
+			candidate.AgeCategory = ageCategoryAt(cricketer.DateOfBirth, checker.season)
+			candidate.Reasons = append(candidate.Reasons, checker.issues(cricketer)...)
+		}
+		candidate.Eligible = len(candidate.Reasons) == 0
+		candidate.InSquad = tournament.Member(entry.CricketerID) != nil
+		candidates = append(candidates, candidate)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(candidates)
+}
+
+// SelectSquad replaces a tournament's squad. Every player must be eligible and must not have said
+// they are unavailable; there can be one captain and one vice-captain. Newly selected players and
+// their guardians are notified.
+func (h *TournamentHandler) SelectSquad(w http.ResponseWriter, r *http.Request) {
+	userID, err := subjectIDFromClaims(r)
+	if err != nil {
+		http.Error(w, "Invalid token claims", http.StatusUnauthorized)
+		return
+	}
+	tournament, ok := h.tournamentFromURL(w, r)
+	if !ok {
+		return
+	}
+	if tournament.EndDate.Before(time.Now()) {
+		http.Error(w, "The tournament has ended", http.StatusConflict)
+		return
+	}
+
+	var req models.SelectSquadRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	if len(req.Members) > tournament.SquadSize {
+		writeFieldError(w, "members", "max", fmt.Sprintf("the squad can have at most %d players", tournament.SquadSize))
+		return
+	}
+
+	ids := make([]primitive.ObjectID, len(req.Members))
+	for i, member := range req.Members {
+		ids[i], _ = primitive.ObjectIDFromHex(member.CricketerID)
+	}
+	checker, err := newSelectionChecker(r.Context(), h.db, tournament, ids)
+	if err != nil {
+		http.Error(w, "Error checking eligibility", http.StatusInternalServerError)
+		return
+	}
+	availability, err := h.db.GetTournamentAvailability(r.Context(), tournament.ID)
+	if err != nil {
+		http.Error(w, "Error fetching availability", http.StatusInternalServerError)
+		return
+	}
+	unavailable := make(map[primitive.ObjectID]bool)
+	for _, entry := range availability {
+		if entry.Response == models.AvailabilityUnavailable {
+			unavailable[entry.CricketerID] = true
+		}
+	}
+
+	squad := make([]models.SquadMember, 0, len(req.Members))
+	cricketers := make([]models.Cricketer, 0, len(req.Members))
+	seen := make(map[primitive.ObjectID]bool, len(req.Members))
+	roleHolders := make(map[string]int)
+	for i, member := range req.Members {
+		field := fmt.Sprintf("members[%d]", i)
+		cricketerID := ids[i]
+		if seen[cricketerID] {
+			writeFieldError(w, field+".cricketerId", "unique", "cricketer is listed more than once")
+			return
+		}
+		seen[cricketerID] = true
+
+		roles, ok := squadRoles(w, field, member.Roles)
+		if !ok {
+			return
+		}
+		for _, role := range roles {
+			roleHolders[role]++
+			if role != models.SquadWicketKeeper && roleHolders[role] > 1 {
+				writeFieldError(w, field+".roles", "unique", "the squad already has a "+strings.ReplaceAll(role, "_", "-"))
+				return
+			}
+		}
+
+		cricketer, err := h.db.GetCricketerByID(r.Context(), cricketerID)
+		if err != nil {
+			if err == mongo.ErrNoDocuments {
+				writeFieldError(w, field+".cricketerId", "exists", "cricketer not found")
+			} else {
+				http.Error(w, "Error fetching cricketer", http.StatusInternalServerError)
+			}
+			return
+		}
+		if issues := checker.issues(cricketer); len(issues) > 0 {
+			writeFieldError(w, field+".cricketerId", "eligible", cricketer.Name+" is not eligible: "+strings.Join(issues, "; "))
+			return
+		}
+		if unavailable[cricketerID] {
+			writeFieldError(w, field+".cricketerId", "available", cricketer.Name+" is not available for this tournament")
+			return
+		}
+
+		squad = append(squad, models.SquadMember{CricketerID: cricketer.ID, Name: cricketer.Name, Roles: roles})
+		cricketers = append(cricketers, *cricketer)
+	}
+	if roleHolders[models.SquadCaptain] > 0 && roleHolders[models.SquadViceCaptain] > 0 {
+		for _, member := range squad {
+			if containsString(member.Roles, models.SquadCaptain) && containsString(member.Roles, models.SquadViceCaptain) {
+				writeFieldError(w, "members", "roles", member.Name+" can't be both captain and vice-captain")
+				return
+			}
+		}
+	}
+
+	if err := h.db.SaveTournamentSquad(r.Context(), tournament.ID, squad, userID.Hex()); err != nil {
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "Tournament not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Error saving squad", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	for i, member := range squad {
+		if tournament.Member(member.CricketerID) != nil {
+			continue
+		}
+		message := fmt.Sprintf("%s has been selected for the %s squad, %s.", member.Name, tournament.Name, tournamentDates(tournament))
+		if len(member.Roles) > 0 {
+			message += " Role: " + strings.ReplaceAll(strings.Join(member.Roles, ", "), "_", "-") + "."
+		}
+		notifyCricketerAndGuardians(r.Context(), h.db, h.notifier, &cricketers[i], "Selected: "+tournament.Name, message)
+	}
+
+	now := time.Now()
+	tournament.Squad = squad
+	tournament.SquadSelectedBy = userID.Hex()
+	tournament.SquadSelectedAt = &now
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":    "Squad selected successfully",
+		"tournament": tournament,
+	})
+}
+
+// SelectPlayingXI picks a fixture's playing XI from the tournament squad, in batting order, with its
+// captain and wicket-keeper. Players must still be eligible, so an injury since squad selection
+// rules a player out. The XI and their guardians are notified.
+func (h *TournamentHandler) SelectPlayingXI(w http.ResponseWriter, r *http.Request) {
+	userID, err := subjectIDFromClaims(r)
+	if err != nil {
+		http.Error(w, "Invalid token claims", http.StatusUnauthorized)
+		return
+	}
+	fixture, tournament, ok := h.fixtureFromURL(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.PlayingXIRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	ids := make([]primitive.ObjectID, len(req.BattingOrder))
+	seen := make(map[primitive.ObjectID]bool, len(req.BattingOrder))
+	for i, hexID := range req.BattingOrder {
+		field := fmt.Sprintf("battingOrder[%d]", i)
+		cricketerID, err := primitive.ObjectIDFromHex(hexID)
+		if err != nil {
+			writeFieldError(w, field, "objectid", "must be a valid ID")
+			return
+		}
+		if seen[cricketerID] {
+			writeFieldError(w, field, "unique", "player is listed more than once")
+			return
+		}
+		if tournament.Member(cricketerID) == nil {
+			writeFieldError(w, field, "squad", "player is not in the tournament squad")
+			return
+		}
+		seen[cricketerID] = true
+		ids[i] = cricketerID
+	}
+	captainID, _ := primitive.ObjectIDFromHex(req.CaptainID)
+	if !seen[captainID] {
+		writeFieldError(w, "captainId", "xi", "captain must be in the playing XI")
+		return
+	}
+	wicketKeeperID, _ := primitive.ObjectIDFromHex(req.WicketKeeperID)
+	if !seen[wicketKeeperID] {
+		writeFieldError(w, "wicketKeeperId", "xi", "wicket-keeper must be in the playing XI")
+		return
+	}
+
+	checker, err := newSelectionChecker(r.Context(), h.db, tournament, ids)
+	if err != nil {
+		http.Error(w, "Error checking eligibility", http.StatusInternalServerError)
+		return
+	}
+	xi := &models.PlayingXI{
+		BattingOrder:   make([]models.MatchPlayer, 0, len(ids)),
+		CaptainID:      captainID,
+		WicketKeeperID: wicketKeeperID,
+		SelectedBy:     userID.Hex(),
+		SelectedAt:     time.Now(),
+	}
+	cricketers := make([]models.Cricketer, 0, len(ids))
+	for i, cricketerID := range ids {
+		cricketer, err := h.db.GetCricketerByID(r.Context(), cricketerID)
+		if err != nil {
+			if err == mongo.ErrNoDocuments {
+				writeFieldError(w, fmt.Sprintf("battingOrder[%d]", i), "exists", "cricketer not found")
+			} else {
+				http.Error(w, "Error fetching cricketer", http.StatusInternalServerError)
+			}
+			return
+		}
+		if issues := checker.issues(cricketer); len(issues) > 0 {
+			writeFieldError(w, fmt.Sprintf("battingOrder[%d]", i), "eligible", cricketer.Name+" is not eligible: "+strings.Join(issues, "; "))
+			return
+		}
+		id := cricketer.ID
+		xi.BattingOrder = append(xi.BattingOrder, models.MatchPlayer{CricketerID: &id, Name: cricketer.Name})
+		cricketers = append(cricketers, *cricketer)
+	}
+
+	if err := h.db.SaveFixturePlayingXI(r.Context(), fixture.ID, xi); err != nil {
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "Fixture not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Error saving playing XI", http.StatusInternalServerError)
+		}
+		return
+	}
+	fixture.PlayingXI = xi
+
+	for i := range cricketers {
+		message := fmt.Sprintf("%s is in the playing XI against %s on %s, batting at number %d.",
+			cricketers[i].Name, fixture.Opponent, fixture.StartsAt.Format("2 Jan 2006 15:04"), i+1)
+		switch cricketers[i].ID {
+		case captainID:
+			message += " Captain."
+		case wicketKeeperID:
+			message += " Wicket-keeper."
+		}
+		notifyCricketerAndGuardians(r.Context(), h.db, h.notifier, &cricketers[i], "Playing XI: "+tournament.Name, message)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Playing XI selected successfully",
+		"fixture": fixture,
+	})
+}
+
+// GetAvailabilityRequests lists the logged-in cricketer's tournament availability requests, latest first
+func (h *CricketerHandler) GetAvailabilityRequests(w http.ResponseWriter, r *http.Request) {
+	cricketerID, err := subjectIDFromClaims(r)
+	if err != nil {
+		http.Error(w, "Invalid token claims", http.StatusUnauthorized)
+		return
+	}
+	writeCricketerAvailability(w, r, h.db, cricketerID)
+}
+
+// RespondToAvailability answers a tournament's availability request for the logged-in cricketer
+func (h *CricketerHandler) RespondToAvailability(w http.ResponseWriter, r *http.Request) {
+	cricketerID, err := subjectIDFromClaims(r)
+	if err != nil {
+		http.Error(w, "Invalid token claims", http.StatusUnauthorized)
+		return
+	}
+	respondToAvailability(w, r, h.db, cricketerID, cricketerID.Hex(), "cricketer")
+}
+
+// GetChildAvailability lists a child's tournament availability requests, latest first
+func (h *GuardianHandler) GetChildAvailability(w http.ResponseWriter, r *http.Request) {
+	guardian, ok := h.guardianFromClaims(w, r)
+	if !ok {
+		return
+	}
+	cricketer, ok := h.childFromURL(w, r, guardian)
+	if !ok {
+		return
+	}
+	writeCricketerAvailability(w, r, h.db, cricketer.ID)
+}
+
+// RespondToChildAvailability answers a tournament's availability request for a child
+func (h *GuardianHandler) RespondToChildAvailability(w http.ResponseWriter, r *http.Request) {
+	guardian, ok := h.guardianFromClaims(w, r)
+	if !ok {
+		return
+	}
+	cricketer, ok := h.childFromURL(w, r, guardian)
+	if !ok {
+		return
+	}
+	respondToAvailability(w, r, h.db, cricketer.ID, guardian.ID.Hex(), "guardian")
+}
+
+func writeCricketerAvailability(w http.ResponseWriter, r *http.Request, database db.Database, cricketerID primitive.ObjectID) {
+	availability, err := database.GetCricketerAvailability(r.Context(), cricketerID)
+	if err != nil {
+		http.Error(w, "Error fetching availability", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(availability)
+}
+
+// respondToAvailability records an answer to the availability request for the tournament in
+// {tournamentId}. Answers can be changed until the tournament ends.
+func respondToAvailability(w http.ResponseWriter, r *http.Request, database db.Database, cricketerID primitive.ObjectID, respondedBy string, role string) {
+	tournamentID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "tournamentId"))
+	if err != nil {
+		http.Error(w, "Invalid tournament ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.AvailabilityResponseRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	tournament, err := database.GetTournamentByID(r.Context(), tournamentID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "Availability request not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Error fetching tournament", http.StatusInternalServerError)
+		}
+		return
+	}
+	if tournament.EndDate.Before(time.Now()) {
+		http.Error(w, "The tournament has ended", http.StatusConflict)
+		return
+	}
+
+	availability, err := database.RespondToAvailability(r.Context(), tournamentID, cricketerID, req.Response, strings.TrimSpace(req.Note), respondedBy, role)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "Availability request not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Error saving availability", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":      "Availability saved successfully",
+		"availability": availability,
+	})
+}
+
+// selectionChecker checks cricketers against a tournament's selection rules
+type selectionChecker struct {
+	tournament *models.Tournament
+	season     *models.Season
+	injuries   map[primitive.ObjectID]models.Injury
+	now        time.Time
+}
+
+func newSelectionChecker(ctx context.Context, database db.Database, tournament *models.Tournament, cricketerIDs []primitive.ObjectID) (*selectionChecker, error) {
+	season, err := seasonAt(ctx, database, tournament.StartDate)
+	if err != nil {
+		return nil, err
+	}
+	injuries, err := activeInjuries(ctx, database, cricketerIDs)
+	if err != nil {
+		return nil, err
+	}
+	return &selectionChecker{tournament: tournament, season: season, injuries: injuries, now: time.Now()}, nil
+}
+
+// issues lists why a cricketer can't be selected; none means they can. Cricketers must be active,
+// in the tournament's age category, up to date with fees and fully fit.
+func (c *selectionChecker) issues(cricketer *models.Cricketer) []string {
+	issues := []string{}
+	if cricketer.InactiveCricketer {
+		issues = append(issues, "inactive")
+	}
+	if err := checkAgeEligibility(c.tournament.AgeCategory, cricketer.DateOfBirth, c.season); err != nil {
+		issues = append(issues, err.Error())
+	}
+	if cricketer.DueDate != nil && cricketer.DueDate.Before(c.now) {
+		issues = append(issues, "fees overdue since "+cricketer.DueDate.Format("2 Jan 2006"))
+	}
+	if injury, ok := c.injuries[cricketer.ID]; ok {
+		issues = append(issues, fmt.Sprintf("%s %s injury, %s", injury.BodyPart, injury.Type, strings.ReplaceAll(injury.Status, "_", " ")))
+	}
+	return issues
+}
+
+// squadRoles checks and de-duplicates a squad member's roles
+func squadRoles(w http.ResponseWriter, field string, values []string) ([]string, bool) {
+	roles := []string{}
+	for i, role := range values {
+		switch role {
+		case models.SquadCaptain, models.SquadViceCaptain, models.SquadWicketKeeper:
+		default:
+			writeFieldError(w, fmt.Sprintf("%s.roles[%d]", field, i), "oneof", "must be one of captain, vice_captain, wicket_keeper")
+			return nil, false
+		}
+		if !containsString(roles, role) {
+			roles = append(roles, role)
+		}
+	}
+	return roles, true
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func applyTournamentRequest(w http.ResponseWriter, req *models.TournamentRequest, tournament *models.Tournament) bool {
+	if !validBatchAgeCategory(req.AgeCategory) {
+		writeFieldError(w, "ageCategory", "oneof", "ageCategory must be one of: "+strings.Join(batchAgeCategoryNames(), ", "))
+		return false
+	}
+	tournament.Name = strings.TrimSpace(req.Name)
+	tournament.Organiser = strings.TrimSpace(req.Organiser)
+	tournament.AgeCategory = req.AgeCategory
+	tournament.Venue = strings.TrimSpace(req.Venue)
+	tournament.StartDate = req.StartDate
+	tournament.EndDate = req.EndDate
+	tournament.SquadSize = req.SquadSize
+	if tournament.SquadSize == 0 {
+		tournament.SquadSize = defaultSquadSize
+	}
+	return true
+}
+
+// applyFixtureRequest copies a fixture request onto a fixture, which must fall within the tournament
+func applyFixtureRequest(w http.ResponseWriter, req *models.FixtureRequest, tournament *models.Tournament, fixture *models.Fixture) bool {
+	if req.StartsAt.Before(startOfDay(tournament.StartDate)) || !req.StartsAt.Before(startOfDay(tournament.EndDate).AddDate(0, 0, 1)) {
+		writeFieldError(w, "startsAt", "range", "must be within the tournament's dates")
+		return false
+	}
+	fixture.Opponent = strings.TrimSpace(req.Opponent)
+	fixture.StartsAt = req.StartsAt
+	fixture.Venue = strings.TrimSpace(req.Venue)
+	fixture.Stage = strings.TrimSpace(req.Stage)
+	if fixture.Venue == "" {
+		fixture.Venue = tournament.Venue
+	}
+	return true
+}
+
+// tournamentDates formats a tournament's dates for notifications
+func tournamentDates(tournament *models.Tournament) string {
+	if tournament.StartDate.Format("2006-01-02") == tournament.EndDate.Format("2006-01-02") {
+		return tournament.StartDate.Format("2 Jan 2006")
+	}
+	return tournament.StartDate.Format("2 Jan") + " - " + tournament.EndDate.Format("2 Jan 2006")
+}
+
+// notifyCricketerAndGuardians sends a message to a cricketer and their active guardians.
+// Failures are logged.
+func notifyCricketerAndGuardians(ctx context.Context, database db.Database, notifier notification.Notifier, cricketer *models.Cricketer, subject string, message string) {
+	recipients := []notification.Recipient{{Name: cricketer.Name, Mobile: cricketer.Mobile, Email: cricketer.Email}}
+	guardians, err := database.GetGuardiansForCricketer(ctx, cricketer.ID)
+	if err != nil {
+		log.Printf("Error fetching guardians of cricketer %s: %v", cricketer.ID.Hex(), err)
+	}
+	for _, guardian := range guardians {
+		if guardian.IsActive {
+			recipients = append(recipients, notification.Recipient{Name: guardian.Name, Mobile: guardian.Mobile, Email: guardian.Email})
+		}
+	}
+
+	for _, recipient := range recipients {
+		if err := notifier.Notify(ctx, recipient, subject, message); err != nil {
+			log.Printf("Error notifying %s about cricketer %s: %v", recipient.Mobile, cricketer.ID.Hex(), err)
+		}
+	}
+}
+
+func (h *TournamentHandler) tournamentFromURL(w http.ResponseWriter, r *http.Request) (*models.Tournament, bool) {
+	tournamentID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid tournament ID", http.StatusBadRequest)
+		return nil, false
+	}
+
+	tournament, err := h.db.GetTournamentByID(r.Context(), tournamentID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "Tournament not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Error fetching tournament", http.StatusInternalServerError)
+		}
+		return nil, false
+	}
+	return tournament, true
+}
+
+// fixtureFromURL loads the fixture named by {id} and its tournament
+func (h *TournamentHandler) fixtureFromURL(w http.ResponseWriter, r *http.Request) (*models.Fixture, *models.Tournament, bool) {
+	fixtureID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid fixture ID", http.StatusBadRequest)
+		return nil, nil, false
+	}
+
+	fixture, err := h.db.GetFixtureByID(r.Context(), fixtureID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "Fixture not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Error fetching fixture", http.StatusInternalServerError)
+		}
+		return nil, nil, false
+	}
+	tournament, err := h.db.GetTournamentByID(r.Context(), fixture.TournamentID)
+	if err != nil {
+		http.Error(w, "Error fetching tournament", http.StatusInternalServerError)
+		return nil, nil, false
+	}
+	return fixture, tournament, true
+}
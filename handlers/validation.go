@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"cricketApp/validation"
+)
+
+// validationErrorResponse is the body returned when a request fails validation
+type validationErrorResponse struct {
+	Message string            `json:"message"`
+	Code    string            `json:"code"` // invalid_body or validation_failed
+	Errors  validation.Errors `json:"errors"`
+}
+
+// decodeRequest decodes the JSON request body into dst and checks its binding tags.
+// If either fails it writes a 400 response listing the failing fields and returns false.
+func decodeRequest(w http.ResponseWriter, r *http.Request, dst interface{}) bool {
+	return decodeJSON(w, r, dst) && validateRequest(w, dst)
+}
+
+// decodeJSON decodes the JSON request body into dst without validating it,
+// writing a 400 response if the body is malformed
+func decodeJSON(w http.ResponseWriter, r *http.Request, dst interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(dst); err != nil {
+		errs := validation.Errors{}
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) && typeErr.Field != "" {
+			errs = append(errs, validation.FieldError{
+				Field:   typeErr.Field,
+				Code:    "type",
+				Message: typeErr.Field + " must be a " + jsonTypeName(typeErr.Type.String()),
+			})
+		}
+		writeJSONError(w, validationErrorResponse{Message: "Invalid request body", Code: "invalid_body", Errors: errs})
+		return false
+	}
+	return true
+}
+
+// validateRequest checks v's binding tags, writing a 400 response listing the failing fields if any fail
+func validateRequest(w http.ResponseWriter, v interface{}) bool {
+	err := validation.Struct(v)
+	if err == nil {
+		return true
+	}
+	var errs validation.Errors
+	if errors.As(err, &errs) {
+		writeValidationErrors(w, errs)
+	} else {
+		log.Printf("Error validating %T: %v", v, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+	}
+	return false
+}
+
+// writeValidationErrors responds with 400 and the fields that failed validation
+func writeValidationErrors(w http.ResponseWriter, errs validation.Errors) {
+	writeJSONError(w, validationErrorResponse{Message: "Validation failed", Code: "validation_failed", Errors: errs})
+}
+
+// writeFieldError responds with 400 for a single field that failed a check made by the handler
+func writeFieldError(w http.ResponseWriter, field string, code string, message string) {
+	writeValidationErrors(w, validation.Errors{{Field: field, Code: code, Message: message}})
+}
+
+func writeJSONError(w http.ResponseWriter, body validationErrorResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusBadRequest)
+	json.NewEncoder(w).Encode(body)
+}
+
+// jsonTypeName describes a Go type the way a JSON client would think of it
+func jsonTypeName(goType string) string {
+	switch goType {
+	case "string", "time.Time", "primitive.ObjectID":
+		return "string"
+	case "bool":
+		return "boolean"
+	case "int", "int32", "int64", "float64":
+		return "number"
+	}
+	return "valid value"
+}
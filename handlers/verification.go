@@ -0,0 +1,190 @@
+package handlers
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"cricketApp/middleware/ratelimit"
+	"cricketApp/models"
+	"cricketApp/notification"
+	"cricketApp/validation"
+)
+
+const (
+	verificationCodeTTL     = 10 * time.Minute
+	maxVerificationAttempts = 5
+	verificationValidForUse = time.Hour // how long after confirming a code it can be used to register
+)
+
+// StartVerification sends a one-time code to an applicant's email address or phone number (public)
+func (h *RegistrationHandler) StartVerification(w http.ResponseWriter, r *http.Request) {
+	var req models.StartVerificationRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	var target string
+	var recipient notification.Recipient
+	switch req.Channel {
+	case models.VerificationEmail:
+		target = normalizeEmail(req.Target)
+		if !validation.IsEmail(target) {
+			writeFieldError(w, "target", "email", "target must be a valid email address")
+			return
+		}
+		recipient.Email = target
+	case models.VerificationPhone:
+		target = normalizeMobile(req.Target)
+		if !validation.IsMobile(target) {
+			writeFieldError(w, "target", "mobile", "target must be a valid 10-digit Indian mobile number")
+			return
+		}
+		recipient.Mobile = target
+	}
+
+	code, err := generateVerificationCode()
+	if err != nil {
+		http.Error(w, "Error generating verification code", http.StatusInternalServerError)
+		return
+	}
+
+	verification := &models.ContactVerification{
+		ID:        primitive.NewObjectID(),
+		Channel:   req.Channel,
+		Target:    target,
+		ExpiresAt: time.Now().Add(verificationCodeTTL),
+		RequestIP: ratelimit.ClientIP(r),
+	}
+	verification.CodeHash = h.hashVerificationCode(verification.ID, code)
+
+	if err := h.db.CreateVerification(r.Context(), verification); err != nil {
+		http.Error(w, "Error creating verification", http.StatusInternalServerError)
+		return
+	}
+
+	message := fmt.Sprintf("Your academy registration verification code is %s. It expires in %d minutes.", code, int(verificationCodeTTL.Minutes()))
+	// The code must only reach the applicant, never the log
+	if err := h.notifier.Notify(notification.WithSecret(r.Context()), recipient, "Verification code", message); err != nil {
+		log.Printf("Error sending verification code %s: %v", verification.ID.Hex(), err)
+		http.Error(w, "Error sending verification code", http.StatusBadGateway)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":        "Verification code sent",
+		"verificationId": verification.ID.Hex(),
+		"expiresAt":      verification.ExpiresAt,
+	})
+}
+
+// ConfirmVerification checks a one-time code sent by StartVerification (public)
+func (h *RegistrationHandler) ConfirmVerification(w http.ResponseWriter, r *http.Request) {
+	var req models.ConfirmVerificationRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	id, err := primitive.ObjectIDFromHex(req.VerificationID)
+	if err != nil {
+		http.Error(w, "Invalid verification ID", http.StatusBadRequest)
+		return
+	}
+
+	verification, err := h.db.RecordVerificationAttempt(r.Context(), id)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "Verification not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Error fetching verification", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	switch {
+	case verification.VerifiedAt != nil:
+		http.Error(w, "Already verified", http.StatusConflict)
+		return
+	case verification.Attempts > maxVerificationAttempts:
+		http.Error(w, "Too many attempts, request a new code", http.StatusTooManyRequests)
+		return
+	case time.Now().After(verification.ExpiresAt):
+		http.Error(w, "Verification code has expired, request a new code", http.StatusGone)
+		return
+	}
+
+	expected := h.hashVerificationCode(verification.ID, strings.TrimSpace(req.Code))
+	if !hmac.Equal([]byte(expected), []byte(verification.CodeHash)) {
+		http.Error(w, "Incorrect verification code", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.db.MarkVerificationVerified(r.Context(), id); err != nil {
+		http.Error(w, "Error updating verification", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": "Verified successfully"})
+}
+
+// hashVerificationCode keys the code hash to the verification so codes can't be compared across records
+func (h *RegistrationHandler) hashVerificationCode(id primitive.ObjectID, code string) string {
+	mac := hmac.New(sha256.New, h.secret)
+	mac.Write([]byte(id.Hex() + ":" + code))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// generateVerificationCode creates a random 6-digit code
+func generateVerificationCode() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1000000))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%06d", n.Int64()), nil
+}
+
+// normalizeEmail lowercases and trims an email address
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// normalizeMobile strips formatting and the +91 / 0 prefix from an Indian mobile number
+func normalizeMobile(mobile string) string {
+	digits := onlyDigits(mobile)
+	switch {
+	case len(digits) == 12 && strings.HasPrefix(digits, "91"):
+		return digits[2:]
+	case len(digits) == 11 && strings.HasPrefix(digits, "0"):
+		return digits[1:]
+	}
+	return digits
+}
+
+// mobileVariants lists the forms a normalized mobile number may have been stored in
+func mobileVariants(mobile string) []string {
+	return []string{mobile, "0" + mobile, "91" + mobile, "+91" + mobile, "+91 " + mobile}
+}
+
+func onlyDigits(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
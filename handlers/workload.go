@@ -0,0 +1,591 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"cricketApp/agecategory"
+	"cricketApp/db"
+	"cricketApp/models"
+	"cricketApp/notification"
+)
+
+const (
+	workloadWindowDays = 28 // chronic load window
+	workloadAcuteDays  = 7
+)
+
+// WorkloadHandler records bowling deliveries and monitors bowlers' workloads against age-category limits
+type WorkloadHandler struct {
+	db       db.Database
+	notifier notification.Notifier
+}
+
+func NewWorkloadHandler(db db.Database, notifier notification.Notifier) *WorkloadHandler {
+	return &WorkloadHandler{db: db, notifier: notifier}
+}
+
+// RecordSessionDeliveries records how many deliveries each bowler bowled in a session, replacing
+// earlier counts for the same bowlers, and alerts coaches about bowlers near or over their limits
+func (h *WorkloadHandler) RecordSessionDeliveries(w http.ResponseWriter, r *http.Request) {
+	userID, err := subjectIDFromClaims(r)
+	if err != nil {
+		http.Error(w, "Invalid token claims", http.StatusUnauthorized)
+		return
+	}
+	session, ok := staffSessionFromURL(w, r, h.db)
+	if !ok {
+		return
+	}
+
+	var req models.RecordDeliveriesRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	role := roleFromClaims(r)
+	entries := make([]models.WorkloadEntry, 0, len(req.Bowlers))
+	seen := make(map[primitive.ObjectID]bool, len(req.Bowlers))
+	for i, bowler := range req.Bowlers {
+		field := fmt.Sprintf("bowlers[%d].cricketerId", i)
+		cricketerID, _ := primitive.ObjectIDFromHex(bowler.CricketerID)
+		if seen[cricketerID] {
+			writeFieldError(w, field, "unique", "bowler is listed more than once")
+			return
+		}
+		seen[cricketerID] = true
+
+		cricketer, err := h.db.GetCricketerByID(r.Context(), cricketerID)
+		if err != nil {
+			if err == mongo.ErrNoDocuments {
+				writeFieldError(w, field, "exists", "cricketer not found")
+			} else {
+				http.Error(w, "Error fetching cricketer", http.StatusInternalServerError)
+			}
+			return
+		}
+		if session.BatchID != nil && (cricketer.BatchID == nil || *cricketer.BatchID != *session.BatchID) {
+			writeFieldError(w, field, "batch", "cricketer is not in this session's batch")
+			return
+		}
+
+		entries = append(entries, models.WorkloadEntry{
+			CricketerID:    cricketerID,
+			SessionID:      session.ID,
+			Date:           session.Date,
+			Deliveries:     bowler.Deliveries,
+			RecordedBy:     userID.Hex(),
+			RecordedByRole: role,
+		})
+	}
+
+	if err := h.db.SaveWorkloadEntries(r.Context(), entries); err != nil {
+		http.Error(w, "Error saving deliveries", http.StatusInternalServerError)
+		return
+	}
+
+	alerts := checkWorkloadAlerts(r.Context(), h.db, h.notifier, workloadBowlerIDs(entries), session.Date)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Deliveries recorded successfully",
+		"entries": entries,
+		"alerts":  alerts,
+	})
+}
+
+// GetSessionDeliveries returns the deliveries recorded for a session
+func (h *WorkloadHandler) GetSessionDeliveries(w http.ResponseWriter, r *http.Request) {
+	session, ok := staffSessionFromURL(w, r, h.db)
+	if !ok {
+		return
+	}
+
+	entries, err := h.db.GetWorkloadEntriesForSession(r.Context(), session.ID)
+	if err != nil {
+		http.Error(w, "Error fetching deliveries", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(entries)
+}
+
+// GetCricketerWorkload returns a bowler's daily deliveries over the last 28 days, their
+// acute:chronic workload ratio and their standing against their age category's limits,
+// as of ?date (default today)
+func (h *WorkloadHandler) GetCricketerWorkload(w http.ResponseWriter, r *http.Request) {
+	cricketer, ok := staffCricketerFromURL(w, r, h.db)
+	if !ok {
+		return
+	}
+	asOf, ok := workloadDateFromQuery(w, r)
+	if !ok {
+		return
+	}
+
+	workloads, err := bowlingWorkloads(r.Context(), h.db, []models.Cricketer{*cricketer}, asOf, true)
+	if err != nil {
+		http.Error(w, "Error calculating workload", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(workloads[0])
+}
+
+// GetBatchWorkload returns the workload of every cricketer in a batch who bowled in the last
+// 28 days, heaviest acute load first
+func (h *WorkloadHandler) GetBatchWorkload(w http.ResponseWriter, r *http.Request) {
+	batch, ok := staffBatchFromURL(w, r, h.db)
+	if !ok {
+		return
+	}
+	asOf, ok := workloadDateFromQuery(w, r)
+	if !ok {
+		return
+	}
+
+	cricketers, err := h.db.GetCricketersByBatches(r.Context(), []primitive.ObjectID{batch.ID})
+	if err != nil {
+		http.Error(w, "Error fetching cricketers", http.StatusInternalServerError)
+		return
+	}
+	workloads, err := bowlingWorkloads(r.Context(), h.db, cricketers, asOf, false)
+	if err != nil {
+		http.Error(w, "Error calculating workload", http.StatusInternalServerError)
+		return
+	}
+
+	bowlers := []models.BowlingWorkload{}
+	for _, workload := range workloads {
+		if workload.Chronic > 0 {
+			bowlers = append(bowlers, workload)
+		}
+	}
+	sort.SliceStable(bowlers, func(i, j int) bool { return bowlers[i].Acute > bowlers[j].Acute })
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(bowlers)
+}
+
+// GetWorkloadLimits lists the workload limits for each age category
+func (h *WorkloadHandler) GetWorkloadLimits(w http.ResponseWriter, r *http.Request) {
+	limits, err := h.db.GetWorkloadLimits(r.Context())
+	if err != nil {
+		http.Error(w, "Error fetching workload limits", http.StatusInternalServerError)
+		return
+	}
+	sort.Slice(limits, func(i, j int) bool {
+		return ageCategoryRank(limits[i].AgeCategory) < ageCategoryRank(limits[j].AgeCategory)
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(limits)
+}
+
+// UpdateWorkloadLimit sets the daily and weekly delivery limits for an age category (admin only)
+func (h *WorkloadHandler) UpdateWorkloadLimit(w http.ResponseWriter, r *http.Request) {
+	adminID, err := subjectIDFromClaims(r)
+	if err != nil {
+		http.Error(w, "Invalid token claims", http.StatusUnauthorized)
+		return
+	}
+	category := chi.URLParam(r, "ageCategory")
+	if _, ok := agecategory.Lookup(category); !ok && category != agecategory.Open {
+		http.Error(w, "Invalid age category", http.StatusBadRequest)
+		return
+	}
+
+	var req models.WorkloadLimitRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	limit := &models.WorkloadLimit{
+		AgeCategory:    category,
+		DailyMax:       req.DailyMax,
+		WeeklyMax:      req.WeeklyMax,
+		WarningPercent: req.WarningPercent,
+		UpdatedBy:      adminID.Hex(),
+	}
+	if limit.WarningPercent == 0 {
+		limit.WarningPercent = 80
+	}
+	if err := h.db.SaveWorkloadLimit(r.Context(), limit); err != nil {
+		http.Error(w, "Error saving workload limit", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Workload limit updated successfully",
+		"limit":   limit,
+	})
+}
+
+// GetWorkloadAlerts lists workload alerts, newest first, optionally only ?open ones or for ?cricketerId.
+// Coaches see alerts for their batches.
+func (h *WorkloadHandler) GetWorkloadAlerts(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := models.WorkloadAlertFilter{}
+	filter.Open, _ = strconv.ParseBool(query.Get("open"))
+	if value := query.Get("cricketerId"); value != "" {
+		cricketerID, err := primitive.ObjectIDFromHex(value)
+		if err != nil {
+			http.Error(w, "Invalid cricketer ID", http.StatusBadRequest)
+			return
+		}
+		filter.CricketerID = &cricketerID
+	}
+
+	if roleFromClaims(r) == "coach" {
+		batchIDs, ok := coachBatchIDs(w, r, h.db)
+		if !ok {
+			return
+		}
+		filter.BatchIDs = batchIDs
+	}
+
+	alerts, err := h.db.GetWorkloadAlerts(r.Context(), filter)
+	if err != nil {
+		http.Error(w, "Error fetching workload alerts", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(alerts)
+}
+
+// AcknowledgeWorkloadAlert marks a workload alert as followed up
+func (h *WorkloadHandler) AcknowledgeWorkloadAlert(w http.ResponseWriter, r *http.Request) {
+	alertID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid alert ID", http.StatusBadRequest)
+		return
+	}
+	userID, err := subjectIDFromClaims(r)
+	if err != nil {
+		http.Error(w, "Invalid token claims", http.StatusUnauthorized)
+		return
+	}
+
+	alert, err := h.db.GetWorkloadAlertByID(r.Context(), alertID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "Workload alert not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Error fetching workload alert", http.StatusInternalServerError)
+		}
+		return
+	}
+	if roleFromClaims(r) == "coach" {
+		batchIDs, ok := coachBatchIDs(w, r, h.db)
+		if !ok {
+			return
+		}
+		if alert.BatchID == nil || !containsObjectID(batchIDs, *alert.BatchID) {
+			http.Error(w, "Workload alert not found", http.StatusNotFound)
+			return
+		}
+	}
+
+	alert, err = h.db.AcknowledgeWorkloadAlert(r.Context(), alertID, userID.Hex())
+	if err != nil {
+		http.Error(w, "Error acknowledging workload alert", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Workload alert acknowledged",
+		"alert":   alert,
+	})
+}
+
+// bowlingWorkloads works out each cricketer's workload for the 28 days up to and including asOf's
+// day, from session delivery counts and match bowling figures
+func bowlingWorkloads(ctx context.Context, database db.Database, cricketers []models.Cricketer, asOf time.Time, withDays bool) ([]models.BowlingWorkload, error) {
+	today := startOfDay(asOf)
+	from := today.AddDate(0, 0, 1-workloadWindowDays)
+	to := today.AddDate(0, 0, 1)
+
+	dayIndex := make(map[string]int, workloadWindowDays)
+	for i := 0; i < workloadWindowDays; i++ {
+		dayIndex[from.AddDate(0, 0, i).Format("2006-01-02")] = i
+	}
+	dayOf := func(t time.Time) (int, bool) {
+		i, ok := dayIndex[t.In(today.Location()).Format("2006-01-02")]
+		return i, ok
+	}
+
+	ids := make([]primitive.ObjectID, len(cricketers))
+	days := make(map[primitive.ObjectID][]models.WorkloadDay, len(cricketers))
+	for i, cricketer := range cricketers {
+		ids[i] = cricketer.ID
+		days[cricketer.ID] = make([]models.WorkloadDay, workloadWindowDays)
+	}
+
+	entries, err := database.GetWorkloadEntries(ctx, ids, from, to)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if i, ok := dayOf(entry.Date); ok {
+			days[entry.CricketerID][i].Sessions += entry.Deliveries
+		}
+	}
+
+	matches, err := database.GetMatches(ctx, models.MatchFilter{From: &from, To: &to})
+	if err != nil {
+		return nil, err
+	}
+	for _, match := range matches {
+		i, ok := dayOf(match.Date)
+		if !ok {
+			continue
+		}
+		for _, innings := range match.Innings {
+			for _, bowling := range innings.Bowling {
+				if bowling.CricketerID == nil {
+					continue
+				}
+				if bowlerDays, ok := days[*bowling.CricketerID]; ok {
+					bowlerDays[i].Matches += bowling.Balls + bowling.Wides + bowling.NoBalls
+				}
+			}
+		}
+	}
+
+	season, err := seasonAt(ctx, database, asOf)
+	if err != nil {
+		return nil, err
+	}
+	limitList, err := database.GetWorkloadLimits(ctx)
+	if err != nil {
+		return nil, err
+	}
+	limits := make(map[string]models.WorkloadLimit, len(limitList))
+	for _, limit := range limitList {
+		limits[limit.AgeCategory] = limit
+	}
+
+	workloads := make([]models.BowlingWorkload, 0, len(cricketers))
+	for _, cricketer := range cricketers {
+		workload := models.BowlingWorkload{
+			CricketerID:   cricketer.ID,
+			CricketerName: cricketer.Name,
+			BatchID:       cricketer.BatchID,
+			AgeCategory:   ageCategoryAt(cricketer.DateOfBirth, season),
+			AsOf:          today,
+			DailyStatus:   models.WorkloadWithinLimit,
+			WeeklyStatus:  models.WorkloadWithinLimit,
+		}
+
+		total := 0
+		bowlerDays := days[cricketer.ID]
+		for i := range bowlerDays {
+			bowlerDays[i].Date = from.AddDate(0, 0, i)
+			bowlerDays[i].Total = bowlerDays[i].Sessions + bowlerDays[i].Matches
+			total += bowlerDays[i].Total
+			if i >= workloadWindowDays-workloadAcuteDays {
+				workload.Acute += bowlerDays[i].Total
+			}
+		}
+		workload.Today = bowlerDays[workloadWindowDays-1].Total
+		workload.Chronic = float64(total) / (workloadWindowDays / workloadAcuteDays)
+		if workload.Chronic > 0 {
+			ratio := math.Round(float64(workload.Acute)/workload.Chronic*100) / 100
+			workload.Ratio = &ratio
+			workload.Zone = workloadZone(ratio)
+		}
+		if withDays {
+			workload.Days = bowlerDays
+		}
+
+		if limit, ok := limits[workload.AgeCategory]; ok {
+			workload.Limit = &limit
+			workload.DailyStatus = limit.Status(workload.Today, limit.DailyMax)
+			workload.WeeklyStatus = limit.Status(workload.Acute, limit.WeeklyMax)
+		}
+		workloads = append(workloads, workload)
+	}
+	return workloads, nil
+}
+
+// workloadZone classifies an acute:chronic workload ratio
+func workloadZone(ratio float64) string {
+	switch {
+	case ratio > 1.5:
+		return models.WorkloadZoneHighRisk
+	case ratio > 1.3:
+		return models.WorkloadZoneCaution
+	case ratio >= 0.8:
+		return models.WorkloadZoneOptimal
+	}
+	return models.WorkloadZoneLow
+}
+
+// checkWorkloadAlerts raises alerts for bowlers approaching or over their limits, or whose
+// acute:chronic ratio is in the high-risk zone, on the day they bowled, and notifies their batch's
+// coaches of new alerts. Failures are logged: the deliveries are already saved and alerts only
+// prompt the coach.
+func checkWorkloadAlerts(ctx context.Context, database db.Database, notifier notification.Notifier, cricketerIDs []primitive.ObjectID, date time.Time) []models.WorkloadAlert {
+	alerts := []models.WorkloadAlert{}
+	if len(cricketerIDs) == 0 {
+		return alerts
+	}
+
+	cricketers := make([]models.Cricketer, 0, len(cricketerIDs))
+	for _, id := range cricketerIDs {
+		cricketer, err := database.GetCricketerByID(ctx, id)
+		if err != nil {
+			log.Printf("Error fetching cricketer %s for workload alerts: %v", id.Hex(), err)
+			continue
+		}
+		cricketers = append(cricketers, *cricketer)
+	}
+	workloads, err := bowlingWorkloads(ctx, database, cricketers, date, false)
+	if err != nil {
+		log.Printf("Error calculating workloads for alerts: %v", err)
+		return alerts
+	}
+
+	for _, workload := range workloads {
+		candidates := []models.WorkloadAlert{}
+		if workload.Limit != nil && workload.DailyStatus != models.WorkloadWithinLimit {
+			candidates = append(candidates, models.WorkloadAlert{Kind: models.WorkloadAlertDaily, Level: workload.DailyStatus,
+				Deliveries: workload.Today, Limit: workload.Limit.DailyMax})
+		}
+		if workload.Limit != nil && workload.WeeklyStatus != models.WorkloadWithinLimit {
+			candidates = append(candidates, models.WorkloadAlert{Kind: models.WorkloadAlertWeekly, Level: workload.WeeklyStatus,
+				Deliveries: workload.Acute, Limit: workload.Limit.WeeklyMax})
+		}
+		if workload.Zone == models.WorkloadZoneHighRisk {
+			candidates = append(candidates, models.WorkloadAlert{Kind: models.WorkloadAlertRatio, Level: models.WorkloadZoneHighRisk,
+				Deliveries: workload.Acute, Ratio: *workload.Ratio})
+		}
+
+		for _, alert := range candidates {
+			alert.CricketerID = workload.CricketerID
+			alert.CricketerName = workload.CricketerName
+			alert.BatchID = workload.BatchID
+			alert.Date = workload.AsOf
+			created, err := database.CreateWorkloadAlert(ctx, &alert)
+			if err != nil {
+				log.Printf("Error creating workload alert for cricketer %s: %v", workload.CricketerID.Hex(), err)
+				continue
+			}
+			if created {
+				alerts = append(alerts, alert)
+			}
+		}
+	}
+
+	notifyWorkloadAlerts(ctx, database, notifier, alerts)
+	return alerts
+}
+
+// notifyWorkloadAlerts sends each batch's coaches one message listing the batch's new alerts
+func notifyWorkloadAlerts(ctx context.Context, database db.Database, notifier notification.Notifier, alerts []models.WorkloadAlert) {
+	byBatch := make(map[primitive.ObjectID][]string)
+	var batchIDs []primitive.ObjectID
+	for _, alert := range alerts {
+		if alert.BatchID == nil {
+			continue
+		}
+		if _, ok := byBatch[*alert.BatchID]; !ok {
+			batchIDs = append(batchIDs, *alert.BatchID)
+		}
+		byBatch[*alert.BatchID] = append(byBatch[*alert.BatchID], describeWorkloadAlert(alert))
+	}
+
+	for _, batchID := range batchIDs {
+		batch, err := database.GetBatchByID(ctx, batchID)
+		if err != nil {
+			log.Printf("Error fetching batch %s for workload alerts: %v", batchID.Hex(), err)
+			continue
+		}
+		message := strings.Join(byBatch[batchID], "; ")
+		for _, coachID := range batch.CoachIDs {
+			coach, err := database.GetCoachByID(ctx, coachID)
+			if err != nil {
+				log.Printf("Error fetching coach %s for workload alerts: %v", coachID.Hex(), err)
+				continue
+			}
+			recipient := notification.Recipient{Name: coach.Name, Mobile: coach.Mobile}
+			if err := notifier.Notify(ctx, recipient, "Bowling workload in "+batch.Name, message); err != nil {
+				log.Printf("Error notifying coach %s of workload alerts: %v", coachID.Hex(), err)
+			}
+		}
+	}
+}
+
+func describeWorkloadAlert(alert models.WorkloadAlert) string {
+	switch alert.Kind {
+	case models.WorkloadAlertDaily:
+		return fmt.Sprintf("%s has bowled %d deliveries today (%s the daily limit of %d)",
+			alert.CricketerName, alert.Deliveries, alert.Level, alert.Limit)
+	case models.WorkloadAlertWeekly:
+		return fmt.Sprintf("%s has bowled %d deliveries in 7 days (%s the weekly limit of %d)",
+			alert.CricketerName, alert.Deliveries, alert.Level, alert.Limit)
+	}
+	return fmt.Sprintf("%s's acute:chronic workload ratio is %.2f", alert.CricketerName, alert.Ratio)
+}
+
+// matchBowlerIDs returns the academy cricketers who bowled in a match's innings
+func matchBowlerIDs(innings []models.Innings) []primitive.ObjectID {
+	var ids []primitive.ObjectID
+	for _, entry := range innings {
+		for _, bowling := range entry.Bowling {
+			if bowling.CricketerID != nil {
+				ids = append(ids, *bowling.CricketerID)
+			}
+		}
+	}
+	return uniqueObjectIDs(ids)
+}
+
+func workloadBowlerIDs(entries []models.WorkloadEntry) []primitive.ObjectID {
+	ids := make([]primitive.ObjectID, len(entries))
+	for i, entry := range entries {
+		ids[i] = entry.CricketerID
+	}
+	return ids
+}
+
+// workloadDateFromQuery reads ?date (YYYY-MM-DD), defaulting to now
+func workloadDateFromQuery(w http.ResponseWriter, r *http.Request) (time.Time, bool) {
+	value := r.URL.Query().Get("date")
+	if value == "" {
+		return time.Now(), true
+	}
+	date, err := time.ParseInLocation("2006-01-02", value, time.Local)
+	if err != nil {
+		http.Error(w, "Invalid date, expected YYYY-MM-DD", http.StatusBadRequest)
+		return time.Time{}, false
+	}
+	return date, true
+}
+
+// ageCategoryRank orders age categories youngest first, with Open last
+func ageCategoryRank(name string) int {
+	for i, category := range agecategory.Categories {
+		if category.Name == name {
+			return i
+		}
+	}
+	return len(agecategory.Categories)
+}
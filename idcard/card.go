@@ -0,0 +1,259 @@
+package idcard
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/jpeg" // photos are JPEG
+	"io"
+	"math"
+	"strings"
+	"time"
+
+	"cricketApp/qrcode"
+)
+
+// Card dimensions in points: ISO/IEC 7810 ID-1, the size of a bank card
+const (
+	cardWidth  = 242.65
+	cardHeight = 153.07
+)
+
+// quietZone is the blank margin, in modules, that scanners need around a QR code
+const quietZone = 4
+
+// Card is the information printed on a player ID card
+type Card struct {
+	Title       string // the academy's name
+	Name        string
+	Batch       string
+	AgeCategory string
+	Number      string
+	ValidUntil  time.Time
+	Token       string // encoded in the QR code
+	Photo       []byte // JPEG; the cricketer's initials are shown without one
+}
+
+// WritePDF writes the card as a single-page PDF the size of the card
+func WritePDF(w io.Writer, card *Card) error {
+	code, err := qrcode.Encode([]byte(card.Token))
+	if err != nil {
+		return err
+	}
+
+	var photo *pdfImage
+	if len(card.Photo) > 0 {
+		if photo, err = jpegImage(card.Photo); err != nil {
+			return err
+		}
+	}
+
+	var content bytes.Buffer
+	drawCard(&content, card, code, photo)
+
+	doc := &pdfDocument{}
+	doc.add("<< /Type /Catalog /Pages 2 0 R >>")
+	doc.add("<< /Type /Pages /Kids [3 0 R] /Count 1 >>")
+	resources := "/Font << /F1 5 0 R /F2 6 0 R >>"
+	if photo != nil {
+		resources += " /XObject << /Photo 7 0 R >>"
+	}
+	doc.add(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] /Resources << %s >> /Contents 4 0 R >>", cardWidth, cardHeight, resources))
+	doc.addStream("", content.Bytes())
+	doc.add("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	doc.add("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	if photo != nil {
+		doc.addStream(fmt.Sprintf("/Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /%s /BitsPerComponent 8 /Filter /DCTDecode",
+			photo.width, photo.height, photo.colorSpace), photo.data)
+	}
+
+	_, err = w.Write(doc.bytes())
+	return err
+}
+
+// drawCard writes the page's content stream
+func drawCard(out *bytes.Buffer, card *Card, code *qrcode.Code, photo *pdfImage) {
+	// Header band
+	fmt.Fprintf(out, "0.067 0.365 0.235 rg 0 125 %.2f 28.07 re f\n", cardWidth)
+	text(out, "F2", 11, 10, 135, 1, fit(card.Title, 11, 160, true))
+	text(out, "F1", 7, cardWidth-52, 136, 1, "PLAYER ID")
+
+	// Name across the card, details beside the photo
+	text(out, "F2", 11, 10, 108, 0, fit(card.Name, 11, 138, true))
+	details := [][2]string{
+		{"Batch", card.Batch},
+		{"Age category", card.AgeCategory},
+		{"Card no.", card.Number},
+		{"Valid until", card.ValidUntil.Format("2 Jan 2006")},
+	}
+	y := 86.0
+	for _, detail := range details {
+		if detail[1] == "" {
+			continue
+		}
+		text(out, "F1", 6, 70, y+7, 0.4, detail[0])
+		text(out, "F2", 7.5, 70, y, 0, fit(detail[1], 7.5, 78, true))
+		y -= 16
+	}
+
+	// Photo, scaled to cover its box and cropped, or initials in its place
+	const photoX, photoY, photoW, photoH = 10.0, 30.0, 52.0, 66.0
+	if photo != nil {
+		scale := math.Max(photoW/float64(photo.width), photoH/float64(photo.height))
+		drawW, drawH := float64(photo.width)*scale, float64(photo.height)*scale
+		fmt.Fprintf(out, "q %.2f %.2f %.2f %.2f re W n %.3f 0 0 %.3f %.3f %.3f cm /Photo Do Q\n",
+			photoX, photoY, photoW, photoH, drawW, drawH, photoX-(drawW-photoW)/2, photoY-(drawH-photoH)/2)
+	} else {
+		fmt.Fprintf(out, "0.9 0.9 0.9 rg %.2f %.2f %.2f %.2f re f\n", photoX, photoY, photoW, photoH)
+		initials := initialsOf(card.Name)
+		text(out, "F2", 20, photoX+photoW/2-width(initials, 20, true)/2, photoY+photoH/2-7, 0.5, initials)
+	}
+
+	// QR code with its quiet zone, dark modules as filled squares
+	const qrX, qrY, qrSize = 152.0, 20.0, 84.0
+	module := qrSize / float64(code.Size+2*quietZone)
+	out.WriteString("0 0 0 rg\n")
+	for y := 0; y < code.Size; y++ {
+		for x := 0; x < code.Size; x++ {
+			if code.Dark(x, y) {
+				fmt.Fprintf(out, "%.3f %.3f %.3f %.3f re\n",
+					qrX+float64(x+quietZone)*module, qrY+qrSize-float64(y+quietZone+1)*module, module, module)
+			}
+		}
+	}
+	out.WriteString("f\n")
+
+	text(out, "F1", 5.5, 10, 10, 0.4, "Show this card to your coach at the start of every session.")
+}
+
+// text draws a line of text in the given font, size, position and grey level (0 is black, 1 white)
+func text(out *bytes.Buffer, font string, size float64, x, y float64, grey float64, value string) {
+	fmt.Fprintf(out, "BT %.3f g /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", grey, font, size, x, y, escape(value))
+}
+
+// escape encodes a string for a PDF literal in WinAnsiEncoding, replacing characters it can't show
+func escape(value string) string {
+	var b strings.Builder
+	for _, r := range value {
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r >= 0x20 && r < 0x7F:
+			b.WriteRune(r)
+		case r >= 0xA0 && r <= 0xFF:
+			fmt.Fprintf(&b, "\\%03o", r)
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
+
+// width estimates the width of a string in points. Helvetica averages a little over half an em
+// per character, bold slightly more; capitals and digits are wider than lower case.
+func width(value string, size float64, bold bool) float64 {
+	total := 0.0
+	for _, r := range value {
+		switch {
+		case r == ' ' || r == '.' || r == ',' || r == 'i' || r == 'l' || r == 'j' || r == 'I':
+			total += 0.28
+		case r >= 'A' && r <= 'Z', r == 'm', r == 'w':
+			total += 0.72
+		default:
+			total += 0.56
+		}
+	}
+	if bold {
+		total *= 1.06
+	}
+	return total * size
+}
+
+// fit shortens value with an ellipsis until it fits in maxWidth points
+func fit(value string, size float64, maxWidth float64, bold bool) string {
+	if width(value, size, bold) <= maxWidth {
+		return value
+	}
+	runes := []rune(value)
+	for len(runes) > 0 && width(string(runes)+"...", size, bold) > maxWidth {
+		runes = runes[:len(runes)-1]
+	}
+	return strings.TrimSpace(string(runes)) + "..."
+}
+
+// initialsOf returns the first letters of the first and last words of a name
+func initialsOf(name string) string {
+	words := strings.Fields(name)
+	if len(words) == 0 {
+		return "?"
+	}
+	initials := []rune(words[0])[:1]
+	if len(words) > 1 {
+		initials = append(initials, []rune(words[len(words)-1])[0])
+	}
+	return strings.ToUpper(string(initials))
+}
+
+// pdfImage is a JPEG that PDF readers can decode directly
+type pdfImage struct {
+	data          []byte
+	width, height int
+	colorSpace    string
+}
+
+func jpegImage(data []byte) (*pdfImage, error) {
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if format != "jpeg" {
+		return nil, fmt.Errorf("photo must be a JPEG, not %s", format)
+	}
+	colorSpace := "DeviceRGB"
+	switch config.ColorModel {
+	case color.GrayModel:
+		colorSpace = "DeviceGray"
+	case color.CMYKModel:
+		colorSpace = "DeviceCMYK"
+	}
+	return &pdfImage{data: data, width: config.Width, height: config.Height, colorSpace: colorSpace}, nil
+}
+
+// pdfDocument collects numbered objects and writes them with a cross-reference table
+type pdfDocument struct {
+	objects [][]byte
+}
+
+func (d *pdfDocument) add(object string) {
+	d.objects = append(d.objects, []byte(object))
+}
+
+func (d *pdfDocument) addStream(dictionary string, data []byte) {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "<< %s /Length %d >>\nstream\n", strings.TrimSpace(dictionary), len(data))
+	b.Write(data)
+	b.WriteString("\nendstream")
+	d.objects = append(d.objects, b.Bytes())
+}
+
+func (d *pdfDocument) bytes() []byte {
+	var b bytes.Buffer
+	b.WriteString("%PDF-1.4\n%\xE2\xE3\xCF\xD3\n")
+	offsets := make([]int, len(d.objects))
+	for i, object := range d.objects {
+		offsets[i] = b.Len()
+		fmt.Fprintf(&b, "%d 0 obj\n", i+1)
+		b.Write(object)
+		b.WriteString("\nendobj\n")
+	}
+
+	xref := b.Len()
+	fmt.Fprintf(&b, "xref\n0 %d\n0000000000 65535 f \n", len(d.objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&b, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&b, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(d.objects)+1, xref)
+	return b.Bytes()
+}
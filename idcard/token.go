@@ -0,0 +1,107 @@
+// Package idcard issues player ID cards: a printable PDF card carrying a QR code with a signed
+// token that coaches scan to check cricketers in at sessions.
+package idcard
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"cricketApp/pii"
+)
+
+var (
+	ErrInvalidToken = errors.New("ID card token is invalid")
+	ErrTokenExpired = errors.New("ID card has expired")
+)
+
+// tokenPrefix marks the token format so it can change without old cards being misread
+const tokenPrefix = "CA1"
+
+// signatureSize is how much of the HMAC-SHA256 is kept, enough to resist forgery while keeping the QR code small
+const signatureSize = 16
+
+// Claims is what an ID card token vouches for
+type Claims struct {
+	CricketerID primitive.ObjectID
+	Version     int // the card's version; reissuing a card makes older versions invalid
+	ExpiresAt   time.Time
+}
+
+// Signer signs and verifies ID card tokens. Tokens are signed with a key derived from the
+// current PII key-encryption key and name the key they were signed with, so rotating the PII
+// keys also rotates card signing while cards signed with older keys keep working until they expire.
+type Signer struct {
+	keys pii.KeyProvider
+}
+
+// NewSigner creates a Signer using keys from provider
+func NewSigner(keys pii.KeyProvider) *Signer {
+	return &Signer{keys: keys}
+}
+
+// Sign returns the token for claims, formatted as CA1:<key ID>:<payload>:<signature>
+func (s *Signer) Sign(claims Claims) (string, error) {
+	keyID := s.keys.CurrentKeyID()
+	payload := make([]byte, 0, 24)
+	payload = append(payload, claims.CricketerID[:]...)
+	payload = binary.BigEndian.AppendUint32(payload, uint32(claims.Version))
+	payload = binary.BigEndian.AppendUint64(payload, uint64(claims.ExpiresAt.Unix()))
+
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	signature, err := s.signature(keyID, encoded)
+	if err != nil {
+		return "", err
+	}
+	return strings.Join([]string{tokenPrefix, keyID, encoded, signature}, ":"), nil
+}
+
+// Verify checks a token's signature and expiry and returns its claims
+func (s *Signer) Verify(token string) (*Claims, error) {
+	parts := strings.Split(strings.TrimSpace(token), ":")
+	if len(parts) != 4 || parts[0] != tokenPrefix {
+		return nil, ErrInvalidToken
+	}
+	keyID, encoded := parts[1], parts[2]
+
+	expected, err := s.signature(keyID, encoded)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	if !hmac.Equal([]byte(expected), []byte(parts[3])) {
+		return nil, ErrInvalidToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil || len(payload) != 24 {
+		return nil, ErrInvalidToken
+	}
+	claims := &Claims{
+		Version:   int(binary.BigEndian.Uint32(payload[12:16])),
+		ExpiresAt: time.Unix(int64(binary.BigEndian.Uint64(payload[16:])), 0),
+	}
+	copy(claims.CricketerID[:], payload[:12])
+	if time.Now().After(claims.ExpiresAt) {
+		return claims, ErrTokenExpired
+	}
+	return claims, nil
+}
+
+func (s *Signer) signature(keyID string, payload string) (string, error) {
+	key, err := s.keys.Key(keyID)
+	if err != nil {
+		return "", err
+	}
+	// Derive a separate signing key so the key-encryption key itself is never used for MACs
+	derive := hmac.New(sha256.New, key)
+	derive.Write([]byte("idcard-signing"))
+	mac := hmac.New(sha256.New, derive.Sum(nil))
+	mac.Write([]byte(keyID + ":" + payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil)[:signatureSize]), nil
+}
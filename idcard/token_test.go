@@ -0,0 +1,181 @@
+package idcard
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"cricketApp/pii"
+)
+
+// testKeys is an in-memory pii.KeyProvider
+type testKeys struct {
+	current string
+	keys    map[string][]byte
+}
+
+func newTestKeys(ids ...string) *testKeys {
+	k := &testKeys{keys: map[string][]byte{}}
+	for _, id := range ids {
+		k.add(id)
+	}
+	return k
+}
+
+// add adds a key and makes it current
+func (k *testKeys) add(id string) {
+	k.keys[id] = bytes.Repeat([]byte(id[:1]), pii.KeySize)
+	k.current = id
+}
+
+func (k *testKeys) CurrentKeyID() string { return k.current }
+
+func (k *testKeys) Key(id string) ([]byte, error) {
+	key, ok := k.keys[id]
+	if !ok {
+		return nil, pii.ErrUnknownKey
+	}
+	return key, nil
+}
+
+func (k *testKeys) IndexKey() []byte { return make([]byte, pii.KeySize) }
+
+func testClaims(expiresIn time.Duration) Claims {
+	return Claims{
+		CricketerID: primitive.NewObjectID(),
+		Version:     3,
+		ExpiresAt:   time.Now().Add(expiresIn).Truncate(time.Second),
+	}
+}
+
+func TestSignVerify(t *testing.T) {
+	signer := NewSigner(newTestKeys("a1"))
+	claims := testClaims(24 * time.Hour)
+
+	token, err := signer.Sign(claims)
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+	if !strings.HasPrefix(token, "CA1:a1:") {
+		t.Errorf("token %q doesn't name the format and key", token)
+	}
+
+	got, err := signer.Verify(" " + token + "\n")
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if got.CricketerID != claims.CricketerID || got.Version != claims.Version || !got.ExpiresAt.Equal(claims.ExpiresAt) {
+		t.Errorf("claims = %+v, want %+v", got, claims)
+	}
+}
+
+func TestVerifyExpired(t *testing.T) {
+	signer := NewSigner(newTestKeys("a1"))
+	claims := testClaims(-time.Hour)
+	token, err := signer.Sign(claims)
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+
+	got, err := signer.Verify(token)
+	if !errors.Is(err, ErrTokenExpired) {
+		t.Fatalf("Verify = %v, want ErrTokenExpired", err)
+	}
+	// The claims are still returned so the expiry date can be shown
+	if got == nil || got.CricketerID != claims.CricketerID || !got.ExpiresAt.Equal(claims.ExpiresAt) {
+		t.Errorf("claims = %+v, want %+v", got, claims)
+	}
+}
+
+func TestVerifyRejectsTampering(t *testing.T) {
+	signer := NewSigner(newTestKeys("a1"))
+	token, err := signer.Sign(testClaims(24 * time.Hour))
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+	parts := strings.Split(token, ":")
+
+	// reencoded returns the token with its payload changed but the original signature
+	reencoded := func(change func(payload []byte)) string {
+		payload, err := base64.RawURLEncoding.DecodeString(parts[2])
+		if err != nil {
+			t.Fatalf("decoding payload: %v", err)
+		}
+		change(payload)
+		return strings.Join([]string{parts[0], parts[1], base64.RawURLEncoding.EncodeToString(payload), parts[3]}, ":")
+	}
+	flipped := []byte(parts[3])
+	flipped[0] ^= 1
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"empty", ""},
+		{"other cricketer", reencoded(func(p []byte) { p[11] ^= 1 })},
+		{"older version", reencoded(func(p []byte) { binary.BigEndian.PutUint32(p[12:], 2) })},
+		{"later expiry", reencoded(func(p []byte) {
+			binary.BigEndian.PutUint64(p[16:], binary.BigEndian.Uint64(p[16:])+365*24*3600)
+		})},
+		{"altered signature", strings.Join([]string{parts[0], parts[1], parts[2], string(flipped)}, ":")},
+		{"missing signature", strings.Join(parts[:3], ":")},
+		{"extra part", token + ":x"},
+		{"other format", strings.Join([]string{"CA2", parts[1], parts[2], parts[3]}, ":")},
+		{"unknown key", strings.Join([]string{parts[0], "b2", parts[2], parts[3]}, ":")},
+		{"short payload", strings.Join([]string{parts[0], parts[1], parts[2][:10], parts[3]}, ":")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := signer.Verify(tt.token); !errors.Is(err, ErrInvalidToken) {
+				t.Errorf("Verify = %v, want ErrInvalidToken", err)
+			}
+		})
+	}
+}
+
+func TestVerifyOtherKeys(t *testing.T) {
+	token, err := NewSigner(newTestKeys("a1")).Sign(testClaims(24 * time.Hour))
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+
+	// Another installation with a key of the same ID can't verify it
+	other := newTestKeys("a1")
+	other.keys["a1"] = bytes.Repeat([]byte("z"), pii.KeySize)
+	if _, err := NewSigner(other).Verify(token); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Verify with other keys = %v, want ErrInvalidToken", err)
+	}
+}
+
+func TestVerifyAfterRotation(t *testing.T) {
+	keys := newTestKeys("a1")
+	signer := NewSigner(keys)
+	old, err := signer.Sign(testClaims(24 * time.Hour))
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+
+	keys.add("b2")
+	if _, err := signer.Verify(old); err != nil {
+		t.Errorf("card signed before rotation: Verify = %v, want nil", err)
+	}
+	current, err := signer.Sign(testClaims(24 * time.Hour))
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+	if !strings.HasPrefix(current, "CA1:b2:") {
+		t.Errorf("token %q isn't signed with the current key", current)
+	}
+
+	// Dropping a key invalidates the cards signed with it
+	delete(keys.keys, "a1")
+	if _, err := signer.Verify(old); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("card signed with a removed key: Verify = %v, want ErrInvalidToken", err)
+	}
+}
@@ -0,0 +1,201 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"testing"
+)
+
+// tiffWithOrientation builds the TIFF structure of an EXIF block whose first IFD holds one
+// unrelated entry followed by the orientation
+func tiffWithOrientation(order binary.ByteOrder, orientation uint16) []byte {
+	tiff := make([]byte, 8+2+2*12+4)
+	if order == binary.LittleEndian {
+		copy(tiff, "II")
+	} else {
+		copy(tiff, "MM")
+	}
+	order.PutUint16(tiff[2:], 42)
+	order.PutUint32(tiff[4:], 8)
+	order.PutUint16(tiff[8:], 2)
+
+	// ImageWidth, LONG
+	entry := tiff[10:]
+	order.PutUint16(entry, 0x0100)
+	order.PutUint16(entry[2:], 4)
+	order.PutUint32(entry[4:], 1)
+	order.PutUint32(entry[8:], 4000)
+
+	// Orientation, SHORT
+	entry = tiff[22:]
+	order.PutUint16(entry, orientationTag)
+	order.PutUint16(entry[2:], 3)
+	order.PutUint32(entry[4:], 1)
+	order.PutUint16(entry[8:], orientation)
+	return tiff
+}
+
+// segment builds a JPEG marker segment
+func segment(marker byte, payload []byte) []byte {
+	out := []byte{0xFF, marker, 0, 0}
+	binary.BigEndian.PutUint16(out[2:], uint16(len(payload)+2))
+	return append(out, payload...)
+}
+
+func exifSegment(tiff []byte) []byte {
+	return segment(0xE1, append([]byte("Exif\x00\x00"), tiff...))
+}
+
+// withSegments inserts segments straight after the SOI marker of a JPEG
+func withSegments(jpegData []byte, segments ...[]byte) []byte {
+	out := append([]byte{}, jpegData[:2]...)
+	for _, s := range segments {
+		out = append(out, s...)
+	}
+	return append(out, jpegData[2:]...)
+}
+
+var soi = []byte{0xFF, 0xD8}
+
+func TestExifOrientation(t *testing.T) {
+	for _, order := range []binary.ByteOrder{binary.LittleEndian, binary.BigEndian} {
+		for orientation := 1; orientation <= 8; orientation++ {
+			data := withSegments(append(soi, 0xFF, 0xD9), exifSegment(tiffWithOrientation(order, uint16(orientation))))
+			if got := exifOrientation(data); got != orientation {
+				t.Errorf("%v orientation %d: exifOrientation = %d", order, orientation, got)
+			}
+		}
+	}
+}
+
+func TestExifOrientationAfterOtherSegments(t *testing.T) {
+	jfif := segment(0xE0, []byte("JFIF\x00\x01\x01\x00\x00\x01\x00\x01\x00\x00"))
+	xmp := segment(0xE1, []byte("http://ns.adobe.com/xap/1.0/\x00<x:xmpmeta/>"))
+	data := withSegments(append(soi, 0xFF, 0xD9), jfif, xmp, exifSegment(tiffWithOrientation(binary.BigEndian, 6)))
+
+	if got := exifOrientation(data); got != 6 {
+		t.Errorf("exifOrientation = %d, want 6", got)
+	}
+}
+
+func TestExifOrientationMalformed(t *testing.T) {
+	valid := tiffWithOrientation(binary.LittleEndian, 6)
+	modified := func(change func(tiff []byte)) []byte {
+		tiff := append([]byte{}, valid...)
+		change(tiff)
+		return tiff
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"empty", nil},
+		{"not a JPEG", []byte("\x89PNG\r\n\x1a\n")},
+		{"SOI only", soi},
+		{"garbage after SOI", append(soi, 0x00, 0x01, 0x02, 0x03)},
+		{"segment length below 2", append(soi, 0xFF, 0xE1, 0x00, 0x01, 0x00, 0x00)},
+		{"segment longer than the file", append(soi, 0xFF, 0xE1, 0x40, 0x00, 'E', 'x')},
+		{"EXIF after the image data", append(append(soi, segment(0xDA, []byte{0, 0})...), exifSegment(valid)...)},
+		{"EXIF header with no TIFF", append(soi, segment(0xE1, []byte("Exif\x00\x00"))...)},
+		{"short TIFF header", append(soi, exifSegment([]byte("II*\x00"))...)},
+		{"unknown byte order", append(soi, exifSegment(modified(func(tiff []byte) { copy(tiff, "XX") }))...)},
+		{"IFD inside the header", append(soi, exifSegment(modified(func(tiff []byte) { binary.LittleEndian.PutUint32(tiff[4:], 2) }))...)},
+		{"IFD past the end", append(soi, exifSegment(modified(func(tiff []byte) { binary.LittleEndian.PutUint32(tiff[4:], 0xFFFFFFF0) }))...)},
+		{"more entries than data", append(soi, exifSegment(modified(func(tiff []byte) {
+			binary.LittleEndian.PutUint16(tiff[8:], 0xFFFF)
+			binary.LittleEndian.PutUint16(tiff[22:], 0x0101) // hide the orientation so the count is what matters
+		}))...)},
+		{"orientation 0", append(soi, exifSegment(tiffWithOrientation(binary.LittleEndian, 0))...)},
+		{"orientation 9", append(soi, exifSegment(tiffWithOrientation(binary.LittleEndian, 9))...)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := exifOrientation(tt.data); got != 1 {
+				t.Errorf("exifOrientation = %d, want 1", got)
+			}
+		})
+	}
+}
+
+func TestExifOrientationTruncated(t *testing.T) {
+	data := withSegments(append(soi, 0xFF, 0xD9), exifSegment(tiffWithOrientation(binary.BigEndian, 8)))
+
+	// Every prefix must be read without panicking, and only a complete EXIF block may count
+	complete := 2 + len(exifSegment(tiffWithOrientation(binary.BigEndian, 8)))
+	for n := 0; n < len(data); n++ {
+		got := exifOrientation(data[:n])
+		if n < complete && got != 1 {
+			t.Errorf("truncated to %d bytes: exifOrientation = %d, want 1", n, got)
+		}
+	}
+}
+
+// testImage is 3x2 with a different colour in each pixel:
+//
+//	A B C
+//	D E F
+func testImage() *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, 3, 2))
+	for i := 0; i < 6; i++ {
+		img.SetNRGBA(i%3, i/3, color.NRGBA{R: uint8('A' + i), A: 0xFF})
+	}
+	return img
+}
+
+func TestOrient(t *testing.T) {
+	tests := []struct {
+		orientation int
+		want        []string // rows of the upright photo
+	}{
+		{1, []string{"ABC", "DEF"}},
+		{2, []string{"CBA", "FED"}},
+		{3, []string{"FED", "CBA"}},
+		{4, []string{"DEF", "ABC"}},
+		{5, []string{"AD", "BE", "CF"}},
+		{6, []string{"DA", "EB", "FC"}},
+		{7, []string{"FC", "EB", "DA"}},
+		{8, []string{"CF", "BE", "AD"}},
+	}
+	for _, tt := range tests {
+		p := orient(testImage(), tt.orientation)
+		if p.Width != len(tt.want[0]) || p.Height != len(tt.want) {
+			t.Errorf("orientation %d: size %dx%d, want %dx%d", tt.orientation, p.Width, p.Height, len(tt.want[0]), len(tt.want))
+			continue
+		}
+		for y, row := range tt.want {
+			got := make([]byte, p.Width)
+			for x := range got {
+				got[x] = p.pix[(y*p.Width+x)*3]
+			}
+			if string(got) != row {
+				t.Errorf("orientation %d: row %d = %q, want %q", tt.orientation, y, got, row)
+			}
+		}
+	}
+}
+
+func TestDecodeAppliesOrientation(t *testing.T) {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, image.NewGray(image.Rect(0, 0, 300, 200)), nil); err != nil {
+		t.Fatalf("encoding test JPEG: %v", err)
+	}
+
+	for orientation := 1; orientation <= 8; orientation++ {
+		data := withSegments(buf.Bytes(), exifSegment(tiffWithOrientation(binary.BigEndian, uint16(orientation))))
+		photo, err := Decode(data)
+		if err != nil {
+			t.Fatalf("orientation %d: Decode: %v", orientation, err)
+		}
+		wantWidth, wantHeight := 300, 200
+		if orientation >= 5 {
+			wantWidth, wantHeight = 200, 300
+		}
+		if photo.Width != wantWidth || photo.Height != wantHeight {
+			t.Errorf("orientation %d: decoded %dx%d, want %dx%d", orientation, photo.Width, photo.Height, wantWidth, wantHeight)
+		}
+	}
+}
@@ -0,0 +1,271 @@
+// Package imaging validates uploaded photos and produces resized JPEG renditions of them in
+// pure Go. Renditions are re-encoded from the decoded pixels, so EXIF and any other metadata in
+// the upload (camera details, GPS location...) never reaches them.
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	_ "image/png" // PNG uploads are accepted alongside JPEG
+)
+
+// Limits on uploaded photos
+const (
+	MinSide   = 200              // shortest side in pixels, after applying the EXIF orientation
+	MaxSide   = 8000             // longest side in pixels
+	MaxPixels = 25 * 1000 * 1000 // bounds the memory needed to decode a photo
+	MaxAspect = 3.0              // longest side over shortest side
+)
+
+// jpegQuality is the quality renditions are encoded at
+const jpegQuality = 85
+
+var (
+	ErrUnsupportedFormat = errors.New("photo must be a JPEG or PNG image")
+	ErrTooSmall          = fmt.Errorf("photo must be at least %dx%d pixels", MinSide, MinSide)
+	ErrTooLarge          = fmt.Errorf("photo must be at most %d pixels on its longest side and %d megapixels", MaxSide, MaxPixels/1000000)
+	ErrBadAspect         = fmt.Errorf("photo can't be more than %g times as long as it is wide", MaxAspect)
+)
+
+// Photo is a decoded upload, turned the right way up
+type Photo struct {
+	Width, Height int
+	pix           []uint8 // RGB, 3 bytes per pixel, row by row
+}
+
+// Decode validates an uploaded JPEG or PNG and decodes it. The size is checked from the header
+// before any pixels are decoded. JPEGs are rotated or flipped as their EXIF orientation says, and
+// transparent areas of PNGs become white.
+func Decode(data []byte) (*Photo, error) {
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedFormat
+	}
+	if format != "jpeg" && format != "png" {
+		return nil, ErrUnsupportedFormat
+	}
+	if err := checkSize(config.Width, config.Height); err != nil {
+		return nil, err
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("photo could not be read: %w", err)
+	}
+	orientation := 1
+	if format == "jpeg" {
+		orientation = exifOrientation(data)
+	}
+	return orient(img, orientation), nil
+}
+
+func checkSize(width, height int) error {
+	short, long := width, height
+	if short > long {
+		short, long = long, short
+	}
+	switch {
+	case short < MinSide:
+		return ErrTooSmall
+	case long > MaxSide || width*height > MaxPixels:
+		return ErrTooLarge
+	case float64(long) > MaxAspect*float64(short):
+		return ErrBadAspect
+	}
+	return nil
+}
+
+// Square crops the middle of the photo to a square and scales it down to size pixels a side.
+// Photos smaller than size are cropped but not enlarged.
+func (p *Photo) Square(size int) *Photo {
+	side := p.Width
+	if p.Height < side {
+		side = p.Height
+	}
+	crop := image.Rect((p.Width-side)/2, (p.Height-side)/2, (p.Width-side)/2+side, (p.Height-side)/2+side)
+	if side < size {
+		size = side
+	}
+	return p.resample(crop, size, size)
+}
+
+// Fit scales the photo down so its longest side is at most size pixels, keeping its shape
+func (p *Photo) Fit(size int) *Photo {
+	width, height := p.Width, p.Height
+	if width > size || height > size {
+		if width >= height {
+			width, height = size, max(1, (p.Height*size+p.Width/2)/p.Width)
+		} else {
+			width, height = max(1, (p.Width*size+p.Height/2)/p.Height), size
+		}
+	}
+	return p.resample(image.Rect(0, 0, p.Width, p.Height), width, height)
+}
+
+// JPEG encodes the photo. The output carries no metadata.
+func (p *Photo) JPEG() ([]byte, error) {
+	img := image.NewRGBA(image.Rect(0, 0, p.Width, p.Height))
+	for i, j := 0, 0; i < len(p.pix); i, j = i+3, j+4 {
+		img.Pix[j], img.Pix[j+1], img.Pix[j+2], img.Pix[j+3] = p.pix[i], p.pix[i+1], p.pix[i+2], 0xFF
+	}
+	var out bytes.Buffer
+	if err := jpeg.Encode(&out, img, &jpeg.Options{Quality: jpegQuality}); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// orient copies img into a Photo, applying an EXIF orientation (1-8) and flattening any
+// transparency onto white
+func orient(img image.Image, orientation int) *Photo {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	p := &Photo{Width: w, Height: h}
+	if orientation >= 5 {
+		p.Width, p.Height = h, w
+	}
+	p.pix = make([]uint8, p.Width*p.Height*3)
+
+	// source maps a pixel of the oriented photo back to the decoded image
+	source := func(x, y int) (int, int) {
+		switch orientation {
+		case 2: // mirrored
+			return w - 1 - x, y
+		case 3: // upside down
+			return w - 1 - x, h - 1 - y
+		case 4: // upside down and mirrored
+			return x, h - 1 - y
+		case 5: // on its side and mirrored
+			return y, x
+		case 6: // needs turning clockwise
+			return y, h - 1 - x
+		case 7: // needs turning clockwise and mirrored
+			return w - 1 - y, h - 1 - x
+		case 8: // needs turning anticlockwise
+			return w - 1 - y, x
+		default:
+			return x, y
+		}
+	}
+
+	i := 0
+	for y := 0; y < p.Height; y++ {
+		for x := 0; x < p.Width; x++ {
+			sx, sy := source(x, y)
+			r, g, b := rgbAt(img, bounds.Min.X+sx, bounds.Min.Y+sy)
+			p.pix[i], p.pix[i+1], p.pix[i+2] = r, g, b
+			i += 3
+		}
+	}
+	return p
+}
+
+// rgbAt reads one pixel, with fast paths for the image types JPEG and PNG decode to
+func rgbAt(img image.Image, x, y int) (uint8, uint8, uint8) {
+	switch img := img.(type) {
+	case *image.YCbCr:
+		yi, ci := img.YOffset(x, y), img.COffset(x, y)
+		return color.YCbCrToRGB(img.Y[yi], img.Cb[ci], img.Cr[ci])
+	case *image.Gray:
+		v := img.Pix[img.PixOffset(x, y)]
+		return v, v, v
+	case *image.NRGBA:
+		i := img.PixOffset(x, y)
+		return overWhite(img.Pix[i], img.Pix[i+3]), overWhite(img.Pix[i+1], img.Pix[i+3]), overWhite(img.Pix[i+2], img.Pix[i+3])
+	}
+	// Premultiplied 16-bit colour: add white scaled by the transparency
+	r, g, b, a := img.At(x, y).RGBA()
+	white := 0xFFFF - a
+	return uint8((r + white) >> 8), uint8((g + white) >> 8), uint8((b + white) >> 8)
+}
+
+// overWhite composites a non-premultiplied channel value onto white
+func overWhite(value, alpha uint8) uint8 {
+	return uint8((int(value)*int(alpha) + 0xFF*(0xFF-int(alpha)) + 0x7F) / 0xFF)
+}
+
+// resample scales the crop rectangle of the photo to width x height by averaging the area of the
+// source each output pixel covers, first across and then down
+func (p *Photo) resample(crop image.Rectangle, width, height int) *Photo {
+	columns := coverage(crop.Min.X, crop.Dx(), width)
+	rows := coverage(crop.Min.Y, crop.Dy(), height)
+
+	// Horizontal pass over just the rows of the crop
+	across := make([]float32, width*crop.Dy()*3)
+	for y := 0; y < crop.Dy(); y++ {
+		row := p.pix[(crop.Min.Y+y)*p.Width*3:]
+		out := across[y*width*3:]
+		for x, c := range columns {
+			var r, g, b float32
+			for k, weight := range c.weights {
+				i := (c.first + k) * 3
+				r += weight * float32(row[i])
+				g += weight * float32(row[i+1])
+				b += weight * float32(row[i+2])
+			}
+			out[x*3], out[x*3+1], out[x*3+2] = r, g, b
+		}
+	}
+
+	scaled := &Photo{Width: width, Height: height, pix: make([]uint8, width*height*3)}
+	for y, c := range rows {
+		out := scaled.pix[y*width*3:]
+		for x := 0; x < width*3; x++ {
+			var v float32
+			for k, weight := range c.weights {
+				v += weight * across[(c.first-crop.Min.Y+k)*width*3+x]
+			}
+			out[x] = clamp(v)
+		}
+	}
+	return scaled
+}
+
+// span is the run of source pixels one output pixel averages, with each pixel's share
+type span struct {
+	first   int
+	weights []float32
+}
+
+// coverage works out, for each of n output pixels, which of the length source pixels starting at
+// start it covers and by how much
+func coverage(start, length, n int) []span {
+	scale := float64(length) / float64(n)
+	spans := make([]span, n)
+	for i := range spans {
+		from, to := float64(i)*scale, float64(i+1)*scale
+		first, last := int(from), int(to)
+		if float64(last) == to || last >= length {
+			last--
+		}
+		if last < first {
+			last = first
+		}
+		weights := make([]float32, last-first+1)
+		total := 0.0
+		for j := first; j <= last; j++ {
+			overlap := max(0, min(to, float64(j+1))-max(from, float64(j)))
+			weights[j-first] = float32(overlap)
+			total += overlap
+		}
+		for k := range weights {
+			weights[k] /= float32(total)
+		}
+		spans[i] = span{first: start + first, weights: weights}
+	}
+	return spans
+}
+
+func clamp(v float32) uint8 {
+	switch {
+	case v <= 0:
+		return 0
+	case v >= 255:
+		return 255
+	}
+	return uint8(v + 0.5)
+}
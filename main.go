@@ -2,16 +2,37 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"log"
 	"net/http"
+	"os"
 
 	"cricketApp/db"
 	"cricketApp/handlers"
+	"cricketApp/middleware/ratelimit"
+	"cricketApp/models"
+	"cricketApp/notification"
+	"cricketApp/payments"
+	"cricketApp/pii"
 	"cricketApp/router"
 	"cricketApp/scheduler"
+	"cricketApp/storage"
+	"cricketApp/virusscan"
 )
 
 func main() {
+	initPIIKeys := flag.Bool("init-pii-keys", false, "create the PII encryption keys for a new installation and exit")
+	flag.Parse()
+
+	if *initPIIKeys {
+		if _, err := pii.InitKeyProviderFromEnv(); err != nil {
+			log.Fatalf("Creating PII keys failed: %v", err)
+		}
+		log.Println("PII keys created. Back them up: data encrypted with them can't be read without them.")
+		return
+	}
+
 	// Initialize MongoDB client and collections/indexes
 	client, err := db.Init()
 	if err != nil {
@@ -23,21 +44,132 @@ func main() {
 		}
 	}()
 
+	// Sensitive registration fields are encrypted with keys from the configured provider
+	piiKeys, err := pii.NewKeyProviderFromEnv()
+	if errors.Is(err, pii.ErrNoKeyfile) {
+		log.Fatalf("PII key provider initialization failed: %v. Restore the keyfile, or run with -init-pii-keys on a new installation.", err)
+	}
+	if err != nil {
+		log.Fatalf("PII key provider initialization failed: %v", err)
+	}
+
 	// Create database instance using the initialized client
 	dbName := "cricketApp"
-	database := db.NewMongoDB(client, dbName)
+	database := db.NewMongoDB(client, dbName, pii.NewCipher(piiKeys))
+
+	// Encrypt registrations stored in plaintext and re-wrap any left on an old key
+	if updated, err := database.MigrateRegistrationPII(context.Background()); err != nil {
+		log.Fatalf("Registration PII migration failed: %v", err)
+	} else if updated > 0 {
+		log.Printf("Encrypted or re-wrapped personal information on %d registrations", updated)
+	}
+
+	if updated, err := database.RewrapMedicalProfiles(context.Background()); err != nil {
+		log.Fatalf("Medical profile key re-wrap failed: %v", err)
+	} else if updated > 0 {
+		log.Printf("Re-wrapped keys of %d medical profiles", updated)
+	}
+
+	// Registration form numbers must be unique before the index enforcing it can be created
+	duplicates, err := database.MigrateRegistrationFormNumbers(context.Background())
+	if err != nil {
+		log.Printf("Registration form number migration failed: %v", err)
+	}
+	for _, duplicate := range duplicates {
+		log.Printf("Duplicate registration form number %q used by %d registrations: %v",
+			duplicate.FormNo, duplicate.Count, duplicate.RegistrationIDs)
+	}
+	if len(duplicates) > 0 {
+		log.Println("Unique form number index not created; resolve the duplicates above (see GET /api/registrations/duplicates)")
+	}
+
+	// Coaches can start assessing with the built-in rubrics
+	if created, err := database.EnsureDefaultAssessmentTemplates(context.Background()); err != nil {
+		log.Printf("Error creating default assessment templates: %v", err)
+	} else if created > 0 {
+		log.Printf("Created %d default assessment templates", created)
+	}
+
+	// Start the fitness catalogue with the tests the academy runs each quarter
+	if created, err := database.EnsureDefaultFitnessTests(context.Background()); err != nil {
+		log.Printf("Error creating default fitness tests: %v", err)
+	} else if created > 0 {
+		log.Printf("Created %d default fitness tests", created)
+	}
+
+	// Junior bowling limits apply until an admin sets the academy's own
+	if created, err := database.EnsureDefaultWorkloadLimits(context.Background()); err != nil {
+		log.Printf("Error creating default workload limits: %v", err)
+	} else if created > 0 {
+		log.Printf("Created default workload limits for %d age categories", created)
+	}
+
+	// Make sure someone can reveal personal information and manage its keys
+	defaultAdminEmail := os.Getenv("DEFAULT_ADMIN_EMAIL")
+	if defaultAdminEmail == "" {
+		defaultAdminEmail = "admin@example.com"
+	}
+	for _, permission := range []string{models.PermissionRevealPII, models.PermissionManagePIIKeys} {
+		granted, err := database.BootstrapAdminPermission(context.Background(), defaultAdminEmail, permission)
+		if err != nil {
+			log.Printf("Error granting %s to the default admin: %v", permission, err)
+		} else if granted {
+			log.Printf("Granted %s to %s", permission, defaultAdminEmail)
+		}
+	}
+
+	// Create blob store for uploaded files
+	blobStore, err := storage.NewBlobStoreFromEnv()
+	if err != nil {
+		log.Fatalf("Blob store initialization failed: %v", err)
+	}
+
+	// Uploads are scanned before they are stored
+	scanner, err := virusscan.NewScannerFromEnv()
+	if err != nil {
+		log.Fatalf("Virus scanner initialization failed: %v", err)
+	}
+
+	// Guardians pay fees through the configured payment gateway
+	gateway, err := payments.NewGatewayFromEnv()
+	if err != nil {
+		log.Fatalf("Payment gateway initialization failed: %v", err)
+	}
+
+	// Client IPs are only taken from forwarding headers set by our own reverse proxies
+	proxies, err := ratelimit.TrustedProxiesFromEnv()
+	if err != nil {
+		log.Fatalf("Trusted proxies configuration failed: %v", err)
+	}
+
+	// Notifications go out through the configured delivery provider
+	notifier, err := notification.NewNotifierFromEnv()
+	if err != nil {
+		log.Fatalf("Notifier initialization failed: %v", err)
+	}
+
+	// Challenges, codes and signed links are keyed with secrets that must be configured
+	secrets, err := handlers.SecretsFromEnv()
+	if err != nil {
+		log.Fatalf("Secrets configuration failed: %v", err)
+	}
 
 	// Create handlers
-	cricketerHandler := handlers.NewCricketerHandler(database)
+	cricketerHandler := handlers.NewCricketerHandler(database, notifier, secrets.PhotoURL)
 
 	// Setup router with handlers and database instance
-	r := router.SetupRouter(database, cricketerHandler)
+	r := router.SetupRouter(database, cricketerHandler, blobStore, scanner, piiKeys, gateway, notifier, proxies, secrets)
 
 	// Start the reminder scheduler
 	reminderScheduler := scheduler.NewReminderScheduler(database)
 	go reminderScheduler.Start()
 	log.Println("Reminder scheduler started")
 
+	// Start the equipment scheduler
+	equipmentScheduler := scheduler.NewEquipmentScheduler(database, notifier)
+	go equipmentScheduler.Start()
+	log.Println("Equipment scheduler started")
+
 	// Start server
 	log.Println("Server starting on :8080")
 	if err := http.ListenAndServe(":8080", r); err != nil {
@@ -0,0 +1,208 @@
+// Package markdown renders a small, safe subset of Markdown to HTML.
+//
+// Raw HTML in the source is always escaped and link targets are restricted to
+// http, https, mailto and relative URLs, so the output can be embedded in a page
+// without further sanitization. Supported syntax: headings (#), paragraphs,
+// unordered (-, *, +) and ordered (1.) lists, blockquotes (>), fenced code
+// blocks (```), inline code, **bold**, *italic* / _italic_ and [links](url).
+package markdown
+
+import (
+	"html"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+var (
+	headingPattern     = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	unorderedPattern   = regexp.MustCompile(`^\s*[-*+]\s+(.*)$`)
+	orderedPattern     = regexp.MustCompile(`^\s*\d+[.)]\s+(.*)$`)
+	blockquotePattern  = regexp.MustCompile(`^\s*>\s?(.*)$`)
+	fencePattern       = regexp.MustCompile("^\\s*```")
+	escapablePunctChar = "\\`*_[]()#+-.!>"
+)
+
+// ToSafeHTML converts Markdown source into sanitized HTML
+func ToSafeHTML(src string) string {
+	lines := strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n")
+
+	var b strings.Builder
+	var paragraph []string
+	var listTag string
+
+	flushParagraph := func() {
+		if len(paragraph) > 0 {
+			b.WriteString("<p>" + renderInline(strings.Join(paragraph, "\n")) + "</p>\n")
+			paragraph = nil
+		}
+	}
+	closeList := func() {
+		if listTag != "" {
+			b.WriteString("</" + listTag + ">\n")
+			listTag = ""
+		}
+	}
+	openList := func(tag string) {
+		if listTag != tag {
+			closeList()
+			b.WriteString("<" + tag + ">\n")
+			listTag = tag
+		}
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+
+		switch {
+		case fencePattern.MatchString(line):
+			flushParagraph()
+			closeList()
+			var code []string
+			for i++; i < len(lines) && !fencePattern.MatchString(lines[i]); i++ {
+				code = append(code, lines[i])
+			}
+			b.WriteString("<pre><code>" + html.EscapeString(strings.Join(code, "\n")) + "</code></pre>\n")
+
+		case strings.TrimSpace(line) == "":
+			flushParagraph()
+			closeList()
+
+		case headingPattern.MatchString(line):
+			flushParagraph()
+			closeList()
+			m := headingPattern.FindStringSubmatch(line)
+			level := string(rune('0' + len(m[1])))
+			b.WriteString("<h" + level + ">" + renderInline(m[2]) + "</h" + level + ">\n")
+
+		case unorderedPattern.MatchString(line):
+			flushParagraph()
+			openList("ul")
+			b.WriteString("<li>" + renderInline(unorderedPattern.FindStringSubmatch(line)[1]) + "</li>\n")
+
+		case orderedPattern.MatchString(line):
+			flushParagraph()
+			openList("ol")
+			b.WriteString("<li>" + renderInline(orderedPattern.FindStringSubmatch(line)[1]) + "</li>\n")
+
+		case blockquotePattern.MatchString(line):
+			flushParagraph()
+			closeList()
+			var quote []string
+			for ; i < len(lines) && blockquotePattern.MatchString(lines[i]); i++ {
+				quote = append(quote, blockquotePattern.FindStringSubmatch(lines[i])[1])
+			}
+			i--
+			b.WriteString("<blockquote><p>" + renderInline(strings.Join(quote, "\n")) + "</p></blockquote>\n")
+
+		default:
+			closeList()
+			paragraph = append(paragraph, strings.TrimSpace(line))
+		}
+	}
+	flushParagraph()
+	closeList()
+
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// renderInline renders inline Markdown, escaping everything that isn't recognised syntax
+func renderInline(s string) string {
+	var b strings.Builder
+
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == '\\' && i+1 < len(s) && strings.IndexByte(escapablePunctChar, s[i+1]) >= 0:
+			b.WriteString(html.EscapeString(s[i+1 : i+2]))
+			i += 2
+			continue
+
+		case c == '`':
+			if end := strings.IndexByte(s[i+1:], '`'); end >= 0 {
+				b.WriteString("<code>" + html.EscapeString(s[i+1:i+1+end]) + "</code>")
+				i += end + 2
+				continue
+			}
+
+		case c == '[':
+			if text, target, n, ok := parseLink(s[i:]); ok {
+				if safe, ok := safeURL(target); ok {
+					b.WriteString(`<a href="` + html.EscapeString(safe) + `" rel="nofollow noopener noreferrer">` + renderInline(text) + "</a>")
+				} else {
+					b.WriteString(renderInline(text))
+				}
+				i += n
+				continue
+			}
+
+		case strings.HasPrefix(s[i:], "**") || strings.HasPrefix(s[i:], "__"):
+			delim := s[i : i+2]
+			if end := strings.Index(s[i+2:], delim); end > 0 {
+				b.WriteString("<strong>" + renderInline(s[i+2:i+2+end]) + "</strong>")
+				i += end + 4
+				continue
+			}
+
+		case (c == '*' || (c == '_' && (i == 0 || !isWordChar(s[i-1])))) && i+1 < len(s) && s[i+1] != ' ':
+			if end := strings.IndexByte(s[i+1:], c); end > 0 && s[i+end] != ' ' {
+				b.WriteString("<em>" + renderInline(s[i+1:i+1+end]) + "</em>")
+				i += end + 2
+				continue
+			}
+
+		case c == '\n':
+			b.WriteString("<br>\n")
+			i++
+			continue
+		}
+
+		b.WriteString(html.EscapeString(s[i : i+1]))
+		i++
+	}
+
+	return b.String()
+}
+
+// parseLink parses "[text](target)" at the start of s, returning the number of bytes consumed
+func parseLink(s string) (text string, target string, n int, ok bool) {
+	closeText := strings.Index(s, "](")
+	if closeText < 0 {
+		return "", "", 0, false
+	}
+	closeTarget := strings.IndexByte(s[closeText+2:], ')')
+	if closeTarget < 0 {
+		return "", "", 0, false
+	}
+	text = s[1:closeText]
+	target = strings.TrimSpace(s[closeText+2 : closeText+2+closeTarget])
+	return text, target, closeText + 2 + closeTarget + 1, true
+}
+
+// safeURL allows only http, https, mailto and relative link targets
+func safeURL(raw string) (string, bool) {
+	for _, r := range raw {
+		if r < 0x20 || r == 0x7f {
+			return "", false
+		}
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", false
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "http", "https", "mailto":
+		return u.String(), true
+	case "":
+		if strings.HasPrefix(raw, "//") {
+			return "", false
+		}
+		return u.String(), true
+	default:
+		return "", false
+	}
+}
+
+func isWordChar(c byte) bool {
+	return c == '_' || (c >= '0' && c <= '9') || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
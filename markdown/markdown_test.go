@@ -0,0 +1,105 @@
+package markdown
+
+import "testing"
+
+const rel = ` rel="nofollow noopener noreferrer"`
+
+func TestToSafeHTMLLinks(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"https", "[site](https://example.com/a?b=1&c=2)", `<p><a href="https://example.com/a?b=1&amp;c=2"` + rel + `>site</a></p>`},
+		{"http", "[site](http://example.com)", `<p><a href="http://example.com"` + rel + `>site</a></p>`},
+		{"mailto", "[mail](mailto:coach@example.com)", `<p><a href="mailto:coach@example.com"` + rel + `>mail</a></p>`},
+		{"relative", "[fees](/fees?month=5&year=2026)", `<p><a href="/fees?month=5&amp;year=2026"` + rel + `>fees</a></p>`},
+		{"target is trimmed", "[site](  https://example.com  )", `<p><a href="https://example.com"` + rel + `>site</a></p>`},
+		{"quote can't end the attribute", `[x](https://example.com/"onmouseover=alert(1))`, `<p><a href="https://example.com/%22onmouseover=alert%281"` + rel + `>x</a>)</p>`},
+		{"space is encoded", "[x](https://example.com/a b)", `<p><a href="https://example.com/a%20b"` + rel + `>x</a></p>`},
+		{"text is escaped", "[<b>hi</b>](https://example.com)", `<p><a href="https://example.com"` + rel + `>&lt;b&gt;hi&lt;/b&gt;</a></p>`},
+		{"text keeps inline markup", "[**hi**](https://example.com)", `<p><a href="https://example.com"` + rel + `><strong>hi</strong></a></p>`},
+
+		// Unsafe targets keep the link text and drop the link
+		{"javascript", "[x](javascript:alert)", "<p>x</p>"},
+		{"javascript in mixed case", "[x](JaVaScRiPt:alert)", "<p>x</p>"},
+		{"javascript after spaces", "[x](   javascript:alert)", "<p>x</p>"},
+		{"javascript with parentheses", "[x](javascript:alert(1))", "<p>x)</p>"},
+		{"vbscript", "[x](vbscript:msgbox)", "<p>x</p>"},
+		{"data", "[x](data:text/html;base64,PHNjcmlwdD4=)", "<p>x</p>"},
+		{"file", "[x](file:///etc/passwd)", "<p>x</p>"},
+		{"tab inside the scheme", "[x](java\tscript:alert)", "<p>x</p>"},
+		{"newline inside the scheme", "[x](java\nscript:alert)", "<p>x</p>"},
+		{"leading control character", "[x](\x01javascript:alert)", "<p>x</p>"},
+		{"delete character", "[x](https://example.com/\x7f)", "<p>x</p>"},
+		{"protocol-relative", "[x](//evil.example/a)", "<p>x</p>"},
+		{"protocol-relative after spaces", "[x]( //evil.example)", "<p>x</p>"},
+
+		// Backslashes are percent-encoded so browsers can't read them as //
+		{"backslash host", `[x](\\evil.example)`, `<p><a href="%5C%5Cevil.example"` + rel + `>x</a></p>`},
+		{"slash backslash host", `[x](/\evil.example)`, `<p><a href="/%5Cevil.example"` + rel + `>x</a></p>`},
+		{"encoded colon stays relative", "[x](javascript&#58;alert)", `<p><a href="javascript&amp;#58;alert"` + rel + `>x</a></p>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ToSafeHTML(tt.src); got != tt.want {
+				t.Errorf("ToSafeHTML(%q)\n got %s\nwant %s", tt.src, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToSafeHTMLEscapesHTML(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"script", "<script>alert(1)</script>", "<p>&lt;script&gt;alert(1)&lt;/script&gt;</p>"},
+		{"event handler", `<img src=x onerror="alert(1)">`, "<p>&lt;img src=x onerror=&#34;alert(1)&#34;&gt;</p>"},
+		{"entities are shown as written", "AT&T &amp; &lt;b&gt; &#60;", "<p>AT&amp;T &amp;amp; &amp;lt;b&amp;gt; &amp;#60;</p>"},
+		{"quotes", `"Coach's" notes`, "<p>&#34;Coach&#39;s&#34; notes</p>"},
+		{"heading", "# <i>Nets</i>", "<h1>&lt;i&gt;Nets&lt;/i&gt;</h1>"},
+		{"list item", "- <b>bat</b>", "<ul>\n<li>&lt;b&gt;bat&lt;/b&gt;</li>\n</ul>"},
+		{"blockquote", "> <u>quote</u>", "<blockquote><p>&lt;u&gt;quote&lt;/u&gt;</p></blockquote>"},
+		{"inside emphasis", "**<i>bold</i>**", "<p><strong>&lt;i&gt;bold&lt;/i&gt;</strong></p>"},
+		{"inline code", "`<b>&amp;</b>`", "<p><code>&lt;b&gt;&amp;amp;&lt;/b&gt;</code></p>"},
+		{"code block", "```\n<script>&</script>\n```", "<pre><code>&lt;script&gt;&amp;&lt;/script&gt;</code></pre>"},
+		{"escaped markup", `\*not italic\* \<b>`, `<p>*not italic* \&lt;b&gt;</p>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ToSafeHTML(tt.src); got != tt.want {
+				t.Errorf("ToSafeHTML(%q)\n got %s\nwant %s", tt.src, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToSafeHTMLBlocks(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"paragraphs", "Nets at 6.\nBring pads.\n\nNo spikes.", "<p>Nets at 6.<br>\nBring pads.</p>\n<p>No spikes.</p>"},
+		{"CRLF line endings", "a\r\nb", "<p>a<br>\nb</p>"},
+		{"headings", "## Fixtures ##\n###### Small", "<h2>Fixtures</h2>\n<h6>Small</h6>"},
+		{"seven hashes is a paragraph", "####### x", "<p>####### x</p>"},
+		{"unordered list", "- bat\n* pads\n+ gloves", "<ul>\n<li>bat</li>\n<li>pads</li>\n<li>gloves</li>\n</ul>"},
+		{"ordered list", "1. warm up\n2) nets", "<ol>\n<li>warm up</li>\n<li>nets</li>\n</ol>"},
+		{"list type change", "- a\n1. b", "<ul>\n<li>a</li>\n</ul>\n<ol>\n<li>b</li>\n</ol>"},
+		{"blockquote", "> one\n> two", "<blockquote><p>one<br>\ntwo</p></blockquote>"},
+		{"unclosed fence", "```\ncode", "<pre><code>code</code></pre>"},
+		{"emphasis", "*a* _b_ **c** __d__ snake_case_name", "<p><em>a</em> <em>b</em> <strong>c</strong> <strong>d</strong> snake_case_name</p>"},
+		{"unmatched markers", "2 * 3 and [not a link]", "<p>2 * 3 and [not a link]</p>"},
+		{"empty", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ToSafeHTML(tt.src); got != tt.want {
+				t.Errorf("ToSafeHTML(%q)\n got %q\nwant %q", tt.src, got, tt.want)
+			}
+		})
+	}
+}
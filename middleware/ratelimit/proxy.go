@@ -0,0 +1,95 @@
+package ratelimit
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// TrustedProxies are the networks of reverse proxies whose X-Forwarded-For and X-Real-IP headers
+// are believed. Anyone else can put whatever they like in those headers.
+type TrustedProxies []*net.IPNet
+
+// ParseTrustedProxies parses a comma-separated list of IP addresses and CIDR ranges
+func ParseTrustedProxies(list string) (TrustedProxies, error) {
+	var proxies TrustedProxies
+	for _, entry := range strings.Split(list, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if !strings.Contains(entry, "/") {
+			ip := net.ParseIP(entry)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q", entry)
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			proxies = append(proxies, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, network, err := net.ParseCIDR(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", entry, err)
+		}
+		proxies = append(proxies, network)
+	}
+	return proxies, nil
+}
+
+// TrustedProxiesFromEnv reads the trusted proxies from TRUSTED_PROXIES. When it is unset no
+// forwarding headers are believed and clients are identified by their connection address.
+func TrustedProxiesFromEnv() (TrustedProxies, error) {
+	return ParseTrustedProxies(os.Getenv("TRUSTED_PROXIES"))
+}
+
+func (t TrustedProxies) trusts(ip net.IP) bool {
+	for _, network := range t {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// RealIP replaces RemoteAddr with the client address from the forwarding headers, but only for
+// requests that come through a trusted proxy. The client is the last address in X-Forwarded-For
+// that isn't one of our proxies, since earlier entries are whatever the client sent.
+func (t TrustedProxies) RealIP(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if peer := net.ParseIP(ClientIP(r)); peer != nil && t.trusts(peer) {
+			if ip := t.forwardedFor(r); ip != "" {
+				r.RemoteAddr = ip
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// forwardedFor finds the client address in the forwarding headers of a request from a trusted proxy
+func (t TrustedProxies) forwardedFor(r *http.Request) string {
+	if header := r.Header.Values("X-Forwarded-For"); len(header) > 0 {
+		hops := strings.Split(strings.Join(header, ","), ",")
+		client := ""
+		for i := len(hops) - 1; i >= 0; i-- {
+			ip := net.ParseIP(strings.TrimSpace(hops[i]))
+			if ip == nil {
+				break
+			}
+			client = ip.String()
+			if !t.trusts(ip) {
+				break
+			}
+		}
+		return client
+	}
+
+	if ip := net.ParseIP(strings.TrimSpace(r.Header.Get("X-Real-IP"))); ip != nil {
+		return ip.String()
+	}
+	return ""
+}
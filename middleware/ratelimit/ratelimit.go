@@ -0,0 +1,100 @@
+package ratelimit
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// bucket is a token bucket for a single client
+type bucket struct {
+	tokens   float64
+	lastSeen time.Time
+}
+
+// Limiter is an in-memory token bucket rate limiter keyed by client IP
+type Limiter struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	rate    float64 // tokens added per second
+	burst   float64
+	// lastSweep is when idle buckets were last removed
+	lastSweep time.Time
+}
+
+// NewLimiter allows each IP up to requests per window, with bursts of up to burst requests
+func NewLimiter(requests int, window time.Duration, burst int) *Limiter {
+	return &Limiter{
+		buckets:   make(map[string]*bucket),
+		rate:      float64(requests) / window.Seconds(),
+		burst:     float64(burst),
+		lastSweep: time.Now(),
+	}
+}
+
+// Allow reports whether a request from key may proceed, and if not how long to wait
+func (l *Limiter) Allow(key string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.sweep(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: l.burst, lastSeen: now}
+		l.buckets[key] = b
+	}
+
+	// Refill based on time since the last request
+	b.tokens = math.Min(l.burst, b.tokens+now.Sub(b.lastSeen).Seconds()*l.rate)
+	b.lastSeen = now
+
+	if b.tokens < 1 {
+		wait := time.Duration((1 - b.tokens) / l.rate * float64(time.Second))
+		return false, wait
+	}
+	b.tokens--
+	return true, 0
+}
+
+// sweep drops buckets that have been idle long enough to be full again
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < time.Minute {
+		return
+	}
+	l.lastSweep = now
+	idle := time.Duration(l.burst / l.rate * float64(time.Second))
+	for key, b := range l.buckets {
+		if now.Sub(b.lastSeen) > idle {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// Middleware rejects requests with 429 Too Many Requests once the client IP exceeds the limit.
+// It should run after TrustedProxies.RealIP so RemoteAddr holds the client address. Don't use chi's
+// RealIP, which believes forwarding headers from anyone and lets clients pick their own key.
+func (l *Limiter) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		allowed, wait := l.Allow(ClientIP(r))
+		if !allowed {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			http.Error(w, "Too many requests, please try again later", http.StatusTooManyRequests)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// ClientIP returns the client IP address of a request without the port
+func ClientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
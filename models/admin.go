@@ -1,8 +1,33 @@
 package models
 
 type Admin struct {
-	ID       string `json:"id" bson:"_id,omitempty"`
-	Name     string `json:"name" bson:"name"`
-	Email    string `json:"email" bson:"email"`
-	Password string `json:"-" bson:"password"`
+	ID          string   `json:"id" bson:"_id,omitempty"`
+	Name        string   `json:"name" bson:"name"`
+	Email       string   `json:"email" bson:"email"`
+	Password    string   `json:"-" bson:"password"`
+	Permissions []string `json:"permissions,omitempty" bson:"permissions,omitempty"`
+}
+
+// Admin permissions beyond the admin role
+const (
+	// PermissionRevealPII allows viewing unmasked Aadhaar numbers, addresses and contact numbers
+	PermissionRevealPII = "pii:reveal"
+
+	// PermissionManagePIIKeys allows rotating the keys that encrypt personal information
+	PermissionManagePIIKeys = "pii:manageKeys"
+)
+
+// HasPermission reports whether the admin has been granted permission
+func (a *Admin) HasPermission(permission string) bool {
+	for _, p := range a.Permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// UpdateAdminPermissionsRequest represents the request body for changing an admin's permissions
+type UpdateAdminPermissionsRequest struct {
+	Permissions []string `json:"permissions"`
 }
@@ -2,12 +2,89 @@ package models
 
 import (
 	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type Announcement struct {
-	ID        string    `json:"id" bson:"_id,omitempty"`
-	Title     string    `json:"title" bson:"title"`
-	Content   string    `json:"content" bson:"content"`
-	CreatedBy string    `json:"createdBy" bson:"createdBy"`
-	CreatedAt time.Time `json:"createdAt" bson:"createdAt"`
+	ID                      string                 `json:"id" bson:"_id,omitempty"`
+	Title                   string                 `json:"title" bson:"title" binding:"required,max=200"`
+	Content                 string                 `json:"content" bson:"content"`         // Markdown source
+	ContentHTML             string                 `json:"contentHtml" bson:"contentHtml"` // Sanitized HTML rendered from Content
+	RequiresAcknowledgement bool                   `json:"requiresAcknowledgement" bson:"requiresAcknowledgement"`
+	AttachmentIDs           []primitive.ObjectID   `json:"attachmentIds,omitempty" bson:"attachmentIds,omitempty"`
+	BatchIDs                []primitive.ObjectID   `json:"batchIds,omitempty" bson:"batchIds,omitempty"` // Empty means academy-wide
+	CreatedBy               string                 `json:"createdBy" bson:"createdBy"`
+	CreatedByRole           string                 `json:"createdByRole" bson:"createdByRole"` // admin, coach
+	CreatedAt               time.Time              `json:"createdAt" bson:"createdAt"`
+	UpdatedAt               *time.Time             `json:"updatedAt,omitempty" bson:"updatedAt,omitempty"`
+	DeletedAt               *time.Time             `json:"deletedAt,omitempty" bson:"deletedAt,omitempty"`
+	DeletedBy               string                 `json:"deletedBy,omitempty" bson:"deletedBy,omitempty"`
+	History                 []AnnouncementRevision `json:"history,omitempty" bson:"history,omitempty"`
+}
+
+// AnnouncementRevision is a previous version of an announcement, kept when it is edited
+type AnnouncementRevision struct {
+	Title    string    `json:"title" bson:"title"`
+	Content  string    `json:"content" bson:"content"`
+	EditedBy string    `json:"editedBy" bson:"editedBy"`
+	EditedAt time.Time `json:"editedAt" bson:"editedAt"`
+}
+
+// UpdateAnnouncementRequest represents the request body for editing an announcement
+type UpdateAnnouncementRequest struct {
+	Title                   *string               `json:"title,omitempty"`
+	Content                 *string               `json:"content,omitempty"`
+	RequiresAcknowledgement *bool                 `json:"requiresAcknowledgement,omitempty"`
+	AttachmentIDs           *[]primitive.ObjectID `json:"attachmentIds,omitempty"`
+	BatchIDs                *[]primitive.ObjectID `json:"batchIds,omitempty"`
+}
+
+// AnnouncementFilter controls which announcements are listed and how they are paginated
+type AnnouncementFilter struct {
+	// BatchIDs restricts the result to academy-wide announcements and those targeting these batches
+	BatchIDs []primitive.ObjectID
+	// AllBatches disables audience filtering (admins)
+	AllBatches bool
+	// Before and BeforeID are the pagination cursor: only announcements after (Before, BeforeID)
+	// in newest-first order are returned. Without BeforeID, those created strictly before Before.
+	Before   *time.Time
+	BeforeID *primitive.ObjectID
+	Limit    int
+}
+
+// AnnouncementReceipt records that a cricketer has read (and optionally acknowledged) an announcement
+type AnnouncementReceipt struct {
+	ID             primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	AnnouncementID primitive.ObjectID `json:"announcementId" bson:"announcementId"`
+	CricketerID    primitive.ObjectID `json:"cricketerId" bson:"cricketerId"`
+	ReadAt         time.Time          `json:"readAt" bson:"readAt"`
+	AcknowledgedAt *time.Time         `json:"acknowledgedAt,omitempty" bson:"acknowledgedAt,omitempty"`
+	AcknowledgedBy string             `json:"acknowledgedBy,omitempty" bson:"acknowledgedBy,omitempty"` // guardian ID when a guardian acknowledged for the cricketer
+}
+
+// CricketerAnnouncement is an announcement as seen by a single cricketer, including their receipt state
+type CricketerAnnouncement struct {
+	Announcement
+	ReadAt         *time.Time `json:"readAt,omitempty"`
+	AcknowledgedAt *time.Time `json:"acknowledgedAt,omitempty"`
+	AcknowledgedBy string     `json:"acknowledgedBy,omitempty"`
+}
+
+// AnnouncementRecipientStatus is a cricketer's read/acknowledgement state for an announcement
+type AnnouncementRecipientStatus struct {
+	CricketerID    primitive.ObjectID `json:"cricketerId"`
+	Name           string             `json:"name"`
+	Mobile         string             `json:"mobile"`
+	ReadAt         *time.Time         `json:"readAt,omitempty"`
+	AcknowledgedAt *time.Time         `json:"acknowledgedAt,omitempty"`
+}
+
+// AnnouncementReceiptReport lists who has and hasn't read/acknowledged an announcement
+type AnnouncementReceiptReport struct {
+	Announcement    Announcement                  `json:"announcement"`
+	Read            []AnnouncementRecipientStatus `json:"read"`
+	Unread          []AnnouncementRecipientStatus `json:"unread"`
+	Acknowledged    []AnnouncementRecipientStatus `json:"acknowledged"`
+	NotAcknowledged []AnnouncementRecipientStatus `json:"notAcknowledged"`
 }
@@ -0,0 +1,229 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Assessment categories
+const (
+	AssessmentBattingTechnique = "batting_technique"
+	AssessmentBowlingAction    = "bowling_action"
+	AssessmentFielding         = "fielding"
+	AssessmentFitness          = "fitness"
+	AssessmentGameAwareness    = "game_awareness"
+)
+
+// RubricLevel is one point on an assessment template's scale
+type RubricLevel struct {
+	Score      int    `json:"score" bson:"score" binding:"min=0,max=100"`
+	Label      string `json:"label" bson:"label" binding:"required,max=50"` // e.g. Developing
+	Descriptor string `json:"descriptor,omitempty" bson:"descriptor,omitempty" binding:"omitempty,max=500"`
+}
+
+// AssessmentCriterion is one skill assessed by a template
+type AssessmentCriterion struct {
+	Key         string `json:"key" bson:"key" binding:"required,max=50"` // stable identifier used to compare assessments
+	Name        string `json:"name" bson:"name" binding:"required,max=100"`
+	Description string `json:"description,omitempty" bson:"description,omitempty" binding:"omitempty,max=500"`
+}
+
+// AssessmentTemplate defines the criteria coaches score and the rubric they score them on.
+// Criteria and scale can't change once a template exists, so assessments stay comparable;
+// create a new template instead.
+type AssessmentTemplate struct {
+	ID          primitive.ObjectID    `json:"id" bson:"_id,omitempty"`
+	Name        string                `json:"name" bson:"name"`
+	Category    string                `json:"category" bson:"category"`
+	Description string                `json:"description,omitempty" bson:"description,omitempty"`
+	Criteria    []AssessmentCriterion `json:"criteria" bson:"criteria"`
+	Scale       []RubricLevel         `json:"scale" bson:"scale"` // lowest score first
+	Active      bool                  `json:"active" bson:"active"`
+	CreatedBy   string                `json:"createdBy,omitempty" bson:"createdBy,omitempty"`
+	CreatedAt   time.Time             `json:"createdAt" bson:"createdAt"`
+	UpdatedAt   time.Time             `json:"updatedAt" bson:"updatedAt"`
+}
+
+// ScaleRange returns the lowest and highest scores on the template's scale
+func (t *AssessmentTemplate) ScaleRange() (int, int) {
+	if len(t.Scale) == 0 {
+		return 0, 0
+	}
+	return t.Scale[0].Score, t.Scale[len(t.Scale)-1].Score
+}
+
+// Level returns the scale level with the given score
+func (t *AssessmentTemplate) Level(score int) (RubricLevel, bool) {
+	for _, level := range t.Scale {
+		if level.Score == score {
+			return level, true
+		}
+	}
+	return RubricLevel{}, false
+}
+
+// CreateAssessmentTemplateRequest represents the request body for creating an assessment template
+type CreateAssessmentTemplateRequest struct {
+	Name        string                `json:"name" binding:"required,max=100"`
+	Category    string                `json:"category" binding:"required,oneof=batting_technique bowling_action fielding fitness game_awareness"`
+	Description string                `json:"description" binding:"omitempty,max=1000"`
+	Criteria    []AssessmentCriterion `json:"criteria" binding:"required,max=20"`
+	Scale       []RubricLevel         `json:"scale" binding:"required,min=2,max=10"`
+}
+
+// UpdateAssessmentTemplateRequest represents the request body for renaming or retiring a template
+type UpdateAssessmentTemplateRequest struct {
+	Name        *string `json:"name,omitempty" binding:"omitempty,max=100"`
+	Description *string `json:"description,omitempty" binding:"omitempty,max=1000"`
+	Active      *bool   `json:"active,omitempty"`
+}
+
+// AssessmentScore is a criterion's score on an assessment
+type AssessmentScore struct {
+	CriterionKey string `json:"criterionKey" bson:"criterionKey" binding:"required"`
+	Score        int    `json:"score" bson:"score"`
+	Comment      string `json:"comment,omitempty" bson:"comment,omitempty" binding:"omitempty,max=500"`
+}
+
+// Assessment is a coach's scoring of a cricketer against a template on a given day
+type Assessment struct {
+	ID             primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	CricketerID    primitive.ObjectID `json:"cricketerId" bson:"cricketerId"`
+	TemplateID     primitive.ObjectID `json:"templateId" bson:"templateId"`
+	TemplateName   string             `json:"templateName" bson:"templateName"`
+	Category       string             `json:"category" bson:"category"`
+	AssessedOn     time.Time          `json:"assessedOn" bson:"assessedOn"`
+	Scores         []AssessmentScore  `json:"scores" bson:"scores"`
+	Average        float64            `json:"average" bson:"average"` // mean criterion score
+	Percent        float64            `json:"percent" bson:"percent"` // average as a percentage of the scale, for comparing templates
+	Strengths      string             `json:"strengths,omitempty" bson:"strengths,omitempty"`
+	AreasToImprove string             `json:"areasToImprove,omitempty" bson:"areasToImprove,omitempty"`
+	Comments       string             `json:"comments,omitempty" bson:"comments,omitempty"`
+	AssessedBy     string             `json:"assessedBy" bson:"assessedBy"`
+	AssessedByRole string             `json:"assessedByRole" bson:"assessedByRole"`
+	AssessorName   string             `json:"assessorName" bson:"assessorName"`
+	CreatedAt      time.Time          `json:"createdAt" bson:"createdAt"`
+	UpdatedAt      time.Time          `json:"updatedAt" bson:"updatedAt"`
+}
+
+// Score returns the score recorded for a criterion
+func (a *Assessment) Score(criterionKey string) (int, bool) {
+	for _, score := range a.Scores {
+		if score.CriterionKey == criterionKey {
+			return score.Score, true
+		}
+	}
+	return 0, false
+}
+
+// AssessmentRequest represents the request body for recording or correcting an assessment.
+// Every criterion on the template must be scored.
+type AssessmentRequest struct {
+	CricketerID    string            `json:"cricketerId" binding:"required,objectid"`
+	TemplateID     string            `json:"templateId" binding:"required,objectid"`
+	AssessedOn     time.Time         `json:"assessedOn" binding:"required,past"`
+	Scores         []AssessmentScore `json:"scores" binding:"required"`
+	Strengths      string            `json:"strengths" binding:"omitempty,max=1000"`
+	AreasToImprove string            `json:"areasToImprove" binding:"omitempty,max=1000"`
+	Comments       string            `json:"comments" binding:"omitempty,max=2000"`
+}
+
+// AssessmentFilter selects assessments; empty fields match everything
+type AssessmentFilter struct {
+	CricketerID *primitive.ObjectID
+	TemplateID  *primitive.ObjectID
+	From        *time.Time
+	To          *time.Time
+}
+
+// CriterionChange compares a criterion's score between assessments
+type CriterionChange struct {
+	Key      string `json:"key"`
+	Name     string `json:"name"`
+	First    *int   `json:"first"`    // earliest assessment in the period
+	Previous *int   `json:"previous"` // the assessment before the latest
+	Latest   *int   `json:"latest"`
+	Change   int    `json:"change"`       // latest - first
+	Recent   int    `json:"recentChange"` // latest - previous
+}
+
+// AssessmentProgress is a cricketer's assessments against one template over time
+type AssessmentProgress struct {
+	Template    AssessmentTemplate `json:"template"`
+	Assessments []Assessment       `json:"assessments"` // oldest first
+	Changes     []CriterionChange  `json:"changes"`
+}
+
+// TermReportSection summarises one template's assessments in a term report
+type TermReportSection struct {
+	TemplateID   primitive.ObjectID `json:"templateId"`
+	TemplateName string             `json:"templateName"`
+	Category     string             `json:"category"`
+	Scale        []RubricLevel      `json:"scale"`
+	Assessments  int                `json:"assessments"`
+	Latest       Assessment         `json:"latest"`
+	Changes      []CriterionChange  `json:"changes"`
+}
+
+// TermReport summarises a cricketer's assessments and attendance over a term
+type TermReport struct {
+	CricketerID   primitive.ObjectID  `json:"cricketerId"`
+	CricketerName string              `json:"cricketerName"`
+	AgeCategory   string              `json:"ageCategory,omitempty"`
+	BatchName     string              `json:"batchName,omitempty"`
+	Term          string              `json:"term"`
+	From          time.Time           `json:"from"`
+	To            time.Time           `json:"to"`
+	Attendance    AttendanceSummary   `json:"attendance"`
+	Sections      []TermReportSection `json:"sections"`
+	GeneratedAt   time.Time           `json:"generatedAt"`
+}
+
+// defaultAssessmentScale is the five-point rubric used by the built-in templates
+var defaultAssessmentScale = []RubricLevel{
+	{Score: 1, Label: "Beginning", Descriptor: "Needs constant guidance; the skill breaks down often"},
+	{Score: 2, Label: "Developing", Descriptor: "Performs the skill in drills with reminders"},
+	{Score: 3, Label: "Competent", Descriptor: "Performs the skill reliably in practice"},
+	{Score: 4, Label: "Proficient", Descriptor: "Performs the skill under match pressure"},
+	{Score: 5, Label: "Advanced", Descriptor: "Performs the skill consistently at a level above the age group"},
+}
+
+// DefaultAssessmentTemplates are created when the academy has no templates yet
+func DefaultAssessmentTemplates() []AssessmentTemplate {
+	criteria := func(pairs ...string) []AssessmentCriterion {
+		list := make([]AssessmentCriterion, 0, len(pairs)/2)
+		for i := 0; i+1 < len(pairs); i += 2 {
+			list = append(list, AssessmentCriterion{Key: pairs[i], Name: pairs[i+1]})
+		}
+		return list
+	}
+	templates := []AssessmentTemplate{
+		{Name: "Batting technique", Category: AssessmentBattingTechnique, Criteria: criteria(
+			"grip_stance", "Grip and stance", "backlift", "Backlift", "front_foot", "Front-foot play",
+			"back_foot", "Back-foot play", "shot_selection", "Shot selection")},
+		{Name: "Bowling action", Category: AssessmentBowlingAction, Criteria: criteria(
+			"run_up", "Run-up and rhythm", "gather", "Load and gather", "front_arm", "Front arm",
+			"release", "Release", "follow_through", "Follow-through", "line_length", "Line and length")},
+		{Name: "Fielding", Category: AssessmentFielding, Criteria: criteria(
+			"ground_fielding", "Ground fielding", "catching", "Catching", "throwing", "Throwing accuracy",
+			"anticipation", "Anticipation and movement")},
+		{Name: "Fitness", Category: AssessmentFitness, Criteria: criteria(
+			"speed", "Speed", "agility", "Agility", "endurance", "Endurance", "strength", "Strength",
+			"flexibility", "Flexibility")},
+		{Name: "Game awareness", Category: AssessmentGameAwareness, Criteria: criteria(
+			"match_situation", "Reading the match situation", "running", "Running between wickets",
+			"field_settings", "Understanding field settings", "communication", "Communication",
+			"decision_making", "Decision making")},
+	}
+	for i := range templates {
+		templates[i].Scale = DefaultAssessmentScale()
+		templates[i].Active = true
+	}
+	return templates
+}
+
+// DefaultAssessmentScale returns a copy of the built-in five-point rubric
+func DefaultAssessmentScale() []RubricLevel {
+	return append([]RubricLevel(nil), defaultAssessmentScale...)
+}
@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"cricketApp/virusscan"
+)
+
+// Attachment describes a file stored in the blob store
+type Attachment struct {
+	ID          primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	Key         string             `json:"-" bson:"key"`
+	FileName    string             `json:"fileName" bson:"fileName"`
+	ContentType string             `json:"contentType" bson:"contentType"`
+	Size        int64              `json:"size" bson:"size"`
+	Scan        *virusscan.Result  `json:"scan,omitempty" bson:"scan,omitempty"`
+	UploadedBy  string             `json:"uploadedBy" bson:"uploadedBy"`
+	CreatedAt   time.Time          `json:"createdAt" bson:"createdAt"`
+}
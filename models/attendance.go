@@ -0,0 +1,62 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Attendance statuses
+const (
+	AttendancePresent = "present"
+	AttendanceLate    = "late"
+	AttendanceAbsent  = "absent"
+	AttendanceExcused = "excused"
+)
+
+// Attendance records whether a cricketer attended a session. There is one record per cricketer per session.
+type Attendance struct {
+	ID           primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	SessionID    primitive.ObjectID `json:"sessionId" bson:"sessionId"`
+	CricketerID  primitive.ObjectID `json:"cricketerId" bson:"cricketerId"`
+	SessionDate  time.Time          `json:"sessionDate" bson:"sessionDate"`
+	Status       string             `json:"status" bson:"status"`
+	Note         string             `json:"note,omitempty" bson:"note,omitempty"`
+	MarkedBy     string             `json:"markedBy" bson:"markedBy"`
+	MarkedByRole string             `json:"markedByRole" bson:"markedByRole"` // admin, coach
+	MarkedAt     time.Time          `json:"markedAt" bson:"markedAt"`
+}
+
+// AttendanceMark is one cricketer's attendance in a MarkAttendanceRequest
+type AttendanceMark struct {
+	CricketerID string `json:"cricketerId" binding:"required,objectid"`
+	Status      string `json:"status" binding:"required,oneof=present late absent excused"`
+	Note        string `json:"note" binding:"omitempty,max=500"`
+}
+
+// MarkAttendanceRequest represents the request body for recording attendance for a session
+type MarkAttendanceRequest struct {
+	Records []AttendanceMark `json:"records" binding:"required"`
+}
+
+// AttendanceSummary counts a cricketer's attendance records by status
+type AttendanceSummary struct {
+	Present int `json:"present"`
+	Late    int `json:"late"`
+	Absent  int `json:"absent"`
+	Excused int `json:"excused"`
+}
+
+// Add counts one attendance record
+func (s *AttendanceSummary) Add(status string) {
+	switch status {
+	case AttendancePresent:
+		s.Present++
+	case AttendanceLate:
+		s.Late++
+	case AttendanceAbsent:
+		s.Absent++
+	case AttendanceExcused:
+		s.Excused++
+	}
+}
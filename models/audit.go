@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AuditEntry records a sensitive action for later review
+type AuditEntry struct {
+	ID         primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	Action     string             `json:"action" bson:"action"`
+	ActorID    string             `json:"actorId" bson:"actorId"`
+	ActorRole  string             `json:"actorRole" bson:"actorRole"`
+	TargetType string             `json:"targetType" bson:"targetType"`
+	TargetID   string             `json:"targetId" bson:"targetId"`
+	Reason     string             `json:"reason,omitempty" bson:"reason,omitempty"`
+	Details    map[string]string  `json:"details,omitempty" bson:"details,omitempty"`
+	IP         string             `json:"ip" bson:"ip"`
+	CreatedAt  time.Time          `json:"createdAt" bson:"createdAt"`
+}
+
+// Audit actions
+const (
+	AuditRevealPII        = "pii.reveal"
+	AuditGrantPermissions = "admin.permissions"
+	AuditRotatePIIKey     = "pii.rotateKey"
+	AuditExportData       = "data.export"
+	AuditViewMedical      = "medical.view"
+	AuditUpdateMedical    = "medical.update"
+	AuditPublishConsent   = "consent.publish"
+)
+
+// AuditFilter selects audit entries; empty fields match everything
+type AuditFilter struct {
+	Action   string
+	ActorID  string
+	TargetID string
+	Limit    int
+}
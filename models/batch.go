@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Batch is a group of cricketers trained together by one or more coaches
+type Batch struct {
+	ID          primitive.ObjectID   `json:"id" bson:"_id,omitempty"`
+	Name        string               `json:"name" bson:"name" binding:"required"`
+	Description string               `json:"description" bson:"description"`
+	CoachIDs    []primitive.ObjectID `json:"coachIds" bson:"coachIds"`
+	AgeCategory string               `json:"ageCategory,omitempty" bson:"ageCategory,omitempty"` // e.g. U-14; empty for batches open to all ages
+	MonthlyFee  int64                `json:"monthlyFee" bson:"monthlyFee"`                       // in paise; 0 when no fee is collected online
+	CreatedAt   time.Time            `json:"createdAt" bson:"createdAt"`
+	UpdatedAt   time.Time            `json:"updatedAt" bson:"updatedAt"`
+}
+
+// CreateBatchRequest represents the request body for creating a new batch
+type CreateBatchRequest struct {
+	Name        string   `json:"name" binding:"required"`
+	Description string   `json:"description"`
+	CoachIDs    []string `json:"coachIds"`
+	AgeCategory string   `json:"ageCategory"`
+	MonthlyFee  int64    `json:"monthlyFee" binding:"omitempty,min=0"`
+}
+
+// UpdateBatchRequest represents the request body for updating a batch
+type UpdateBatchRequest struct {
+	Name        *string   `json:"name,omitempty" binding:"omitempty,min=1"`
+	Description *string   `json:"description,omitempty"`
+	CoachIDs    *[]string `json:"coachIds,omitempty"`
+	AgeCategory *string   `json:"ageCategory,omitempty"`
+	MonthlyFee  *int64    `json:"monthlyFee,omitempty" binding:"omitempty,min=0"`
+}
@@ -7,15 +7,16 @@ import (
 )
 
 type Coach struct {
-	ID        primitive.ObjectID `json:"id" bson:"_id,omitempty"`
-	Name      string             `json:"name" bson:"name" binding:"required"`
-	Mobile    string             `json:"mobile" bson:"mobile" binding:"required"`
-	Password  string             `json:"password" bson:"password" binding:"required,min=6"`
-	CreatedAt time.Time          `json:"createdAt" bson:"createdAt"`
-	IsActive  bool               `json:"isActive" bson:"isActive"`
+	ID        primitive.ObjectID  `json:"id" bson:"_id,omitempty"`
+	Name      string              `json:"name" bson:"name" binding:"required,max=100"`
+	Mobile    string              `json:"mobile" bson:"mobile" binding:"required,mobile"`
+	Password  string              `json:"password" bson:"password" binding:"required,min=6"`
+	CreatedAt time.Time           `json:"createdAt" bson:"createdAt"`
+	IsActive  bool                `json:"isActive" bson:"isActive"`
+	PhotoID   *primitive.ObjectID `json:"photoId,omitempty" bson:"photoId,omitempty"` // the active ProfilePhoto
 }
 
 type UpdateCoachRequest struct {
-	Name     string `json:"name,omitempty"`
+	Name     string `json:"name,omitempty" binding:"omitempty,max=100"`
 	IsActive *bool  `json:"isActive,omitempty"`
 }
@@ -0,0 +1,78 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ConsentDocument is one version of a consent form or waiver. Publishing new text under the same key
+// creates a new version, and signatures of earlier versions no longer count.
+type ConsentDocument struct {
+	ID          primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	Key         string             `json:"key" bson:"key"` // e.g. participation-waiver
+	Version     int                `json:"version" bson:"version"`
+	Title       string             `json:"title" bson:"title"`
+	Body        string             `json:"body" bson:"body"` // markdown
+	Hash        string             `json:"hash" bson:"hash"` // SHA-256 of the title and body, hex encoded
+	Required    bool               `json:"required" bson:"required"`
+	Current     bool               `json:"current" bson:"current"` // the latest version of its key
+	PublishedBy string             `json:"publishedBy" bson:"publishedBy"`
+	PublishedAt time.Time          `json:"publishedAt" bson:"publishedAt"`
+}
+
+// PublishConsentDocumentRequest represents the request body for publishing a consent document version
+type PublishConsentDocumentRequest struct {
+	Key      string `json:"key" binding:"required,max=50"`
+	Title    string `json:"title" binding:"required,max=200"`
+	Body     string `json:"body" binding:"required,max=20000"`
+	Required bool   `json:"required"`
+}
+
+// ConsentSignature is an electronic signature of a consent document version for a cricketer
+type ConsentSignature struct {
+	ID           primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	DocumentID   primitive.ObjectID `json:"documentId" bson:"documentId"`
+	DocumentKey  string             `json:"documentKey" bson:"documentKey"`
+	Version      int                `json:"version" bson:"version"`
+	DocumentHash string             `json:"documentHash" bson:"documentHash"`
+	CricketerID  primitive.ObjectID `json:"cricketerId" bson:"cricketerId"`
+	SignedBy     string             `json:"signedBy" bson:"signedBy"`
+	SignerRole   string             `json:"signerRole" bson:"signerRole"` // guardian, cricketer
+	TypedName    string             `json:"typedName" bson:"typedName"`
+	IP           string             `json:"ip" bson:"ip"`
+	UserAgent    string             `json:"userAgent,omitempty" bson:"userAgent,omitempty"`
+	SignedAt     time.Time          `json:"signedAt" bson:"signedAt"`
+}
+
+// SignConsentRequest represents the request body for signing a consent document.
+// DocumentHash must be the hash of the version the signer was shown.
+type SignConsentRequest struct {
+	TypedName    string `json:"typedName" binding:"required,max=100"`
+	DocumentHash string `json:"documentHash" binding:"required,len=64"`
+	Agree        bool   `json:"agree"`
+}
+
+// Consent statuses of a document for a cricketer
+const (
+	ConsentSigned   = "signed"
+	ConsentOutdated = "outdated" // an earlier version was signed; the current one needs signing
+	ConsentMissing  = "missing"
+)
+
+// ConsentStatus is whether a cricketer has signed the current version of a consent document
+type ConsentStatus struct {
+	Document  ConsentDocument   `json:"document"`
+	Status    string            `json:"status"`
+	Signature *ConsentSignature `json:"signature,omitempty"` // the latest signature of any version
+}
+
+// OutstandingConsent is a cricketer missing a signature of a required consent document
+type OutstandingConsent struct {
+	CricketerID   primitive.ObjectID `json:"cricketerId"`
+	CricketerName string             `json:"cricketerName"`
+	DocumentKey   string             `json:"documentKey"`
+	Title         string             `json:"title"`
+	Version       int                `json:"version"`
+	Status        string             `json:"status"`
+}
@@ -7,13 +7,33 @@ import (
 )
 
 type Cricketer struct {
-	ID                primitive.ObjectID `json:"id" bson:"_id,omitempty"`
-	Name              string             `json:"name" bson:"name" binding:"required"`
-	Mobile            string             `json:"mobile" bson:"mobile" binding:"required"`
-	Email             string             `json:"email" bson:"email" binding:"required,email"`
-	Password          string             `json:"password" bson:"password" binding:"required,min=6"`
-	CreatedAt         time.Time          `json:"createdAt" bson:"createdAt"`
-	JoiningDate       *time.Time         `json:"joiningDate,omitempty" bson:"joiningDate,omitempty"`
-	DueDate           *time.Time         `json:"dueDate,omitempty" bson:"dueDate,omitempty"`
-	InactiveCricketer bool               `json:"inactiveCricketer" bson:"inactiveCricketer"`
+	ID                primitive.ObjectID  `json:"id" bson:"_id,omitempty"`
+	Name              string              `json:"name" bson:"name" binding:"required,max=100"`
+	Mobile            string              `json:"mobile" bson:"mobile" binding:"required,mobile"`
+	Email             string              `json:"email" bson:"email" binding:"required,email"`
+	Password          string              `json:"password" bson:"password" binding:"required,min=6"`
+	CreatedAt         time.Time           `json:"createdAt" bson:"createdAt"`
+	JoiningDate       *time.Time          `json:"joiningDate,omitempty" bson:"joiningDate,omitempty"`
+	DueDate           *time.Time          `json:"dueDate,omitempty" bson:"dueDate,omitempty"`
+	InactiveCricketer bool                `json:"inactiveCricketer" bson:"inactiveCricketer"`
+	BatchID           *primitive.ObjectID `json:"batchId,omitempty" bson:"batchId,omitempty"`
+	DateOfBirth       *time.Time          `json:"dateOfBirth,omitempty" bson:"dateOfBirth,omitempty"`
+	Gender            string              `json:"gender,omitempty" bson:"gender,omitempty"`   // male, female; used for fitness benchmarks
+	PhotoID           *primitive.ObjectID `json:"photoId,omitempty" bson:"photoId,omitempty"` // the active ProfilePhoto
+	// PasswordSetupHash lets a cricketer whose account was created for them choose their password
+	// from a one-time link, until PasswordSetupExpiresAt
+	PasswordSetupHash      string     `json:"-" bson:"passwordSetupHash,omitempty"`
+	PasswordSetupExpiresAt *time.Time `json:"-" bson:"passwordSetupExpiresAt,omitempty"`
 }
+
+// CompletePasswordSetupRequest represents the request body for choosing a password from a setup link
+type CompletePasswordSetupRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required,min=6"`
+}
+
+// Genders
+const (
+	GenderMale   = "male"
+	GenderFemale = "female"
+)
@@ -0,0 +1,83 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"cricketApp/virusscan"
+)
+
+// Registration document types
+const (
+	DocumentBirthCertificate = "birth_certificate"
+	DocumentAadhaarCopy      = "aadhaar_copy"
+	DocumentPassportPhoto    = "passport_photo"
+	DocumentSchoolID         = "school_id"
+)
+
+// Registration document review statuses
+const (
+	DocumentPendingReview = "pending_review"
+	DocumentAccepted      = "accepted"
+	DocumentNeedsReupload = "needs_reupload"
+)
+
+// DocumentType describes a document slot on a registration
+type DocumentType struct {
+	Type         string   `json:"type"`
+	Label        string   `json:"label"`
+	Required     bool     `json:"required"`
+	ContentTypes []string `json:"contentTypes"`
+	MaxSize      int64    `json:"maxSize"`
+}
+
+// RegistrationDocumentTypes lists the documents collected with a registration
+var RegistrationDocumentTypes = []DocumentType{
+	{Type: DocumentBirthCertificate, Label: "Birth certificate", Required: true, ContentTypes: []string{"application/pdf", "image/jpeg", "image/png"}, MaxSize: 5 << 20},
+	{Type: DocumentAadhaarCopy, Label: "Aadhaar card copy", Required: true, ContentTypes: []string{"application/pdf", "image/jpeg", "image/png"}, MaxSize: 5 << 20},
+	{Type: DocumentPassportPhoto, Label: "Passport size photo", Required: true, ContentTypes: []string{"image/jpeg", "image/png"}, MaxSize: 2 << 20},
+	{Type: DocumentSchoolID, Label: "School ID card", Required: false, ContentTypes: []string{"application/pdf", "image/jpeg", "image/png"}, MaxSize: 5 << 20},
+}
+
+// LookupDocumentType returns the registration document type with the given name
+func LookupDocumentType(name string) (DocumentType, bool) {
+	for _, documentType := range RegistrationDocumentTypes {
+		if documentType.Type == name {
+			return documentType, true
+		}
+	}
+	return DocumentType{}, false
+}
+
+// RegistrationDocument is the file uploaded for one document slot of a registration.
+// Uploading again replaces the file and resets the review.
+type RegistrationDocument struct {
+	ID             primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	RegistrationID primitive.ObjectID `json:"registrationId" bson:"registrationId"`
+	Type           string             `json:"type" bson:"type"`
+	Key            string             `json:"-" bson:"key"`
+	FileName       string             `json:"fileName" bson:"fileName"`
+	ContentType    string             `json:"contentType" bson:"contentType"`
+	Size           int64              `json:"size" bson:"size"`
+	Scan           virusscan.Result   `json:"scan" bson:"scan"`
+	Status         string             `json:"status" bson:"status"` // see Document* status constants
+	ReviewNote     string             `json:"reviewNote,omitempty" bson:"reviewNote,omitempty"`
+	ReviewedBy     string             `json:"reviewedBy,omitempty" bson:"reviewedBy,omitempty"`
+	ReviewedAt     *time.Time         `json:"reviewedAt,omitempty" bson:"reviewedAt,omitempty"`
+	UploadedBy     string             `json:"uploadedBy" bson:"uploadedBy"` // "applicant" or the admin's ID
+	UploadedAt     time.Time          `json:"uploadedAt" bson:"uploadedAt"`
+	ApprovedAt     *time.Time         `json:"approvedAt,omitempty" bson:"approvedAt,omitempty"` // when the registration was approved with this document
+}
+
+// RegistrationDocumentSlot pairs a document type with the document uploaded for it, if any
+type RegistrationDocumentSlot struct {
+	DocumentType
+	Document *RegistrationDocument `json:"document,omitempty"`
+}
+
+// ReviewDocumentRequest represents the request body for reviewing a registration document
+type ReviewDocumentRequest struct {
+	Status string `json:"status" binding:"required,oneof=accepted needs_reupload"`
+	Note   string `json:"note"`
+}
@@ -0,0 +1,181 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Equipment conditions, best first
+const (
+	EquipmentNew      = "new"
+	EquipmentGood     = "good"
+	EquipmentFair     = "fair"
+	EquipmentPoor     = "poor"
+	EquipmentUnusable = "unusable" // can't be issued until repaired
+)
+
+// Equipment issue statuses
+const (
+	EquipmentIssued   = "issued"
+	EquipmentReturned = "returned"
+)
+
+// EquipmentItem is a line of kit the academy keeps, e.g. size M helmets, counted together
+type EquipmentItem struct {
+	ID                primitive.ObjectID    `json:"id" bson:"_id,omitempty"`
+	Name              string                `json:"name" bson:"name"`
+	Category          string                `json:"category" bson:"category"`
+	Size              string                `json:"size,omitempty" bson:"size,omitempty"`
+	Condition         string                `json:"condition" bson:"condition"` // see Equipment* condition constants
+	Location          string                `json:"location" bson:"location"`
+	Quantity          int                   `json:"quantity" bson:"quantity"`   // owned, including what is out on issue
+	Available         int                   `json:"available" bson:"available"` // in store and free to issue
+	LowStockThreshold int                   `json:"lowStockThreshold" bson:"lowStockThreshold"`
+	LowStock          bool                  `json:"lowStock" bson:"-"`
+	Notes             string                `json:"notes,omitempty" bson:"notes,omitempty"`
+	Retired           bool                  `json:"retired" bson:"retired"`
+	Adjustments       []EquipmentAdjustment `json:"adjustments" bson:"adjustments"` // oldest first, starting with the opening stock
+	CreatedAt         time.Time             `json:"createdAt" bson:"createdAt"`
+	UpdatedAt         time.Time             `json:"updatedAt" bson:"updatedAt"`
+}
+
+// IsLowStock reports whether so few are left in store that more should be bought
+func (i *EquipmentItem) IsLowStock() bool {
+	return i.LowStockThreshold > 0 && i.Available <= i.LowStockThreshold
+}
+
+// EquipmentAdjustment records stock bought, found, written off or counted in
+type EquipmentAdjustment struct {
+	Change  int                 `json:"change" bson:"change"` // positive adds stock, negative removes it
+	Reason  string              `json:"reason" bson:"reason"`
+	By      string              `json:"by" bson:"by"`
+	At      time.Time           `json:"at" bson:"at"`
+	IssueID *primitive.ObjectID `json:"issueId,omitempty" bson:"issueId,omitempty"` // set for kit lost or damaged on issue
+}
+
+// EquipmentIssue lends some of an item to a cricketer or for a session, and records its return
+type EquipmentIssue struct {
+	ID            primitive.ObjectID  `json:"id" bson:"_id,omitempty"`
+	ItemID        primitive.ObjectID  `json:"itemId" bson:"itemId"`
+	ItemName      string              `json:"itemName" bson:"itemName"`
+	Size          string              `json:"size,omitempty" bson:"size,omitempty"`
+	Quantity      int                 `json:"quantity" bson:"quantity"`
+	CricketerID   *primitive.ObjectID `json:"cricketerId,omitempty" bson:"cricketerId,omitempty"`
+	CricketerName string              `json:"cricketerName,omitempty" bson:"cricketerName,omitempty"`
+	BatchID       *primitive.ObjectID `json:"batchId,omitempty" bson:"batchId,omitempty"` // the cricketer's or session's batch
+	SessionID     *primitive.ObjectID `json:"sessionId,omitempty" bson:"sessionId,omitempty"`
+	Note          string              `json:"note,omitempty" bson:"note,omitempty"`
+	Status        string              `json:"status" bson:"status"` // see Equipment* status constants
+	IssuedBy      string              `json:"issuedBy" bson:"issuedBy"`
+	IssuedByRole  string              `json:"issuedByRole" bson:"issuedByRole"`
+	IssuedAt      time.Time           `json:"issuedAt" bson:"issuedAt"`
+	DueAt         time.Time           `json:"dueAt" bson:"dueAt"`
+	Overdue       bool                `json:"overdue" bson:"-"`
+	RemindedAt    *time.Time          `json:"remindedAt,omitempty" bson:"remindedAt,omitempty"` // last overdue reminder
+	Return        *EquipmentReturn    `json:"return,omitempty" bson:"return,omitempty"`
+}
+
+// IsOverdue reports whether the kit is still out after it was due back
+func (i *EquipmentIssue) IsOverdue(now time.Time) bool {
+	return i.Status == EquipmentIssued && now.After(i.DueAt)
+}
+
+// EquipmentReturn records what came back from an issue
+type EquipmentReturn struct {
+	Returned   int       `json:"returned" bson:"returned"` // back in store
+	Lost       int       `json:"lost" bson:"lost"`
+	Damaged    int       `json:"damaged" bson:"damaged"` // written off
+	Note       string    `json:"note,omitempty" bson:"note,omitempty"`
+	ReceivedBy string    `json:"receivedBy" bson:"receivedBy"`
+	ReceivedAt time.Time `json:"receivedAt" bson:"receivedAt"`
+}
+
+// CreateEquipmentItemRequest represents the request body for adding a line of kit to the inventory
+type CreateEquipmentItemRequest struct {
+	EquipmentDetails
+	Quantity int `json:"quantity" binding:"min=0,max=10000"`
+}
+
+// EquipmentDetails are the parts of an item that can be edited
+type EquipmentDetails struct {
+	Name              string `json:"name" binding:"required,max=100"`
+	Category          string `json:"category" binding:"required,oneof=bat pads helmet gloves thigh_guard arm_guard abdominal_guard keeping_gloves ball stumps bowling_machine kit_bag training_aid other"`
+	Size              string `json:"size" binding:"omitempty,max=20"`
+	Condition         string `json:"condition" binding:"required,oneof=new good fair poor unusable"`
+	Location          string `json:"location" binding:"required,max=100"`
+	LowStockThreshold int    `json:"lowStockThreshold" binding:"min=0,max=10000"`
+	Notes             string `json:"notes" binding:"omitempty,max=1000"`
+}
+
+// UpdateEquipmentItemRequest represents the request body for editing an item
+type UpdateEquipmentItemRequest struct {
+	EquipmentDetails
+	Retired bool `json:"retired"`
+}
+
+// AdjustEquipmentStockRequest represents the request body for adding or removing stock outside an issue
+type AdjustEquipmentStockRequest struct {
+	Change int    `json:"change" binding:"required,min=-10000,max=10000"`
+	Reason string `json:"reason" binding:"required,max=200"`
+}
+
+// IssueEquipmentRequest represents the request body for lending kit to a cricketer or for a session
+type IssueEquipmentRequest struct {
+	ItemID      string     `json:"itemId" binding:"required,objectid"`
+	Quantity    int        `json:"quantity" binding:"required,min=1,max=100"`
+	CricketerID string     `json:"cricketerId" binding:"omitempty,objectid"`
+	SessionID   string     `json:"sessionId" binding:"omitempty,objectid"`
+	DueAt       *time.Time `json:"dueAt"` // defaults to the end of the session, or a week for cricketers
+	Note        string     `json:"note" binding:"omitempty,max=500"`
+}
+
+// ReturnEquipmentRequest represents the request body for taking kit back; whatever isn't lost or damaged goes back in store
+type ReturnEquipmentRequest struct {
+	Lost    int    `json:"lost" binding:"min=0,max=100"`
+	Damaged int    `json:"damaged" binding:"min=0,max=100"`
+	Note    string `json:"note" binding:"omitempty,max=500"`
+}
+
+// EquipmentFilter selects inventory items; empty fields match everything
+type EquipmentFilter struct {
+	Category       string
+	Location       string
+	IncludeRetired bool
+}
+
+// EquipmentIssueFilter selects equipment issues; empty fields match everything
+type EquipmentIssueFilter struct {
+	ItemID          *primitive.ObjectID
+	CricketerID     *primitive.ObjectID
+	SessionID       *primitive.ObjectID
+	BatchIDs        []primitive.ObjectID // nil matches every batch
+	OutstandingOnly bool                 // only kit not yet returned
+	DueBefore       *time.Time
+}
+
+// EquipmentIssueSummary counts what a group of issues lent and what came back
+type EquipmentIssueSummary struct {
+	TimesIssued int `json:"timesIssued"`
+	UnitsIssued int `json:"unitsIssued"`
+	Outstanding int `json:"outstanding"` // units still out
+	Overdue     int `json:"overdue"`     // units out past their due date
+	Lost        int `json:"lost"`
+	Damaged     int `json:"damaged"`
+}
+
+// EquipmentItemHistory is an item's issues and stock adjustments
+type EquipmentItemHistory struct {
+	Item    EquipmentItem         `json:"item"`
+	Summary EquipmentIssueSummary `json:"summary"`
+	Issues  []EquipmentIssue      `json:"issues"` // newest first
+}
+
+// CricketerEquipmentHistory is the kit a cricketer has borrowed
+type CricketerEquipmentHistory struct {
+	CricketerID   primitive.ObjectID    `json:"cricketerId"`
+	CricketerName string                `json:"cricketerName"`
+	Summary       EquipmentIssueSummary `json:"summary"`
+	Holding       []EquipmentIssue      `json:"holding"` // not yet returned, due soonest first
+	Issues        []EquipmentIssue      `json:"issues"`  // newest first
+}
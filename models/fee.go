@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"cricketApp/payments"
+)
+
+// FeePayment is a monthly fee payment for a cricketer. Paying moves the cricketer's due date
+// to the end of the period paid for.
+type FeePayment struct {
+	ID          primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	CricketerID primitive.ObjectID `json:"cricketerId" bson:"cricketerId"`
+	BatchID     primitive.ObjectID `json:"batchId" bson:"batchId"`
+	Months      int                `json:"months" bson:"months"`
+	Amount      int64              `json:"amount" bson:"amount"` // in paise
+	Currency    string             `json:"currency" bson:"currency"`
+	PeriodStart time.Time          `json:"periodStart" bson:"periodStart"`
+	PeriodEnd   time.Time          `json:"periodEnd" bson:"periodEnd"` // the new due date
+	PaidBy      string             `json:"paidBy" bson:"paidBy"`
+	PaidByRole  string             `json:"paidByRole" bson:"paidByRole"` // guardian, cricketer, admin
+	Receipt     payments.Receipt   `json:"receipt" bson:"receipt"`
+	// IdempotencyKey is the payer's key for the attempt, scoped to the payer
+	IdempotencyKey string    `json:"-" bson:"idempotencyKey"`
+	CreatedAt      time.Time `json:"createdAt" bson:"createdAt"`
+}
+
+// PayFeeRequest represents the request body for paying a cricketer's fees
+type PayFeeRequest struct {
+	Months         int    `json:"months" binding:"omitempty,min=1,max=12"` // defaults to 1
+	PaymentToken   string `json:"paymentToken" binding:"required"`
+	IdempotencyKey string `json:"idempotencyKey" binding:"required,max=100"` // generated by the client once per payment attempt
+}
@@ -0,0 +1,253 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Fitness ratings against a test's benchmark
+const (
+	FitnessBelowAverage = "below_average"
+	FitnessAverage      = "average"
+	FitnessGood         = "good"
+	FitnessExcellent    = "excellent"
+)
+
+// FitnessBenchmark is the result needed for each rating in an age category.
+// An empty gender applies to everyone without a more specific benchmark.
+type FitnessBenchmark struct {
+	AgeCategory string  `json:"ageCategory" bson:"ageCategory" binding:"required"` // U-12 ... U-19 or Open
+	Gender      string  `json:"gender,omitempty" bson:"gender,omitempty" binding:"omitempty,oneof=male female"`
+	Average     float64 `json:"average" bson:"average"`
+	Good        float64 `json:"good" bson:"good"`
+	Excellent   float64 `json:"excellent" bson:"excellent"`
+}
+
+// FitnessTest is a test in the fitness catalogue
+type FitnessTest struct {
+	ID             primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	Key            string             `json:"key" bson:"key"` // e.g. yo_yo
+	Name           string             `json:"name" bson:"name"`
+	Unit           string             `json:"unit" bson:"unit"` // e.g. seconds, cm, level
+	HigherIsBetter bool               `json:"higherIsBetter" bson:"higherIsBetter"`
+	Description    string             `json:"description,omitempty" bson:"description,omitempty"`
+	DropThreshold  float64            `json:"dropThreshold" bson:"dropThreshold"` // % worse than the previous result that raises an alert
+	Benchmarks     []FitnessBenchmark `json:"benchmarks" bson:"benchmarks"`
+	Active         bool               `json:"active" bson:"active"`
+	CreatedAt      time.Time          `json:"createdAt" bson:"createdAt"`
+	UpdatedAt      time.Time          `json:"updatedAt" bson:"updatedAt"`
+}
+
+// Better reports whether result a is better than result b
+func (t *FitnessTest) Better(a float64, b float64) bool {
+	if t.HigherIsBetter {
+		return a > b
+	}
+	return a < b
+}
+
+// Benchmark returns the benchmark for an age category, preferring one specific to the gender
+func (t *FitnessTest) Benchmark(ageCategory string, gender string) *FitnessBenchmark {
+	var fallback *FitnessBenchmark
+	for i := range t.Benchmarks {
+		benchmark := &t.Benchmarks[i]
+		if benchmark.AgeCategory != ageCategory {
+			continue
+		}
+		if gender != "" && benchmark.Gender == gender {
+			return benchmark
+		}
+		if benchmark.Gender == "" {
+			fallback = benchmark
+		}
+	}
+	return fallback
+}
+
+// Rate returns the rating for a result, or "" when there is no benchmark for the age category
+func (t *FitnessTest) Rate(value float64, ageCategory string, gender string) string {
+	benchmark := t.Benchmark(ageCategory, gender)
+	if benchmark == nil {
+		return ""
+	}
+	reaches := func(threshold float64) bool { return value == threshold || t.Better(value, threshold) }
+	switch {
+	case reaches(benchmark.Excellent):
+		return FitnessExcellent
+	case reaches(benchmark.Good):
+		return FitnessGood
+	case reaches(benchmark.Average):
+		return FitnessAverage
+	}
+	return FitnessBelowAverage
+}
+
+// DropPercent returns how much worse current is than previous, as a percentage of previous.
+// It is zero or negative when current is the same or better.
+func (t *FitnessTest) DropPercent(previous float64, current float64) float64 {
+	if previous == 0 {
+		return 0
+	}
+	if t.HigherIsBetter {
+		return (previous - current) / previous * 100
+	}
+	return (current - previous) / previous * 100
+}
+
+// CreateFitnessTestRequest represents the request body for adding a test to the catalogue
+type CreateFitnessTestRequest struct {
+	Key            string             `json:"key" binding:"required,max=50"`
+	Name           string             `json:"name" binding:"required,max=100"`
+	Unit           string             `json:"unit" binding:"required,max=20"`
+	HigherIsBetter bool               `json:"higherIsBetter"`
+	Description    string             `json:"description" binding:"omitempty,max=1000"`
+	DropThreshold  float64            `json:"dropThreshold" binding:"min=0,max=100"`
+	Benchmarks     []FitnessBenchmark `json:"benchmarks" binding:"max=50"`
+}
+
+// UpdateFitnessTestRequest represents the request body for updating a test. The key, unit and
+// direction can't change, so results stay comparable.
+type UpdateFitnessTestRequest struct {
+	Name          *string             `json:"name,omitempty" binding:"omitempty,max=100"`
+	Description   *string             `json:"description,omitempty" binding:"omitempty,max=1000"`
+	DropThreshold *float64            `json:"dropThreshold,omitempty" binding:"omitempty,min=0,max=100"`
+	Benchmarks    *[]FitnessBenchmark `json:"benchmarks,omitempty" binding:"omitempty,max=50"`
+	Active        *bool               `json:"active,omitempty"`
+}
+
+// FitnessResult is a cricketer's result in a fitness test
+type FitnessResult struct {
+	ID             primitive.ObjectID  `json:"id" bson:"_id,omitempty"`
+	CricketerID    primitive.ObjectID  `json:"cricketerId" bson:"cricketerId"`
+	CricketerName  string              `json:"cricketerName" bson:"cricketerName"`
+	BatchID        *primitive.ObjectID `json:"batchId,omitempty" bson:"batchId,omitempty"`
+	TestID         primitive.ObjectID  `json:"testId" bson:"testId"`
+	TestName       string              `json:"testName" bson:"testName"`
+	Unit           string              `json:"unit" bson:"unit"`
+	TestedOn       time.Time           `json:"testedOn" bson:"testedOn"`
+	Value          float64             `json:"value" bson:"value"`
+	AgeCategory    string              `json:"ageCategory,omitempty" bson:"ageCategory,omitempty"` // in the season of the test
+	Gender         string              `json:"gender,omitempty" bson:"gender,omitempty"`
+	Rating         string              `json:"rating,omitempty" bson:"rating,omitempty"`
+	Note           string              `json:"note,omitempty" bson:"note,omitempty"`
+	RecordedBy     string              `json:"recordedBy" bson:"recordedBy"`
+	RecordedByRole string              `json:"recordedByRole" bson:"recordedByRole"`
+	CreatedAt      time.Time           `json:"createdAt" bson:"createdAt"`
+}
+
+// FitnessResultEntry is one cricketer's result in a FitnessBatchResultsRequest
+type FitnessResultEntry struct {
+	CricketerID string  `json:"cricketerId" binding:"required,objectid"`
+	Value       float64 `json:"value" binding:"min=0"`
+	Note        string  `json:"note" binding:"omitempty,max=500"`
+}
+
+// FitnessBatchResultsRequest represents the request body for entering a batch's results in a test.
+// Cricketers who missed the test are left out.
+type FitnessBatchResultsRequest struct {
+	TestID   string               `json:"testId" binding:"required,objectid"`
+	TestedOn time.Time            `json:"testedOn" binding:"required,past"`
+	Results  []FitnessResultEntry `json:"results" binding:"required,max=100"`
+}
+
+// FitnessResultFilter selects fitness results; empty fields match everything
+type FitnessResultFilter struct {
+	CricketerID *primitive.ObjectID
+	TestID      *primitive.ObjectID
+}
+
+// FitnessRanking places a result among the latest results of cricketers in the same age category
+type FitnessRanking struct {
+	AgeCategory string  `json:"ageCategory"`
+	Season      string  `json:"season"`
+	Rank        int     `json:"rank"`       // 1 is the best; equal results share a rank
+	Cohort      int     `json:"cohort"`     // cricketers ranked
+	Percentile  float64 `json:"percentile"` // share of the cohort this result beats, counting ties as half
+}
+
+// FitnessTrend is a cricketer's results in one test over time
+type FitnessTrend struct {
+	Test          FitnessTest     `json:"test"`
+	Results       []FitnessResult `json:"results"` // oldest first
+	Best          *FitnessResult  `json:"best,omitempty"`
+	Change        *float64        `json:"change"`        // latest - previous
+	ChangePercent *float64        `json:"changePercent"` // improvement on the previous result, negative when worse
+	Ranking       *FitnessRanking `json:"ranking,omitempty"`
+}
+
+// FitnessLeaderboardEntry is a cricketer's latest result in a FitnessLeaderboard
+type FitnessLeaderboardEntry struct {
+	FitnessResult `bson:",inline"`
+	Rank          int     `json:"rank"`
+	Percentile    float64 `json:"percentile"`
+}
+
+// FitnessLeaderboard ranks the latest results in a test within an age category for a season
+type FitnessLeaderboard struct {
+	Test        FitnessTest               `json:"test"`
+	AgeCategory string                    `json:"ageCategory"`
+	Season      string                    `json:"season"`
+	Entries     []FitnessLeaderboardEntry `json:"entries"`
+}
+
+// FitnessAlert flags a result significantly worse than the cricketer's previous result in the test
+type FitnessAlert struct {
+	ID               primitive.ObjectID  `json:"id" bson:"_id,omitempty"`
+	CricketerID      primitive.ObjectID  `json:"cricketerId" bson:"cricketerId"`
+	CricketerName    string              `json:"cricketerName" bson:"cricketerName"`
+	BatchID          *primitive.ObjectID `json:"batchId,omitempty" bson:"batchId,omitempty"`
+	TestID           primitive.ObjectID  `json:"testId" bson:"testId"`
+	TestName         string              `json:"testName" bson:"testName"`
+	Unit             string              `json:"unit" bson:"unit"`
+	ResultID         primitive.ObjectID  `json:"resultId" bson:"resultId"`
+	PreviousResultID primitive.ObjectID  `json:"previousResultId" bson:"previousResultId"`
+	Previous         float64             `json:"previous" bson:"previous"`
+	Current          float64             `json:"current" bson:"current"`
+	DropPercent      float64             `json:"dropPercent" bson:"dropPercent"`
+	CreatedAt        time.Time           `json:"createdAt" bson:"createdAt"`
+	AcknowledgedBy   string              `json:"acknowledgedBy,omitempty" bson:"acknowledgedBy,omitempty"`
+	AcknowledgedAt   *time.Time          `json:"acknowledgedAt,omitempty" bson:"acknowledgedAt,omitempty"`
+}
+
+// FitnessAlertFilter selects fitness alerts; empty fields match everything
+type FitnessAlertFilter struct {
+	BatchIDs    []primitive.ObjectID // nil matches every batch
+	CricketerID *primitive.ObjectID
+	Open        bool // only unacknowledged alerts
+}
+
+// DefaultFitnessTests are added to the catalogue when it is empty
+func DefaultFitnessTests() []FitnessTest {
+	benchmarks := func(values ...float64) []FitnessBenchmark {
+		categories := []string{"U-12", "U-14", "U-16", "U-19", "Open"}
+		list := make([]FitnessBenchmark, 0, len(categories))
+		for i, category := range categories {
+			list = append(list, FitnessBenchmark{
+				AgeCategory: category,
+				Average:     values[i*3],
+				Good:        values[i*3+1],
+				Excellent:   values[i*3+2],
+			})
+		}
+		return list
+	}
+	tests := []FitnessTest{
+		{Key: "yo_yo", Name: "Yo-Yo intermittent recovery (level 1)", Unit: "level", HigherIsBetter: true, DropThreshold: 5,
+			Description: "Level and shuttle reached, e.g. 16.1",
+			Benchmarks:  benchmarks(12.1, 13.4, 14.8, 13.4, 14.8, 15.8, 14.8, 15.8, 16.8, 15.8, 16.8, 17.8, 16.1, 17.1, 18.1)},
+		{Key: "sprint_20m", Name: "20m sprint", Unit: "seconds", DropThreshold: 5,
+			Description: "Best of two timed runs from a standing start",
+			Benchmarks:  benchmarks(3.9, 3.6, 3.4, 3.7, 3.45, 3.25, 3.5, 3.3, 3.1, 3.35, 3.15, 3.0, 3.3, 3.1, 2.95)},
+		{Key: "broad_jump", Name: "Standing broad jump", Unit: "cm", HigherIsBetter: true, DropThreshold: 10,
+			Description: "Best of three jumps, measured to the back of the nearest heel",
+			Benchmarks:  benchmarks(150, 170, 185, 170, 190, 205, 190, 210, 225, 205, 225, 240, 210, 230, 245)},
+		{Key: "plank", Name: "Plank hold", Unit: "seconds", HigherIsBetter: true, DropThreshold: 20,
+			Description: "Forearm plank held with a straight back",
+			Benchmarks:  benchmarks(45, 75, 105, 60, 90, 120, 75, 105, 150, 90, 120, 180, 90, 120, 180)},
+	}
+	for i := range tests {
+		tests[i].Active = true
+	}
+	return tests
+}
@@ -0,0 +1,67 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Guardian is a parent or guardian who logs in on behalf of one or more cricketers
+type Guardian struct {
+	ID           primitive.ObjectID   `json:"id" bson:"_id,omitempty"`
+	Name         string               `json:"name" bson:"name"`
+	Mobile       string               `json:"mobile" bson:"mobile"` // normalized 10-digit number, used to log in
+	Email        string               `json:"email,omitempty" bson:"email,omitempty"`
+	Password     string               `json:"-" bson:"password"`
+	CricketerIDs []primitive.ObjectID `json:"cricketerIds" bson:"cricketerIds"` // linked children
+	IsActive     bool                 `json:"isActive" bson:"isActive"`
+	CreatedAt    time.Time            `json:"createdAt" bson:"createdAt"`
+	UpdatedAt    time.Time            `json:"updatedAt" bson:"updatedAt"`
+}
+
+// IsGuardianOf reports whether the guardian is linked to the cricketer
+func (g *Guardian) IsGuardianOf(cricketerID primitive.ObjectID) bool {
+	for _, id := range g.CricketerIDs {
+		if id == cricketerID {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateGuardianRequest represents the request body for creating a guardian account
+type CreateGuardianRequest struct {
+	Name         string   `json:"name" binding:"required,max=100"`
+	Mobile       string   `json:"mobile" binding:"required,mobile"`
+	Email        string   `json:"email" binding:"omitempty,email"`
+	Password     string   `json:"password" binding:"required,min=6"`
+	CricketerIDs []string `json:"cricketerIds"`
+}
+
+// UpdateGuardianRequest represents the request body for updating a guardian account
+type UpdateGuardianRequest struct {
+	Name         *string   `json:"name,omitempty" binding:"omitempty,min=1,max=100"`
+	Email        *string   `json:"email,omitempty" binding:"omitempty,email"`
+	IsActive     *bool     `json:"isActive,omitempty"`
+	CricketerIDs *[]string `json:"cricketerIds,omitempty"` // replaces the linked children
+}
+
+// GuardianChild is one child's section of the guardian dashboard
+type GuardianChild struct {
+	ID                      primitive.ObjectID      `json:"id"`
+	Name                    string                  `json:"name"`
+	BatchID                 *primitive.ObjectID     `json:"batchId,omitempty"`
+	BatchName               string                  `json:"batchName,omitempty"`
+	AgeCategory             string                  `json:"ageCategory,omitempty"`
+	Inactive                bool                    `json:"inactive"`
+	MonthlyFee              int64                   `json:"monthlyFee"` // in paise; 0 when fees can't be paid online
+	DueDate                 *time.Time              `json:"dueDate,omitempty"`
+	FeeOverdue              bool                    `json:"feeOverdue"`
+	FeePayments             []FeePayment            `json:"feePayments"`
+	UpcomingSessions        []*Session              `json:"upcomingSessions"`
+	Attendance              []Attendance            `json:"attendance"` // recent sessions, most recent first
+	AttendanceSummary       AttendanceSummary       `json:"attendanceSummary"`
+	Announcements           []CricketerAnnouncement `json:"announcements"`
+	PendingAcknowledgements int                     `json:"pendingAcknowledgements"`
+	PendingConsents         int                     `json:"pendingConsents"` // required documents not signed at their current version
+}
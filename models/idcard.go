@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// IDCard is the player ID card issued to a cricketer. Reissuing the card bumps its version, so
+// the QR codes on earlier cards stop working.
+type IDCard struct {
+	ID            primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	CricketerID   primitive.ObjectID `json:"cricketerId" bson:"cricketerId"`
+	Version       int                `json:"version" bson:"version"`
+	ValidUntil    time.Time          `json:"validUntil" bson:"validUntil"` // the end of the season it was issued in
+	IssuedBy      string             `json:"issuedBy" bson:"issuedBy"`
+	IssuedAt      time.Time          `json:"issuedAt" bson:"issuedAt"`
+	ReissueReason string             `json:"reissueReason,omitempty" bson:"reissueReason,omitempty"`
+}
+
+// ReissueIDCardRequest represents the request body for replacing a cricketer's ID card, e.g. when it is lost
+type ReissueIDCardRequest struct {
+	Reason string `json:"reason" binding:"required,max=200"`
+}
+
+// CheckInRequest represents the request body for checking a cricketer in to a session by scanning their ID card
+type CheckInRequest struct {
+	Token string `json:"token" binding:"required,max=300"` // the text in the card's QR code
+}
@@ -0,0 +1,147 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Injury statuses, in return-to-play order
+const (
+	InjuryInjured           = "injured"
+	InjuryRehab             = "rehab"
+	InjuryClearedRestricted = "cleared_with_restrictions"
+	InjuryFullyFit          = "fully_fit"
+)
+
+// Injury severities
+const (
+	InjuryMinor    = "minor"    // back within a week
+	InjuryModerate = "moderate" // one to four weeks out
+	InjurySevere   = "severe"   // more than four weeks out
+)
+
+// InjuryTransitions lists the statuses an injury can move to from each status. A cricketer can
+// be cleared straight from any earlier stage, and a setback sends them back a stage; fully fit closes
+// the injury.
+var InjuryTransitions = map[string][]string{
+	InjuryInjured:           {InjuryRehab, InjuryClearedRestricted, InjuryFullyFit},
+	InjuryRehab:             {InjuryInjured, InjuryClearedRestricted, InjuryFullyFit},
+	InjuryClearedRestricted: {InjuryRehab, InjuryFullyFit},
+}
+
+// Injury is an injury a cricketer picked up and their progress back to full fitness
+type Injury struct {
+	ID             primitive.ObjectID   `json:"id" bson:"_id,omitempty"`
+	CricketerID    primitive.ObjectID   `json:"cricketerId" bson:"cricketerId"`
+	CricketerName  string               `json:"cricketerName" bson:"cricketerName"`
+	BatchID        *primitive.ObjectID  `json:"batchId,omitempty" bson:"batchId,omitempty"` // the cricketer's batch when injured
+	BodyPart       string               `json:"bodyPart" bson:"bodyPart"`
+	Type           string               `json:"type" bson:"type"`
+	Severity       string               `json:"severity" bson:"severity"`
+	Mechanism      string               `json:"mechanism" bson:"mechanism"`
+	Description    string               `json:"description,omitempty" bson:"description,omitempty"`
+	InjuredOn      time.Time            `json:"injuredOn" bson:"injuredOn"`
+	Status         string               `json:"status" bson:"status"`
+	Restrictions   string               `json:"restrictions,omitempty" bson:"restrictions,omitempty"` // while cleared with restrictions
+	ClearedBy      string               `json:"clearedBy,omitempty" bson:"clearedBy,omitempty"`
+	ClearedByName  string               `json:"clearedByName,omitempty" bson:"clearedByName,omitempty"`
+	ClearedAt      *time.Time           `json:"clearedAt,omitempty" bson:"clearedAt,omitempty"`
+	History        []InjuryStatusChange `json:"history" bson:"history"` // oldest first, starting with the report
+	ReportedBy     string               `json:"reportedBy" bson:"reportedBy"`
+	ReportedByRole string               `json:"reportedByRole" bson:"reportedByRole"`
+	CreatedAt      time.Time            `json:"createdAt" bson:"createdAt"`
+	UpdatedAt      time.Time            `json:"updatedAt" bson:"updatedAt"`
+}
+
+// CanMoveTo reports whether the injury's status can change to status
+func (i *Injury) CanMoveTo(status string) bool {
+	for _, next := range InjuryTransitions[i.Status] {
+		if next == status {
+			return true
+		}
+	}
+	return false
+}
+
+// Active reports whether the cricketer has not yet been cleared as fully fit
+func (i *Injury) Active() bool {
+	return i.Status != InjuryFullyFit
+}
+
+// BlocksTraining reports whether the cricketer is not yet cleared to take part in sessions.
+// Cricketers cleared with restrictions can train but are not selected for matches until fully fit.
+func (i *Injury) BlocksTraining() bool {
+	return i.Status == InjuryInjured || i.Status == InjuryRehab
+}
+
+// InjuryStatusChange records one step of an injury's status flow
+type InjuryStatusChange struct {
+	Status        string    `json:"status" bson:"status"`
+	Restrictions  string    `json:"restrictions,omitempty" bson:"restrictions,omitempty"`
+	Note          string    `json:"note,omitempty" bson:"note,omitempty"`
+	ChangedBy     string    `json:"changedBy" bson:"changedBy"`
+	ChangedByName string    `json:"changedByName" bson:"changedByName"`
+	ChangedByRole string    `json:"changedByRole" bson:"changedByRole"`
+	ChangedAt     time.Time `json:"changedAt" bson:"changedAt"`
+}
+
+// CreateInjuryRequest represents the request body for reporting an injury
+type CreateInjuryRequest struct {
+	BodyPart    string    `json:"bodyPart" binding:"required,oneof=head neck shoulder arm elbow wrist hand back side abdomen hip groin thigh hamstring knee calf shin ankle foot other"`
+	Type        string    `json:"type" binding:"required,oneof=strain sprain fracture bruise concussion dislocation tendinopathy stress_fracture laceration other"`
+	Severity    string    `json:"severity" binding:"required,oneof=minor moderate severe"`
+	Mechanism   string    `json:"mechanism" binding:"required,oneof=bowling batting fielding wicket_keeping fitness collision overuse other"`
+	Description string    `json:"description" binding:"omitempty,max=2000"`
+	InjuredOn   time.Time `json:"injuredOn" binding:"required,past"`
+}
+
+// UpdateInjuryStatusRequest represents the request body for moving an injury along its status flow
+type UpdateInjuryStatusRequest struct {
+	Status       string `json:"status" binding:"required,oneof=injured rehab cleared_with_restrictions fully_fit"`
+	Restrictions string `json:"restrictions" binding:"omitempty,max=500"` // required when clearing with restrictions
+	Note         string `json:"note" binding:"omitempty,max=1000"`
+}
+
+// InjuryFilter selects injuries; empty fields match everything
+type InjuryFilter struct {
+	CricketerID *primitive.ObjectID
+	BatchIDs    []primitive.ObjectID // nil matches every batch
+	ActiveOnly  bool                 // only injuries not yet cleared as fully fit
+	From        *time.Time           // injured on or after
+	To          *time.Time           // injured before
+}
+
+// InjuryCount summarises a group of injuries
+type InjuryCount struct {
+	Total    int `json:"total"`
+	Active   int `json:"active"`
+	Minor    int `json:"minor"`
+	Moderate int `json:"moderate"`
+	Severe   int `json:"severe"`
+	// AverageDaysOut is the mean number of days from injury to full fitness, over injuries that have healed
+	AverageDaysOut *float64 `json:"averageDaysOut"`
+}
+
+// InjuryTypeSummary counts injuries of one type
+type InjuryTypeSummary struct {
+	Type string `json:"type"`
+	InjuryCount
+}
+
+// InjuryBatchSummary counts a batch's injuries, overall and by type
+type InjuryBatchSummary struct {
+	BatchID   *primitive.ObjectID `json:"batchId"` // nil for cricketers without a batch
+	BatchName string              `json:"batchName"`
+	InjuryCount
+	ByType map[string]int `json:"byType"`
+}
+
+// InjuryReport summarises the injuries that happened in a period by type and by batch
+type InjuryReport struct {
+	From    time.Time            `json:"from"`
+	To      time.Time            `json:"to"`
+	Overall InjuryCount          `json:"overall"`
+	ByType  []InjuryTypeSummary  `json:"byType"`  // most injuries first
+	ByBatch []InjuryBatchSummary `json:"byBatch"` // most injuries first
+}
@@ -0,0 +1,181 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// League fixture stages
+const (
+	LeagueStageLeague       = "league"
+	LeagueStageQuarterFinal = "quarter_final"
+	LeagueStageSemiFinal    = "semi_final"
+	LeagueStageFinal        = "final"
+)
+
+// League result statuses. Abandoned matches count as no result.
+const (
+	LeagueResultCompleted = "completed"
+	LeagueResultTie       = "tie"
+	LeagueResultNoResult  = "no_result"
+)
+
+// Points awarded in the league stage
+const (
+	LeaguePointsWin      = 2
+	LeaguePointsTie      = 1
+	LeaguePointsNoResult = 1
+)
+
+// League is an internal league between teams of academy cricketers
+type League struct {
+	ID              primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	Name            string             `json:"name" bson:"name"`
+	OversPerInnings int                `json:"oversPerInnings" bson:"oversPerInnings"`
+	Teams           []LeagueTeam       `json:"teams" bson:"teams"`
+	CreatedBy       string             `json:"createdBy" bson:"createdBy"`
+	CreatedAt       time.Time          `json:"createdAt" bson:"createdAt"`
+	UpdatedAt       time.Time          `json:"updatedAt" bson:"updatedAt"`
+}
+
+// Team returns the league team with the given ID, or nil
+func (l *League) Team(id primitive.ObjectID) *LeagueTeam {
+	for i := range l.Teams {
+		if l.Teams[i].ID == id {
+			return &l.Teams[i]
+		}
+	}
+	return nil
+}
+
+// LeagueTeam is a team of academy cricketers in a league
+type LeagueTeam struct {
+	ID      primitive.ObjectID `json:"id" bson:"id"`
+	Name    string             `json:"name" bson:"name"`
+	Players []MatchPlayer      `json:"players" bson:"players"`
+}
+
+// MaxWickets is the number of wickets that bowls the team out
+func (t *LeagueTeam) MaxWickets() int {
+	if len(t.Players) > 11 || len(t.Players) < 2 {
+		return 10
+	}
+	return len(t.Players) - 1
+}
+
+// LeagueTeamRequest is one team in a CreateLeagueRequest
+type LeagueTeamRequest struct {
+	Name         string   `json:"name" binding:"required,max=100"`
+	CricketerIDs []string `json:"cricketerIds" binding:"required,min=2,max=16"`
+}
+
+// CreateLeagueRequest represents the request body for creating a league
+type CreateLeagueRequest struct {
+	Name            string              `json:"name" binding:"required,max=100"`
+	OversPerInnings int                 `json:"oversPerInnings" binding:"required,min=1,max=50"`
+	Teams           []LeagueTeamRequest `json:"teams" binding:"required,min=2,max=16"`
+}
+
+// LeagueSchedule describes when and where a league's matches can be played
+type LeagueSchedule struct {
+	StartDate       time.Time `json:"startDate" binding:"required"`
+	Weekdays        []int     `json:"weekdays" binding:"max=7"`                  // 0 (Sunday) to 6; empty for every day
+	StartTimes      []string  `json:"startTimes" binding:"required,min=1,max=6"` // HH:MM, local time
+	DurationMinutes int       `json:"durationMinutes" binding:"required,min=30,max=600"`
+	Venues          []string  `json:"venues" binding:"required,min=1,max=10"`
+}
+
+// GenerateLeagueFixturesRequest represents the request body for generating a league's round-robin fixtures
+type GenerateLeagueFixturesRequest struct {
+	LeagueSchedule
+	DoubleRoundRobin bool `json:"doubleRoundRobin"` // every pair plays twice, home and away
+}
+
+// KnockoutRequest represents the request body for generating a knockout bracket from the top of the table
+type KnockoutRequest struct {
+	LeagueSchedule
+	TopN int `json:"topN" binding:"required,oneof=2 4 8"`
+}
+
+// LeagueFixture is a scheduled league or knockout match
+type LeagueFixture struct {
+	ID       primitive.ObjectID  `json:"id" bson:"_id,omitempty"`
+	LeagueID primitive.ObjectID  `json:"leagueId" bson:"leagueId"`
+	Stage    string              `json:"stage" bson:"stage"`
+	Round    int                 `json:"round" bson:"round"`           // 1, 2, 3... within the stage
+	HomeID   *primitive.ObjectID `json:"homeTeamId" bson:"homeTeamId"` // nil until a knockout winner is known
+	AwayID   *primitive.ObjectID `json:"awayTeamId" bson:"awayTeamId"`
+	// HomeSeed and AwaySeed are the teams' league positions in the first knockout round
+	HomeSeed  int           `json:"homeSeed,omitempty" bson:"homeSeed,omitempty"`
+	AwaySeed  int           `json:"awaySeed,omitempty" bson:"awaySeed,omitempty"`
+	StartsAt  time.Time     `json:"startsAt" bson:"startsAt"`
+	EndsAt    time.Time     `json:"endsAt" bson:"endsAt"`
+	Venue     string        `json:"venue" bson:"venue"`
+	Next      *BracketSlot  `json:"next,omitempty" bson:"next,omitempty"` // where a knockout winner goes
+	Result    *LeagueResult `json:"result,omitempty" bson:"result,omitempty"`
+	CreatedAt time.Time     `json:"createdAt" bson:"createdAt"`
+	UpdatedAt time.Time     `json:"updatedAt" bson:"updatedAt"`
+}
+
+// BracketSlot is a side of a later knockout fixture
+type BracketSlot struct {
+	FixtureID primitive.ObjectID `json:"fixtureId" bson:"fixtureId"`
+	Home      bool               `json:"home" bson:"home"`
+}
+
+// LeagueResult is the outcome of a league fixture
+type LeagueResult struct {
+	Status     string              `json:"status" bson:"status"`
+	WinnerID   *primitive.ObjectID `json:"winnerTeamId,omitempty" bson:"winnerTeamId,omitempty"`
+	Innings    []LeagueInnings     `json:"innings" bson:"innings"` // in batting order
+	Summary    string              `json:"summary" bson:"summary"`
+	RecordedBy string              `json:"recordedBy" bson:"recordedBy"`
+	RecordedAt time.Time           `json:"recordedAt" bson:"recordedAt"`
+}
+
+// LeagueInnings is a team's total in a league fixture
+type LeagueInnings struct {
+	TeamID        primitive.ObjectID `json:"teamId" bson:"teamId"`
+	Runs          int                `json:"runs" bson:"runs"`
+	Wickets       int                `json:"wickets" bson:"wickets"`
+	Balls         int                `json:"balls" bson:"balls"` // legal deliveries faced
+	Overs         string             `json:"overs" bson:"overs"`
+	AllottedOvers int                `json:"allottedOvers" bson:"allottedOvers"`
+	AllOut        bool               `json:"allOut" bson:"allOut"`
+}
+
+// LeagueInningsRequest is one innings in a LeagueResultRequest
+type LeagueInningsRequest struct {
+	TeamID        string `json:"teamId" binding:"required,objectid"`
+	Runs          int    `json:"runs" binding:"min=0,max=1000"`
+	Wickets       int    `json:"wickets" binding:"min=0,max=10"`
+	Overs         string `json:"overs" binding:"required"`                       // e.g. 19.4
+	AllottedOvers int    `json:"allottedOvers" binding:"omitempty,min=1,max=50"` // for reduced matches; defaults to the league's overs
+}
+
+// LeagueResultRequest represents the request body for entering a league fixture's result.
+// Knockout fixtures that end in a tie or no result need the winner decided by super over or seeding.
+type LeagueResultRequest struct {
+	Status       string                 `json:"status" binding:"required,oneof=completed tie no_result"`
+	Innings      []LeagueInningsRequest `json:"innings" binding:"max=2"` // in batting order; both for completed and tied matches
+	WinnerTeamID string                 `json:"winnerTeamId" binding:"omitempty,objectid"`
+}
+
+// PointsTableRow is a team's league-stage record
+type PointsTableRow struct {
+	Position    int                `json:"position"`
+	TeamID      primitive.ObjectID `json:"teamId"`
+	TeamName    string             `json:"teamName"`
+	Played      int                `json:"played"`
+	Won         int                `json:"won"`
+	Lost        int                `json:"lost"`
+	Tied        int                `json:"tied"`
+	NoResult    int                `json:"noResult"`
+	Points      int                `json:"points"`
+	RunsFor     int                `json:"runsFor"`
+	OversFaced  string             `json:"oversFaced"` // counting the full quota when all out
+	RunsAgainst int                `json:"runsAgainst"`
+	OversBowled string             `json:"oversBowled"`
+	NetRunRate  float64            `json:"netRunRate"`
+}
@@ -0,0 +1,239 @@
+package models
+
+import (
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Match formats
+const (
+	MatchFormatT20    = "t20"
+	MatchFormatODI    = "odi"
+	MatchFormatCustom = "custom" // overs per innings set on the match
+)
+
+// FormatOvers returns the overs per innings of a standard format, or 0 for custom
+func FormatOvers(format string) int {
+	switch format {
+	case MatchFormatT20:
+		return 20
+	case MatchFormatODI:
+		return 50
+	}
+	return 0
+}
+
+// Match statuses. Only completed and no-result matches count towards career statistics.
+const (
+	MatchScheduled  = "scheduled"
+	MatchInProgress = "in_progress"
+	MatchCompleted  = "completed"
+	MatchNoResult   = "no_result"
+	MatchAbandoned  = "abandoned"
+)
+
+// StatsMatchStatuses lists the statuses of matches whose scorecards count towards career statistics
+var StatsMatchStatuses = []string{MatchCompleted, MatchNoResult}
+
+// Dismissals
+const (
+	DismissalNotOut      = "not_out"
+	DismissalDidNotBat   = "did_not_bat"
+	DismissalRetiredHurt = "retired_hurt"
+	DismissalBowled      = "bowled"
+	DismissalCaught      = "caught"
+	DismissalLBW         = "lbw"
+	DismissalStumped     = "stumped"
+	DismissalHitWicket   = "hit_wicket"
+	DismissalRunOut      = "run_out"
+	DismissalRetiredOut  = "retired_out"
+	DismissalObstructing = "obstructing_field"
+	DismissalTimedOut    = "timed_out"
+)
+
+// DismissalCountsAsOut reports whether a dismissal ends the batter's innings as out.
+// Not out, retired hurt and did not bat don't count against the batting average.
+func DismissalCountsAsOut(dismissal string) bool {
+	switch dismissal {
+	case DismissalNotOut, DismissalDidNotBat, DismissalRetiredHurt:
+		return false
+	}
+	return true
+}
+
+// DismissalCreditsBowler reports whether the bowler is credited with the wicket
+func DismissalCreditsBowler(dismissal string) bool {
+	switch dismissal {
+	case DismissalBowled, DismissalCaught, DismissalLBW, DismissalStumped, DismissalHitWicket:
+		return true
+	}
+	return false
+}
+
+// Match is a game played by academy cricketers, with its scorecard
+type Match struct {
+	ID              primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	Title           string             `json:"title" bson:"title"`
+	Type            string             `json:"type" bson:"type"` // practice, league, friendly, tournament
+	Format          string             `json:"format" bson:"format"`
+	OversPerInnings int                `json:"oversPerInnings" bson:"oversPerInnings"`
+	Date            time.Time          `json:"date" bson:"date"`
+	Venue           string             `json:"venue" bson:"venue"`
+	Teams           []MatchTeam        `json:"teams" bson:"teams"` // exactly two
+	TossWinner      string             `json:"tossWinner,omitempty" bson:"tossWinner,omitempty"`
+	TossDecision    string             `json:"tossDecision,omitempty" bson:"tossDecision,omitempty"` // bat, bowl
+	Innings         []Innings          `json:"innings" bson:"innings"`
+	Result          MatchResult        `json:"result" bson:"result"`
+	CreatedBy       string             `json:"createdBy" bson:"createdBy"`
+	CreatedByRole   string             `json:"createdByRole" bson:"createdByRole"`
+	CreatedAt       time.Time          `json:"createdAt" bson:"createdAt"`
+	UpdatedAt       time.Time          `json:"updatedAt" bson:"updatedAt"`
+}
+
+// MatchTeam is one side of a match. Academy cricketers are linked by ID; opponents can be named only.
+type MatchTeam struct {
+	Name    string        `json:"name" bson:"name" binding:"required,max=100"`
+	Players []MatchPlayer `json:"players" bson:"players"`
+}
+
+// MatchPlayer is a player in a team or a scorecard entry
+type MatchPlayer struct {
+	CricketerID *primitive.ObjectID `json:"cricketerId,omitempty" bson:"cricketerId,omitempty"`
+	Name        string              `json:"name" bson:"name" binding:"max=100"`
+}
+
+// Innings is one team's innings. Runs, wickets and balls are derived from the entries.
+type Innings struct {
+	BattingTeam string         `json:"battingTeam" bson:"battingTeam" binding:"required"`
+	Runs        int            `json:"runs" bson:"runs"`
+	Wickets     int            `json:"wickets" bson:"wickets"`
+	Balls       int            `json:"balls" bson:"balls"` // legal deliveries bowled
+	Overs       string         `json:"overs" bson:"overs"` // e.g. 19.4
+	Extras      Extras         `json:"extras" bson:"extras"`
+	Batting     []BattingEntry `json:"batting" bson:"batting"`
+	Bowling     []BowlingEntry `json:"bowling" bson:"bowling"`
+}
+
+// Extras are runs not scored off the bat
+type Extras struct {
+	Byes      int `json:"byes" bson:"byes" binding:"min=0"`
+	LegByes   int `json:"legByes" bson:"legByes" binding:"min=0"`
+	Wides     int `json:"wides" bson:"wides" binding:"min=0"`
+	NoBalls   int `json:"noBalls" bson:"noBalls" binding:"min=0"`
+	Penalties int `json:"penalties" bson:"penalties" binding:"min=0"`
+}
+
+// Total returns the sum of all extras
+func (e Extras) Total() int {
+	return e.Byes + e.LegByes + e.Wides + e.NoBalls + e.Penalties
+}
+
+// BattingEntry is a batter's line on the scorecard
+type BattingEntry struct {
+	MatchPlayer `bson:",inline"`
+	Position    int    `json:"position" bson:"position" binding:"required,min=1,max=11"`
+	Runs        int    `json:"runs" bson:"runs" binding:"min=0"`
+	Balls       int    `json:"balls" bson:"balls" binding:"min=0"`
+	Fours       int    `json:"fours" bson:"fours" binding:"min=0"`
+	Sixes       int    `json:"sixes" bson:"sixes" binding:"min=0"`
+	Dismissal   string `json:"dismissal" bson:"dismissal" binding:"required,oneof=not_out did_not_bat retired_hurt bowled caught lbw stumped hit_wicket run_out retired_out obstructing_field timed_out"`
+	Bowler      string `json:"bowler,omitempty" bson:"bowler,omitempty"`   // credited bowler's name
+	Fielder     string `json:"fielder,omitempty" bson:"fielder,omitempty"` // catcher, stumper or run-out fielder
+}
+
+// BowlingEntry is a bowler's line on the scorecard
+type BowlingEntry struct {
+	MatchPlayer `bson:",inline"`
+	Overs       string `json:"overs" bson:"overs" binding:"required"` // completed overs and balls, e.g. 3.4
+	Balls       int    `json:"balls" bson:"balls"`                    // legal deliveries, derived from overs
+	Maidens     int    `json:"maidens" bson:"maidens" binding:"min=0"`
+	Runs        int    `json:"runs" bson:"runs" binding:"min=0"` // including wides and no-balls
+	Wickets     int    `json:"wickets" bson:"wickets" binding:"min=0,max=10"`
+	Wides       int    `json:"wides" bson:"wides" binding:"min=0"`
+	NoBalls     int    `json:"noBalls" bson:"noBalls" binding:"min=0"`
+}
+
+// MatchResult is the outcome of a match
+type MatchResult struct {
+	Status  string `json:"status" bson:"status" binding:"required,oneof=scheduled in_progress completed no_result abandoned"`
+	Winner  string `json:"winner,omitempty" bson:"winner,omitempty"` // team name; empty for a tie
+	Margin  string `json:"margin,omitempty" bson:"margin,omitempty"` // e.g. 5 wickets, 23 runs
+	Summary string `json:"summary,omitempty" bson:"summary,omitempty" binding:"omitempty,max=500"`
+}
+
+// MatchRequest represents the request body for creating or replacing a match and its scorecard
+type MatchRequest struct {
+	Title           string      `json:"title" binding:"required,max=200"`
+	Type            string      `json:"type" binding:"required,oneof=practice league friendly tournament"`
+	Format          string      `json:"format" binding:"required,oneof=t20 odi custom"`
+	OversPerInnings int         `json:"oversPerInnings" binding:"omitempty,min=1,max=50"` // required for custom matches
+	Date            time.Time   `json:"date" binding:"required"`
+	Venue           string      `json:"venue" binding:"required,max=200"`
+	Teams           []MatchTeam `json:"teams" binding:"required,len=2"`
+	TossWinner      string      `json:"tossWinner"`
+	TossDecision    string      `json:"tossDecision" binding:"omitempty,oneof=bat bowl"`
+	Innings         []Innings   `json:"innings" binding:"omitempty,max=2"`
+	Result          MatchResult `json:"result"`
+}
+
+// MatchFilter selects matches; empty fields match everything
+type MatchFilter struct {
+	CricketerID *primitive.ObjectID
+	Status      string
+	From        *time.Time
+	To          *time.Time
+	Limit       int
+}
+
+// FormatOversFromBalls writes a number of legal deliveries as overs, e.g. 22 -> 3.4
+func FormatOversFromBalls(balls int) string {
+	overs := strconv.Itoa(balls / 6)
+	if balls%6 != 0 {
+		overs += "." + strconv.Itoa(balls%6)
+	}
+	return overs
+}
+
+// BattingStats are a cricketer's career batting figures
+type BattingStats struct {
+	Innings       int      `json:"innings" bson:"innings"`
+	NotOuts       int      `json:"notOuts" bson:"notOuts"`
+	Runs          int      `json:"runs" bson:"runs"`
+	Balls         int      `json:"balls" bson:"balls"`
+	Fours         int      `json:"fours" bson:"fours"`
+	Sixes         int      `json:"sixes" bson:"sixes"`
+	Fifties       int      `json:"fifties" bson:"fifties"`
+	Hundreds      int      `json:"hundreds" bson:"hundreds"`
+	HighestScore  int      `json:"highestScore" bson:"highestScore"`
+	HighestNotOut bool     `json:"highestNotOut" bson:"highestNotOut"`
+	Average       *float64 `json:"average" bson:"-"`    // runs per dismissal; null when never out
+	StrikeRate    *float64 `json:"strikeRate" bson:"-"` // runs per 100 balls
+}
+
+// BowlingStats are a cricketer's career bowling figures
+type BowlingStats struct {
+	Innings     int      `json:"innings" bson:"innings"`
+	Balls       int      `json:"balls" bson:"balls"`
+	Overs       string   `json:"overs" bson:"-"`
+	Maidens     int      `json:"maidens" bson:"maidens"`
+	Runs        int      `json:"runs" bson:"runs"`
+	Wickets     int      `json:"wickets" bson:"wickets"`
+	Wides       int      `json:"wides" bson:"wides"`
+	NoBalls     int      `json:"noBalls" bson:"noBalls"`
+	FiveWickets int      `json:"fiveWickets" bson:"fiveWickets"`
+	BestWickets int      `json:"-" bson:"bestWickets"`
+	BestRuns    int      `json:"-" bson:"bestRuns"`
+	Best        string   `json:"bestFigures,omitempty" bson:"-"` // e.g. 4/21
+	Average     *float64 `json:"average" bson:"-"`               // runs per wicket
+	Economy     *float64 `json:"economy" bson:"-"`               // runs per over
+	StrikeRate  *float64 `json:"strikeRate" bson:"-"`            // balls per wicket
+}
+
+// CareerStats are a cricketer's statistics across completed matches
+type CareerStats struct {
+	Matches int          `json:"matches"`
+	Batting BattingStats `json:"batting"`
+	Bowling BowlingStats `json:"bowling"`
+}
@@ -0,0 +1,67 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"cricketApp/pii"
+)
+
+// PIIFieldMedicalProfile names the encrypted medical profile, bound to its ciphertext
+const PIIFieldMedicalProfile = "medicalProfile"
+
+// BloodGroups lists the accepted blood groups
+var BloodGroups = []string{"A+", "A-", "B+", "B-", "AB+", "AB-", "O+", "O-"}
+
+// MedicalInfo is a cricketer's medical information
+type MedicalInfo struct {
+	BloodGroup  string   `json:"bloodGroup" binding:"omitempty,oneof=A+ A- B+ B- AB+ AB- O+ O-"`
+	Allergies   []string `json:"allergies"`
+	Conditions  []string `json:"conditions"`  // e.g. asthma, epilepsy
+	Medications []string `json:"medications"` // taken regularly or to be given in an emergency
+	Notes       string   `json:"notes" binding:"omitempty,max=1000"`
+}
+
+// EmergencyContact is someone to call if a cricketer is hurt
+type EmergencyContact struct {
+	Name         string `json:"name" binding:"required,max=100"`
+	Relationship string `json:"relationship" binding:"required,max=50"`
+	Mobile       string `json:"mobile" binding:"required,mobile"`
+	AltMobile    string `json:"altMobile" binding:"omitempty,mobile"`
+}
+
+// MedicalProfile holds a cricketer's medical information and emergency contacts.
+// Both are encrypted at rest together, see Sealed.
+type MedicalProfile struct {
+	ID                primitive.ObjectID  `json:"-" bson:"_id,omitempty"`
+	CricketerID       primitive.ObjectID  `json:"cricketerId" bson:"cricketerId"`
+	Medical           MedicalInfo         `json:"medical" bson:"-"`
+	EmergencyContacts []EmergencyContact  `json:"emergencyContacts" bson:"-"`
+	Sealed            *pii.EncryptedValue `json:"-" bson:"sealed"`
+	UpdatedBy         string              `json:"updatedBy" bson:"updatedBy"`
+	UpdatedByRole     string              `json:"updatedByRole" bson:"updatedByRole"` // admin, guardian
+	UpdatedAt         time.Time           `json:"updatedAt" bson:"updatedAt"`
+}
+
+// UpdateMedicalProfileRequest represents the request body for replacing a cricketer's medical profile
+type UpdateMedicalProfileRequest struct {
+	Medical           MedicalInfo        `json:"medical"`
+	EmergencyContacts []EmergencyContact `json:"emergencyContacts" binding:"required,min=1,max=5"`
+}
+
+// EmergencyCard is what a coach needs if a cricketer in their session is hurt
+type EmergencyCard struct {
+	CricketerID       primitive.ObjectID `json:"cricketerId"`
+	Name              string             `json:"name"`
+	Age               int                `json:"age,omitempty"`
+	BloodGroup        string             `json:"bloodGroup,omitempty"`
+	Allergies         []string           `json:"allergies"`
+	Conditions        []string           `json:"conditions"`
+	Medications       []string           `json:"medications"`
+	Notes             string             `json:"notes,omitempty"`
+	EmergencyContacts []EmergencyContact `json:"emergencyContacts"`
+	Guardians         []EmergencyContact `json:"guardians"`
+	MissingProfile    bool               `json:"missingProfile"`   // no medical profile has been recorded
+	ConsentsComplete  bool               `json:"consentsComplete"` // every required waiver is signed at its current version
+}
@@ -0,0 +1,57 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Note visibility levels
+const (
+	NoteCoachesOnly = "coaches" // the cricketer's coaches and admins
+	NoteShared      = "shared"  // also the cricketer and their guardians
+	NoteAdminOnly   = "admin"   // admins and the note's author
+)
+
+// CricketerNote is a coach's or admin's note about a cricketer
+type CricketerNote struct {
+	ID          primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	CricketerID primitive.ObjectID `json:"cricketerId" bson:"cricketerId"`
+	Body        string             `json:"body" bson:"body"`
+	Visibility  string             `json:"visibility" bson:"visibility"`
+	Tags        []string           `json:"tags" bson:"tags"`
+	AuthorID    string             `json:"authorId" bson:"authorId"`
+	AuthorRole  string             `json:"authorRole" bson:"authorRole"`
+	AuthorName  string             `json:"authorName" bson:"authorName"`
+	CreatedAt   time.Time          `json:"createdAt" bson:"createdAt"`
+	UpdatedAt   time.Time          `json:"updatedAt" bson:"updatedAt"`
+}
+
+// CreateNoteRequest represents the request body for writing a note about a cricketer
+type CreateNoteRequest struct {
+	Body       string   `json:"body" binding:"required,max=5000"`
+	Visibility string   `json:"visibility" binding:"required,oneof=coaches shared admin"`
+	Tags       []string `json:"tags" binding:"max=10"`
+}
+
+// UpdateNoteRequest represents the request body for editing a note
+type UpdateNoteRequest struct {
+	Body       *string   `json:"body,omitempty" binding:"omitempty,max=5000"`
+	Visibility *string   `json:"visibility,omitempty" binding:"omitempty,oneof=coaches shared admin"`
+	Tags       *[]string `json:"tags,omitempty" binding:"omitempty,max=10"`
+}
+
+// NoteFilter selects a page of a cricketer's notes, newest first
+type NoteFilter struct {
+	CricketerID primitive.ObjectID
+	// Visibilities restricts the feed to these visibility levels; nil matches every level
+	Visibilities []string
+	// AuthorID also includes this author's notes whatever their visibility
+	AuthorID string
+	Tag      string
+	// Before and BeforeID are the pagination cursor: only notes after (Before, BeforeID) in
+	// newest-first order are returned. Without BeforeID, those created strictly before Before.
+	Before   *time.Time
+	BeforeID *primitive.ObjectID
+	Limit    int
+}
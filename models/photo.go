@@ -0,0 +1,51 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Profile photo owners
+const (
+	PhotoOwnerCricketer = "cricketer"
+	PhotoOwnerCoach     = "coach"
+)
+
+// Profile photo statuses
+const (
+	PhotoActive   = "active"   // shown on the owner's profile
+	PhotoReplaced = "replaced" // a newer photo was uploaded or the photo was removed
+	PhotoRejected = "rejected" // taken down by an admin
+)
+
+// ProfilePhoto is a photo uploaded for a cricketer or coach. Only resized renditions are kept;
+// the upload itself, with its metadata, is never stored.
+type ProfilePhoto struct {
+	ID             primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	OwnerType      string             `json:"ownerType" bson:"ownerType"` // see PhotoOwner* constants
+	OwnerID        primitive.ObjectID `json:"ownerId" bson:"ownerId"`
+	ThumbnailKey   string             `json:"-" bson:"thumbnailKey"`
+	MediumKey      string             `json:"-" bson:"mediumKey"`
+	Width          int                `json:"width" bson:"width"` // of the medium rendition
+	Height         int                `json:"height" bson:"height"`
+	Status         string             `json:"status" bson:"status"` // see Photo* status constants
+	UploadedBy     string             `json:"uploadedBy" bson:"uploadedBy"`
+	UploadedByRole string             `json:"uploadedByRole" bson:"uploadedByRole"`
+	UploadedAt     time.Time          `json:"uploadedAt" bson:"uploadedAt"`
+	RejectedBy     string             `json:"rejectedBy,omitempty" bson:"rejectedBy,omitempty"`
+	RejectedAt     *time.Time         `json:"rejectedAt,omitempty" bson:"rejectedAt,omitempty"`
+	RejectReason   string             `json:"rejectReason,omitempty" bson:"rejectReason,omitempty"`
+}
+
+// PhotoURLs are time-limited links to a profile photo's renditions
+type PhotoURLs struct {
+	ThumbnailURL string    `json:"thumbnailUrl"`
+	MediumURL    string    `json:"mediumUrl"`
+	ExpiresAt    time.Time `json:"expiresAt"`
+}
+
+// RejectPhotoRequest represents the request body for taking down a profile photo
+type RejectPhotoRequest struct {
+	Reason string `json:"reason" binding:"required,max=200"`
+}
@@ -4,53 +4,163 @@ import (
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"cricketApp/pii"
 )
 
 // ParentDetails represents the parent/guardian information
 type ParentDetails struct {
-	Name       string `json:"name" bson:"name" binding:"required"`
-	ContactNo  string `json:"contactNo" bson:"contactNo" binding:"required"`
+	Name       string `json:"name" bson:"name" binding:"required,max=100"`
+	ContactNo  string `json:"contactNo" bson:"contactNo,omitempty" binding:"required,mobile"` // encrypted at rest, see SealedRegistrationPII
 	Occupation string `json:"occupation" bson:"occupation" binding:"required"`
 }
 
 // RegistrationForm represents the complete registration form
 type RegistrationForm struct {
-	ID               primitive.ObjectID `json:"id" bson:"_id,omitempty"`
-	FormNo           string             `json:"formNo" bson:"formNo" binding:"required"`
-	Date             time.Time          `json:"date" bson:"date" binding:"required"`
-	Reference        string             `json:"reference" bson:"reference"`
-	FullName         string             `json:"fullName" bson:"fullName" binding:"required"`
-	DateOfBirth      time.Time          `json:"dateOfBirth" bson:"dateOfBirth" binding:"required"`
-	ResidenceAddress string             `json:"residenceAddress" bson:"residenceAddress" binding:"required"`
-	ContactNo        string             `json:"contactNo" bson:"contactNo" binding:"required"`
-	Email            string             `json:"email" bson:"email" binding:"required,email"`
-	Education        string             `json:"education" bson:"education" binding:"required"`
-	SchoolCollege    string             `json:"schoolCollege" bson:"schoolCollege" binding:"required"`
-	AadhaarNo        string             `json:"aadhaarNo" bson:"aadhaarNo" binding:"required"`
-	Whatsapp         string             `json:"whatsapp" bson:"whatsapp" binding:"required"`
-	ParentDetails    ParentDetails      `json:"parentDetails" bson:"parentDetails" binding:"required"`
-	CricketerID      primitive.ObjectID `json:"cricketerId,omitempty" bson:"cricketerId,omitempty"`
-	Status           string             `json:"status" bson:"status"` // pending, approved, rejected
-	CreatedAt        time.Time          `json:"createdAt" bson:"createdAt"`
-	UpdatedAt        time.Time          `json:"updatedAt" bson:"updatedAt"`
-}
-
-// CreateRegistrationRequest represents the request body for creating a new registration
+	ID                 primitive.ObjectID         `json:"id" bson:"_id,omitempty"`
+	FormNo             string                     `json:"formNo" bson:"formNo" binding:"required"`
+	Date               time.Time                  `json:"date" bson:"date" binding:"required"`
+	Reference          string                     `json:"reference" bson:"reference"`
+	FullName           string                     `json:"fullName" bson:"fullName" binding:"required,max=100"`
+	DateOfBirth        time.Time                  `json:"dateOfBirth" bson:"dateOfBirth" binding:"required,past"`
+	ResidenceAddress   string                     `json:"residenceAddress" bson:"residenceAddress,omitempty" binding:"required"` // encrypted at rest
+	ContactNo          string                     `json:"contactNo" bson:"contactNo" binding:"required,mobile"`
+	Email              string                     `json:"email" bson:"email" binding:"required,email"`
+	Education          string                     `json:"education" bson:"education" binding:"required"`
+	SchoolCollege      string                     `json:"schoolCollege" bson:"schoolCollege" binding:"required"`
+	AadhaarNo          string                     `json:"aadhaarNo" bson:"aadhaarNo,omitempty" binding:"required,aadhaar"` // encrypted at rest
+	Whatsapp           string                     `json:"whatsapp" bson:"whatsapp" binding:"required,mobile"`
+	ParentDetails      ParentDetails              `json:"parentDetails" bson:"parentDetails" binding:"required"`
+	CricketerID        primitive.ObjectID         `json:"cricketerId,omitempty" bson:"cricketerId,omitempty"`
+	Status             string                     `json:"status" bson:"status"` // see Registration* status constants
+	StatusReason       string                     `json:"statusReason,omitempty" bson:"statusReason,omitempty"`
+	ReviewedBy         string                     `json:"reviewedBy,omitempty" bson:"reviewedBy,omitempty"`
+	StatusHistory      []RegistrationStatusChange `json:"statusHistory,omitempty" bson:"statusHistory,omitempty"`
+	BatchID            *primitive.ObjectID        `json:"batchId,omitempty" bson:"batchId,omitempty"`
+	EmailVerified      bool                       `json:"emailVerified" bson:"emailVerified"`
+	PhoneVerified      bool                       `json:"phoneVerified" bson:"phoneVerified"`
+	PossibleDuplicates []DuplicateMatch           `json:"possibleDuplicates,omitempty" bson:"possibleDuplicates,omitempty"`
+	SubmittedFromIP    string                     `json:"submittedFromIp,omitempty" bson:"submittedFromIp,omitempty"`
+	SealedPII          *SealedRegistrationPII     `json:"-" bson:"sealedPii,omitempty"`
+	AadhaarIndex       string                     `json:"-" bson:"aadhaarIndex,omitempty"` // blind index for duplicate checks
+	PIIMasked          bool                       `json:"piiMasked,omitempty" bson:"-"`
+	AgeCategory        string                     `json:"ageCategory,omitempty" bson:"-"`       // for the current season
+	DocumentTokenHash  string                     `json:"-" bson:"documentTokenHash,omitempty"` // lets the applicant upload documents
+	CreatedAt          time.Time                  `json:"createdAt" bson:"createdAt"`
+	UpdatedAt          time.Time                  `json:"updatedAt" bson:"updatedAt"`
+}
+
+// Names of the encrypted registration fields, bound to their ciphertexts
+const (
+	PIIFieldAadhaarNo        = "registration.aadhaarNo"
+	PIIFieldResidenceAddress = "registration.residenceAddress"
+	PIIFieldParentContactNo  = "registration.parentDetails.contactNo"
+)
+
+// SealedRegistrationPII holds the encrypted sensitive fields of a registration.
+// The plaintext fields are never stored once a registration has been sealed.
+type SealedRegistrationPII struct {
+	AadhaarNo        *pii.EncryptedValue `bson:"aadhaarNo,omitempty"`
+	ResidenceAddress *pii.EncryptedValue `bson:"residenceAddress,omitempty"`
+	ParentContactNo  *pii.EncryptedValue `bson:"parentContactNo,omitempty"`
+}
+
+// MaskPII replaces sensitive fields with masked values for display
+func (r *RegistrationForm) MaskPII() {
+	r.AadhaarNo = pii.MaskAadhaar(r.AadhaarNo)
+	r.ResidenceAddress = pii.MaskAddress(r.ResidenceAddress)
+	r.ParentDetails.ContactNo = pii.MaskPhone(r.ParentDetails.ContactNo)
+	r.PIIMasked = true
+}
+
+// Registration statuses. A registration moves
+// submitted -> under_review -> approved / rejected / waitlisted,
+// and a waitlisted registration can be reviewed again.
+const (
+	RegistrationSubmitted   = "submitted"
+	RegistrationUnderReview = "under_review"
+	RegistrationApproved    = "approved"
+	RegistrationRejected    = "rejected"
+	RegistrationWaitlisted  = "waitlisted"
+
+	// registrationPending is the status used before the review workflow existed
+	registrationPending = "pending"
+)
+
+var registrationTransitions = map[string][]string{
+	RegistrationSubmitted:   {RegistrationUnderReview},
+	RegistrationUnderReview: {RegistrationApproved, RegistrationRejected, RegistrationWaitlisted},
+	RegistrationWaitlisted:  {RegistrationUnderReview, RegistrationApproved, RegistrationRejected},
+}
+
+// CanTransitionRegistration reports whether a registration may move from one status to another
+func CanTransitionRegistration(from string, to string) bool {
+	if from == registrationPending {
+		from = RegistrationSubmitted
+	}
+	for _, allowed := range registrationTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// RegistrationStatusChange records a single step in the review workflow
+type RegistrationStatusChange struct {
+	From      string    `json:"from" bson:"from"`
+	To        string    `json:"to" bson:"to"`
+	Reason    string    `json:"reason,omitempty" bson:"reason,omitempty"`
+	ChangedBy string    `json:"changedBy" bson:"changedBy"`
+	ChangedAt time.Time `json:"changedAt" bson:"changedAt"`
+}
+
+// RegistrationApproval holds everything needed to approve a registration and provision the cricketer
+type RegistrationApproval struct {
+	Change      RegistrationStatusChange
+	JoiningDate time.Time
+	BatchID     *primitive.ObjectID
+	// CricketerID links the registration to an existing cricketer the reviewer has confirmed,
+	// e.g. one that only shares the applicant's mobile number
+	CricketerID *primitive.ObjectID
+	// NewCricketer is created when the registration isn't linked to an existing cricketer
+	// and no cricketer with the same email exists
+	NewCricketer *Cricketer
+}
+
+// RegistrationTransitionRequest represents the request body for moving a registration to a new status
+type RegistrationTransitionRequest struct {
+	Status      string     `json:"status" binding:"required,oneof=under_review approved rejected waitlisted"`
+	Reason      string     `json:"reason"`
+	BatchID     string     `json:"batchId,omitempty" binding:"omitempty,objectid"`     // approval only
+	CricketerID string     `json:"cricketerId,omitempty" binding:"omitempty,objectid"` // approval only, links an existing cricketer
+	JoiningDate *time.Time `json:"joiningDate,omitempty"`                              // approval only, defaults to today
+}
+
+// CreateRegistrationRequest represents the request body for a public registration application.
+// The form number is allocated by the server and the applicant is linked to a cricketer account on approval.
 type CreateRegistrationRequest struct {
-	FormNo           string        `json:"formNo" binding:"required"`
 	Date             time.Time     `json:"date" binding:"required"`
 	Reference        string        `json:"reference"`
-	FullName         string        `json:"fullName" binding:"required"`
-	DateOfBirth      time.Time     `json:"dateOfBirth" binding:"required"`
+	FullName         string        `json:"fullName" binding:"required,max=100"`
+	DateOfBirth      time.Time     `json:"dateOfBirth" binding:"required,past"`
 	ResidenceAddress string        `json:"residenceAddress" binding:"required"`
-	ContactNo        string        `json:"contactNo" binding:"required"`
+	ContactNo        string        `json:"contactNo" binding:"required,mobile"`
 	Email            string        `json:"email" binding:"required,email"`
 	Education        string        `json:"education" binding:"required"`
 	SchoolCollege    string        `json:"schoolCollege" binding:"required"`
-	AadhaarNo        string        `json:"aadhaarNo" binding:"required"`
-	Whatsapp         string        `json:"whatsapp" binding:"required"`
+	AadhaarNo        string        `json:"aadhaarNo" binding:"required,aadhaar"`
+	Whatsapp         string        `json:"whatsapp" binding:"required,mobile"`
 	ParentDetails    ParentDetails `json:"parentDetails" binding:"required"`
-	CricketerID      string        `json:"cricketerId" binding:"required"`
+
+	// Applicant verification, see POST /api/registrations/verify/start
+	EmailVerificationID string `json:"emailVerificationId" binding:"required,objectid"`
+	PhoneVerificationID string `json:"phoneVerificationId" binding:"required,objectid"`
+
+	// Anti-bot checks: a solved proof-of-work challenge and a honeypot field that must stay empty
+	Challenge string `json:"challenge" binding:"required"`
+	Solution  string `json:"solution" binding:"required"`
+	Website   string `json:"website"`
 }
 
 // UpdateRegistrationRequest represents the request body for updating a registration
@@ -58,15 +168,71 @@ type UpdateRegistrationRequest struct {
 	FormNo           *string        `json:"formNo,omitempty"`
 	Date             *time.Time     `json:"date,omitempty"`
 	Reference        *string        `json:"reference,omitempty"`
-	FullName         *string        `json:"fullName,omitempty"`
-	DateOfBirth      *time.Time     `json:"dateOfBirth,omitempty"`
+	FullName         *string        `json:"fullName,omitempty" binding:"omitempty,max=100"`
+	DateOfBirth      *time.Time     `json:"dateOfBirth,omitempty" binding:"omitempty,past"`
 	ResidenceAddress *string        `json:"residenceAddress,omitempty"`
-	ContactNo        *string        `json:"contactNo,omitempty"`
-	Email            *string        `json:"email,omitempty"`
+	ContactNo        *string        `json:"contactNo,omitempty" binding:"omitempty,mobile"`
+	Email            *string        `json:"email,omitempty" binding:"omitempty,email"`
 	Education        *string        `json:"education,omitempty"`
 	SchoolCollege    *string        `json:"schoolCollege,omitempty"`
-	AadhaarNo        *string        `json:"aadhaarNo,omitempty"`
-	Whatsapp         *string        `json:"whatsapp,omitempty"`
+	AadhaarNo        *string        `json:"aadhaarNo,omitempty" binding:"omitempty,aadhaar"`
+	Whatsapp         *string        `json:"whatsapp,omitempty" binding:"omitempty,mobile"`
 	ParentDetails    *ParentDetails `json:"parentDetails,omitempty"`
 	Status           *string        `json:"status,omitempty"`
 }
+
+// IsRegistrationStatus reports whether status is one of the Registration* statuses
+func IsRegistrationStatus(status string) bool {
+	switch status {
+	case RegistrationSubmitted, RegistrationUnderReview, RegistrationApproved, RegistrationRejected, RegistrationWaitlisted:
+		return true
+	}
+	return false
+}
+
+// StoredRegistrationStatuses returns the statuses to query for, including the legacy
+// pending status that registrations submitted before the review workflow still carry
+func StoredRegistrationStatuses(statuses []string) []string {
+	stored := append([]string{}, statuses...)
+	for _, status := range statuses {
+		if status == RegistrationSubmitted {
+			stored = append(stored, registrationPending)
+		}
+	}
+	return stored
+}
+
+// RegistrationFilter selects registrations for search and export; empty fields match everything.
+// Text fields match case-insensitively anywhere in the value.
+type RegistrationFilter struct {
+	Name     string     // applicant's full name
+	Phone    string     // applicant's contact or WhatsApp number
+	FormNo   string     // matches from the start of the form number
+	School   string     // school or college
+	Statuses []string   // any of these statuses
+	From     *time.Time // form date on or after
+	To       *time.Time // form date before
+	Skip     int
+	Limit    int
+}
+
+// DuplicateFormNo reports a form number shared by more than one registration
+type DuplicateFormNo struct {
+	FormNo          string               `json:"formNo" bson:"_id"`
+	Count           int                  `json:"count" bson:"count"`
+	RegistrationIDs []primitive.ObjectID `json:"registrationIds" bson:"registrationIds"`
+}
+
+// Duplicate match fields
+const (
+	DuplicateOnAadhaar    = "aadhaar"
+	DuplicateOnPhone      = "phone"
+	DuplicateOnNameAndDOB = "nameAndDob"
+)
+
+// DuplicateMatch points at an existing registration or cricketer that looks like the same applicant
+type DuplicateMatch struct {
+	Field          string              `json:"field" bson:"field"`
+	RegistrationID *primitive.ObjectID `json:"registrationId,omitempty" bson:"registrationId,omitempty"`
+	CricketerID    *primitive.ObjectID `json:"cricketerId,omitempty" bson:"cricketerId,omitempty"`
+}
@@ -0,0 +1,143 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Ball event types. Events are never changed or removed; an undo event cancels the latest
+// event that hasn't already been undone.
+const (
+	BallEventInningsStart = "innings_start"
+	BallEventBall         = "ball"
+	BallEventInningsEnd   = "innings_end"
+	BallEventUndo         = "undo"
+)
+
+// Extras on a delivery
+const (
+	ExtraWide   = "wide"
+	ExtraNoBall = "no_ball"
+	ExtraBye    = "bye"
+	ExtraLegBye = "leg_bye"
+)
+
+// Reasons an innings ends
+const (
+	InningsAllOut   = "all_out"
+	InningsOvers    = "overs"
+	InningsTarget   = "target"
+	InningsDeclared = "declared"
+	InningsClosed   = "closed" // e.g. stopped by rain or bad light
+)
+
+// BallEvent is an entry in a match's append-only scoring log
+type BallEvent struct {
+	ID      primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	MatchID primitive.ObjectID `json:"matchId" bson:"matchId"`
+	Seq     int                `json:"seq" bson:"seq"` // 1, 2, 3... within the match
+	Type    string             `json:"type" bson:"type"`
+
+	// innings_start
+	BattingTeam string       `json:"battingTeam,omitempty" bson:"battingTeam,omitempty"`
+	Striker     *MatchPlayer `json:"striker,omitempty" bson:"striker,omitempty"`
+	NonStriker  *MatchPlayer `json:"nonStriker,omitempty" bson:"nonStriker,omitempty"`
+
+	// ball
+	Bowler    *MatchPlayer `json:"bowler,omitempty" bson:"bowler,omitempty"`
+	Runs      int          `json:"runs" bson:"runs"`                               // off the bat
+	Boundary  bool         `json:"boundary,omitempty" bson:"boundary,omitempty"`   // runs were a four or six
+	Extra     string       `json:"extra,omitempty" bson:"extra,omitempty"`         // wide, no_ball, bye, leg_bye
+	ExtraRuns int          `json:"extraRuns,omitempty" bson:"extraRuns,omitempty"` // runs beyond the wide/no-ball penalty, or the byes
+	Wicket    *BallWicket  `json:"wicket,omitempty" bson:"wicket,omitempty"`
+	NewBatter *MatchPlayer `json:"newBatter,omitempty" bson:"newBatter,omitempty"`
+
+	// innings_end
+	Reason string `json:"reason,omitempty" bson:"reason,omitempty"`
+
+	// undo
+	Undoes int `json:"undoes,omitempty" bson:"undoes,omitempty"` // seq of the cancelled event
+
+	RecordedBy     string    `json:"recordedBy" bson:"recordedBy"`
+	RecordedByRole string    `json:"recordedByRole" bson:"recordedByRole"`
+	RecordedAt     time.Time `json:"recordedAt" bson:"recordedAt"`
+}
+
+// BallWicket is a dismissal on a delivery
+type BallWicket struct {
+	Kind    string `json:"kind" bson:"kind" binding:"required,oneof=bowled caught lbw stumped hit_wicket run_out obstructing_field"`
+	Batter  string `json:"batter,omitempty" bson:"batter,omitempty" binding:"omitempty,oneof=striker non_striker"` // defaults to the striker
+	Fielder string `json:"fielder,omitempty" bson:"fielder,omitempty" binding:"omitempty,max=100"`
+}
+
+// BallEventRequest represents the request body for recording the next event in a match
+type BallEventRequest struct {
+	Type        string       `json:"type" binding:"required,oneof=innings_start ball innings_end"`
+	Seq         *int         `json:"seq"` // optional: the seq the scorer expects this event to get, to catch stale clients
+	BattingTeam string       `json:"battingTeam"`
+	Striker     *MatchPlayer `json:"striker"`
+	NonStriker  *MatchPlayer `json:"nonStriker"`
+	Bowler      *MatchPlayer `json:"bowler"`
+	Runs        int          `json:"runs" binding:"min=0,max=6"`
+	Boundary    bool         `json:"boundary"`
+	Extra       string       `json:"extra" binding:"omitempty,oneof=wide no_ball bye leg_bye"`
+	ExtraRuns   int          `json:"extraRuns" binding:"min=0,max=6"`
+	Wicket      *BallWicket  `json:"wicket"`
+	NewBatter   *MatchPlayer `json:"newBatter"`
+	Reason      string       `json:"reason" binding:"omitempty,oneof=declared closed"`
+}
+
+// UndoBallRequest represents the request body for undoing the latest event
+type UndoBallRequest struct {
+	Seq *int `json:"seq"` // optional: the seq the scorer expects the undo to get
+}
+
+// Partnership is the runs added while two batters were together
+type Partnership struct {
+	Wicket      int         `json:"wicket"` // 1 for the opening partnership
+	Batter1     MatchPlayer `json:"batter1"`
+	Batter1Runs int         `json:"batter1Runs"`
+	Batter2     MatchPlayer `json:"batter2"`
+	Batter2Runs int         `json:"batter2Runs"`
+	Runs        int         `json:"runs"` // including extras
+	Balls       int         `json:"balls"`
+	Unbroken    bool        `json:"unbroken"`
+}
+
+// FallOfWicket records the score when a wicket fell
+type FallOfWicket struct {
+	Wicket    int         `json:"wicket"`
+	Runs      int         `json:"runs"`
+	Overs     string      `json:"overs"`
+	Batter    MatchPlayer `json:"batter"`
+	Dismissal string      `json:"dismissal"`
+}
+
+// LiveInnings is an innings scorecard with the state needed to keep scoring it
+type LiveInnings struct {
+	Innings
+	Target          int            `json:"target,omitempty"` // runs needed to win, in the second innings
+	Complete        bool           `json:"complete"`
+	EndReason       string         `json:"endReason,omitempty"`
+	Striker         *MatchPlayer   `json:"striker,omitempty"`
+	NonStriker      *MatchPlayer   `json:"nonStriker,omitempty"`
+	Bowler          *MatchPlayer   `json:"bowler,omitempty"`     // bowling the current over
+	LastBowler      *MatchPlayer   `json:"lastBowler,omitempty"` // bowled the previous over and can't bowl the next
+	ThisOver        []string       `json:"thisOver"`             // e.g. ["1", "4", "1wd", "W"]
+	RunRate         *float64       `json:"runRate"`
+	RequiredRunRate *float64       `json:"requiredRunRate,omitempty"`
+	Partnerships    []Partnership  `json:"partnerships"`
+	FallOfWickets   []FallOfWicket `json:"fallOfWickets"`
+}
+
+// LiveScore is the scorecard derived from a match's ball events
+type LiveScore struct {
+	MatchID         primitive.ObjectID `json:"matchId"`
+	Seq             int                `json:"seq"` // seq of the latest event in the log
+	Format          string             `json:"format"`
+	OversPerInnings int                `json:"oversPerInnings"`
+	MaxBowlerOvers  int                `json:"maxBowlerOvers"`
+	Innings         []LiveInnings      `json:"innings"`
+	Result          MatchResult        `json:"result"`
+}
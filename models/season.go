@@ -0,0 +1,53 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Season is a cricket season; age categories are determined by players' ages on its cutoff date
+type Season struct {
+	ID         primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	Name       string             `json:"name" bson:"name" binding:"required"` // e.g. 2026-27
+	StartDate  time.Time          `json:"startDate" bson:"startDate" binding:"required"`
+	EndDate    time.Time          `json:"endDate" bson:"endDate" binding:"required,gtfield=StartDate"`
+	CutoffDate time.Time          `json:"cutoffDate" bson:"cutoffDate" binding:"required"`
+	Default    bool               `json:"default,omitempty" bson:"-"` // derived because no season is configured for the date
+	CreatedAt  time.Time          `json:"createdAt" bson:"createdAt"`
+	UpdatedAt  time.Time          `json:"updatedAt" bson:"updatedAt"`
+}
+
+// CreateSeasonRequest represents the request body for creating a season
+type CreateSeasonRequest struct {
+	Name       string    `json:"name" binding:"required"`
+	StartDate  time.Time `json:"startDate" binding:"required"`
+	EndDate    time.Time `json:"endDate" binding:"required,gtfield=StartDate"`
+	CutoffDate time.Time `json:"cutoffDate" binding:"required"`
+}
+
+// UpdateSeasonRequest represents the request body for updating a season
+type UpdateSeasonRequest struct {
+	Name       *string    `json:"name,omitempty" binding:"omitempty,min=1"`
+	StartDate  *time.Time `json:"startDate,omitempty"`
+	EndDate    *time.Time `json:"endDate,omitempty"`
+	CutoffDate *time.Time `json:"cutoffDate,omitempty"`
+}
+
+// AgeCategoryChange is a player whose age category changes from one season to the next
+type AgeCategoryChange struct {
+	CricketerID     primitive.ObjectID  `json:"cricketerId"`
+	Name            string              `json:"name"`
+	DateOfBirth     time.Time           `json:"dateOfBirth"`
+	BatchID         *primitive.ObjectID `json:"batchId,omitempty"`
+	CurrentCategory string              `json:"currentCategory"`
+	NextCategory    string              `json:"nextCategory"`
+}
+
+// AgeCategoryChangeReport lists players moving up a category next season
+type AgeCategoryChangeReport struct {
+	CurrentSeason Season               `json:"currentSeason"`
+	NextSeason    Season               `json:"nextSeason"`
+	Changes       []AgeCategoryChange  `json:"changes"`
+	MissingDOB    []primitive.ObjectID `json:"missingDateOfBirth"` // active cricketers whose category can't be computed
+}
@@ -8,38 +8,41 @@ import (
 
 // Session represents a coaching session
 type Session struct {
-	ID          primitive.ObjectID `json:"id" bson:"_id,omitempty"`
-	CoachID     primitive.ObjectID `json:"coachId" bson:"coachId" binding:"required"`
-	Title       string             `json:"title" bson:"title" binding:"required"`
-	Description string             `json:"description" bson:"description"`
-	Date        time.Time          `json:"date" bson:"date" binding:"required"`
-	StartTime   time.Time          `json:"startTime" bson:"startTime" binding:"required"`
-	EndTime     time.Time          `json:"endTime" bson:"endTime" binding:"required"`
-	Venue       string             `json:"venue" bson:"venue" binding:"required"`
-	MaxStudents int                `json:"maxStudents" bson:"maxStudents" binding:"required,min=1"`
-	CreatedAt   time.Time          `json:"createdAt" bson:"createdAt"`
-	UpdatedAt   time.Time          `json:"updatedAt" bson:"updatedAt"`
+	ID          primitive.ObjectID  `json:"id" bson:"_id,omitempty"`
+	CoachID     primitive.ObjectID  `json:"coachId" bson:"coachId" binding:"required"`
+	BatchID     *primitive.ObjectID `json:"batchId,omitempty" bson:"batchId,omitempty"` // the batch the session is for, if any
+	Title       string              `json:"title" bson:"title" binding:"required"`
+	Description string              `json:"description" bson:"description"`
+	Date        time.Time           `json:"date" bson:"date" binding:"required"`
+	StartTime   time.Time           `json:"startTime" bson:"startTime" binding:"required"`
+	EndTime     time.Time           `json:"endTime" bson:"endTime" binding:"required,gtfield=StartTime"`
+	Venue       string              `json:"venue" bson:"venue" binding:"required"`
+	MaxStudents int                 `json:"maxStudents" bson:"maxStudents" binding:"required,min=1"`
+	CreatedAt   time.Time           `json:"createdAt" bson:"createdAt"`
+	UpdatedAt   time.Time           `json:"updatedAt" bson:"updatedAt"`
 }
 
 // CreateSessionRequest represents the request body for creating a new session
 type CreateSessionRequest struct {
-	CoachID     string    `json:"coachId" binding:"required"`
+	CoachID     string    `json:"coachId" binding:"required,objectid"`
+	BatchID     string    `json:"batchId" binding:"omitempty,objectid"`
 	Title       string    `json:"title" binding:"required"`
 	Description string    `json:"description"`
 	Date        time.Time `json:"date" binding:"required"`
 	StartTime   time.Time `json:"startTime" binding:"required"`
-	EndTime     time.Time `json:"endTime" binding:"required"`
+	EndTime     time.Time `json:"endTime" binding:"required,gtfield=StartTime"`
 	Venue       string    `json:"venue" binding:"required"`
 	MaxStudents int       `json:"maxStudents" binding:"required,min=1"`
 }
 
 // UpdateSessionRequest represents the request body for updating a session
 type UpdateSessionRequest struct {
-	Title       *string    `json:"title,omitempty"`
+	Title       *string    `json:"title,omitempty" binding:"omitempty,min=1"`
+	BatchID     *string    `json:"batchId,omitempty" binding:"omitempty,objectid"` // empty to detach the session from its batch
 	Description *string    `json:"description,omitempty"`
 	Date        *time.Time `json:"date,omitempty"`
 	StartTime   *time.Time `json:"startTime,omitempty"`
-	EndTime     *time.Time `json:"endTime,omitempty"`
-	Venue       *string    `json:"venue,omitempty"`
-	MaxStudents *int       `json:"maxStudents,omitempty"`
+	EndTime     *time.Time `json:"endTime,omitempty" binding:"omitempty,gtfield=StartTime"`
+	Venue       *string    `json:"venue,omitempty" binding:"omitempty,min=1"`
+	MaxStudents *int       `json:"maxStudents,omitempty" binding:"omitempty,min=1"`
 }
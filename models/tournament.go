@@ -0,0 +1,160 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Availability responses
+const (
+	AvailabilityPending     = "pending"
+	AvailabilityAvailable   = "available"
+	AvailabilityUnavailable = "unavailable"
+)
+
+// Squad roles
+const (
+	SquadCaptain      = "captain"
+	SquadViceCaptain  = "vice_captain"
+	SquadWicketKeeper = "wicket_keeper"
+)
+
+// PlayingXISize is the number of players in a playing XI
+const PlayingXISize = 11
+
+// Tournament is an external tournament the academy enters a squad in
+type Tournament struct {
+	ID          primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	Name        string             `json:"name" bson:"name"`
+	Organiser   string             `json:"organiser,omitempty" bson:"organiser,omitempty"`
+	AgeCategory string             `json:"ageCategory,omitempty" bson:"ageCategory,omitempty"` // empty for open tournaments
+	Venue       string             `json:"venue,omitempty" bson:"venue,omitempty"`
+	StartDate   time.Time          `json:"startDate" bson:"startDate"`
+	EndDate     time.Time          `json:"endDate" bson:"endDate"`
+	SquadSize   int                `json:"squadSize" bson:"squadSize"` // the most players the squad can have
+	Squad       []SquadMember      `json:"squad" bson:"squad"`
+	// SquadSelectedBy and SquadSelectedAt record the latest squad selection
+	SquadSelectedBy string     `json:"squadSelectedBy,omitempty" bson:"squadSelectedBy,omitempty"`
+	SquadSelectedAt *time.Time `json:"squadSelectedAt,omitempty" bson:"squadSelectedAt,omitempty"`
+	CreatedBy       string     `json:"createdBy" bson:"createdBy"`
+	CreatedAt       time.Time  `json:"createdAt" bson:"createdAt"`
+	UpdatedAt       time.Time  `json:"updatedAt" bson:"updatedAt"`
+}
+
+// Member returns the squad member for a cricketer, or nil if they aren't in the squad
+func (t *Tournament) Member(cricketerID primitive.ObjectID) *SquadMember {
+	for i := range t.Squad {
+		if t.Squad[i].CricketerID == cricketerID {
+			return &t.Squad[i]
+		}
+	}
+	return nil
+}
+
+// SquadMember is a cricketer selected for a tournament squad
+type SquadMember struct {
+	CricketerID primitive.ObjectID `json:"cricketerId" bson:"cricketerId"`
+	Name        string             `json:"name" bson:"name"`
+	Roles       []string           `json:"roles" bson:"roles"` // captain, vice_captain, wicket_keeper
+}
+
+// TournamentRequest represents the request body for creating or updating a tournament
+type TournamentRequest struct {
+	Name        string    `json:"name" binding:"required,max=100"`
+	Organiser   string    `json:"organiser" binding:"omitempty,max=100"`
+	AgeCategory string    `json:"ageCategory" binding:"omitempty,max=20"`
+	Venue       string    `json:"venue" binding:"omitempty,max=200"`
+	StartDate   time.Time `json:"startDate" binding:"required"`
+	EndDate     time.Time `json:"endDate" binding:"required,gtefield=StartDate"`
+	SquadSize   int       `json:"squadSize" binding:"omitempty,min=11,max=25"` // defaults to 15
+}
+
+// SquadMemberRequest is one cricketer in a SelectSquadRequest
+type SquadMemberRequest struct {
+	CricketerID string   `json:"cricketerId" binding:"required,objectid"`
+	Roles       []string `json:"roles" binding:"max=3"`
+}
+
+// SelectSquadRequest represents the request body for selecting a tournament squad. It replaces
+// the previous squad.
+type SelectSquadRequest struct {
+	Members []SquadMemberRequest `json:"members" binding:"required,max=25"`
+}
+
+// Fixture is one of a tournament's matches
+type Fixture struct {
+	ID           primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	TournamentID primitive.ObjectID `json:"tournamentId" bson:"tournamentId"`
+	Opponent     string             `json:"opponent" bson:"opponent"`
+	StartsAt     time.Time          `json:"startsAt" bson:"startsAt"`
+	Venue        string             `json:"venue,omitempty" bson:"venue,omitempty"`
+	Stage        string             `json:"stage,omitempty" bson:"stage,omitempty"` // e.g. group, quarter-final
+	PlayingXI    *PlayingXI         `json:"playingXI,omitempty" bson:"playingXI,omitempty"`
+	CreatedAt    time.Time          `json:"createdAt" bson:"createdAt"`
+	UpdatedAt    time.Time          `json:"updatedAt" bson:"updatedAt"`
+}
+
+// FixtureRequest represents the request body for creating or updating a fixture
+type FixtureRequest struct {
+	Opponent string    `json:"opponent" binding:"required,max=100"`
+	StartsAt time.Time `json:"startsAt" binding:"required"`
+	Venue    string    `json:"venue" binding:"omitempty,max=200"`
+	Stage    string    `json:"stage" binding:"omitempty,max=50"`
+}
+
+// PlayingXI is the team picked for a fixture, in batting order
+type PlayingXI struct {
+	BattingOrder   []MatchPlayer      `json:"battingOrder" bson:"battingOrder"`
+	CaptainID      primitive.ObjectID `json:"captainId" bson:"captainId"`
+	WicketKeeperID primitive.ObjectID `json:"wicketKeeperId" bson:"wicketKeeperId"`
+	SelectedBy     string             `json:"selectedBy" bson:"selectedBy"`
+	SelectedAt     time.Time          `json:"selectedAt" bson:"selectedAt"`
+}
+
+// PlayingXIRequest represents the request body for picking a fixture's playing XI from the squad
+type PlayingXIRequest struct {
+	BattingOrder   []string `json:"battingOrder" binding:"required,len=11"` // cricketer IDs, openers first
+	CaptainID      string   `json:"captainId" binding:"required,objectid"`
+	WicketKeeperID string   `json:"wicketKeeperId" binding:"required,objectid"`
+}
+
+// Availability is a cricketer's answer to whether they can play in a tournament
+type Availability struct {
+	ID              primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	TournamentID    primitive.ObjectID `json:"tournamentId" bson:"tournamentId"`
+	TournamentName  string             `json:"tournamentName" bson:"tournamentName"`
+	StartDate       time.Time          `json:"startDate" bson:"startDate"`
+	EndDate         time.Time          `json:"endDate" bson:"endDate"`
+	CricketerID     primitive.ObjectID `json:"cricketerId" bson:"cricketerId"`
+	CricketerName   string             `json:"cricketerName" bson:"cricketerName"`
+	Response        string             `json:"response" bson:"response"`
+	Note            string             `json:"note,omitempty" bson:"note,omitempty"`
+	RespondedBy     string             `json:"respondedBy,omitempty" bson:"respondedBy,omitempty"`
+	RespondedByRole string             `json:"respondedByRole,omitempty" bson:"respondedByRole,omitempty"` // guardian, cricketer
+	RespondedAt     *time.Time         `json:"respondedAt,omitempty" bson:"respondedAt,omitempty"`
+	RequestedBy     string             `json:"requestedBy" bson:"requestedBy"`
+	RequestedAt     time.Time          `json:"requestedAt" bson:"requestedAt"`
+}
+
+// AvailabilityPollRequest represents the request body for asking a candidate pool whether they are
+// available for a tournament. Cricketers already asked keep their answers.
+type AvailabilityPollRequest struct {
+	CricketerIDs []string `json:"cricketerIds" binding:"max=200"`
+	BatchIDs     []string `json:"batchIds" binding:"max=20"` // every active cricketer in these batches
+}
+
+// AvailabilityResponseRequest represents the request body for answering an availability poll
+type AvailabilityResponseRequest struct {
+	Response string `json:"response" binding:"required,oneof=available unavailable"`
+	Note     string `json:"note" binding:"omitempty,max=500"`
+}
+
+// TournamentCandidate is a polled cricketer's availability and eligibility for a tournament
+type TournamentCandidate struct {
+	Availability
+	AgeCategory string   `json:"ageCategory,omitempty"`
+	Eligible    bool     `json:"eligible"`
+	Reasons     []string `json:"reasons"` // why the cricketer isn't eligible
+	InSquad     bool     `json:"inSquad"`
+}
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Verification channels
+const (
+	VerificationEmail = "email"
+	VerificationPhone = "phone"
+)
+
+// ContactVerification is a one-time code sent to an applicant's email or phone
+type ContactVerification struct {
+	ID         primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	Channel    string             `json:"channel" bson:"channel"`
+	Target     string             `json:"target" bson:"target"` // normalized email or phone number
+	CodeHash   string             `json:"-" bson:"codeHash"`
+	Attempts   int                `json:"-" bson:"attempts"`
+	ExpiresAt  time.Time          `json:"expiresAt" bson:"expiresAt"`
+	VerifiedAt *time.Time         `json:"verifiedAt,omitempty" bson:"verifiedAt,omitempty"`
+	ConsumedAt *time.Time         `json:"-" bson:"consumedAt,omitempty"`
+	RequestIP  string             `json:"-" bson:"requestIp"`
+	CreatedAt  time.Time          `json:"createdAt" bson:"createdAt"`
+}
+
+// StartVerificationRequest represents the request body for sending a verification code
+type StartVerificationRequest struct {
+	Channel string `json:"channel" binding:"required,oneof=email phone"`
+	Target  string `json:"target" binding:"required"`
+}
+
+// ConfirmVerificationRequest represents the request body for confirming a verification code
+type ConfirmVerificationRequest struct {
+	VerificationID string `json:"verificationId" binding:"required,objectid"`
+	Code           string `json:"code" binding:"required,len=6,numeric"`
+}
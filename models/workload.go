@@ -0,0 +1,147 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Workload status against a limit
+const (
+	WorkloadWithinLimit = "ok"
+	WorkloadApproaching = "approaching"
+	WorkloadExceeded    = "exceeded"
+)
+
+// Acute:chronic workload ratio zones
+const (
+	WorkloadZoneLow      = "low"       // below 0.8: under-prepared for a spike
+	WorkloadZoneOptimal  = "optimal"   // 0.8 to 1.3
+	WorkloadZoneCaution  = "caution"   // above 1.3 to 1.5
+	WorkloadZoneHighRisk = "high_risk" // above 1.5
+)
+
+// Workload alert kinds
+const (
+	WorkloadAlertDaily  = "daily_limit"
+	WorkloadAlertWeekly = "weekly_limit"
+	WorkloadAlertRatio  = "acute_chronic_ratio"
+)
+
+// WorkloadEntry is the number of deliveries a cricketer bowled in a session.
+// Match deliveries are read from match scorecards instead.
+type WorkloadEntry struct {
+	ID             primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	CricketerID    primitive.ObjectID `json:"cricketerId" bson:"cricketerId"`
+	SessionID      primitive.ObjectID `json:"sessionId" bson:"sessionId"`
+	Date           time.Time          `json:"date" bson:"date"` // the session's date
+	Deliveries     int                `json:"deliveries" bson:"deliveries"`
+	RecordedBy     string             `json:"recordedBy" bson:"recordedBy"`
+	RecordedByRole string             `json:"recordedByRole" bson:"recordedByRole"`
+	UpdatedAt      time.Time          `json:"updatedAt" bson:"updatedAt"`
+}
+
+// DeliveryCount is one bowler's deliveries in a RecordDeliveriesRequest
+type DeliveryCount struct {
+	CricketerID string `json:"cricketerId" binding:"required,objectid"`
+	Deliveries  int    `json:"deliveries" binding:"min=0,max=300"`
+}
+
+// RecordDeliveriesRequest represents the request body for recording deliveries bowled in a session.
+// Recording a bowler again replaces their count for the session.
+type RecordDeliveriesRequest struct {
+	Bowlers []DeliveryCount `json:"bowlers" binding:"required,max=100"`
+}
+
+// WorkloadLimit caps the deliveries bowlers in an age category should bowl
+type WorkloadLimit struct {
+	AgeCategory    string    `json:"ageCategory" bson:"_id"`
+	DailyMax       int       `json:"dailyMax" bson:"dailyMax"`             // deliveries in a day
+	WeeklyMax      int       `json:"weeklyMax" bson:"weeklyMax"`           // deliveries in the last 7 days
+	WarningPercent int       `json:"warningPercent" bson:"warningPercent"` // share of a limit that counts as approaching it
+	UpdatedBy      string    `json:"updatedBy,omitempty" bson:"updatedBy,omitempty"`
+	UpdatedAt      time.Time `json:"updatedAt" bson:"updatedAt"`
+}
+
+// Status compares deliveries with a maximum
+func (l *WorkloadLimit) Status(deliveries int, max int) string {
+	switch {
+	case max <= 0:
+		return WorkloadWithinLimit
+	case deliveries > max:
+		return WorkloadExceeded
+	case deliveries*100 >= max*l.WarningPercent:
+		return WorkloadApproaching
+	}
+	return WorkloadWithinLimit
+}
+
+// WorkloadLimitRequest represents the request body for setting an age category's limits
+type WorkloadLimitRequest struct {
+	DailyMax       int `json:"dailyMax" binding:"required,min=1,max=500"`
+	WeeklyMax      int `json:"weeklyMax" binding:"required,min=1,max=2000,gtefield=DailyMax"`
+	WarningPercent int `json:"warningPercent" binding:"omitempty,min=50,max=100"`
+}
+
+// WorkloadDay is a bowler's deliveries on one day
+type WorkloadDay struct {
+	Date     time.Time `json:"date"`
+	Sessions int       `json:"sessions"` // deliveries in sessions
+	Matches  int       `json:"matches"`  // deliveries in matches, including wides and no-balls
+	Total    int       `json:"total"`
+}
+
+// BowlingWorkload is a bowler's recent workload against their age category's limits
+type BowlingWorkload struct {
+	CricketerID   primitive.ObjectID  `json:"cricketerId"`
+	CricketerName string              `json:"cricketerName"`
+	BatchID       *primitive.ObjectID `json:"batchId,omitempty"`
+	AgeCategory   string              `json:"ageCategory,omitempty"`
+	AsOf          time.Time           `json:"asOf"`
+	Today         int                 `json:"today"`
+	Acute         int                 `json:"acute"`             // deliveries in the last 7 days
+	Chronic       float64             `json:"chronic"`           // average weekly deliveries over the last 28 days
+	Ratio         *float64            `json:"acuteChronicRatio"` // nil without a chronic load
+	Zone          string              `json:"zone,omitempty"`
+	Limit         *WorkloadLimit      `json:"limit,omitempty"`
+	DailyStatus   string              `json:"dailyStatus"`
+	WeeklyStatus  string              `json:"weeklyStatus"`
+	Days          []WorkloadDay       `json:"days,omitempty"` // the last 28 days, oldest first
+}
+
+// WorkloadAlert tells coaches a bowler is approaching or over a limit, or their workload has spiked.
+// There is at most one alert per bowler, day, kind and level.
+type WorkloadAlert struct {
+	ID             primitive.ObjectID  `json:"id" bson:"_id,omitempty"`
+	CricketerID    primitive.ObjectID  `json:"cricketerId" bson:"cricketerId"`
+	CricketerName  string              `json:"cricketerName" bson:"cricketerName"`
+	BatchID        *primitive.ObjectID `json:"batchId,omitempty" bson:"batchId,omitempty"`
+	Date           time.Time           `json:"date" bson:"date"`
+	Kind           string              `json:"kind" bson:"kind"`
+	Level          string              `json:"level" bson:"level"` // approaching, exceeded or high_risk
+	Deliveries     int                 `json:"deliveries" bson:"deliveries"`
+	Limit          int                 `json:"limit,omitempty" bson:"limit,omitempty"`
+	Ratio          float64             `json:"ratio,omitempty" bson:"ratio,omitempty"`
+	CreatedAt      time.Time           `json:"createdAt" bson:"createdAt"`
+	AcknowledgedBy string              `json:"acknowledgedBy,omitempty" bson:"acknowledgedBy,omitempty"`
+	AcknowledgedAt *time.Time          `json:"acknowledgedAt,omitempty" bson:"acknowledgedAt,omitempty"`
+}
+
+// WorkloadAlertFilter selects workload alerts; empty fields match everything
+type WorkloadAlertFilter struct {
+	BatchIDs    []primitive.ObjectID // nil matches every batch
+	CricketerID *primitive.ObjectID
+	Open        bool // only unacknowledged alerts
+}
+
+// DefaultWorkloadLimits are junior fast-bowling limits in line with national guidelines,
+// used until an admin sets the academy's own
+func DefaultWorkloadLimits() []WorkloadLimit {
+	return []WorkloadLimit{
+		{AgeCategory: "U-12", DailyMax: 36, WeeklyMax: 120, WarningPercent: 80},
+		{AgeCategory: "U-14", DailyMax: 42, WeeklyMax: 150, WarningPercent: 80},
+		{AgeCategory: "U-16", DailyMax: 48, WeeklyMax: 175, WarningPercent: 80},
+		{AgeCategory: "U-19", DailyMax: 60, WeeklyMax: 210, WarningPercent: 80},
+		{AgeCategory: "Open", DailyMax: 72, WeeklyMax: 250, WarningPercent: 80},
+	}
+}
@@ -0,0 +1,70 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+)
+
+// Recipient identifies who a notification should be delivered to
+type Recipient struct {
+	Name   string
+	Mobile string
+	Email  string
+}
+
+// Notifier delivers a message to a recipient
+type Notifier interface {
+	Notify(ctx context.Context, recipient Recipient, subject string, message string) error
+}
+
+// NewNotifierFromEnv creates the notifier selected by NOTIFIER. "log" (the default) only writes
+// notifications to the application log and is meant for local development; "webhook" delivers
+// them through an SMS/email relay (see NewWebhookNotifierFromEnv).
+func NewNotifierFromEnv() (Notifier, error) {
+	switch os.Getenv("NOTIFIER") {
+	case "", "log":
+		return NewLogNotifier(), nil
+	case "webhook":
+		return NewWebhookNotifierFromEnv()
+	default:
+		return nil, fmt.Errorf("unsupported NOTIFIER %q", os.Getenv("NOTIFIER"))
+	}
+}
+
+type secretKey struct{}
+
+// WithSecret marks the notifications sent with ctx as carrying a secret, such as a verification
+// code or a password setup link. Notifiers deliver them as usual but never log their message.
+func WithSecret(ctx context.Context) context.Context {
+	return context.WithValue(ctx, secretKey{}, true)
+}
+
+// IsSecret reports whether ctx was marked by WithSecret
+func IsSecret(ctx context.Context) bool {
+	secret, _ := ctx.Value(secretKey{}).(bool)
+	return secret
+}
+
+// LogNotifier writes notifications to the application log. Messages marked with WithSecret are
+// redacted, so nothing that needs them (e.g. registration verification) works with it.
+type LogNotifier struct{}
+
+// NewLogNotifier creates a new LogNotifier
+func NewLogNotifier() *LogNotifier {
+	return &LogNotifier{}
+}
+
+func (n *LogNotifier) Notify(ctx context.Context, recipient Recipient, subject string, message string) error {
+	if IsSecret(ctx) {
+		message = "[redacted]"
+	}
+	log.Printf("Sending notification to %s (mobile: %s, email: %s): %s - %s",
+		recipient.Name,
+		recipient.Mobile,
+		recipient.Email,
+		subject,
+		message)
+	return nil
+}
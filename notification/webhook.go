@@ -0,0 +1,81 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+)
+
+// WebhookNotifier delivers notifications by posting them as JSON to an SMS/email relay, which
+// sends an SMS when the recipient has a mobile number and an email when they have an address:
+//
+//	{"name": "...", "mobile": "...", "email": "...", "subject": "...", "message": "..."}
+//
+// Any 2xx response counts as delivered.
+type WebhookNotifier struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+// NewWebhookNotifier creates a WebhookNotifier posting to url. token, if set, is sent as a bearer token.
+func NewWebhookNotifier(url string, token string) *WebhookNotifier {
+	return &WebhookNotifier{url: url, token: token, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// NewWebhookNotifierFromEnv creates a WebhookNotifier from NOTIFIER_WEBHOOK_URL and NOTIFIER_WEBHOOK_TOKEN
+func NewWebhookNotifierFromEnv() (*WebhookNotifier, error) {
+	url := os.Getenv("NOTIFIER_WEBHOOK_URL")
+	if url == "" {
+		return nil, fmt.Errorf("NOTIFIER_WEBHOOK_URL is required for the webhook notifier")
+	}
+	return NewWebhookNotifier(url, os.Getenv("NOTIFIER_WEBHOOK_TOKEN")), nil
+}
+
+type webhookPayload struct {
+	Name    string `json:"name,omitempty"`
+	Mobile  string `json:"mobile,omitempty"`
+	Email   string `json:"email,omitempty"`
+	Subject string `json:"subject"`
+	Message string `json:"message"`
+}
+
+// Notify implements Notifier. Errors never include the message, which may carry a secret.
+func (n *WebhookNotifier) Notify(ctx context.Context, recipient Recipient, subject string, message string) error {
+	body, err := json.Marshal(webhookPayload{
+		Name:    recipient.Name,
+		Mobile:  recipient.Mobile,
+		Email:   recipient.Email,
+		Subject: subject,
+		Message: message,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if n.token != "" {
+		req.Header.Set("Authorization", "Bearer "+n.token)
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("notification webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("notification webhook returned %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+// Package payments defines the hook fee payments are charged through.
+package payments
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ErrDeclined is returned when the payment method was refused
+var ErrDeclined = errors.New("payment declined")
+
+// Charge is a request to collect money with a payment token created by the gateway's checkout
+type Charge struct {
+	Amount         int64  // in the smallest currency unit (paise)
+	Currency       string // ISO 4217, e.g. INR
+	Token          string // single-use payment method token from the client-side checkout
+	Description    string
+	IdempotencyKey string // retries with the same key are charged once
+}
+
+// Receipt is a successful charge
+type Receipt struct {
+	Gateway   string `json:"gateway" bson:"gateway"`
+	ChargeID  string `json:"chargeId" bson:"chargeId"`
+	Reference string `json:"reference,omitempty" bson:"reference,omitempty"` // shown to the payer, e.g. a UPI reference
+}
+
+// Gateway collects payments
+type Gateway interface {
+	Charge(ctx context.Context, charge Charge) (Receipt, error)
+}
+
+// NewGatewayFromEnv creates the gateway selected by PAYMENT_GATEWAY.
+// Only "stub" (the default) is available; it is meant for local development.
+func NewGatewayFromEnv() (Gateway, error) {
+	switch os.Getenv("PAYMENT_GATEWAY") {
+	case "", "stub":
+		return NewStubGateway(), nil
+	default:
+		return nil, fmt.Errorf("unsupported PAYMENT_GATEWAY %q", os.Getenv("PAYMENT_GATEWAY"))
+	}
+}
+
+// StubGateway stands in for a real gateway. It approves every token except those
+// containing "declined", so the failure path can be exercised without a provider.
+type StubGateway struct{}
+
+// NewStubGateway creates a StubGateway
+func NewStubGateway() *StubGateway {
+	return &StubGateway{}
+}
+
+// Charge implements Gateway
+func (g *StubGateway) Charge(ctx context.Context, charge Charge) (Receipt, error) {
+	if charge.Amount <= 0 {
+		return Receipt{}, fmt.Errorf("invalid amount %d", charge.Amount)
+	}
+	if charge.Token == "" || strings.Contains(charge.Token, "declined") {
+		return Receipt{}, ErrDeclined
+	}
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return Receipt{}, err
+	}
+	return Receipt{Gateway: "stub", ChargeID: "ch_" + hex.EncodeToString(id)}, nil
+}
@@ -0,0 +1,172 @@
+// Package pii protects personally identifiable information with application-level
+// envelope encryption.
+//
+// Each value is encrypted with its own random data key (AES-256-GCM), and the data
+// key is wrapped with a key-encryption key from a KeyProvider. Rotating the
+// key-encryption key only requires re-wrapping data keys, not re-encrypting values.
+// Values that need to be searched for equality get a blind index: an HMAC of the
+// normalized value under a separate index key.
+package pii
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+)
+
+// ErrDecrypt is returned when a value can't be decrypted, e.g. because it was tampered with
+var ErrDecrypt = errors.New("unable to decrypt value")
+
+// EncryptedValue is a single encrypted field as stored in the database
+type EncryptedValue struct {
+	KeyID      string `bson:"keyId"`
+	WrappedKey []byte `bson:"wrappedKey"` // nonce + data key sealed with the key-encryption key
+	Ciphertext []byte `bson:"ciphertext"` // nonce + value sealed with the data key
+}
+
+// Cipher encrypts and decrypts field values
+type Cipher struct {
+	keys KeyProvider
+}
+
+// NewCipher creates a Cipher using keys from provider
+func NewCipher(keys KeyProvider) *Cipher {
+	return &Cipher{keys: keys}
+}
+
+// Keys returns the cipher's key provider
+func (c *Cipher) Keys() KeyProvider {
+	return c.keys
+}
+
+// Encrypt encrypts plaintext for the named field. Empty values aren't encrypted and return nil.
+// The field name is bound to the ciphertext so values can't be swapped between fields.
+func (c *Cipher) Encrypt(field string, plaintext string) (*EncryptedValue, error) {
+	if plaintext == "" {
+		return nil, nil
+	}
+
+	dataKey := make([]byte, KeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, err
+	}
+	ciphertext, err := seal(dataKey, []byte(plaintext), []byte(field))
+	if err != nil {
+		return nil, err
+	}
+
+	keyID := c.keys.CurrentKeyID()
+	wrapped, err := c.wrap(keyID, dataKey)
+	if err != nil {
+		return nil, err
+	}
+	return &EncryptedValue{KeyID: keyID, WrappedKey: wrapped, Ciphertext: ciphertext}, nil
+}
+
+// Decrypt decrypts a value encrypted for the named field. A nil value decrypts to "".
+func (c *Cipher) Decrypt(field string, value *EncryptedValue) (string, error) {
+	if value == nil {
+		return "", nil
+	}
+	dataKey, err := c.unwrap(value)
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := open(dataKey, value.Ciphertext, []byte(field))
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// NeedsRewrap reports whether value's data key is wrapped with an old key-encryption key
+func (c *Cipher) NeedsRewrap(value *EncryptedValue) bool {
+	return value != nil && value.KeyID != c.keys.CurrentKeyID()
+}
+
+// Rewrap re-wraps value's data key with the current key-encryption key, leaving the ciphertext unchanged
+func (c *Cipher) Rewrap(value *EncryptedValue) error {
+	if !c.NeedsRewrap(value) {
+		return nil
+	}
+	dataKey, err := c.unwrap(value)
+	if err != nil {
+		return err
+	}
+	keyID := c.keys.CurrentKeyID()
+	wrapped, err := c.wrap(keyID, dataKey)
+	if err != nil {
+		return err
+	}
+	value.KeyID = keyID
+	value.WrappedKey = wrapped
+	return nil
+}
+
+// BlindIndex returns a deterministic keyed hash of a normalized value for equality lookups.
+// Empty values return "".
+func (c *Cipher) BlindIndex(field string, normalized string) string {
+	if normalized == "" {
+		return ""
+	}
+	mac := hmac.New(sha256.New, c.keys.IndexKey())
+	mac.Write([]byte(field + ":" + normalized))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (c *Cipher) wrap(keyID string, dataKey []byte) ([]byte, error) {
+	kek, err := c.keys.Key(keyID)
+	if err != nil {
+		return nil, err
+	}
+	return seal(kek, dataKey, []byte(keyID))
+}
+
+func (c *Cipher) unwrap(value *EncryptedValue) ([]byte, error) {
+	kek, err := c.keys.Key(value.KeyID)
+	if err != nil {
+		return nil, err
+	}
+	return open(kek, value.WrappedKey, []byte(value.KeyID))
+}
+
+// seal encrypts plaintext with AES-GCM, prefixing the random nonce
+func seal(key []byte, plaintext []byte, additionalData []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+// open decrypts data produced by seal
+func open(key []byte, data []byte, additionalData []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < gcm.NonceSize() {
+		return nil, ErrDecrypt
+	}
+	plaintext, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], additionalData)
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+	return plaintext, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,221 @@
+package pii
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// ErrUnknownKey is returned when a value was encrypted with a key the provider doesn't have
+var ErrUnknownKey = errors.New("unknown encryption key")
+
+// ErrNoKeyfile is returned when the keyfile doesn't exist. It is never created implicitly: fresh keys
+// can't read anything encrypted before, so a lost keyfile must stop the server rather than be replaced.
+var ErrNoKeyfile = errors.New("PII keyfile not found")
+
+// KeySize is the size of key-encryption and data-encryption keys (AES-256)
+const KeySize = 32
+
+// KeyProvider supplies the key-encryption keys (KEKs) used to wrap per-value data keys.
+// Old keys must stay available after rotation so existing values can still be read.
+type KeyProvider interface {
+	// CurrentKeyID returns the ID of the key new values are wrapped with
+	CurrentKeyID() string
+	// Key returns the key with the given ID
+	Key(id string) ([]byte, error)
+	// IndexKey returns the key used for blind indexes. It does not rotate, since
+	// changing it would break lookups of existing values.
+	IndexKey() []byte
+}
+
+// Rotator is implemented by key providers that can generate a new current key
+type Rotator interface {
+	Rotate() (string, error)
+}
+
+// keyFile is the on-disk format of a LocalKeyProvider
+type keyFile struct {
+	Current  string            `json:"current"`
+	Keys     map[string]string `json:"keys"` // key ID -> base64 key
+	IndexKey string            `json:"indexKey"`
+}
+
+// LocalKeyProvider reads keys from a JSON keyfile on local disk
+type LocalKeyProvider struct {
+	path string
+
+	mu       sync.RWMutex
+	current  string
+	keys     map[string][]byte
+	indexKey []byte
+}
+
+// NewKeyProviderFromEnv creates the key provider selected by PII_KEY_PROVIDER.
+// Only "local" (the default) is supported; it uses the keyfile at PII_KEYFILE.
+func NewKeyProviderFromEnv() (KeyProvider, error) {
+	switch os.Getenv("PII_KEY_PROVIDER") {
+	case "", "local":
+		return NewLocalKeyProvider(keyfileFromEnv())
+	default:
+		return nil, fmt.Errorf("unsupported PII_KEY_PROVIDER %q", os.Getenv("PII_KEY_PROVIDER"))
+	}
+}
+
+// InitKeyProviderFromEnv creates the keys for the provider selected by PII_KEY_PROVIDER. It is only
+// for setting up a new installation and refuses to replace existing keys.
+func InitKeyProviderFromEnv() (KeyProvider, error) {
+	switch os.Getenv("PII_KEY_PROVIDER") {
+	case "", "local":
+		return InitLocalKeyProvider(keyfileFromEnv())
+	default:
+		return nil, fmt.Errorf("unsupported PII_KEY_PROVIDER %q", os.Getenv("PII_KEY_PROVIDER"))
+	}
+}
+
+func keyfileFromEnv() string {
+	if path := os.Getenv("PII_KEYFILE"); path != "" {
+		return path
+	}
+	return "keys/pii-keys.json"
+}
+
+// InitLocalKeyProvider creates a keyfile with fresh keys at path. It fails if the keyfile already exists.
+func InitLocalKeyProvider(path string) (*LocalKeyProvider, error) {
+	if _, err := os.Stat(path); err == nil {
+		return nil, fmt.Errorf("keyfile %s already exists", path)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	p := &LocalKeyProvider{path: path, keys: make(map[string][]byte)}
+	var err error
+	if p.indexKey, err = randomKey(); err != nil {
+		return nil, err
+	}
+	if _, err := p.Rotate(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// NewLocalKeyProvider loads the keyfile at path. A missing keyfile returns ErrNoKeyfile; use
+// InitLocalKeyProvider to create one.
+func NewLocalKeyProvider(path string) (*LocalKeyProvider, error) {
+	p := &LocalKeyProvider{path: path}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w at %s", ErrNoKeyfile, path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var file keyFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parsing keyfile %s: %w", path, err)
+	}
+	p.current = file.Current
+	p.keys = make(map[string][]byte, len(file.Keys))
+	for id, encoded := range file.Keys {
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil || len(key) != KeySize {
+			return nil, fmt.Errorf("keyfile %s: key %q must be %d bytes of base64", path, id, KeySize)
+		}
+		p.keys[id] = key
+	}
+	if _, ok := p.keys[p.current]; !ok {
+		return nil, fmt.Errorf("keyfile %s: current key %q not found", path, p.current)
+	}
+	p.indexKey, err = base64.StdEncoding.DecodeString(file.IndexKey)
+	if err != nil || len(p.indexKey) != KeySize {
+		return nil, fmt.Errorf("keyfile %s: indexKey must be %d bytes of base64", path, KeySize)
+	}
+	return p, nil
+}
+
+// CurrentKeyID implements KeyProvider
+func (p *LocalKeyProvider) CurrentKeyID() string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.current
+}
+
+// Key implements KeyProvider
+func (p *LocalKeyProvider) Key(id string) ([]byte, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	key, ok := p.keys[id]
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+	return key, nil
+}
+
+// IndexKey implements KeyProvider
+func (p *LocalKeyProvider) IndexKey() []byte {
+	return p.indexKey
+}
+
+// Rotate adds a new key, makes it current and saves the keyfile. Previous keys are kept for decryption.
+func (p *LocalKeyProvider) Rotate() (string, error) {
+	key, err := randomKey()
+	if err != nil {
+		return "", err
+	}
+	id := time.Now().UTC().Format("20060102T150405.000Z")
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, exists := p.keys[id]; exists {
+		return "", fmt.Errorf("key %q already exists", id)
+	}
+	p.keys[id] = key
+	previous := p.current
+	p.current = id
+	if err := p.save(); err != nil {
+		delete(p.keys, id)
+		p.current = previous
+		return "", err
+	}
+	return id, nil
+}
+
+// save writes the keyfile atomically with owner-only permissions. The caller must hold p.mu.
+func (p *LocalKeyProvider) save() error {
+	file := keyFile{
+		Current:  p.current,
+		Keys:     make(map[string]string, len(p.keys)),
+		IndexKey: base64.StdEncoding.EncodeToString(p.indexKey),
+	}
+	for id, key := range p.keys {
+		file.Keys[id] = base64.StdEncoding.EncodeToString(key)
+	}
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(p.path), 0o700); err != nil {
+		return err
+	}
+	tmp := p.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, p.path)
+}
+
+func randomKey() ([]byte, error) {
+	key := make([]byte, KeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
@@ -0,0 +1,56 @@
+package pii
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewLocalKeyProviderRequiresKeyfile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys", "pii-keys.json")
+
+	if _, err := NewLocalKeyProvider(path); !errors.Is(err, ErrNoKeyfile) {
+		t.Fatalf("NewLocalKeyProvider() error = %v, want ErrNoKeyfile", err)
+	}
+	if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("keyfile was created without an explicit init (stat error %v)", err)
+	}
+}
+
+func TestInitLocalKeyProvider(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys", "pii-keys.json")
+
+	created, err := InitLocalKeyProvider(path)
+	if err != nil {
+		t.Fatalf("InitLocalKeyProvider() error = %v", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("keyfile not written: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("keyfile permissions = %o, want 600", perm)
+	}
+
+	loaded, err := NewLocalKeyProvider(path)
+	if err != nil {
+		t.Fatalf("NewLocalKeyProvider() error = %v", err)
+	}
+	if loaded.CurrentKeyID() != created.CurrentKeyID() {
+		t.Errorf("current key = %q, want %q", loaded.CurrentKeyID(), created.CurrentKeyID())
+	}
+	want, _ := created.Key(created.CurrentKeyID())
+	got, err := loaded.Key(loaded.CurrentKeyID())
+	if err != nil || !bytes.Equal(got, want) {
+		t.Errorf("loaded key differs from the created one (error %v)", err)
+	}
+	if !bytes.Equal(loaded.IndexKey(), created.IndexKey()) {
+		t.Error("loaded index key differs from the created one")
+	}
+
+	if _, err := InitLocalKeyProvider(path); err == nil {
+		t.Error("InitLocalKeyProvider() replaced an existing keyfile")
+	}
+}
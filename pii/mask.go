@@ -0,0 +1,42 @@
+package pii
+
+import "strings"
+
+// MaskAadhaar masks all but the last four digits of an Aadhaar number, e.g. XXXX-XXXX-1234
+func MaskAadhaar(aadhaarNo string) string {
+	if aadhaarNo == "" {
+		return ""
+	}
+	return "XXXX-XXXX-" + lastDigits(aadhaarNo, 4)
+}
+
+// MaskPhone masks all but the last four digits of a phone number, e.g. XXXXXX1234
+func MaskPhone(phone string) string {
+	if phone == "" {
+		return ""
+	}
+	return "XXXXXX" + lastDigits(phone, 4)
+}
+
+// MaskAddress hides an address completely
+func MaskAddress(address string) string {
+	if address == "" {
+		return ""
+	}
+	return "XXXX"
+}
+
+// lastDigits returns up to the last n digits of s
+func lastDigits(s string, n int) string {
+	var digits strings.Builder
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			digits.WriteRune(r)
+		}
+	}
+	d := digits.String()
+	if len(d) > n {
+		d = d[len(d)-n:]
+	}
+	return d
+}
@@ -0,0 +1,369 @@
+package qrcode
+
+import (
+	"bytes"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestReedSolomonKnownVectors(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want []byte
+	}{
+		{
+			// ISO/IEC 18004 annex I: "01234567" at 1-M
+			name: "01234567",
+			data: []byte{0x10, 0x20, 0x0C, 0x56, 0x61, 0x80, 0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11},
+			want: []byte{0xA5, 0x24, 0xD4, 0xC1, 0xED, 0x36, 0xC7, 0x87, 0x2C, 0x55},
+		},
+		{
+			// "HELLO WORLD" in alphanumeric mode at 1-M
+			name: "HELLO WORLD",
+			data: []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17},
+			want: []byte{196, 35, 39, 119, 235, 215, 231, 226, 93, 23},
+		},
+	}
+	for _, tt := range tests {
+		if got := reedSolomonRemainder(tt.data, reedSolomonDivisor(len(tt.want))); !bytes.Equal(got, tt.want) {
+			t.Errorf("%s: error correction codewords % X, want % X", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDataCodewords(t *testing.T) {
+	// Byte mode, a count of 2, "ab", the terminator, then alternating pad bytes
+	want := []byte{0x40, 0x26, 0x16, 0x20, 0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11}
+	if got := dataCodewords(1, []byte("ab")); !bytes.Equal(got, want) {
+		t.Errorf("dataCodewords = % X, want % X", got, want)
+	}
+}
+
+func TestVersionCapacity(t *testing.T) {
+	// Byte mode capacities at level M from ISO/IEC 18004 table 7
+	capacities := []int{0, 14, 26, 42, 62, 84, 106, 122, 152, 180, 213}
+	for version := 1; version <= MaxVersion; version++ {
+		if got := capacity(version); got != capacities[version] {
+			t.Errorf("version %d holds %d bytes, want %d", version, got, capacities[version])
+		}
+
+		c, err := Encode(bytes.Repeat([]byte("x"), capacities[version]))
+		if err != nil {
+			t.Fatalf("encoding %d bytes: %v", capacities[version], err)
+		}
+		if c.Version != version || c.Size != 17+4*version {
+			t.Errorf("%d bytes: version %d of size %d, want version %d", capacities[version], c.Version, c.Size, version)
+		}
+	}
+
+	if _, err := Encode(bytes.Repeat([]byte("x"), capacities[MaxVersion]+1)); !errors.Is(err, ErrTooLong) {
+		t.Errorf("encoding %d bytes: error %v, want ErrTooLong", capacities[MaxVersion]+1, err)
+	}
+}
+
+// formatBitsM are the 15-bit format strings for level M and masks 0 to 7, from ISO/IEC 18004 annex C
+var formatBitsM = []string{
+	"101010000010010",
+	"101000100100101",
+	"101111001111100",
+	"101101101001011",
+	"100010111111001",
+	"100000011001110",
+	"100111110010111",
+	"100101010100000",
+}
+
+// versionBits are the 18-bit version strings from ISO/IEC 18004 annex D
+var versionBits = map[int]int{7: 0x07C94, 8: 0x085BC, 9: 0x09A99, 10: 0x0A4D3}
+
+// readFormat reads both copies of the format information, least significant bit first
+func readFormat(c *Code) (int, int) {
+	var first, second int
+	set := func(value *int, i int, dark bool) {
+		if dark {
+			*value |= 1 << i
+		}
+	}
+	for i := 0; i <= 5; i++ {
+		set(&first, i, c.Dark(8, i))
+	}
+	set(&first, 6, c.Dark(8, 7))
+	set(&first, 7, c.Dark(8, 8))
+	set(&first, 8, c.Dark(7, 8))
+	for i := 9; i < 15; i++ {
+		set(&first, i, c.Dark(14-i, 8))
+	}
+	for i := 0; i < 8; i++ {
+		set(&second, i, c.Dark(c.Size-1-i, 8))
+	}
+	for i := 8; i < 15; i++ {
+		set(&second, i, c.Dark(8, c.Size-15+i))
+	}
+	return first, second
+}
+
+func TestFormatBits(t *testing.T) {
+	for mask, want := range formatBitsM {
+		c := &Code{Version: 1, Size: 21, modules: newGrid(21), function: newGrid(21)}
+		c.drawFormatBits(mask)
+
+		wantBits, _ := strconv.ParseInt(want, 2, 32)
+		first, second := readFormat(c)
+		if first != int(wantBits) || second != int(wantBits) {
+			t.Errorf("mask %d: format bits %015b and %015b, want %s", mask, first, second, want)
+		}
+		if !c.Dark(8, c.Size-8) {
+			t.Errorf("mask %d: the dark module is light", mask)
+		}
+	}
+}
+
+func TestVersionBits(t *testing.T) {
+	for version, want := range versionBits {
+		size := 17 + 4*version
+		c := &Code{Version: version, Size: size, modules: newGrid(size), function: newGrid(size)}
+		c.drawVersionBits()
+
+		var topRight, bottomLeft int
+		for i := 0; i < 18; i++ {
+			if c.Dark(size-11+i%3, i/3) {
+				topRight |= 1 << i
+			}
+			if c.Dark(i/3, size-11+i%3) {
+				bottomLeft |= 1 << i
+			}
+		}
+		if topRight != want || bottomLeft != want {
+			t.Errorf("version %d: version bits %018b and %018b, want %018b", version, topRight, bottomLeft, want)
+		}
+	}
+}
+
+// reserved marks the modules that hold function patterns, format and version information,
+// worked out from the standard rather than from the encoder
+func reserved(version int) [][]bool {
+	size := 17 + 4*version
+	grid := newGrid(size)
+	mark := func(x0, y0, w, h int) {
+		for y := y0; y < y0+h; y++ {
+			for x := x0; x < x0+w; x++ {
+				grid[y][x] = true
+			}
+		}
+	}
+	// Finders with separators and format areas
+	mark(0, 0, 9, 9)
+	mark(size-8, 0, 8, 9)
+	mark(0, size-8, 9, 8)
+	// Timing patterns
+	mark(6, 0, 1, size)
+	mark(0, 6, size, 1)
+	// Alignment patterns from ISO/IEC 18004 annex E, except where they'd overlap the finders
+	centres := map[int][]int{
+		2: {6, 18}, 3: {6, 22}, 4: {6, 26}, 5: {6, 30}, 6: {6, 34},
+		7: {6, 22, 38}, 8: {6, 24, 42}, 9: {6, 26, 46}, 10: {6, 28, 50},
+	}[version]
+	last := len(centres) - 1
+	for i, x := range centres {
+		for j, y := range centres {
+			finder := (i == 0 && j == 0) || (i == 0 && j == last) || (i == last && j == 0)
+			if !finder {
+				mark(x-2, y-2, 5, 5)
+			}
+		}
+	}
+	// Version information
+	if version >= 7 {
+		mark(size-11, 0, 3, 6)
+		mark(0, size-11, 6, 3)
+	}
+	return grid
+}
+
+func maskFlips(mask int, x int, y int) bool {
+	switch mask {
+	case 0:
+		return (y+x)%2 == 0
+	case 1:
+		return y%2 == 0
+	case 2:
+		return x%3 == 0
+	case 3:
+		return (y+x)%3 == 0
+	case 4:
+		return (y/2+x/3)%2 == 0
+	case 5:
+		return (y*x)%2+(y*x)%3 == 0
+	case 6:
+		return ((y*x)%2+(y*x)%3)%2 == 0
+	default:
+		return ((y+x)%2+(y*x)%3)%2 == 0
+	}
+}
+
+// decode reads a symbol back as a scanner would and returns the data it holds
+func decode(t *testing.T, c *Code) []byte {
+	t.Helper()
+	first, second := readFormat(c)
+	if first != second {
+		t.Fatalf("format copies differ: %015b and %015b", first, second)
+	}
+	mask := -1
+	for m, format := range formatBitsM {
+		if want, _ := strconv.ParseInt(format, 2, 32); int(want) == first {
+			mask = m
+		}
+	}
+	if mask < 0 {
+		t.Fatalf("format bits %015b are not level M", first)
+	}
+
+	// Read the data modules in the zigzag order, undoing the mask
+	function := reserved(c.Version)
+	var bits []bool
+	for right := c.Size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right--
+		}
+		for vertical := 0; vertical < c.Size; vertical++ {
+			y := vertical
+			if ((c.Size-1-right)/2)%2 == 0 {
+				y = c.Size - 1 - vertical // upward
+			}
+			for x := right; x >= right-1; x-- {
+				if !function[y][x] {
+					bits = append(bits, c.Dark(x, y) != maskFlips(mask, x, y))
+				}
+			}
+		}
+	}
+
+	layout := layouts[c.Version]
+	blocks := 0
+	for _, group := range layout.groups {
+		blocks += group[0]
+	}
+	total := totalDataCodewords(c.Version) + blocks*layout.ecPerBlock
+	if remainder := len(bits) - total*8; remainder < 0 || remainder > 7 {
+		t.Fatalf("%d data modules for %d codewords", len(bits), total)
+	}
+	codewords := make([]byte, total)
+	for i := range codewords {
+		for j := 0; j < 8; j++ {
+			if bits[i*8+j] {
+				codewords[i] |= 1 << (7 - j)
+			}
+		}
+	}
+
+	// De-interleave the blocks and check each one's error correction
+	var sizes []int
+	for _, group := range layout.groups {
+		for i := 0; i < group[0]; i++ {
+			sizes = append(sizes, group[1])
+		}
+	}
+	dataBlocks := make([][]byte, len(sizes))
+	next := 0
+	for i := 0; i < sizes[len(sizes)-1]; i++ {
+		for b, size := range sizes {
+			if i < size {
+				dataBlocks[b] = append(dataBlocks[b], codewords[next])
+				next++
+			}
+		}
+	}
+	ecBlocks := make([][]byte, len(sizes))
+	for i := 0; i < layout.ecPerBlock; i++ {
+		for b := range sizes {
+			ecBlocks[b] = append(ecBlocks[b], codewords[next])
+			next++
+		}
+	}
+	var data []byte
+	for b := range dataBlocks {
+		if want := reedSolomonRemainder(dataBlocks[b], reedSolomonDivisor(layout.ecPerBlock)); !bytes.Equal(ecBlocks[b], want) {
+			t.Errorf("block %d: error correction % X, want % X", b, ecBlocks[b], want)
+		}
+		data = append(data, dataBlocks[b]...)
+	}
+
+	// Parse the byte-mode segment
+	if data[0]>>4 != 0b0100 {
+		t.Fatalf("mode %04b, want byte mode", data[0]>>4)
+	}
+	stream := bitBuffer{}
+	for _, b := range data {
+		stream.append(int(b), 8)
+	}
+	read := func(from, n int) int {
+		value := 0
+		for _, set := range stream[from : from+n] {
+			value <<= 1
+			if set {
+				value |= 1
+			}
+		}
+		return value
+	}
+	count := read(4, countBits(c.Version))
+	payload := make([]byte, count)
+	for i := range payload {
+		payload[i] = byte(read(4+countBits(c.Version)+i*8, 8))
+	}
+	return payload
+}
+
+// checkFunctionPatterns checks the finders and timing patterns a scanner locates the symbol by
+func checkFunctionPatterns(t *testing.T, c *Code) {
+	t.Helper()
+	finder := []string{
+		"#######",
+		"#.....#",
+		"#.###.#",
+		"#.###.#",
+		"#.###.#",
+		"#.....#",
+		"#######",
+	}
+	for _, corner := range [][2]int{{0, 0}, {c.Size - 7, 0}, {0, c.Size - 7}} {
+		for dy, row := range finder {
+			for dx, module := range row {
+				if c.Dark(corner[0]+dx, corner[1]+dy) != (module == '#') {
+					t.Fatalf("finder at %v is wrong at %d,%d", corner, dx, dy)
+				}
+			}
+		}
+	}
+	for i := 8; i < c.Size-8; i++ {
+		if c.Dark(i, 6) != (i%2 == 0) || c.Dark(6, i) != (i%2 == 0) {
+			t.Fatalf("timing pattern is wrong at %d", i)
+		}
+	}
+}
+
+func TestEncodeRoundTrip(t *testing.T) {
+	inputs := []string{
+		"",
+		"a",
+		"https://example.com",
+		"CA1:k1:ZGF0YWRhdGFkYXRhZGF0YWRhdGFk:c2lnbmF0dXJlc2lnbmF0dQ",
+		strings.Repeat("0123456789", 8),
+		strings.Repeat("QR", 61),
+		strings.Repeat("\x00\xff", 75),
+		strings.Repeat("z", 213),
+	}
+	for _, input := range inputs {
+		c, err := Encode([]byte(input))
+		if err != nil {
+			t.Fatalf("Encode(%d bytes): %v", len(input), err)
+		}
+		checkFunctionPatterns(t, c)
+		if got := decode(t, c); string(got) != input {
+			t.Errorf("version %d: decoded %q, want %q", c.Version, got, input)
+		}
+	}
+}
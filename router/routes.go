@@ -3,6 +3,7 @@ package router
 import (
 	"log"
 	"net/http"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
@@ -11,15 +12,27 @@ import (
 	"cricketApp/db"
 	"cricketApp/handlers"
 	"cricketApp/middleware/authmiddleware"
+	"cricketApp/middleware/ratelimit"
+	"cricketApp/notification"
+	"cricketApp/payments"
+	"cricketApp/pii"
+	"cricketApp/storage"
+	"cricketApp/virusscan"
 )
 
-func SetupRouter(database db.Database, cricketerHandler *handlers.CricketerHandler) http.Handler {
+// Public registration endpoints allow each client IP this many requests per hour, in bursts of up to registrationBurst
+const (
+	registrationRequestsPerHour = 30
+	registrationBurst           = 10
+)
+
+func SetupRouter(database db.Database, cricketerHandler *handlers.CricketerHandler, blobStore storage.BlobStore, scanner virusscan.Scanner, piiKeys pii.KeyProvider, gateway payments.Gateway, notifier notification.Notifier, proxies ratelimit.TrustedProxies, secrets *handlers.Secrets) http.Handler {
 	r := chi.NewRouter()
 
 	// Add middleware
 	r.Use(middleware.Logger)         // Request logging
 	r.Use(middleware.Recoverer)      // Panic recovery
-	r.Use(middleware.RealIP)         // Get real IP
+	r.Use(proxies.RealIP)            // Get real IP, from trusted proxies only
 	r.Use(middleware.RequestID)      // Add request ID
 	r.Use(middleware.Heartbeat("/")) // Health check endpoint
 
@@ -48,13 +61,65 @@ func SetupRouter(database db.Database, cricketerHandler *handlers.CricketerHandl
 	})
 
 	// Create coach handler
-	coachHandler := handlers.NewCoachHandler(database)
+	coachHandler := handlers.NewCoachHandler(database, secrets.PhotoURL)
 
 	// Create session handler
 	sessionHandler := handlers.NewSessionHandler(database)
 
 	// Create registration handler
-	registrationHandler := handlers.NewRegistrationHandler(database)
+	registrationHandler := handlers.NewRegistrationHandler(database, blobStore, scanner, notifier, secrets.Registration)
+
+	// Create attachment handler
+	attachmentHandler := handlers.NewAttachmentHandler(database, blobStore, scanner, secrets.AttachmentURL)
+
+	// Create batch handler
+	batchHandler := handlers.NewBatchHandler(database)
+
+	// Create season handler
+	seasonHandler := handlers.NewSeasonHandler(database)
+
+	// Create guardian handler
+	guardianHandler := handlers.NewGuardianHandler(database, gateway)
+
+	// Create medical and consent handlers
+	medicalHandler := handlers.NewMedicalHandler(database)
+	consentHandler := handlers.NewConsentHandler(database)
+
+	// Create match handler
+	matchHandler := handlers.NewMatchHandler(database, notifier)
+
+	// Create assessment handler
+	assessmentHandler := handlers.NewAssessmentHandler(database)
+
+	// Create fitness handler
+	fitnessHandler := handlers.NewFitnessHandler(database, notifier)
+
+	// Create note handler
+	noteHandler := handlers.NewNoteHandler(database)
+
+	// Create workload handler
+	workloadHandler := handlers.NewWorkloadHandler(database, notifier)
+
+	// Create injury handler
+	injuryHandler := handlers.NewInjuryHandler(database)
+
+	// Create tournament handler
+	tournamentHandler := handlers.NewTournamentHandler(database, notifier)
+
+	// Create league handler
+	leagueHandler := handlers.NewLeagueHandler(database)
+
+	// Create ID card handler
+	idCardHandler := handlers.NewIDCardHandler(database, blobStore, piiKeys)
+
+	// Create photo handler
+	photoHandler := handlers.NewPhotoHandler(database, blobStore, notifier, secrets.PhotoURL)
+
+	// Create equipment handler
+	equipmentHandler := handlers.NewEquipmentHandler(database)
+
+	// Create security handler
+	securityHandler := handlers.NewSecurityHandler(database, piiKeys)
 
 	// Public routes
 	r.Group(func(r chi.Router) {
@@ -62,6 +127,11 @@ func SetupRouter(database db.Database, cricketerHandler *handlers.CricketerHandl
 		r.Post("/api/login", cricketerHandler.HandleCricketerLogin)   //done
 		r.Post("/api/admin/login", cricketerHandler.HandleAdminLogin) //done
 		r.Post("/api/coach/login", coachHandler.HandleCoachLogin)     //done
+		r.Post("/api/guardian/login", guardianHandler.HandleGuardianLogin)
+
+		// Signed, time-limited download links
+		r.Get("/api/attachments/{attachmentId}/download", attachmentHandler.DownloadAttachment)
+		r.Get("/api/photos/{id}/{rendition}", photoHandler.ServePhoto)
 	})
 
 	// Protected routes
@@ -76,6 +146,19 @@ func SetupRouter(database db.Database, cricketerHandler *handlers.CricketerHandl
 				r.Get("/profile", cricketerHandler.GetCricketerProfile)    //done
 				r.Put("/profile", cricketerHandler.UpdateCricketerProfile) //done
 				r.Get("/announcement", cricketerHandler.GetAnnouncements)
+				r.Post("/announcement/{id}/read", cricketerHandler.MarkAnnouncementRead)
+				r.Post("/announcement/{id}/acknowledge", cricketerHandler.AcknowledgeAnnouncement)
+				r.Get("/announcement/{id}/attachments/{attachmentId}", attachmentHandler.GetAnnouncementAttachmentURL)
+				r.Get("/consents", cricketerHandler.GetConsents)
+				r.Post("/consents/{documentId}/sign", cricketerHandler.SignConsent)
+				r.Get("/notes", cricketerHandler.GetSharedNotes)
+				r.Get("/availability", cricketerHandler.GetAvailabilityRequests)
+				r.Put("/availability/{tournamentId}", cricketerHandler.RespondToAvailability)
+				r.Get("/id-card", idCardHandler.GetOwnIDCard)
+				r.Get("/photo", photoHandler.GetOwnPhoto)
+				r.Put("/photo", photoHandler.UploadOwnPhoto)
+				r.Delete("/photo", photoHandler.DeleteOwnPhoto)
+				r.Get("/equipment", equipmentHandler.GetOwnEquipment)
 			})
 		})
 
@@ -85,6 +168,100 @@ func SetupRouter(database db.Database, cricketerHandler *handlers.CricketerHandl
 
 			r.Route("/api/coach", func(r chi.Router) {
 				r.Get("/profile", coachHandler.GetCoachProfile) //done
+				r.Get("/batches", batchHandler.GetCoachBatches)
+
+				r.Get("/announcements", cricketerHandler.ListAnnouncements)
+				r.Post("/announcements", cricketerHandler.CreateAnnouncement)
+				r.Put("/announcements/{id}", cricketerHandler.UpdateAnnouncement)
+				r.Delete("/announcements/{id}", cricketerHandler.DeleteAnnouncement)
+				r.Post("/attachments", attachmentHandler.UploadAttachment)
+				r.Get("/sessions/{id}/attendance", sessionHandler.GetSessionAttendance)
+				r.Put("/sessions/{id}/attendance", sessionHandler.MarkAttendance)
+				r.Get("/sessions/{id}/emergency-cards", medicalHandler.GetSessionEmergencyCards)
+
+				r.Post("/matches", matchHandler.CreateMatch)
+				r.Get("/matches", matchHandler.GetMatches)
+				r.Get("/matches/{id}", matchHandler.GetMatch)
+				r.Put("/matches/{id}", matchHandler.UpdateMatch)
+				r.Delete("/matches/{id}", matchHandler.DeleteMatch)
+				r.Get("/matches/{id}/live", matchHandler.GetLiveScore)
+				r.Get("/matches/{id}/balls", matchHandler.GetBallEvents)
+				r.Post("/matches/{id}/balls", matchHandler.RecordBallEvent)
+				r.Post("/matches/{id}/balls/undo", matchHandler.UndoBallEvent)
+				r.Get("/cricketers/{id}/stats", matchHandler.GetCricketerStats)
+				r.Get("/assessment-templates", assessmentHandler.GetAssessmentTemplates)
+				r.Post("/assessments", assessmentHandler.CreateAssessment)
+				r.Put("/assessments/{id}", assessmentHandler.UpdateAssessment)
+				r.Get("/cricketers/{id}/assessments", assessmentHandler.GetCricketerAssessments)
+				r.Get("/cricketers/{id}/assessments/progress", assessmentHandler.GetAssessmentProgress)
+				r.Get("/cricketers/{id}/term-report", assessmentHandler.GetTermReport)
+				r.Get("/fitness-tests", fitnessHandler.GetFitnessTests)
+				r.Get("/fitness-tests/{id}/leaderboard", fitnessHandler.GetFitnessLeaderboard)
+				r.Post("/batches/{id}/fitness-results", fitnessHandler.RecordBatchFitnessResults)
+				r.Get("/cricketers/{id}/fitness", fitnessHandler.GetCricketerFitness)
+				r.Get("/fitness-alerts", fitnessHandler.GetFitnessAlerts)
+				r.Post("/fitness-alerts/{id}/acknowledge", fitnessHandler.AcknowledgeFitnessAlert)
+				r.Post("/cricketers/{id}/notes", noteHandler.CreateNote)
+				r.Get("/cricketers/{id}/notes", noteHandler.GetCricketerNotes)
+				r.Put("/notes/{id}", noteHandler.UpdateNote)
+				r.Delete("/notes/{id}", noteHandler.DeleteNote)
+				r.Post("/sessions/{id}/deliveries", workloadHandler.RecordSessionDeliveries)
+				r.Get("/sessions/{id}/deliveries", workloadHandler.GetSessionDeliveries)
+				r.Get("/cricketers/{id}/workload", workloadHandler.GetCricketerWorkload)
+				r.Get("/batches/{id}/workload", workloadHandler.GetBatchWorkload)
+				r.Get("/workload-limits", workloadHandler.GetWorkloadLimits)
+				r.Get("/workload-alerts", workloadHandler.GetWorkloadAlerts)
+				r.Post("/workload-alerts/{id}/acknowledge", workloadHandler.AcknowledgeWorkloadAlert)
+				r.Post("/cricketers/{id}/injuries", injuryHandler.CreateInjury)
+				r.Get("/cricketers/{id}/injuries", injuryHandler.GetCricketerInjuries)
+				r.Get("/injuries", injuryHandler.GetInjuries)
+				r.Get("/injuries/{id}", injuryHandler.GetInjury)
+				r.Put("/injuries/{id}/status", injuryHandler.UpdateInjuryStatus)
+				r.Get("/tournaments", tournamentHandler.GetTournaments)
+				r.Get("/tournaments/{id}", tournamentHandler.GetTournament)
+				r.Post("/tournaments/{id}/availability", tournamentHandler.PollAvailability)
+				r.Get("/tournaments/{id}/candidates", tournamentHandler.GetTournamentCandidates)
+				r.Put("/tournaments/{id}/squad", tournamentHandler.SelectSquad)
+				r.Put("/fixtures/{id}/playing-xi", tournamentHandler.SelectPlayingXI)
+				r.Get("/leagues", leagueHandler.GetLeagues)
+				r.Get("/leagues/{id}", leagueHandler.GetLeague)
+				r.Get("/leagues/{id}/fixtures", leagueHandler.GetLeagueFixtures)
+				r.Get("/leagues/{id}/table", leagueHandler.GetPointsTable)
+				r.Put("/league-fixtures/{id}/result", leagueHandler.RecordLeagueResult)
+				r.Get("/cricketers/{id}/id-card", idCardHandler.GetIDCard)
+				r.Post("/sessions/{id}/check-in", idCardHandler.CheckIn)
+				r.Get("/photo", photoHandler.GetOwnPhoto)
+				r.Put("/photo", photoHandler.UploadOwnPhoto)
+				r.Delete("/photo", photoHandler.DeleteOwnPhoto)
+				r.Put("/cricketers/{id}/photo", photoHandler.UploadCricketerPhoto)
+				r.Get("/equipment", equipmentHandler.GetEquipmentItems)
+				r.Post("/equipment/issues", equipmentHandler.IssueEquipment)
+				r.Get("/equipment/issues", equipmentHandler.GetEquipmentIssues)
+				r.Post("/equipment/issues/{id}/return", equipmentHandler.ReturnEquipment)
+				r.Get("/sessions/{id}/equipment", equipmentHandler.GetSessionEquipment)
+				r.Get("/cricketers/{id}/equipment", equipmentHandler.GetCricketerEquipment)
+			})
+		})
+
+		// Guardian routes
+		r.Group(func(r chi.Router) {
+			r.Use(authmiddleware.Authorizer("guardian"))
+
+			r.Route("/api/guardian", func(r chi.Router) {
+				r.Get("/profile", guardianHandler.GetGuardianProfile)
+				r.Get("/dashboard", guardianHandler.GetGuardianDashboard)
+				r.Get("/children/{cricketerId}/fees", guardianHandler.GetChildFeePayments)
+				r.Post("/children/{cricketerId}/fees/pay", guardianHandler.PayChildFee)
+				r.Post("/children/{cricketerId}/announcements/{id}/acknowledge", guardianHandler.AcknowledgeChildAnnouncement)
+				r.Get("/children/{cricketerId}/medical", guardianHandler.GetChildMedicalProfile)
+				r.Put("/children/{cricketerId}/medical", guardianHandler.UpdateChildMedicalProfile)
+				r.Get("/children/{cricketerId}/consents", guardianHandler.GetChildConsents)
+				r.Post("/children/{cricketerId}/consents/{documentId}/sign", guardianHandler.SignChildConsent)
+				r.Get("/children/{cricketerId}/assessments/progress", guardianHandler.GetChildAssessmentProgress)
+				r.Get("/children/{cricketerId}/term-report", guardianHandler.GetChildTermReport)
+				r.Get("/children/{cricketerId}/notes", guardianHandler.GetChildNotes)
+				r.Get("/children/{cricketerId}/availability", guardianHandler.GetChildAvailability)
+				r.Put("/children/{cricketerId}/availability/{tournamentId}", guardianHandler.RespondToChildAvailability)
 			})
 		})
 
@@ -92,20 +269,148 @@ func SetupRouter(database db.Database, cricketerHandler *handlers.CricketerHandl
 		r.Route("/api/admin", func(r chi.Router) {
 			r.Use(authmiddleware.Authorizer("admin"))
 
-			r.Get("/cricketers", cricketerHandler.GetAllCricketers)       //done
+			r.Get("/cricketers", cricketerHandler.GetAllCricketers) //done
+			r.Get("/announcements", cricketerHandler.ListAnnouncements)
 			r.Post("/announcements", cricketerHandler.CreateAnnouncement) //done
+			r.Put("/announcements/{id}", cricketerHandler.UpdateAnnouncement)
+			r.Delete("/announcements/{id}", cricketerHandler.DeleteAnnouncement)
+			r.Get("/announcements/{id}/receipts", cricketerHandler.GetAnnouncementReceipts)
+			r.Post("/announcements/{id}/renotify", cricketerHandler.RenotifyAnnouncement)
+			r.Post("/attachments", attachmentHandler.UploadAttachment)
+			r.Get("/attachments/{attachmentId}/url", attachmentHandler.GetAttachmentURL)
 			r.Put("/cricketers/{id}/joining-date", cricketerHandler.UpdateCricketerJoiningDate)
 			r.Put("/cricketers/{id}/inactive-status", cricketerHandler.UpdateCricketerInactiveStatus)
+			r.Put("/cricketers/{id}/batch", batchHandler.AssignCricketerBatch)
+			r.Put("/cricketers/{id}/date-of-birth", cricketerHandler.UpdateCricketerDateOfBirth)
+			r.Put("/cricketers/{id}/gender", cricketerHandler.UpdateCricketerGender)
+			r.Get("/cricketers/{id}/eligibility", seasonHandler.CheckCricketerEligibility)
+			r.Post("/seasons", seasonHandler.CreateSeason)
+			r.Get("/seasons", seasonHandler.GetAllSeasons)
+			r.Put("/seasons/{id}", seasonHandler.UpdateSeason)
+			r.Get("/seasons/category-changes", seasonHandler.GetAgeCategoryChanges)
+			r.Post("/batches", batchHandler.CreateBatch)
+			r.Get("/batches", batchHandler.GetAllBatches)
+			r.Put("/batches/{id}", batchHandler.UpdateBatch)
 			r.Post("/coach", coachHandler.CreateCoach)
 			r.Get("/coaches", coachHandler.GetAllCoaches)
 			r.Put("/coach", coachHandler.UpdateCoach)
 
 			r.Post("/session", sessionHandler.CreateSession)
-			r.Put("/session{id}", sessionHandler.UpdateSession)
+			r.Put("/session/{id}", sessionHandler.UpdateSession)
 			r.Delete("/session/{id}", sessionHandler.DeleteSession)
+			r.Get("/session/{id}/attendance", sessionHandler.GetSessionAttendance)
+			r.Put("/session/{id}/attendance", sessionHandler.MarkAttendance)
+			r.Get("/session/{id}/emergency-cards", medicalHandler.GetSessionEmergencyCards)
+
+			r.Get("/cricketers/{id}/medical", medicalHandler.GetMedicalProfile)
+			r.Put("/cricketers/{id}/medical", medicalHandler.UpdateMedicalProfile)
+			r.Get("/cricketers/{id}/consents", consentHandler.GetCricketerConsents)
+			r.Post("/consent-documents", consentHandler.PublishConsentDocument)
+			r.Get("/consent-documents", consentHandler.GetConsentDocuments)
+			r.Get("/consents/outstanding", consentHandler.GetOutstandingConsents)
+
+			r.Post("/matches", matchHandler.CreateMatch)
+			r.Get("/matches", matchHandler.GetMatches)
+			r.Get("/matches/{id}", matchHandler.GetMatch)
+			r.Put("/matches/{id}", matchHandler.UpdateMatch)
+			r.Delete("/matches/{id}", matchHandler.DeleteMatch)
+			r.Get("/matches/{id}/live", matchHandler.GetLiveScore)
+			r.Get("/matches/{id}/balls", matchHandler.GetBallEvents)
+			r.Post("/matches/{id}/balls", matchHandler.RecordBallEvent)
+			r.Post("/matches/{id}/balls/undo", matchHandler.UndoBallEvent)
+			r.Get("/cricketers/{id}/stats", matchHandler.GetCricketerStats)
+
+			r.Post("/assessment-templates", assessmentHandler.CreateAssessmentTemplate)
+			r.Get("/assessment-templates", assessmentHandler.GetAssessmentTemplates)
+			r.Put("/assessment-templates/{id}", assessmentHandler.UpdateAssessmentTemplate)
+			r.Post("/assessments", assessmentHandler.CreateAssessment)
+			r.Put("/assessments/{id}", assessmentHandler.UpdateAssessment)
+			r.Get("/cricketers/{id}/assessments", assessmentHandler.GetCricketerAssessments)
+			r.Get("/cricketers/{id}/assessments/progress", assessmentHandler.GetAssessmentProgress)
+			r.Get("/cricketers/{id}/term-report", assessmentHandler.GetTermReport)
+
+			r.Post("/fitness-tests", fitnessHandler.CreateFitnessTest)
+			r.Get("/fitness-tests", fitnessHandler.GetFitnessTests)
+			r.Put("/fitness-tests/{id}", fitnessHandler.UpdateFitnessTest)
+			r.Get("/fitness-tests/{id}/leaderboard", fitnessHandler.GetFitnessLeaderboard)
+			r.Post("/batches/{id}/fitness-results", fitnessHandler.RecordBatchFitnessResults)
+			r.Get("/cricketers/{id}/fitness", fitnessHandler.GetCricketerFitness)
+			r.Get("/fitness-alerts", fitnessHandler.GetFitnessAlerts)
+			r.Post("/fitness-alerts/{id}/acknowledge", fitnessHandler.AcknowledgeFitnessAlert)
+
+			r.Post("/cricketers/{id}/notes", noteHandler.CreateNote)
+			r.Get("/cricketers/{id}/notes", noteHandler.GetCricketerNotes)
+			r.Put("/notes/{id}", noteHandler.UpdateNote)
+			r.Delete("/notes/{id}", noteHandler.DeleteNote)
+
+			r.Post("/sessions/{id}/deliveries", workloadHandler.RecordSessionDeliveries)
+			r.Get("/sessions/{id}/deliveries", workloadHandler.GetSessionDeliveries)
+			r.Get("/cricketers/{id}/workload", workloadHandler.GetCricketerWorkload)
+			r.Get("/batches/{id}/workload", workloadHandler.GetBatchWorkload)
+			r.Get("/workload-limits", workloadHandler.GetWorkloadLimits)
+			r.Put("/workload-limits/{ageCategory}", workloadHandler.UpdateWorkloadLimit)
+			r.Get("/workload-alerts", workloadHandler.GetWorkloadAlerts)
+			r.Post("/workload-alerts/{id}/acknowledge", workloadHandler.AcknowledgeWorkloadAlert)
+
+			r.Post("/cricketers/{id}/injuries", injuryHandler.CreateInjury)
+			r.Get("/cricketers/{id}/injuries", injuryHandler.GetCricketerInjuries)
+			r.Get("/injuries", injuryHandler.GetInjuries)
+			r.Get("/injuries/report", injuryHandler.GetInjuryReport)
+			r.Get("/injuries/{id}", injuryHandler.GetInjury)
+			r.Put("/injuries/{id}/status", injuryHandler.UpdateInjuryStatus)
+
+			r.Post("/tournaments", tournamentHandler.CreateTournament)
+			r.Get("/tournaments", tournamentHandler.GetTournaments)
+			r.Get("/tournaments/{id}", tournamentHandler.GetTournament)
+			r.Put("/tournaments/{id}", tournamentHandler.UpdateTournament)
+			r.Post("/tournaments/{id}/fixtures", tournamentHandler.CreateFixture)
+			r.Put("/fixtures/{id}", tournamentHandler.UpdateFixture)
+			r.Post("/tournaments/{id}/availability", tournamentHandler.PollAvailability)
+			r.Get("/tournaments/{id}/candidates", tournamentHandler.GetTournamentCandidates)
+			r.Put("/tournaments/{id}/squad", tournamentHandler.SelectSquad)
+			r.Put("/fixtures/{id}/playing-xi", tournamentHandler.SelectPlayingXI)
+
+			r.Post("/leagues", leagueHandler.CreateLeague)
+			r.Get("/leagues", leagueHandler.GetLeagues)
+			r.Get("/leagues/{id}", leagueHandler.GetLeague)
+			r.Post("/leagues/{id}/fixtures/generate", leagueHandler.GenerateLeagueFixtures)
+			r.Get("/leagues/{id}/fixtures", leagueHandler.GetLeagueFixtures)
+			r.Get("/leagues/{id}/table", leagueHandler.GetPointsTable)
+			r.Post("/leagues/{id}/knockout", leagueHandler.GenerateKnockout)
+			r.Put("/league-fixtures/{id}/result", leagueHandler.RecordLeagueResult)
 
+			r.Get("/cricketers/{id}/id-card", idCardHandler.GetIDCard)
+			r.Post("/cricketers/{id}/id-card/reissue", idCardHandler.ReissueIDCard)
+
+			r.Put("/cricketers/{id}/photo", photoHandler.UploadCricketerPhoto)
+			r.Put("/coaches/{id}/photo", photoHandler.UploadCoachPhoto)
+			r.Get("/photos", photoHandler.ListPhotos)
+			r.Post("/photos/{id}/reject", photoHandler.RejectPhoto)
+
+			r.Post("/equipment", equipmentHandler.CreateEquipmentItem)
+			r.Get("/equipment", equipmentHandler.GetEquipmentItems)
+			r.Get("/equipment/{id}", equipmentHandler.GetEquipmentItem)
+			r.Put("/equipment/{id}", equipmentHandler.UpdateEquipmentItem)
+			r.Post("/equipment/{id}/stock", equipmentHandler.AdjustEquipmentStock)
+			r.Get("/equipment/{id}/history", equipmentHandler.GetEquipmentItemHistory)
+			r.Post("/equipment/issues", equipmentHandler.IssueEquipment)
+			r.Get("/equipment/issues", equipmentHandler.GetEquipmentIssues)
+			r.Post("/equipment/issues/{id}/return", equipmentHandler.ReturnEquipment)
+			r.Get("/sessions/{id}/equipment", equipmentHandler.GetSessionEquipment)
+			r.Get("/cricketers/{id}/equipment", equipmentHandler.GetCricketerEquipment)
+
+			r.Post("/guardians", guardianHandler.CreateGuardian)
+			r.Get("/guardians", guardianHandler.GetAllGuardians)
+			r.Put("/guardians/{id}", guardianHandler.UpdateGuardian)
+
+			r.Put("/admins/{id}/permissions", securityHandler.UpdateAdminPermissions)
+			r.Get("/audit", securityHandler.GetAuditLog)
+			r.Post("/pii/rotate-key", securityHandler.RotatePIIKey)
 		})
 
+		// Current season and age categories, for any logged-in user
+		r.Get("/api/seasons/current", seasonHandler.GetCurrentSeason)
+
 		// Session routes
 		r.Route("/api/sessions", func(r chi.Router) {
 			r.Get("/sessions/coach/{coachId}", sessionHandler.GetSessionsByCoach)
@@ -113,19 +418,39 @@ func SetupRouter(database db.Database, cricketerHandler *handlers.CricketerHandl
 			r.Get("/", sessionHandler.GetAllSessions)
 
 		})
+	})
 
-		// Registration routes
-		r.Route("/api/registrations", func(r chi.Router) {
-			// Public routes
+	// Registrations are submitted by the public and reviewed by admins
+	registrationLimiter := ratelimit.NewLimiter(registrationRequestsPerHour, time.Hour, registrationBurst)
+	r.Route("/api/registrations", func(r chi.Router) {
+		// Public routes, rate limited per client IP
+		r.Group(func(r chi.Router) {
+			r.Use(registrationLimiter.Middleware)
+			r.Get("/challenge", registrationHandler.GetRegistrationChallenge)
+			r.Post("/verify/start", registrationHandler.StartVerification)
+			r.Post("/verify/confirm", registrationHandler.ConfirmVerification)
+			r.Post("/password-setup", registrationHandler.CompletePasswordSetup)
 			r.Post("/", registrationHandler.CreateRegistration)
+			r.Get("/{id}/applicant-documents", registrationHandler.ListApplicantDocuments)
+			r.Put("/{id}/applicant-documents/{type}", registrationHandler.UploadApplicantDocument)
+		})
 
-			// Protected routes (admin only)
-			r.Group(func(r chi.Router) {
-				r.Use(authmiddleware.Authorizer("admin"))
-				r.Get("/", registrationHandler.GetAllRegistrations)
-				r.Get("/{id}", registrationHandler.GetRegistration)
-				r.Put("/{id}", registrationHandler.UpdateRegistration)
-			})
+		// Protected routes (admin only)
+		r.Group(func(r chi.Router) {
+			r.Use(authmiddleware.Authenticator)
+			r.Use(authmiddleware.Authorizer("admin"))
+			r.Get("/", registrationHandler.GetAllRegistrations)
+			r.Get("/export", registrationHandler.ExportRegistrations)
+			r.Get("/duplicates", registrationHandler.GetDuplicateFormNumbers)
+			r.Get("/{id}", registrationHandler.GetRegistration)
+			r.Put("/{id}", registrationHandler.UpdateRegistration)
+			r.Post("/{id}/status", registrationHandler.TransitionRegistration)
+			r.Post("/{id}/password-setup", registrationHandler.ResendPasswordSetup)
+			r.Post("/{id}/reveal", registrationHandler.RevealRegistration)
+			r.Get("/{id}/documents", registrationHandler.ListRegistrationDocuments)
+			r.Put("/{id}/documents/{type}", registrationHandler.UploadRegistrationDocument)
+			r.Get("/{id}/documents/{type}/download", registrationHandler.DownloadRegistrationDocument)
+			r.Post("/{id}/documents/{type}/review", registrationHandler.ReviewRegistrationDocument)
 		})
 	})
 
@@ -0,0 +1,158 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"cricketApp/db"
+	"cricketApp/models"
+	"cricketApp/notification"
+)
+
+// equipmentRemindEvery is how long to wait before reminding the holder of overdue kit again
+const equipmentRemindEvery = 20 * time.Hour
+
+// EquipmentScheduler chases kit that is overdue back and warns when items run low in store
+type EquipmentScheduler struct {
+	db       db.Database
+	notifier notification.Notifier
+	manager  notification.Recipient
+}
+
+// NewEquipmentScheduler creates an EquipmentScheduler. Low-stock alerts go to the equipment manager
+// named by EQUIPMENT_MANAGER_NAME, EQUIPMENT_MANAGER_MOBILE and EQUIPMENT_MANAGER_EMAIL, and are
+// only logged if none is set.
+func NewEquipmentScheduler(db db.Database, notifier notification.Notifier) *EquipmentScheduler {
+	return &EquipmentScheduler{
+		db:       db,
+		notifier: notifier,
+		manager: notification.Recipient{
+			Name:   os.Getenv("EQUIPMENT_MANAGER_NAME"),
+			Mobile: os.Getenv("EQUIPMENT_MANAGER_MOBILE"),
+			Email:  os.Getenv("EQUIPMENT_MANAGER_EMAIL"),
+		},
+	}
+}
+
+func (s *EquipmentScheduler) Start() {
+	// Run immediately on start
+	go s.checkEquipment()
+
+	// Schedule to run daily at 9 AM
+	for {
+		now := time.Now()
+		next := time.Date(now.Year(), now.Month(), now.Day(), 9, 0, 0, 0, now.Location())
+		if now.After(next) {
+			next = next.Add(24 * time.Hour)
+		}
+		time.Sleep(next.Sub(now))
+		s.checkEquipment()
+	}
+}
+
+func (s *EquipmentScheduler) checkEquipment() {
+	ctx := context.Background()
+	s.remindOverdue(ctx)
+	s.warnLowStock(ctx)
+}
+
+// remindOverdue tells whoever holds overdue kit to bring it back: the cricketer it was issued to,
+// or the coach who ran the session it was issued for
+func (s *EquipmentScheduler) remindOverdue(ctx context.Context) {
+	now := time.Now()
+	issues, err := s.db.GetEquipmentIssues(ctx, models.EquipmentIssueFilter{OutstandingOnly: true, DueBefore: &now})
+	if err != nil {
+		log.Printf("Error fetching overdue equipment: %v", err)
+		return
+	}
+
+	reminded := []primitive.ObjectID{}
+	for _, issue := range issues {
+		if issue.RemindedAt != nil && now.Sub(*issue.RemindedAt) < equipmentRemindEvery {
+			continue
+		}
+
+		recipient, ok := s.holder(ctx, issue)
+		if !ok {
+			continue
+		}
+		message := fmt.Sprintf("%d x %s", issue.Quantity, issue.ItemName)
+		if issue.Size != "" {
+			message += " (" + issue.Size + ")"
+		}
+		message += fmt.Sprintf(" was due back on %s. Please return it to the academy.", issue.DueAt.Format("2 Jan 2006"))
+		if err := s.notifier.Notify(ctx, recipient, "Equipment overdue", message); err != nil {
+			log.Printf("Error sending overdue equipment reminder for issue %s: %v", issue.ID.Hex(), err)
+			continue
+		}
+		reminded = append(reminded, issue.ID)
+	}
+
+	if err := s.db.MarkEquipmentIssuesReminded(ctx, reminded, now); err != nil {
+		log.Printf("Error recording equipment reminders: %v", err)
+	}
+}
+
+// holder finds who to chase for an issue
+func (s *EquipmentScheduler) holder(ctx context.Context, issue models.EquipmentIssue) (notification.Recipient, bool) {
+	if issue.CricketerID != nil {
+		cricketer, err := s.db.GetCricketerByID(ctx, *issue.CricketerID)
+		if err != nil {
+			log.Printf("Error fetching cricketer %s for equipment reminder: %v", issue.CricketerID.Hex(), err)
+			return notification.Recipient{}, false
+		}
+		return notification.Recipient{Name: cricketer.Name, Mobile: cricketer.Mobile, Email: cricketer.Email}, true
+	}
+	if issue.SessionID == nil {
+		return notification.Recipient{}, false
+	}
+
+	session, err := s.db.GetSessionByID(ctx, *issue.SessionID)
+	if err != nil {
+		log.Printf("Error fetching session %s for equipment reminder: %v", issue.SessionID.Hex(), err)
+		return notification.Recipient{}, false
+	}
+	coach, err := s.db.GetCoachByID(ctx, session.CoachID)
+	if err != nil {
+		log.Printf("Error fetching coach %s for equipment reminder: %v", session.CoachID.Hex(), err)
+		return notification.Recipient{}, false
+	}
+	return notification.Recipient{Name: coach.Name, Mobile: coach.Mobile}, true
+}
+
+// warnLowStock lists the items at or below their low-stock threshold for the equipment manager
+func (s *EquipmentScheduler) warnLowStock(ctx context.Context) {
+	items, err := s.db.GetEquipmentItems(ctx, models.EquipmentFilter{})
+	if err != nil {
+		log.Printf("Error fetching equipment for low-stock check: %v", err)
+		return
+	}
+
+	message := ""
+	for _, item := range items {
+		if !item.IsLowStock() {
+			continue
+		}
+		name := item.Name
+		if item.Size != "" {
+			name += " (" + item.Size + ")"
+		}
+		message += fmt.Sprintf("%s: %d left in %s, threshold %d\n", name, item.Available, item.Location, item.LowStockThreshold)
+	}
+	if message == "" {
+		return
+	}
+
+	if s.manager.Mobile == "" && s.manager.Email == "" {
+		log.Printf("Equipment running low:\n%s", message)
+		return
+	}
+	if err := s.notifier.Notify(ctx, s.manager, "Equipment running low", message); err != nil {
+		log.Printf("Error sending low-stock alert: %v", err)
+	}
+}
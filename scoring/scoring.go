@@ -0,0 +1,661 @@
+// Package scoring derives a live scorecard from a match's ball-by-ball event log.
+//
+// The log is append-only: an undo is itself an event that cancels the latest event still in
+// effect. A Scorer replays the events in effect and checks each new one against the laws of the
+// game as they apply to limited-overs cricket: six legal balls an over, no bowler bowling
+// consecutive overs or more than a fifth of the innings, strike changing on odd runs and at the
+// end of each over, and the innings ending when the batting side is all out, the overs are
+// used up or the target is reached.
+//
+// Two simplifications: a run-out batter is replaced at the end they were at after the runs
+// completed on that ball, and a new batter after a catch takes strike (the post-2022 law).
+package scoring
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"cricketApp/models"
+)
+
+// RuleError is an event that doesn't fit the state of the match. Field is the request field at fault.
+type RuleError struct {
+	Field   string
+	Code    string
+	Message string
+}
+
+func (e *RuleError) Error() string {
+	return e.Message
+}
+
+func ruleError(field string, code string, format string, args ...interface{}) *RuleError {
+	return &RuleError{Field: field, Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// MaxBowlerOvers is the most overs one bowler may bowl in an innings: a fifth of the overs,
+// rounded up, e.g. 4 in a T20 and 10 in an ODI
+func MaxBowlerOvers(oversPerInnings int) int {
+	return (oversPerInnings + 4) / 5
+}
+
+// dismissalsOnExtra lists the dismissals possible off each kind of delivery
+var dismissalsOnExtra = map[string][]string{
+	"":                 {models.DismissalBowled, models.DismissalCaught, models.DismissalLBW, models.DismissalStumped, models.DismissalHitWicket, models.DismissalRunOut, models.DismissalObstructing},
+	models.ExtraWide:   {models.DismissalStumped, models.DismissalHitWicket, models.DismissalRunOut, models.DismissalObstructing},
+	models.ExtraNoBall: {models.DismissalRunOut, models.DismissalObstructing},
+	models.ExtraBye:    {models.DismissalRunOut, models.DismissalObstructing},
+	models.ExtraLegBye: {models.DismissalRunOut, models.DismissalObstructing},
+}
+
+// Scorer holds the state of a match rebuilt from its events
+type Scorer struct {
+	match   *models.Match
+	innings []*inningsState
+	applied []models.BallEvent // events in effect, oldest first
+	seq     int                // seq of the latest event in the log, including undos
+}
+
+type inningsState struct {
+	live        models.LiveInnings
+	battingTeam *models.MatchTeam
+	bowlingTeam *models.MatchTeam
+	maxWickets  int
+
+	batters map[string]int // player key -> index in live.Batting
+	bowlers map[string]int // player key -> index in live.Bowling
+
+	striker      string
+	nonStriker   string
+	bowler       string // bowling the current over, empty between overs
+	lastBowler   string // bowled the previous over
+	overBalls    int    // legal balls in the current over
+	overConceded int    // runs charged to the bowler in the current over
+	overComplete bool   // live.ThisOver holds the over just finished
+}
+
+// New returns a Scorer for a match with no events
+func New(match *models.Match) *Scorer {
+	return &Scorer{match: match}
+}
+
+// Replay rebuilds the state of a match from its complete log, oldest event first
+func Replay(match *models.Match, events []models.BallEvent) (*Scorer, error) {
+	var effective []models.BallEvent
+	for _, event := range events {
+		if event.Type == models.BallEventUndo {
+			if len(effective) == 0 {
+				return nil, fmt.Errorf("event %d: nothing to undo", event.Seq)
+			}
+			effective = effective[:len(effective)-1]
+			continue
+		}
+		effective = append(effective, event)
+	}
+
+	scorer := New(match)
+	for _, event := range effective {
+		if err := scorer.Apply(event); err != nil {
+			return nil, fmt.Errorf("event %d: %w", event.Seq, err)
+		}
+	}
+	if len(events) > 0 {
+		scorer.seq = events[len(events)-1].Seq
+	}
+	return scorer, nil
+}
+
+// NextSeq is the seq the next event in the log gets
+func (s *Scorer) NextSeq() int {
+	return s.seq + 1
+}
+
+// LastEvent returns the latest event in effect, which an undo would cancel, or nil if there is none
+func (s *Scorer) LastEvent() *models.BallEvent {
+	if len(s.applied) == 0 {
+		return nil
+	}
+	return &s.applied[len(s.applied)-1]
+}
+
+// Apply checks event against the state of the match and applies it. It returns a *RuleError,
+// leaving the state unchanged, when the event isn't allowed.
+func (s *Scorer) Apply(event models.BallEvent) error {
+	var err error
+	switch event.Type {
+	case models.BallEventInningsStart:
+		err = s.startInnings(event)
+	case models.BallEventBall:
+		err = s.bowl(event)
+	case models.BallEventInningsEnd:
+		err = s.endInnings(event)
+	default:
+		err = ruleError("type", "oneof", "unknown event type %q", event.Type)
+	}
+	if err != nil {
+		return err
+	}
+
+	s.applied = append(s.applied, event)
+	if event.Seq > s.seq {
+		s.seq = event.Seq
+	}
+	return nil
+}
+
+// current returns the innings in progress, or nil between innings
+func (s *Scorer) current() *inningsState {
+	if len(s.innings) == 0 {
+		return nil
+	}
+	if innings := s.innings[len(s.innings)-1]; !innings.live.Complete {
+		return innings
+	}
+	return nil
+}
+
+func (s *Scorer) startInnings(event models.BallEvent) error {
+	if current := s.current(); current != nil {
+		return ruleError("type", "innings", "%s are still batting", current.live.BattingTeam)
+	}
+	if len(s.innings) >= 2 {
+		return ruleError("type", "complete", "both innings have been played")
+	}
+
+	battingTeam := s.team(event.BattingTeam)
+	if battingTeam == nil {
+		return ruleError("battingTeam", "team", "battingTeam must be one of the teams")
+	}
+	bowlingTeam := &s.match.Teams[0]
+	if bowlingTeam == battingTeam {
+		bowlingTeam = &s.match.Teams[1]
+	}
+	if len(s.innings) == 1 && s.innings[0].battingTeam == battingTeam {
+		return ruleError("battingTeam", "unique", "%s have already batted", battingTeam.Name)
+	}
+
+	striker, err := resolvePlayer(battingTeam, event.Striker, "striker")
+	if err != nil {
+		return err
+	}
+	nonStriker, err := resolvePlayer(battingTeam, event.NonStriker, "nonStriker")
+	if err != nil {
+		return err
+	}
+	if playerKey(striker) == playerKey(nonStriker) {
+		return ruleError("nonStriker", "unique", "the opening batters must be different players")
+	}
+
+	innings := &inningsState{
+		battingTeam: battingTeam,
+		bowlingTeam: bowlingTeam,
+		maxWickets:  10,
+		batters:     map[string]int{},
+		bowlers:     map[string]int{},
+	}
+	if players := len(battingTeam.Players); players >= 2 && players-1 < innings.maxWickets {
+		innings.maxWickets = players - 1
+	}
+	innings.live.BattingTeam = battingTeam.Name
+	innings.live.Overs = "0"
+	innings.live.Batting = []models.BattingEntry{}
+	innings.live.Bowling = []models.BowlingEntry{}
+	innings.live.ThisOver = []string{}
+	innings.live.FallOfWickets = []models.FallOfWicket{}
+	if len(s.innings) == 1 {
+		innings.live.Target = s.innings[0].live.Runs + 1
+	}
+
+	innings.striker = innings.addBatter(striker)
+	innings.nonStriker = innings.addBatter(nonStriker)
+	innings.live.Partnerships = []models.Partnership{{Wicket: 1, Batter1: striker, Batter2: nonStriker, Unbroken: true}}
+
+	s.innings = append(s.innings, innings)
+	return nil
+}
+
+func (s *Scorer) endInnings(event models.BallEvent) error {
+	innings := s.current()
+	if innings == nil {
+		return ruleError("type", "innings", "no innings is in progress")
+	}
+	if event.Reason != models.InningsDeclared && event.Reason != models.InningsClosed {
+		return ruleError("reason", "required", "reason must be declared or closed")
+	}
+	innings.end(event.Reason)
+	return nil
+}
+
+// bowl validates a delivery completely before changing any state
+func (s *Scorer) bowl(event models.BallEvent) error {
+	innings := s.current()
+	if innings == nil {
+		if len(s.innings) >= 2 {
+			return ruleError("type", "complete", "the match is complete")
+		}
+		return ruleError("type", "innings", "no innings is in progress; start one first")
+	}
+
+	bowler, err := resolvePlayer(innings.bowlingTeam, event.Bowler, "bowler")
+	if err != nil {
+		return err
+	}
+	bowlerKey := playerKey(bowler)
+	maxOvers := MaxBowlerOvers(s.match.OversPerInnings)
+	if innings.bowler == "" {
+		if bowlerKey == innings.lastBowler {
+			return ruleError("bowler", "consecutive_overs", "%s bowled the previous over", bowler.Name)
+		}
+		if i, ok := innings.bowlers[bowlerKey]; ok && innings.live.Bowling[i].Balls >= maxOvers*6 {
+			return ruleError("bowler", "max_overs", "%s has bowled their %d overs", bowler.Name, maxOvers)
+		}
+	} else if bowlerKey != innings.bowler {
+		return ruleError("bowler", "mid_over", "%s is bowling this over", innings.live.Bowling[innings.bowlers[innings.bowler]].Name)
+	}
+
+	switch event.Extra {
+	case "":
+		if event.ExtraRuns > 0 {
+			return ruleError("extraRuns", "extra", "extraRuns need an extra type")
+		}
+	case models.ExtraWide:
+		if event.Runs > 0 {
+			return ruleError("runs", "wide", "runs can't be scored off the bat from a wide; record them as extraRuns")
+		}
+	case models.ExtraNoBall:
+		if event.Runs > 0 && event.ExtraRuns > 0 {
+			return ruleError("extraRuns", "no_ball", "runs off a no-ball are either hit or run as byes, not both")
+		}
+	case models.ExtraBye, models.ExtraLegBye:
+		if event.Runs > 0 {
+			return ruleError("runs", event.Extra, "byes and leg byes aren't runs off the bat; record them as extraRuns")
+		}
+		if event.ExtraRuns == 0 {
+			return ruleError("extraRuns", "required", "byes and leg byes need at least one run")
+		}
+	default:
+		return ruleError("extra", "oneof", "unknown extra %q", event.Extra)
+	}
+	if event.Boundary && event.Runs != 4 && event.Runs != 6 {
+		return ruleError("boundary", "runs", "a boundary is 4 or 6 runs off the bat")
+	}
+
+	var outKey, newBatterKey string
+	var newBatter models.MatchPlayer
+	if wicket := event.Wicket; wicket != nil {
+		if !contains(dismissalsOnExtra[event.Extra], wicket.Kind) {
+			delivery := "a legal delivery"
+			if event.Extra != "" {
+				delivery = "a " + strings.ReplaceAll(event.Extra, "_", "-")
+			}
+			return ruleError("wicket.kind", "extra", "%s is not possible off %s", strings.ReplaceAll(wicket.Kind, "_", " "), delivery)
+		}
+		runOut := wicket.Kind == models.DismissalRunOut || wicket.Kind == models.DismissalObstructing
+		if !runOut && (event.Runs > 0 || event.ExtraRuns > 0) {
+			return ruleError("wicket.kind", "runs", "no runs can be completed when the batter is %s", strings.ReplaceAll(wicket.Kind, "_", " "))
+		}
+		outKey = innings.striker
+		if wicket.Batter == "non_striker" {
+			if !runOut {
+				return ruleError("wicket.batter", "striker", "only the striker can be %s", strings.ReplaceAll(wicket.Kind, "_", " "))
+			}
+			outKey = innings.nonStriker
+		}
+
+		if innings.live.Wickets+1 < innings.maxWickets {
+			newBatter, err = resolvePlayer(innings.battingTeam, event.NewBatter, "newBatter")
+			if err != nil {
+				return err
+			}
+			newBatterKey = playerKey(newBatter)
+			if _, batted := innings.batters[newBatterKey]; batted {
+				return ruleError("newBatter", "batted", "%s has already batted", newBatter.Name)
+			}
+		}
+	} else if event.NewBatter != nil {
+		return ruleError("newBatter", "wicket", "a new batter only comes in after a wicket")
+	}
+
+	// The delivery is valid; record it
+	wide, noBall := event.Extra == models.ExtraWide, event.Extra == models.ExtraNoBall
+	legal := !wide && !noBall
+	penalty := 0
+	if wide || noBall {
+		penalty = 1
+	}
+	total := event.Runs + penalty + event.ExtraRuns
+	conceded := event.Runs + penalty // byes and leg byes aren't charged to the bowler
+	if wide {
+		conceded += event.ExtraRuns
+	}
+
+	if innings.bowler == "" {
+		innings.bowler = bowlerKey
+		innings.overConceded = 0
+		if innings.overComplete {
+			innings.live.ThisOver = []string{}
+			innings.overComplete = false
+		}
+	}
+	bowlerIndex, ok := innings.bowlers[bowlerKey]
+	if !ok {
+		innings.live.Bowling = append(innings.live.Bowling, models.BowlingEntry{MatchPlayer: bowler, Overs: "0"})
+		bowlerIndex = len(innings.live.Bowling) - 1
+		innings.bowlers[bowlerKey] = bowlerIndex
+	}
+	bowling := &innings.live.Bowling[bowlerIndex]
+	if legal {
+		bowling.Balls++
+		bowling.Overs = models.FormatOversFromBalls(bowling.Balls)
+	}
+	bowling.Runs += conceded
+
+	extras := &innings.live.Extras
+	switch event.Extra {
+	case models.ExtraWide:
+		bowling.Wides += 1 + event.ExtraRuns
+		extras.Wides += 1 + event.ExtraRuns
+	case models.ExtraNoBall:
+		bowling.NoBalls++
+		extras.NoBalls++
+		extras.Byes += event.ExtraRuns
+	case models.ExtraBye:
+		extras.Byes += event.ExtraRuns
+	case models.ExtraLegBye:
+		extras.LegByes += event.ExtraRuns
+	}
+
+	striker := &innings.live.Batting[innings.batters[innings.striker]]
+	if !wide {
+		striker.Balls++
+	}
+	striker.Runs += event.Runs
+	if event.Boundary && event.Runs == 4 {
+		striker.Fours++
+	} else if event.Boundary && event.Runs == 6 {
+		striker.Sixes++
+	}
+
+	innings.live.Runs += total
+	if legal {
+		innings.live.Balls++
+		innings.overBalls++
+	}
+	innings.live.Overs = models.FormatOversFromBalls(innings.live.Balls)
+	innings.overConceded += conceded
+
+	partnership := &innings.live.Partnerships[len(innings.live.Partnerships)-1]
+	partnership.Runs += total
+	if legal {
+		partnership.Balls++
+	}
+	if playerKey(partnership.Batter1) == innings.striker {
+		partnership.Batter1Runs += event.Runs
+	} else {
+		partnership.Batter2Runs += event.Runs
+	}
+
+	innings.live.ThisOver = append(innings.live.ThisOver, ballSymbol(event))
+
+	if (event.Runs+event.ExtraRuns)%2 == 1 {
+		innings.striker, innings.nonStriker = innings.nonStriker, innings.striker
+	}
+
+	if wicket := event.Wicket; wicket != nil {
+		out := &innings.live.Batting[innings.batters[outKey]]
+		out.Dismissal = wicket.Kind
+		out.Fielder = wicket.Fielder
+		if models.DismissalCreditsBowler(wicket.Kind) {
+			out.Bowler = bowler.Name
+			bowling.Wickets++
+		}
+		innings.live.Wickets++
+		innings.live.FallOfWickets = append(innings.live.FallOfWickets, models.FallOfWicket{
+			Wicket:    innings.live.Wickets,
+			Runs:      innings.live.Runs,
+			Overs:     innings.live.Overs,
+			Batter:    out.MatchPlayer,
+			Dismissal: wicket.Kind,
+		})
+		partnership.Unbroken = false
+
+		if newBatterKey != "" {
+			innings.addBatter(newBatter)
+			survivorKey := innings.nonStriker
+			if outKey == innings.nonStriker {
+				survivorKey = innings.striker
+				innings.nonStriker = newBatterKey
+			} else {
+				innings.striker = newBatterKey
+			}
+			survivor := innings.live.Batting[innings.batters[survivorKey]].MatchPlayer
+			innings.live.Partnerships = append(innings.live.Partnerships, models.Partnership{
+				Wicket:   innings.live.Wickets + 1,
+				Batter1:  survivor,
+				Batter2:  newBatter,
+				Unbroken: true,
+			})
+		}
+	}
+
+	if legal && innings.overBalls == 6 {
+		if innings.overConceded == 0 {
+			bowling.Maidens++
+		}
+		innings.striker, innings.nonStriker = innings.nonStriker, innings.striker
+		innings.lastBowler = innings.bowler
+		innings.bowler = ""
+		innings.overBalls = 0
+		innings.overComplete = true
+	}
+
+	switch {
+	case innings.live.Target > 0 && innings.live.Runs >= innings.live.Target:
+		innings.end(models.InningsTarget)
+	case innings.live.Wickets >= innings.maxWickets:
+		innings.end(models.InningsAllOut)
+	case innings.live.Balls >= s.match.OversPerInnings*6:
+		innings.end(models.InningsOvers)
+	}
+	return nil
+}
+
+func (innings *inningsState) addBatter(player models.MatchPlayer) string {
+	key := playerKey(player)
+	innings.live.Batting = append(innings.live.Batting, models.BattingEntry{
+		MatchPlayer: player,
+		Position:    len(innings.live.Batting) + 1,
+		Dismissal:   models.DismissalNotOut,
+	})
+	innings.batters[key] = len(innings.live.Batting) - 1
+	return key
+}
+
+func (innings *inningsState) end(reason string) {
+	innings.live.Complete = true
+	innings.live.EndReason = reason
+	innings.bowler = ""
+}
+
+// Score returns the live scorecard
+func (s *Scorer) Score() models.LiveScore {
+	score := models.LiveScore{
+		MatchID:         s.match.ID,
+		Seq:             s.seq,
+		Format:          s.match.Format,
+		OversPerInnings: s.match.OversPerInnings,
+		MaxBowlerOvers:  MaxBowlerOvers(s.match.OversPerInnings),
+		Innings:         make([]models.LiveInnings, len(s.innings)),
+		Result:          s.Result(),
+	}
+	for i, innings := range s.innings {
+		live := innings.live
+		if !live.Complete {
+			live.Striker = innings.batter(innings.striker)
+			live.NonStriker = innings.batter(innings.nonStriker)
+			live.Bowler = innings.bowlerEntry(innings.bowler)
+			live.LastBowler = innings.bowlerEntry(innings.lastBowler)
+			if live.Target > 0 {
+				live.RequiredRunRate = rate(live.Target-live.Runs, s.match.OversPerInnings*6-live.Balls)
+			}
+		}
+		live.RunRate = rate(live.Runs, live.Balls)
+		score.Innings[i] = live
+	}
+	return score
+}
+
+// Scorecard returns the innings as they are stored on the match
+func (s *Scorer) Scorecard() []models.Innings {
+	scorecard := make([]models.Innings, len(s.innings))
+	for i, innings := range s.innings {
+		scorecard[i] = innings.live.Innings
+	}
+	return scorecard
+}
+
+// Result returns the match result: in progress until the second innings ends
+func (s *Scorer) Result() models.MatchResult {
+	if len(s.innings) == 0 {
+		return models.MatchResult{Status: models.MatchScheduled}
+	}
+	if len(s.innings) < 2 || !s.innings[1].live.Complete {
+		return models.MatchResult{Status: models.MatchInProgress}
+	}
+
+	first, second := s.innings[0], s.innings[1]
+	switch {
+	case second.live.Runs >= second.live.Target:
+		margin := plural(second.maxWickets-second.live.Wickets, "wicket")
+		return models.MatchResult{
+			Status:  models.MatchCompleted,
+			Winner:  second.live.BattingTeam,
+			Margin:  margin,
+			Summary: fmt.Sprintf("%s won by %s with %s remaining", second.live.BattingTeam, margin, plural(s.match.OversPerInnings*6-second.live.Balls, "ball")),
+		}
+	case second.live.EndReason == models.InningsClosed:
+		return models.MatchResult{Status: models.MatchNoResult, Summary: "No result"}
+	case second.live.Runs == second.live.Target-1:
+		return models.MatchResult{Status: models.MatchCompleted, Summary: "Match tied"}
+	default:
+		margin := plural(first.live.Runs-second.live.Runs, "run")
+		return models.MatchResult{
+			Status:  models.MatchCompleted,
+			Winner:  first.live.BattingTeam,
+			Margin:  margin,
+			Summary: fmt.Sprintf("%s won by %s", first.live.BattingTeam, margin),
+		}
+	}
+}
+
+func (innings *inningsState) batter(key string) *models.MatchPlayer {
+	if i, ok := innings.batters[key]; ok {
+		player := innings.live.Batting[i].MatchPlayer
+		return &player
+	}
+	return nil
+}
+
+func (innings *inningsState) bowlerEntry(key string) *models.MatchPlayer {
+	if i, ok := innings.bowlers[key]; ok {
+		player := innings.live.Bowling[i].MatchPlayer
+		return &player
+	}
+	return nil
+}
+
+func (s *Scorer) team(name string) *models.MatchTeam {
+	name = strings.TrimSpace(name)
+	for i := range s.match.Teams {
+		if strings.EqualFold(s.match.Teams[i].Name, name) {
+			return &s.match.Teams[i]
+		}
+	}
+	return nil
+}
+
+// resolvePlayer finds player in the team sheet. Teams without a sheet accept any named player.
+func resolvePlayer(team *models.MatchTeam, player *models.MatchPlayer, field string) (models.MatchPlayer, error) {
+	if player == nil || (player.CricketerID == nil && strings.TrimSpace(player.Name) == "") {
+		return models.MatchPlayer{}, ruleError(field, "required", "%s is required", field)
+	}
+	name := strings.TrimSpace(player.Name)
+	if len(team.Players) == 0 {
+		return models.MatchPlayer{CricketerID: player.CricketerID, Name: name}, nil
+	}
+	for _, teamPlayer := range team.Players {
+		if player.CricketerID != nil {
+			if teamPlayer.CricketerID != nil && *teamPlayer.CricketerID == *player.CricketerID {
+				return teamPlayer, nil
+			}
+		} else if strings.EqualFold(teamPlayer.Name, name) {
+			return teamPlayer, nil
+		}
+	}
+	if name == "" {
+		name = player.CricketerID.Hex()
+	}
+	return models.MatchPlayer{}, ruleError(field, "team", "%s is not in %s", name, team.Name)
+}
+
+// playerKey identifies a player within a match: by cricketer ID for academy players, by name otherwise
+func playerKey(player models.MatchPlayer) string {
+	if player.CricketerID != nil {
+		return "id:" + player.CricketerID.Hex()
+	}
+	return "name:" + strings.ToLower(strings.TrimSpace(player.Name))
+}
+
+// ballSymbol describes a delivery the way a scorer writes it in the over, e.g. 4, 1wd, 2lb, W
+func ballSymbol(event models.BallEvent) string {
+	var symbol string
+	switch event.Extra {
+	case models.ExtraWide:
+		symbol = strconv.Itoa(1+event.ExtraRuns) + "wd"
+	case models.ExtraNoBall:
+		symbol = "nb"
+		if runs := event.Runs + event.ExtraRuns; runs > 0 {
+			symbol = strconv.Itoa(runs) + "nb"
+		}
+	case models.ExtraBye:
+		symbol = strconv.Itoa(event.ExtraRuns) + "b"
+	case models.ExtraLegBye:
+		symbol = strconv.Itoa(event.ExtraRuns) + "lb"
+	default:
+		symbol = strconv.Itoa(event.Runs)
+	}
+	if event.Wicket != nil {
+		if symbol == "0" {
+			return "W"
+		}
+		return symbol + "W"
+	}
+	return symbol
+}
+
+// rate returns runs per over, rounded to two places, or nil when no balls have been (or remain to be) bowled
+func rate(runs int, balls int) *float64 {
+	if balls <= 0 {
+		return nil
+	}
+	value := math.Round(float64(runs)*6/float64(balls)*100) / 100
+	return &value
+}
+
+func plural(n int, unit string) string {
+	if n == 1 {
+		return "1 " + unit
+	}
+	return strconv.Itoa(n) + " " + unit + "s"
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,551 @@
+package scoring
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"cricketApp/models"
+)
+
+// newMatch returns a match between Lions and Tigers with team sheets of the given sizes.
+// Players are named L1, L2... and T1, T2...
+func newMatch(overs int, lions int, tigers int) *models.Match {
+	sheet := func(prefix string, n int) []models.MatchPlayer {
+		players := make([]models.MatchPlayer, n)
+		for i := range players {
+			players[i] = models.MatchPlayer{Name: fmt.Sprintf("%s%d", prefix, i+1)}
+		}
+		return players
+	}
+	return &models.Match{
+		Format:          "T20",
+		OversPerInnings: overs,
+		Teams: []models.MatchTeam{
+			{Name: "Lions", Players: sheet("L", lions)},
+			{Name: "Tigers", Players: sheet("T", tigers)},
+		},
+	}
+}
+
+func player(name string) *models.MatchPlayer {
+	return &models.MatchPlayer{Name: name}
+}
+
+func start(team string, striker string, nonStriker string) models.BallEvent {
+	return models.BallEvent{Type: models.BallEventInningsStart, BattingTeam: team, Striker: player(striker), NonStriker: player(nonStriker)}
+}
+
+func ball(bowler string, runs int) models.BallEvent {
+	return models.BallEvent{Type: models.BallEventBall, Bowler: player(bowler), Runs: runs, Boundary: runs == 4 || runs == 6}
+}
+
+func extra(bowler string, kind string, runs int, extraRuns int) models.BallEvent {
+	return models.BallEvent{Type: models.BallEventBall, Bowler: player(bowler), Extra: kind, Runs: runs, ExtraRuns: extraRuns}
+}
+
+// out is a delivery on which a batter is dismissed after runs were completed
+func out(bowler string, kind string, batter string, runs int, newBatter string) models.BallEvent {
+	event := ball(bowler, runs)
+	event.Boundary = false
+	event.Wicket = &models.BallWicket{Kind: kind, Batter: batter, Fielder: "F"}
+	if newBatter != "" {
+		event.NewBatter = player(newBatter)
+	}
+	return event
+}
+
+func undo() models.BallEvent {
+	return models.BallEvent{Type: models.BallEventUndo}
+}
+
+// over is six deliveries of the given runs by one bowler
+func over(bowler string, runs ...int) []models.BallEvent {
+	events := make([]models.BallEvent, len(runs))
+	for i, r := range runs {
+		events[i] = ball(bowler, r)
+	}
+	return events
+}
+
+// play applies events in order, numbering them, and fails the test on any rule error
+func play(t *testing.T, s *Scorer, events ...models.BallEvent) {
+	t.Helper()
+	for _, event := range events {
+		event.Seq = s.NextSeq()
+		if err := s.Apply(event); err != nil {
+			t.Fatalf("event %d (%+v): %v", event.Seq, event, err)
+		}
+	}
+}
+
+// started returns a scorer for a 20-over match with Lions batting, L1 facing and L2 at the other end
+func started(t *testing.T) *Scorer {
+	t.Helper()
+	s := New(newMatch(20, 11, 11))
+	play(t, s, start("Lions", "L1", "L2"))
+	return s
+}
+
+func liveInnings(s *Scorer) models.LiveInnings {
+	score := s.Score()
+	return score.Innings[len(score.Innings)-1]
+}
+
+func name(p *models.MatchPlayer) string {
+	if p == nil {
+		return ""
+	}
+	return p.Name
+}
+
+// ruleCode returns the code of a *RuleError, or "" for nil or any other error
+func ruleCode(err error) string {
+	var ruleErr *RuleError
+	if errors.As(err, &ruleErr) {
+		return ruleErr.Code
+	}
+	return ""
+}
+
+func TestStrikeRotation(t *testing.T) {
+	tests := []struct {
+		name        string
+		delivery    models.BallEvent
+		wantStriker string
+		wantRuns    int
+		wantBalls   int
+	}{
+		{"dot", ball("T1", 0), "L1", 0, 1},
+		{"single", ball("T1", 1), "L2", 1, 1},
+		{"two", ball("T1", 2), "L1", 2, 1},
+		{"three", ball("T1", 3), "L2", 3, 1},
+		{"four", ball("T1", 4), "L1", 4, 1},
+		{"wide", extra("T1", models.ExtraWide, 0, 0), "L1", 1, 0},
+		{"wide, one run", extra("T1", models.ExtraWide, 0, 1), "L2", 2, 0},
+		{"wide, two runs", extra("T1", models.ExtraWide, 0, 2), "L1", 3, 0},
+		{"no-ball", extra("T1", models.ExtraNoBall, 0, 0), "L1", 1, 0},
+		{"no-ball hit for one", extra("T1", models.ExtraNoBall, 1, 0), "L2", 2, 0},
+		{"no-ball, one bye", extra("T1", models.ExtraNoBall, 0, 1), "L2", 2, 0},
+		{"no-ball hit for four", extra("T1", models.ExtraNoBall, 4, 0), "L1", 5, 0},
+		{"one bye", extra("T1", models.ExtraBye, 0, 1), "L2", 1, 1},
+		{"two byes", extra("T1", models.ExtraBye, 0, 2), "L1", 2, 1},
+		{"three leg byes", extra("T1", models.ExtraLegBye, 0, 3), "L2", 3, 1},
+		{"four leg byes", extra("T1", models.ExtraLegBye, 0, 4), "L1", 4, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := started(t)
+			play(t, s, tt.delivery)
+
+			live := liveInnings(s)
+			if got := name(live.Striker); got != tt.wantStriker {
+				t.Errorf("striker = %s, want %s", got, tt.wantStriker)
+			}
+			if live.Runs != tt.wantRuns || live.Balls != tt.wantBalls {
+				t.Errorf("score = %d off %d balls, want %d off %d", live.Runs, live.Balls, tt.wantRuns, tt.wantBalls)
+			}
+		})
+	}
+}
+
+func TestExtrasCharged(t *testing.T) {
+	s := started(t)
+	play(t, s,
+		extra("T1", models.ExtraWide, 0, 4),   // 5 wides, all charged to the bowler
+		extra("T1", models.ExtraNoBall, 4, 0), // 1 no-ball and 4 off the bat
+		extra("T1", models.ExtraNoBall, 0, 2), // 1 no-ball and 2 byes
+		extra("T1", models.ExtraBye, 0, 1),
+		extra("T1", models.ExtraLegBye, 0, 2),
+	)
+
+	live := liveInnings(s)
+	wantExtras := models.Extras{Wides: 5, NoBalls: 2, Byes: 3, LegByes: 2}
+	if live.Extras != wantExtras {
+		t.Errorf("extras = %+v, want %+v", live.Extras, wantExtras)
+	}
+	if live.Runs != 16 {
+		t.Errorf("runs = %d, want 16", live.Runs)
+	}
+	bowling := live.Bowling[0]
+	if bowling.Runs != 11 || bowling.Wides != 5 || bowling.NoBalls != 2 || bowling.Balls != 2 {
+		t.Errorf("bowling = %+v, want 11 runs, 5 wides, 2 no-balls off 2 balls", bowling)
+	}
+	if got := live.ThisOver; !reflect.DeepEqual(got, []string{"5wd", "4nb", "2nb", "1b", "2lb"}) {
+		t.Errorf("this over = %v", got)
+	}
+}
+
+func TestMaidens(t *testing.T) {
+	tests := []struct {
+		name       string
+		deliveries []models.BallEvent
+		want       int
+	}{
+		{"six dots", over("T1", 0, 0, 0, 0, 0, 0), 1},
+		{"a single", over("T1", 0, 0, 1, 0, 0, 0), 0},
+		{"spoiled by a wide", append(over("T1", 0, 0, 0, 0, 0), extra("T1", models.ExtraWide, 0, 0), ball("T1", 0)), 0},
+		{"spoiled by a no-ball", append(over("T1", 0, 0, 0, 0, 0), extra("T1", models.ExtraNoBall, 0, 0), ball("T1", 0)), 0},
+		{"byes don't count", append(over("T1", 0, 0, 0, 0, 0), extra("T1", models.ExtraBye, 0, 4)), 1},
+		{"leg byes don't count", append(over("T1", 0, 0, 0, 0, 0), extra("T1", models.ExtraLegBye, 0, 1)), 1},
+		{"wicket maiden", append(over("T1", 0, 0, 0, 0, 0), out("T1", models.DismissalBowled, "", 0, "L3")), 1},
+		{"five dots is not an over", over("T1", 0, 0, 0, 0, 0), 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := started(t)
+			play(t, s, tt.deliveries...)
+
+			if got := liveInnings(s).Bowling[0].Maidens; got != tt.want {
+				t.Errorf("maidens = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOverCompletion(t *testing.T) {
+	s := started(t)
+	play(t, s, ball("T1", 1), ball("T1", 0), extra("T1", models.ExtraWide, 0, 0), extra("T1", models.ExtraNoBall, 0, 0), ball("T1", 0), ball("T1", 0), ball("T1", 0))
+
+	live := liveInnings(s)
+	if live.Overs != "0.5" || name(live.Bowler) != "T1" {
+		t.Fatalf("after 5 legal balls: overs %s, bowler %q, want 0.5 and T1 still bowling", live.Overs, name(live.Bowler))
+	}
+	if got := ruleCode(s.Apply(ball("T2", 0))); got != "mid_over" {
+		t.Errorf("changing bowler mid-over: code %q, want mid_over", got)
+	}
+
+	play(t, s, ball("T1", 0))
+	live = liveInnings(s)
+	if live.Overs != "1" || live.Bowler != nil || name(live.LastBowler) != "T1" {
+		t.Errorf("after the over: overs %s, bowler %q, last bowler %q, want 1, none, T1", live.Overs, name(live.Bowler), name(live.LastBowler))
+	}
+	// L2 took the single and faced the rest of the over; the ends change at its end
+	if name(live.Striker) != "L1" || name(live.NonStriker) != "L2" {
+		t.Errorf("after the over: striker %s, non-striker %s, want L1 and L2", name(live.Striker), name(live.NonStriker))
+	}
+	if len(live.ThisOver) != 8 {
+		t.Errorf("this over = %v, want 8 deliveries", live.ThisOver)
+	}
+
+	if got := ruleCode(s.Apply(ball("T1", 0))); got != "consecutive_overs" {
+		t.Errorf("same bowler again: code %q, want consecutive_overs", got)
+	}
+	play(t, s, ball("T2", 0))
+	if live := liveInnings(s); !reflect.DeepEqual(live.ThisOver, []string{"0"}) {
+		t.Errorf("new over = %v, want [0]", live.ThisOver)
+	}
+
+	// T1 may come back after someone else has bowled an over
+	play(t, s, over("T2", 0, 0, 0, 0, 0)...)
+	play(t, s, ball("T1", 0))
+}
+
+func TestMaxBowlerOvers(t *testing.T) {
+	tests := []struct {
+		overs int
+		want  int
+	}{
+		{1, 1},
+		{5, 1},
+		{6, 2},
+		{10, 2},
+		{20, 4},
+		{50, 10},
+	}
+	for _, tt := range tests {
+		if got := MaxBowlerOvers(tt.overs); got != tt.want {
+			t.Errorf("MaxBowlerOvers(%d) = %d, want %d", tt.overs, got, tt.want)
+		}
+	}
+}
+
+func TestMaxBowlerOversEnforced(t *testing.T) {
+	s := New(newMatch(10, 11, 11))
+	play(t, s, start("Lions", "L1", "L2"))
+	for _, bowler := range []string{"T1", "T2", "T1", "T2"} {
+		play(t, s, over(bowler, 0, 0, 0, 0, 0, 0)...)
+	}
+
+	err := s.Apply(ball("T1", 0))
+	if got := ruleCode(err); got != "max_overs" {
+		t.Fatalf("T1's third over of ten: code %q, want max_overs", got)
+	}
+	var ruleErr *RuleError
+	if errors.As(err, &ruleErr); ruleErr.Field != "bowler" {
+		t.Errorf("field = %q, want bowler", ruleErr.Field)
+	}
+	play(t, s, ball("T3", 0))
+}
+
+func TestWickets(t *testing.T) {
+	tests := []struct {
+		name           string
+		before         []models.BallEvent
+		delivery       models.BallEvent
+		wantOut        string
+		wantStriker    string
+		wantNonStriker string
+		wantBowlerWkts int
+	}{
+		{"striker run out, no run", nil, out("T1", models.DismissalRunOut, "striker", 0, "L3"), "L1", "L3", "L2", 0},
+		{"striker run out after a run", nil, out("T1", models.DismissalRunOut, "striker", 1, "L3"), "L1", "L2", "L3", 0},
+		{"non-striker run out, no run", nil, out("T1", models.DismissalRunOut, "non_striker", 0, "L3"), "L2", "L1", "L3", 0},
+		{"non-striker run out after a run", nil, out("T1", models.DismissalRunOut, "non_striker", 1, "L3"), "L2", "L3", "L1", 0},
+		{"caught", nil, out("T1", models.DismissalCaught, "", 0, "L3"), "L1", "L3", "L2", 1},
+		{"caught off the last ball of the over", over("T1", 0, 0, 0, 0, 0), out("T1", models.DismissalCaught, "", 0, "L3"), "L1", "L2", "L3", 1},
+		{"stumped off a wide", nil, func() models.BallEvent {
+			event := out("T1", models.DismissalStumped, "", 0, "L3")
+			event.Extra = models.ExtraWide
+			return event
+		}(), "L1", "L3", "L2", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := started(t)
+			play(t, s, tt.before...)
+			play(t, s, tt.delivery)
+
+			live := liveInnings(s)
+			if name(live.Striker) != tt.wantStriker || name(live.NonStriker) != tt.wantNonStriker {
+				t.Errorf("striker %s, non-striker %s, want %s and %s", name(live.Striker), name(live.NonStriker), tt.wantStriker, tt.wantNonStriker)
+			}
+			if live.Wickets != 1 || len(live.FallOfWickets) != 1 || live.FallOfWickets[0].Batter.Name != tt.wantOut {
+				t.Errorf("wickets %d, fall of wickets %+v, want %s out", live.Wickets, live.FallOfWickets, tt.wantOut)
+			}
+			for _, entry := range live.Batting {
+				if entry.Name == tt.wantOut && entry.Dismissal != tt.delivery.Wicket.Kind {
+					t.Errorf("%s dismissal = %s, want %s", entry.Name, entry.Dismissal, tt.delivery.Wicket.Kind)
+				}
+			}
+			if got := live.Bowling[0].Wickets; got != tt.wantBowlerWkts {
+				t.Errorf("bowler wickets = %d, want %d", got, tt.wantBowlerWkts)
+			}
+			partnership := live.Partnerships[len(live.Partnerships)-1]
+			if partnership.Wicket != 2 || !partnership.Unbroken || partnership.Batter2.Name != "L3" {
+				t.Errorf("new partnership = %+v, want the second wicket with L3", partnership)
+			}
+		})
+	}
+}
+
+func TestWicketRules(t *testing.T) {
+	tests := []struct {
+		name      string
+		delivery  models.BallEvent
+		wantField string
+		wantCode  string
+	}{
+		{"non-striker caught", out("T1", models.DismissalCaught, "non_striker", 0, "L3"), "wicket.batter", "striker"},
+		{"bowled with a run", out("T1", models.DismissalBowled, "", 1, "L3"), "wicket.kind", "runs"},
+		{"stumped off a no-ball", func() models.BallEvent {
+			event := out("T1", models.DismissalStumped, "", 0, "L3")
+			event.Extra = models.ExtraNoBall
+			return event
+		}(), "wicket.kind", "extra"},
+		{"no new batter", out("T1", models.DismissalBowled, "", 0, ""), "newBatter", "required"},
+		{"new batter already batted", out("T1", models.DismissalBowled, "", 0, "L2"), "newBatter", "batted"},
+		{"new batter not in the team", out("T1", models.DismissalBowled, "", 0, "T5"), "newBatter", "team"},
+		{"new batter without a wicket", func() models.BallEvent {
+			event := ball("T1", 0)
+			event.NewBatter = player("L3")
+			return event
+		}(), "newBatter", "wicket"},
+		{"bowler from the batting side", ball("L5", 0), "bowler", "team"},
+		{"runs off the bat from a wide", extra("T1", models.ExtraWide, 1, 0), "runs", "wide"},
+		{"byes without runs", extra("T1", models.ExtraBye, 0, 0), "extraRuns", "required"},
+		{"five-run boundary", func() models.BallEvent {
+			event := ball("T1", 5)
+			event.Boundary = true
+			return event
+		}(), "boundary", "runs"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := started(t)
+			before := s.Score()
+
+			err := s.Apply(tt.delivery)
+			var ruleErr *RuleError
+			if !errors.As(err, &ruleErr) {
+				t.Fatalf("Apply = %v, want a *RuleError", err)
+			}
+			if ruleErr.Field != tt.wantField || ruleErr.Code != tt.wantCode {
+				t.Errorf("error %s/%s (%s), want %s/%s", ruleErr.Field, ruleErr.Code, ruleErr.Message, tt.wantField, tt.wantCode)
+			}
+			if after := s.Score(); !reflect.DeepEqual(after, before) {
+				t.Errorf("a rejected delivery changed the score")
+			}
+		})
+	}
+}
+
+// firstInnings plays a one-over first innings in which Lions score 5
+func firstInnings(t *testing.T, s *Scorer) {
+	t.Helper()
+	play(t, s, start("Lions", "L1", "L2"))
+	play(t, s, over("T1", 4, 0, 0, 0, 0, 1)...)
+}
+
+func TestResult(t *testing.T) {
+	tests := []struct {
+		name        string
+		chase       []models.BallEvent
+		wantReason  string
+		wantWinner  string
+		wantSummary string
+	}{
+		{"target reached", over("L1", 0, 6), models.InningsTarget, "Tigers", "Tigers won by 10 wickets with 4 balls remaining"},
+		{"target reached off a wide", append(over("L1", 4, 1), extra("L1", models.ExtraWide, 0, 0)), models.InningsTarget, "Tigers", "Tigers won by 10 wickets with 4 balls remaining"},
+		{"tied", over("L1", 1, 1, 1, 1, 1, 0), models.InningsOvers, "", "Match tied"},
+		{"defended", over("L1", 0, 0, 2, 0, 0, 0), models.InningsOvers, "Lions", "Lions won by 3 runs"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New(newMatch(1, 11, 11))
+			firstInnings(t, s)
+			if result := s.Result(); result.Status != models.MatchInProgress {
+				t.Fatalf("after the first innings status = %s, want in progress", result.Status)
+			}
+			play(t, s, start("Tigers", "T1", "T2"))
+			if target := liveInnings(s).Target; target != 6 {
+				t.Fatalf("target = %d, want 6", target)
+			}
+			play(t, s, tt.chase...)
+
+			live := liveInnings(s)
+			if !live.Complete || live.EndReason != tt.wantReason {
+				t.Errorf("innings complete %v, reason %q, want %q", live.Complete, live.EndReason, tt.wantReason)
+			}
+			result := s.Result()
+			if result.Status != models.MatchCompleted || result.Winner != tt.wantWinner || result.Summary != tt.wantSummary {
+				t.Errorf("result = %+v, want %q winning: %q", result, tt.wantWinner, tt.wantSummary)
+			}
+			if got := ruleCode(s.Apply(ball("L2", 0))); got != "complete" {
+				t.Errorf("ball after the match: code %q, want complete", got)
+			}
+		})
+	}
+}
+
+func TestNoResultWhenClosed(t *testing.T) {
+	s := New(newMatch(1, 11, 11))
+	firstInnings(t, s)
+	play(t, s, start("Tigers", "T1", "T2"), ball("L1", 1), models.BallEvent{Type: models.BallEventInningsEnd, Reason: models.InningsClosed})
+
+	if result := s.Result(); result.Status != models.MatchNoResult {
+		t.Errorf("result = %+v, want no result", result)
+	}
+}
+
+func TestAllOutWithShortXI(t *testing.T) {
+	// Tigers have four players, so they are all out when three are dismissed
+	s := New(newMatch(20, 11, 4))
+	firstInnings(t, s)
+	play(t, s, models.BallEvent{Type: models.BallEventInningsEnd, Reason: models.InningsDeclared})
+	play(t, s, start("Tigers", "T1", "T2"),
+		out("L2", models.DismissalBowled, "", 0, "T3"),
+		ball("L2", 1),
+		out("L2", models.DismissalLBW, "", 0, "T4"),
+	)
+	if live := liveInnings(s); live.Complete {
+		t.Fatalf("innings ended at %d wickets", live.Wickets)
+	}
+
+	// The last wicket needs no new batter
+	play(t, s, out("L2", models.DismissalCaught, "", 0, ""))
+
+	live := liveInnings(s)
+	if !live.Complete || live.EndReason != models.InningsAllOut || live.Wickets != 3 {
+		t.Errorf("innings complete %v, reason %q at %d wickets, want all out for 3", live.Complete, live.EndReason, live.Wickets)
+	}
+	if result := s.Result(); result.Winner != "Lions" || result.Summary != "Lions won by 4 runs" {
+		t.Errorf("result = %+v, want Lions by 4 runs", result)
+	}
+}
+
+func TestReplay(t *testing.T) {
+	match := newMatch(20, 11, 11)
+	events := []models.BallEvent{
+		start("Lions", "L1", "L2"),
+		ball("T1", 1),
+		ball("T1", 4),
+		undo(), // the four
+		ball("T1", 6),
+		ball("T1", 2),
+		undo(), // the two
+		undo(), // the six
+		ball("T1", 0),
+	}
+	for i := range events {
+		events[i].Seq = i + 1
+	}
+
+	s, err := Replay(match, events)
+	if err != nil {
+		t.Fatalf("Replay: %v", err)
+	}
+	live := liveInnings(s)
+	if live.Runs != 1 || live.Balls != 2 || !reflect.DeepEqual(live.ThisOver, []string{"1", "0"}) {
+		t.Errorf("score %d off %d balls, this over %v, want 1 off 2 with [1 0]", live.Runs, live.Balls, live.ThisOver)
+	}
+	if s.NextSeq() != len(events)+1 {
+		t.Errorf("NextSeq = %d, want %d", s.NextSeq(), len(events)+1)
+	}
+	if last := s.LastEvent(); last == nil || last.Seq != 9 {
+		t.Errorf("LastEvent = %+v, want seq 9", last)
+	}
+
+	// Replaying matches applying only the events still in effect
+	direct := New(match)
+	play(t, direct, start("Lions", "L1", "L2"), ball("T1", 1), ball("T1", 0))
+	want, got := direct.Score(), s.Score()
+	want.Seq, got.Seq = 0, 0
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("replayed score differs from applying the effective events:\n got %+v\nwant %+v", got, want)
+	}
+}
+
+func TestReplayUndoAcrossOver(t *testing.T) {
+	events := append([]models.BallEvent{start("Lions", "L1", "L2")}, over("T1", 0, 0, 0, 0, 0, 1)...)
+	events = append(events, undo())
+	for i := range events {
+		events[i].Seq = i + 1
+	}
+
+	s, err := Replay(newMatch(20, 11, 11), events)
+	if err != nil {
+		t.Fatalf("Replay: %v", err)
+	}
+	live := liveInnings(s)
+	if live.Overs != "0.5" || name(live.Bowler) != "T1" || live.Bowling[0].Maidens != 0 {
+		t.Errorf("overs %s, bowler %q, maidens %d, want 0.5 with T1 mid-over and no maiden", live.Overs, name(live.Bowler), live.Bowling[0].Maidens)
+	}
+	// T1 finishes the over, and can now bowl the sixth ball again
+	play(t, s, ball("T1", 0))
+	if got := liveInnings(s).Bowling[0].Maidens; got != 1 {
+		t.Errorf("maidens = %d, want 1", got)
+	}
+}
+
+func TestReplayNothingToUndo(t *testing.T) {
+	events := []models.BallEvent{start("Lions", "L1", "L2"), undo(), undo()}
+	for i := range events {
+		events[i].Seq = i + 1
+	}
+	if _, err := Replay(newMatch(20, 11, 11), events); err == nil {
+		t.Error("Replay with more undos than events succeeded")
+	}
+}
+
+func TestReplayRejectsInvalidLog(t *testing.T) {
+	events := []models.BallEvent{start("Lions", "L1", "L2"), ball("T1", 0), ball("T2", 0)}
+	for i := range events {
+		events[i].Seq = i + 1
+	}
+	_, err := Replay(newMatch(20, 11, 11), events)
+	if got := ruleCode(err); got != "mid_over" {
+		t.Errorf("Replay error = %v, want a mid_over rule error", err)
+	}
+}
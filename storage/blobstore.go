@@ -0,0 +1,42 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"os"
+)
+
+// ErrNotFound is returned when a blob does not exist in the store
+var ErrNotFound = errors.New("blob not found")
+
+// BlobStore defines the operations needed to store and retrieve binary objects
+type BlobStore interface {
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// NewBlobStoreFromEnv creates the blob store selected by the BLOB_STORE environment variable.
+// "s3" uses an S3-compatible service (AWS S3, MinIO...), anything else uses the local filesystem.
+func NewBlobStoreFromEnv() (BlobStore, error) {
+	switch os.Getenv("BLOB_STORE") {
+	case "s3":
+		log.Println("Using S3-compatible blob store")
+		return NewS3Store(S3Config{
+			Endpoint:  os.Getenv("S3_ENDPOINT"),
+			Region:    os.Getenv("S3_REGION"),
+			Bucket:    os.Getenv("S3_BUCKET"),
+			AccessKey: os.Getenv("S3_ACCESS_KEY"),
+			SecretKey: os.Getenv("S3_SECRET_KEY"),
+		})
+	default:
+		dir := os.Getenv("BLOB_LOCAL_DIR")
+		if dir == "" {
+			dir = "uploads"
+		}
+		log.Printf("Using local blob store in %s", dir)
+		return NewLocalStore(dir)
+	}
+}
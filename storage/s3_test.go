@@ -0,0 +1,199 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+const (
+	testAccessKey = "AKIDEXAMPLE"
+	testSecretKey = "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"
+	testRegion    = "ap-south-1"
+	testBucket    = "academy"
+)
+
+// fakeS3 is a minimal in-memory S3 stand-in that checks the SigV4 signature of every request
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	types   map[string]string
+}
+
+func newFakeS3() *fakeS3 {
+	return &fakeS3{objects: map[string][]byte{}, types: map[string]string{}}
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := verifySigV4(r); err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	key := r.URL.Path
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch r.Method {
+	case http.MethodPut:
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		f.objects[key] = body
+		f.types[key] = r.Header.Get("Content-Type")
+	case http.MethodGet:
+		body, ok := f.objects[key]
+		if !ok {
+			http.Error(w, "NoSuchKey", http.StatusNotFound)
+			return
+		}
+		w.Write(body)
+	case http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "unsupported method", http.StatusMethodNotAllowed)
+	}
+}
+
+// verifySigV4 recomputes the signature from the request as the server received it
+func verifySigV4(r *http.Request) error {
+	auth := r.Header.Get("Authorization")
+	var credential, signedHeaders, signature string
+	if _, err := fmt.Sscanf(strings.ReplaceAll(auth, ",", ""), "AWS4-HMAC-SHA256 Credential=%s SignedHeaders=%s Signature=%s",
+		&credential, &signedHeaders, &signature); err != nil {
+		return fmt.Errorf("malformed Authorization header %q", auth)
+	}
+	parts := strings.SplitN(credential, "/", 2)
+	if len(parts) != 2 || parts[0] != testAccessKey {
+		return fmt.Errorf("unknown credential %q", credential)
+	}
+	scope := parts[1]
+	date := strings.SplitN(scope, "/", 2)[0]
+	if scope != date+"/"+testRegion+"/s3/aws4_request" {
+		return fmt.Errorf("unexpected scope %q", scope)
+	}
+	amzDate := r.Header.Get("X-Amz-Date")
+	if !strings.HasPrefix(amzDate, date) {
+		return fmt.Errorf("X-Amz-Date %q does not match scope date %q", amzDate, date)
+	}
+
+	canonicalHeaders := ""
+	for _, name := range strings.Split(signedHeaders, ";") {
+		value := r.Header.Get(name)
+		if name == "host" {
+			value = r.Host
+		}
+		canonicalHeaders += name + ":" + strings.TrimSpace(value) + "\n"
+	}
+	canonicalRequest := strings.Join([]string{
+		r.Method,
+		r.URL.EscapedPath(),
+		r.URL.RawQuery,
+		canonicalHeaders,
+		signedHeaders,
+		r.Header.Get("X-Amz-Content-Sha256"),
+	}, "\n")
+	hashed := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(hashed[:])
+
+	mac := func(key []byte, data string) []byte {
+		h := hmac.New(sha256.New, key)
+		h.Write([]byte(data))
+		return h.Sum(nil)
+	}
+	key := mac([]byte("AWS4"+testSecretKey), date)
+	key = mac(key, testRegion)
+	key = mac(key, "s3")
+	key = mac(key, "aws4_request")
+	if expected := hex.EncodeToString(mac(key, stringToSign)); !hmac.Equal([]byte(expected), []byte(signature)) {
+		return errors.New("SignatureDoesNotMatch")
+	}
+	return nil
+}
+
+func newTestS3Store(t *testing.T, secretKey string) (*S3Store, *fakeS3) {
+	t.Helper()
+	fake := newFakeS3()
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	store, err := NewS3Store(S3Config{
+		Endpoint:  server.URL,
+		Region:    testRegion,
+		Bucket:    testBucket,
+		AccessKey: testAccessKey,
+		SecretKey: secretKey,
+	})
+	if err != nil {
+		t.Fatalf("NewS3Store: %v", err)
+	}
+	return store, fake
+}
+
+func TestS3StorePutGetDelete(t *testing.T) {
+	ctx := context.Background()
+	store, fake := newTestS3Store(t, testSecretKey)
+
+	keys := []string{"attachments/report.pdf", "photos/with space+plus/photo.jpg"}
+	for _, key := range keys {
+		t.Run(key, func(t *testing.T) {
+			content := []byte("contents of " + key)
+			if err := store.Put(ctx, key, bytes.NewReader(content), int64(len(content)), "application/pdf"); err != nil {
+				t.Fatalf("Put: %v", err)
+			}
+			if got := fake.types["/"+testBucket+"/"+key]; got != "application/pdf" {
+				t.Errorf("stored content type = %q, want application/pdf", got)
+			}
+
+			body, err := store.Get(ctx, key)
+			if err != nil {
+				t.Fatalf("Get: %v", err)
+			}
+			got, err := io.ReadAll(body)
+			body.Close()
+			if err != nil {
+				t.Fatalf("reading body: %v", err)
+			}
+			if !bytes.Equal(got, content) {
+				t.Errorf("Get = %q, want %q", got, content)
+			}
+
+			if err := store.Delete(ctx, key); err != nil {
+				t.Fatalf("Delete: %v", err)
+			}
+			if _, err := store.Get(ctx, key); !errors.Is(err, ErrNotFound) {
+				t.Errorf("Get after Delete error = %v, want ErrNotFound", err)
+			}
+		})
+	}
+}
+
+func TestS3StoreRejectsBadSignature(t *testing.T) {
+	store, _ := newTestS3Store(t, "not-the-secret")
+
+	err := store.Put(context.Background(), "attachments/a.txt", strings.NewReader("x"), 1, "text/plain")
+	if err == nil || !strings.Contains(err.Error(), "403") {
+		t.Fatalf("Put with wrong secret error = %v, want a 403 failure", err)
+	}
+}
+
+func TestNewS3StoreRequiresEndpointAndBucket(t *testing.T) {
+	if _, err := NewS3Store(S3Config{Bucket: testBucket}); err == nil {
+		t.Error("NewS3Store without endpoint succeeded")
+	}
+	if _, err := NewS3Store(S3Config{Endpoint: "http://localhost:9000"}); err == nil {
+		t.Error("NewS3Store without bucket succeeded")
+	}
+}
@@ -0,0 +1,59 @@
+package storage
+
+import (
+	"crypto/hmac"
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+var (
+	ErrURLExpired          = errors.New("download URL has expired")
+	ErrURLInvalidSignature = errors.New("download URL signature is invalid")
+)
+
+// URLSigner issues and verifies time-limited download URLs
+type URLSigner struct {
+	secret []byte
+	ttl    time.Duration
+}
+
+// NewURLSigner creates a new URLSigner whose URLs are valid for ttl
+func NewURLSigner(secret []byte, ttl time.Duration) *URLSigner {
+	return &URLSigner{secret: secret, ttl: ttl}
+}
+
+// Sign returns the path with expires and sig query parameters appended, and the expiry time
+func (s *URLSigner) Sign(path string) (string, time.Time) {
+	expiresAt := time.Now().Add(s.ttl)
+	expires := strconv.FormatInt(expiresAt.Unix(), 10)
+
+	query := url.Values{}
+	query.Set("expires", expires)
+	query.Set("sig", s.signature(path, expires))
+	return path + "?" + query.Encode(), expiresAt
+}
+
+// Verify checks the expires and sig query parameters of a signed URL for path
+func (s *URLSigner) Verify(path string, query url.Values) error {
+	expires := query.Get("expires")
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return ErrURLInvalidSignature
+	}
+
+	expected := s.signature(path, expires)
+	if !hmac.Equal([]byte(expected), []byte(query.Get("sig"))) {
+		return ErrURLInvalidSignature
+	}
+	if time.Now().Unix() > expiresAt {
+		return ErrURLExpired
+	}
+	return nil
+}
+
+func (s *URLSigner) signature(path string, expires string) string {
+	return hex.EncodeToString(hmacSHA256(s.secret, path+"\n"+expires))
+}
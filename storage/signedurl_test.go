@@ -0,0 +1,95 @@
+package storage
+
+import (
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func signedQuery(t *testing.T, signer *URLSigner, path string) url.Values {
+	t.Helper()
+	signed, _ := signer.Sign(path)
+	u, err := url.Parse(signed)
+	if err != nil {
+		t.Fatalf("parsing signed URL %q: %v", signed, err)
+	}
+	if u.Path != path {
+		t.Fatalf("signed URL path = %q, want %q", u.Path, path)
+	}
+	return u.Query()
+}
+
+func TestURLSignerSignVerify(t *testing.T) {
+	signer := NewURLSigner([]byte("secret"), time.Hour)
+	path := "/api/attachments/64b7f0c2a1e4d3b2c1a09f87/download"
+
+	signed, expiresAt := signer.Sign(path)
+	if !strings.HasPrefix(signed, path+"?") {
+		t.Errorf("Sign = %q, want it to start with the path", signed)
+	}
+	if d := time.Until(expiresAt); d < 59*time.Minute || d > time.Hour {
+		t.Errorf("expiresAt is %v away, want about an hour", d)
+	}
+	if err := signer.Verify(path, signedQuery(t, signer, path)); err != nil {
+		t.Errorf("Verify of a fresh URL = %v, want nil", err)
+	}
+}
+
+func TestURLSignerRejectsTampering(t *testing.T) {
+	signer := NewURLSigner([]byte("secret"), time.Hour)
+	path := "/api/photos/64b7f0c2a1e4d3b2c1a09f87/thumb"
+
+	tests := []struct {
+		name   string
+		path   string
+		modify func(url.Values)
+	}{
+		{"other path", "/api/photos/64b7f0c2a1e4d3b2c1a09f88/thumb", func(url.Values) {}},
+		{"extended expiry", path, func(q url.Values) {
+			expires, _ := strconv.ParseInt(q.Get("expires"), 10, 64)
+			q.Set("expires", strconv.FormatInt(expires+3600, 10))
+		}},
+		{"non-numeric expiry", path, func(q url.Values) { q.Set("expires", "never") }},
+		{"missing expiry", path, func(q url.Values) { q.Del("expires") }},
+		{"altered signature", path, func(q url.Values) {
+			sig := []byte(q.Get("sig"))
+			if sig[0] == '0' {
+				sig[0] = '1'
+			} else {
+				sig[0] = '0'
+			}
+			q.Set("sig", string(sig))
+		}},
+		{"missing signature", path, func(q url.Values) { q.Del("sig") }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query := signedQuery(t, signer, path)
+			tt.modify(query)
+			if err := signer.Verify(tt.path, query); !errors.Is(err, ErrURLInvalidSignature) {
+				t.Errorf("Verify = %v, want ErrURLInvalidSignature", err)
+			}
+		})
+	}
+}
+
+func TestURLSignerOtherSecret(t *testing.T) {
+	path := "/api/attachments/64b7f0c2a1e4d3b2c1a09f87/download"
+	query := signedQuery(t, NewURLSigner([]byte("secret"), time.Hour), path)
+
+	if err := NewURLSigner([]byte("other-secret"), time.Hour).Verify(path, query); !errors.Is(err, ErrURLInvalidSignature) {
+		t.Errorf("Verify with another secret = %v, want ErrURLInvalidSignature", err)
+	}
+}
+
+func TestURLSignerExpiry(t *testing.T) {
+	signer := NewURLSigner([]byte("secret"), -time.Minute)
+	path := "/api/attachments/64b7f0c2a1e4d3b2c1a09f87/download"
+
+	if err := signer.Verify(path, signedQuery(t, signer, path)); !errors.Is(err, ErrURLExpired) {
+		t.Errorf("Verify of an expired URL = %v, want ErrURLExpired", err)
+	}
+}
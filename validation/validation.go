@@ -0,0 +1,514 @@
+// Package validation enforces the `binding` struct tags on request bodies.
+//
+// Tags hold comma-separated rules, e.g. `binding:"required,email"`:
+//
+//	required       the field must not be empty (blank strings, zero times and nil pointers are empty)
+//	omitempty      skip the remaining rules when the field is empty
+//	email          a plain email address
+//	min=N, max=N   length of a string or slice, or value of a number
+//	len=N          exact length of a string or slice
+//	oneof=a b c    one of the listed values
+//	numeric        digits only
+//	mobile         an Indian mobile number, optionally with a +91 or 0 prefix
+//	aadhaar        a 12-digit Aadhaar number with a valid Verhoeff check digit
+//	objectid       a MongoDB ObjectID in hex
+//	past           a time before now
+//	gtfield=F      greater than sibling field F (times and numbers), skipped while either is empty
+//	gtefield=F     greater than or equal to sibling field F
+//
+// Nested structs and slices of structs are validated too, with their fields reported as
+// parent.child and items[0].child.
+//
+// A type's tags are checked the first time it is validated: an unknown rule, a bad parameter or a
+// rule that can't apply to its field is an error from Struct rather than a failed field.
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// FieldError describes a single field that failed a rule
+type FieldError struct {
+	Field   string `json:"field"`           // JSON name of the field, e.g. parentDetails.contactNo
+	Code    string `json:"code"`            // the rule that failed, e.g. required, email, min
+	Param   string `json:"param,omitempty"` // the rule's parameter, e.g. 6 for min=6
+	Message string `json:"message"`
+}
+
+// Errors lists every field that failed validation
+type Errors []FieldError
+
+func (e Errors) Error() string {
+	messages := make([]string, len(e))
+	for i, fieldErr := range e {
+		messages[i] = fieldErr.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+var objectIDType = reflect.TypeOf(primitive.ObjectID{})
+
+// checkedTypes caches the result of checking each struct type's tags
+var checkedTypes sync.Map // reflect.Type -> error
+
+// Struct checks v (a struct or pointer to one) against its binding tags. It returns nil when
+// every field is valid, Errors listing the fields that aren't, or another error if the type's
+// tags are malformed.
+func Struct(v interface{}) error {
+	value := reflect.ValueOf(v)
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return nil
+	}
+	if err := CheckTags(value.Interface()); err != nil {
+		return err
+	}
+
+	var errs Errors
+	validateStruct(value, "", &errs)
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// CheckTags checks the binding tags of v's type, and of the structs nested in it, without
+// validating any values. Types are only checked once.
+func CheckTags(v interface{}) error {
+	t := derefType(reflect.TypeOf(v))
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil
+	}
+	if err, ok := checkedTypes.Load(t); ok {
+		return errOrNil(err)
+	}
+	err := checkStructTags(t, map[reflect.Type]bool{})
+	checkedTypes.Store(t, err)
+	return err
+}
+
+// errOrNil turns a cached nil error back into an untyped nil
+func errOrNil(err interface{}) error {
+	if err == nil {
+		return nil
+	}
+	return err.(error)
+}
+
+func checkStructTags(t reflect.Type, seen map[reflect.Type]bool) error {
+	if seen[t] {
+		return nil
+	}
+	seen[t] = true
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		fieldType := derefType(field.Type)
+		if field.Anonymous && field.Tag.Get("json") == "" && isNestedStructType(fieldType) {
+			if err := checkStructTags(fieldType, seen); err != nil {
+				return err
+			}
+			continue
+		}
+		if jsonName(field) == "-" {
+			continue
+		}
+
+		if tag := field.Tag.Get("binding"); tag != "" && tag != "-" {
+			for _, rule := range strings.Split(tag, ",") {
+				code, param, _ := strings.Cut(strings.TrimSpace(rule), "=")
+				if err := checkRule(t, fieldType, code, param); err != nil {
+					return fmt.Errorf("validation: %s.%s: %w", t.Name(), field.Name, err)
+				}
+			}
+		}
+
+		if fieldType.Kind() == reflect.Slice {
+			fieldType = derefType(fieldType.Elem())
+		}
+		if isNestedStructType(fieldType) {
+			if err := checkStructTags(fieldType, seen); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// checkRule checks that a rule exists, that its parameter is well formed and that it can apply
+// to a field of fieldType in parent
+func checkRule(parent reflect.Type, fieldType reflect.Type, code string, param string) error {
+	switch code {
+	case "", "required", "omitempty", "email", "oneof", "numeric", "mobile", "aadhaar", "objectid", "past":
+		return nil
+	case "min", "max":
+		if _, err := strconv.ParseFloat(param, 64); err != nil {
+			return fmt.Errorf("invalid %s=%s", code, param)
+		}
+		if !measurable(fieldType) {
+			return fmt.Errorf("%s is not supported for %s", code, fieldType)
+		}
+		return nil
+	case "len":
+		if _, err := strconv.Atoi(param); err != nil {
+			return fmt.Errorf("invalid len=%s", param)
+		}
+		if !measurable(fieldType) {
+			return fmt.Errorf("len is not supported for %s", fieldType)
+		}
+		return nil
+	case "gtfield", "gtefield":
+		other, ok := parent.FieldByName(param)
+		if !ok {
+			return fmt.Errorf("%s refers to unknown field %s", code, param)
+		}
+		if !orderable(fieldType, derefType(other.Type)) {
+			return fmt.Errorf("%s cannot compare %s with %s", code, fieldType, other.Type)
+		}
+		return nil
+	}
+	return errors.New("unknown rule " + code)
+}
+
+func validateStruct(value reflect.Value, prefix string, errs *Errors) {
+	structType := value.Type()
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		// Embedded structs without a JSON name are flattened by encoding/json, so their fields share the prefix
+		if field.Anonymous && field.Tag.Get("json") == "" && isNestedStruct(indirect(value.Field(i))) {
+			validateStruct(indirect(value.Field(i)), prefix, errs)
+			continue
+		}
+		name := jsonName(field)
+		if name == "-" {
+			continue
+		}
+		path := prefix + name
+		fieldValue := value.Field(i)
+
+		if tag := field.Tag.Get("binding"); tag != "" && tag != "-" {
+			if !validateField(value, fieldValue, path, strings.Split(tag, ","), errs) {
+				continue
+			}
+		}
+
+		// Descend into nested structs, and slices of them, that were supplied
+		nested := indirect(fieldValue)
+		if nested.Kind() == reflect.Slice {
+			for j := 0; j < nested.Len(); j++ {
+				if element := indirect(nested.Index(j)); isNestedStruct(element) {
+					validateStruct(element, fmt.Sprintf("%s[%d].", path, j), errs)
+				}
+			}
+		} else if isNestedStruct(nested) {
+			validateStruct(nested, path+".", errs)
+		}
+	}
+}
+
+// isNestedStruct reports whether value is a struct whose fields should be validated
+func isNestedStruct(value reflect.Value) bool {
+	return value.Kind() == reflect.Struct && isNestedStructType(value.Type())
+}
+
+func isNestedStructType(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && t != timeType && t != objectIDType
+}
+
+// validateField applies rules to one field, stopping at its first failure.
+// It returns false if the field failed or was skipped as empty.
+func validateField(parent reflect.Value, value reflect.Value, path string, rules []string, errs *Errors) bool {
+	empty := isEmpty(value)
+	for _, rule := range rules {
+		code, param, _ := strings.Cut(strings.TrimSpace(rule), "=")
+		switch code {
+		case "":
+			continue
+		case "required":
+			if empty {
+				*errs = append(*errs, FieldError{Field: path, Code: code, Message: path + " is required"})
+				return false
+			}
+			continue
+		case "omitempty":
+			if empty {
+				return false
+			}
+			continue
+		}
+		if empty {
+			// Only required rejects empty values
+			continue
+		}
+
+		if message, ok := check(parent, indirect(value), code, param); !ok {
+			if code == "gtfield" || code == "gtefield" {
+				// Report the other field by its JSON name
+				if other, found := parent.Type().FieldByName(param); found {
+					param = jsonName(other)
+				}
+			}
+			*errs = append(*errs, FieldError{Field: path, Code: code, Param: param, Message: path + " " + message})
+			return false
+		}
+	}
+	return true
+}
+
+// check applies a single rule to a non-empty value, returning a message describing the failure.
+// The rule has already been checked against the field by checkRule.
+func check(parent reflect.Value, value reflect.Value, code string, param string) (string, bool) {
+	switch code {
+	case "email":
+		return "must be a valid email address", IsEmail(value.String())
+	case "min", "max":
+		limit, _ := strconv.ParseFloat(param, 64)
+		size, unit := measure(value)
+		if code == "min" && limit == 1 && unit != "" {
+			return "must not be empty", size >= limit
+		}
+		if code == "min" {
+			return fmt.Sprintf("must be at least %s%s", param, unit), size >= limit
+		}
+		return fmt.Sprintf("must be at most %s%s", param, unit), size <= limit
+	case "len":
+		n, _ := strconv.Atoi(param)
+		size, unit := measure(value)
+		return fmt.Sprintf("must be exactly %d%s", n, unit), size == float64(n)
+	case "oneof":
+		s := fmt.Sprint(value.Interface())
+		for _, option := range strings.Fields(param) {
+			if s == option {
+				return "", true
+			}
+		}
+		return "must be one of: " + strings.Join(strings.Fields(param), ", "), false
+	case "numeric":
+		s := value.String()
+		return "must contain only digits", s != "" && strings.Trim(s, "0123456789") == ""
+	case "mobile":
+		return "must be a valid 10-digit Indian mobile number", IsMobile(value.String())
+	case "aadhaar":
+		return "must be a valid 12-digit Aadhaar number", IsAadhaar(value.String())
+	case "objectid":
+		return "must be a valid ID", primitive.IsValidObjectID(value.String())
+	case "past":
+		t, ok := value.Interface().(time.Time)
+		return "must be in the past", ok && t.Before(time.Now())
+	case "gtfield", "gtefield":
+		other := parent.FieldByName(param)
+		if isEmpty(value) || isEmpty(other) {
+			return "", true
+		}
+		otherName := param
+		if field, ok := parent.Type().FieldByName(param); ok {
+			otherName = jsonName(field)
+		}
+		cmp := compare(value, indirect(other))
+		if code == "gtfield" {
+			return "must be after " + otherName, cmp > 0
+		}
+		return "must not be before " + otherName, cmp >= 0
+	}
+	return "", true
+}
+
+// IsEmail reports whether s is a bare email address with a dotted domain, e.g. name@example.com
+func IsEmail(s string) bool {
+	s = strings.TrimSpace(s)
+	address, err := mail.ParseAddress(s)
+	return err == nil && address.Address == s && strings.Contains(s[strings.LastIndex(s, "@"):], ".")
+}
+
+// IsMobile reports whether s is an Indian mobile number: ten digits starting with 6-9,
+// optionally formatted and prefixed with +91 or 0
+func IsMobile(s string) bool {
+	digits := strings.Map(func(r rune) rune {
+		switch {
+		case r >= '0' && r <= '9':
+			return r
+		case r == ' ' || r == '-' || r == '+' || r == '(' || r == ')':
+			return -1
+		}
+		return 'x'
+	}, s)
+	switch {
+	case len(digits) == 12 && strings.HasPrefix(digits, "91"):
+		digits = digits[2:]
+	case len(digits) == 11 && strings.HasPrefix(digits, "0"):
+		digits = digits[1:]
+	}
+	return len(digits) == 10 && digits[0] >= '6' && digits[0] <= '9' && strings.Trim(digits, "0123456789") == ""
+}
+
+// IsAadhaar reports whether s is a 12-digit Aadhaar number (spaces and dashes allowed)
+// that doesn't start with 0 or 1 and has a valid Verhoeff check digit
+func IsAadhaar(s string) bool {
+	digits := strings.NewReplacer(" ", "", "-", "").Replace(s)
+	if len(digits) != 12 || strings.Trim(digits, "0123456789") != "" || digits[0] == '0' || digits[0] == '1' {
+		return false
+	}
+	return verhoeffValid(digits)
+}
+
+// Verhoeff tables: d is the dihedral group D5 multiplication, p the position permutation
+var verhoeffD = [10][10]int{
+	{0, 1, 2, 3, 4, 5, 6, 7, 8, 9},
+	{1, 2, 3, 4, 0, 6, 7, 8, 9, 5},
+	{2, 3, 4, 0, 1, 7, 8, 9, 5, 6},
+	{3, 4, 0, 1, 2, 8, 9, 5, 6, 7},
+	{4, 0, 1, 2, 3, 9, 5, 6, 7, 8},
+	{5, 9, 8, 7, 6, 0, 4, 3, 2, 1},
+	{6, 5, 9, 8, 7, 1, 0, 4, 3, 2},
+	{7, 6, 5, 9, 8, 2, 1, 0, 4, 3},
+	{8, 7, 6, 5, 9, 3, 2, 1, 0, 4},
+	{9, 8, 7, 6, 5, 4, 3, 2, 1, 0},
+}
+
+var verhoeffP = [8][10]int{
+	{0, 1, 2, 3, 4, 5, 6, 7, 8, 9},
+	{1, 5, 7, 6, 2, 8, 3, 0, 9, 4},
+	{5, 8, 0, 3, 7, 9, 6, 1, 4, 2},
+	{8, 9, 1, 6, 0, 4, 3, 5, 2, 7},
+	{9, 4, 5, 3, 1, 2, 6, 8, 7, 0},
+	{4, 2, 8, 6, 5, 7, 3, 9, 0, 1},
+	{2, 7, 9, 3, 8, 0, 6, 4, 1, 5},
+	{7, 0, 4, 6, 9, 1, 3, 2, 5, 8},
+}
+
+// verhoeffValid checks a string of digits whose last digit is a Verhoeff check digit
+func verhoeffValid(digits string) bool {
+	c := 0
+	for i := 0; i < len(digits); i++ {
+		digit := int(digits[len(digits)-1-i] - '0')
+		c = verhoeffD[c][verhoeffP[i%8][digit]]
+	}
+	return c == 0
+}
+
+func jsonName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
+
+func derefType(t reflect.Type) reflect.Type {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+func indirect(value reflect.Value) reflect.Value {
+	for value.Kind() == reflect.Ptr && !value.IsNil() {
+		value = value.Elem()
+	}
+	return value
+}
+
+func isEmpty(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return value.IsNil()
+	case reflect.String:
+		return strings.TrimSpace(value.String()) == ""
+	case reflect.Slice, reflect.Map, reflect.Array:
+		if value.Type() == objectIDType {
+			return value.Interface().(primitive.ObjectID).IsZero()
+		}
+		return value.Len() == 0
+	case reflect.Struct:
+		if t, ok := value.Interface().(time.Time); ok {
+			return t.IsZero()
+		}
+		return false
+	}
+	return value.IsZero()
+}
+
+// measurable reports whether min, max and len apply to values of type t
+func measurable(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// measure returns the size compared by min and max, and the unit to describe it with.
+// The value's type is one measurable accepts.
+func measure(value reflect.Value) (float64, string) {
+	switch value.Kind() {
+	case reflect.String:
+		return float64(len([]rune(value.String()))), " characters"
+	case reflect.Slice, reflect.Map, reflect.Array:
+		return float64(value.Len()), " items"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(value.Int()), ""
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(value.Uint()), ""
+	case reflect.Float32, reflect.Float64:
+		return value.Float(), ""
+	}
+	return 0, ""
+}
+
+// orderable reports whether gtfield and gtefield can order values of types a and b
+func orderable(a, b reflect.Type) bool {
+	if a != b {
+		return false
+	}
+	switch a.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Float32, reflect.Float64:
+		return true
+	}
+	return a == timeType
+}
+
+// compare orders two times or numbers of a type orderable accepts
+func compare(a, b reflect.Value) int {
+	if t, ok := a.Interface().(time.Time); ok {
+		return t.Compare(b.Interface().(time.Time))
+	}
+	switch a.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return cmpOrdered(a.Int(), b.Int())
+	}
+	return cmpOrdered(a.Float(), b.Float())
+}
+
+func cmpOrdered[T int64 | float64](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
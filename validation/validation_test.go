@@ -0,0 +1,287 @@
+package validation
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIsAadhaar(t *testing.T) {
+	tests := []struct {
+		number string
+		want   bool
+	}{
+		{"234123412346", true},
+		{"499187654323", true},
+		{"876543210988", true},
+		{"6123 4567 8904", true},
+		{"6123-4567-8904", true},
+		{"234123412345", false},  // wrong check digit
+		{"234213412346", false},  // adjacent digits transposed
+		{"334123412346", false},  // one digit changed
+		{"034123412346", false},  // starts with 0
+		{"134123412346", false},  // starts with 1
+		{"23412341234", false},   // 11 digits
+		{"2341234123461", false}, // 13 digits
+		{"23412341234a", false},
+		{"2341.2341.2346", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := IsAadhaar(tt.number); got != tt.want {
+			t.Errorf("IsAadhaar(%q) = %v, want %v", tt.number, got, tt.want)
+		}
+	}
+}
+
+func TestVerhoeffValid(t *testing.T) {
+	// The worked example from Verhoeff's scheme: 236 has check digit 3
+	if !verhoeffValid("2363") {
+		t.Error("2363 should be valid")
+	}
+	for check := '0'; check <= '9'; check++ {
+		if check != '3' && verhoeffValid("236"+string(check)) {
+			t.Errorf("236%c should be invalid", check)
+		}
+	}
+}
+
+func TestIsMobile(t *testing.T) {
+	tests := []struct {
+		number string
+		want   bool
+	}{
+		{"9876543210", true},
+		{"6000000000", true},
+		{"+919876543210", true},
+		{"+91 98765 43210", true},
+		{"+91-98765-43210", true},
+		{"919876543210", true},
+		{"09876543210", true},
+		{"(0) 98765 43210", true},
+		{"5876543210", false},     // starts with 5
+		{"0876543210", false},     // landline-style leading 0 with ten digits
+		{"987654321", false},      // nine digits
+		{"98765432101", false},    // eleven digits without a 0 prefix
+		{"+449876543210", false},  // another country code
+		{"9876543210x", false},    // letters
+		{"98765.43210", false},    // unsupported separator
+		{"+91 5876543210", false}, // valid prefix, invalid number
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := IsMobile(tt.number); got != tt.want {
+			t.Errorf("IsMobile(%q) = %v, want %v", tt.number, got, tt.want)
+		}
+	}
+}
+
+type window struct {
+	Start *time.Time `json:"start"`
+	End   *time.Time `json:"end" binding:"omitempty,gtfield=Start"`
+	Min   int        `json:"min"`
+	Max   int        `json:"max" binding:"gtefield=Min"`
+}
+
+func TestGtfield(t *testing.T) {
+	at := func(hour int) *time.Time {
+		value := time.Date(2026, 5, 1, hour, 0, 0, 0, time.UTC)
+		return &value
+	}
+	tests := []struct {
+		name   string
+		window window
+		want   []string // failing fields
+	}{
+		{"after", window{Start: at(9), End: at(10)}, nil},
+		{"equal", window{Start: at(9), End: at(9)}, []string{"end"}},
+		{"before", window{Start: at(10), End: at(9)}, []string{"end"}},
+		{"start missing", window{End: at(9)}, nil},
+		{"end missing", window{Start: at(9)}, nil},
+		{"gte equal", window{Min: 3, Max: 3}, nil},
+		{"gte below", window{Min: 3, Max: 2}, []string{"max"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Struct(tt.window)
+			if got := failedFields(t, err); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("failed fields %v, want %v (%v)", got, tt.want, err)
+			}
+		})
+	}
+
+	var errs Errors
+	if !errors.As(Struct(window{Start: at(10), End: at(9)}), &errs) || errs[0].Param != "start" || errs[0].Message != "end must be after start" {
+		t.Errorf("errors = %+v, want end reported against start by JSON name", errs)
+	}
+}
+
+type contact struct {
+	Name   string `json:"name" binding:"required"`
+	Mobile string `json:"mobile" binding:"omitempty,mobile"`
+}
+
+type address struct {
+	City    string `json:"city" binding:"required"`
+	Pincode string `json:"pincode" binding:"omitempty,len=6,numeric"`
+}
+
+type Audit struct {
+	Note string `json:"note" binding:"max=5"`
+}
+
+type application struct {
+	Audit                         // flattened, like encoding/json does
+	Email      string             `json:"email" binding:"required,email"`
+	Address    address            `json:"address"`
+	Previous   *address           `json:"previous"`
+	Contacts   []contact          `json:"contacts" binding:"max=3"`
+	Emergency  []*contact         `json:"emergency"`
+	Level      string             `json:"level" binding:"omitempty,oneof=junior senior"`
+	Age        int                `json:"age" binding:"min=5,max=19"`
+	Attributes map[string]string  `json:"attributes" binding:"omitempty,min=1"`
+	Ignored    address            `json:"-"`
+	unexported struct{ X string } `binding:"required"`
+}
+
+func failedFields(t *testing.T, err error) []string {
+	t.Helper()
+	if err == nil {
+		return nil
+	}
+	var errs Errors
+	if !errors.As(err, &errs) {
+		t.Fatalf("error %v is not Errors", err)
+	}
+	fields := make([]string, len(errs))
+	for i, fieldErr := range errs {
+		fields[i] = fieldErr.Field
+	}
+	return fields
+}
+
+func TestStructFieldPaths(t *testing.T) {
+	valid := func() application {
+		return application{
+			Email:    "coach@example.com",
+			Address:  address{City: "Pune", Pincode: "411001"},
+			Contacts: []contact{{Name: "A", Mobile: "9876543210"}},
+			Age:      12,
+		}
+	}
+	tests := []struct {
+		name   string
+		change func(a *application)
+		want   []string
+	}{
+		{"valid", func(a *application) {}, nil},
+		{"nested field", func(a *application) { a.Address.City = " " }, []string{"address.city"}},
+		{"nested rules in order", func(a *application) { a.Address.Pincode = "4110" }, []string{"address.pincode"}},
+		{"nested pointer", func(a *application) { a.Previous = &address{Pincode: "41100x"} }, []string{"previous.city", "previous.pincode"}},
+		{"nil pointer is skipped", func(a *application) { a.Previous = nil }, nil},
+		{"slice element", func(a *application) {
+			a.Contacts = append(a.Contacts, contact{Name: "B", Mobile: "12345"})
+		}, []string{"contacts[1].mobile"}},
+		{"slice of pointers", func(a *application) {
+			a.Emergency = []*contact{{Name: "C"}, {}}
+		}, []string{"emergency[1].name"}},
+		{"slice length", func(a *application) {
+			a.Contacts = []contact{{Name: "A"}, {Name: "B"}, {Name: "C"}, {Name: "D"}}
+		}, []string{"contacts"}},
+		{"embedded struct is flattened", func(a *application) { a.Note = "too long" }, []string{"note"}},
+		{"ignored field", func(a *application) { a.Ignored = address{} }, nil},
+		{"several failures", func(a *application) {
+			a.Email = "not an email"
+			a.Level = "senior citizen"
+			a.Age = 20
+			a.Attributes = map[string]string{}
+		}, []string{"email", "level", "age"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := valid()
+			tt.change(&a)
+			err := Struct(&a)
+			if got := failedFields(t, err); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("failed fields %v, want %v (%v)", got, tt.want, err)
+			}
+		})
+	}
+}
+
+func TestStructMessages(t *testing.T) {
+	var errs Errors
+	err := Struct(application{Email: "a@example.com", Address: address{City: "Pune"}, Age: 3, Contacts: []contact{{}}})
+	if !errors.As(err, &errs) {
+		t.Fatalf("Struct = %v, want Errors", err)
+	}
+	want := Errors{
+		{Field: "contacts[0].name", Code: "required", Message: "contacts[0].name is required"},
+		{Field: "age", Code: "min", Param: "5", Message: "age must be at least 5"},
+	}
+	if !reflect.DeepEqual(errs, want) {
+		t.Errorf("errors = %+v, want %+v", errs, want)
+	}
+}
+
+func TestStructRejectsBadTags(t *testing.T) {
+	type unknownRule struct {
+		Name string `binding:"required,shouty"`
+	}
+	type badLimit struct {
+		Name string `binding:"max=ten"`
+	}
+	type unmeasurable struct {
+		Active bool `binding:"min=1"`
+	}
+	type badLen struct {
+		Code string `binding:"omitempty,len=six"`
+	}
+	type unknownField struct {
+		End time.Time `binding:"gtfield=Begin"`
+	}
+	type mismatched struct {
+		Start string
+		End   time.Time `binding:"gtfield=Start"`
+	}
+	type nestedBad struct {
+		Items []unknownRule `json:"items"`
+	}
+
+	tests := []struct {
+		name  string
+		value interface{}
+		want  string
+	}{
+		{"unknown rule", unknownRule{Name: "x"}, "unknown rule shouty"},
+		{"bad limit", badLimit{}, "invalid max=ten"},
+		{"unmeasurable kind", unmeasurable{Active: true}, "min is not supported for bool"},
+		{"bad len, even when skipped as empty", badLen{}, "invalid len=six"},
+		{"unknown field", unknownField{}, "refers to unknown field Begin"},
+		{"mismatched types", mismatched{}, "cannot compare"},
+		{"nested type", nestedBad{}, "unknown rule shouty"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Struct(tt.value)
+			var errs Errors
+			if err == nil || errors.As(err, &errs) || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Struct = %v, want a tag error containing %q", err, tt.want)
+			}
+			if again := Struct(tt.value); again == nil || again.Error() != err.Error() {
+				t.Errorf("second Struct = %v, want the cached %v", again, err)
+			}
+		})
+	}
+}
+
+func TestStructNonStruct(t *testing.T) {
+	var nilPointer *application
+	for _, v := range []interface{}{nil, nilPointer, 5, "x"} {
+		if err := Struct(v); err != nil {
+			t.Errorf("Struct(%#v) = %v, want nil", v, err)
+		}
+	}
+}
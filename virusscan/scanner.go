@@ -0,0 +1,52 @@
+// Package virusscan defines the hook uploads pass through before they are stored.
+package virusscan
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Result is the outcome of scanning a file
+type Result struct {
+	Clean     bool   `json:"clean" bson:"clean"`
+	Signature string `json:"signature,omitempty" bson:"signature,omitempty"` // name of the threat found, if any
+	Scanner   string `json:"scanner" bson:"scanner"`
+}
+
+// Scanner checks file contents for malware
+type Scanner interface {
+	Scan(ctx context.Context, body io.Reader) (Result, error)
+}
+
+// NewScannerFromEnv creates the scanner selected by VIRUS_SCANNER.
+// Only "stub" (the default) is available; it is meant for local development.
+func NewScannerFromEnv() (Scanner, error) {
+	switch os.Getenv("VIRUS_SCANNER") {
+	case "", "stub":
+		return StubScanner{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported VIRUS_SCANNER %q", os.Getenv("VIRUS_SCANNER"))
+	}
+}
+
+// eicar is the standard antivirus test file signature
+var eicar = []byte(`X5O!P%@AP[4\PZX54(P^)7CC)7}$EICAR-STANDARD-ANTIVIRUS-TEST-FILE!$H+H*`)
+
+// StubScanner stands in for a real scanner. It only flags the EICAR test file,
+// so the rejection path can be exercised without an antivirus engine.
+type StubScanner struct{}
+
+// Scan implements Scanner
+func (StubScanner) Scan(ctx context.Context, body io.Reader) (Result, error) {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return Result{}, err
+	}
+	if bytes.Contains(data, eicar) {
+		return Result{Clean: false, Signature: "EICAR-Test-File", Scanner: "stub"}, nil
+	}
+	return Result{Clean: true, Scanner: "stub"}, nil
+}